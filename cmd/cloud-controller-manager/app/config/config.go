@@ -17,6 +17,8 @@ limitations under the License.
 package app
 
 import (
+	"time"
+
 	apiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
@@ -26,8 +28,12 @@ import (
 	ccmconfig "k8s.io/cloud-provider/config"
 
 	nodeipamconfig "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/eventrecorder"
 )
 
+// CloudConfigSourceFake is the source of the Azure resources served by the in-memory fake backend.
+const CloudConfigSourceFake = "fake"
+
 // Config is the main context object for the cloud controller manager.
 type Config struct {
 	ComponentConfig ccmconfig.CloudControllerManagerConfiguration
@@ -59,8 +65,38 @@ type Config struct {
 	// SharedInformers gives access to informers for the controller.
 	SharedInformers informers.SharedInformerFactory
 
+	// DisablePodInformers disables the informers caching the pods, the pods are listed from
+	// the API server instead.
+	DisablePodInformers bool
+
+	// LeaderElectionWarmStandby keeps the informers and the Azure caches of the replicas not being
+	// the leader warm, without changing any resource.
+	LeaderElectionWarmStandby bool
+
+	// LoadBalancerClasses are the load balancer classes of the services reconciled by the service
+	// controller besides the ones without a class.
+	LoadBalancerClasses []string
+
+	// LoadBalancerRepairPeriod is the period of the checks of the load balancer repair controller.
+	LoadBalancerRepairPeriod time.Duration
+
+	// ControllerSyncStaleness is how long the calls of a controller to the cloud provider can keep failing
+	// before its health check fails, 0 never fails the checks.
+	ControllerSyncStaleness time.Duration
+
+	// CloudConfigSource is the source of the Azure resources, either empty for Azure or CloudConfigSourceFake.
+	CloudConfigSource string
+	// FakeAzureResourcesFile is the file of the resources loaded into the fake backend.
+	FakeAzureResourcesFile string
+
 	DynamicReloadingConfig DynamicReloadingConfig
 
+	ControllerWorkersConfig ControllerWorkersConfig
+
+	TracingConfig TracingConfig
+
+	EventsConfig EventsConfig
+
 	// Node filtering configuration
 	NodeFilteringConfig NodeFilteringConfig
 }
@@ -72,6 +108,58 @@ type DynamicReloadingConfig struct {
 	CloudConfigKey             string
 }
 
+// DefaultConcurrentNodeStatusUpdates is the least number of nodes the cloud-node controller initializes and
+// updates the status of in parallel by default, so a new node pool is initialized without waiting for the
+// nodes one by one.
+const DefaultConcurrentNodeStatusUpdates = 10
+
+// ControllerWorkersConfig contains the worker pool sizes of the node controllers of the cloud provider.
+// Zero uses the worker pool size of the node controller, --concurrent-node-syncs.
+type ControllerWorkersConfig struct {
+	ConcurrentNodeAnnotatorSyncs  int32
+	ConcurrentNodeProviderIDSyncs int32
+	// ConcurrentNodeStatusUpdates is the number of nodes the cloud-node controller initializes and
+	// updates the status of in parallel, it replaces --concurrent-node-syncs for the cloud-node controller.
+	// Zero uses the larger of DefaultConcurrentNodeStatusUpdates and --concurrent-node-syncs, so a larger
+	// --concurrent-node-syncs set for the cloud-node controller is still respected.
+	ConcurrentNodeStatusUpdates int32
+}
+
+// GetConcurrentNodeStatusUpdates returns the number of nodes the cloud-node controller initializes and
+// updates the status of in parallel.
+func (c ControllerWorkersConfig) GetConcurrentNodeStatusUpdates(concurrentNodeSyncs int32) int32 {
+	if c.ConcurrentNodeStatusUpdates > 0 {
+		return c.ConcurrentNodeStatusUpdates
+	}
+	return max(DefaultConcurrentNodeStatusUpdates, concurrentNodeSyncs)
+}
+
+// TracingConfig contains the configuration of the OpenTelemetry tracing of the reconciliations and
+// the ARM client calls. The spans are not exported if the OTLP endpoint is empty.
+type TracingConfig struct {
+	OTLPEndpoint  string
+	OTLPInsecure  bool
+	SamplingRatio float64
+}
+
+// EventsConfig contains the aggregation policy of the events of the controller manager. The events
+// of an object with the same type and reason are rate limited, and the suppressed ones are counted
+// in summary events.
+type EventsConfig struct {
+	Burst           int32
+	QPS             float64
+	SummaryInterval time.Duration
+}
+
+// Policy returns the event aggregation policy of the config.
+func (c EventsConfig) Policy() eventrecorder.Policy {
+	return eventrecorder.Policy{
+		Burst:           int(c.Burst),
+		QPS:             c.QPS,
+		SummaryInterval: c.SummaryInterval,
+	}
+}
+
 // NodeFilteringConfig contains node filtering configuration
 type NodeFilteringConfig struct {
 	EnableNodeFiltering bool
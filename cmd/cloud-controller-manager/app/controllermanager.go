@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -55,11 +56,15 @@ import (
 	cloudcontrollerconfig "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
 	"sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/dynamic"
 	"sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/options"
+	statusv1alpha1 "sigs.k8s.io/cloud-provider-azure/pkg/apis/status/v1alpha1"
 	armmetrics "sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
 	"sigs.k8s.io/cloud-provider-azure/pkg/log"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 	"sigs.k8s.io/cloud-provider-azure/pkg/trace/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/eventrecorder"
+	informerutil "sigs.k8s.io/cloud-provider-azure/pkg/util/informer"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version/verflag"
 )
@@ -71,6 +76,10 @@ const (
 	ConfigzName = "cloudcontrollermanager.config.k8s.io"
 )
 
+// runningCloud is the cloud of the running controllers. Its credentials are rotated in place if the
+// client secrets are the only change of the cloud config when the dynamic reloading is enabled.
+var runningCloud atomic.Pointer[provider.Cloud]
+
 // NewCloudControllerManagerCommand creates a *cobra.Command object with default parameters
 func NewCloudControllerManagerCommand() *cobra.Command {
 	s, err := options.NewCloudControllerManagerOptions()
@@ -97,7 +106,12 @@ func NewCloudControllerManagerCommand() *cobra.Command {
 			var traceProvider *trace.Provider
 			{
 				var err error
-				traceProvider, err = trace.New()
+				traceProvider, err = trace.New(context.Background(), trace.Options{
+					OTLPEndpoint:  c.TracingConfig.OTLPEndpoint,
+					OTLPInsecure:  c.TracingConfig.OTLPInsecure,
+					SamplingRatio: c.TracingConfig.SamplingRatio,
+					ServiceName:   "cloud-controller-manager",
+				})
 				if err != nil {
 					log.Background().Error(err, "Failed to create trace provider")
 					os.Exit(1)
@@ -161,18 +175,20 @@ func NewCloudControllerManagerCommand() *cobra.Command {
 					electionChecker = leaderelection.NewLeaderHealthzAdaptor(time.Second * 20)
 				}
 
+				stopStandby := func() {}
+				if c.LeaderElectionWarmStandby {
+					stopStandby = startWarmStandby(cmd.Context(), c.Complete())
+				}
+
 				leaderelection.RunOrDie(context.TODO(), leaderelection.LeaderElectionConfig{
 					Lock:          rl,
 					LeaseDuration: c.ComponentConfig.Generic.LeaderElection.LeaseDuration.Duration,
 					RenewDeadline: c.ComponentConfig.Generic.LeaderElection.RenewDeadline.Duration,
 					RetryPeriod:   c.ComponentConfig.Generic.LeaderElection.RetryPeriod.Duration,
-					Callbacks: leaderelection.LeaderCallbacks{
-						OnStartedLeading: RunWrapper(s, c, healthHandler),
-						OnStoppedLeading: func() {
-							klog.ErrorS(nil, "leaderelection lost")
-							klog.FlushAndExit(klog.ExitFlushTimeout, 1)
-						},
-					},
+					Callbacks: newLeaderCallbacks(RunWrapper(s, c, healthHandler), stopStandby, func() {
+						klog.ErrorS(nil, "leaderelection lost")
+						klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+					}),
 					WatchDog: electionChecker,
 					Name:     "cloud-controller-manager",
 				})
@@ -189,6 +205,7 @@ func NewCloudControllerManagerCommand() *cobra.Command {
 	namedFlagSets := s.Flags(KnownControllers(), ControllersDisabledByDefault.List())
 	verflag.AddFlags(namedFlagSets.FlagSet("global"))
 	globalflag.AddGlobalFlags(namedFlagSets.FlagSet("global"), cmd.Name())
+	log.BindCLIFlags(namedFlagSets.FlagSet("global"))
 
 	for _, f := range namedFlagSets.FlagSets {
 		fs.AddFlagSet(f)
@@ -205,7 +222,7 @@ func NewCloudControllerManagerCommand() *cobra.Command {
 		cliflag.PrintSections(cmd.OutOrStdout(), namedFlagSets, cols)
 	})
 
-	log.BindCLIFlags(fs)
+	cmd.AddCommand(newLintServicesCommand())
 
 	return cmd
 }
@@ -236,10 +253,14 @@ func RunWrapper(s *options.CloudControllerManagerOptions, c *cloudcontrollerconf
 		for {
 			select {
 			case <-updateCh:
+				if rotateCredentialsInPlace(ctx, cloudConfigFile, c) {
+					continue
+				}
 				klog.V(2).Info("RunWrapper: detected the cloud config has been updated, re-constructing the cloud controller manager")
 
 				// stop the previous goroutines
 				cancelFunc()
+				runningCloud.Store(nil)
 
 				var (
 					shouldRemainStopped bool
@@ -268,6 +289,44 @@ func RunWrapper(s *options.CloudControllerManagerOptions, c *cloudcontrollerconf
 	}
 }
 
+// rotateCredentialsInPlace rotates the credentials of the running cloud if the client secrets are the only change
+// of the cloud config, so that the controllers and their ARM operations in flight keep running. It returns false
+// if the controllers have to be restarted to apply the cloud config.
+func rotateCredentialsInPlace(ctx context.Context, cloudConfigFile string, c *cloudcontrollerconfig.Config) bool {
+	az := runningCloud.Load()
+	if az == nil {
+		return false
+	}
+
+	var (
+		config *azureconfig.Config
+		err    error
+	)
+	if cloudConfigFile != "" {
+		var configFile *os.File
+		configFile, err = os.Open(cloudConfigFile)
+		if err != nil {
+			klog.Errorf("rotateCredentialsInPlace: failed to open %s: %v", cloudConfigFile, err)
+			return false
+		}
+		defer configFile.Close()
+		config, err = azureconfig.ParseConfig(configFile)
+	} else {
+		config, err = provider.LoadConfigFromSecret(ctx, c.VersionedClient, c.DynamicReloadingConfig.CloudConfigSecretName, c.DynamicReloadingConfig.CloudConfigSecretNamespace, c.DynamicReloadingConfig.CloudConfigKey)
+	}
+	if err != nil || config == nil {
+		klog.Errorf("rotateCredentialsInPlace: failed to load the cloud config: %v", err)
+		return false
+	}
+
+	if err := az.RotateCredentials(config); err != nil {
+		klog.V(2).Infof("rotateCredentialsInPlace: %v", err)
+		return false
+	}
+	klog.V(2).Info("rotateCredentialsInPlace: the cloud config has no changes other than the client secrets and the rate limits, keep the controllers running")
+	return true
+}
+
 func shouldDisableCloudProvider(configFilePath string) (bool, error) {
 	configBytes, err := os.ReadFile(configFilePath)
 	if err != nil {
@@ -327,6 +386,9 @@ func StartHTTPServer(ctx context.Context, c *cloudcontrollerconfig.CompletedConf
 		unsecuredMux.Unregister(MetricsPath) // Unregisterer handler of legacy registerer
 		unsecuredMux.Handle(MetricsPath, traceProvider.MetricsHTTPHandler())
 		unsecuredMux.Handle("/metrics/v2", traceProvider.MetricsHTTPHandler()) // Will remove in the future after migration
+		unsecuredMux.HandlePrefix(ControllerDebuggingPath, controllerDebuggingHandlers)
+		unsecuredMux.Handle(StateDebuggingPath, cloudStateDebuggingHandler)
+		unsecuredMux.Handle(ControllerSyncPath, controllerSyncs)
 
 		handler := genericcontrollermanager.BuildHandlerChain(unsecuredMux, &c.Authorization, &c.Authentication)
 		// TODO: handle stoppedCh returned by c.SecureServing.Serve
@@ -350,7 +412,15 @@ func Run(ctx context.Context, c *cloudcontrollerconfig.CompletedConfig, h *contr
 		err   error
 	)
 
-	if c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile != "" {
+	if az := standbyCloud.Swap(nil); az != nil {
+		klog.Infof("Run: taking over the cloud kept warm in the standby")
+		cloud = az
+	} else if c.CloudConfigSource == cloudcontrollerconfig.CloudConfigSourceFake {
+		cloud, err = provider.NewFakeCloudFromConfigFile(ctx, c.ClientBuilder, c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile, c.FakeAzureResourcesFile, true)
+		if err != nil {
+			klog.Fatalf("Cloud provider azure could not be initialized with the fake backend: %v", err)
+		}
+	} else if c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile != "" {
 		cloud, err = provider.NewCloudFromConfigFile(ctx, c.ClientBuilder, c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile, true)
 		if err != nil {
 			klog.Fatalf("Cloud provider azure could not be initialized: %v", err)
@@ -365,6 +435,9 @@ func Run(ctx context.Context, c *cloudcontrollerconfig.CompletedConfig, h *contr
 	if cloud == nil {
 		klog.Fatalf("cloud provider is nil, please check if the --cloud-config is set properly")
 	}
+	if az, ok := cloud.(*provider.Cloud); ok {
+		runningCloud.Store(az)
+	}
 
 	if !cloud.HasClusterID() {
 		if c.ComponentConfig.KubeCloudShared.AllowUntaggedCloud {
@@ -398,13 +471,28 @@ func Run(ctx context.Context, c *cloudcontrollerconfig.CompletedConfig, h *contr
 // startControllers starts the cloud specific controller loops.
 func startControllers(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig,
 	cloud cloudprovider.Interface, controllers map[string]initFunc, healthzHandler *controllerhealthz.MutableHealthzHandler) error {
+	// The syncs of the controllers are reported in the status of the cloud provider, the registry is
+	// shared across the restarts of the controllers
+	if reporter, ok := cloud.(controllerStatusReporter); ok {
+		reporter.SetControllerStatuses(controllerSyncs.controllerStatuses)
+	}
+	if eventPolicyUserCloud, ok := cloud.(eventPolicyUser); ok {
+		eventPolicyUserCloud.SetEventPolicy(completedConfig.EventsConfig.Policy())
+	}
 	// Initialize the cloud provider with a reference to the clientBuilder
 	cloud.Initialize(completedConfig.ClientBuilder, ctx.Done())
+	if dumper, ok := cloud.(stateDumper); ok {
+		cloudStateDebuggingHandler.set(dumper)
+	}
 	// Set the informer on the user cloud object
+	if podInformerUserCloud, ok := cloud.(podInformerUser); ok && completedConfig.DisablePodInformers {
+		podInformerUserCloud.DisablePodInformer()
+	}
 	if informerUserCloud, ok := cloud.(cloudprovider.InformerUser); ok {
 		informerUserCloud.SetInformers(completedConfig.SharedInformers)
 	}
 
+	controllerSyncs.reset()
 	var controllerChecks []healthz.HealthChecker
 	for controllerName, initFn := range controllers {
 		if !genericcontrollermanager.IsControllerEnabled(controllerName, ControllersDisabledByDefault, completedConfig.ComponentConfig.Generic.Controllers) {
@@ -422,8 +510,12 @@ func startControllers(ctx context.Context, controllerContext genericcontrollerma
 			klog.Warningf("Skipping %q", controllerName)
 			continue
 		}
-		check := controllerhealthz.NamedPingChecker(controllerName)
+		var check healthz.HealthChecker = controllerhealthz.NamedPingChecker(controllerName)
+		if tracker := controllerSyncs.get(controllerName); tracker != nil {
+			check = tracker
+		}
 		if ctrl != nil {
+			controllerDebuggingHandlers.set(controllerName, ctrl)
 			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
 				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
 					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
@@ -457,6 +549,24 @@ func startControllers(ctx context.Context, controllerContext genericcontrollerma
 // initFunc is used to launch a particular controller.  It may run additional "should I activate checks".
 // Any error returned will cause the controller process to `Fatal`
 // The bool indicates whether the controller was enabled.
+// controllerStatusReporter is implemented by the cloud provider which reports the syncs of the
+// controllers in its status.
+type controllerStatusReporter interface {
+	SetControllerStatuses(controllerStatuses func() []statusv1alpha1.ControllerStatus)
+}
+
+// eventPolicyUser is implemented by the cloud provider which records events with the aggregation
+// policy set before Initialize is called.
+type eventPolicyUser interface {
+	SetEventPolicy(policy eventrecorder.Policy)
+}
+
+// podInformerUser is implemented by the cloud provider which watches the pods, unless the pod
+// informer is disabled before SetInformers is called.
+type podInformerUser interface {
+	DisablePodInformer()
+}
+
 type initFunc func(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (debuggingHandler http.Handler, enabled bool, err error)
 
 // KnownControllers indicate the default controller we are known.
@@ -466,7 +576,27 @@ func KnownControllers() []string {
 }
 
 // ControllersDisabledByDefault is the controller disabled default when starting cloud-controller managers.
-var ControllersDisabledByDefault = sets.NewString()
+var ControllersDisabledByDefault = sets.NewString(
+	nodeAnnotatorControllerName,
+	nodeProviderIDControllerName,
+)
+
+const (
+	// nodeIPAMControllerName is the name of the controller allocating the pod cidrs of the nodes.
+	nodeIPAMControllerName = "node-ipam"
+	// nodeAnnotatorControllerName is the name of the controller labeling the nodes with the hardware details of their VMs.
+	nodeAnnotatorControllerName = "node-annotator"
+	// nodeProviderIDControllerName is the name of the controller backfilling the provider IDs of the nodes registered without one.
+	nodeProviderIDControllerName = "node-provider-id"
+	// loadBalancerRepairControllerName is the name of the controller repairing the unhealthy load balancers.
+	loadBalancerRepairControllerName = "load-balancer-repair"
+	// loadBalancerFrontendRepairControllerName is the name of the controller recreating the load balancer frontends deleted out of band.
+	loadBalancerFrontendRepairControllerName = "load-balancer-frontend-repair"
+	// nodePoolOutboundControllerName is the name of the controller managing the outbound rules of the node pools.
+	nodePoolOutboundControllerName = "node-pool-outbound"
+	// nodeDeletionControllerName is the name of the controller taking the nodes marked for deletion out of the load balancers and the routes.
+	nodeDeletionControllerName = "node-deletion"
+)
 
 // newControllerInitializers is a private map of named controller groups (you can start more than one in an init func)
 // paired to their initFunc.  This allows for structured downstream composition and subdivision.
@@ -476,7 +606,13 @@ func newControllerInitializers() map[string]initFunc {
 	controllers[names.CloudNodeLifecycleController] = startCloudNodeLifecycleController
 	controllers[names.ServiceLBController] = startServiceController
 	controllers[names.NodeRouteController] = startRouteController
-	controllers["node-ipam"] = startNodeIpamController
+	controllers[nodeIPAMControllerName] = startNodeIpamController
+	controllers[nodeAnnotatorControllerName] = startNodeAnnotatorController
+	controllers[nodeProviderIDControllerName] = startNodeProviderIDController
+	controllers[loadBalancerRepairControllerName] = startLoadBalancerRepairController
+	controllers[loadBalancerFrontendRepairControllerName] = startLoadBalancerFrontendRepairController
+	controllers[nodePoolOutboundControllerName] = startNodePoolOutboundController
+	controllers[nodeDeletionControllerName] = startNodeDeletionController
 	return controllers
 }
 
@@ -493,7 +629,7 @@ func CreateControllerContext(s *cloudcontrollerconfig.CompletedConfig, clientBui
 		// Create filtered informer factory with same filtering logic as completedConfig
 		sharedInformers = options.CreateFilteredInformerFactory(versionedClient, ResyncPeriod(s)(), nodeFilterConfig.NodeLabelSelector, nodeFilterConfig.NodeExcludeLabels)
 	} else {
-		sharedInformers = informers.NewSharedInformerFactory(versionedClient, ResyncPeriod(s)())
+		sharedInformers = informers.NewSharedInformerFactoryWithOptions(versionedClient, ResyncPeriod(s)(), informers.WithTransform(informerutil.StripUnusedFields))
 	}
 
 	metadataClient := metadata.NewForConfigOrDie(clientBuilder.ConfigOrDie("metadata-informers"))
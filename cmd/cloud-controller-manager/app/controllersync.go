@@ -0,0 +1,334 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	statusv1alpha1 "sigs.k8s.io/cloud-provider-azure/pkg/apis/status/v1alpha1"
+)
+
+// ControllerSyncPath is the path of the last syncs of the controllers.
+const ControllerSyncPath = "/debug/controller-syncs"
+
+// controllerSyncTracker tracks the syncs of a controller by the results of its calls to the cloud provider,
+// and serves as the health check of the controller on /healthz/<controller name>. A successful call syncs
+// the controller. The check fails if the calls have been failing for longer than the staleness window
+// without any success, e.g. with expired credentials. An idle controller making no calls stays healthy.
+type controllerSyncTracker struct {
+	name string
+	// staleness is the staleness window, 0 never fails the check.
+	staleness time.Duration
+	now       func() time.Time
+
+	lock         sync.Mutex
+	lastSyncTime time.Time
+	failingSince time.Time
+	lastError    error
+}
+
+func newControllerSyncTracker(name string, staleness time.Duration) *controllerSyncTracker {
+	return &controllerSyncTracker{
+		name:      name,
+		staleness: staleness,
+		now:       time.Now,
+	}
+}
+
+// observe records the result of a call of the controller.
+func (t *controllerSyncTracker) observe(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	if err == nil {
+		t.lastSyncTime = now
+		t.failingSince = time.Time{}
+		t.lastError = nil
+		return
+	}
+	if t.failingSince.IsZero() {
+		t.failingSince = now
+	}
+	t.lastError = err
+}
+
+// controllerSyncStatus is the sync status of a controller served on ControllerSyncPath.
+type controllerSyncStatus struct {
+	Name         string     `json:"name"`
+	LastSyncTime *time.Time `json:"lastSyncTime,omitempty"`
+	FailingSince *time.Time `json:"failingSince,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	Stale        bool       `json:"stale"`
+}
+
+func (t *controllerSyncTracker) status() controllerSyncStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := controllerSyncStatus{Name: t.name}
+	if !t.lastSyncTime.IsZero() {
+		lastSyncTime := t.lastSyncTime
+		status.LastSyncTime = &lastSyncTime
+	}
+	if !t.failingSince.IsZero() {
+		failingSince := t.failingSince
+		status.FailingSince = &failingSince
+		status.LastError = t.lastError.Error()
+		status.Stale = t.staleness > 0 && t.now().Sub(t.failingSince) > t.staleness
+	}
+	return status
+}
+
+// Name returns the name of the controller.
+func (t *controllerSyncTracker) Name() string {
+	return t.name
+}
+
+// Check returns an error with the last sync time if the controller is stale.
+func (t *controllerSyncTracker) Check(_ *http.Request) error {
+	status := t.status()
+	if !status.Stale {
+		return nil
+	}
+	lastSync := "it started"
+	if status.LastSyncTime != nil {
+		lastSync = status.LastSyncTime.Format(time.RFC3339)
+	}
+	return fmt.Errorf("controller %s has not synced since %s, failing since %s: %s",
+		t.name, lastSync, status.FailingSince.Format(time.RFC3339), status.LastError)
+}
+
+// controllerSyncRegistry keeps the sync trackers of the started controllers, which are replaced when the
+// controllers are restarted after the cloud config is reloaded.
+type controllerSyncRegistry struct {
+	lock     sync.RWMutex
+	trackers map[string]*controllerSyncTracker
+}
+
+// controllerSyncs is shared by the HTTP server and the controllers.
+var controllerSyncs = newControllerSyncRegistry()
+
+func newControllerSyncRegistry() *controllerSyncRegistry {
+	return &controllerSyncRegistry{
+		trackers: make(map[string]*controllerSyncTracker),
+	}
+}
+
+// reset forgets the trackers of the controllers started before.
+func (r *controllerSyncRegistry) reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.trackers = make(map[string]*controllerSyncTracker)
+}
+
+// track returns a new sync tracker of the controller.
+func (r *controllerSyncRegistry) track(controllerName string, staleness time.Duration) *controllerSyncTracker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	tracker := newControllerSyncTracker(controllerName, staleness)
+	r.trackers[controllerName] = tracker
+	return tracker
+}
+
+// get returns the sync tracker of the controller, or nil if the controller is not tracked.
+func (r *controllerSyncRegistry) get(controllerName string) *controllerSyncTracker {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.trackers[controllerName]
+}
+
+// statuses returns the sync statuses of the tracked controllers sorted by name.
+func (r *controllerSyncRegistry) statuses() []controllerSyncStatus {
+	r.lock.RLock()
+	statuses := make([]controllerSyncStatus, 0, len(r.trackers))
+	for _, tracker := range r.trackers {
+		statuses = append(statuses, tracker.status())
+	}
+	r.lock.RUnlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// controllerStatuses returns the sync statuses of the tracked controllers reported in the
+// AzureCloudProviderStatus.
+func (r *controllerSyncRegistry) controllerStatuses() []statusv1alpha1.ControllerStatus {
+	var result []statusv1alpha1.ControllerStatus
+	for _, status := range r.statuses() {
+		controllerStatus := statusv1alpha1.ControllerStatus{
+			Name:  status.Name,
+			Error: status.LastError,
+			Stale: status.Stale,
+		}
+		if status.LastSyncTime != nil {
+			controllerStatus.LastSyncTime = ptr.To(metav1.NewTime(*status.LastSyncTime))
+		}
+		if status.FailingSince != nil {
+			controllerStatus.FailingSince = ptr.To(metav1.NewTime(*status.FailingSince))
+		}
+		result = append(result, controllerStatus)
+	}
+	return result
+}
+
+func (r *controllerSyncRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	statuses := r.statuses()
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(statuses); err != nil {
+		klog.Errorf("failed to encode the syncs of the controllers: %v", err)
+	}
+}
+
+// syncTrackedCloud is the cloud provider given to a controller, which reports the results of the calls
+// of the controller to the instances, the load balancers and the routes to its sync tracker.
+type syncTrackedCloud struct {
+	cloudprovider.Interface
+	tracker *controllerSyncTracker
+}
+
+func newSyncTrackedCloud(cloud cloudprovider.Interface, tracker *controllerSyncTracker) cloudprovider.Interface {
+	return &syncTrackedCloud{Interface: cloud, tracker: tracker}
+}
+
+func (c *syncTrackedCloud) InstancesV2() (cloudprovider.InstancesV2, bool) {
+	instances, ok := c.Interface.InstancesV2()
+	if !ok {
+		return instances, ok
+	}
+	return &syncTrackedInstancesV2{InstancesV2: instances, tracker: c.tracker}, true
+}
+
+func (c *syncTrackedCloud) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
+	balancer, ok := c.Interface.LoadBalancer()
+	if !ok {
+		return balancer, ok
+	}
+	return &syncTrackedLoadBalancer{LoadBalancer: balancer, tracker: c.tracker}, true
+}
+
+func (c *syncTrackedCloud) Routes() (cloudprovider.Routes, bool) {
+	routes, ok := c.Interface.Routes()
+	if !ok {
+		return routes, ok
+	}
+	return &syncTrackedRoutes{Routes: routes, tracker: c.tracker}, true
+}
+
+type syncTrackedInstancesV2 struct {
+	cloudprovider.InstancesV2
+	tracker *controllerSyncTracker
+}
+
+func (i *syncTrackedInstancesV2) InstanceExists(ctx context.Context, node *v1.Node) (bool, error) {
+	exists, err := i.InstancesV2.InstanceExists(ctx, node)
+	i.tracker.observe(err)
+	return exists, err
+}
+
+func (i *syncTrackedInstancesV2) InstanceShutdown(ctx context.Context, node *v1.Node) (bool, error) {
+	shutdown, err := i.InstancesV2.InstanceShutdown(ctx, node)
+	i.tracker.observe(err)
+	return shutdown, err
+}
+
+func (i *syncTrackedInstancesV2) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudprovider.InstanceMetadata, error) {
+	metadata, err := i.InstancesV2.InstanceMetadata(ctx, node)
+	if errors.Is(err, cloudprovider.InstanceNotFound) {
+		// the instance of the node is deleted, which is not a failure of the controller
+		i.tracker.observe(nil)
+	} else {
+		i.tracker.observe(err)
+	}
+	return metadata, err
+}
+
+type syncTrackedLoadBalancer struct {
+	cloudprovider.LoadBalancer
+	tracker *controllerSyncTracker
+}
+
+func (lb *syncTrackedLoadBalancer) GetLoadBalancer(ctx context.Context, clusterName string, service *v1.Service) (*v1.LoadBalancerStatus, bool, error) {
+	status, exists, err := lb.LoadBalancer.GetLoadBalancer(ctx, clusterName, service)
+	lb.tracker.observe(err)
+	return status, exists, err
+}
+
+func (lb *syncTrackedLoadBalancer) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
+	status, err := lb.LoadBalancer.EnsureLoadBalancer(ctx, clusterName, service, nodes)
+	lb.tracker.observe(err)
+	return status, err
+}
+
+func (lb *syncTrackedLoadBalancer) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) error {
+	err := lb.LoadBalancer.UpdateLoadBalancer(ctx, clusterName, service, nodes)
+	lb.tracker.observe(err)
+	return err
+}
+
+func (lb *syncTrackedLoadBalancer) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) error {
+	err := lb.LoadBalancer.EnsureLoadBalancerDeleted(ctx, clusterName, service)
+	lb.tracker.observe(err)
+	return err
+}
+
+type syncTrackedRoutes struct {
+	cloudprovider.Routes
+	tracker *controllerSyncTracker
+}
+
+func (r *syncTrackedRoutes) ListRoutes(ctx context.Context, clusterName string) ([]*cloudprovider.Route, error) {
+	routes, err := r.Routes.ListRoutes(ctx, clusterName)
+	r.tracker.observe(err)
+	return routes, err
+}
+
+func (r *syncTrackedRoutes) CreateRoute(ctx context.Context, clusterName string, nameHint string, route *cloudprovider.Route) error {
+	err := r.Routes.CreateRoute(ctx, clusterName, nameHint, route)
+	r.tracker.observe(err)
+	return err
+}
+
+func (r *syncTrackedRoutes) DeleteRoute(ctx context.Context, clusterName string, route *cloudprovider.Route) error {
+	err := r.Routes.DeleteRoute(ctx, clusterName, route)
+	r.tracker.observe(err)
+	return err
+}
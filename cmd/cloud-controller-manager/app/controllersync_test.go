@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+func TestControllerSyncTracker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newControllerSyncTracker("node-route-controller", time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	assert.Equal(t, "node-route-controller", tracker.Name())
+	assert.NoError(t, tracker.Check(nil), "an idle controller is healthy")
+
+	tracker.observe(errors.New("throttled"))
+	now = now.Add(2 * time.Minute)
+	assert.EqualError(t, tracker.Check(nil), "controller node-route-controller has not synced since it started, failing since 2026-01-01T00:00:00Z: throttled")
+
+	tracker.observe(nil)
+	assert.NoError(t, tracker.Check(nil))
+	lastSyncTime := now
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, tracker.Check(nil), "the controller is not stale without failures")
+	tracker.observe(errors.New("unauthorized"))
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, tracker.Check(nil), "the controller is not stale within the staleness window")
+	now = now.Add(time.Minute)
+	assert.EqualError(t, tracker.Check(nil), "controller node-route-controller has not synced since 2026-01-01T00:02:00Z, failing since 2026-01-01T01:02:00Z: unauthorized")
+
+	status := tracker.status()
+	assert.Equal(t, lastSyncTime, *status.LastSyncTime)
+	assert.Equal(t, "unauthorized", status.LastError)
+	assert.True(t, status.Stale)
+
+	tracker.staleness = 0
+	assert.NoError(t, tracker.Check(nil), "the check never fails without the staleness window")
+}
+
+func TestControllerSyncRegistry(t *testing.T) {
+	r := newControllerSyncRegistry()
+	r.track("service-lb-controller", time.Minute).observe(nil)
+	r.track("node-ipam", time.Minute).observe(errors.New("conflict"))
+	assert.NotNil(t, r.get("node-ipam"))
+	assert.Nil(t, r.get("cloud-node-controller"))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ControllerSyncPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var statuses []controllerSyncStatus
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "node-ipam", statuses[0].Name)
+	assert.Nil(t, statuses[0].LastSyncTime)
+	assert.Equal(t, "conflict", statuses[0].LastError)
+	assert.Equal(t, "service-lb-controller", statuses[1].Name)
+	assert.NotNil(t, statuses[1].LastSyncTime)
+	assert.Nil(t, statuses[1].FailingSince)
+
+	controllerStatuses := r.controllerStatuses()
+	assert.Len(t, controllerStatuses, 2)
+	assert.Equal(t, "node-ipam", controllerStatuses[0].Name)
+	assert.Equal(t, "conflict", controllerStatuses[0].Error)
+	assert.NotNil(t, controllerStatuses[0].FailingSince)
+	assert.Nil(t, controllerStatuses[0].LastSyncTime)
+	assert.Equal(t, "service-lb-controller", controllerStatuses[1].Name)
+	assert.Equal(t, statuses[1].LastSyncTime.Unix(), controllerStatuses[1].LastSyncTime.Unix())
+
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ControllerSyncPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	r.reset()
+	assert.Nil(t, r.get("node-ipam"))
+}
+
+type fakeSyncCloud struct {
+	cloudprovider.Interface
+	err error
+}
+
+func (c *fakeSyncCloud) InstancesV2() (cloudprovider.InstancesV2, bool) {
+	return &fakeSyncInstancesV2{err: c.err}, true
+}
+
+func (c *fakeSyncCloud) Routes() (cloudprovider.Routes, bool) {
+	return nil, false
+}
+
+type fakeSyncInstancesV2 struct {
+	cloudprovider.InstancesV2
+	err error
+}
+
+func (i *fakeSyncInstancesV2) InstanceMetadata(_ context.Context, _ *v1.Node) (*cloudprovider.InstanceMetadata, error) {
+	return &cloudprovider.InstanceMetadata{}, i.err
+}
+
+func TestSyncTrackedCloud(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		err           error
+		expectedError string
+	}{
+		{desc: "a successful call syncs the controller"},
+		{desc: "a deleted instance syncs the controller", err: cloudprovider.InstanceNotFound},
+		{desc: "a failed call does not sync the controller", err: errors.New("throttled"), expectedError: "throttled"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tracker := newControllerSyncTracker("cloud-node-controller", time.Minute)
+			cloud := newSyncTrackedCloud(&fakeSyncCloud{err: tc.err}, tracker)
+
+			_, ok := cloud.Routes()
+			assert.False(t, ok)
+			instances, ok := cloud.InstancesV2()
+			assert.True(t, ok)
+			_, err := instances.InstanceMetadata(context.Background(), &v1.Node{})
+			assert.Equal(t, tc.err, err)
+
+			status := tracker.status()
+			assert.Equal(t, tc.expectedError == "", status.LastSyncTime != nil)
+			assert.Equal(t, tc.expectedError, status.LastError)
+		})
+	}
+}
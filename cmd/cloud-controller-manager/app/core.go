@@ -26,32 +26,46 @@ import (
 	"net/http"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/labels"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
 	cloudprovider "k8s.io/cloud-provider"
 	nodecontroller "k8s.io/cloud-provider/controllers/node"
 	nodelifecyclecontroller "k8s.io/cloud-provider/controllers/nodelifecycle"
 	routecontroller "k8s.io/cloud-provider/controllers/route"
 	servicecontroller "k8s.io/cloud-provider/controllers/service"
+	"k8s.io/cloud-provider/names"
 	genericcontrollermanager "k8s.io/controller-manager/app"
 	"k8s.io/klog/v2"
 	netutils "k8s.io/utils/net"
 
 	cloudcontrollerconfig "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/frontendrepair"
+	"sigs.k8s.io/cloud-provider-azure/pkg/loadbalancerrepair"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeannotator"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodedeletion"
 	nodeipamcontroller "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam"
 	nodeipamconfig "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodepooloutbound"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeproviderid"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	informerutil "sigs.k8s.io/cloud-provider-azure/pkg/util/informer"
 )
 
 func startCloudNodeController(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	// The workers of the CloudNodeController both initialize the new nodes and update the status of the nodes.
+	workers := completedConfig.ControllerWorkersConfig.GetConcurrentNodeStatusUpdates(completedConfig.ComponentConfig.NodeController.ConcurrentNodeSyncs)
 	// Start the CloudNodeController
 	nodeController, err := nodecontroller.NewCloudNodeController(
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		// cloud node controller uses existing cluster role from node-controller
 		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
-		cloud,
+		newSyncTrackedCloud(cloud, controllerSyncs.track(names.CloudNodeController, completedConfig.ControllerSyncStaleness)),
 		completedConfig.ComponentConfig.NodeStatusUpdateFrequency.Duration,
-		completedConfig.ComponentConfig.NodeController.ConcurrentNodeSyncs,
+		workers,
 	)
 	if err != nil {
 		klog.Warningf("failed to start cloud node controller: %s", err)
@@ -69,7 +83,7 @@ func startCloudNodeLifecycleController(ctx context.Context, controllerContext ge
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		// cloud node lifecycle controller uses existing cluster role from node-controller
 		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
-		cloud,
+		newSyncTrackedCloud(cloud, controllerSyncs.track(names.CloudNodeLifecycleController, completedConfig.ControllerSyncStaleness)),
 		completedConfig.ComponentConfig.KubeCloudShared.NodeMonitorPeriod.Duration,
 	)
 	if err != nil {
@@ -83,11 +97,28 @@ func startCloudNodeLifecycleController(ctx context.Context, controllerContext ge
 }
 
 func startServiceController(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	// The service controller only reconciles the services without a load balancer class. The
+	// services of the accepted classes are fed to it from a dedicated informer clearing their
+	// class, and the cloud provider leaves the services of the other classes alone as well.
+	services := completedConfig.SharedInformers.Core().V1().Services()
+	var serviceInformers informers.SharedInformerFactory
+	if len(completedConfig.LoadBalancerClasses) > 0 {
+		serviceInformers = informers.NewSharedInformerFactoryWithOptions(
+			completedConfig.ClientBuilder.ClientOrDie("service-controller-informers"),
+			ResyncPeriod(completedConfig)(),
+			informers.WithTransform(informerutil.AcceptLoadBalancerClasses(completedConfig.LoadBalancerClasses)),
+		)
+		services = serviceInformers.Core().V1().Services()
+	}
+	if setter, ok := cloud.(loadBalancerClassesSetter); ok {
+		setter.SetLoadBalancerClasses(completedConfig.LoadBalancerClasses)
+	}
+
 	// Start the service controller
 	serviceController, err := servicecontroller.New(
-		cloud,
+		newSyncTrackedCloud(cloud, controllerSyncs.track(names.ServiceLBController, completedConfig.ControllerSyncStaleness)),
 		completedConfig.ClientBuilder.ClientOrDie("service-controller"),
-		completedConfig.SharedInformers.Core().V1().Services(),
+		services,
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
 		utilfeature.DefaultFeatureGate,
@@ -98,11 +129,162 @@ func startServiceController(ctx context.Context, controllerContext genericcontro
 		return nil, false, nil
 	}
 
+	if serviceInformers != nil {
+		serviceInformers.Start(ctx.Done())
+	}
 	go serviceController.Run(ctx, int(completedConfig.ComponentConfig.ServiceController.ConcurrentServiceSyncs), controllerContext.ControllerManagerMetrics)
 
+	// serve the load balancer preview at /debug/controllers/service-lb-controller?namespace=<namespace>&name=<name>
+	if previewer, ok := cloud.(loadBalancerPreviewer); ok {
+		return previewer.LoadBalancerPreviewHandler(completedConfig.ComponentConfig.KubeCloudShared.ClusterName), true, nil
+	}
+
+	return nil, true, nil
+}
+
+// loadBalancerClassesSetter is implemented by the cloud provider reconciling the services of
+// the load balancer classes besides the ones without a class.
+type loadBalancerClassesSetter interface {
+	SetLoadBalancerClasses(classes []string)
+}
+
+// loadBalancerPreviewer is implemented by the cloud provider serving the load balancer preview.
+type loadBalancerPreviewer interface {
+	LoadBalancerPreviewHandler(clusterName string) http.Handler
+}
+
+func startNodeAnnotatorController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	labelsProvider, ok := cloud.(nodeannotator.HardwareLabelsProvider)
+	if !ok {
+		klog.Warning("node-annotator controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	nodeAnnotatorController := nodeannotator.NewController(
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		// node annotator controller uses existing cluster role from node-controller
+		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
+		labelsProvider,
+		provider.NodeHardwareLabelKeys,
+		completedConfig.ComponentConfig.Generic.MinResyncPeriod.Duration,
+	)
+
+	go nodeAnnotatorController.Run(ctx, getControllerWorkers(completedConfig.ControllerWorkersConfig.ConcurrentNodeAnnotatorSyncs, completedConfig.ComponentConfig.NodeController.ConcurrentNodeSyncs))
+
+	return nil, true, nil
+}
+
+func startNodeProviderIDController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	resolver, ok := cloud.(nodeproviderid.ProviderIDResolver)
+	if !ok {
+		klog.Warning("node-provider-id controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	nodeProviderIDController := nodeproviderid.NewController(
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		// node provider ID controller uses existing cluster role from node-controller
+		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
+		resolver,
+		completedConfig.ComponentConfig.Generic.MinResyncPeriod.Duration,
+	)
+
+	go nodeProviderIDController.Run(ctx, getControllerWorkers(completedConfig.ControllerWorkersConfig.ConcurrentNodeProviderIDSyncs, completedConfig.ComponentConfig.NodeController.ConcurrentNodeSyncs))
+
+	return nil, true, nil
+}
+
+func startLoadBalancerRepairController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	repairer, ok := cloud.(loadbalancerrepair.Repairer)
+	if !ok {
+		klog.Warning("load-balancer-repair controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	// the services are read by the cloud provider to find the resources of the deleted services
+	loadBalancerRepairController := loadbalancerrepair.NewController(
+		repairer,
+		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
+		completedConfig.LoadBalancerRepairPeriod,
+		completedConfig.SharedInformers.Core().V1().Services().Informer().HasSynced,
+	)
+
+	go loadBalancerRepairController.Run(ctx)
+
+	return nil, true, nil
+}
+
+func startLoadBalancerFrontendRepairController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	repairer, ok := cloud.(frontendrepair.Repairer)
+	if !ok {
+		klog.Warning("load-balancer-frontend-repair controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	// the frontends are checked as often as the load balancers by the load-balancer-repair controller
+	frontendRepairController := frontendrepair.NewController(
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		completedConfig.SharedInformers.Core().V1().Services().Informer().HasSynced,
+		repairer,
+		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
+		completedConfig.LoadBalancerRepairPeriod,
+	)
+
+	go frontendRepairController.Run(ctx)
+
+	return nil, true, nil
+}
+
+func startNodePoolOutboundController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	reconciler, ok := cloud.(nodepooloutbound.Reconciler)
+	if !ok {
+		klog.Warning("node-pool-outbound controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	// the reconciliation is a no-op unless the node pool outbound configurations are set in the cloud config
+	nodePoolOutboundController := nodepooloutbound.NewController(
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		reconciler,
+		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
+		completedConfig.ComponentConfig.Generic.MinResyncPeriod.Duration,
+	)
+
+	go nodePoolOutboundController.Run(ctx)
+
 	return nil, true, nil
 }
 
+func startNodeDeletionController(ctx context.Context, _ genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
+	reconciler, ok := cloud.(nodedeletion.Reconciler)
+	if !ok {
+		klog.Warning("node-deletion controller is not supported by the cloud provider")
+		return nil, false, nil
+	}
+
+	// the routes are only deleted if they are configured by the route controller
+	nodeDeletionController := nodedeletion.NewController(
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		completedConfig.SharedInformers.Core().V1().Services().Informer().HasSynced,
+		reconciler,
+		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
+		completedConfig.ComponentConfig.KubeCloudShared.ConfigureCloudRoutes,
+	)
+
+	go nodeDeletionController.Run(ctx)
+
+	return nil, true, nil
+}
+
+// getControllerWorkers returns the worker pool size of a controller, which falls back to the
+// worker pool size of the node controller if it is not set.
+func getControllerWorkers(workers, concurrentNodeSyncs int32) int {
+	if workers > 0 {
+		return int(workers)
+	}
+	return int(concurrentNodeSyncs)
+}
+
 func startRouteController(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface) (http.Handler, bool, error) {
 	if !completedConfig.ComponentConfig.KubeCloudShared.ConfigureCloudRoutes {
 		klog.Infof("Will not configure cloud provider routes, --configure-cloud-routes: %v.", completedConfig.ComponentConfig.KubeCloudShared.ConfigureCloudRoutes)
@@ -132,8 +314,9 @@ func startRouteController(ctx context.Context, controllerContext genericcontroll
 		return nil, false, fmt.Errorf("length of clusterCIDRs is:%v more than max allowed of 2", len(clusterCIDRs))
 	}
 
+	routeTracker := controllerSyncs.track(names.NodeRouteController, completedConfig.ControllerSyncStaleness)
 	routeController := routecontroller.New(
-		routes,
+		&syncTrackedRoutes{Routes: routes, tracker: routeTracker},
 		completedConfig.ClientBuilder.ClientOrDie("route-controller"),
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
@@ -205,21 +388,52 @@ func startNodeIpamController(ctx context.Context, _ genericcontrollermanager.Con
 	// get list of node cidr mask sizes
 	nodeCIDRMaskSizes := getNodeCIDRMaskSizes(clusterCIDRs, nodeCIDRMaskSizeIPv4, nodeCIDRMaskSizeIPv6)
 
+	nodePoolCIDRMaskSizes, err := getNodePoolCIDRMaskSizes(completedConfig.NodeIPAMControllerConfig.NodePoolCIDRMaskSizes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the additional cluster cidrs are read from the cloud config, so they are picked up when the
+	// controllers are restarted after the cloud config is reloaded
+	var additionalClusterCIDRs []*net.IPNet
+	var nodePoolClusterCIDRs []ipam.NodePoolClusterCIDR
+	if az, ok := cloud.(*provider.Cloud); ok {
+		if len(az.AdditionalClusterCIDRs) > 0 {
+			additionalClusterCIDRs, err = netutils.ParseCIDRs(az.AdditionalClusterCIDRs)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		nodePoolClusterCIDRs, err = getNodePoolClusterCIDRs(az.NodePoolClusterCIDRs)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
 	nodeIpamController, err := nodeipamcontroller.NewNodeIpamController(
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		cloud,
 		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
 		clusterCIDRs,
+		additionalClusterCIDRs,
 		serviceCIDR,
 		secondaryServiceCIDR,
 		nodeCIDRMaskSizes,
+		nodePoolCIDRMaskSizes,
+		nodePoolClusterCIDRs,
+		int(completedConfig.NodeIPAMControllerConfig.CIDRUtilizationThreshold),
+		completedConfig.NodeIPAMControllerConfig.PodSubnetName,
+		completedConfig.NodeIPAMControllerConfig.NodeCIDRStateFile,
 		ipam.CIDRAllocatorType(completedConfig.ComponentConfig.KubeCloudShared.CIDRAllocatorType),
+		controllerSyncs.track(nodeIPAMControllerName, completedConfig.ControllerSyncStaleness).observe,
 	)
 	if err != nil {
 		return nil, true, err
 	}
 	go nodeIpamController.Run(ctx)
-	return nil, true, nil
+
+	// export the pod cidrs of the nodes at /debug/controllers/node-ipam
+	return nodeIpamController.CIDRStateHandler(), true, nil
 }
 
 // setNodeCIDRMaskSizesDualStack returns the IPv4 and IPv6 node cidr mask sizes to the value provided
@@ -258,6 +472,42 @@ func getNodeCIDRMaskSizes(clusterCIDRs []*net.IPNet, maskSizeIPv4, maskSizeIPv6
 	return nodeMaskCIDRs
 }
 
+// getNodePoolCIDRMaskSizes parses the label selectors of the node pool cidr mask sizes
+func getNodePoolCIDRMaskSizes(nodePools []nodeipamconfig.NodePoolCIDRMaskSize) ([]ipam.NodePoolCIDRMaskSize, error) {
+	nodePoolCIDRMaskSizes := make([]ipam.NodePoolCIDRMaskSize, 0, len(nodePools))
+	for _, nodePool := range nodePools {
+		selector, err := labels.Parse(nodePool.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the node selector %q of the node pool cidr mask size: %w", nodePool.NodeSelector, err)
+		}
+		nodePoolCIDRMaskSizes = append(nodePoolCIDRMaskSizes, ipam.NodePoolCIDRMaskSize{
+			Selector:         selector,
+			NodeCIDRMaskSize: int(nodePool.NodeCIDRMaskSize),
+		})
+	}
+	return nodePoolCIDRMaskSizes, nil
+}
+
+// getNodePoolClusterCIDRs parses the label selectors and cluster cidrs of the node pools in the cloud config
+func getNodePoolClusterCIDRs(nodePools []azureconfig.NodePoolClusterCIDR) ([]ipam.NodePoolClusterCIDR, error) {
+	nodePoolClusterCIDRs := make([]ipam.NodePoolClusterCIDR, 0, len(nodePools))
+	for _, nodePool := range nodePools {
+		selector, err := labels.Parse(nodePool.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the node selector %q of the node pool cluster cidrs: %w", nodePool.NodeSelector, err)
+		}
+		clusterCIDRs, err := netutils.ParseCIDRs(nodePool.ClusterCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the cluster cidrs of node pool %q: %w", nodePool.NodeSelector, err)
+		}
+		nodePoolClusterCIDRs = append(nodePoolClusterCIDRs, ipam.NodePoolClusterCIDR{
+			Selector:     selector,
+			ClusterCIDRs: clusterCIDRs,
+		})
+	}
+	return nodePoolClusterCIDRs, nil
+}
+
 // processCIDRs is a helper function that works on a comma separated cidrs and returns
 // a list of typed cidrs
 // a flag if cidrs represents a dual stack
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	cloudcontrollerconfig "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
 	nodeipamconfig "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/config"
 )
 
@@ -66,3 +67,34 @@ func TestSetNodeCIDRMaskSizesDualStack(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConcurrentNodeStatusUpdates(t *testing.T) {
+	for _, testCase := range []struct {
+		description                 string
+		concurrentNodeStatusUpdates int32
+		concurrentNodeSyncs         int32
+		expected                    int32
+	}{
+		{
+			description:         "the default should be used if --concurrent-node-syncs is smaller",
+			concurrentNodeSyncs: 1,
+			expected:            cloudcontrollerconfig.DefaultConcurrentNodeStatusUpdates,
+		},
+		{
+			description:         "--concurrent-node-syncs should be used if it is larger than the default",
+			concurrentNodeSyncs: 20,
+			expected:            20,
+		},
+		{
+			description:                 "--concurrent-node-status-updates should be used if it is set",
+			concurrentNodeStatusUpdates: 5,
+			concurrentNodeSyncs:         20,
+			expected:                    5,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			cfg := cloudcontrollerconfig.ControllerWorkersConfig{ConcurrentNodeStatusUpdates: testCase.concurrentNodeStatusUpdates}
+			assert.Equal(t, testCase.expected, cfg.GetConcurrentNodeStatusUpdates(testCase.concurrentNodeSyncs))
+		})
+	}
+}
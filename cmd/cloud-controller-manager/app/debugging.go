@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+// ControllerDebuggingPath is the path prefix of the debugging handlers of the controllers.
+const ControllerDebuggingPath = "/debug/controllers/"
+
+// controllerDebuggingHandler serves the debugging handler of each controller under
+// /debug/controllers/<controller name>. The handlers are replaced when the controllers
+// are restarted after the cloud config is reloaded.
+type controllerDebuggingHandler struct {
+	lock     sync.RWMutex
+	handlers map[string]http.Handler
+}
+
+// controllerDebuggingHandlers is shared by the HTTP server and the controllers.
+var controllerDebuggingHandlers = newControllerDebuggingHandler()
+
+func newControllerDebuggingHandler() *controllerDebuggingHandler {
+	return &controllerDebuggingHandler{
+		handlers: make(map[string]http.Handler),
+	}
+}
+
+// set sets the debugging handler of the controller.
+func (h *controllerDebuggingHandler) set(controllerName string, handler http.Handler) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.handlers[controllerName] = handler
+}
+
+func (h *controllerDebuggingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	controllerName, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, ControllerDebuggingPath), "/")
+
+	h.lock.RLock()
+	handler, ok := h.handlers[controllerName]
+	h.lock.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// StateDebuggingPath is the path of the dump of the internal state of the cloud provider.
+const StateDebuggingPath = "/debug/state"
+
+// stateDumper is implemented by the cloud providers dumping their internal state for debugging.
+type stateDumper interface {
+	// DumpState returns the internal state, which is encoded as JSON.
+	DumpState(ctx context.Context) interface{}
+}
+
+// stateDebuggingHandler serves the internal state of the cloud provider as JSON on /debug/state. The
+// cloud provider is replaced when it is initialized again after the cloud config is reloaded.
+type stateDebuggingHandler struct {
+	lock   sync.RWMutex
+	dumper stateDumper
+}
+
+// cloudStateDebuggingHandler is shared by the HTTP server and the controllers.
+var cloudStateDebuggingHandler = &stateDebuggingHandler{}
+
+// set sets the cloud provider whose state is served.
+func (h *stateDebuggingHandler) set(dumper stateDumper) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.dumper = dumper
+}
+
+func (h *stateDebuggingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.lock.RLock()
+	dumper := h.dumper
+	h.lock.RUnlock()
+	if dumper == nil {
+		http.Error(w, "the cloud provider is not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(dumper.DumpState(r.Context())); err != nil {
+		klog.Errorf("failed to encode the state of the cloud provider: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerDebuggingHandler(t *testing.T) {
+	h := newControllerDebuggingHandler()
+	h.set("service-lb-controller", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for _, tc := range []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/debug/controllers/service-lb-controller", expectedStatus: http.StatusTeapot},
+		{path: "/debug/controllers/service-lb-controller/preview", expectedStatus: http.StatusTeapot},
+		{path: "/debug/controllers/node-route-controller", expectedStatus: http.StatusNotFound},
+	} {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		assert.Equal(t, tc.expectedStatus, recorder.Code, tc.path)
+	}
+}
+
+type fakeStateDumper struct{}
+
+func (fakeStateDumper) DumpState(_ context.Context) interface{} {
+	return map[string]string{"services": "none"}
+}
+
+func TestStateDebuggingHandler(t *testing.T) {
+	h := &stateDebuggingHandler{}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, StateDebuggingPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	h.set(fakeStateDumper{})
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, StateDebuggingPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"services": "none"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, StateDebuggingPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	cloudcontrollerconfig "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// warmStandbyCacheRefreshInterval is the interval of refreshing the expired entries of the Azure caches
+// of the cloud kept warm on a non-leader replica.
+const warmStandbyCacheRefreshInterval = time.Minute
+
+var (
+	leaderElectionIsLeader = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "leader_election_is_leader",
+			Help:           "Whether the replica is the leader, 1 if it is and 0 otherwise",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	leaderElectionTransitions = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "leader_election_transitions_total",
+			Help:           "Number of the changes of the leader observed by the replica",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	leaderElectionWarmStandby = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "leader_election_warm_standby",
+			Help:           "Whether the replica keeps its informers and Azure caches warm as a non-leader, 1 if it does and 0 otherwise",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	registerLeaderElectionMetricsOnce sync.Once
+)
+
+func registerLeaderElectionMetrics() {
+	registerLeaderElectionMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(leaderElectionIsLeader)
+		legacyregistry.MustRegister(leaderElectionTransitions)
+		legacyregistry.MustRegister(leaderElectionWarmStandby)
+	})
+}
+
+// standbyCloud is the cloud kept warm by the non-leader replica, which is taken over by Run when the
+// replica becomes the leader.
+var standbyCloud atomic.Pointer[provider.Cloud]
+
+// leaderElectionObserver updates the leader election metrics from the callbacks of the leader elector.
+type leaderElectionObserver struct {
+	lock       sync.Mutex
+	lastLeader string
+}
+
+// newLeaderCallbacks returns the callbacks of the leader elector running run once the replica becomes
+// the leader. stopStandby is called before run to stop warming up the caches of the standby cloud.
+func newLeaderCallbacks(run func(ctx context.Context), stopStandby func(), onStoppedLeading func()) leaderelection.LeaderCallbacks {
+	registerLeaderElectionMetrics()
+	leaderElectionIsLeader.Set(0)
+	observer := &leaderElectionObserver{}
+	return leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			leaderElectionIsLeader.Set(1)
+			stopStandby()
+			run(ctx)
+		},
+		OnStoppedLeading: func() {
+			leaderElectionIsLeader.Set(0)
+			onStoppedLeading()
+		},
+		OnNewLeader: observer.onNewLeader,
+	}
+}
+
+// onNewLeader counts the transitions of the leader, the first leader observed by the replica is not
+// counted as it may have been the leader for long.
+func (o *leaderElectionObserver) onNewLeader(identity string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.lastLeader != "" && o.lastLeader != identity {
+		leaderElectionTransitions.Inc()
+	}
+	klog.V(2).Infof("onNewLeader: the leader is %s", identity)
+	o.lastLeader = identity
+}
+
+// startWarmStandby creates the cloud from the cloud config file and keeps it warm until the returned
+// function is called: the informers are started and the Azure caches are refreshed periodically, while
+// nothing is changed until the cloud is initialized by the leader. The cloud is left to Run in
+// standbyCloud. The informers keep running with ctx, as they are shared with the controllers.
+func startWarmStandby(ctx context.Context, c *cloudcontrollerconfig.CompletedConfig) (stop func()) {
+	cloudConfigFile := c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile
+	cloud, err := provider.NewCloudFromConfigFile(ctx, c.ClientBuilder, cloudConfigFile, true)
+	if err != nil {
+		klog.Errorf("startWarmStandby: failed to create the cloud from %s, the caches are not kept warm: %v", cloudConfigFile, err)
+		return func() {}
+	}
+	az, ok := cloud.(*provider.Cloud)
+	if !ok || az == nil {
+		klog.Errorf("startWarmStandby: the cloud is not created from %s, the caches are not kept warm", cloudConfigFile)
+		return func() {}
+	}
+
+	klog.Infof("startWarmStandby: keeping the informers and the Azure caches warm until becoming the leader")
+	registerLeaderElectionMetrics()
+	leaderElectionWarmStandby.Set(1)
+	az.EnterWarmStandby()
+	if c.DisablePodInformers {
+		az.DisablePodInformer()
+	}
+	az.SetInformers(c.SharedInformers)
+	c.SharedInformers.Start(ctx.Done())
+	standbyCloud.Store(az)
+
+	standbyCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.SharedInformers.WaitForCacheSync(standbyCtx.Done())
+		_ = wait.PollUntilContextCancel(standbyCtx, warmStandbyCacheRefreshInterval, true, func(ctx context.Context) (bool, error) {
+			az.WarmUpCaches(ctx)
+			return false, nil
+		})
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		leaderElectionWarmStandby.Set(0)
+		klog.Infof("startWarmStandby: stopped warming up the caches")
+	}
+}
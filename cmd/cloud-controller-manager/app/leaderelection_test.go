@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestLeaderCallbacks(t *testing.T) {
+	var calls []string
+	callbacks := newLeaderCallbacks(func(_ context.Context) {
+		isLeader, err := testutil.GetGaugeMetricValue(leaderElectionIsLeader)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(1), isLeader)
+		calls = append(calls, "run")
+	}, func() {
+		calls = append(calls, "stopStandby")
+	}, func() {
+		calls = append(calls, "stoppedLeading")
+	})
+
+	transitions := func() float64 {
+		value, err := testutil.GetCounterMetricValue(leaderElectionTransitions)
+		assert.NoError(t, err)
+		return value
+	}
+	initialTransitions := transitions()
+	// the first leader observed is not counted as a transition
+	callbacks.OnNewLeader("replica-0")
+	callbacks.OnNewLeader("replica-0")
+	assert.Equal(t, initialTransitions, transitions())
+	callbacks.OnNewLeader("replica-1")
+	assert.Equal(t, initialTransitions+1, transitions())
+
+	callbacks.OnStartedLeading(context.Background())
+	callbacks.OnStoppedLeading()
+	assert.Equal(t, []string{"stopStandby", "run", "stoppedLeading"}, calls, "the standby should be stopped before running the controllers")
+	isLeader, err := testutil.GetGaugeMetricValue(leaderElectionIsLeader)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), isLeader)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/term"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// lintServicesOptions are the options of the lint-services subcommand.
+type lintServicesOptions struct {
+	Kubeconfig          string
+	CloudConfigFile     string
+	Namespace           string
+	LoadBalancerClasses []string
+	CheckAzure          bool
+}
+
+// Flags returns the flags of the lint-services subcommand by sections.
+func (o *lintServicesOptions) Flags() cliflag.NamedFlagSets {
+	fss := cliflag.NamedFlagSets{}
+
+	fs := fss.FlagSet("cluster")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file of the cluster. The in-cluster config is used if it is not set.")
+	fs.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "The namespace of the services to check. The services of all namespaces are checked if it is not set.")
+
+	fs = fss.FlagSet("cloud provider")
+	fs.StringVar(&o.CloudConfigFile, "cloud-config", o.CloudConfigFile, "Path to the cloud config file the services are checked against.")
+	fs.StringSliceVar(&o.LoadBalancerClasses, "load-balancer-classes", o.LoadBalancerClasses, "The load balancer classes reconciled by the cloud controller manager, as set by its --load-balancer-classes.")
+	fs.BoolVar(&o.CheckAzure, "check-azure", o.CheckAzure, "Check the public IPs of the services and the load balancers in Azure. Only the annotations are checked if it is false.")
+
+	return fss
+}
+
+// newLintServicesCommand creates the lint-services subcommand, which prints the problems of the LoadBalancer
+// services found by checking them against the cloud config and the Azure resources, e.g. before upgrading
+// the cloud controller manager.
+func newLintServicesCommand() *cobra.Command {
+	o := &lintServicesOptions{CheckAzure: true}
+	cmd := &cobra.Command{
+		Use:   "lint-services",
+		Short: "Check the LoadBalancer services against the cloud config and the Azure resources",
+		Long: `The lint-services command checks the annotations of the LoadBalancer services against the cloud config and the
+Azure resources, and prints the deprecated annotations, the impossible combinations, the invalid values and the
+load balancers about to run out of load balancing rules. Nothing is changed in the cluster or in Azure.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runLintServices(cmd.Context(), o, cmd.OutOrStdout())
+		},
+	}
+
+	namedFlagSets := o.Flags()
+	for _, f := range namedFlagSets.FlagSets {
+		cmd.Flags().AddFlagSet(f)
+	}
+	usageFmt := "Usage:\n  %s\n"
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		fmt.Fprintf(cmd.OutOrStderr(), usageFmt, cmd.UseLine())
+		cliflag.PrintSections(cmd.OutOrStderr(), namedFlagSets, cols)
+		return nil
+	})
+	cmd.SetHelpFunc(func(cmd *cobra.Command, _ []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
+		cliflag.PrintSections(cmd.OutOrStdout(), namedFlagSets, cols)
+	})
+
+	return cmd
+}
+
+func runLintServices(ctx context.Context, o *lintServicesOptions, out io.Writer) error {
+	if o.CloudConfigFile == "" {
+		return errors.New("--cloud-config is required")
+	}
+
+	kubeconfig, err := clientcmd.BuildConfigFromFlags("", o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create the kubernetes client: %w", err)
+	}
+
+	cloud, err := provider.NewCloudFromConfigFile(ctx, nil, o.CloudConfigFile, false)
+	if err != nil {
+		return err
+	}
+	az, ok := cloud.(*provider.Cloud)
+	if !ok {
+		return fmt.Errorf("unexpected cloud provider %T", cloud)
+	}
+	az.SetLoadBalancerClasses(o.LoadBalancerClasses)
+
+	serviceList, err := kubeClient.CoreV1().Services(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the services: %w", err)
+	}
+	return printLintWarnings(ctx, az, serviceList.Items, o.CheckAzure, out)
+}
+
+// serviceLinter is implemented by the cloud providers checking the LoadBalancer services.
+type serviceLinter interface {
+	LintServices(ctx context.Context, services []*v1.Service, checkAzure bool) ([]provider.LintWarning, error)
+}
+
+// printLintWarnings prints the warnings of the services one per line, followed by a summary.
+func printLintWarnings(ctx context.Context, linter serviceLinter, services []v1.Service, checkAzure bool, out io.Writer) error {
+	servicePtrs := make([]*v1.Service, 0, len(services))
+	for i := range services {
+		servicePtrs = append(servicePtrs, &services[i])
+	}
+	warnings, err := linter.LintServices(ctx, servicePtrs, checkAzure)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "%s: %s\n", warning.Resource, warning.Message)
+	}
+	fmt.Fprintf(out, "%d warning(s) found\n", len(warnings))
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+type fakeServiceLinter struct {
+	services   []string
+	checkAzure bool
+}
+
+func (l *fakeServiceLinter) LintServices(_ context.Context, services []*v1.Service, checkAzure bool) ([]provider.LintWarning, error) {
+	for _, service := range services {
+		l.services = append(l.services, service.Name)
+	}
+	l.checkAzure = checkAzure
+	return []provider.LintWarning{
+		{Resource: "LoadBalancer kubernetes", Message: "too many rules"},
+		{Resource: "Service default/svc1", Message: "deprecated annotation"},
+	}, nil
+}
+
+func TestPrintLintWarnings(t *testing.T) {
+	linter := &fakeServiceLinter{}
+	services := []v1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "svc2", Namespace: "default"}},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, printLintWarnings(context.Background(), linter, services, true, &out))
+	assert.Equal(t, []string{"svc1", "svc2"}, linter.services)
+	assert.True(t, linter.checkAzure)
+	assert.Equal(t, `LoadBalancer kubernetes: too many rules
+Service default/svc1: deprecated annotation
+2 warning(s) found
+`, out.String())
+}
+
+func TestLintServicesCommandRequiresCloudConfig(t *testing.T) {
+	cmd := newLintServicesCommand()
+	cmd.SetArgs([]string{"--check-azure=false"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	assert.EqualError(t, cmd.Execute(), "--cloud-config is required")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	app "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/eventrecorder"
+)
+
+// EventsOptions holds the aggregation policy of the events of the controller manager
+type EventsOptions struct {
+	Burst           int32
+	QPS             float64
+	SummaryInterval time.Duration
+}
+
+func defaultEventsOptions() *EventsOptions {
+	return &EventsOptions{
+		Burst:           eventrecorder.DefaultBurst,
+		QPS:             eventrecorder.DefaultQPS,
+		SummaryInterval: eventrecorder.DefaultSummaryInterval,
+	}
+}
+
+// AddFlags adds flags related to the events for controller manager to the specified FlagSet
+func (o *EventsOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.Burst, "event-burst", o.Burst, "The number of events of an object with the same type and reason emitted at once before they are rate limited. The events are not rate limited if it is 0.")
+	fs.Float64Var(&o.QPS, "event-qps", o.QPS, "The rate of the events of an object with the same type and reason emitted after the burst.")
+	fs.DurationVar(&o.SummaryInterval, "event-summary-interval", o.SummaryInterval, "The interval of the summary events counting the events suppressed by the rate limits.")
+}
+
+// ApplyTo fills up the events config with options
+func (o *EventsOptions) ApplyTo(cfg *app.EventsConfig) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.Burst = o.Burst
+	cfg.QPS = o.QPS
+	cfg.SummaryInterval = o.SummaryInterval
+
+	return nil
+}
+
+// Validate checks validation of EventsOptions
+func (o *EventsOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.Burst < 0 {
+		errs = append(errs, fmt.Errorf("--event-burst must not be negative, got %d", o.Burst))
+	}
+	if o.Burst > 0 && o.QPS <= 0 {
+		errs = append(errs, fmt.Errorf("--event-qps must be positive, got %v", o.QPS))
+	}
+	if o.SummaryInterval < 0 {
+		errs = append(errs, fmt.Errorf("--event-summary-interval must not be negative, got %v", o.SummaryInterval))
+	}
+	return errs
+}
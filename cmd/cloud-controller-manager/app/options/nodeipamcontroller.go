@@ -18,9 +18,11 @@ package options
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	nodeipamconfig "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/config"
@@ -38,8 +40,50 @@ func (o *NodeIPAMControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	}
 	fs.StringVar(&o.ServiceCIDR, "service-cluster-ip-range", "", "CIDR Range for Services in cluster. Requires --allocate-node-cidrs to be true")
 	fs.Int32Var(&o.NodeCIDRMaskSize, "node-cidr-mask-size", consts.DefaultNodeCIDRMaskSize, "Mask size for node cidr in cluster. Default is 24 for IPv4 and 64 for IPv6.")
-	fs.Int32Var(&o.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4", 0, "Mask size for IPv4 node cidr in dual-stack cluster. Default is 24.")
-	fs.Int32Var(&o.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6", 0, "Mask size for IPv6 node cidr in dual-stack cluster. Default is 64.")
+	fs.Int32Var(&o.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4", 0, "Mask size for IPv4 node cidr in dual-stack cluster, allocated from the IPv4 entry of --cluster-cidr. Default is 24.")
+	fs.Int32Var(&o.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6", 0, "Mask size for IPv6 node cidr in dual-stack cluster, allocated from the IPv6 entry of --cluster-cidr. Default is 64.")
+	fs.Var(&nodePoolCIDRMaskSizesValue{&o.NodePoolCIDRMaskSizes}, "node-pool-cidr-mask-size", "Mask size for IPv4 node cidr of the nodes matching a label selector, in the format of <label-selector>:<mask-size>, e.g. agentpool=small:26. "+
+		"Can be specified multiple times, and the first one matching the node is used. The nodes matching none of them use the default mask size. Requires --cidr-allocator-type=RangeAllocator or SubnetAllocator.")
+	fs.Int32Var(&o.CIDRUtilizationThreshold, "cidr-utilization-threshold", 0, "Percentage of the allocated node cidrs of a cluster cidr to record a warning event at, 0 disables it. Requires --cidr-allocator-type=RangeAllocator or SubnetAllocator.")
+	fs.StringVar(&o.PodSubnetName, "pod-subnet-name", "", "Name of the subnet in the virtual network of the cluster to allocate the node cidrs from. The subnet must be dedicated to the node cidrs: it can't be delegated, e.g. to Azure CNI, or have IP configurations. Requires --cidr-allocator-type=SubnetAllocator.")
+	fs.StringVar(&o.NodeCIDRStateFile, "node-cidr-state-file", "", "Path of the file of the pod cidrs of the nodes, in the format exported at /debug/controllers/node-ipam, to assign back to the nodes registered without pod cidrs instead of allocating new ones, "+
+		"e.g. when migrating from the NodeIPAM of kube-controller-manager. The file is read when the controller starts. Requires --cidr-allocator-type=RangeAllocator or SubnetAllocator.")
+}
+
+// nodePoolCIDRMaskSizesValue is the flag value of the node pool cidr mask sizes.
+type nodePoolCIDRMaskSizesValue struct {
+	nodePools *[]nodeipamconfig.NodePoolCIDRMaskSize
+}
+
+func (v *nodePoolCIDRMaskSizesValue) String() string {
+	if v.nodePools == nil {
+		return ""
+	}
+	values := make([]string, 0, len(*v.nodePools))
+	for _, nodePool := range *v.nodePools {
+		values = append(values, fmt.Sprintf("%s:%d", nodePool.NodeSelector, nodePool.NodeCIDRMaskSize))
+	}
+	return strings.Join(values, ",")
+}
+
+func (v *nodePoolCIDRMaskSizesValue) Set(value string) error {
+	idx := strings.LastIndex(value, ":")
+	if idx <= 0 {
+		return fmt.Errorf("%q is not in the format of <label-selector>:<mask-size>", value)
+	}
+	maskSize, err := strconv.ParseInt(value[idx+1:], 10, 32)
+	if err != nil {
+		return fmt.Errorf("failed to parse the mask size of %q: %w", value, err)
+	}
+	*v.nodePools = append(*v.nodePools, nodeipamconfig.NodePoolCIDRMaskSize{
+		NodeSelector:     value[:idx],
+		NodeCIDRMaskSize: int32(maskSize),
+	})
+	return nil
+}
+
+func (v *nodePoolCIDRMaskSizesValue) Type() string {
+	return "string"
 }
 
 // ApplyTo fills up NodeIpamController config with options.
@@ -60,6 +104,10 @@ func (o *NodeIPAMControllerOptions) ApplyTo(cfg *nodeipamconfig.NodeIPAMControll
 	cfg.NodeCIDRMaskSize = o.NodeCIDRMaskSize
 	cfg.NodeCIDRMaskSizeIPv4 = o.NodeCIDRMaskSizeIPv4
 	cfg.NodeCIDRMaskSizeIPv6 = o.NodeCIDRMaskSizeIPv6
+	cfg.NodePoolCIDRMaskSizes = o.NodePoolCIDRMaskSizes
+	cfg.CIDRUtilizationThreshold = o.CIDRUtilizationThreshold
+	cfg.PodSubnetName = o.PodSubnetName
+	cfg.NodeCIDRStateFile = o.NodeCIDRStateFile
 
 	return nil
 }
@@ -75,6 +123,23 @@ func (o *NodeIPAMControllerOptions) Validate() []error {
 	if len(serviceCIDRList) > 2 {
 		errs = append(errs, fmt.Errorf("--service-cluster-ip-range can not contain more than two entries"))
 	}
+	if o.NodeCIDRMaskSizeIPv4 < 0 || o.NodeCIDRMaskSizeIPv4 > 32 {
+		errs = append(errs, fmt.Errorf("--node-cidr-mask-size-ipv4 must be between 0 and 32, got %d", o.NodeCIDRMaskSizeIPv4))
+	}
+	if o.NodeCIDRMaskSizeIPv6 < 0 || o.NodeCIDRMaskSizeIPv6 > 128 {
+		errs = append(errs, fmt.Errorf("--node-cidr-mask-size-ipv6 must be between 0 and 128, got %d", o.NodeCIDRMaskSizeIPv6))
+	}
+	if o.CIDRUtilizationThreshold < 0 || o.CIDRUtilizationThreshold > 100 {
+		errs = append(errs, fmt.Errorf("--cidr-utilization-threshold must be between 0 and 100, got %d", o.CIDRUtilizationThreshold))
+	}
+	for _, nodePool := range o.NodePoolCIDRMaskSizes {
+		if _, err := labels.Parse(nodePool.NodeSelector); err != nil {
+			errs = append(errs, fmt.Errorf("--node-pool-cidr-mask-size has an invalid label selector %q: %w", nodePool.NodeSelector, err))
+		}
+		if nodePool.NodeCIDRMaskSize <= 0 || nodePool.NodeCIDRMaskSize > 32 {
+			errs = append(errs, fmt.Errorf("--node-pool-cidr-mask-size must be between 1 and 32, got %d for %q", nodePool.NodeCIDRMaskSize, nodePool.NodeSelector))
+		}
+	}
 
 	return errs
 }
@@ -17,23 +17,22 @@ limitations under the License.
 package options
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net"
 	"strings"
 	"time"
 
-	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
-	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
@@ -50,6 +49,10 @@ import (
 
 	cloudcontrollerconfig "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/features"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/eventrecorder"
+	informerutil "sigs.k8s.io/cloud-provider-azure/pkg/util/informer"
 
 	// add the kubernetes feature gates
 	_ "k8s.io/controller-manager/pkg/features/register"
@@ -62,6 +65,9 @@ const (
 	// CloudControllerManagerPort is the default port for the cloud controller manager server.
 	// This value may be overridden by a flag at startup.
 	CloudControllerManagerPort = cloudprovider.CloudControllerManagerPort
+
+	// defaultLoadBalancerRepairPeriod is the default period of the checks of the load balancer repair controller.
+	defaultLoadBalancerRepairPeriod = 5 * time.Minute
 )
 
 // CloudControllerManagerOptions is the main context object for the controller manager.
@@ -81,8 +87,43 @@ type CloudControllerManagerOptions struct {
 	// NodeStatusUpdateFrequency is the frequency at which the controller updates nodes' status
 	NodeStatusUpdateFrequency metav1.Duration
 
+	// DisablePodInformers disables the informers caching the pods
+	DisablePodInformers bool
+
+	// LeaderElectWarmStandby keeps the informers and the Azure caches of the non-leader replicas warm
+	LeaderElectWarmStandby bool
+
+	// AzureFeatureGates are the Azure feature gates taking precedence over the ones of the cloud config
+	AzureFeatureGates map[string]bool
+
+	// LoadBalancerClasses are the load balancer classes of the services reconciled by the service
+	// controller besides the ones without a class
+	LoadBalancerClasses []string
+
+	// LoadBalancerRepairPeriod is the period of the checks of the load balancer repair controller
+	LoadBalancerRepairPeriod metav1.Duration
+
+	// ControllerSyncStaleness is how long the calls of a controller to the cloud provider can keep failing
+	// before its health check fails
+	ControllerSyncStaleness metav1.Duration
+
+	// CloudConfigSource is the source of the Azure resources, the fake source serves them from an
+	// in-memory fake backend instead of Azure
+	CloudConfigSource string
+	// FakeAzureResourcesFile is the file of the resources loaded into the fake backend
+	FakeAzureResourcesFile string
+
+	// ARMFaultInjectionConfigFile is the config of the faults injected into the ARM requests for testing
+	ARMFaultInjectionConfigFile string
+
 	DynamicReloading *DynamicReloadingOptions
 
+	ControllerWorkers *ControllerWorkersOptions
+
+	Tracing *TracingOptions
+
+	Events *EventsOptions
+
 	// Node filtering options
 	EnableNodeFiltering bool
 	NodeLabelSelector   string
@@ -107,7 +148,11 @@ func NewCloudControllerManagerOptions() (*CloudControllerManagerOptions, error)
 		Authentication:            apiserveroptions.NewDelegatingAuthenticationOptions(),
 		Authorization:             apiserveroptions.NewDelegatingAuthorizationOptions(),
 		NodeStatusUpdateFrequency: componentConfig.NodeStatusUpdateFrequency,
+		LoadBalancerRepairPeriod:  metav1.Duration{Duration: defaultLoadBalancerRepairPeriod},
 		DynamicReloading:          defaultDynamicReloadingOptions(),
+		ControllerWorkers:         defaultControllerWorkersOptions(),
+		Tracing:                   &TracingOptions{SamplingRatio: 1},
+		Events:                    defaultEventsOptions(),
 	}
 
 	s.Authentication.RemoteKubeConfigFileOptional = true
@@ -145,6 +190,7 @@ func (o *CloudControllerManagerOptions) Flags(allControllers, disabledByDefaultC
 	o.Generic.AddFlags(&fss, allControllers, disabledByDefaultControllers, names.CCMControllerAliases())
 	o.KubeCloudShared.AddFlags(fss.FlagSet("generic"))
 	o.ServiceController.AddFlags(fss.FlagSet("service controller"))
+	fss.FlagSet("service controller").StringSliceVar(&o.LoadBalancerClasses, "load-balancer-classes", o.LoadBalancerClasses, "The comma-separated load balancer classes of the services to reconcile besides the services without spec.loadBalancerClass. The services of the other classes are left to the other load balancer implementations in the cluster.")
 	o.NodeIPAMController.AddFlags(fss.FlagSet("node ipam controller"))
 
 	o.SecureServing.AddFlags(fss.FlagSet("secure serving"))
@@ -152,11 +198,20 @@ func (o *CloudControllerManagerOptions) Flags(allControllers, disabledByDefaultC
 	o.Authorization.AddFlags(fss.FlagSet("authorization"))
 
 	o.DynamicReloading.AddFlags(fss.FlagSet("dynamic reloading"))
+	o.ControllerWorkers.AddFlags(fss.FlagSet("controller workers"))
+	o.Tracing.AddFlags(fss.FlagSet("tracing"))
+	o.Events.AddFlags(fss.FlagSet("events"))
 
 	fs := fss.FlagSet("misc")
 	fs.StringVar(&o.Master, "master", o.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig).")
 	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to kubeconfig file with authorization and master location information.")
 	fs.DurationVar(&o.NodeStatusUpdateFrequency.Duration, "node-status-update-frequency", o.NodeStatusUpdateFrequency.Duration, "Specifies how often the controller updates nodes' status.")
+	fs.BoolVar(&o.DisablePodInformers, "disable-pod-informers", o.DisablePodInformers, "Disable the informers caching the pods, which take up most of the memory on large clusters. The pods are listed from the API server when they are needed instead.")
+	fs.DurationVar(&o.LoadBalancerRepairPeriod.Duration, "load-balancer-repair-period", o.LoadBalancerRepairPeriod.Duration, "The period of the checks of the load-balancer-repair controller, which re-issues the updates of the load balancers in the Failed provisioning state or with the resources of deleted services, and of the load-balancer-frontend-repair controller, which recreates the load balancer frontends and the public IPs of the services deleted out of band. A load balancer or a service is repaired if it is found unhealthy by two checks in a row. The controllers can be disabled by --controllers=*,-load-balancer-repair,-load-balancer-frontend-repair.")
+	fs.StringVar(&o.ARMFaultInjectionConfigFile, "arm-fault-injection-config", o.ARMFaultInjectionConfigFile, "Path to the YAML config of the latency, the 429s and the 5xx errors injected into the ARM requests by their methods and resource types, to reproduce the throttling and the failures of ARM in testing. Never use it in production.")
+	_ = fs.MarkHidden("arm-fault-injection-config")
+	fs.DurationVar(&o.ControllerSyncStaleness.Duration, "controller-sync-staleness", o.ControllerSyncStaleness.Duration, "How long the calls of the cloud-node, cloud-node-lifecycle, service, route and node-ipam controllers can keep failing without any success before their checks on /healthz/<controller name> fail. The calls to the cloud provider, or the updates of the pod CIDRs for node-ipam, sync the controllers, and the controllers making no calls stay healthy. The last syncs of the controllers are served on /debug/controller-syncs. 0 never fails the checks.")
+	fs.BoolVar(&o.LeaderElectWarmStandby, "leader-elect-warm-standby", o.LeaderElectWarmStandby, "Keep the informers and the Azure caches of the replicas not being the leader warm, so the replica taking over the leadership doesn't start with cold caches. The replicas only read from Azure and the API server until they become the leader. Requires --leader-elect and a static cloud config file.")
 
 	// Node filtering flags
 	nodeFilterFs := fss.FlagSet("node filtering")
@@ -164,7 +219,10 @@ func (o *CloudControllerManagerOptions) Flags(allControllers, disabledByDefaultC
 	nodeFilterFs.StringVar(&o.NodeLabelSelector, "node-label-selector", o.NodeLabelSelector, "Label selector for nodes to be managed by CCM (e.g., 'kubernetes.azure.com/managed=true')")
 	nodeFilterFs.StringVar(&o.NodeExcludeLabels, "node-exclude-labels", o.NodeExcludeLabels, "Label selector for nodes to exclude from CCM management (e.g., 'kubernetes.azure.com/managed=false')")
 
+	fss.FlagSet("generic").StringVar(&o.CloudConfigSource, "cloud-config-source", o.CloudConfigSource, "The source of the Azure resources. If set to \""+cloudcontrollerconfig.CloudConfigSourceFake+"\", the Azure clients are served by an in-memory fake backend instead of Azure, so the cloud controller manager runs without an Azure subscription, e.g. in kind clusters for testing. The --cloud-config is optional then, and the missing subscription, resource group and network resources are set to fake ones.")
+	fss.FlagSet("generic").StringVar(&o.FakeAzureResourcesFile, "fake-azure-resources", o.FakeAzureResourcesFile, "Path to the JSON file of the Azure resources loaded into the fake backend, e.g. the VMs or the scale sets of the nodes. It is an array of ARM resources with their IDs. Requires --cloud-config-source="+cloudcontrollerconfig.CloudConfigSourceFake+".")
 	utilfeature.DefaultMutableFeatureGate.AddFlag(fss.FlagSet("generic"))
+	fss.FlagSet("generic").Var(cliflag.NewMapStringBool(&o.AzureFeatureGates), "azure-feature-gates", "A set of key=value pairs that describe the Azure feature gates, taking precedence over azureFeatureGates of the cloud config. Options are:\n"+strings.Join(features.NewFeatureGate().KnownFeatures(), "\n"))
 
 	return fss
 }
@@ -194,6 +252,29 @@ func (o *CloudControllerManagerOptions) ApplyTo(
 	if err = o.DynamicReloading.ApplyTo(&c.DynamicReloadingConfig); err != nil {
 		return err
 	}
+	if err = o.ControllerWorkers.ApplyTo(&c.ControllerWorkersConfig); err != nil {
+		return err
+	}
+	if err = o.Tracing.ApplyTo(&c.TracingConfig); err != nil {
+		return err
+	}
+	if err = o.Events.ApplyTo(&c.EventsConfig); err != nil {
+		return err
+	}
+
+	c.DisablePodInformers = o.DisablePodInformers
+	c.LeaderElectionWarmStandby = o.LeaderElectWarmStandby
+	c.LoadBalancerClasses = o.LoadBalancerClasses
+	c.LoadBalancerRepairPeriod = o.LoadBalancerRepairPeriod.Duration
+	c.ControllerSyncStaleness = o.ControllerSyncStaleness.Duration
+	c.CloudConfigSource = o.CloudConfigSource
+	c.FakeAzureResourcesFile = o.FakeAzureResourcesFile
+	if err = features.SetOverrides(o.AzureFeatureGates); err != nil {
+		return err
+	}
+	if err = provider.SetARMFaultInjectionConfigFile(o.ARMFaultInjectionConfigFile); err != nil {
+		return err
+	}
 
 	// Apply node filtering configuration
 	c.NodeFilteringConfig.EnableNodeFiltering = o.EnableNodeFiltering
@@ -226,7 +307,7 @@ func (o *CloudControllerManagerOptions) ApplyTo(
 		return err
 	}
 
-	c.EventRecorder = createRecorder(c.Client, userAgent)
+	c.EventRecorder = createRecorder(c.Client, userAgent, c.EventsConfig)
 
 	rootClientBuilder := clientbuilder.SimpleControllerClientBuilder{
 		ClientConfig: c.Kubeconfig,
@@ -245,7 +326,7 @@ func (o *CloudControllerManagerOptions) ApplyTo(
 	if o.EnableNodeFiltering || o.NodeExcludeLabels != "" {
 		c.SharedInformers = CreateFilteredInformerFactory(c.VersionedClient, ResyncPeriod(c)(), o.NodeLabelSelector, o.NodeExcludeLabels)
 	} else {
-		c.SharedInformers = informers.NewSharedInformerFactory(c.VersionedClient, ResyncPeriod(c)())
+		c.SharedInformers = informers.NewSharedInformerFactoryWithOptions(c.VersionedClient, ResyncPeriod(c)(), informers.WithTransform(informerutil.StripUnusedFields))
 	}
 
 	// sync back to component config
@@ -268,6 +349,9 @@ func (o *CloudControllerManagerOptions) Validate(allControllers, disabledByDefau
 	errors = append(errors, o.Authentication.Validate()...)
 	errors = append(errors, o.Authorization.Validate()...)
 	errors = append(errors, o.DynamicReloading.Validate()...)
+	errors = append(errors, o.ControllerWorkers.Validate()...)
+	errors = append(errors, o.Tracing.Validate()...)
+	errors = append(errors, o.Events.Validate()...)
 
 	if len(o.KubeCloudShared.CloudProvider.Name) == 0 {
 		errors = append(errors, fmt.Errorf("--cloud-provider cannot be empty"))
@@ -277,7 +361,50 @@ func (o *CloudControllerManagerOptions) Validate(allControllers, disabledByDefau
 		errors = append(errors, fmt.Errorf("--concurrent-service-syncs is limited to 1 only"))
 	}
 
-	if !o.DynamicReloading.EnableDynamicReloading && o.KubeCloudShared.CloudProvider.CloudConfigFile == "" {
+	if err := features.NewFeatureGate().SetFromMap(o.AzureFeatureGates); err != nil {
+		errors = append(errors, fmt.Errorf("invalid --azure-feature-gates: %w", err))
+	}
+
+	if o.LoadBalancerRepairPeriod.Duration <= 0 {
+		errors = append(errors, fmt.Errorf("--load-balancer-repair-period must be positive, got %s", o.LoadBalancerRepairPeriod.Duration))
+	}
+
+	if o.ControllerSyncStaleness.Duration < 0 {
+		errors = append(errors, fmt.Errorf("--controller-sync-staleness cannot be negative, got %s", o.ControllerSyncStaleness.Duration))
+	}
+
+	for _, class := range o.LoadBalancerClasses {
+		if msgs := validation.IsQualifiedName(class); len(msgs) > 0 {
+			errors = append(errors, fmt.Errorf("invalid --load-balancer-classes %q: %s", class, strings.Join(msgs, "; ")))
+		}
+	}
+
+	if o.LeaderElectWarmStandby {
+		if !o.Generic.LeaderElection.LeaderElect {
+			errors = append(errors, fmt.Errorf("--leader-elect-warm-standby requires --leader-elect"))
+		}
+		if o.DynamicReloading.EnableDynamicReloading {
+			errors = append(errors, fmt.Errorf("--leader-elect-warm-standby cannot be used with --enable-dynamic-reloading"))
+		}
+	}
+
+	switch o.CloudConfigSource {
+	case "":
+		if o.FakeAzureResourcesFile != "" {
+			errors = append(errors, fmt.Errorf("--fake-azure-resources requires --cloud-config-source=%s", cloudcontrollerconfig.CloudConfigSourceFake))
+		}
+	case cloudcontrollerconfig.CloudConfigSourceFake:
+		if o.DynamicReloading.EnableDynamicReloading {
+			errors = append(errors, fmt.Errorf("--cloud-config-source=%s cannot be used with --enable-dynamic-reloading", cloudcontrollerconfig.CloudConfigSourceFake))
+		}
+		if o.LeaderElectWarmStandby {
+			errors = append(errors, fmt.Errorf("--cloud-config-source=%s cannot be used with --leader-elect-warm-standby", cloudcontrollerconfig.CloudConfigSourceFake))
+		}
+	default:
+		errors = append(errors, fmt.Errorf("invalid --cloud-config-source %q, it must be empty or %q", o.CloudConfigSource, cloudcontrollerconfig.CloudConfigSourceFake))
+	}
+
+	if !o.DynamicReloading.EnableDynamicReloading && o.KubeCloudShared.CloudProvider.CloudConfigFile == "" && o.CloudConfigSource != cloudcontrollerconfig.CloudConfigSourceFake {
 		errors = append(errors, fmt.Errorf("--cloud-config cannot be empty when --enable-dynamic-reloading is not set to true"))
 	}
 
@@ -313,12 +440,8 @@ func (o *CloudControllerManagerOptions) Config(
 	return c, nil
 }
 
-func createRecorder(kubeClient clientset.Interface, userAgent string) record.EventRecorder {
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(klog.Infof)
-	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
-	// TODO: remove dependence on the legacyscheme
-	return eventBroadcaster.NewRecorder(runtime.NewScheme(), v1.EventSource{Component: userAgent})
+func createRecorder(kubeClient clientset.Interface, userAgent string, cfg cloudcontrollerconfig.EventsConfig) record.EventRecorder {
+	return eventrecorder.New(context.Background(), kubeClient, userAgent, cfg.Policy())
 }
 
 // CreateFilteredInformerFactory creates a filtered informer factory with node filtering
@@ -397,7 +520,10 @@ func CreateFilteredInformerFactory(client clientset.Interface, resyncPeriod time
 	}
 
 	// Create filtered informer factory
-	return informers.NewFilteredSharedInformerFactory(client, resyncPeriod, metav1.NamespaceAll, func(options *metav1.ListOptions) {
-		options.LabelSelector = selector.String()
-	})
+	return informers.NewSharedInformerFactoryWithOptions(client, resyncPeriod,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}),
+		informers.WithTransform(informerutil.StripUnusedFields),
+	)
 }
@@ -19,6 +19,7 @@ package options
 import (
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/apis/apiserver"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
@@ -136,12 +138,20 @@ func TestDefaultFlags(t *testing.T) {
 		Kubeconfig:                "",
 		Master:                    "",
 		NodeStatusUpdateFrequency: metav1.Duration{Duration: 5 * time.Minute},
+		LoadBalancerRepairPeriod:  metav1.Duration{Duration: 5 * time.Minute},
 		DynamicReloading: &DynamicReloadingOptions{
 			EnableDynamicReloading:     false,
 			CloudConfigSecretName:      "azure-cloud-provider",
 			CloudConfigSecretNamespace: "kube-system",
 			CloudConfigKey:             "",
 		},
+		ControllerWorkers: &ControllerWorkersOptions{},
+		Tracing:           &TracingOptions{SamplingRatio: 1},
+		Events: &EventsOptions{
+			Burst:           5,
+			QPS:             1.0 / 60,
+			SummaryInterval: 5 * time.Minute,
+		},
 	}
 	if !reflect.DeepEqual(expected, s) {
 		t.Errorf("Got different run options than expected.\nDifference detected on:\n%s", diff.ObjectReflectDiff(expected, s))
@@ -179,13 +189,32 @@ func TestAddFlags(t *testing.T) {
 		"--leader-elect-retry-period=5s",
 		"--master=192.168.4.20",
 		"--min-resync-period=100m",
+		"--cidr-utilization-threshold=80",
+		"--node-pool-cidr-mask-size=agentpool in (small,tiny):26",
+		"--node-pool-cidr-mask-size=agentpool=large:23",
 		"--node-status-update-frequency=10m",
+		"--disable-pod-informers",
+		"--leader-elect-warm-standby",
+		"--azure-feature-gates=PrivateLinkService=false",
+		"--load-balancer-classes=example.com/azure,azure",
+		"--load-balancer-repair-period=10m",
+		"--controller-sync-staleness=15m",
+		"--cloud-config-source=fake",
+		"--fake-azure-resources=/resources.json",
+		"--arm-fault-injection-config=/faults.yaml",
 		"--profiling=false",
 		"--route-reconciliation-period=30s",
 		"--secure-port=10001",
 		"--use-service-account-credentials=false",
 		"--enable-dynamic-reloading=true",
 		"--cloud-config-secret-name=test-secret",
+		"--concurrent-node-annotator-syncs=3",
+		"--concurrent-node-status-updates=20",
+		"--tracing-otlp-endpoint=collector:4317",
+		"--tracing-sampling-ratio=0.1",
+		"--event-burst=10",
+		"--event-qps=0.5",
+		"--event-summary-interval=10m",
 	}
 	err := fs.Parse(args)
 	if err != nil {
@@ -245,7 +274,12 @@ func TestAddFlags(t *testing.T) {
 		},
 		NodeIPAMController: &NodeIPAMControllerOptions{
 			NodeIPAMControllerConfiguration: &config.NodeIPAMControllerConfiguration{
-				NodeCIDRMaskSize: consts.DefaultNodeCIDRMaskSize,
+				NodeCIDRMaskSize:         consts.DefaultNodeCIDRMaskSize,
+				CIDRUtilizationThreshold: 80,
+				NodePoolCIDRMaskSizes: []config.NodePoolCIDRMaskSize{
+					{NodeSelector: "agentpool in (small,tiny)", NodeCIDRMaskSize: 26},
+					{NodeSelector: "agentpool=large", NodeCIDRMaskSize: 23},
+				},
 			},
 		},
 		SecureServing: (&apiserveroptions.SecureServingOptions{
@@ -279,15 +313,37 @@ func TestAddFlags(t *testing.T) {
 			WebhookRetryBackoff:          &wait.Backoff{Duration: 500 * time.Millisecond, Factor: 1.5, Jitter: 0.2, Steps: 5},
 			ClientTimeout:                10 * time.Second,
 		},
-		Kubeconfig:                "/kubeconfig",
-		Master:                    "192.168.4.20",
-		NodeStatusUpdateFrequency: metav1.Duration{Duration: 10 * time.Minute},
+		Kubeconfig:                  "/kubeconfig",
+		Master:                      "192.168.4.20",
+		NodeStatusUpdateFrequency:   metav1.Duration{Duration: 10 * time.Minute},
+		DisablePodInformers:         true,
+		LeaderElectWarmStandby:      true,
+		AzureFeatureGates:           map[string]bool{"PrivateLinkService": false},
+		LoadBalancerClasses:         []string{"example.com/azure", "azure"},
+		LoadBalancerRepairPeriod:    metav1.Duration{Duration: 10 * time.Minute},
+		ControllerSyncStaleness:     metav1.Duration{Duration: 15 * time.Minute},
+		CloudConfigSource:           "fake",
+		FakeAzureResourcesFile:      "/resources.json",
+		ARMFaultInjectionConfigFile: "/faults.yaml",
 		DynamicReloading: &DynamicReloadingOptions{
 			EnableDynamicReloading:     true,
 			CloudConfigSecretName:      "test-secret",
 			CloudConfigSecretNamespace: "kube-system",
 			CloudConfigKey:             "cloud-config",
 		},
+		ControllerWorkers: &ControllerWorkersOptions{
+			ConcurrentNodeAnnotatorSyncs: 3,
+			ConcurrentNodeStatusUpdates:  20,
+		},
+		Tracing: &TracingOptions{
+			OTLPEndpoint:  "collector:4317",
+			SamplingRatio: 0.1,
+		},
+		Events: &EventsOptions{
+			Burst:           10,
+			QPS:             0.5,
+			SummaryInterval: 10 * time.Minute,
+		},
 	}
 	if !reflect.DeepEqual(expected, s) {
 		t.Errorf("Got different run options than expected.\nDifference detected on:\n%s", diff.ObjectReflectDiff(expected, s))
@@ -329,6 +385,166 @@ func TestValidate(t *testing.T) {
 				return s
 			},
 		},
+		{
+			desc:     "should return an error when validating options with a negative number of controller workers",
+			expected: "--concurrent-node-provider-id-syncs must not be negative, got -1",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.ControllerWorkers.ConcurrentNodeProviderIDSyncs = -1
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with a negative number of node status updates",
+			expected: "--concurrent-node-status-updates must not be negative, got -1",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.ControllerWorkers.ConcurrentNodeStatusUpdates = -1
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid tracing sampling ratio",
+			expected: "--tracing-sampling-ratio must be between 0 and 1, got 1.5",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.Tracing.SamplingRatio = 1.5
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid event qps",
+			expected: "--event-qps must be positive, got 0",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.Events.QPS = 0
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid ipv6 node cidr mask size",
+			expected: "--node-cidr-mask-size-ipv6 must be between 0 and 128, got 129",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.NodeIPAMController.NodeCIDRMaskSizeIPv6 = 129
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid node pool cidr mask size",
+			expected: "--node-pool-cidr-mask-size must be between 1 and 32, got 33 for \"agentpool=small\"",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.NodeIPAMController.NodePoolCIDRMaskSizes = []config.NodePoolCIDRMaskSize{{NodeSelector: "agentpool=small", NodeCIDRMaskSize: 33}}
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid cidr utilization threshold",
+			expected: "--cidr-utilization-threshold must be between 0 and 100, got 101",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.NodeIPAMController.CIDRUtilizationThreshold = 101
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with the warm standby and the dynamic reloading",
+			expected: "--leader-elect-warm-standby cannot be used with --enable-dynamic-reloading",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.LeaderElectWarmStandby = true
+				s.DynamicReloading.EnableDynamicReloading = true
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an unknown azure feature gate",
+			expected: "invalid --azure-feature-gates: unrecognized feature gate: Unknown",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.AzureFeatureGates = map[string]bool{"Unknown": true}
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with a non-positive load balancer repair period",
+			expected: "--load-balancer-repair-period must be positive, got 0s",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.LoadBalancerRepairPeriod.Duration = 0
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with a negative controller sync staleness",
+			expected: "--controller-sync-staleness cannot be negative, got -1m0s",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.ControllerSyncStaleness.Duration = -time.Minute
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid load balancer class",
+			expected: `invalid --load-balancer-classes "example.com/-azure": ` + strings.Join(validation.IsQualifiedName("example.com/-azure"), "; "),
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.LoadBalancerClasses = []string{"example.com/azure", "example.com/-azure"}
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should not return an error if the cloud config file is empty with the fake cloud config source",
+			expected: "",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.CloudConfigSource = "fake"
+				s.FakeAzureResourcesFile = "resources.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with an invalid cloud config source",
+			expected: `invalid --cloud-config-source "azure", it must be empty or "fake"`,
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.CloudConfigSource = "azure"
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with the fake azure resources but not the fake cloud config source",
+			expected: "--fake-azure-resources requires --cloud-config-source=fake",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.FakeAzureResourcesFile = "resources.json"
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error when validating options with the fake cloud config source and the dynamic reloading",
+			expected: "--cloud-config-source=fake cannot be used with --enable-dynamic-reloading",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.CloudConfigSource = "fake"
+				s.DynamicReloading.EnableDynamicReloading = true
+				return s
+			},
+		},
 		{
 			desc:     "should return an error if the cloud config file is empty and the dynamic reloading is not enabled",
 			expected: "--cloud-config cannot be empty when --enable-dynamic-reloading is not set to true",
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	app "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
+)
+
+// TracingOptions holds the options of the OpenTelemetry tracing of the reconciliations and the ARM client calls
+type TracingOptions struct {
+	OTLPEndpoint  string
+	OTLPInsecure  bool
+	SamplingRatio float64
+}
+
+// AddFlags adds flags related to the tracing for controller manager to the specified FlagSet
+func (o *TracingOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.OTLPEndpoint, "tracing-otlp-endpoint", o.OTLPEndpoint, "The host:port of the OTLP gRPC collector the spans of the reconciliations and the ARM client calls are exported to. Tracing is disabled if it is empty.")
+	fs.BoolVar(&o.OTLPInsecure, "tracing-otlp-insecure", o.OTLPInsecure, "Connect to the OTLP collector without TLS.")
+	fs.Float64Var(&o.SamplingRatio, "tracing-sampling-ratio", o.SamplingRatio, "The ratio of the traces sampled, between 0 and 1.")
+}
+
+// ApplyTo fills up the tracing config with options
+func (o *TracingOptions) ApplyTo(cfg *app.TracingConfig) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.OTLPEndpoint = o.OTLPEndpoint
+	cfg.OTLPInsecure = o.OTLPInsecure
+	cfg.SamplingRatio = o.SamplingRatio
+
+	return nil
+}
+
+// Validate checks validation of TracingOptions
+func (o *TracingOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.SamplingRatio < 0 || o.SamplingRatio > 1 {
+		errs = append(errs, fmt.Errorf("--tracing-sampling-ratio must be between 0 and 1, got %v", o.SamplingRatio))
+	}
+	return errs
+}
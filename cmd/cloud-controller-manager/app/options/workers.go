@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	app "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
+)
+
+// ControllerWorkersOptions holds the worker pool sizes of the node controllers of the cloud provider
+type ControllerWorkersOptions struct {
+	ConcurrentNodeAnnotatorSyncs  int32
+	ConcurrentNodeProviderIDSyncs int32
+	ConcurrentNodeStatusUpdates   int32
+}
+
+func defaultControllerWorkersOptions() *ControllerWorkersOptions {
+	return &ControllerWorkersOptions{}
+}
+
+// AddFlags adds flags related to the controller workers for controller manager to the specified FlagSet
+func (o *ControllerWorkersOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentNodeAnnotatorSyncs, "concurrent-node-annotator-syncs", o.ConcurrentNodeAnnotatorSyncs, "The number of workers of the node-annotator controller syncing the hardware labels of the nodes. Default is 0, which uses --concurrent-node-syncs.")
+	fs.Int32Var(&o.ConcurrentNodeProviderIDSyncs, "concurrent-node-provider-id-syncs", o.ConcurrentNodeProviderIDSyncs, "The number of workers of the node-provider-id controller backfilling the provider IDs of the nodes. Default is 0, which uses --concurrent-node-syncs.")
+	fs.Int32Var(&o.ConcurrentNodeStatusUpdates, "concurrent-node-status-updates", o.ConcurrentNodeStatusUpdates, fmt.Sprintf("The number of nodes the cloud-node controller initializes and updates the status of in parallel. It replaces --concurrent-node-syncs for the cloud-node controller, while --concurrent-node-syncs keeps setting the default workers of the node-annotator and node-provider-id controllers. Default is 0, which uses the larger of %d and --concurrent-node-syncs.", app.DefaultConcurrentNodeStatusUpdates))
+}
+
+// ApplyTo fills up the controller workers config with options
+func (o *ControllerWorkersOptions) ApplyTo(cfg *app.ControllerWorkersConfig) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentNodeAnnotatorSyncs = o.ConcurrentNodeAnnotatorSyncs
+	cfg.ConcurrentNodeProviderIDSyncs = o.ConcurrentNodeProviderIDSyncs
+	cfg.ConcurrentNodeStatusUpdates = o.ConcurrentNodeStatusUpdates
+
+	return nil
+}
+
+// Validate checks validation of ControllerWorkersOptions
+func (o *ControllerWorkersOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentNodeAnnotatorSyncs < 0 {
+		errs = append(errs, fmt.Errorf("--concurrent-node-annotator-syncs must not be negative, got %d", o.ConcurrentNodeAnnotatorSyncs))
+	}
+	if o.ConcurrentNodeProviderIDSyncs < 0 {
+		errs = append(errs, fmt.Errorf("--concurrent-node-provider-id-syncs must not be negative, got %d", o.ConcurrentNodeProviderIDSyncs))
+	}
+	if o.ConcurrentNodeStatusUpdates < 0 {
+		errs = append(errs, fmt.Errorf("--concurrent-node-status-updates must not be negative, got %d", o.ConcurrentNodeStatusUpdates))
+	}
+	return errs
+}
@@ -78,6 +78,13 @@ type Config struct {
 	// Specifies if node information is retrieved via IMDS or ARM.
 	UseInstanceMetadata bool
 
+	// NodeAddressSource, NodeAddressOrder, NodeAddressIPFamilies and ExcludeNodePublicIPs are the
+	// options of the node addresses when the node information is retrieved via IMDS.
+	NodeAddressSource     string
+	NodeAddressOrder      string
+	NodeAddressIPFamilies string
+	ExcludeNodePublicIPs  bool
+
 	// WindowsService should be set to true if cloud-node-manager is running as a service on Windows.
 	// Its corresponding flag only gets registered in Windows builds
 	WindowsService bool
@@ -86,4 +93,13 @@ type Config struct {
 	// If true, the node will apply beta topology labels.
 	// DEPRECATED: This flag will be removed in a future release.
 	EnableDeprecatedBetaTopologyLabels bool
+
+	// EnableScheduledEvents indicates whether the node should be tainted when IMDS reports a scheduled preemption of the VM,
+	// and get the AzureMaintenanceScheduled condition when IMDS reports a scheduled maintenance of the VM.
+	EnableScheduledEvents bool
+	// ScheduledEventsPollInterval is the interval at which the manager polls the scheduled events from IMDS.
+	ScheduledEventsPollInterval metav1.Duration
+	// DrainOnScheduledPreemption indicates whether the node should be tainted with NoExecute instead of NoSchedule
+	// on a scheduled preemption.
+	DrainOnScheduledPreemption bool
 }
@@ -130,13 +130,35 @@ func startControllers(ctx context.Context, c *cloudnodeconfig.Config, healthzHan
 		c.SharedInformers.Core().V1().Nodes(),
 		// cloud node controller uses existing cluster role from node-controller
 		c.ClientBuilder.ClientOrDie("node-controller"),
-		nodeprovider.NewNodeProvider(ctx, c.UseInstanceMetadata, c.CloudConfigFilePath),
+		nodeprovider.NewNodeProvider(ctx, c.UseInstanceMetadata, c.CloudConfigFilePath, nodeprovider.NodeAddressOptions{
+			Source:           c.NodeAddressSource,
+			Order:            c.NodeAddressOrder,
+			IPFamilies:       c.NodeAddressIPFamilies,
+			ExcludePublicIPs: c.ExcludeNodePublicIPs,
+		}),
 		c.NodeStatusUpdateFrequency.Duration,
 		c.WaitForRoutes,
 		c.EnableDeprecatedBetaTopologyLabels)
 
 	go nodeController.Run(ctx)
 
+	if c.EnableScheduledEvents {
+		eventsProvider, err := nodeprovider.NewIMDSScheduledEventsProvider()
+		if err != nil {
+			return fmt.Errorf("failed to create the scheduled events provider: %w", err)
+		}
+		scheduledEventsController := nodemanager.NewScheduledEventsController(
+			c.NodeName,
+			c.SharedInformers.Core().V1().Nodes(),
+			c.ClientBuilder.ClientOrDie("node-controller"),
+			c.EventRecorder,
+			eventsProvider,
+			c.ScheduledEventsPollInterval.Duration,
+			c.DrainOnScheduledPreemption)
+
+		go scheduledEventsController.Run(ctx)
+	}
+
 	check := controllerhealthz.NamedPingChecker(c.NodeName)
 	healthzHandler.AddHealthChecker(check)
 
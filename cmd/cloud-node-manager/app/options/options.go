@@ -56,6 +56,8 @@ const (
 	CloudControllerManagerPort = 10263
 	// defaultNodeStatusUpdateFrequencyInMinute is the default frequency at which the manager updates nodes' status.
 	defaultNodeStatusUpdateFrequencyInMinute = 5
+	// defaultScheduledEventsPollIntervalInSeconds is the default interval at which the manager polls the scheduled events.
+	defaultScheduledEventsPollIntervalInSeconds = 5
 )
 
 // CloudNodeManagerOptions is the main context object for the controller manager.
@@ -83,6 +85,13 @@ type CloudNodeManagerOptions struct {
 
 	UseInstanceMetadata bool
 
+	// NodeAddressSource, NodeAddressOrder, NodeAddressIPFamilies and ExcludeNodePublicIPs are the
+	// options of the node addresses when UseInstanceMetadata is true.
+	NodeAddressSource     string
+	NodeAddressOrder      string
+	NodeAddressIPFamilies string
+	ExcludeNodePublicIPs  bool
+
 	// WindowsService should be set to true if cloud-node-manager is running as a service on Windows.
 	// Its corresponding flag only gets registered in Windows builds
 	WindowsService bool
@@ -91,6 +100,15 @@ type CloudNodeManagerOptions struct {
 	// If true, the node will apply beta topology labels.
 	// DEPRECATED: This flag will be removed in a future release.
 	EnableDeprecatedBetaTopologyLabels bool
+
+	// EnableScheduledEvents indicates whether the node should be tainted when IMDS reports a scheduled preemption of the VM,
+	// and get the AzureMaintenanceScheduled condition when IMDS reports a scheduled maintenance of the VM.
+	EnableScheduledEvents bool
+	// ScheduledEventsPollInterval is the interval at which the manager polls the scheduled events from IMDS.
+	ScheduledEventsPollInterval metav1.Duration
+	// DrainOnScheduledPreemption indicates whether the node should be tainted with NoExecute instead of NoSchedule
+	// on a scheduled preemption, which evicts the pods not tolerating the taint.
+	DrainOnScheduledPreemption bool
 }
 
 // NewCloudNodeManagerOptions creates a new CloudNodeManagerOptions with a default config.
@@ -102,6 +120,9 @@ func NewCloudNodeManagerOptions() (*CloudNodeManagerOptions, error) {
 		NodeStatusUpdateFrequency: metav1.Duration{
 			Duration: defaultNodeStatusUpdateFrequencyInMinute * time.Minute,
 		},
+		ScheduledEventsPollInterval: metav1.Duration{
+			Duration: defaultScheduledEventsPollIntervalInSeconds * time.Second,
+		},
 	}
 
 	s.Authentication.RemoteKubeConfigFileOptional = true
@@ -135,8 +156,15 @@ func (o *CloudNodeManagerOptions) Flags() cliflag.NamedFlagSets {
 	fs.Int32Var(&o.ClientConnection.Burst, "kube-api-burst", 30, "Burst to use while talking with kubernetes apiserver.")
 	fs.BoolVar(&o.WaitForRoutes, "wait-routes", false, "Whether the nodes should wait for routes created on Azure route table. It should be set to true when using kubenet plugin.")
 	fs.BoolVar(&o.UseInstanceMetadata, "use-instance-metadata", true, "Should use Instance Metadata Service for fetching node information; if false will use ARM instead.")
+	fs.StringVar(&o.NodeAddressSource, "node-address-source", o.NodeAddressSource, "Where the node addresses are read from when using Instance Metadata Service: imds (default) or arm. Without --use-instance-metadata, the nodeAddressSource of the cloud config is used.")
+	fs.StringVar(&o.NodeAddressOrder, "node-address-order", o.NodeAddressOrder, "The order of the InternalIPs and ExternalIPs when using Instance Metadata Service: internalIPFirst (default) or externalIPFirst. Without --use-instance-metadata, the nodeAddressOrder of the cloud config is used.")
+	fs.StringVar(&o.NodeAddressIPFamilies, "node-address-ip-families", o.NodeAddressIPFamilies, "Comma-separated list of the IP families of the node addresses in the order of preference when using Instance Metadata Service, e.g. IPv4,IPv6. Without --use-instance-metadata, the nodeAddressIPFamilies of the cloud config is used.")
+	fs.BoolVar(&o.ExcludeNodePublicIPs, "exclude-node-public-ips", o.ExcludeNodePublicIPs, "If true, no ExternalIP is reported in the node addresses when using Instance Metadata Service. Without --use-instance-metadata, the excludeNodePublicIPs of the cloud config is used.")
 	fs.StringVar(&o.CloudConfigFilePath, "cloud-config", o.CloudConfigFilePath, "The path to the cloud config file to be used when using ARM to fetch node information.")
 	fs.BoolVar(&o.EnableDeprecatedBetaTopologyLabels, "enable-deprecated-beta-topology-labels", o.EnableDeprecatedBetaTopologyLabels, "DEPRECATED: This flag will be removed in a future release. If true, the node will apply beta topology labels.")
+	fs.BoolVar(&o.EnableScheduledEvents, "enable-scheduled-events", o.EnableScheduledEvents, "If true, the node will be tainted when the Instance Metadata Service reports a scheduled preemption of the VM, and get the AzureMaintenanceScheduled condition when it reports a scheduled Freeze, Reboot or Redeploy of the VM.")
+	fs.DurationVar(&o.ScheduledEventsPollInterval.Duration, "scheduled-events-poll-interval", o.ScheduledEventsPollInterval.Duration, "Specifies how often the scheduled events are polled from the Instance Metadata Service.")
+	fs.BoolVar(&o.DrainOnScheduledPreemption, "drain-on-scheduled-preemption", o.DrainOnScheduledPreemption, "If true, the node will be tainted with NoExecute instead of NoSchedule on a scheduled preemption, which evicts the pods not tolerating the taint.")
 	return fss
 }
 
@@ -195,6 +223,10 @@ func (o *CloudNodeManagerOptions) ApplyTo(c *cloudnodeconfig.Config, userAgent s
 	}))
 	c.NodeStatusUpdateFrequency = o.NodeStatusUpdateFrequency
 	c.UseInstanceMetadata = o.UseInstanceMetadata
+	c.NodeAddressSource = o.NodeAddressSource
+	c.NodeAddressOrder = o.NodeAddressOrder
+	c.NodeAddressIPFamilies = o.NodeAddressIPFamilies
+	c.ExcludeNodePublicIPs = o.ExcludeNodePublicIPs
 	c.CloudConfigFilePath = o.CloudConfigFilePath
 
 	c.WindowsService = o.WindowsService
@@ -202,6 +234,10 @@ func (o *CloudNodeManagerOptions) ApplyTo(c *cloudnodeconfig.Config, userAgent s
 	// Allow users to choose to apply beta topology labels until they are removed by all cloud providers.
 	c.EnableDeprecatedBetaTopologyLabels = o.EnableDeprecatedBetaTopologyLabels
 
+	c.EnableScheduledEvents = o.EnableScheduledEvents
+	c.ScheduledEventsPollInterval = o.ScheduledEventsPollInterval
+	c.DrainOnScheduledPreemption = o.DrainOnScheduledPreemption
+
 	return nil
 }
 
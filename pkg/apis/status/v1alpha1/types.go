@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the AzureCloudProviderStatus API, through which the cloud provider reports
+// the resources it manages and its health.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the API group of the status.
+	GroupName = "cloudprovider.azure.x-k8s.io"
+	// Kind is the kind of the status.
+	Kind = "AzureCloudProviderStatus"
+	// DefaultName is the name of the status object the cloud provider keeps updated, the status is
+	// cluster-scoped and there is one per cluster.
+	DefaultName = "azure-cloud-provider"
+)
+
+const (
+	// ConditionHealthy is true if all the other conditions are healthy.
+	ConditionHealthy = "Healthy"
+	// ConditionLoadBalancersReconciled is false if the load balancers of some services failed to reconcile.
+	ConditionLoadBalancersReconciled = "LoadBalancersReconciled"
+	// ConditionRouteTablesReady is false if some route tables can't be read or are not provisioned.
+	ConditionRouteTablesReady = "RouteTablesReady"
+	// ConditionCredentialsValid is false if some credentials fail to get tokens or their certificates
+	// are about to expire.
+	ConditionCredentialsValid = "CredentialsValid"
+	// ConditionThrottled is true if the requests to some resources are backed off after being throttled by ARM.
+	ConditionThrottled = "Throttled"
+	// ConditionControllersSynced is false if some controllers have been failing to sync for longer than their
+	// staleness window.
+	ConditionControllersSynced = "ControllersSynced"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the status.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+	// Resource is the resource of the status.
+	Resource = SchemeGroupVersion.WithResource("azurecloudproviderstatuses")
+)
+
+// AzureCloudProviderStatus is the view of the cloud provider of the resources it manages and its health.
+type AzureCloudProviderStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status AzureCloudProviderStatusStatus `json:"status,omitempty"`
+}
+
+// AzureCloudProviderStatusStatus is the status reported by the cloud provider.
+type AzureCloudProviderStatusStatus struct {
+	// LastUpdateTime is when the status was last reported.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Conditions are the conditions of the cloud provider, see the Condition constants.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LoadBalancers are the load balancers in the load balancer cache, as last read by the reconciliations.
+	LoadBalancers []LoadBalancerStatus `json:"loadBalancers,omitempty"`
+	// RouteTables are the route tables the routes of the nodes are managed in.
+	RouteTables []RouteTableStatus `json:"routeTables,omitempty"`
+	// Credentials are the credentials of the ARM clients.
+	Credentials []CredentialStatus `json:"credentials,omitempty"`
+	// ThrottledResources are the resources whose requests are backed off after being throttled by ARM.
+	ThrottledResources []ThrottledResource `json:"throttledResources,omitempty"`
+	// FailingServices are the services whose last load balancer reconciliation failed.
+	FailingServices []FailingService `json:"failingServices,omitempty"`
+	// Controllers are the syncs of the controllers running in the cloud controller manager.
+	Controllers []ControllerStatus `json:"controllers,omitempty"`
+}
+
+// LoadBalancerStatus is the status of a load balancer.
+type LoadBalancerStatus struct {
+	Name              string           `json:"name"`
+	SKU               string           `json:"sku,omitempty"`
+	ProvisioningState string           `json:"provisioningState,omitempty"`
+	Frontends         []FrontendStatus `json:"frontends,omitempty"`
+	// LoadBalancingRules is the number of the load balancing rules.
+	LoadBalancingRules int `json:"loadBalancingRules"`
+}
+
+// FrontendStatus is the status of a frontend IP configuration of a load balancer.
+type FrontendStatus struct {
+	Name string `json:"name"`
+	// PrivateIPAddress is the IP address of an internal frontend.
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+	// PublicIPAddressID is the resource ID of the public IP address of an external frontend.
+	PublicIPAddressID string `json:"publicIPAddressID,omitempty"`
+}
+
+// RouteTableStatus is the status of a route table.
+type RouteTableStatus struct {
+	Name              string `json:"name"`
+	ProvisioningState string `json:"provisioningState,omitempty"`
+	// Routes is the number of the routes in the route table.
+	Routes int `json:"routes"`
+	// Error is the error reading the route table.
+	Error string `json:"error,omitempty"`
+}
+
+// CredentialStatus is the status of a credential.
+type CredentialStatus struct {
+	// Name is compute or network.
+	Name string `json:"name"`
+	// TokenExpirationTime is when the current ARM token of the credential expires.
+	TokenExpirationTime *metav1.Time `json:"tokenExpirationTime,omitempty"`
+	// CertificateExpirationTime is when the client certificate of the credential expires.
+	CertificateExpirationTime *metav1.Time `json:"certificateExpirationTime,omitempty"`
+	// Error is the error getting the token.
+	Error string `json:"error,omitempty"`
+}
+
+// ThrottledResource is a resource whose requests are backed off after being throttled by ARM.
+type ThrottledResource struct {
+	// Operation is Reads, Writes or Deletes.
+	Operation  string      `json:"operation"`
+	ResourceID string      `json:"resourceID"`
+	Until      metav1.Time `json:"until"`
+}
+
+// FailingService is a service whose last load balancer reconciliation failed.
+type FailingService struct {
+	// Name is the namespace/name of the service.
+	Name string `json:"name"`
+	// FailureCategory is the category of the last failure, e.g. throttled or authorization.
+	FailureCategory string `json:"failureCategory"`
+	// Parked is true if the reconciliation is parked by the circuit breaker after consecutive failures.
+	Parked bool `json:"parked,omitempty"`
+}
+
+// ControllerStatus is the sync status of a controller, by the results of its calls to the cloud provider.
+type ControllerStatus struct {
+	Name string `json:"name"`
+	// LastSyncTime is when the last call of the controller succeeded.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// FailingSince is when the calls of the controller started failing, unset if the last call succeeded.
+	FailingSince *metav1.Time `json:"failingSince,omitempty"`
+	// Error is the error of the last failed call.
+	Error string `json:"error,omitempty"`
+	// Stale is true if the calls have been failing for longer than the staleness window.
+	Stale bool `json:"stale,omitempty"`
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package azclient
 
 import (
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -33,9 +34,22 @@ type ARMClientConfig struct {
 	Cloud string `json:"cloud,omitempty" yaml:"cloud,omitempty"`
 	// The user agent for Azure customer usage attribution
 	UserAgent string `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+	// UserAgentSuffix is appended to the User-Agent of all the requests, e.g. to identify the cluster issuing the calls.
+	UserAgentSuffix string `json:"userAgentSuffix,omitempty" yaml:"userAgentSuffix,omitempty"`
+	// EnableTelemetry keeps the telemetry of the Azure SDK, i.e. the SDK module, its version and the platform, in the
+	// User-Agent when userAgent is set. The userAgent is then the application ID of the telemetry, which the SDK truncates
+	// to 24 characters. The User-Agent is the userAgent alone by default.
+	EnableTelemetry bool `json:"enableTelemetry,omitempty" yaml:"enableTelemetry,omitempty"`
 	// ResourceManagerEndpoint is the cloud's resource manager endpoint. If set, cloud provider queries this endpoint
 	// in order to generate an autorest.Environment instance instead of using one of the pre-defined Environments.
 	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty" yaml:"resourceManagerEndpoint,omitempty"`
+	// EnvironmentFilePath is the path of the environment metadata JSON file of a custom cloud, e.g. an air-gapped cloud
+	// without the metadata endpoint. It overrides the endpoints of the cloud and the ones queried from resourceManagerEndpoint.
+	EnvironmentFilePath string `json:"environmentFilePath,omitempty" yaml:"environmentFilePath,omitempty"`
+	// ActiveDirectoryEndpoint overrides the AAD authority host of the cloud used by all clients.
+	ActiveDirectoryEndpoint string `json:"activeDirectoryEndpoint,omitempty" yaml:"activeDirectoryEndpoint,omitempty"`
+	// ResourceManagerAudience overrides the audience of the tokens of the resource manager used by all clients.
+	ResourceManagerAudience string `json:"resourceManagerAudience,omitempty" yaml:"resourceManagerAudience,omitempty"`
 	// The AAD Tenant ID for the Subscription that the cluster is deployed in
 	TenantID string `json:"tenantId,omitempty" yaml:"tenantId,omitempty"`
 	// The AAD Tenant ID for the Subscription that the network resources are deployed in.
@@ -52,6 +66,23 @@ type ARMClientConfig struct {
 	DisableAzureStackCloud bool `json:"disableAzureStackCloud,omitempty" yaml:"disableAzureStackCloud,omitempty"`
 	// If true, HTTP responses' retry-after header will be overridden with the configured minimum retry-after value if lower than the configured minimum
 	EnableMinimumRetryAfter bool `json:"enableMinimumRetryAfter,omitempty" yaml:"enableMinimumRetryAfter,omitempty"`
+	// HTTPSProxy is the url of the proxy of the https requests of the ARM and AAD clients. The link-local addresses, e.g. IMDS,
+	// are never accessed through it. The proxy of the environment is used if it is empty.
+	HTTPSProxy string `json:"httpsProxy,omitempty" yaml:"httpsProxy,omitempty"`
+	// NoProxy is the comma separated list of the hosts, IPs and CIDRs which are not accessed through HTTPSProxy.
+	NoProxy string `json:"noProxy,omitempty" yaml:"noProxy,omitempty"`
+	// CACertFile is the path of the PEM CA bundle trusted by the ARM, AAD and IMDS clients in addition to the system roots,
+	// e.g. the CA of a TLS-inspecting egress proxy.
+	CACertFile string `json:"caCertFile,omitempty" yaml:"caCertFile,omitempty"`
+}
+
+// GetTransport returns the transport of the outbound calls configured with the proxy and the CA bundle of the config,
+// or the default transport if none of them is set.
+func GetTransport(armConfig *ARMClientConfig) (*http.Transport, error) {
+	if armConfig == nil || (armConfig.HTTPSProxy == "" && armConfig.NoProxy == "" && armConfig.CACertFile == "") {
+		return utils.DefaultTransport, nil
+	}
+	return utils.NewTransport(armConfig.HTTPSProxy, armConfig.NoProxy, armConfig.CACertFile)
 }
 
 func (config *ARMClientConfig) GetTenantID() string {
@@ -69,9 +100,16 @@ func GetAzCoreClientOption(armConfig *ARMClientConfig) (*policy.ClientOptions, *
 	clientConfig := utils.GetDefaultAzCoreClientOption()
 	if armConfig != nil {
 		//update user agent header
-		if userAgent := strings.TrimSpace(armConfig.UserAgent); userAgent != "" {
-			clientConfig.Telemetry.Disabled = true
-			clientConfig.PerCallPolicies = append(clientConfig.PerCallPolicies, useragent.NewCustomUserAgentPolicy(userAgent))
+		userAgent, userAgentSuffix := strings.TrimSpace(armConfig.UserAgent), strings.TrimSpace(armConfig.UserAgentSuffix)
+		if userAgent != "" {
+			if armConfig.EnableTelemetry {
+				clientConfig.Telemetry.ApplicationID = userAgent
+			} else {
+				clientConfig.Telemetry.Disabled = true
+			}
+		}
+		if userAgent != "" || userAgentSuffix != "" {
+			clientConfig.PerCallPolicies = append(clientConfig.PerCallPolicies, useragent.NewCustomUserAgentPolicyWithSuffix(userAgent, userAgentSuffix))
 		}
 		//set cloud
 		clientConfig.Cloud, env, err = GetAzureCloudConfigAndEnvConfig(armConfig)
@@ -88,6 +126,16 @@ func GetAzCoreClientOption(armConfig *ARMClientConfig) (*policy.ClientOptions, *
 			// Add the minimum retry-after policy to enforce a minimum retry-after value configured in clientConfig.Retry.RetryDelay (default 5s)
 			clientConfig.PerRetryPolicies = append(clientConfig.PerRetryPolicies, retryaftermin.NewRetryAfterMinPolicy(clientConfig.Retry.RetryDelay))
 		}
+		transport, err := GetTransport(armConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		if transport != utils.DefaultTransport {
+			clientConfig.Transport = &http.Client{
+				Transport: transport,
+				Timeout:   time.Minute,
+			}
+		}
 	}
 	return &clientConfig, env, nil
 }
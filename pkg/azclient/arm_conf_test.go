@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azclient_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+)
+
+var _ = ginkgo.Describe("GetAzCoreClientOption", func() {
+	ginkgo.It("should keep the telemetry without userAgent", func() {
+		options, _, err := azclient.GetAzCoreClientOption(&azclient.ARMClientConfig{UserAgentSuffix: "cluster/test"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(options.Telemetry.Disabled).To(gomega.BeFalse())
+	})
+	ginkgo.It("should disable the telemetry with userAgent by default", func() {
+		options, _, err := azclient.GetAzCoreClientOption(&azclient.ARMClientConfig{UserAgent: "test"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(options.Telemetry.Disabled).To(gomega.BeTrue())
+	})
+	ginkgo.It("should send userAgent as the application ID of the telemetry if it is enabled", func() {
+		options, _, err := azclient.GetAzCoreClientOption(&azclient.ARMClientConfig{UserAgent: "test", EnableTelemetry: true})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(options.Telemetry.Disabled).To(gomega.BeFalse())
+		gomega.Expect(options.Telemetry.ApplicationID).To(gomega.Equal("test"))
+	})
+})
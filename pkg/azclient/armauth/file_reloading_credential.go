@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armauth
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"k8s.io/klog/v2"
+)
+
+// FileReloadingCredential rebuilds the credential from a file when the content of the file changes,
+// e.g. a client certificate rotated by the Key Vault secrets store CSI driver. The file is read at most
+// once per reload interval, and the previous credential is kept if the file cannot be loaded.
+type FileReloadingCredential struct {
+	path           string
+	reloadInterval time.Duration
+	readFile       func(name string) ([]byte, error)
+	newCredential  func(data []byte) (azcore.TokenCredential, error)
+	now            func() time.Time
+
+	mtx        sync.RWMutex
+	data       []byte
+	credential azcore.TokenCredential
+	checkedAt  time.Time
+}
+
+// NewFileReloadingCredential loads the credential from the file. It returns an error if the
+// initial credential cannot be loaded.
+func NewFileReloadingCredential(
+	path string,
+	reloadInterval time.Duration,
+	readFile func(name string) ([]byte, error),
+	newCredential func(data []byte) (azcore.TokenCredential, error),
+) (*FileReloadingCredential, error) {
+	c := &FileReloadingCredential{
+		path:           path,
+		reloadInterval: reloadInterval,
+		readFile:       readFile,
+		newCredential:  newCredential,
+		now:            time.Now,
+	}
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := newCredential(data)
+	if err != nil {
+		return nil, err
+	}
+	c.data, c.credential, c.checkedAt = data, credential, c.now()
+	return c, nil
+}
+
+func (c *FileReloadingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return c.getCredential().GetToken(ctx, options)
+}
+
+// getCredential returns the credential, reloading it first if the file changed since the last check.
+func (c *FileReloadingCredential) getCredential() azcore.TokenCredential {
+	c.mtx.RLock()
+	if c.now().Sub(c.checkedAt) < c.reloadInterval {
+		defer c.mtx.RUnlock()
+		return c.credential
+	}
+	c.mtx.RUnlock()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.now().Sub(c.checkedAt) < c.reloadInterval {
+		return c.credential
+	}
+	c.checkedAt = c.now()
+
+	data, err := c.readFile(c.path)
+	if err != nil {
+		klog.Errorf("FileReloadingCredential: failed to read %s, keep using the previous credential: %v", c.path, err)
+		return c.credential
+	}
+	if bytes.Equal(data, c.data) {
+		return c.credential
+	}
+	credential, err := c.newCredential(data)
+	if err != nil {
+		klog.Errorf("FileReloadingCredential: failed to load the credential from %s, keep using the previous credential: %v", c.path, err)
+		return c.credential
+	}
+	klog.Infof("FileReloadingCredential: reloaded the credential from %s", c.path)
+	c.data, c.credential = data, credential
+	return c.credential
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFileReloadingCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		testErr     = errors.New("test error")
+		fileData    = "cert-1"
+		fileErr     error
+		credentials = map[string]*MockTokenCredential{
+			"cert-1": NewMockTokenCredential(ctrl),
+			"cert-2": NewMockTokenCredential(ctrl),
+		}
+		now = time.Now()
+	)
+	readFile := func(name string) ([]byte, error) {
+		assert.Equal(t, "cert.pem", name)
+		return []byte(fileData), fileErr
+	}
+	newCredential := func(data []byte) (azcore.TokenCredential, error) {
+		credential, ok := credentials[string(data)]
+		if !ok {
+			return nil, testErr
+		}
+		return credential, nil
+	}
+
+	_, err := NewFileReloadingCredential("cert.pem", time.Minute, func(_ string) ([]byte, error) { return nil, testErr }, newCredential)
+	assert.ErrorIs(t, err, testErr)
+
+	c, err := NewFileReloadingCredential("cert.pem", time.Minute, readFile, newCredential)
+	assert.NoError(t, err)
+	c.now, c.checkedAt = func() time.Time { return now }, now
+
+	getToken := func(expected *MockTokenCredential) {
+		t.Helper()
+		expected.EXPECT().GetToken(gomock.Any(), gomock.Any()).Return(azcore.AccessToken{Token: "token"}, nil)
+		token, err := c.GetToken(context.Background(), policy.TokenRequestOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token.Token)
+	}
+
+	// the file is not checked within the reload interval
+	fileData = "cert-2"
+	getToken(credentials["cert-1"])
+
+	// the credential is reloaded after the file changes
+	now = now.Add(time.Minute)
+	getToken(credentials["cert-2"])
+
+	// the previous credential is kept if the file cannot be read or loaded
+	now = now.Add(time.Minute)
+	fileErr = testErr
+	getToken(credentials["cert-2"])
+	now = now.Add(time.Minute)
+	fileData, fileErr = "invalid", nil
+	getToken(credentials["cert-2"])
+}
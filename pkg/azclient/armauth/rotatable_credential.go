@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armauth
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// RotatableCredential delegates to a credential that can be replaced without rebuilding the clients
+// using it, e.g. after the client secret is rotated. The requests in flight are not interrupted, and
+// the tokens cached by the clients are refreshed from the new credential.
+type RotatableCredential struct {
+	mtx        sync.RWMutex
+	credential azcore.TokenCredential
+}
+
+func NewRotatableCredential(credential azcore.TokenCredential) *RotatableCredential {
+	return &RotatableCredential{
+		credential: credential,
+	}
+}
+
+func (c *RotatableCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.mtx.RLock()
+	credential := c.credential
+	c.mtx.RUnlock()
+	return credential.GetToken(ctx, options)
+}
+
+// Rotate replaces the credential.
+func (c *RotatableCredential) Rotate(credential azcore.TokenCredential) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.credential = credential
+}
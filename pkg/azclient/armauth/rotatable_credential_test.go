@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRotatableCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldCredential := NewMockTokenCredential(ctrl)
+	oldCredential.EXPECT().GetToken(gomock.Any(), gomock.Any()).Return(azcore.AccessToken{Token: "old"}, nil)
+	newCredential := NewMockTokenCredential(ctrl)
+	newCredential.EXPECT().GetToken(gomock.Any(), gomock.Any()).Return(azcore.AccessToken{Token: "new"}, nil)
+
+	c := NewRotatableCredential(oldCredential)
+	token, err := c.GetToken(context.Background(), policy.TokenRequestOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "old", token.Token)
+
+	c.Rotate(newCredential)
+	token, err = c.GetToken(context.Background(), policy.TokenRequestOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "new", token.Token)
+}
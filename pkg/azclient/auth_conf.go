@@ -33,12 +33,20 @@ type AzureAuthConfig struct {
 	AADClientCertPath string `json:"aadClientCertPath,omitempty" yaml:"aadClientCertPath,omitempty"`
 	// The password of the client certificate for an AAD application with RBAC access to talk to Azure RM APIs
 	AADClientCertPassword string `json:"aadClientCertPassword,omitempty" yaml:"aadClientCertPassword,omitempty" datapolicy:"password"`
+	// The interval to check the client certificate file for rotation, e.g. when it is synced from Key Vault by the secrets
+	// store CSI driver. The PFX or PEM certificate is reloaded without restarting if it changes. Disabled by default.
+	AADClientCertReloadIntervalInSeconds int `json:"aadClientCertReloadIntervalInSeconds,omitempty" yaml:"aadClientCertReloadIntervalInSeconds,omitempty"`
 	// Use managed service identity for the virtual machine to access Azure ARM APIs
 	UseManagedIdentityExtension bool `json:"useManagedIdentityExtension,omitempty" yaml:"useManagedIdentityExtension,omitempty"`
-	// UserAssignedIdentityID contains the Client ID of the user assigned MSI which is assigned to the underlying VMs. If empty the user assigned identity is not used.
+	// UserAssignedIdentityID contains the Client ID or the full resource ID of the user assigned MSI which is assigned to the underlying VMs.
+	// If empty the user assigned identity is not used.
 	// More details of the user assigned identity can be found at: https://docs.microsoft.com/en-us/azure/active-directory/managed-service-identity/overview
 	// For the user assigned identity specified here to be used, the UseManagedIdentityExtension has to be set to true.
 	UserAssignedIdentityID string `json:"userAssignedIdentityID,omitempty" yaml:"userAssignedIdentityID,omitempty"`
+	// NetworkUserAssignedIdentityID contains the Client ID or the full resource ID of the user assigned MSI used by the network
+	// clients, so the network and the compute resources can be operated by different identities. The UserAssignedIdentityID is used
+	// by the compute clients, and by the network clients too if empty. It can't be used with the multi-tenant network resources.
+	NetworkUserAssignedIdentityID string `json:"networkUserAssignedIdentityID,omitempty" yaml:"networkUserAssignedIdentityID,omitempty"`
 	// The AAD federated token file
 	AADFederatedTokenFile string `json:"aadFederatedTokenFile,omitempty" yaml:"aadFederatedTokenFile,omitempty"`
 	// Use workload identity federation for the virtual machine to access Azure ARM APIs
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -32,13 +33,18 @@ import (
 )
 
 var (
-	ErrAuxiliaryTokenProviderNotSet = errors.New("auxiliary token provider is not set when multi-tenant is enabled for MSI")
-	ErrNewKeyVaultCredentialFailed  = errors.New("create KeyVaultCredential failed")
+	ErrAuxiliaryTokenProviderNotSet        = errors.New("auxiliary token provider is not set when multi-tenant is enabled for MSI")
+	ErrNewKeyVaultCredentialFailed         = errors.New("create KeyVaultCredential failed")
+	ErrNetworkIdentityWithMultiTenant      = errors.New("network user assigned identity can't be used when multi-tenant is enabled for MSI")
+	ErrInvalidUserAssignedIdentityResource = errors.New("invalid resource ID of the user assigned identity")
 )
 
 // newAuthProviderWithWorkloadIdentity creates a new AuthProvider with workload identity.
 // The caller is responsible for checking if workload identity is enabled.
-// NOTE: it does NOT support multi-tenant scenarios.
+// The projected service account token is re-read from the token file whenever the credential
+// refreshes its ARM token, so rotated tokens are picked up without restarting.
+// When multi-tenant is enabled, the federated credential is exchanged in the network tenant as well,
+// which requires the application to be registered as multi-tenant.
 func newAuthProviderWithWorkloadIdentity(
 	aadFederatedTokenFile string,
 	armConfig *ARMClientConfig,
@@ -46,18 +52,50 @@ func newAuthProviderWithWorkloadIdentity(
 	clientOptions *policy.ClientOptions,
 	opts *authProviderOptions,
 ) (*AuthProvider, error) {
-	computeCredential, err := opts.NewWorkloadIdentityCredentialFn(&azidentity.WorkloadIdentityCredentialOptions{
+	if !IsMultiTenant(armConfig) {
+		// Single tenant
+		computeCredential, err := opts.NewWorkloadIdentityCredentialFn(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: *clientOptions,
+			ClientID:      config.GetAADClientID(),
+			TenantID:      armConfig.GetTenantID(),
+			TokenFilePath: aadFederatedTokenFile,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &AuthProvider{
+			ComputeCredential: computeCredential,
+			CloudConfig:       clientOptions.Cloud,
+		}, nil
+	}
+
+	// Network credential for network resource access
+	networkCredential, err := opts.NewWorkloadIdentityCredentialFn(&azidentity.WorkloadIdentityCredentialOptions{
 		ClientOptions: *clientOptions,
 		ClientID:      config.GetAADClientID(),
-		TenantID:      armConfig.GetTenantID(),
+		TenantID:      armConfig.NetworkResourceTenantID,
 		TokenFilePath: aadFederatedTokenFile,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Compute credential with additional allowed tenants for cross-tenant access
+	computeCredential, err := opts.NewWorkloadIdentityCredentialFn(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions:              *clientOptions,
+		ClientID:                   config.GetAADClientID(),
+		TenantID:                   armConfig.GetTenantID(),
+		TokenFilePath:              aadFederatedTokenFile,
+		AdditionallyAllowedTenants: []string{armConfig.NetworkResourceTenantID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &AuthProvider{
 		ComputeCredential: computeCredential,
+		NetworkCredential: networkCredential,
 		CloudConfig:       clientOptions.Cloud,
 	}, nil
 }
@@ -65,25 +103,15 @@ func newAuthProviderWithWorkloadIdentity(
 // newAuthProviderWithManagedIdentity creates a new AuthProvider with managed identity.
 // When multi-tenant is enabled, it uses the auxiliary token provider to create a network credential
 // for cross-tenant resource access. If multi-tenant is enabled but the auxiliary token provider
-// is not configured, it returns an error.
+// is not configured, it returns an error. Otherwise the network credential is created from the network user
+// assigned identity if set.
 func newAuthProviderWithManagedIdentity(
 	armConfig *ARMClientConfig,
 	config *AzureAuthConfig,
 	clientOptions *policy.ClientOptions,
 	opts *authProviderOptions,
 ) (*AuthProvider, error) {
-	credOptions := &azidentity.ManagedIdentityCredentialOptions{
-		ClientOptions: *clientOptions,
-	}
-	if len(config.UserAssignedIdentityID) > 0 {
-		if strings.Contains(strings.ToUpper(config.UserAssignedIdentityID), "/SUBSCRIPTIONS/") {
-			credOptions.ID = azidentity.ResourceID(config.UserAssignedIdentityID)
-		} else {
-			credOptions.ID = azidentity.ClientID(config.UserAssignedIdentityID)
-		}
-	}
-
-	computeCredential, err := opts.NewManagedIdentityCredentialFn(credOptions)
+	computeCredential, err := newManagedIdentityCredential(config.UserAssignedIdentityID, clientOptions, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -94,9 +122,19 @@ func newAuthProviderWithManagedIdentity(
 	}
 
 	if !IsMultiTenant(armConfig) {
+		if len(config.NetworkUserAssignedIdentityID) > 0 {
+			// The network clients are routed to the network credential
+			rv.NetworkCredential, err = newManagedIdentityCredential(config.NetworkUserAssignedIdentityID, clientOptions, opts)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return rv, nil
 	}
 
+	if len(config.NetworkUserAssignedIdentityID) > 0 {
+		return nil, ErrNetworkIdentityWithMultiTenant
+	}
 	if config.AuxiliaryTokenProvider == nil {
 		return nil, ErrAuxiliaryTokenProviderNotSet
 	}
@@ -124,6 +162,29 @@ func newAuthProviderWithManagedIdentity(
 	return rv, nil
 }
 
+// newManagedIdentityCredential creates the managed identity credential of the user assigned identity,
+// which is specified by either the client ID or the full resource ID, or of the system assigned identity if empty.
+func newManagedIdentityCredential(
+	userAssignedIdentityID string,
+	clientOptions *policy.ClientOptions,
+	opts *authProviderOptions,
+) (azcore.TokenCredential, error) {
+	credOptions := &azidentity.ManagedIdentityCredentialOptions{
+		ClientOptions: *clientOptions,
+	}
+	if len(userAssignedIdentityID) > 0 {
+		if strings.Contains(strings.ToUpper(userAssignedIdentityID), "/SUBSCRIPTIONS/") {
+			if _, err := arm.ParseResourceID(userAssignedIdentityID); err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidUserAssignedIdentityResource, userAssignedIdentityID, err)
+			}
+			credOptions.ID = azidentity.ResourceID(userAssignedIdentityID)
+		} else {
+			credOptions.ID = azidentity.ClientID(userAssignedIdentityID)
+		}
+	}
+	return opts.NewManagedIdentityCredentialFn(credOptions)
+}
+
 // newAuthProviderWithServicePrincipalClientSecret creates a new AuthProvider with service principal client secret.
 // When multi-tenant is enabled, it creates a compute credential with additional allowed tenants for cross-tenant access.
 func newAuthProviderWithServicePrincipalClientSecret(
@@ -203,39 +264,55 @@ func newAuthProviderWithServicePrincipalClientSecret(
 
 // newAuthProviderWithServicePrincipalClientCertificate creates a new AuthProvider with service principal client certificate.
 // When multi-tenant is enabled, it creates a compute credential with additional allowed tenants for cross-tenant access.
+// When the reload interval is set, the credentials are rebuilt after the certificate file is rotated.
 func newAuthProviderWithServicePrincipalClientCertificate(
 	armConfig *ARMClientConfig,
 	config *AzureAuthConfig,
 	clientOptions *policy.ClientOptions,
 	opts *authProviderOptions,
 ) (*AuthProvider, error) {
-	certData, err := opts.ReadFileFn(config.AADClientCertPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading the client certificate from file %s: %w", config.AADClientCertPath, err)
-	}
-	certificate, privateKey, err := opts.ParseCertificatesFn(certData, []byte(config.AADClientCertPassword))
-	if err != nil {
-		return nil, fmt.Errorf("decoding the client certificate: %w", err)
+	readCertificate := func(name string) ([]byte, error) {
+		certData, err := opts.ReadFileFn(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading the client certificate from file %s: %w", name, err)
+		}
+		return certData, nil
 	}
+	newCredential := func(tenantID string, credOptions *azidentity.ClientCertificateCredentialOptions) (azcore.TokenCredential, error) {
+		newCredentialFromCertificate := func(certData []byte) (azcore.TokenCredential, error) {
+			certificate, privateKey, err := opts.ParseCertificatesFn(certData, []byte(config.AADClientCertPassword))
+			if err != nil {
+				return nil, fmt.Errorf("decoding the client certificate: %w", err)
+			}
+			return opts.NewClientCertificateCredentialFn(tenantID, config.GetAADClientID(), certificate, privateKey, credOptions)
+		}
 
-	var (
-		computeCredential azcore.TokenCredential
-		networkCredential azcore.TokenCredential
-	)
+		if config.AADClientCertReloadIntervalInSeconds > 0 {
+			credential, err := armauth.NewFileReloadingCredential(
+				config.AADClientCertPath,
+				time.Duration(config.AADClientCertReloadIntervalInSeconds)*time.Second,
+				readCertificate,
+				newCredentialFromCertificate,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return credential, nil
+		}
+
+		certData, err := readCertificate(config.AADClientCertPath)
+		if err != nil {
+			return nil, err
+		}
+		return newCredentialFromCertificate(certData)
+	}
 
 	if !IsMultiTenant(armConfig) {
 		// Single tenant
-		credOptions := &azidentity.ClientCertificateCredentialOptions{
+		computeCredential, err := newCredential(armConfig.GetTenantID(), &azidentity.ClientCertificateCredentialOptions{
 			ClientOptions:        *clientOptions,
 			SendCertificateChain: true,
-		}
-		computeCredential, err = opts.NewClientCertificateCredentialFn(
-			armConfig.GetTenantID(),
-			config.GetAADClientID(),
-			certificate,
-			privateKey,
-			credOptions,
-		)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -246,40 +323,22 @@ func newAuthProviderWithServicePrincipalClientCertificate(
 	}
 
 	// Network credential for network resource access
-	{
-		credOptions := &azidentity.ClientCertificateCredentialOptions{
-			ClientOptions:        *clientOptions,
-			SendCertificateChain: true,
-		}
-		networkCredential, err = opts.NewClientCertificateCredentialFn(
-			armConfig.NetworkResourceTenantID,
-			config.GetAADClientID(),
-			certificate,
-			privateKey,
-			credOptions,
-		)
-		if err != nil {
-			return nil, err
-		}
+	networkCredential, err := newCredential(armConfig.NetworkResourceTenantID, &azidentity.ClientCertificateCredentialOptions{
+		ClientOptions:        *clientOptions,
+		SendCertificateChain: true,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Compute credential with additional allowed tenants for cross-tenant access
-	{
-		credOptions := &azidentity.ClientCertificateCredentialOptions{
-			ClientOptions:              *clientOptions,
-			AdditionallyAllowedTenants: []string{armConfig.NetworkResourceTenantID},
-			SendCertificateChain:       true,
-		}
-		computeCredential, err = opts.NewClientCertificateCredentialFn(
-			armConfig.GetTenantID(),
-			config.GetAADClientID(),
-			certificate,
-			privateKey,
-			credOptions,
-		)
-		if err != nil {
-			return nil, err
-		}
+	computeCredential, err := newCredential(armConfig.GetTenantID(), &azidentity.ClientCertificateCredentialOptions{
+		ClientOptions:              *clientOptions,
+		AdditionallyAllowedTenants: []string{armConfig.NetworkResourceTenantID},
+		SendCertificateChain:       true,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &AuthProvider{
@@ -38,18 +38,24 @@ func TestNewAuthProviderWithWorkloadIdentity(t *testing.T) {
 	t.Parallel()
 
 	var (
-		testAADClientID   = faker.UUIDHyphenated()
-		testTenantID      = faker.UUIDHyphenated()
-		testTokenFileName = faker.Word()
-		testARMConfig     = &ARMClientConfig{
+		testAADClientID     = faker.UUIDHyphenated()
+		testTenantID        = faker.UUIDHyphenated()
+		testNetworkTenantID = faker.UUIDHyphenated()
+		testTokenFileName   = faker.Word()
+		testARMConfig       = &ARMClientConfig{
 			TenantID: testTenantID,
 		}
+		testARMConfigMultiTenant = &ARMClientConfig{
+			TenantID:                testTenantID,
+			NetworkResourceTenantID: testNetworkTenantID,
+		}
 		testAzureAuthConfig = &AzureAuthConfig{
 			AADClientID: testAADClientID,
 		}
 		testCloudConfig                = cloud.AzurePublic
 		testClientOption               = &policy.ClientOptions{Cloud: testCloudConfig}
 		testFakeComputeTokenCredential = newFakeTokenCredential()
+		testFakeNetworkTokenCredential = newFakeTokenCredential()
 		testErr                        = errors.New("test error")
 	)
 
@@ -77,7 +83,7 @@ func TestNewAuthProviderWithWorkloadIdentity(t *testing.T) {
 			ExpectErr: testErr,
 		},
 		{
-			Name:                  "success",
+			Name:                  "success with single tenant",
 			AADFederatedTokenFile: testTokenFileName,
 			ARMConfig:             testARMConfig,
 			AuthConfig:            testAzureAuthConfig,
@@ -88,6 +94,7 @@ func TestNewAuthProviderWithWorkloadIdentity(t *testing.T) {
 					assert.Equal(t, testAADClientID, options.ClientID)
 					assert.Equal(t, testTenantID, options.TenantID)
 					assert.Equal(t, testTokenFileName, options.TokenFilePath)
+					assert.Empty(t, options.AdditionallyAllowedTenants)
 
 					return testFakeComputeTokenCredential, nil
 				},
@@ -99,6 +106,69 @@ func TestNewAuthProviderWithWorkloadIdentity(t *testing.T) {
 				AssertCloudConfig(testCloudConfig),
 			},
 		},
+		{
+			Name:                  "error when creating network credential in multi-tenant",
+			AADFederatedTokenFile: testTokenFileName,
+			ARMConfig:             testARMConfigMultiTenant,
+			AuthConfig:            testAzureAuthConfig,
+			ClientOption:          testClientOption,
+			Opts: &authProviderOptions{
+				NewWorkloadIdentityCredentialFn: func(options *azidentity.WorkloadIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					if options.TenantID == testNetworkTenantID {
+						return nil, testErr
+					}
+					return testFakeComputeTokenCredential, nil
+				},
+			},
+			ExpectErr: testErr,
+		},
+		{
+			Name:                  "error when creating compute credential in multi-tenant",
+			AADFederatedTokenFile: testTokenFileName,
+			ARMConfig:             testARMConfigMultiTenant,
+			AuthConfig:            testAzureAuthConfig,
+			ClientOption:          testClientOption,
+			Opts: &authProviderOptions{
+				NewWorkloadIdentityCredentialFn: func(options *azidentity.WorkloadIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					if options.TenantID == testTenantID {
+						return nil, testErr
+					}
+					return testFakeNetworkTokenCredential, nil
+				},
+			},
+			ExpectErr: testErr,
+		},
+		{
+			Name:                  "success with multi-tenant",
+			AADFederatedTokenFile: testTokenFileName,
+			ARMConfig:             testARMConfigMultiTenant,
+			AuthConfig:            testAzureAuthConfig,
+			ClientOption:          testClientOption,
+			Opts: &authProviderOptions{
+				NewWorkloadIdentityCredentialFn: func(options *azidentity.WorkloadIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					assert.Equal(t, *testClientOption, options.ClientOptions)
+					assert.Equal(t, testAADClientID, options.ClientID)
+					assert.Equal(t, testTokenFileName, options.TokenFilePath)
+
+					if options.TenantID == testNetworkTenantID {
+						assert.Empty(t, options.AdditionallyAllowedTenants)
+						return testFakeNetworkTokenCredential, nil
+					} else if options.TenantID == testTenantID {
+						assert.Contains(t, options.AdditionallyAllowedTenants, testNetworkTenantID)
+						return testFakeComputeTokenCredential, nil
+					}
+
+					t.Fatalf("unexpected tenant ID: %s", options.TenantID)
+					return nil, nil
+				},
+			},
+			Assertions: []AuthProviderAssertions{
+				AssertComputeTokenCredential(testFakeComputeTokenCredential),
+				AssertNetworkTokenCredential(testFakeNetworkTokenCredential),
+				AssertEmptyAdditionalComputeClientOptions(),
+				AssertCloudConfig(testCloudConfig),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +211,11 @@ func TestNewAuthProviderWithManagedIdentity(t *testing.T) {
 		testAzureAuthConfig = &AzureAuthConfig{
 			UserAssignedIdentityID: testUserAssignedIdentityID,
 		}
+		testNetworkUserAssignedIdentityID      = "/subscriptions/" + faker.UUIDHyphenated() + "/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/network"
+		testAzureAuthConfigWithNetworkIdentity = &AzureAuthConfig{
+			UserAssignedIdentityID:        testUserAssignedIdentityID,
+			NetworkUserAssignedIdentityID: testNetworkUserAssignedIdentityID,
+		}
 		testAzureAuthConfigWithAuxiliaryProvider = &AzureAuthConfig{
 			UserAssignedIdentityID: testUserAssignedIdentityID,
 			AuxiliaryTokenProvider: &AzureAuthAuxiliaryTokenProvider{
@@ -197,6 +272,53 @@ func TestNewAuthProviderWithManagedIdentity(t *testing.T) {
 				AssertCloudConfig(testCloudConfig),
 			},
 		},
+		{
+			Name:         "success with single tenant and network identity by resource ID",
+			ARMConfig:    testARMConfig,
+			AuthConfig:   testAzureAuthConfigWithNetworkIdentity,
+			ClientOption: testClientOption,
+			Opts: &authProviderOptions{
+				NewManagedIdentityCredentialFn: func(options *azidentity.ManagedIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					if options.ID == azidentity.ResourceID(testNetworkUserAssignedIdentityID) {
+						return testFakeNetworkTokenCredential, nil
+					}
+					assert.Equal(t, azidentity.ClientID(testUserAssignedIdentityID), options.ID)
+					return testFakeComputeTokenCredential, nil
+				},
+			},
+			Assertions: []AuthProviderAssertions{
+				AssertComputeTokenCredential(testFakeComputeTokenCredential),
+				AssertNetworkTokenCredential(testFakeNetworkTokenCredential),
+				AssertEmptyAdditionalComputeClientOptions(),
+				AssertCloudConfig(testCloudConfig),
+			},
+		},
+		{
+			Name:      "error with invalid resource ID of the user assigned identity",
+			ARMConfig: testARMConfig,
+			AuthConfig: &AzureAuthConfig{
+				UserAssignedIdentityID: "/subscriptions/",
+			},
+			ClientOption: testClientOption,
+			Opts: &authProviderOptions{
+				NewManagedIdentityCredentialFn: func(_ *azidentity.ManagedIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					return testFakeComputeTokenCredential, nil
+				},
+			},
+			ExpectErr: ErrInvalidUserAssignedIdentityResource,
+		},
+		{
+			Name:         "error with multi-tenant and network identity",
+			ARMConfig:    testARMConfigMultiTenant,
+			AuthConfig:   testAzureAuthConfigWithNetworkIdentity,
+			ClientOption: testClientOption,
+			Opts: &authProviderOptions{
+				NewManagedIdentityCredentialFn: func(_ *azidentity.ManagedIdentityCredentialOptions) (azcore.TokenCredential, error) {
+					return testFakeComputeTokenCredential, nil
+				},
+			},
+			ExpectErr: ErrNetworkIdentityWithMultiTenant,
+		},
 		{
 			Name:         "error with multi-tenant and no auxiliary token provider",
 			ARMConfig:    testARMConfigMultiTenant,
@@ -569,6 +691,37 @@ func TestNewAuthProviderWithServicePrincipalClientCertificate(t *testing.T) {
 				AssertCloudConfig(testCloudConfig),
 			},
 		},
+		{
+			Name:      "success with certificate reloading",
+			ARMConfig: testARMConfig,
+			AuthConfig: &AzureAuthConfig{
+				AADClientID:                          testAADClientID,
+				AADClientCertPath:                    testAADClientCertPath,
+				AADClientCertPassword:                testAADClientCertPassword,
+				AADClientCertReloadIntervalInSeconds: 60,
+			},
+			ClientOption: testClientOption,
+			Opts: &authProviderOptions{
+				ReadFileFn: func(name string) ([]byte, error) {
+					assert.Equal(t, testAADClientCertPath, name)
+					return testCertData, nil
+				},
+				ParseCertificatesFn: func(_ []byte, _ []byte) ([]*x509.Certificate, crypto.PrivateKey, error) {
+					return testCerts, testPrivateKey, nil
+				},
+				NewClientCertificateCredentialFn: func(_ string, _ string, _ []*x509.Certificate, _ crypto.PrivateKey, _ *azidentity.ClientCertificateCredentialOptions) (azcore.TokenCredential, error) {
+					return testFakeComputeTokenCredential, nil
+				},
+			},
+			Assertions: []AuthProviderAssertions{
+				func(t testing.TB, authProvider *AuthProvider) {
+					_, ok := authProvider.ComputeCredential.(*armauth.FileReloadingCredential)
+					assert.True(t, ok, "expected a file reloading credential")
+				},
+				AssertNilNetworkTokenCredential(),
+				AssertCloudConfig(testCloudConfig),
+			},
+		},
 		{
 			Name:         "error when creating network credential in multi-tenant",
 			ARMConfig:    testARMConfigMultiTenant,
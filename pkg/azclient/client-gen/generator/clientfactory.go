@@ -123,7 +123,6 @@ func (generator ClientFactoryGenerator) Generate(ctx *genall.GenerationContext)
 		codeimportList["github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"] = make(map[string]struct{})
 		codeimportList["github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"] = make(map[string]struct{})
 		codeimportList["sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"] = make(map[string]struct{})
-		codeimportList["sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"] = make(map[string]struct{})
 		codeimportList["github.com/Azure/azure-sdk-for-go/sdk/azidentity"] = make(map[string]struct{})
 
 		err = DumpHeaderToWriter(ctx, file, generator.HeaderFile, codeimportList, "azclient")
@@ -259,11 +258,7 @@ func (factory *ClientFactoryImpl) create{{$resource}}Client(subscription string)
 	{{- end }}
 	{{- with $client.RateLimitKey}}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("{{.}}")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("{{.}}"))
 	{{- end }}
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"strings"
@@ -111,6 +112,12 @@ func OverrideAzureCloudConfigFromEnv(cloudName string, config *cloud.Configurati
 	if !ok {
 		return nil
 	}
+	return OverrideAzureCloudConfigFromFile(envFilePath, config, env)
+}
+
+// OverrideAzureCloudConfigFromFile overrides the cloud config and the environment with the
+// environment metadata JSON file, e.g. of an Azure Stack Hub or an air-gapped cloud.
+func OverrideAzureCloudConfigFromFile(envFilePath string, config *cloud.Configuration, env *Environment) error {
 	content, err := os.ReadFile(envFilePath)
 	if err != nil {
 		return err
@@ -139,14 +146,43 @@ func GetAzureCloudConfigAndEnvConfig(armConfig *ARMClientConfig) (cloud.Configur
 	if armConfig == nil {
 		return *config, nil, nil
 	}
-	env := EnvironmentFromName(cloudName)
+	// copy the predefined cloud and environment so the overrides below don't leak into the shared ones
+	configCopy := *config
+	configCopy.Services = maps.Clone(config.Services)
+	config = &configCopy
+	envCopy := *EnvironmentFromName(cloudName)
+	env := &envCopy
 
 	err := OverrideAzureCloudConfigAndEnvConfigFromMetadataService(armConfig.ResourceManagerEndpoint, cloudName, config, env)
 	if err != nil {
 		return *config, nil, err
 	}
 	err = OverrideAzureCloudConfigFromEnv(cloudName, config, env)
-	return *config, env, err
+	if err != nil {
+		return *config, env, err
+	}
+	if armConfig.EnvironmentFilePath != "" {
+		if err = OverrideAzureCloudConfigFromFile(armConfig.EnvironmentFilePath, config, env); err != nil {
+			return *config, env, fmt.Errorf("reading the environment from %s: %w", armConfig.EnvironmentFilePath, err)
+		}
+	}
+	OverrideAzureCloudConfigFromARMConfig(armConfig, config, env)
+	return *config, env, nil
+}
+
+// OverrideAzureCloudConfigFromARMConfig overrides the AAD endpoint and the resource manager audience
+// of the cloud config and the environment with the ones set in the ARM client config.
+func OverrideAzureCloudConfigFromARMConfig(armConfig *ARMClientConfig, config *cloud.Configuration, env *Environment) {
+	if armConfig.ActiveDirectoryEndpoint != "" {
+		config.ActiveDirectoryAuthorityHost = armConfig.ActiveDirectoryEndpoint
+		env.ActiveDirectoryEndpoint = armConfig.ActiveDirectoryEndpoint
+	}
+	if armConfig.ResourceManagerAudience != "" {
+		service := config.Services[cloud.ResourceManager]
+		service.Audience = armConfig.ResourceManagerAudience
+		config.Services[cloud.ResourceManager] = service
+		env.TokenAudience = armConfig.ResourceManagerAudience
+	}
 }
 
 // Environment represents a set of endpoints for each of Azure's Clouds.
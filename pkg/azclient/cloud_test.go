@@ -263,4 +263,57 @@ var _ = ginkgo.Describe("Cloud", func() {
 		})
 
 	})
+	ginkgo.Context("GetAzureCloudConfigAndEnvConfig", func() {
+		ginkgo.When("the environment file path is set", func() {
+			ginkgo.It("should return the custom cloud", func() {
+				configFile, err := os.CreateTemp("", "environment.json")
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				defer os.Remove(configFile.Name())
+
+				err = os.WriteFile(configFile.Name(), []byte(`
+				{
+				   "name":"AzureCustomCloud",
+				   "resourceManagerEndpoint":"https://management.custom.cloud",
+				   "activeDirectoryEndpoint":"https://login.custom.cloud",
+				   "tokenAudience":"https://management.core.custom.cloud/",
+				   "storageEndpointSuffix":"core.custom.cloud"
+				}`), 0600)
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				cloudConfig, env, err := azclient.GetAzureCloudConfigAndEnvConfig(&azclient.ARMClientConfig{
+					Cloud:               "AzurePublicCloud",
+					EnvironmentFilePath: configFile.Name(),
+				})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(cloudConfig.ActiveDirectoryAuthorityHost).To(gomega.Equal("https://login.custom.cloud"))
+				gomega.Expect(cloudConfig.Services[cloud.ResourceManager].Endpoint).To(gomega.Equal("https://management.custom.cloud"))
+				gomega.Expect(cloudConfig.Services[cloud.ResourceManager].Audience).To(gomega.Equal("https://management.core.custom.cloud/"))
+				gomega.Expect(env.StorageEndpointSuffix).To(gomega.Equal("core.custom.cloud"))
+				gomega.Expect(azclient.PublicCloud.ResourceManagerEndpoint).To(gomega.Equal("https://management.azure.com/"))
+				gomega.Expect(utils.AzureCloudConfigFromName("AzurePublicCloud").Services[cloud.ResourceManager].Endpoint).NotTo(gomega.Equal("https://management.custom.cloud"))
+			})
+		})
+		ginkgo.When("the environment file is not found", func() {
+			ginkgo.It("should return error", func() {
+				_, _, err := azclient.GetAzureCloudConfigAndEnvConfig(&azclient.ARMClientConfig{
+					EnvironmentFilePath: "notfound",
+				})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+		ginkgo.When("the endpoint and the audience are overridden", func() {
+			ginkgo.It("should return the overridden cloud", func() {
+				cloudConfig, env, err := azclient.GetAzureCloudConfigAndEnvConfig(&azclient.ARMClientConfig{
+					Cloud:                   "AzureChinaCloud",
+					ActiveDirectoryEndpoint: "https://login.custom.cloud/",
+					ResourceManagerAudience: "https://management.custom.cloud/",
+				})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(cloudConfig.ActiveDirectoryAuthorityHost).To(gomega.Equal("https://login.custom.cloud/"))
+				gomega.Expect(cloudConfig.Services[cloud.ResourceManager].Endpoint).To(gomega.Equal("https://management.chinacloudapi.cn"))
+				gomega.Expect(cloudConfig.Services[cloud.ResourceManager].Audience).To(gomega.Equal("https://management.custom.cloud/"))
+				gomega.Expect(env.ActiveDirectoryEndpoint).To(gomega.Equal("https://login.custom.cloud/"))
+				gomega.Expect(env.TokenAudience).To(gomega.Equal("https://management.custom.cloud/"))
+			})
+		})
+	})
 })
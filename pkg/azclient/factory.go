@@ -45,6 +45,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/routetableclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/secretclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/snapshotclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/sshpublickeyresourceclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/subnetclient"
@@ -90,6 +91,7 @@ type ClientFactory interface {
 	GetRouteTableClient() routetableclient.Interface
 	GetSecretClient() secretclient.Interface
 	GetSecurityGroupClient() securitygroupclient.Interface
+	GetSecurityRuleClient() securityruleclient.Interface
 	GetSnapshotClient() snapshotclient.Interface
 	GetSnapshotClientForSub(subscriptionID string) (snapshotclient.Interface, error)
 	GetSSHPublicKeyResourceClient() sshpublickeyresourceclient.Interface
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
@@ -49,6 +50,29 @@ type ClientFactoryConfig struct {
 	ratelimit.CloudProviderRateLimitConfig
 	// The ID of the Azure Subscription that the cluster is deployed in
 	SubscriptionID string `json:"subscriptionId,omitempty" yaml:"subscriptionId,omitempty"`
+
+	rateLimitLock     sync.Mutex
+	rateLimitPolicies []*ratelimit.Policy
+}
+
+// NewRateLimitPolicy returns the rate limit policy of the client, which follows the rate limit config
+// applied later with UpdateRateLimitConfig.
+func (config *ClientFactoryConfig) NewRateLimitPolicy(clientName string) azpolicy.Policy {
+	config.rateLimitLock.Lock()
+	defer config.rateLimitLock.Unlock()
+	p := ratelimit.NewDynamicRateLimitPolicy(clientName, config.GetRateLimitConfig(clientName))
+	config.rateLimitPolicies = append(config.rateLimitPolicies, p)
+	return p
+}
+
+// UpdateRateLimitConfig applies the rate limit config to the clients created by the factory without recreating them.
+func (config *ClientFactoryConfig) UpdateRateLimitConfig(rateLimitConfig ratelimit.CloudProviderRateLimitConfig) {
+	config.rateLimitLock.Lock()
+	defer config.rateLimitLock.Unlock()
+	config.CloudProviderRateLimitConfig = rateLimitConfig
+	for _, p := range config.rateLimitPolicies {
+		p.Update(config.GetRateLimitConfig(p.ClientName()))
+	}
 }
 
 func GetDefaultResourceClientOption(armConfig *ARMClientConfig) (*policy.ClientOptions, error) {
@@ -64,5 +88,18 @@ func GetDefaultResourceClientOption(armConfig *ARMClientConfig) (*policy.ClientO
 	}
 
 	armClientOption.ClientOptions.Transport = DefaultResourceClientTransport
+	transport, err := GetTransport(armConfig)
+	if err != nil {
+		return nil, err
+	}
+	if transport != utils.DefaultTransport {
+		armClientOption.ClientOptions.Transport = &http.Client{
+			Transport: armbalancer.New(context.Background(), armbalancer.Options{
+				Transport: transport,
+				PoolSize:  100,
+			}),
+			Timeout: time.Minute,
+		}
+	}
 	return &armClientOption, err
 }
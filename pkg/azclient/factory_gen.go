@@ -40,7 +40,6 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/ipgroupclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/managedclusterclient"
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatednszonegroupclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privateendpointclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatelinkserviceclient"
@@ -54,6 +53,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/routetableclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/secretclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/snapshotclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/sshpublickeyresourceclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/subnetclient"
@@ -99,6 +99,7 @@ type ClientFactoryImpl struct {
 	routetableclientInterface               routetableclient.Interface
 	secretclientInterface                   secretclient.Interface
 	securitygroupclientInterface            securitygroupclient.Interface
+	securityruleclientInterface             securityruleclient.Interface
 	snapshotclientInterface                 sync.Map
 	sshpublickeyresourceclientInterface     sshpublickeyresourceclient.Interface
 	subnetclientInterface                   subnetclient.Interface
@@ -290,6 +291,12 @@ func NewClientFactory(config *ClientFactoryConfig, armConfig *ARMClientConfig, c
 		return nil, err
 	}
 
+	//initialize securityruleclient
+	factory.securityruleclientInterface, err = factory.createSecurityRuleClient(config.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
 	//initialize snapshotclient
 	_, err = factory.GetSnapshotClientForSub(config.SubscriptionID)
 	if err != nil {
@@ -359,11 +366,7 @@ func (factory *ClientFactoryImpl) createAccountClient(subscription string) (acco
 		options.ClientOptions.APIVersion = accountclient.MooncakeApiVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("storageAccountRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("storageAccountRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -404,11 +407,7 @@ func (factory *ClientFactoryImpl) createAvailabilitySetClient(subscription strin
 		options.ClientOptions.APIVersion = availabilitysetclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("availabilitySetRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("availabilitySetRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -429,11 +428,7 @@ func (factory *ClientFactoryImpl) createBackendAddressPoolClient(subscription st
 	}
 	options.Cloud = factory.cloudConfig
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("loadBalancerRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("loadBalancerRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -536,11 +531,7 @@ func (factory *ClientFactoryImpl) createDeploymentClient(subscription string) (d
 	}
 	options.Cloud = factory.cloudConfig
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("deploymentRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("deploymentRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -564,11 +555,7 @@ func (factory *ClientFactoryImpl) createDiskClient(subscription string) (diskcli
 		options.ClientOptions.APIVersion = diskclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("diskRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("diskRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -706,11 +693,7 @@ func (factory *ClientFactoryImpl) createInterfaceClient(subscription string) (in
 		options.ClientOptions.APIVersion = interfaceclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("interfaceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("interfaceRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -734,11 +717,7 @@ func (factory *ClientFactoryImpl) createIPGroupClient(subscription string) (ipgr
 		options.ClientOptions.APIVersion = ipgroupclient.MooncakeApiVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("ipGroupRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("ipGroupRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -762,11 +741,7 @@ func (factory *ClientFactoryImpl) createLoadBalancerClient(subscription string)
 		options.ClientOptions.APIVersion = loadbalancerclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("loadBalancerRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("loadBalancerRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -787,11 +762,7 @@ func (factory *ClientFactoryImpl) createManagedClusterClient(subscription string
 	}
 	options.Cloud = factory.cloudConfig
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("containerServiceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("containerServiceRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -831,11 +802,7 @@ func (factory *ClientFactoryImpl) createPrivateEndpointClient(subscription strin
 	}
 	options.Cloud = factory.cloudConfig
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("privateEndpointRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("privateEndpointRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -859,11 +826,7 @@ func (factory *ClientFactoryImpl) createPrivateLinkServiceClient(subscription st
 		options.ClientOptions.APIVersion = privatelinkserviceclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("privateLinkServiceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("privateLinkServiceRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -887,11 +850,7 @@ func (factory *ClientFactoryImpl) createPrivateZoneClient(subscription string) (
 		options.ClientOptions.APIVersion = privatezoneclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("privateDNSRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("privateDNSRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -934,11 +893,7 @@ func (factory *ClientFactoryImpl) createPublicIPAddressClient(subscription strin
 		options.ClientOptions.APIVersion = publicipaddressclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("publicIPAddressRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("publicIPAddressRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1041,11 +996,7 @@ func (factory *ClientFactoryImpl) createRouteTableClient(subscription string) (r
 		options.ClientOptions.APIVersion = routetableclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("routeTableRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("routeTableRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1088,11 +1039,7 @@ func (factory *ClientFactoryImpl) createSecurityGroupClient(subscription string)
 		options.ClientOptions.APIVersion = securitygroupclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("securityGroupRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("securityGroupRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1105,6 +1052,27 @@ func (factory *ClientFactoryImpl) GetSecurityGroupClient() securitygroupclient.I
 	return factory.securitygroupclientInterface
 }
 
+func (factory *ClientFactoryImpl) createSecurityRuleClient(subscription string) (securityruleclient.Interface, error) {
+	//initialize securityruleclient
+	options, err := GetDefaultResourceClientOption(factory.armConfig)
+	if err != nil {
+		return nil, err
+	}
+	options.Cloud = factory.cloudConfig
+	//add ratelimit policy
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("securityGroupRateLimit"))
+	for _, optionMutFn := range factory.clientOptionsMutFn {
+		if optionMutFn != nil {
+			optionMutFn(options)
+		}
+	}
+	return securityruleclient.New(subscription, factory.cred, options)
+}
+
+func (factory *ClientFactoryImpl) GetSecurityRuleClient() securityruleclient.Interface {
+	return factory.securityruleclientInterface
+}
+
 func (factory *ClientFactoryImpl) createSnapshotClient(subscription string) (snapshotclient.Interface, error) {
 	//initialize snapshotclient
 	options, err := GetDefaultResourceClientOption(factory.armConfig)
@@ -1116,11 +1084,7 @@ func (factory *ClientFactoryImpl) createSnapshotClient(subscription string) (sna
 		options.ClientOptions.APIVersion = snapshotclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("snapshotRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("snapshotRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1180,11 +1144,7 @@ func (factory *ClientFactoryImpl) createSubnetClient(subscription string) (subne
 		options.ClientOptions.APIVersion = subnetclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("subnetsRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("subnetsRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1227,11 +1187,7 @@ func (factory *ClientFactoryImpl) createVirtualMachineClient(subscription string
 		options.ClientOptions.APIVersion = virtualmachineclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("virtualMachineRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("virtualMachineRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1255,11 +1211,7 @@ func (factory *ClientFactoryImpl) createVirtualMachineScaleSetClient(subscriptio
 		options.ClientOptions.APIVersion = virtualmachinescalesetclient.AzureStackCloudAPIVersion
 	}
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("virtualMachineScaleSetRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("virtualMachineScaleSetRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -1321,11 +1273,7 @@ func (factory *ClientFactoryImpl) createVirtualNetworkLinkClient(subscription st
 	}
 	options.Cloud = factory.cloudConfig
 	//add ratelimit policy
-	ratelimitOption := factory.factoryConfig.GetRateLimitConfig("virtualNetworkRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
-	if rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
+	options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, factory.factoryConfig.NewRateLimitPolicy("virtualNetworkRateLimit"))
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
 			optionMutFn(options)
@@ -214,6 +214,13 @@ var _ = ginkgo.Describe("Factory", func() {
 			client := factory.GetSecurityGroupClient()
 			gomega.Expect(client).NotTo(gomega.BeNil())
 		})
+		ginkgo.It("should create factory instance without painc - SecurityRule", func() {
+			factory, err := NewClientFactory(nil, nil, cloud.AzurePublic, nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(factory).NotTo(gomega.BeNil())
+			client := factory.GetSecurityRuleClient()
+			gomega.Expect(client).NotTo(gomega.BeNil())
+		})
 		ginkgo.It("should create factory instance without painc - Snapshot", func() {
 			factory, err := NewClientFactory(nil, nil, cloud.AzurePublic, nil)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -20,6 +20,7 @@ package loadbalancerclient
 import (
 	"context"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
@@ -32,4 +33,8 @@ type Interface interface {
 	utils.DeleteFunc[armnetwork.LoadBalancer]
 	utils.ListFunc[armnetwork.LoadBalancer]
 	MigrateToIPBased(ctx context.Context, groupName string, loadBalancerName string, options *armnetwork.LoadBalancersClientMigrateToIPBasedOptions) (armnetwork.LoadBalancersClientMigrateToIPBasedResponse, error)
+
+	// BeginCreateOrUpdate starts a LoadBalancer update and returns its poller without waiting for it,
+	// so the operation can be tracked and resumed with options.ResumeToken.
+	BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, loadBalancerName string, parameters armnetwork.LoadBalancer, options *armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions) (*runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], error)
 }
@@ -30,6 +30,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	runtime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -60,6 +61,45 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BeginCreateOrUpdate mocks base method.
+func (m *MockInterface) BeginCreateOrUpdate(ctx context.Context, resourceGroupName, loadBalancerName string, parameters armnetwork.LoadBalancer, options *armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions) (*runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginCreateOrUpdate", ctx, resourceGroupName, loadBalancerName, parameters, options)
+	ret0, _ := ret[0].(*runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginCreateOrUpdate indicates an expected call of BeginCreateOrUpdate.
+func (mr *MockInterfaceMockRecorder) BeginCreateOrUpdate(ctx, resourceGroupName, loadBalancerName, parameters, options any) *MockInterfaceBeginCreateOrUpdateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginCreateOrUpdate", reflect.TypeOf((*MockInterface)(nil).BeginCreateOrUpdate), ctx, resourceGroupName, loadBalancerName, parameters, options)
+	return &MockInterfaceBeginCreateOrUpdateCall{Call: call}
+}
+
+// MockInterfaceBeginCreateOrUpdateCall wrap *gomock.Call
+type MockInterfaceBeginCreateOrUpdateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockInterfaceBeginCreateOrUpdateCall) Return(arg0 *runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], arg1 error) *MockInterfaceBeginCreateOrUpdateCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockInterfaceBeginCreateOrUpdateCall) Do(f func(context.Context, string, string, armnetwork.LoadBalancer, *armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions) (*runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], error)) *MockInterfaceBeginCreateOrUpdateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockInterfaceBeginCreateOrUpdateCall) DoAndReturn(f func(context.Context, string, string, armnetwork.LoadBalancer, *armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions) (*runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], error)) *MockInterfaceBeginCreateOrUpdateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CreateOrUpdate mocks base method.
 func (m *MockInterface) CreateOrUpdate(ctx context.Context, resourceGroupName, resourceName string, resourceParam armnetwork.LoadBalancer) (*armnetwork.LoadBalancer, error) {
 	m.ctrl.T.Helper()
@@ -37,6 +37,10 @@ var (
 	armRequestErrors     api.Int64Counter
 	armRequestRateLimits api.Int64Counter
 	armRequestThrottles  api.Int64Counter
+	armRequestCache      api.Int64Counter
+
+	armRequestAttempts       api.Int64Counter
+	armRequestAttemptLatency api.Float64Histogram
 )
 
 // ARMContext is the context for ARM metrics.
@@ -150,6 +154,30 @@ func ARMRequestThrottles() api.Int64Counter {
 	return armRequestThrottles
 }
 
+// ARMRequestCacheResults returns the counter for the results of the ARM GETs served through the read cache.
+func ARMRequestCacheResults() api.Int64Counter {
+	if armRequestCache == nil {
+		return noop.Int64Counter{}
+	}
+	return armRequestCache
+}
+
+// ARMRequestAttempts returns the counter for the attempts of the ARM requests.
+func ARMRequestAttempts() api.Int64Counter {
+	if armRequestAttempts == nil {
+		return noop.Int64Counter{}
+	}
+	return armRequestAttempts
+}
+
+// ARMRequestAttemptLatency returns the histogram for the latency of the attempts of the ARM requests.
+func ARMRequestAttemptLatency() api.Float64Histogram {
+	if armRequestAttemptLatency == nil {
+		return noop.Float64Histogram{}
+	}
+	return armRequestAttemptLatency
+}
+
 // Setup sets up the ARM metrics.
 func Setup(meter api.Meter) error {
 	setups := []func(api.Meter) error{
@@ -157,6 +185,11 @@ func Setup(meter api.Meter) error {
 		setupARMRequestErrors,
 		setupARMRequestRateLimits,
 		setupARMRequestThrottles,
+		setupARMRateLimitSaturation,
+		setupARMRequestCache,
+		setupARMRequestAttempts,
+		setupARMRequestAttemptLatency,
+		setupARMRemainingQuota,
 	}
 
 	for _, setup := range setups {
@@ -228,3 +261,107 @@ func setupARMRequestThrottles(meter api.Meter) error {
 
 	return nil
 }
+
+func setupARMRateLimitSaturation(meter api.Meter) error {
+	_, err := meter.Float64ObservableGauge(
+		"arm.ratelimit.saturation",
+		api.WithDescription("Measures the fraction of the rate limiter bucket in use of Azure ARM clients."),
+		api.WithFloat64Callback(observeRateLimitSaturation),
+	)
+
+	if err != nil {
+		return fmt.Errorf("create arm.ratelimit.saturation gauge: %w", err)
+	}
+
+	return nil
+}
+
+// observeRateLimitSaturation reports the highest saturation among the rate limiters of each client,
+// since the same client may be created by several client factories.
+func observeRateLimitSaturation(_ context.Context, observer api.Float64Observer) error {
+	type key struct {
+		client    string
+		operation string
+	}
+	saturations := map[key]float64{}
+	ratelimit.RangeDynamicPolicies(func(p *ratelimit.Policy) {
+		read, write, ok := p.Saturation()
+		if !ok {
+			return
+		}
+		if k := (key{p.ClientName(), "read"}); read >= saturations[k] {
+			saturations[k] = read
+		}
+		if k := (key{p.ClientName(), "write"}); write >= saturations[k] {
+			saturations[k] = write
+		}
+	})
+	for k, saturation := range saturations {
+		observer.Observe(saturation, api.WithAttributes(
+			attribute.String("client", k.client),
+			attribute.String("operation", k.operation),
+		))
+	}
+	return nil
+}
+
+func setupARMRequestCache(meter api.Meter) error {
+	c, err := meter.Int64Counter(
+		"arm.request.cache.counter",
+		api.WithDescription("Measures the number of Azure ARM GETs served from the read cache, coalesced with a GET in flight, or sent to ARM."),
+	)
+
+	if err != nil {
+		return fmt.Errorf("create arm.request.cache.counter counter: %w", err)
+	}
+
+	armRequestCache = c
+
+	return nil
+}
+
+func setupARMRequestAttempts(meter api.Meter) error {
+	c, err := meter.Int64Counter(
+		"arm.request.attempt.counter",
+		api.WithDescription("Measures the number of attempts of Azure ARM API calls by the resource type, the verb, the status code, the throttling reason and the caller."),
+	)
+
+	if err != nil {
+		return fmt.Errorf("create arm.request.attempt.counter counter: %w", err)
+	}
+
+	armRequestAttempts = c
+
+	return nil
+}
+
+func setupARMRequestAttemptLatency(meter api.Meter) error {
+	m, err := meter.Float64Histogram(
+		"arm.request.attempt.duration",
+		api.WithUnit("s"),
+		api.WithDescription("Measures the duration of the attempts of Azure ARM API calls by the resource type, the verb, the status code, the throttling reason and the caller."),
+		api.WithExplicitBucketBoundaries(.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60),
+	)
+
+	if err != nil {
+		return fmt.Errorf("create arm.request.attempt.duration histogram: %w", err)
+	}
+
+	armRequestAttemptLatency = m
+
+	return nil
+}
+
+func setupARMRemainingQuota(meter api.Meter) error {
+	_, err := meter.Int64ObservableGauge(
+		"arm.quota.remaining",
+		api.WithDescription("Measures the remaining Azure ARM quota of the subscriptions reported by the x-ms-ratelimit-remaining-* response headers."),
+		api.WithInt64Callback(observeRemainingQuotaGauge),
+	)
+
+	if err != nil {
+		return fmt.Errorf("create arm.quota.remaining gauge: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
+)
+
+const (
+	// CallerUnknown is the caller of the ARM requests sent with the contexts without callers.
+	CallerUnknown = "unknown"
+
+	headerRemainingPrefix   = "X-Ms-Ratelimit-Remaining-"
+	headerRemainingResource = "X-Ms-Ratelimit-Remaining-Resource"
+
+	// remainingQuotaStaleAfter is how long the remaining quota reported by ARM is reported,
+	// the quota of ARM is replenished continuously.
+	remainingQuotaStaleAfter = 5 * time.Minute
+)
+
+type callerKey struct{}
+
+// WithCaller returns a context whose ARM requests are recorded as sent by the caller, e.g. the
+// controller reconciling the resources.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller of the ARM requests sent with the context.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerKey{}).(string)
+	return caller, ok
+}
+
+type remainingQuotaKey struct {
+	subscriptionID string
+	quota          string
+}
+
+type remainingQuota struct {
+	remaining  int64
+	observedAt time.Time
+}
+
+// remainingQuotas holds the remaining ARM quotas by the subscription and the quota, from the
+// x-ms-ratelimit-remaining-* headers of the last responses.
+var remainingQuotas sync.Map
+
+// Policy records the metrics of every attempt of the ARM requests, including the retries and the
+// attempts rejected by the throttling policies, and the remaining ARM quotas reported in the
+// response headers. It should be the first per-retry policy of the pipeline.
+type Policy struct {
+	now func() time.Time
+}
+
+// NewPolicy returns the policy recording the metrics of the ARM requests.
+func NewPolicy() policy.Policy {
+	return &Policy{now: time.Now}
+}
+
+func (p *Policy) Do(req *policy.Request) (*http.Response, error) {
+	startedAt := p.now()
+	resp, err := req.Next()
+
+	ctx := req.Raw().Context()
+	subscriptionID, resourceType := parseResourcePath(req.Raw().URL.Path)
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		caller = CallerUnknown
+	}
+	attributes := api.WithAttributes(
+		attribute.String("resource_type", resourceType),
+		attribute.String("verb", strings.ToLower(req.Raw().Method)),
+		attribute.String("status_code", statusCodeOf(resp)),
+		attribute.String("throttle_reason", throttleReasonOf(resp, err)),
+		attribute.String("caller", caller),
+	)
+	ARMRequestAttempts().Add(ctx, 1, attributes)
+	ARMRequestAttemptLatency().Record(ctx, p.now().Sub(startedAt).Seconds(), attributes)
+
+	if resp != nil {
+		observeRemainingQuotas(subscriptionID, resp.Header, p.now())
+	}
+	return resp, err
+}
+
+// parseResourcePath returns the subscription and the type of the resource of the ARM request path,
+// e.g. microsoft.compute/virtualmachinescalesets/virtualmachines for the VMSS VMs.
+func parseResourcePath(path string) (subscriptionID, resourceType string) {
+	segments := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	if len(segments) >= 2 && segments[0] == "subscriptions" {
+		subscriptionID = segments[1]
+	}
+
+	// the types are the segments after the namespace of the last provider, skipping the names
+	start := 0
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "providers" {
+			start = i + 1
+			break
+		}
+	}
+	types := []string{}
+	if start > 0 {
+		types = append(types, segments[start])
+		start++
+	}
+	for i := start; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	return subscriptionID, strings.Join(types, "/")
+}
+
+func statusCodeOf(resp *http.Response) string {
+	if resp == nil {
+		return "none"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
+
+// throttleReasonOf returns why the request is throttled, either by the policies of the client
+// before it is sent, or by the ARM quota exhausted. It is empty if the request is not throttled.
+func throttleReasonOf(resp *http.Response, err error) string {
+	if resp == nil {
+		switch {
+		case errors.Is(err, retryrepectthrottled.ErrTooManyRequest):
+			return "client_backoff"
+		case errors.Is(err, ratelimit.ErrRateLimitReached):
+			return "client_rate_limit"
+		}
+		return ""
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return ""
+	}
+
+	// the resource provider quotas, e.g. Microsoft.Compute/HighCostGet3Min;0
+	for _, entry := range strings.Split(resp.Header.Get(headerRemainingResource), ",") {
+		name, remaining, found := strings.Cut(strings.TrimSpace(entry), ";")
+		if found && strings.TrimSpace(remaining) == "0" {
+			return strings.ToLower(name)
+		}
+	}
+	// the subscription and tenant quotas, e.g. x-ms-ratelimit-remaining-subscription-reads: 0
+	for key, values := range resp.Header {
+		if len(values) > 0 && values[0] == "0" && strings.HasPrefix(key, headerRemainingPrefix) && key != headerRemainingResource {
+			return strings.ToLower(strings.TrimPrefix(key, headerRemainingPrefix))
+		}
+	}
+	return "unknown"
+}
+
+func observeRemainingQuotas(subscriptionID string, header http.Header, now time.Time) {
+	if subscriptionID == "" {
+		subscriptionID = "tenant"
+	}
+	store := func(quota, value string) {
+		if remaining, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			remainingQuotas.Store(remainingQuotaKey{subscriptionID, quota}, remainingQuota{remaining: remaining, observedAt: now})
+		}
+	}
+	for key, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(key, headerRemainingPrefix) {
+			continue
+		}
+		if key != headerRemainingResource {
+			store(strings.ToLower(strings.TrimPrefix(key, headerRemainingPrefix)), values[0])
+			continue
+		}
+		for _, entry := range strings.Split(values[0], ",") {
+			if name, remaining, found := strings.Cut(strings.TrimSpace(entry), ";"); found {
+				store(strings.ToLower(name), remaining)
+			}
+		}
+	}
+}
+
+// observeRemainingQuotaGauge reports the remaining ARM quotas observed recently.
+func observeRemainingQuotaGauge(_ context.Context, observer api.Int64Observer) error {
+	now := time.Now()
+	remainingQuotas.Range(func(k, v any) bool {
+		key, quota := k.(remainingQuotaKey), v.(remainingQuota)
+		if now.Sub(quota.observedAt) > remainingQuotaStaleAfter {
+			remainingQuotas.Delete(k)
+			return true
+		}
+		observer.Observe(quota.remaining, api.WithAttributes(
+			attribute.String("subscription_id", key.subscriptionID),
+			attribute.String("quota", key.quota),
+		))
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
+)
+
+func TestParseResourcePath(t *testing.T) {
+	for _, tc := range []struct {
+		path                 string
+		expectedSubscription string
+		expectedResourceType string
+	}{
+		{
+			path:                 "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb",
+			expectedSubscription: "sub",
+			expectedResourceType: "microsoft.network/loadbalancers",
+		},
+		{
+			path:                 "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines",
+			expectedSubscription: "sub",
+			expectedResourceType: "microsoft.compute/virtualmachinescalesets/virtualmachines",
+		},
+		{
+			path:                 "/subscriptions/sub/providers/Microsoft.Network/locations/eastus/operations/op",
+			expectedSubscription: "sub",
+			expectedResourceType: "microsoft.network/locations/operations",
+		},
+		{
+			path:                 "/subscriptions/sub/resourceGroups/rg",
+			expectedSubscription: "sub",
+			expectedResourceType: "subscriptions/resourcegroups",
+		},
+	} {
+		subscriptionID, resourceType := parseResourcePath(tc.path)
+		assert.Equal(t, tc.expectedSubscription, subscriptionID, tc.path)
+		assert.Equal(t, tc.expectedResourceType, resourceType, tc.path)
+	}
+}
+
+func TestThrottleReasonOf(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		resp     *http.Response
+		err      error
+		expected string
+	}{
+		{
+			desc:     "succeeded",
+			resp:     &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+			expected: "",
+		},
+		{
+			desc:     "rejected by the client",
+			err:      retryrepectthrottled.ErrTooManyRequest,
+			expected: "client_backoff",
+		},
+		{
+			desc: "resource provider quota exhausted",
+			resp: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{
+				"X-Ms-Ratelimit-Remaining-Resource": []string{"Microsoft.Compute/HighCostGet3Min;0,Microsoft.Compute/HighCostGet30Min;120"},
+			}},
+			expected: "microsoft.compute/highcostget3min",
+		},
+		{
+			desc: "subscription quota exhausted",
+			resp: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{
+				"X-Ms-Ratelimit-Remaining-Subscription-Reads": []string{"0"},
+			}},
+			expected: "subscription-reads",
+		},
+		{
+			desc:     "unknown quota exhausted",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+			expected: "unknown",
+		},
+	} {
+		assert.Equal(t, tc.expected, throttleReasonOf(tc.resp, tc.err), tc.desc)
+	}
+}
+
+type fakeTransport struct {
+	header http.Header
+}
+
+func (t fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     t.header,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestPolicyObservesRemainingQuotas(t *testing.T) {
+	pl := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerRetryPolicies: []policy.Policy{NewPolicy()},
+		Transport: fakeTransport{header: http.Header{
+			"X-Ms-Ratelimit-Remaining-Subscription-Reads": []string{"11999"},
+			"X-Ms-Ratelimit-Remaining-Resource":           []string{"Microsoft.Compute/HighCostGet3Min;107,Microsoft.Compute/HighCostGet30Min;587"},
+		}},
+	})
+	ctx := WithCaller(context.Background(), "service")
+	req, err := runtime.NewRequest(ctx, http.MethodGet, "https://management.azure.com/subscriptions/quota-sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = pl.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for quota, expected := range map[string]int64{
+		"subscription-reads":                 11999,
+		"microsoft.compute/highcostget3min":  107,
+		"microsoft.compute/highcostget30min": 587,
+	} {
+		v, ok := remainingQuotas.Load(remainingQuotaKey{subscriptionID: "quota-sub", quota: quota})
+		if !assert.True(t, ok, quota) {
+			continue
+		}
+		assert.Equal(t, expected, v.(remainingQuota).remaining, quota)
+		assert.WithinDuration(t, time.Now(), v.(remainingQuota).observedAt, time.Minute)
+	}
+}
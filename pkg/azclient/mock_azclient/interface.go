@@ -58,6 +58,7 @@ import (
 	routetableclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/routetableclient"
 	secretclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/secretclient"
 	securitygroupclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient"
+	securityruleclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
 	snapshotclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/snapshotclient"
 	sshpublickeyresourceclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/sshpublickeyresourceclient"
 	subnetclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/subnetclient"
@@ -575,6 +576,20 @@ func (mr *MockClientFactoryMockRecorder) GetSecurityGroupClient() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupClient", reflect.TypeOf((*MockClientFactory)(nil).GetSecurityGroupClient))
 }
 
+// GetSecurityRuleClient mocks base method.
+func (m *MockClientFactory) GetSecurityRuleClient() securityruleclient.Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecurityRuleClient")
+	ret0, _ := ret[0].(securityruleclient.Interface)
+	return ret0
+}
+
+// GetSecurityRuleClient indicates an expected call of GetSecurityRuleClient.
+func (mr *MockClientFactoryMockRecorder) GetSecurityRuleClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityRuleClient", reflect.TypeOf((*MockClientFactory)(nil).GetSecurityRuleClient))
+}
+
 // GetSnapshotClient mocks base method.
 func (m *MockClientFactory) GetSnapshotClient() snapshotclient.Interface {
 	m.ctrl.T.Helper()
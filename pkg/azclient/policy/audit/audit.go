@@ -0,0 +1,426 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit implements a policy which records the mutating ARM calls, i.e. who changed what
+// resource when, a summary of the changes and the outcome, to the audit sinks, so the changes made
+// by the clients can be reconstructed after an incident.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
+)
+
+const (
+	// OutcomeSucceeded is the outcome of the calls with successful responses.
+	OutcomeSucceeded = "succeeded"
+	// OutcomeFailed is the outcome of the calls with error responses or without responses.
+	OutcomeFailed = "failed"
+	// OutcomeUnknown is the outcome of the long running operations which were not polled until
+	// they finished.
+	OutcomeUnknown = "unknown"
+
+	// DefaultMaxTrackedResources is the default number of resources whose last known states are
+	// kept to summarize the changes.
+	DefaultMaxTrackedResources = 10000
+
+	headerCorrelationRequestID = "X-Ms-Correlation-Request-Id"
+	headerAzureAsyncOperation  = "Azure-Asyncoperation"
+	headerLocation             = "Location"
+)
+
+// Record is the audit record of a mutating ARM call.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Caller is the controller which made the call, if it is known.
+	Caller     string `json:"caller,omitempty"`
+	Method     string `json:"method"`
+	ResourceID string `json:"resourceID"`
+	// Diff summarizes the fields of the resource changed by the call.
+	Diff          *Diff         `json:"diff,omitempty"`
+	CorrelationID string        `json:"correlationID,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	Outcome       string        `json:"outcome"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Diff summarizes the changes of the top level fields and the properties of a resource, e.g.
+// properties.backendAddressPools. The changes are compared to the state of the resource last
+// read or written through the policy, all the fields written are reported as changed if it is
+// not known.
+type Diff struct {
+	PreviousStateKnown bool     `json:"previousStateKnown"`
+	Added              []string `json:"added,omitempty"`
+	Changed            []string `json:"changed,omitempty"`
+	Removed            []string `json:"removed,omitempty"`
+}
+
+// Sink is where the audit records are sent to.
+type Sink interface {
+	Record(record Record)
+}
+
+// Policy records the mutating ARM calls to the sink. It should be a per-call policy, so a call is
+// recorded once with the outcome after the retries. The calls accepted as long running operations
+// are recorded once the polling of the operations finishes. The GET responses are tracked to
+// summarize the changes of the following writes.
+type Policy struct {
+	sink Sink
+
+	lock         sync.Mutex
+	states       map[string]map[string]string
+	operations   map[string]*operation
+	maxResources int
+	now          func() time.Time
+}
+
+// operation is a long running operation accepted by ARM, whose record waits for the outcome.
+type operation struct {
+	record     Record
+	resourceID string
+	written    map[string]string
+	// statusInBody is true if the outcome is the status in the body of the polled operation rather
+	// than the status code.
+	statusInBody bool
+}
+
+// NewPolicy returns the policy recording the mutating ARM calls to the sink.
+func NewPolicy(sink Sink) *Policy {
+	return &Policy{
+		sink:         sink,
+		states:       make(map[string]map[string]string),
+		operations:   make(map[string]*operation),
+		maxResources: DefaultMaxTrackedResources,
+		now:          time.Now,
+	}
+}
+
+func (p *Policy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	resourceID := strings.ToLower(raw.URL.Path)
+	if raw.Method == http.MethodGet || raw.Method == http.MethodHead {
+		resp, err := req.Next()
+		if raw.Method == http.MethodGet && p.pollOperation(raw.URL.String(), resp, err) {
+			return resp, err
+		}
+		if err == nil && raw.Method == http.MethodGet && resp.StatusCode == http.StatusOK {
+			if body, ok := readBody(resp); ok {
+				p.setState(resourceID, fieldHashes(body))
+			}
+		}
+		return resp, err
+	}
+
+	var written map[string]string
+	if body := req.Body(); body != nil {
+		data, err := io.ReadAll(body)
+		if rewindErr := req.RewindBody(); err == nil && rewindErr == nil {
+			written = fieldHashes(data)
+		}
+	}
+
+	startedAt := p.now()
+	resp, err := req.Next()
+	record := Record{
+		Time:       startedAt,
+		Method:     raw.Method,
+		ResourceID: raw.URL.Path,
+		Outcome:    OutcomeSucceeded,
+		Duration:   p.now().Sub(startedAt),
+	}
+	if caller, ok := metrics.CallerFromContext(raw.Context()); ok {
+		record.Caller = caller
+	}
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+		record.CorrelationID = resp.Header.Get(headerCorrelationRequestID)
+	}
+	switch {
+	case err != nil:
+		record.Outcome = OutcomeFailed
+		record.Error = err.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		record.Outcome = OutcomeFailed
+	}
+	if written != nil {
+		record.Diff = p.diff(resourceID, raw.Method, written)
+	}
+
+	if record.Outcome == OutcomeSucceeded {
+		if pollingURL, statusInBody := operationPollingURL(resp); pollingURL != "" {
+			// the outcome is known once the operation is polled until it finishes
+			p.addOperation(pollingURL, &operation{
+				record:       record,
+				resourceID:   resourceID,
+				written:      written,
+				statusInBody: statusInBody,
+			})
+			return resp, err
+		}
+	}
+	p.finish(record, resourceID, written)
+	return resp, err
+}
+
+// finish records the call, and tracks the state of the resource written by it.
+func (p *Policy) finish(record Record, resourceID string, written map[string]string) {
+	if record.Outcome == OutcomeSucceeded {
+		switch record.Method {
+		case http.MethodDelete:
+			p.setState(resourceID, nil)
+		case http.MethodPut:
+			p.setState(resourceID, written)
+		default:
+			// the resource is patched or an action is posted, its state is unknown
+			p.setState(resourceID, nil)
+		}
+	}
+	p.sink.Record(record)
+}
+
+// operationPollingURL returns the URL polled for the outcome of the long running operation accepted
+// by the response, or "" if the call is not a long running operation.
+func operationPollingURL(resp *http.Response) (string, bool) {
+	if pollingURL := resp.Header.Get(headerAzureAsyncOperation); pollingURL != "" {
+		return normalizeURL(pollingURL), true
+	}
+	if pollingURL := resp.Header.Get(headerLocation); pollingURL != "" && resp.StatusCode == http.StatusAccepted {
+		return normalizeURL(pollingURL), false
+	}
+	return "", false
+}
+
+// normalizeURL encodes the URL as the requests to it do, so the polled URLs match the headers.
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.String()
+}
+
+// addOperation tracks the long running operation by its polling URL. An arbitrary operation is
+// recorded with the unknown outcome if there are too many, e.g. when the callers stopped polling.
+func (p *Policy) addOperation(pollingURL string, op *operation) {
+	p.lock.Lock()
+	var evicted *operation
+	if _, found := p.operations[pollingURL]; !found && len(p.operations) >= p.maxResources {
+		for key, o := range p.operations {
+			evicted = o
+			delete(p.operations, key)
+			break
+		}
+	}
+	p.operations[pollingURL] = op
+	p.lock.Unlock()
+
+	if evicted != nil {
+		evicted.record.Outcome = OutcomeUnknown
+		p.finish(evicted.record, evicted.resourceID, evicted.written)
+	}
+}
+
+// pollOperation records the long running operation polled by the GET once it finishes. It returns
+// false if the GET does not poll a tracked operation.
+func (p *Policy) pollOperation(pollingURL string, resp *http.Response, err error) bool {
+	p.lock.Lock()
+	op, found := p.operations[pollingURL]
+	p.lock.Unlock()
+	if !found {
+		return false
+	}
+
+	outcome, errMessage, done := operationOutcome(op.statusInBody, resp, err)
+	if !done {
+		return true
+	}
+	p.lock.Lock()
+	if p.operations[pollingURL] != op {
+		// another poll of the operation has recorded it
+		p.lock.Unlock()
+		return true
+	}
+	delete(p.operations, pollingURL)
+	p.lock.Unlock()
+
+	record := op.record
+	record.Outcome = outcome
+	record.Error = errMessage
+	record.Duration = p.now().Sub(record.Time)
+	p.finish(record, op.resourceID, op.written)
+	return true
+}
+
+// operationOutcome returns the outcome of the long running operation from the response of the poll,
+// and whether the operation has finished.
+func operationOutcome(statusInBody bool, resp *http.Response, err error) (string, string, bool) {
+	switch {
+	case err != nil:
+		return OutcomeFailed, err.Error(), true
+	case resp.StatusCode >= http.StatusBadRequest:
+		return OutcomeFailed, "", true
+	case !statusInBody && resp.StatusCode == http.StatusAccepted:
+		return "", "", false
+	case !statusInBody:
+		return OutcomeSucceeded, "", true
+	}
+
+	var status struct {
+		Status string `json:"status"`
+		Error  *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	body, ok := readBody(resp)
+	if !ok || json.Unmarshal(body, &status) != nil {
+		return "", "", false
+	}
+	switch strings.ToLower(status.Status) {
+	case "succeeded":
+		return OutcomeSucceeded, "", true
+	case "failed", "canceled", "cancelled":
+		errMessage := "the operation is " + strings.ToLower(status.Status)
+		if status.Error != nil {
+			errMessage = status.Error.Code + ": " + status.Error.Message
+		}
+		return OutcomeFailed, errMessage, true
+	default:
+		return "", "", false
+	}
+}
+
+// diff summarizes the changes of the written fields. The fields not written by a PATCH or a POST
+// are left unchanged, so they are not reported as removed.
+func (p *Policy) diff(resourceID, method string, written map[string]string) *Diff {
+	p.lock.Lock()
+	previous, known := p.states[resourceID]
+	p.lock.Unlock()
+
+	diff := &Diff{PreviousStateKnown: known}
+	for field, hash := range written {
+		previousHash, found := previous[field]
+		switch {
+		case !known:
+			diff.Changed = append(diff.Changed, field)
+		case !found:
+			diff.Added = append(diff.Added, field)
+		case previousHash != hash:
+			diff.Changed = append(diff.Changed, field)
+		}
+	}
+	if known && method == http.MethodPut {
+		for field := range previous {
+			if _, found := written[field]; !found {
+				diff.Removed = append(diff.Removed, field)
+			}
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// setState sets the last known state of the resource, or forgets it if fields is nil.
+func (p *Policy) setState(resourceID string, fields map[string]string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if fields == nil {
+		delete(p.states, resourceID)
+		return
+	}
+	if _, found := p.states[resourceID]; !found && len(p.states) >= p.maxResources {
+		// evict an arbitrary resource, its next change is reported without the previous state
+		for evicted := range p.states {
+			delete(p.states, evicted)
+			break
+		}
+	}
+	p.states[resourceID] = fields
+}
+
+func readBody(resp *http.Response) ([]byte, bool) {
+	if resp.Body == nil {
+		return nil, false
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err == nil
+}
+
+// readOnlyFields are the fields which are set by ARM rather than written by the clients.
+var readOnlyFields = map[string]bool{
+	"id":                           true,
+	"name":                         true,
+	"type":                         true,
+	"etag":                         true,
+	"properties.provisioningState": true,
+	"properties.resourceGuid":      true,
+}
+
+// fieldHashes returns the hashes of the top level fields and the properties of the resource.
+func fieldHashes(body []byte) map[string]string {
+	var resource map[string]json.RawMessage
+	if err := json.Unmarshal(body, &resource); err != nil {
+		return nil
+	}
+	fields := make(map[string]string)
+	add := func(field string, value json.RawMessage) {
+		if readOnlyFields[field] {
+			return
+		}
+		// the value is decoded and encoded again so the keys of the objects are sorted
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return
+		}
+		data, _ := json.Marshal(v)
+		hash := sha256.Sum256(data)
+		fields[field] = hex.EncodeToString(hash[:])
+	}
+	for field, value := range resource {
+		if field != "properties" {
+			add(field, value)
+			continue
+		}
+		var properties map[string]json.RawMessage
+		if err := json.Unmarshal(value, &properties); err != nil {
+			add(field, value)
+			continue
+		}
+		for property, v := range properties {
+			add("properties."+property, v)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
+)
+
+const testResourceURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb?api-version=2024-05-01"
+
+type recordingSink struct {
+	lock    sync.Mutex
+	records []Record
+}
+
+func (s *recordingSink) Record(record Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, record)
+}
+
+// fakeTransport responds with the body of the request, or the resource for the GETs.
+type fakeTransport struct {
+	resource   string
+	statusCode int
+}
+
+func (t fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	body := t.resource
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	statusCode := t.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{headerCorrelationRequestID: []string{"correlation"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func doRequest(ctx context.Context, t *testing.T, pl runtime.Pipeline, method, body string) *http.Response {
+	req, err := runtime.NewRequest(ctx, method, testResourceURL)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	if body != "" {
+		assert.NoError(t, req.SetBody(streaming.NopCloser(strings.NewReader(body)), "application/json"))
+	}
+	resp, err := pl.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestPolicy(t *testing.T) {
+	sink := &recordingSink{}
+	auditPolicy := NewPolicy(sink)
+	newPipeline := func(transport fakeTransport) runtime.Pipeline {
+		return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			PerCallPolicies: []policy.Policy{auditPolicy},
+			Transport:       transport,
+			Retry:           policy.RetryOptions{MaxRetries: -1},
+		})
+	}
+	ctx := metrics.WithCaller(context.Background(), "service")
+
+	// the change is not known to be an addition before the resource is read
+	pl := newPipeline(fakeTransport{})
+	doRequest(ctx, t, pl, http.MethodPut, `{"name":"lb","tags":{"a":"b"},"properties":{"provisioningState":"Updating","frontendIPConfigurations":[]}}`)
+	if !assert.Len(t, sink.records, 1) {
+		return
+	}
+	record := sink.records[0]
+	assert.Equal(t, "service", record.Caller)
+	assert.Equal(t, http.MethodPut, record.Method)
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb", record.ResourceID)
+	assert.Equal(t, "correlation", record.CorrelationID)
+	assert.Equal(t, http.StatusOK, record.StatusCode)
+	assert.Equal(t, OutcomeSucceeded, record.Outcome)
+	assert.Equal(t, &Diff{Changed: []string{"properties.frontendIPConfigurations", "tags"}}, record.Diff)
+
+	// the GETs are not recorded but tracked
+	pl = newPipeline(fakeTransport{resource: `{"name":"lb","tags":{"a":"b"},"properties":{"frontendIPConfigurations":[],"probes":[]}}`})
+	resp := doRequest(ctx, t, pl, http.MethodGet, "")
+	if !assert.NotNil(t, resp) {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "probes")
+	assert.Len(t, sink.records, 1)
+
+	doRequest(ctx, t, pl, http.MethodPut, `{"tags":{"a":"c"},"location":"eastus","properties":{"frontendIPConfigurations":[]}}`)
+	if !assert.Len(t, sink.records, 2) {
+		return
+	}
+	assert.Equal(t, &Diff{
+		PreviousStateKnown: true,
+		Added:              []string{"location"},
+		Changed:            []string{"tags"},
+		Removed:            []string{"properties.probes"},
+	}, sink.records[1].Diff)
+
+	// the fields not written by a PATCH are not removed
+	doRequest(ctx, t, pl, http.MethodPatch, `{"tags":{"a":"d"}}`)
+	if !assert.Len(t, sink.records, 3) {
+		return
+	}
+	assert.Equal(t, &Diff{PreviousStateKnown: true, Changed: []string{"tags"}}, sink.records[2].Diff)
+
+	// the failed calls are recorded too
+	pl = newPipeline(fakeTransport{statusCode: http.StatusConflict})
+	doRequest(ctx, t, pl, http.MethodDelete, "")
+	if !assert.Len(t, sink.records, 4) {
+		return
+	}
+	record = sink.records[3]
+	assert.Equal(t, http.MethodDelete, record.Method)
+	assert.Nil(t, record.Diff)
+	assert.Equal(t, http.StatusConflict, record.StatusCode)
+	assert.Equal(t, OutcomeFailed, record.Outcome)
+}
+
+// operationTransport accepts the writes as long running operations, and responds to the polls of
+// the operations with the statuses in order.
+type operationTransport struct {
+	lock     sync.Mutex
+	header   string
+	statuses []string
+}
+
+func (t *operationTransport) Do(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+	if req.Method != http.MethodGet {
+		resp.StatusCode = http.StatusAccepted
+		resp.Header.Set(t.header, testOperationURL)
+		return resp, nil
+	}
+	status := t.statuses[0]
+	t.statuses = t.statuses[1:]
+	if t.header == headerLocation {
+		if status == "InProgress" {
+			resp.StatusCode = http.StatusAccepted
+		}
+		return resp, nil
+	}
+	resp.Body = io.NopCloser(strings.NewReader(status))
+	return resp, nil
+}
+
+const testOperationURL = "https://management.azure.com/subscriptions/sub/providers/Microsoft.Network/locations/eastus/operations/op?api-version=2024-05-01"
+
+func TestPolicyRecordsLongRunningOperations(t *testing.T) {
+	sink := &recordingSink{}
+	auditPolicy := NewPolicy(sink)
+	newPipeline := func(transport *operationTransport) runtime.Pipeline {
+		return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			PerCallPolicies: []policy.Policy{auditPolicy},
+			Transport:       transport,
+			Retry:           policy.RetryOptions{MaxRetries: -1},
+		})
+	}
+	poll := func(pl runtime.Pipeline) {
+		req, err := runtime.NewRequest(context.Background(), http.MethodGet, testOperationURL)
+		if assert.NoError(t, err) {
+			_, err = pl.Do(req)
+			assert.NoError(t, err)
+		}
+	}
+
+	// the failure of the operation is recorded once it is polled until it finishes
+	pl := newPipeline(&operationTransport{
+		header: headerAzureAsyncOperation,
+		statuses: []string{
+			`{"status":"InProgress"}`,
+			`{"status":"Failed","error":{"code":"InternalServerError","message":"boom"}}`,
+		},
+	})
+	doRequest(context.Background(), t, pl, http.MethodPut, `{"tags":{"a":"b"}}`)
+	assert.Empty(t, sink.records)
+	poll(pl)
+	assert.Empty(t, sink.records)
+	poll(pl)
+	if !assert.Len(t, sink.records, 1) {
+		return
+	}
+	record := sink.records[0]
+	assert.Equal(t, http.MethodPut, record.Method)
+	assert.Equal(t, http.StatusAccepted, record.StatusCode)
+	assert.Equal(t, OutcomeFailed, record.Outcome)
+	assert.Equal(t, "InternalServerError: boom", record.Error)
+	assert.Empty(t, auditPolicy.operations)
+	assert.Empty(t, auditPolicy.states)
+
+	// the operations polled through the Location header succeed once they are not accepted anymore
+	pl = newPipeline(&operationTransport{header: headerLocation, statuses: []string{"InProgress", "Succeeded"}})
+	doRequest(context.Background(), t, pl, http.MethodDelete, "")
+	poll(pl)
+	assert.Len(t, sink.records, 1)
+	poll(pl)
+	if !assert.Len(t, sink.records, 2) {
+		return
+	}
+	assert.Equal(t, http.MethodDelete, sink.records[1].Method)
+	assert.Equal(t, OutcomeSucceeded, sink.records[1].Outcome)
+
+	// the operations not polled are recorded with the unknown outcome when they are evicted
+	auditPolicy.maxResources = 1
+	auditPolicy.addOperation("a", &operation{record: Record{Method: http.MethodPut, Outcome: OutcomeSucceeded}})
+	auditPolicy.addOperation("b", &operation{record: Record{Method: http.MethodPut, Outcome: OutcomeSucceeded}})
+	if !assert.Len(t, sink.records, 3) {
+		return
+	}
+	assert.Equal(t, OutcomeUnknown, sink.records[2].Outcome)
+	assert.Len(t, auditPolicy.operations, 1)
+}
+
+func TestPolicyEvictsStates(t *testing.T) {
+	p := NewPolicy(&recordingSink{})
+	p.maxResources = 2
+	p.setState("a", map[string]string{})
+	p.setState("b", map[string]string{})
+	p.setState("a", map[string]string{"tags": ""})
+	assert.Len(t, p.states, 2)
+	p.setState("c", map[string]string{})
+	assert.Len(t, p.states, 2)
+	assert.Contains(t, p.states, "c")
+	p.setState("c", nil)
+	assert.Len(t, p.states, 1)
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	sink.Record(Record{Method: http.MethodPut, ResourceID: "a", Outcome: OutcomeSucceeded})
+	sink.Record(Record{Method: http.MethodDelete, ResourceID: "b", Outcome: OutcomeFailed})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	var record Record
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, Record{Method: http.MethodDelete, ResourceID: "b", Outcome: OutcomeFailed}, record)
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan Record, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record Record
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received <- record
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := NewWebhookSink(ctx, server.URL)
+	sink.Record(Record{Method: http.MethodPut, ResourceID: "a", Outcome: OutcomeSucceeded})
+
+	select {
+	case record := <-received:
+		assert.Equal(t, "a", record.ResourceID)
+	case <-time.After(10 * time.Second):
+		t.Error("the record is not posted to the webhook")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultWebhookQueueSize is the default number of records queued for the webhook, the records
+	// are dropped when the queue is full.
+	DefaultWebhookQueueSize = 1000
+	// DefaultWebhookTimeout is the default timeout of the requests to the webhook.
+	DefaultWebhookTimeout = 10 * time.Second
+)
+
+// WriterSink writes the records to the writer as JSON lines.
+type WriterSink struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewWriterSink returns the sink writing the records to the writer as JSON lines.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Record implements Sink.
+func (s *WriterSink) Record(record Record) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		klog.Errorf("audit: failed to marshal the record of %s %s: %v", record.Method, record.ResourceID, err)
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		klog.Errorf("audit: failed to write the record of %s %s: %v", record.Method, record.ResourceID, err)
+	}
+}
+
+// WebhookSink posts the records to the webhook as JSON in the background, so the ARM calls are not
+// blocked by the webhook. The records are dropped if the webhook falls behind.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan Record
+}
+
+// NewWebhookSink returns the sink posting the records to the webhook until the context is done.
+func NewWebhookSink(ctx context.Context, url string) *WebhookSink {
+	s := &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: DefaultWebhookTimeout},
+		queue:  make(chan Record, DefaultWebhookQueueSize),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Record implements Sink.
+func (s *WebhookSink) Record(record Record) {
+	select {
+	case s.queue <- record:
+	default:
+		klog.Warningf("audit: dropping the record of %s %s as the webhook falls behind", record.Method, record.ResourceID)
+	}
+}
+
+func (s *WebhookSink) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record := <-s.queue:
+			if err := s.post(ctx, record); err != nil {
+				klog.Errorf("audit: failed to post the record of %s %s to the webhook: %v", record.Method, record.ResourceID, err)
+			}
+		}
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// MultiSink sends the records to all the sinks.
+type MultiSink []Sink
+
+// Record implements Sink.
+func (s MultiSink) Record(record Record) {
+	for _, sink := range s {
+		sink.Record(record)
+	}
+}
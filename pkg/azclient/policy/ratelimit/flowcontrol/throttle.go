@@ -44,6 +44,14 @@ type RateLimiter interface {
 	Wait(ctx context.Context) error
 }
 
+// TokenBucket is implemented by the token bucket rate limiters to report the tokens left in the bucket.
+type TokenBucket interface {
+	// Tokens returns the number of tokens available now
+	Tokens() float64
+	// Burst returns the size of the bucket
+	Burst() int
+}
+
 type tokenBucketPassiveRateLimiter struct {
 	limiter *rate.Limiter
 	qps     float32
@@ -117,6 +125,14 @@ func (tbprl *tokenBucketPassiveRateLimiter) QPS() float32 {
 	return tbprl.qps
 }
 
+func (tbprl *tokenBucketPassiveRateLimiter) Tokens() float64 {
+	return tbprl.limiter.TokensAt(tbprl.clock.Now())
+}
+
+func (tbprl *tokenBucketPassiveRateLimiter) Burst() int {
+	return tbprl.limiter.Burst()
+}
+
 func (tbprl *tokenBucketPassiveRateLimiter) TryAccept() bool {
 	return tbprl.limiter.AllowN(tbprl.clock.Now(), 1)
 }
@@ -190,3 +206,4 @@ var (
 )
 
 var _ PassiveRateLimiter = (*tokenBucketPassiveRateLimiter)(nil)
+var _ TokenBucket = (*tokenBucketPassiveRateLimiter)(nil)
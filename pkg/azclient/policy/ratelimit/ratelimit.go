@@ -19,6 +19,9 @@ package ratelimit
 import (
 	"errors"
 	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 
@@ -43,35 +46,110 @@ var (
 	ErrRateLimitReached = errors.New("rate limit reached")
 )
 
+// policies are the dynamic rate limit policies, whose saturation is reported by the metrics.
+var policies sync.Map
+
 func NewRateLimitPolicy(config *Config) policy.Policy {
-	if config != nil && config.CloudProviderRateLimit {
-		readLimiter := flowcontrol.NewTokenBucketRateLimiter(
-			config.CloudProviderRateLimitQPS,
-			config.CloudProviderRateLimitBucket)
+	limiters := newLimiters(config)
+	if limiters == nil {
+		return nil
+	}
+	p := &Policy{}
+	p.limiters.Store(limiters)
+	return p
+}
+
+// NewDynamicRateLimitPolicy returns the rate limit policy of the client which can be retuned with Update.
+// It doesn't limit the requests until a config enabling the rate limiting is applied.
+func NewDynamicRateLimitPolicy(clientName string, config *Config) *Policy {
+	p := &Policy{clientName: clientName}
+	p.Update(config)
+	policies.Store(p, struct{}{})
+	return p
+}
 
-		writeLimiter := flowcontrol.NewTokenBucketRateLimiter(
+// RangeDynamicPolicies calls fn for each dynamic rate limit policy.
+func RangeDynamicPolicies(fn func(p *Policy)) {
+	policies.Range(func(key, _ interface{}) bool {
+		fn(key.(*Policy))
+		return true
+	})
+}
+
+type limiters struct {
+	reader flowcontrol.RateLimiter
+	writer flowcontrol.RateLimiter
+}
+
+func newLimiters(config *Config) *limiters {
+	if config == nil || !config.CloudProviderRateLimit {
+		return nil
+	}
+	return &limiters{
+		reader: flowcontrol.NewTokenBucketRateLimiter(
+			config.CloudProviderRateLimitQPS,
+			config.CloudProviderRateLimitBucket),
+		writer: flowcontrol.NewTokenBucketRateLimiter(
 			config.CloudProviderRateLimitQPSWrite,
-			config.CloudProviderRateLimitBucketWrite)
-		return &Policy{
-			rateLimiterReader: readLimiter,
-			rateLimiterWriter: writeLimiter,
-		}
+			config.CloudProviderRateLimitBucketWrite),
 	}
-	return nil
 }
 
 type Policy struct {
-	rateLimiterWriter flowcontrol.RateLimiter
-	rateLimiterReader flowcontrol.RateLimiter
+	clientName string
+	limiters   atomic.Pointer[limiters]
+}
+
+// ClientName returns the name of the client the policy is created for.
+func (f *Policy) ClientName() string {
+	return f.clientName
+}
+
+// Update replaces the rate limiters of the policy with the ones of the config, the requests are not
+// limited if the rate limiting is disabled in the config.
+func (f *Policy) Update(config *Config) {
+	f.limiters.Store(newLimiters(config))
 }
 
-func (f Policy) Do(req *policy.Request) (*http.Response, error) {
+// Saturation returns the fraction of the bucket of the read and the write rate limiters in use.
+// ok is false if the rate limiting is disabled.
+func (f *Policy) Saturation() (read, write float64, ok bool) {
+	limiters := f.limiters.Load()
+	if limiters == nil {
+		return 0, 0, false
+	}
+	return saturation(limiters.reader), saturation(limiters.writer), true
+}
+
+func saturation(limiter flowcontrol.RateLimiter) float64 {
+	bucket, ok := limiter.(flowcontrol.TokenBucket)
+	if !ok {
+		return 0
+	}
+	if bucket.Burst() <= 0 {
+		return 1
+	}
+	used := 1 - bucket.Tokens()/float64(bucket.Burst())
+	if used < 0 {
+		return 0
+	}
+	if used > 1 {
+		return 1
+	}
+	return used
+}
+
+func (f *Policy) Do(req *policy.Request) (*http.Response, error) {
+	limiters := f.limiters.Load()
+	if limiters == nil {
+		return req.Next()
+	}
 	if req.Raw().Method == http.MethodGet || req.Raw().Method == http.MethodHead {
-		if !f.rateLimiterReader.TryAccept() {
+		if !limiters.reader.TryAccept() {
 			return nil, ErrRateLimitReached
 		}
 	} else {
-		if !f.rateLimiterWriter.TryAccept() {
+		if !limiters.writer.TryAccept() {
 			return nil, ErrRateLimitReached
 		}
 	}
@@ -82,9 +160,25 @@ func (f Policy) Do(req *policy.Request) (*http.Response, error) {
 type CloudProviderRateLimitConfig struct {
 	// The default rate limit config options.
 	Config
-
 	// Rate limit config for each clients. Values would override default settings above.
 	Entries map[string]*Config `json:",inline" yaml:",inline"`
+
+	// Rate limit config of the route table client.
+	RouteTableRateLimit *Config `json:"routeTableRateLimit,omitempty" yaml:"routeTableRateLimit,omitempty"`
+	// Rate limit config of the subnet client.
+	SubnetsRateLimit *Config `json:"subnetsRateLimit,omitempty" yaml:"subnetsRateLimit,omitempty"`
+	// Rate limit config of the network interface client.
+	InterfaceRateLimit *Config `json:"interfaceRateLimit,omitempty" yaml:"interfaceRateLimit,omitempty"`
+	// Rate limit config of the load balancer client.
+	LoadBalancerRateLimit *Config `json:"loadBalancerRateLimit,omitempty" yaml:"loadBalancerRateLimit,omitempty"`
+	// Rate limit config of the public IP address client.
+	PublicIPAddressRateLimit *Config `json:"publicIPAddressRateLimit,omitempty" yaml:"publicIPAddressRateLimit,omitempty"`
+	// Rate limit config of the network security group client.
+	SecurityGroupRateLimit *Config `json:"securityGroupRateLimit,omitempty" yaml:"securityGroupRateLimit,omitempty"`
+	// Rate limit config of the virtual machine client.
+	VirtualMachineRateLimit *Config `json:"virtualMachineRateLimit,omitempty" yaml:"virtualMachineRateLimit,omitempty"`
+	// Rate limit config of the virtual machine scale set client.
+	VirtualMachineScaleSetRateLimit *Config `json:"virtualMachineScaleSetRateLimit,omitempty" yaml:"virtualMachineScaleSetRateLimit,omitempty"`
 }
 
 func NewCloudProviderRateLimitConfig() *CloudProviderRateLimitConfig {
@@ -97,9 +191,58 @@ func NewCloudProviderRateLimitConfig() *CloudProviderRateLimitConfig {
 }
 
 // GetRateLimitConfig returns the rate limit config for the given client. if the client is not found, the default is returned.
+// The QPS and the bucket size not set for an enabled client are inherited from the default.
 func (config *CloudProviderRateLimitConfig) GetRateLimitConfig(clientName string) *Config {
-	if entry, ok := config.Entries[clientName]; ok {
+	entry, ok := config.Entries[clientName]
+	if !ok {
+		entry = config.clientConfigs()[clientName]
+	}
+	if entry == nil {
+		return &config.Config
+	}
+	if !entry.CloudProviderRateLimit {
 		return entry
 	}
-	return &config.Config
+	merged := *entry
+	if merged.CloudProviderRateLimitQPS == 0 {
+		merged.CloudProviderRateLimitQPS = config.CloudProviderRateLimitQPS
+	}
+	if merged.CloudProviderRateLimitBucket == 0 {
+		merged.CloudProviderRateLimitBucket = config.CloudProviderRateLimitBucket
+	}
+	if merged.CloudProviderRateLimitQPSWrite == 0 {
+		merged.CloudProviderRateLimitQPSWrite = config.CloudProviderRateLimitQPSWrite
+	}
+	if merged.CloudProviderRateLimitBucketWrite == 0 {
+		merged.CloudProviderRateLimitBucketWrite = config.CloudProviderRateLimitBucketWrite
+	}
+	return &merged
+}
+
+// ClientNames returns the sorted names of the clients with their own rate limit config.
+func (config *CloudProviderRateLimitConfig) ClientNames() []string {
+	names := make([]string, 0, len(config.Entries))
+	for name := range config.Entries {
+		names = append(names, name)
+	}
+	for name, entry := range config.clientConfigs() {
+		if _, ok := config.Entries[name]; !ok && entry != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (config *CloudProviderRateLimitConfig) clientConfigs() map[string]*Config {
+	return map[string]*Config{
+		"routeTableRateLimit":             config.RouteTableRateLimit,
+		"subnetsRateLimit":                config.SubnetsRateLimit,
+		"interfaceRateLimit":              config.InterfaceRateLimit,
+		"loadBalancerRateLimit":           config.LoadBalancerRateLimit,
+		"publicIPAddressRateLimit":        config.PublicIPAddressRateLimit,
+		"securityGroupRateLimit":          config.SecurityGroupRateLimit,
+		"virtualMachineRateLimit":         config.VirtualMachineRateLimit,
+		"virtualMachineScaleSetRateLimit": config.VirtualMachineScaleSetRateLimit,
+	}
 }
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+func newPipeline(rateLimitPolicy policy.Policy) runtime.Pipeline {
+	return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{
+			rateLimitPolicy,
+			utils.FuncPolicyWrapper(
+				func(_ *policy.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       http.NoBody,
+					}, nil
+				},
+			),
+		},
+	})
+}
+
+func doRequest(t *testing.T, pipeline runtime.Pipeline, method string) error {
+	req, err := runtime.NewRequest(context.Background(), method, "https://management.microsoft.com")
+	assert.NoError(t, err)
+	_, err = pipeline.Do(req)
+	return err
+}
+
+func TestDynamicRateLimitPolicy(t *testing.T) {
+	t.Run("should not limit the requests until the rate limiting is enabled", func(t *testing.T) {
+		p := ratelimit.NewDynamicRateLimitPolicy("loadBalancerRateLimit", &ratelimit.Config{})
+		pipeline := newPipeline(p)
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, doRequest(t, pipeline, http.MethodGet))
+		}
+		_, _, ok := p.Saturation()
+		assert.False(t, ok)
+
+		p.Update(&ratelimit.Config{
+			CloudProviderRateLimit:            true,
+			CloudProviderRateLimitQPS:         0.001,
+			CloudProviderRateLimitBucket:      1,
+			CloudProviderRateLimitQPSWrite:    0.001,
+			CloudProviderRateLimitBucketWrite: 2,
+		})
+		assert.NoError(t, doRequest(t, pipeline, http.MethodGet))
+		assert.True(t, errors.Is(doRequest(t, pipeline, http.MethodGet), ratelimit.ErrRateLimitReached))
+		assert.NoError(t, doRequest(t, pipeline, http.MethodPut))
+
+		read, write, ok := p.Saturation()
+		assert.True(t, ok)
+		assert.InDelta(t, 1, read, 0.01)
+		assert.InDelta(t, 0.5, write, 0.01)
+
+		p.Update(&ratelimit.Config{})
+		assert.NoError(t, doRequest(t, pipeline, http.MethodGet))
+	})
+
+	t.Run("should be reported to the metrics", func(t *testing.T) {
+		p := ratelimit.NewDynamicRateLimitPolicy("routeTableRateLimit", &ratelimit.Config{})
+		found := false
+		ratelimit.RangeDynamicPolicies(func(policy *ratelimit.Policy) {
+			found = found || policy == p
+		})
+		assert.True(t, found)
+	})
+}
+
+func TestGetRateLimitConfig(t *testing.T) {
+	config := ratelimit.CloudProviderRateLimitConfig{
+		Config: ratelimit.Config{
+			CloudProviderRateLimit:            true,
+			CloudProviderRateLimitQPS:         10,
+			CloudProviderRateLimitBucket:      100,
+			CloudProviderRateLimitQPSWrite:    1,
+			CloudProviderRateLimitBucketWrite: 10,
+		},
+		Entries: map[string]*ratelimit.Config{
+			"diskRateLimit": {CloudProviderRateLimit: false},
+		},
+		LoadBalancerRateLimit: &ratelimit.Config{
+			CloudProviderRateLimit:    true,
+			CloudProviderRateLimitQPS: 50,
+		},
+	}
+
+	assert.Equal(t, &config.Config, config.GetRateLimitConfig("routeTableRateLimit"))
+	assert.Equal(t, &ratelimit.Config{CloudProviderRateLimit: false}, config.GetRateLimitConfig("diskRateLimit"))
+	assert.Equal(t, &ratelimit.Config{
+		CloudProviderRateLimit:            true,
+		CloudProviderRateLimitQPS:         50,
+		CloudProviderRateLimitBucket:      100,
+		CloudProviderRateLimitQPSWrite:    1,
+		CloudProviderRateLimitBucketWrite: 10,
+	}, config.GetRateLimitConfig("loadBalancerRateLimit"))
+	assert.Equal(t, float32(50), config.LoadBalancerRateLimit.CloudProviderRateLimitQPS)
+	assert.Equal(t, 0, config.LoadBalancerRateLimit.CloudProviderRateLimitBucket)
+}
+
+func TestClientNames(t *testing.T) {
+	config := ratelimit.CloudProviderRateLimitConfig{
+		Entries: map[string]*ratelimit.Config{
+			"diskRateLimit":       {CloudProviderRateLimit: false},
+			"routeTableRateLimit": {CloudProviderRateLimit: true},
+		},
+		LoadBalancerRateLimit: &ratelimit.Config{CloudProviderRateLimit: true},
+		RouteTableRateLimit:   &ratelimit.Config{CloudProviderRateLimit: false},
+	}
+	assert.Equal(t, []string{"diskRateLimit", "loadBalancerRateLimit", "routeTableRateLimit"}, config.ClientNames())
+	assert.Empty(t, ratelimit.NewCloudProviderRateLimitConfig().ClientNames())
+}
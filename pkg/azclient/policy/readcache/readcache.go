@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readcache implements a policy which coalesces the concurrent identical GETs of the hot resources
+// into a single ARM request, and caches their results shortly for the clients sharing the policy.
+package readcache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
+)
+
+// DefaultResourceTypes are the resource types whose GETs are coalesced and cached by default.
+var DefaultResourceTypes = []string{
+	"loadBalancers",
+	"virtualMachineScaleSets",
+	"networkInterfaces",
+	"subnets",
+}
+
+const (
+	resultHit       = "hit"
+	resultMiss      = "miss"
+	resultCoalesced = "coalesced"
+)
+
+// response is a GET response whose body is read so it can be replayed to every caller.
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	fetchedAt  time.Time
+}
+
+func (r *response) toHTTPResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.statusCode),
+		StatusCode:    r.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// Policy coalesces the concurrent GETs of the same resource into a single request and caches the successful
+// responses for the TTL. The entries of a resource and its parents and children are invalidated by the writes
+// to the resource through the policy, so the clients writing a resource read their own writes.
+type Policy struct {
+	ttl           time.Duration
+	resourceTypes map[string]struct{}
+	group         singleflight.Group
+	now           func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]*response
+	// generation is bumped when a write starts and when it completes, the GETs in flight during
+	// a write don't fill the cache
+	generation uint64
+}
+
+// NewPolicy returns the policy caching the GETs of the resource types for ttl, the GETs are only coalesced if
+// ttl is zero. The DefaultResourceTypes are used if no resource type is given.
+func NewPolicy(ttl time.Duration, resourceTypes ...string) *Policy {
+	if len(resourceTypes) == 0 {
+		resourceTypes = DefaultResourceTypes
+	}
+	p := &Policy{
+		ttl:           ttl,
+		resourceTypes: make(map[string]struct{}, len(resourceTypes)),
+		now:           time.Now,
+		entries:       make(map[string]*response),
+	}
+	for _, resourceType := range resourceTypes {
+		p.resourceTypes[strings.ToLower(resourceType)] = struct{}{}
+	}
+	return p
+}
+
+func (p *Policy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	if raw.Method != http.MethodGet {
+		if raw.Method == http.MethodHead {
+			return req.Next()
+		}
+		// the cache is invalidated again when the write completes, so the GETs sent during the
+		// write don't serve the resource before the write
+		p.Invalidate(raw.URL.Path)
+		defer p.Invalidate(raw.URL.Path)
+		return req.Next()
+	}
+	resourceType, ok := p.resourceType(raw.URL.Path)
+	if !ok {
+		return req.Next()
+	}
+	key := strings.ToLower(raw.URL.Path) + "?" + raw.URL.RawQuery
+
+	p.lock.Lock()
+	if entry, ok := p.entries[key]; ok {
+		if p.now().Sub(entry.fetchedAt) < p.ttl {
+			p.lock.Unlock()
+			observe(raw, resourceType, resultHit)
+			return entry.toHTTPResponse(raw), nil
+		}
+		delete(p.entries, key)
+	}
+	generation := p.generation
+	p.lock.Unlock()
+
+	// the GETs sent after a write don't join the ones sent before it
+	v, err, shared := p.group.Do(key+"#"+strconv.FormatUint(generation, 10), func() (interface{}, error) {
+		resp, err := req.Next()
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		r := &response{
+			statusCode: resp.StatusCode,
+			header:     resp.Header.Clone(),
+			body:       body,
+			fetchedAt:  p.now(),
+		}
+		if resp.StatusCode == http.StatusOK && p.ttl > 0 {
+			p.lock.Lock()
+			if p.generation == generation {
+				p.entries[key] = r
+			}
+			p.lock.Unlock()
+		}
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		observe(raw, resourceType, resultCoalesced)
+	} else {
+		observe(raw, resourceType, resultMiss)
+	}
+	return v.(*response).toHTTPResponse(raw), nil
+}
+
+// Invalidate removes the cached GETs of the resource, its parents and its children.
+func (p *Policy) Invalidate(resourceID string) {
+	resourceID = strings.TrimSuffix(strings.ToLower(resourceID), "/")
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.generation++
+	for key := range p.entries {
+		path, _, _ := strings.Cut(key, "?")
+		if strings.HasPrefix(path, resourceID) || strings.HasPrefix(resourceID, path) {
+			delete(p.entries, key)
+		}
+	}
+}
+
+// resourceType returns the type of the resource if the path is of a single resource of the cached types.
+func (p *Policy) resourceType(path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 {
+		return "", false
+	}
+	resourceType := segments[len(segments)-2]
+	_, ok := p.resourceTypes[strings.ToLower(resourceType)]
+	return resourceType, ok
+}
+
+func observe(req *http.Request, resourceType, result string) {
+	metrics.ARMRequestCacheResults().Add(req.Context(), 1, api.WithAttributes(
+		attribute.String("resource", resourceType),
+		attribute.String("result", result),
+	))
+}
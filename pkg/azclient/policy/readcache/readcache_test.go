@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readcache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+const lbURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+
+func newTestPipeline(p *Policy, calls *atomic.Int32, release <-chan struct{}) runtime.Pipeline {
+	return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{
+			p,
+			utils.FuncPolicyWrapper(
+				func(req *policy.Request) (*http.Response, error) {
+					calls.Add(1)
+					if release != nil {
+						<-release
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{},
+						Body:       io.NopCloser(strings.NewReader(req.Raw().Method)),
+					}, nil
+				},
+			),
+		},
+		Retry: policy.RetryOptions{MaxRetries: -1},
+	})
+}
+
+func doRequest(t *testing.T, pipeline runtime.Pipeline, method, url string) string {
+	req, err := runtime.NewRequest(context.Background(), method, url)
+	assert.NoError(t, err)
+	resp, err := pipeline.Do(req)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestPolicy(t *testing.T) {
+	t.Run("should coalesce the concurrent GETs", func(t *testing.T) {
+		calls := &atomic.Int32{}
+		release := make(chan struct{})
+		p := NewPolicy(0)
+		pipeline := newTestPipeline(p, calls, release)
+
+		wg := sync.WaitGroup{}
+		bodies := make([]string, 5)
+		for i := range bodies {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				bodies[i] = doRequest(t, pipeline, http.MethodGet, lbURL)
+			}(i)
+		}
+		assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+		for _, body := range bodies {
+			assert.Equal(t, http.MethodGet, body)
+		}
+
+		// the results are not cached without TTL
+		before := calls.Load()
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, before+1, calls.Load())
+	})
+
+	t.Run("should cache the GETs until the TTL or the writes", func(t *testing.T) {
+		calls := &atomic.Int32{}
+		now := time.Now()
+		p := NewPolicy(time.Minute)
+		p.now = func() time.Time { return now }
+		pipeline := newTestPipeline(p, calls, nil)
+
+		assert.Equal(t, http.MethodGet, doRequest(t, pipeline, http.MethodGet, lbURL))
+		assert.Equal(t, http.MethodGet, doRequest(t, pipeline, http.MethodGet, lbURL))
+		assert.Equal(t, int32(1), calls.Load())
+
+		// the writes to the children invalidate the parents
+		doRequest(t, pipeline, http.MethodPut, lbURL+"/backendAddressPools/pool")
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, int32(3), calls.Load())
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, int32(3), calls.Load())
+
+		now = now.Add(2 * time.Minute)
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("should not coalesce the GETs sent after a write with the ones sent before it", func(t *testing.T) {
+		calls := &atomic.Int32{}
+		release := make(chan struct{})
+		p := NewPolicy(0)
+		pipeline := newTestPipeline(p, calls, release)
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			doRequest(t, pipeline, http.MethodGet, lbURL)
+		}()
+		assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+		p.Invalidate("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb")
+		go func() {
+			defer wg.Done()
+			doRequest(t, pipeline, http.MethodGet, lbURL)
+		}()
+		assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, 10*time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("should invalidate the GETs sent during a write when it completes", func(t *testing.T) {
+		calls := &atomic.Int32{}
+		p := NewPolicy(time.Minute)
+		writing, release := make(chan struct{}), make(chan struct{})
+		pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			PerCallPolicies: []policy.Policy{
+				p,
+				utils.FuncPolicyWrapper(
+					func(req *policy.Request) (*http.Response, error) {
+						calls.Add(1)
+						if req.Raw().Method == http.MethodPut {
+							close(writing)
+							<-release
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{},
+							Body:       io.NopCloser(strings.NewReader(req.Raw().Method)),
+						}, nil
+					},
+				),
+			},
+			Retry: policy.RetryOptions{MaxRetries: -1},
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			doRequest(t, pipeline, http.MethodPut, lbURL)
+		}()
+		<-writing
+		// the GET sent during the write is cached until the write completes
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, int32(2), calls.Load())
+		close(release)
+		<-done
+
+		doRequest(t, pipeline, http.MethodGet, lbURL)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should not cache the other resources", func(t *testing.T) {
+		calls := &atomic.Int32{}
+		p := NewPolicy(time.Minute)
+		pipeline := newTestPipeline(p, calls, nil)
+
+		doRequest(t, pipeline, http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers")
+		doRequest(t, pipeline, http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers")
+		doRequest(t, pipeline, http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip")
+		doRequest(t, pipeline, http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip")
+		assert.Equal(t, int32(4), calls.Load())
+	})
+}
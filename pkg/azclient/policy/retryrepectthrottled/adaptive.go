@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryrepectthrottled
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultLowWatermark is the remaining quota below which the requests are slowed down.
+	DefaultLowWatermark = 50
+	// DefaultMaxDelay is the delay of the requests when the quota is exhausted.
+	DefaultMaxDelay = 5 * time.Second
+	// DefaultQuotaStaleAfter is how long the remaining quota reported by ARM is trusted,
+	// the quota of ARM is replenished continuously.
+	DefaultQuotaStaleAfter = 30 * time.Second
+
+	headerRemainingPrefix = "X-Ms-Ratelimit-Remaining-"
+)
+
+// AdaptiveThrottlingOptions are the options of the adaptive throttling policy.
+type AdaptiveThrottlingOptions struct {
+	// LowWatermark is the remaining quota below which the requests are slowed down.
+	LowWatermark int
+	// MaxDelay is the delay of the requests when the quota is exhausted.
+	MaxDelay time.Duration
+	// QuotaStaleAfter is how long the remaining quota reported by ARM is trusted.
+	QuotaStaleAfter time.Duration
+}
+
+// quota tracks the remaining ARM quota of the reads, writes or deletes in a subscription or a tenant.
+type quota struct {
+	lock sync.Mutex
+	// remaining is the remaining quota estimated from the last response, minus the requests sent since then
+	remaining  int
+	observedAt time.Time
+}
+
+// throttle tracks the Retry-After of the throttled reads, writes or deletes of a resource provider.
+type throttle struct {
+	lock       sync.Mutex
+	retryAfter time.Time
+}
+
+// quotas holds the quotas by the scope and the operation, and the throttles by the throttling scope and the
+// operation. They are shared by all the clients of the process, so that the requests of all the controllers
+// are slowed down as the quota of the subscription depletes.
+type quotas struct {
+	m         sync.Map
+	throttles sync.Map
+}
+
+func (q *quotas) get(scope, operation string) *quota {
+	v, _ := q.m.LoadOrStore(scope+"/"+operation, &quota{remaining: -1})
+	return v.(*quota)
+}
+
+func (q *quotas) throttle(scope, operation string) *throttle {
+	v, _ := q.throttles.LoadOrStore(scope+"/"+operation, &throttle{})
+	return v.(*throttle)
+}
+
+var sharedQuotas = &quotas{}
+
+// AdaptiveThrottlingPolicy throttles the requests by the ARM quota shared across the clients. It rejects the
+// requests to a resource provider until the Retry-After of a throttled response from the provider, and delays
+// the requests proportionally as the quota reported by the x-ms-ratelimit-remaining-* headers falls below the
+// low watermark.
+type AdaptiveThrottlingPolicy struct {
+	options AdaptiveThrottlingOptions
+	quotas  *quotas
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewAdaptiveThrottlingPolicy returns the adaptive throttling policy, the default options are used if options is nil.
+func NewAdaptiveThrottlingPolicy(options *AdaptiveThrottlingOptions) policy.Policy {
+	p := &AdaptiveThrottlingPolicy{
+		options: AdaptiveThrottlingOptions{
+			LowWatermark:    DefaultLowWatermark,
+			MaxDelay:        DefaultMaxDelay,
+			QuotaStaleAfter: DefaultQuotaStaleAfter,
+		},
+		quotas: sharedQuotas,
+		now:    time.Now,
+		sleep:  sleepWithContext,
+	}
+	if options != nil {
+		p.options = *options
+	}
+	return p
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *AdaptiveThrottlingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	operation := quotaOperation(req.Raw().Method)
+	q := p.quotas.get(quotaScope(req.Raw().URL.Path), operation)
+	// a throttled response only blocks the requests to the same resource provider, the other
+	// providers of the subscription have their own limits
+	t := p.quotas.throttle(throttlingScope(req.Raw().URL.Path), operation)
+
+	delay, err := p.reserve(q, t)
+	if err != nil {
+		return nil, err
+	}
+	if delay > 0 {
+		klog.V(4).Infof("AdaptiveThrottlingPolicy: delaying the %s request to %s by %v as the quota depletes", req.Raw().Method, req.Raw().URL.Path, delay)
+		if err := p.sleep(req.Raw().Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+	p.observe(q, t, operation, resp)
+	if runtime.HasStatusCode(resp, http.StatusTooManyRequests) {
+		return resp, ErrTooManyRequest
+	}
+	return resp, nil
+}
+
+// reserve takes one from the remaining quota and returns how long the request should be delayed.
+func (p *AdaptiveThrottlingPolicy) reserve(q *quota, t *throttle) (time.Duration, error) {
+	now := p.now()
+	t.lock.Lock()
+	retryAfter := t.retryAfter
+	t.lock.Unlock()
+	if retryAfter.After(now) {
+		return 0, ErrTooManyRequest
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.remaining < 0 || now.Sub(q.observedAt) > p.options.QuotaStaleAfter || p.options.LowWatermark <= 0 {
+		return 0, nil
+	}
+	remaining := q.remaining
+	q.remaining--
+	if remaining >= p.options.LowWatermark {
+		return 0, nil
+	}
+	if remaining <= 0 {
+		return p.options.MaxDelay, nil
+	}
+	return p.options.MaxDelay * time.Duration(p.options.LowWatermark-remaining) / time.Duration(p.options.LowWatermark), nil
+}
+
+// observe updates the quota with the remaining quota and the throttle with the Retry-After of the response.
+func (p *AdaptiveThrottlingPolicy) observe(q *quota, t *throttle, operation string, resp *http.Response) {
+	remaining := -1
+	for key, values := range resp.Header {
+		if len(values) == 0 || !strings.HasPrefix(key, headerRemainingPrefix) || !strings.HasSuffix(key, "-"+operation) {
+			continue
+		}
+		if v, err := strconv.Atoi(values[0]); err == nil && (remaining < 0 || v < remaining) {
+			remaining = v
+		}
+	}
+
+	now := p.now()
+	if remaining >= 0 {
+		q.lock.Lock()
+		q.remaining = remaining
+		q.observedAt = now
+		q.lock.Unlock()
+	}
+	if runtime.HasStatusCode(resp, http.StatusTooManyRequests) {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		duration := resp.Header.Get(HeaderRetryAfter)
+		if retryAfter, _ := strconv.Atoi(duration); retryAfter > 0 {
+			t.retryAfter = now.Add(time.Duration(retryAfter) * time.Second)
+		} else if at, err := time.Parse(time.RFC1123, duration); err == nil {
+			t.retryAfter = at
+		}
+	}
+}
+
+// quotaScope returns the subscription of the request, or the tenant for the requests out of subscriptions.
+func quotaScope(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && strings.EqualFold(segments[0], "subscriptions") {
+		return strings.ToLower(segments[1])
+	}
+	return "tenant"
+}
+
+// throttlingScope returns the subscription or the tenant of the request and the resource provider it is sent to,
+// the requests out of the resource providers are sent to Microsoft.Resources.
+func throttlingScope(path string) string {
+	provider := "microsoft.resources"
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			provider = strings.ToLower(segments[i+1])
+			break
+		}
+	}
+	return quotaScope(path) + "/" + provider
+}
+
+// quotaOperation returns the kind of the ARM quota consumed by the request.
+func quotaOperation(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return "Reads"
+	case http.MethodDelete:
+		return "Deletes"
+	default:
+		return "Writes"
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryrepectthrottled
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+type funcPolicy func(*policy.Request) (*http.Response, error)
+
+func (f funcPolicy) Do(req *policy.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = ginkgo.Describe("AdaptiveThrottlingPolicy", func() {
+	var (
+		now       time.Time
+		delays    []time.Duration
+		responses []*http.Response
+		p         *AdaptiveThrottlingPolicy
+		pipeline  runtime.Pipeline
+	)
+
+	newResponse := func(statusCode int, headers map[string]string) *http.Response {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}
+		for key, value := range headers {
+			resp.Header.Set(key, value)
+		}
+		return resp
+	}
+	do := func(method, url string) (*http.Response, error) {
+		req, err := runtime.NewRequest(context.Background(), method, url)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return pipeline.Do(req)
+	}
+
+	ginkgo.BeforeEach(func() {
+		now = time.Now()
+		delays = nil
+		responses = nil
+		p = NewAdaptiveThrottlingPolicy(&AdaptiveThrottlingOptions{
+			LowWatermark:    10,
+			MaxDelay:        10 * time.Second,
+			QuotaStaleAfter: time.Minute,
+		}).(*AdaptiveThrottlingPolicy)
+		p.quotas = &quotas{}
+		p.now = func() time.Time { return now }
+		p.sleep = func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+		pipeline = runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			PerCallPolicies: []policy.Policy{
+				p,
+				funcPolicy(func(*policy.Request) (*http.Response, error) {
+					if len(responses) == 0 {
+						return newResponse(http.StatusOK, nil), nil
+					}
+					resp := responses[0]
+					responses = responses[1:]
+					return resp, nil
+				}),
+			},
+			Retry: policy.RetryOptions{MaxRetries: -1},
+		})
+	})
+
+	ginkgo.It("should not delay the requests with enough quota", func() {
+		responses = append(responses, newResponse(http.StatusOK, map[string]string{
+			"x-ms-ratelimit-remaining-subscription-reads": "100",
+		}))
+		for i := 0; i < 3; i++ {
+			_, err := do(http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+		gomega.Expect(delays).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should delay the requests proportionally as the quota depletes", func() {
+		responses = append(responses, newResponse(http.StatusOK, map[string]string{
+			"x-ms-ratelimit-remaining-subscription-reads":        "20",
+			"x-ms-ratelimit-remaining-subscription-global-reads": "5",
+		}))
+		_, err := do(http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		for i := 0; i < 6; i++ {
+			_, err = do(http.MethodGet, "https://management.azure.com/subscriptions/SUB/resourceGroups/rg"+strconv.Itoa(i))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+		gomega.Expect(delays).To(gomega.Equal([]time.Duration{
+			5 * time.Second, 6 * time.Second, 7 * time.Second, 8 * time.Second, 9 * time.Second, 10 * time.Second,
+		}))
+
+		// the writes and the other subscriptions have their own quota
+		delays = nil
+		_, err = do(http.MethodPut, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		_, err = do(http.MethodGet, "https://management.azure.com/subscriptions/other/resourceGroups/rg")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(delays).To(gomega.BeEmpty())
+
+		// the stale quota is not trusted
+		now = now.Add(2 * time.Minute)
+		_, err = do(http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(delays).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should reject the requests of all clients to the resource provider until retry-after", func() {
+		const lbURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+		responses = append(responses, newResponse(http.StatusTooManyRequests, map[string]string{
+			HeaderRetryAfter: "10",
+		}))
+		_, err := do(http.MethodDelete, lbURL)
+		gomega.Expect(err).To(gomega.MatchError(ErrTooManyRequest))
+
+		other := NewAdaptiveThrottlingPolicy(nil).(*AdaptiveThrottlingPolicy)
+		other.quotas = p.quotas
+		other.now = p.now
+		path := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"
+		q := other.quotas.get(quotaScope(path), quotaOperation(http.MethodDelete))
+		t := other.quotas.throttle(throttlingScope(path), quotaOperation(http.MethodDelete))
+		_, err = other.reserve(q, t)
+		gomega.Expect(err).To(gomega.MatchError(ErrTooManyRequest))
+
+		_, err = do(http.MethodGet, lbURL)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		_, err = do(http.MethodDelete, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/disk")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		_, err = do(http.MethodDelete, "https://management.azure.com/subscriptions/other/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		now = now.Add(11 * time.Second)
+		_, err = do(http.MethodDelete, lbURL)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("should track the tenant quota for the requests out of subscriptions", func() {
+		gomega.Expect(quotaScope("/providers/Microsoft.Network/operations")).To(gomega.Equal("tenant"))
+		gomega.Expect(quotaScope("/subscriptions/SUB")).To(gomega.Equal("sub"))
+		gomega.Expect(quotaOperation(http.MethodPatch)).To(gomega.Equal("Writes"))
+	})
+
+	ginkgo.It("should throttle the requests by the resource provider", func() {
+		gomega.Expect(throttlingScope("/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb")).To(gomega.Equal("sub/microsoft.network"))
+		gomega.Expect(throttlingScope("/subscriptions/sub/resourceGroups/rg")).To(gomega.Equal("sub/microsoft.resources"))
+		gomega.Expect(throttlingScope("/providers/Microsoft.Network/operations")).To(gomega.Equal("tenant/microsoft.network"))
+	})
+})
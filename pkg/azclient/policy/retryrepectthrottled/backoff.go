@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryrepectthrottled
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultResourceBackoffBaseDelay is the delay of the requests to a resource after it is throttled once.
+	DefaultResourceBackoffBaseDelay = time.Second
+	// DefaultResourceBackoffMaxDelay is the max delay of the requests to a throttled resource.
+	DefaultResourceBackoffMaxDelay = 30 * time.Second
+)
+
+// ResourceBackoffOptions are the options of the resource backoff policy.
+type ResourceBackoffOptions struct {
+	// BaseDelay is the delay of the requests to a resource after it is throttled once, which doubles
+	// every time the resource is throttled again.
+	BaseDelay time.Duration
+	// MaxDelay is the max delay of the requests to a throttled resource.
+	MaxDelay time.Duration
+}
+
+// resourceBackoff is the backoff state of the requests of an operation to a resource.
+type resourceBackoff struct {
+	lock sync.Mutex
+	// failures is the number of the consecutive throttled responses
+	failures int
+	until    time.Time
+}
+
+// resourceBackoffs holds the backoff states by the resource and the operation. They are shared by all
+// the clients of the process, so that a throttled response slows down every caller of the resource.
+type resourceBackoffs struct {
+	m sync.Map
+}
+
+func (b *resourceBackoffs) get(key string) *resourceBackoff {
+	v, _ := b.m.LoadOrStore(key, &resourceBackoff{})
+	return v.(*resourceBackoff)
+}
+
+// throttled returns the resources backed off at the time.
+func (b *resourceBackoffs) throttled(now time.Time) []ThrottledResource {
+	var resources []ThrottledResource
+	b.m.Range(func(key, value interface{}) bool {
+		backoff := value.(*resourceBackoff)
+		backoff.lock.Lock()
+		until := backoff.until
+		backoff.lock.Unlock()
+		if until.After(now) {
+			operation, resourceID, _ := strings.Cut(key.(string), " ")
+			resources = append(resources, ThrottledResource{Operation: operation, ResourceID: resourceID, Until: until})
+		}
+		return true
+	})
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].ResourceID != resources[j].ResourceID {
+			return resources[i].ResourceID < resources[j].ResourceID
+		}
+		return resources[i].Operation < resources[j].Operation
+	})
+	return resources
+}
+
+var sharedResourceBackoffs = &resourceBackoffs{}
+
+// ThrottledResource is a resource whose requests of an operation are delayed by the resource backoff.
+type ThrottledResource struct {
+	// Operation is Reads, Writes or Deletes.
+	Operation string
+	// ResourceID is the lower case path of the resource.
+	ResourceID string
+	// Until is when the backoff expires.
+	Until time.Time
+}
+
+// ThrottledResources returns the resources currently backed off by the resource backoff policies of the
+// process, sorted by the resource IDs.
+func ThrottledResources() []ThrottledResource {
+	return sharedResourceBackoffs.throttled(time.Now())
+}
+
+// ResourceBackoffPolicy backs off the requests to the resources exponentially after they are throttled.
+// The requests of an operation to a throttled resource are delayed until the backoff of the resource
+// expires, which is doubled by every throttled response and honors its Retry-After, and is reset by
+// the first response not throttled.
+type ResourceBackoffPolicy struct {
+	options  ResourceBackoffOptions
+	backoffs *resourceBackoffs
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewResourceBackoffPolicy returns the resource backoff policy, the default options are used if options is nil.
+func NewResourceBackoffPolicy(options *ResourceBackoffOptions) policy.Policy {
+	p := &ResourceBackoffPolicy{
+		options: ResourceBackoffOptions{
+			BaseDelay: DefaultResourceBackoffBaseDelay,
+			MaxDelay:  DefaultResourceBackoffMaxDelay,
+		},
+		backoffs: sharedResourceBackoffs,
+		now:      time.Now,
+		sleep:    sleepWithContext,
+	}
+	if options != nil {
+		p.options = *options
+	}
+	return p
+}
+
+func (p *ResourceBackoffPolicy) Do(req *policy.Request) (*http.Response, error) {
+	key := resourceBackoffKey(req.Raw().Method, req.Raw().URL.Path)
+	b := p.backoffs.get(key)
+	if delay := p.delay(b); delay > 0 {
+		klog.V(4).Infof("ResourceBackoffPolicy: delaying the %s request to %s by %v as the resource is throttled", req.Raw().Method, req.Raw().URL.Path, delay)
+		if err := p.sleep(req.Raw().Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+	if runtime.HasStatusCode(resp, http.StatusTooManyRequests) {
+		p.backOff(b, resp)
+	} else {
+		p.reset(key, b)
+	}
+	return resp, nil
+}
+
+// delay returns how long the request should wait for the backoff of the resource.
+func (p *ResourceBackoffPolicy) delay(b *resourceBackoff) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.until.Sub(p.now())
+}
+
+// backOff doubles the backoff of the resource, or extends it to the Retry-After of the response.
+func (p *ResourceBackoffPolicy) backOff(b *resourceBackoff, resp *http.Response) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := p.now()
+	// the failures long before are not consecutive
+	if !b.until.IsZero() && now.Sub(b.until) > p.options.MaxDelay {
+		b.failures = 0
+	}
+	delay := p.options.BaseDelay
+	for i := 0; i < b.failures && delay < p.options.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.options.MaxDelay {
+		delay = p.options.MaxDelay
+	}
+	b.failures++
+
+	until := now.Add(delay)
+	duration := resp.Header.Get(HeaderRetryAfter)
+	if retryAfter, _ := strconv.Atoi(duration); retryAfter > 0 {
+		until = maxTime(until, now.Add(time.Duration(retryAfter)*time.Second))
+	} else if t, err := time.Parse(time.RFC1123, duration); err == nil {
+		until = maxTime(until, t)
+	}
+	b.until = maxTime(b.until, until)
+}
+
+// reset removes the backoff of the resource after a response not throttled.
+func (p *ResourceBackoffPolicy) reset(key string, b *resourceBackoff) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.failures == 0 && b.until.IsZero() {
+		return
+	}
+	b.failures = 0
+	b.until = time.Time{}
+	p.backoffs.m.Delete(key)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// resourceBackoffKey returns the key of the backoff of the operation to the resource of the request.
+func resourceBackoffKey(method, path string) string {
+	return quotaOperation(method) + " " + strings.ToLower(strings.TrimSuffix(path, "/"))
+}
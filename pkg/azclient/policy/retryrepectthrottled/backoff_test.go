@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryrepectthrottled
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("ResourceBackoffPolicy", func() {
+	const (
+		lbURL    = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+		otherURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/other"
+	)
+	var (
+		now       time.Time
+		delays    []time.Duration
+		responses []*http.Response
+		p         *ResourceBackoffPolicy
+		pipeline  runtime.Pipeline
+	)
+
+	newResponse := func(statusCode int, retryAfter string) *http.Response {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}
+		if retryAfter != "" {
+			resp.Header.Set(HeaderRetryAfter, retryAfter)
+		}
+		return resp
+	}
+	do := func(method, url string) {
+		req, err := runtime.NewRequest(context.Background(), method, url)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		_, err = pipeline.Do(req)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	}
+
+	ginkgo.BeforeEach(func() {
+		now = time.Now()
+		delays = nil
+		responses = nil
+		p = NewResourceBackoffPolicy(&ResourceBackoffOptions{
+			BaseDelay: time.Second,
+			MaxDelay:  5 * time.Second,
+		}).(*ResourceBackoffPolicy)
+		p.backoffs = &resourceBackoffs{}
+		p.now = func() time.Time { return now }
+		p.sleep = func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+		pipeline = runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			PerCallPolicies: []policy.Policy{
+				p,
+				funcPolicy(func(*policy.Request) (*http.Response, error) {
+					if len(responses) == 0 {
+						return newResponse(http.StatusOK, ""), nil
+					}
+					resp := responses[0]
+					responses = responses[1:]
+					return resp, nil
+				}),
+			},
+			Retry: policy.RetryOptions{MaxRetries: -1},
+		})
+	})
+
+	ginkgo.It("should back off the requests to the throttled resource exponentially", func() {
+		for i := 0; i < 5; i++ {
+			responses = append(responses, newResponse(http.StatusTooManyRequests, ""))
+		}
+		for i := 0; i < 5; i++ {
+			do(http.MethodGet, lbURL)
+		}
+		// the sleeps are faked, so the backoffs start from the time of the last response
+		gomega.Expect(delays).To(gomega.Equal([]time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second,
+		}))
+
+		// the other resources and the other operations are not delayed
+		delays = nil
+		do(http.MethodGet, otherURL)
+		do(http.MethodPut, lbURL)
+		gomega.Expect(delays).To(gomega.BeEmpty())
+
+		// the backoff is reset by the response not throttled
+		do(http.MethodGet, lbURL)
+		gomega.Expect(delays).To(gomega.Equal([]time.Duration{5 * time.Second}))
+		delays = nil
+		do(http.MethodGet, lbURL)
+		gomega.Expect(delays).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should honor the Retry-After of the throttled response", func() {
+		responses = append(responses, newResponse(http.StatusTooManyRequests, "10"))
+		do(http.MethodDelete, lbURL)
+		do(http.MethodDelete, lbURL+"/")
+		gomega.Expect(delays).To(gomega.Equal([]time.Duration{10 * time.Second}))
+	})
+
+	ginkgo.It("should list the throttled resources", func() {
+		responses = append(responses, newResponse(http.StatusTooManyRequests, "10"), newResponse(http.StatusTooManyRequests, ""))
+		do(http.MethodPut, lbURL)
+		do(http.MethodGet, otherURL)
+		gomega.Expect(p.backoffs.throttled(now)).To(gomega.Equal([]ThrottledResource{
+			{Operation: "Writes", ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.network/loadbalancers/lb", Until: now.Add(10 * time.Second)},
+			{Operation: "Reads", ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.network/loadbalancers/other", Until: now.Add(time.Second)},
+		}))
+		gomega.Expect(p.backoffs.throttled(now.Add(5 * time.Second))).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should restart the backoff after the resource is not requested for a while", func() {
+		responses = append(responses, newResponse(http.StatusTooManyRequests, ""), newResponse(http.StatusTooManyRequests, ""))
+		do(http.MethodGet, lbURL)
+		now = now.Add(time.Minute)
+		do(http.MethodGet, lbURL)
+		do(http.MethodGet, lbURL)
+		gomega.Expect(delays).To(gomega.Equal([]time.Duration{time.Second}))
+	})
+})
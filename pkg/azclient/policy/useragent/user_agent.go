@@ -18,12 +18,15 @@ package useragent
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 )
 
 type CustomUserAgentPolicy struct {
 	CustomUserAgent string
+	// Suffix is appended to the User-Agent.
+	Suffix string
 }
 
 const HeaderUserAgent = "User-Agent"
@@ -34,13 +37,27 @@ func NewCustomUserAgentPolicy(customUserAgent string) policy.Policy {
 	}
 }
 
+// NewCustomUserAgentPolicyWithSuffix returns the policy setting the custom User-Agent if there is none, and
+// appending the suffix to the User-Agent.
+func NewCustomUserAgentPolicyWithSuffix(customUserAgent, suffix string) policy.Policy {
+	return &CustomUserAgentPolicy{
+		CustomUserAgent: customUserAgent,
+		Suffix:          suffix,
+	}
+}
+
 func (p CustomUserAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
-	if p.CustomUserAgent == "" {
+	if p.CustomUserAgent == "" && p.Suffix == "" {
 		return req.Next()
 	}
 	// preserve the existing User-Agent string
-	if ua := req.Raw().Header.Get(HeaderUserAgent); ua == "" {
-		req.Raw().Header.Set(HeaderUserAgent, p.CustomUserAgent)
+	ua := req.Raw().Header.Get(HeaderUserAgent)
+	if ua == "" {
+		ua = p.CustomUserAgent
+	}
+	if p.Suffix != "" {
+		ua = strings.TrimSpace(ua + " " + p.Suffix)
 	}
+	req.Raw().Header.Set(HeaderUserAgent, ua)
 	return req.Next()
 }
@@ -89,5 +89,27 @@ var _ = ginkgo.Describe("useragent", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 			gomega.Expect(req.Raw().Header.Get(useragent.HeaderUserAgent)).To(gomega.BeEmpty())
 		})
+		ginkgo.It("should append the suffix to the existing useragent", func() {
+			pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				PerCallPolicies: []policy.Policy{
+					useragent.NewCustomUserAgentPolicyWithSuffix("test", "cluster/test"),
+					utils.FuncPolicyWrapper(
+						func(*policy.Request) (*http.Response, error) {
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body:       http.NoBody,
+							}, nil
+						},
+					),
+				},
+			})
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, "http://localhost:8080")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = pipeline.Do(req)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			userAgent := req.Raw().Header.Get(useragent.HeaderUserAgent)
+			gomega.Expect(userAgent).To(gomega.HavePrefix("azsdk-go-testmodule/v0.1.0 "))
+			gomega.Expect(userAgent).To(gomega.HaveSuffix(" cluster/test"))
+		})
 	})
 })
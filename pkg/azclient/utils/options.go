@@ -25,6 +25,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
 
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
 )
 
@@ -51,7 +52,10 @@ func GetDefaultAzCoreClientOption() policy.ClientOptions {
 			StatusCodes:   retryrepectthrottled.GetRetriableStatusCode(),
 		},
 		PerRetryPolicies: []policy.Policy{
-			retryrepectthrottled.NewThrottlingPolicy(),
+			// the metrics policy comes first to record the attempts rejected by the throttling policies
+			metrics.NewPolicy(),
+			retryrepectthrottled.NewAdaptiveThrottlingPolicy(nil),
+			retryrepectthrottled.NewResourceBackoffPolicy(nil),
 		},
 		Transport: &http.Client{
 			Transport: DefaultTransport,
@@ -18,8 +18,13 @@ package utils
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,29 +37,109 @@ var once sync.Once
 
 func init() {
 	once.Do(func() {
-		DefaultTransport = &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
-			ForceAttemptHTTP2:     true,
-			MaxIdleConns:          100,
-			MaxConnsPerHost:       100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second, // the same as default transport
-			ResponseHeaderTimeout: 60 * time.Second,
-			TLSClientConfig: &tls.Config{
-				MinVersion:    tls.VersionTLS12,
-				Renegotiation: tls.RenegotiateNever, // the same as default transport https://pkg.go.dev/crypto/tls#RenegotiationSupport
-			},
-		}
-
-		// Configure HTTP/2
-		if http2Transport, err := http2.ConfigureTransports(DefaultTransport); err == nil {
-			http2Transport.ReadIdleTimeout = 30 * time.Second
-			http2Transport.PingTimeout = 15 * time.Second
-		}
+		DefaultTransport = newDefaultTransport()
 	})
 }
+
+func newDefaultTransport() *http.Transport {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxConnsPerHost:       100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second, // the same as default transport
+		ResponseHeaderTimeout: 60 * time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion:    tls.VersionTLS12,
+			Renegotiation: tls.RenegotiateNever, // the same as default transport https://pkg.go.dev/crypto/tls#RenegotiationSupport
+		},
+	}
+
+	// Configure HTTP/2
+	if http2Transport, err := http2.ConfigureTransports(transport); err == nil {
+		http2Transport.ReadIdleTimeout = 30 * time.Second
+		http2Transport.PingTimeout = 15 * time.Second
+	}
+	return transport
+}
+
+// NewTransport returns a transport with the same settings as DefaultTransport which sends the requests
+// through the given proxy, except the ones to the hosts in noProxy, and trusts the CA bundle in caCertFile
+// in addition to the system roots. The proxy of the environment is used if httpsProxy is empty.
+func NewTransport(httpsProxy, noProxy, caCertFile string) (*http.Transport, error) {
+	transport := newDefaultTransport()
+	if httpsProxy != "" {
+		proxyURL, err := url.Parse(httpsProxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the proxy url %q: %w", httpsProxy, err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", httpsProxy)
+		}
+		transport.Proxy = proxyFunc(proxyURL, noProxy)
+	}
+	if caCertFile != "" {
+		content, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading the CA bundle %s: %w", caCertFile, err)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no certificate found in the CA bundle %s", caCertFile)
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	return transport, nil
+}
+
+// proxyFunc returns a proxy function which sends only the https requests through the proxy. The
+// proxy is bypassed for the link-local addresses, e.g. IMDS at 169.254.169.254, and for the hosts
+// matching an entry of the comma separated noProxy list. An entry is "*", an IP address, a CIDR,
+// or a domain name which also matches its subdomains.
+func proxyFunc(proxyURL *url.URL, noProxy string) func(*http.Request) (*url.URL, error) {
+	var entries []string
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if !strings.EqualFold(req.URL.Scheme, "https") {
+			return nil, nil
+		}
+		host := strings.ToLower(req.URL.Hostname())
+		if ip := net.ParseIP(host); ip != nil && (ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()) {
+			return nil, nil
+		}
+		for _, entry := range entries {
+			if matchNoProxy(entry, host) {
+				return nil, nil
+			}
+		}
+		return proxyURL, nil
+	}
+}
+
+func matchNoProxy(entry, host string) bool {
+	if entry == "*" {
+		return true
+	}
+	if _, cidr, err := net.ParseCIDR(entry); err == nil {
+		ip := net.ParseIP(host)
+		return ip != nil && cidr.Contains(ip)
+	}
+	if ip := net.ParseIP(entry); ip != nil {
+		return ip.Equal(net.ParseIP(host))
+	}
+	entry = strings.TrimPrefix(entry, ".")
+	return host == entry || strings.HasSuffix(host, "."+entry)
+}
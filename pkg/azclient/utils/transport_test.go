@@ -17,7 +17,17 @@ limitations under the License.
 package utils
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -73,3 +83,91 @@ func TestDefaultTransport(t *testing.T) {
 
 	// NOTE: http2 transport settings are not exposed hence testing is skipped.
 }
+
+func TestNewTransport(t *testing.T) {
+	transport, err := NewTransport("http://proxy.example.com:3128", ".internal.example.com,10.0.0.0/8,localhost", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transport == DefaultTransport {
+		t.Fatal("expected a new transport")
+	}
+	if transport.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected MinVersion to be TLS1.2, got %v", transport.TLSClientConfig.MinVersion)
+	}
+
+	for host, expectProxy := range map[string]bool{
+		"https://management.azure.com/subscriptions":      true,
+		"http://169.254.169.254/metadata/instance":        false,
+		"https://169.254.169.254/metadata/instance":       false,
+		"https://[fe80::1]/":                              false,
+		"http://management.azure.com/subscriptions":       false,
+		"https://vault.internal.example.com/secrets":      false,
+		"https://internal.example.com/secrets":            false,
+		"https://notinternal.example.com/secrets":         true,
+		"https://10.1.2.3/":                               false,
+		"https://localhost:8443/":                         false,
+		"https://login.microsoftonline.com/tenant/oauth2": true,
+	} {
+		req, err := http.NewRequest(http.MethodGet, host, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		proxyURL, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expectProxy && (proxyURL == nil || proxyURL.Host != "proxy.example.com:3128") {
+			t.Errorf("Expected %s to be accessed through the proxy, got %v", host, proxyURL)
+		}
+		if !expectProxy && proxyURL != nil {
+			t.Errorf("Expected %s not to be accessed through the proxy, got %v", host, proxyURL)
+		}
+	}
+
+	if _, err := NewTransport("proxy.example.com", "", ""); err == nil {
+		t.Error("Expected error for the proxy url without scheme")
+	}
+}
+
+func TestNewTransportWithCABundle(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transport, err := NewTransport("", "", caFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transport.TLSClientConfig.RootCAs == nil {
+		t.Fatal("RootCAs is nil")
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewTransport("", "", invalidFile); err == nil {
+		t.Error("Expected error for the CA bundle without certificate")
+	}
+	if _, err := NewTransport("", "", filepath.Join(dir, "notfound.pem")); err == nil {
+		t.Error("Expected error for the missing CA bundle")
+	}
+}
@@ -56,6 +56,9 @@ type AzureCacheEntry struct {
 	Lock sync.Mutex
 	// time when entry was fetched and created
 	CreatedOn time.Time
+	// TTL is the TTL of the entry caching a nil result of the getter. The entries
+	// caching other results use the TTL of the cache.
+	TTL time.Duration
 }
 
 // cacheKeyFunc defines the key function required in TTLStore.
@@ -81,6 +84,9 @@ type TimedCache struct {
 	Store     cache.Store
 	MutexLock sync.RWMutex
 	TTL       time.Duration
+	// NotFoundTTL is the TTL of the nil results of the getter, which mean the
+	// resources are not found. The nil results are not cached if it is zero.
+	NotFoundTTL time.Duration
 
 	resourceProvider Resource
 }
@@ -91,6 +97,13 @@ type ResourceProvider struct {
 
 // NewTimedCache creates a new azcache.Resource.
 func NewTimedCache(ttl time.Duration, getter GetFunc, disabled bool) (Resource, error) {
+	return NewTimedCacheWithNotFoundTTL(ttl, 0, getter, disabled)
+}
+
+// NewTimedCacheWithNotFoundTTL creates a new azcache.Resource caching the nil results
+// of the getter for notFoundTTL, so the resources not found are not fetched again by
+// every Get.
+func NewTimedCacheWithNotFoundTTL(ttl, notFoundTTL time.Duration, getter GetFunc, disabled bool) (Resource, error) {
 	if getter == nil {
 		return nil, fmt.Errorf("getter is not provided")
 	}
@@ -110,6 +123,7 @@ func NewTimedCache(ttl time.Duration, getter GetFunc, disabled bool) (Resource,
 		Store:            cache.NewStore(cacheKeyFunc),
 		MutexLock:        sync.RWMutex{},
 		TTL:              ttl,
+		NotFoundTTL:      notFoundTTL,
 		resourceProvider: provider,
 	}
 	return timedCache, nil
@@ -191,6 +205,12 @@ func (t *TimedCache) get(ctx context.Context, key string, crt AzureCacheReadType
 			return entry.Data, nil
 		}
 	}
+	// the resource was not found recently, the TTL is honored by unsafe reads too
+	// so the resources created later are found eventually
+	if entry.Data == nil && entry.TTL > 0 && crt != CacheReadTypeForceRefresh &&
+		time.Since(entry.CreatedOn) < entry.TTL {
+		return nil, nil
+	}
 	// Data is not cached yet, cache data is expired or requested force refresh
 	// cache it by getter. entry is locked before getting to ensure concurrent
 	// gets don't result in multiple ARM calls.
@@ -203,6 +223,10 @@ func (t *TimedCache) get(ctx context.Context, key string, crt AzureCacheReadType
 	// to now as the data was recently fetched
 	entry.Data = data
 	entry.CreatedOn = time.Now().UTC()
+	entry.TTL = 0
+	if data == nil {
+		entry.TTL = t.NotFoundTTL
+	}
 
 	return entry.Data, nil
 }
@@ -237,6 +261,7 @@ func (t *TimedCache) Update(key string, data interface{}) {
 		defer entry.Lock.Unlock()
 		entry.Data = data
 		entry.CreatedOn = time.Now().UTC()
+		entry.TTL = 0
 	} else {
 		_ = t.Store.Update(&AzureCacheEntry{
 			Key:       key,
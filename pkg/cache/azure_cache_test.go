@@ -311,3 +311,40 @@ func TestCacheForceRefresh(t *testing.T) {
 	assert.Equal(t, 2, dataSource.called)
 	assert.Equal(t, val, v, "should refetch unexpired data as forced refresh")
 }
+
+func TestCacheNotFoundTTL(t *testing.T) {
+	dataSource := &fakeDataSource{
+		sem: *semaphore.NewWeighted(1),
+	}
+	resource, err := NewTimedCacheWithNotFoundTTL(time.Hour, fakeCacheTTL, dataSource.get, false)
+	assert.NoError(t, err)
+	cache := resource.(*TimedCache)
+
+	v, err := cache.Get(context.TODO(), testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dataSource.called)
+	assert.Nil(t, v)
+
+	v, err = cache.Get(context.TODO(), testKey, CacheReadTypeUnsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dataSource.called, "should not refetch the data not found before the not found TTL")
+	assert.Nil(t, v)
+
+	v, err = cache.Get(context.TODO(), testKey, CacheReadTypeForceRefresh)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dataSource.called, "should refetch the data not found as forced refresh")
+	assert.Nil(t, v)
+
+	val := &fakeDataObj{}
+	dataSource.update(testKey, val)
+	time.Sleep(fakeCacheTTL)
+	v, err = cache.Get(context.TODO(), testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dataSource.called, "should refetch the data not found after the not found TTL")
+	assert.Equal(t, val, v)
+
+	v, err = cache.Get(context.TODO(), testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dataSource.called, "should use the TTL of the cache for the data found")
+	assert.Equal(t, val, v)
+}
@@ -43,6 +43,38 @@ const (
 	LabelFailureDomainBetaRegion = "failure-domain.beta.kubernetes.io/region"
 	// LabelPlatformSubFaultDomain is the label key of platformSubFaultDomain
 	LabelPlatformSubFaultDomain = "topology.kubernetes.azure.com/sub-fault-domain"
+	// LabelPlatformFaultDomain is the label key of the platform fault domain of the VM
+	LabelPlatformFaultDomain = "topology.kubernetes.azure.com/fault-domain"
+	// LabelPlatformUpdateDomain is the label key of the platform update domain of the VM
+	LabelPlatformUpdateDomain = "topology.kubernetes.azure.com/update-domain"
+
+	// LabelAcceleratedNetworking is the label key of whether the primary NIC of the VM has accelerated networking
+	LabelAcceleratedNetworking = "kubernetes.azure.com/accelerated-networking"
+	// LabelUltraSSDEnabled is the label key of whether the VM can attach ultra disks
+	LabelUltraSSDEnabled = "kubernetes.azure.com/ultra-ssd-enabled"
+	// LabelProximityPlacementGroup is the label key of the proximity placement group of the VM
+	LabelProximityPlacementGroup = "kubernetes.azure.com/proximity-placement-group"
+	// LabelDedicatedHostGroup is the label key of the dedicated host group of the VM
+	LabelDedicatedHostGroup = "kubernetes.azure.com/dedicated-host-group"
+	// LabelDedicatedHost is the label key of the dedicated host of the VM
+	LabelDedicatedHost = "kubernetes.azure.com/dedicated-host"
+	// LabelCapacityReservationGroup is the label key of the capacity reservation group of the VM
+	LabelCapacityReservationGroup = "kubernetes.azure.com/capacity-reservation-group"
+	// LabelSKUCPU is the label key of the number of vCPUs of the VM size
+	LabelSKUCPU = "kubernetes.azure.com/sku-cpu"
+	// LabelSKUMemory is the label key of the memory of the VM size in MiB
+	LabelSKUMemory = "kubernetes.azure.com/sku-memory"
+	// LabelSKUGPUCount is the label key of the number of GPUs of the VM size
+	LabelSKUGPUCount = "kubernetes.azure.com/sku-gpu-count"
+	// VMDeletedTaintKey is the NoExecute taint of the nodes whose VMs are deleted, which evicts the pods
+	// before the nodes are removed.
+	VMDeletedTaintKey = "kubernetes.azure.com/vm-deleted"
+	// VMDeletedCordonedAnnotationKey is the annotation of the nodes cordoned because their VMs are deleted,
+	// so they are uncordoned if their VMs exist again.
+	VMDeletedCordonedAnnotationKey = "kubernetes.azure.com/vm-deleted-cordoned"
+	// RouteIPConfigurationAnnotation is the annotation of the node listing the comma-separated names of the IP
+	// configurations of the NICs whose private IPs are the next hops of the routes of the node.
+	RouteIPConfigurationAnnotation = "kubernetes.azure.com/route-ip-configuration"
 
 	// ADFSIdentitySystem is the override value for tenantID on Azure Stack clouds.
 	ADFSIdentitySystem = "adfs"
@@ -96,6 +128,10 @@ const (
 
 	// RetryAfterHeaderKey is the retry-after header key in ARM responses.
 	RetryAfterHeaderKey = "Retry-After"
+	// CorrelationRequestIDHeaderKey is the correlation request ID header key in ARM responses.
+	CorrelationRequestIDHeaderKey = "x-ms-correlation-request-id"
+	// RequestIDHeaderKey is the request ID header key in ARM responses.
+	RequestIDHeaderKey = "x-ms-request-id"
 
 	// StrRawVersion is the raw version string
 	StrRawVersion string = "raw"
@@ -154,6 +190,10 @@ const (
 	NonVmssUniformNodesCacheTTLDefaultInSeconds = 900
 	// VMSSCacheTTLDefaultInSeconds is the TTL of the vmss cache
 	VMSSCacheTTLDefaultInSeconds = 600
+	// DefaultCacheWarmupConcurrency is the default number of resource groups and scale sets listed concurrently to fill the caches
+	DefaultCacheWarmupConcurrency = 8
+	// DefaultCacheSnapshotIntervalInSeconds is the default interval to save the snapshot of the caches
+	DefaultCacheSnapshotIntervalInSeconds = 300
 	// VMSSVirtualMachinesCacheTTLDefaultInSeconds is the TTL of the vmss vm cache
 	VMSSVirtualMachinesCacheTTLDefaultInSeconds = 600
 	// VMASCacheTTLDefaultInSeconds is the TTL of the vmas cache
@@ -316,20 +356,72 @@ const (
 	// `/` would be configured by default.
 	ServiceAnnotationLoadBalancerHealthProbeRequestPath = "service.beta.kubernetes.io/azure-load-balancer-health-probe-request-path"
 
+	// ServiceAnnotationLoadBalancerHealthProbeMode determines the health probe mode of the cluster service,
+	// which overrides the clusterServiceLoadBalancerHealthProbeMode in the cloud config. The supported values
+	// are `servicenodeport`, which probes the node ports of the service, and `shared`, which probes the
+	// shared node health endpoint (kube-proxy's healthz by default). It is ignored by the local service.
+	ServiceAnnotationLoadBalancerHealthProbeMode = "service.beta.kubernetes.io/azure-load-balancer-health-probe-mode"
+
+	// ServiceAnnotationLoadBalancerHealthProbeNodePort determines the node port probed by all the load balancing
+	// rules of the cluster service, instead of probing the node ports of the service one by one. The protocol
+	// and the request path of the probe follow the service level health probe annotations. It cannot be used
+	// together with the `shared` health probe mode annotation, and it is ignored by the local service.
+	ServiceAnnotationLoadBalancerHealthProbeNodePort = "service.beta.kubernetes.io/azure-load-balancer-health-probe-node-port"
+
+	// ServiceAnnotationLoadBalancerRegions is a comma-separated list of the regions of the regional load balancers
+	// the service gets a public frontend in, in addition to its frontend on the load balancer of the cluster.
+	// The regions must be configured by regionalLoadBalancers, and it is not supported by the internal services.
+	ServiceAnnotationLoadBalancerRegions = "service.beta.kubernetes.io/azure-load-balancer-regions"
+
 	// ServiceAnnotationAzurePIPTags determines what tags should be applied to the public IP of the service. The cluster name
 	// and service names tags (which is managed by controller manager itself) would keep unchanged. The supported format
 	// is `a=b,c=d,...`. After updated, the old user-assigned tags would not be replaced by the new ones.
 	ServiceAnnotationAzurePIPTags = "service.beta.kubernetes.io/azure-pip-tags"
 
+	// ServiceAnnotationAzureResourceTags determines what tags should be applied to the public IP and private link service
+	// of the service, in addition to the tags set in the cloud config. The supported format is `a=b,c=d,...`. Tags in this
+	// annotation take precedence over the cloud config tags, and `azure-pip-tags` takes precedence over this annotation on
+	// the public IP. The load balancer is shared by the services and load balancing rules can't be tagged, so they only
+	// get the cloud config tags.
+	ServiceAnnotationAzureResourceTags = "service.beta.kubernetes.io/azure-resource-tags"
+
+	// ServiceAnnotationAzurePIPZones specifies the availability zones of the public IP created for the service.
+	// The supported values are `zone-redundant` (default, all zones of the region), `no-zone`, or a comma-separated
+	// list of zones of the region such as `1` or `1,2`. The zones of a public IP cannot be changed after it is
+	// created, so the annotation only takes effect when the public IP is created.
+	ServiceAnnotationAzurePIPZones = "service.beta.kubernetes.io/azure-pip-zones"
+
+	// ServiceAnnotationLoadBalancerZone pins the service to an availability zone of the region such as `1`. The service
+	// gets a backend pool of its own which only contains the nodes in the zone according to their topology labels, so
+	// the traffic doesn't cross the zones. A service with a public IP in a single zone set by azure-pip-zones is pinned
+	// to the zone as well. It requires the IP-based backend pools of a standard load balancer, and the services with
+	// `externalTrafficPolicy: Local` keep the backend pools of the nodes hosting their endpoints if they are enabled.
+	ServiceAnnotationLoadBalancerZone = "service.beta.kubernetes.io/azure-load-balancer-zone"
+
 	// ServiceAnnotationDisableLoadBalancerFloatingIP is the annotation used on the service to disable floating IP in load balancer rule.
 	// If omitted, the default value is false
 	ServiceAnnotationDisableLoadBalancerFloatingIP = "service.beta.kubernetes.io/azure-disable-load-balancer-floating-ip"
 
+	// ServiceAnnotationSecurityGroupManagementMode is the annotation used on the service to set the security group management mode
+	// of the service, i.e. full, append-only or disabled. The stricter one of it and the securityGroupManagementMode of the
+	// cloud provider config is used.
+	ServiceAnnotationSecurityGroupManagementMode = "service.beta.kubernetes.io/azure-security-group-management-mode"
+
 	// ServiceAnnotationAdditionalPublicIPs sets the additional Public IPs (split by comma) besides the service's Public IP configured on LoadBalancer.
 	// These additional Public IPs would be consumed by kube-proxy to configure the iptables rules on each node. Note they would not be configured
 	// automatically on Azure LoadBalancer. Instead, they need to be configured manually (e.g. on Azure cross-region LoadBalancer by another operator).
 	ServiceAnnotationAdditionalPublicIPs = "service.beta.kubernetes.io/azure-additional-public-ips"
 
+	// ServiceAnnotationAdditionalPIPNames sets the names (split by comma) of existing IPv4 public IPs that would be exposed
+	// in addition to the service's primary public IP. A frontend IP configuration and a copy of the load balancing rules
+	// are created on the load balancer for each of them. It is only supported for external services.
+	ServiceAnnotationAdditionalPIPNames = "service.beta.kubernetes.io/azure-additional-pip-names"
+
+	// ServiceAnnotationAdditionalPIPPrefixID sets the ID of an IPv4 public IP prefix from which an additional public IP
+	// is allocated for the service, exposed the same way as the ones in azure-additional-pip-names. The public IP is named
+	// <load balancer name of the service>-<prefix name>, and it is released when the annotation is removed.
+	ServiceAnnotationAdditionalPIPPrefixID = "service.beta.kubernetes.io/azure-additional-pip-prefix-id"
+
 	// ServiceAnnotationLoadBalancerConfigurations is the list of load balancer configurations the service can use.
 	// The list is separated by comma. It will be omitted if multi-slb is not used.
 	ServiceAnnotationLoadBalancerConfigurations = "service.beta.kubernetes.io/azure-load-balancer-configurations"
@@ -337,6 +429,10 @@ const (
 	// ServiceAnnotationDisableTCPReset is the annotation used on the service to disable TCP reset on the load balancer.
 	ServiceAnnotationDisableTCPReset = "service.beta.kubernetes.io/azure-load-balancer-disable-tcp-reset"
 
+	// ServiceAnnotationLoadBalancerPendingOperation records the asynchronous load balancer update started by the
+	// reconciliation of the service, so it can be resumed by another controller. It is managed by the cloud provider.
+	ServiceAnnotationLoadBalancerPendingOperation = "service.beta.kubernetes.io/azure-load-balancer-pending-operation"
+
 	// ServiceTagKey is the service key applied for public IP tags.
 	ServiceTagKey       = "k8s-azure-service"
 	LegacyServiceTagKey = "service"
@@ -346,6 +442,13 @@ const (
 	// ServiceUsingDNSKey is the service name consuming the DNS label on the public IP
 	ServiceUsingDNSKey       = "k8s-azure-dns-label-service"
 	LegacyServiceUsingDNSKey = "kubernetes-dns-label-service"
+	// AdoptedByServicesKey is the names of the services using the user-assigned public IP, which are
+	// recorded for tracking only, the public IP is never deleted by the cloud provider.
+	AdoptedByServicesKey = "k8s-azure-adopted-by-services"
+	// ManagedTagsKey is the keys of the tags applied by the cloud provider, which are recorded on the
+	// resources if preserveForeignTags is set. The keys which don't fit in one tag value are continued in
+	// k8s-azure-managed-tags-1, k8s-azure-managed-tags-2 and so on.
+	ManagedTagsKey = "k8s-azure-managed-tags"
 
 	// DefaultLoadBalancerSourceRanges is the default value of the load balancer source ranges
 	DefaultLoadBalancerSourceRanges = "0.0.0.0/0"
@@ -353,6 +456,11 @@ const (
 	// TrueAnnotationValue is the true annotation value
 	TrueAnnotationValue = "true"
 
+	// PIPZonesZoneRedundant is the value of the azure-pip-zones annotation to create a zone-redundant public IP
+	PIPZonesZoneRedundant = "zone-redundant"
+	// PIPZonesNoZone is the value of the azure-pip-zones annotation to create a public IP without zones
+	PIPZonesNoZone = "no-zone"
+
 	// LoadBalancerMinimumPriority is the minimum priority
 	LoadBalancerMinimumPriority = 500
 	// LoadBalancerMaximumPriority is the maximum priority
@@ -408,6 +516,8 @@ const (
 	CannotUpdateVMBeingDeletedMessageSuffix = "since it is marked for deletion"
 	// OperationPreemptedErrorMessage is the error message returned for vm operation preempted errors
 	OperationPreemptedErrorMessage = "Operation execution has been preempted by a more recent operation"
+	// ScopeLockedErrorCode is the error code that the request failed due to a read-only or delete management lock.
+	ScopeLockedErrorCode = "ScopeLocked"
 )
 
 // node ipam controller
@@ -432,6 +542,10 @@ const (
 	ImdsInstanceURI = "/metadata/instance"
 	// ImdsLoadBalancerURI is the imds load balancer uri
 	ImdsLoadBalancerURI = "/metadata/loadbalancer"
+	// ImdsScheduledEventsAPIVersion is the imds scheduled events api version
+	ImdsScheduledEventsAPIVersion = "2020-07-01"
+	// ImdsScheduledEventsURI is the imds scheduled events uri
+	ImdsScheduledEventsURI = "/metadata/scheduledevents"
 )
 
 // routes
@@ -441,6 +555,29 @@ const (
 
 	// DefaultRouteUpdateIntervalInSeconds defines the route reconciling interval.
 	DefaultRouteUpdateIntervalInSeconds = 30
+	// DefaultRouteDriftReconciliationIntervalInSeconds defines the interval of detecting the drifted routes.
+	DefaultRouteDriftReconciliationIntervalInSeconds = 300
+
+	// RouteDriftReconciliationModeAudit only reports the drifted routes by metrics.
+	RouteDriftReconciliationModeAudit = "audit"
+	// RouteDriftReconciliationModeRepair reports and repairs the drifted routes.
+	RouteDriftReconciliationModeRepair = "repair"
+)
+
+// ClusterAutoscalerToBeDeletedTaint is the taint cluster-autoscaler adds to the nodes it is going to delete.
+const ClusterAutoscalerToBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+
+// security group management modes
+const (
+	// SecurityGroupManagementModeFull manages the security rules of the services, and removes the service IPs
+	// from any rule in the priority range of the cloud provider. It is the default mode.
+	SecurityGroupManagementModeFull = "full"
+	// SecurityGroupManagementModeAppendOnly only adds and updates the security rules created by the cloud provider,
+	// i.e. the rules named with the prefix k8s-azure-lb_, and never modifies or deletes the other rules.
+	SecurityGroupManagementModeAppendOnly = "append-only"
+	// SecurityGroupManagementModeDisabled never adds or updates any security rule, and only removes the stale
+	// rules of the service created by the cloud provider, e.g. after switching from another mode or on deletion.
+	SecurityGroupManagementModeDisabled = "disabled"
 )
 
 // cloud provider config secret
@@ -561,6 +698,45 @@ const (
 	ServiceNameLabel = "kubernetes.io/service-name"
 )
 
+// Load Balancer resource naming schemes
+const (
+	// LoadBalancerResourceNamingSchemeLegacy names the load balancing rules and probes as
+	// `<prefix>[-<subnet>]-<protocol>-<port>`.
+	LoadBalancerResourceNamingSchemeLegacy = "legacy"
+	// LoadBalancerResourceNamingSchemeHashed names the load balancing rules and probes as
+	// `<prefix>-<hash>`, where hash is the first 16 hex characters of the SHA-256 of `<service UID>[/<subnet>]/<protocol>/<port>`.
+	LoadBalancerResourceNamingSchemeHashed = "hashed"
+	// LoadBalancerResourceNameHashLength is the length of the hash in load balancer resource names.
+	LoadBalancerResourceNameHashLength = 16
+)
+
+// Deallocated VM policies
+const (
+	// DeallocatedVMPolicyShutdown reports the deallocated VMs as shut down, so their nodes
+	// get the shutdown taint and are kept in the cluster.
+	DeallocatedVMPolicyShutdown = "shutdown"
+	// DeallocatedVMPolicyDeleted reports the deallocated VMs as not existing, so their nodes
+	// are deleted from the cluster.
+	DeallocatedVMPolicyDeleted = "deleted"
+)
+
+// Node address sources
+const (
+	// NodeAddressSourceIMDS reads the addresses of the local instance from IMDS if
+	// useInstanceMetadata is enabled, and the addresses of other instances from ARM.
+	NodeAddressSourceIMDS = "imds"
+	// NodeAddressSourceARM always reads the node addresses from the NICs in ARM.
+	NodeAddressSourceARM = "arm"
+)
+
+// Node address orders
+const (
+	// NodeAddressOrderInternalIPFirst reports the InternalIPs before the ExternalIPs.
+	NodeAddressOrderInternalIPFirst = "internalIPFirst"
+	// NodeAddressOrderExternalIPFirst reports the ExternalIPs before the InternalIPs.
+	NodeAddressOrderExternalIPFirst = "externalIPFirst"
+)
+
 // Load Balancer health probe mode
 const (
 	ClusterServiceLoadBalancerHealthProbeModeServiceNodePort = "servicenodeport"
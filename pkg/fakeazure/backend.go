@@ -0,0 +1,512 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakeazure implements an in-memory fake of the Azure Resource Manager API. The fake backend
+// is the transport of the ARM clients, so the cloud provider runs against it without an Azure
+// subscription, e.g. in kind clusters. The resources are stored as they are put, with the IP
+// addresses allocated, and all the operations complete synchronously.
+package fakeazure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const provisioningStateSucceeded = "Succeeded"
+
+// Backend is an in-memory fake of the Azure Resource Manager API, which implements policy.Transporter.
+// The resources are keyed by their IDs, case-insensitively. The child resources, e.g. the backend pools
+// of a load balancer, are kept in the properties of their parents if the parents have them, and are
+// stored as separate resources otherwise, e.g. the VMs of a virtual machine scale set.
+type Backend struct {
+	lock      sync.Mutex
+	resources map[string]map[string]any
+	// revision is increased by every change, it is used for the etags and the request IDs.
+	revision int
+	// addresses is the number of the allocated IP addresses.
+	addresses int
+}
+
+// NewBackend returns an empty Backend.
+func NewBackend() *Backend {
+	return &Backend{
+		resources: make(map[string]map[string]any),
+	}
+}
+
+// ClientOption sets the backend as the transport of the ARM clients.
+func (b *Backend) ClientOption(option *arm.ClientOptions) {
+	option.Transport = b
+}
+
+// Credential is the credential of the ARM clients served by the fake backend, whose tokens are not
+// checked.
+type Credential struct{}
+
+// GetToken implements azcore.TokenCredential.
+func (Credential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// Put stores the resource with the ID, as if it was put by an ARM client.
+func (b *Backend) Put(id string, resource any) error {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if status, payload := b.put(id, body, ""); status >= http.StatusBadRequest {
+		return fmt.Errorf("failed to put %s: %s", id, payload)
+	}
+	return nil
+}
+
+// Get reads the resource with the ID into resource. It returns false if the resource is not found.
+func (b *Backend) Get(id string, resource any) (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	stored, found := b.get(id)
+	if !found {
+		return false, nil
+	}
+	body, err := json.Marshal(stored)
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(body, resource)
+}
+
+// LoadFile stores the resources of the JSON file, which is an array of ARM resources with their IDs.
+func (b *Backend) LoadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var resources []map[string]any
+	if err := json.Unmarshal(content, &resources); err != nil {
+		return fmt.Errorf("failed to parse the resources of %s: %w", path, err)
+	}
+	for i, resource := range resources {
+		id, _ := resource["id"].(string)
+		if id == "" {
+			return fmt.Errorf("the resource %d of %s has no id", i, path)
+		}
+		if err := b.Put(id, resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do implements policy.Transporter.
+func (b *Backend) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b.lock.Lock()
+	status, payload := b.serve(req.Method, strings.TrimSuffix(req.URL.Path, "/"), req.Header.Get("If-Match"), body)
+	requestID := fmt.Sprintf("fake-%d", b.revision)
+	b.lock.Unlock()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("x-ms-request-id", requestID)
+	header.Set("x-ms-correlation-request-id", requestID)
+	resp := &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:        header,
+		Body:          http.NoBody,
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}
+	if len(payload) > 0 {
+		resp.Body = io.NopCloser(strings.NewReader(string(payload)))
+	}
+	return resp, nil
+}
+
+func (b *Backend) serve(method, path, ifMatch string, body []byte) (int, []byte) {
+	switch method {
+	case http.MethodGet:
+		if isCollection(path) {
+			return marshal(http.StatusOK, map[string]any{"value": b.list(path)})
+		}
+		resource, found := b.get(path)
+		if !found {
+			return notFound(path)
+		}
+		return marshal(http.StatusOK, resource)
+	case http.MethodPut:
+		return b.put(path, body, ifMatch)
+	case http.MethodPatch:
+		return b.patch(path, body)
+	case http.MethodDelete:
+		return b.delete(path)
+	case http.MethodPost:
+		// the actions, e.g. the updates of the VMs of a scale set, have nothing to do
+		b.revision++
+		return http.StatusOK, nil
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported", method))
+}
+
+func (b *Backend) get(path string) (map[string]any, bool) {
+	if resource, found := b.resources[strings.ToLower(path)]; found {
+		return resource, true
+	}
+	parent, childType, name, ok := splitChild(path)
+	if !ok {
+		return nil, false
+	}
+	parentResource, found := b.resources[strings.ToLower(parent)]
+	if !found {
+		return nil, false
+	}
+	children, _ := embeddedChildren(parentResource, childType)
+	for _, child := range children {
+		if childName, _ := child["name"].(string); strings.EqualFold(childName, name) {
+			return child, true
+		}
+	}
+	return nil, false
+}
+
+func (b *Backend) list(path string) []map[string]any {
+	collection := strings.ToLower(path)
+	segments := strings.Split(strings.Trim(collection, "/"), "/")
+	// the collections of the subscriptions, e.g. /subscriptions/{id}/providers/Microsoft.Network/loadBalancers
+	var subscriptionPrefix, subscriptionType string
+	if len(segments) == 5 && segments[0] == "subscriptions" && segments[2] == "providers" {
+		subscriptionPrefix = "/subscriptions/" + segments[1] + "/resourcegroups/"
+		subscriptionType = resourceType(collection)
+	}
+
+	result := []map[string]any{}
+	for key, resource := range b.resources {
+		if parentCollection(key) == collection ||
+			(subscriptionPrefix != "" && strings.HasPrefix(key, subscriptionPrefix) && !isChild(key) && resourceType(key) == subscriptionType) {
+			result = append(result, resource)
+		}
+	}
+	if index := strings.LastIndex(path, "/"); index > 0 {
+		if parentResource, found := b.resources[collection[:index]]; found {
+			children, _ := embeddedChildren(parentResource, path[index+1:])
+			result = append(result, children...)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		idI, _ := result[i]["id"].(string)
+		idJ, _ := result[j]["id"].(string)
+		return strings.ToLower(idI) < strings.ToLower(idJ)
+	})
+	return result
+}
+
+func (b *Backend) put(path string, body []byte, ifMatch string) (int, []byte) {
+	var resource map[string]any
+	if err := json.Unmarshal(body, &resource); err != nil || resource == nil {
+		return errorResponse(http.StatusBadRequest, "InvalidRequestContent", fmt.Sprintf("invalid body of %s: %v", path, err))
+	}
+	existing, found := b.get(path)
+	if ifMatch != "" && ifMatch != "*" && (!found || existing["etag"] != ifMatch) {
+		return errorResponse(http.StatusPreconditionFailed, "PreconditionFailed", fmt.Sprintf("the etag of %s does not match %s", path, ifMatch))
+	}
+	b.store(path, resource)
+
+	status := http.StatusCreated
+	if found {
+		status = http.StatusOK
+	}
+	return marshal(status, resource)
+}
+
+func (b *Backend) patch(path string, body []byte) (int, []byte) {
+	var update map[string]any
+	if err := json.Unmarshal(body, &update); err != nil {
+		return errorResponse(http.StatusBadRequest, "InvalidRequestContent", fmt.Sprintf("invalid body of %s: %v", path, err))
+	}
+	existing, found := b.get(path)
+	if !found {
+		return notFound(path)
+	}
+	resource := merge(existing, update)
+	b.store(path, resource)
+	return marshal(http.StatusOK, resource)
+}
+
+func (b *Backend) delete(path string) (int, []byte) {
+	key := strings.ToLower(path)
+	if _, found := b.resources[key]; found {
+		b.revision++
+		for stored := range b.resources {
+			if stored == key || strings.HasPrefix(stored, key+"/") {
+				delete(b.resources, stored)
+			}
+		}
+		return http.StatusOK, nil
+	}
+	if parent, childType, name, ok := splitChild(path); ok {
+		if parentResource, found := b.resources[strings.ToLower(parent)]; found {
+			children, property := embeddedChildren(parentResource, childType)
+			for i, child := range children {
+				if childName, _ := child["name"].(string); strings.EqualFold(childName, name) {
+					b.revision++
+					setEmbeddedChildren(parentResource, property, append(children[:i:i], children[i+1:]...))
+					return http.StatusOK, nil
+				}
+			}
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+// store completes the resource like ARM does, and stores it in its parent or as a separate resource.
+func (b *Backend) store(path string, resource map[string]any) {
+	b.revision++
+	name := path[strings.LastIndex(path, "/")+1:]
+	resource["id"] = path
+	resource["name"] = name
+	resource["type"] = resourceType(path)
+	resource["etag"] = fmt.Sprintf("W/\"%d\"", b.revision)
+	properties, _ := resource["properties"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+		resource["properties"] = properties
+	}
+	properties["provisioningState"] = provisioningStateSucceeded
+	if strings.EqualFold(resourceType(path), "Microsoft.Network/publicIPAddresses") {
+		b.allocatePublicIPAddress(properties)
+	}
+	// the embedded child resources get their IDs, e.g. the frontend IP configurations of a load balancer
+	for property, value := range properties {
+		children, ok := value.([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range children {
+			child, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if childName, _ := child["name"].(string); childName != "" && child["id"] == nil {
+				child["id"] = path + "/" + property + "/" + childName
+			}
+			if childProperties, ok := child["properties"].(map[string]any); ok {
+				b.allocatePrivateIPAddress(childProperties)
+			}
+		}
+	}
+
+	if parent, childType, name, ok := splitChild(path); ok {
+		if parentResource, found := b.resources[strings.ToLower(parent)]; found {
+			if children, property := embeddedChildren(parentResource, childType); property != "" {
+				// the parent is changed with its children
+				parentResource["etag"] = resource["etag"]
+				for i, child := range children {
+					if childName, _ := child["name"].(string); strings.EqualFold(childName, name) {
+						children[i] = resource
+						setEmbeddedChildren(parentResource, property, children)
+						return
+					}
+				}
+				setEmbeddedChildren(parentResource, property, append(children, resource))
+				return
+			}
+		}
+	}
+	b.resources[strings.ToLower(path)] = resource
+}
+
+// allocatePublicIPAddress assigns an address to the public IP if it has none.
+func (b *Backend) allocatePublicIPAddress(properties map[string]any) {
+	if address, _ := properties["ipAddress"].(string); address != "" {
+		return
+	}
+	b.addresses++
+	if version, _ := properties["publicIPAddressVersion"].(string); strings.EqualFold(version, "IPv6") {
+		properties["ipAddress"] = fmt.Sprintf("2001:db8::%x", b.addresses)
+		return
+	}
+	properties["ipAddress"] = fmt.Sprintf("20.%d.%d.%d", b.addresses>>16&0xff, b.addresses>>8&0xff, b.addresses&0xff)
+}
+
+// allocatePrivateIPAddress assigns a private address to the IP configuration in a subnet, e.g. the
+// frontend IP configuration of an internal load balancer, if it has none.
+func (b *Backend) allocatePrivateIPAddress(properties map[string]any) {
+	if properties["subnet"] == nil {
+		return
+	}
+	if address, _ := properties["privateIPAddress"].(string); address != "" {
+		return
+	}
+	b.addresses++
+	if version, _ := properties["privateIPAddressVersion"].(string); strings.EqualFold(version, "IPv6") {
+		properties["privateIPAddress"] = fmt.Sprintf("fd00::%x", b.addresses)
+		return
+	}
+	properties["privateIPAddress"] = fmt.Sprintf("10.224.%d.%d", b.addresses>>8&0xff, b.addresses&0xff)
+}
+
+// embeddedChildren returns the child resources of the type kept in the properties of the parent, and
+// the name of the property. The name is empty if the parent doesn't have the property.
+func embeddedChildren(parent map[string]any, childType string) ([]map[string]any, string) {
+	properties, _ := parent["properties"].(map[string]any)
+	for property, value := range properties {
+		if !strings.EqualFold(property, childType) {
+			continue
+		}
+		items, _ := value.([]any)
+		children := make([]map[string]any, 0, len(items))
+		for _, item := range items {
+			if child, ok := item.(map[string]any); ok {
+				children = append(children, child)
+			}
+		}
+		return children, property
+	}
+	return nil, ""
+}
+
+func setEmbeddedChildren(parent map[string]any, property string, children []map[string]any) {
+	items := make([]any, 0, len(children))
+	for _, child := range children {
+		items = append(items, child)
+	}
+	parent["properties"].(map[string]any)[property] = items
+}
+
+// merge merges the update into the resource like a PATCH, the nested objects are merged and the other
+// values are replaced.
+func merge(resource, update map[string]any) map[string]any {
+	for key, value := range update {
+		if updateObject, ok := value.(map[string]any); ok {
+			if object, ok := resource[key].(map[string]any); ok {
+				resource[key] = merge(object, updateObject)
+				continue
+			}
+		}
+		resource[key] = value
+	}
+	return resource
+}
+
+// providerIndex returns the index of the providers segment of the path, or -1 if there is none.
+func providerIndex(segments []string) int {
+	for i, segment := range segments {
+		if strings.EqualFold(segment, "providers") {
+			return i
+		}
+	}
+	return -1
+}
+
+// isCollection checks whether the path is a collection of resources, e.g.
+// /subscriptions/{id}/resourceGroups/{rg}/providers/Microsoft.Network/loadBalancers, rather than a resource.
+func isCollection(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	index := providerIndex(segments)
+	if index < 0 {
+		return len(segments)%2 == 1
+	}
+	types := len(segments) - index - 2
+	return types > 0 && types%2 == 1
+}
+
+// isChild checks whether the resource of the path is the child of another resource.
+func isChild(path string) bool {
+	_, _, _, ok := splitChild(path)
+	return ok
+}
+
+// splitChild splits the path of a child resource to the path of its parent, its type and its name.
+func splitChild(path string) (parent, childType, name string, ok bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	index := providerIndex(segments)
+	if index < 0 || len(segments)-index-2 < 4 {
+		return "", "", "", false
+	}
+	return "/" + strings.Join(segments[:len(segments)-2], "/"), segments[len(segments)-2], segments[len(segments)-1], true
+}
+
+// parentCollection returns the collection of the resource with the path.
+func parentCollection(path string) string {
+	return path[:strings.LastIndex(path, "/")]
+}
+
+// resourceType returns the type of the resource or the collection, e.g. Microsoft.Network/loadBalancers.
+func resourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	index := providerIndex(segments)
+	if index < 0 {
+		if len(segments) > 2 {
+			return "Microsoft.Resources/resourceGroups"
+		}
+		return "Microsoft.Resources/subscriptions"
+	}
+	if index+1 >= len(segments) {
+		return ""
+	}
+	types := []string{segments[index+1]}
+	for i := index + 2; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	return strings.Join(types, "/")
+}
+
+func marshal(status int, value any) (int, []byte) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "InternalServerError", err.Error())
+	}
+	return status, payload
+}
+
+func notFound(path string) (int, []byte) {
+	return errorResponse(http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("the resource %s is not found", path))
+}
+
+func errorResponse(status int, code, message string) (int, []byte) {
+	payload, _ := json.Marshal(map[string]any{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+	return status, payload
+}
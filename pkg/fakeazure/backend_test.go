@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeazure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	testSubscriptionID = "sub"
+	testLBID           = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+)
+
+func newTestClientOptions(backend *Backend) *arm.ClientOptions {
+	options := &arm.ClientOptions{}
+	backend.ClientOption(options)
+	return options
+}
+
+func TestBackendLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	client, err := armnetwork.NewLoadBalancersClient(testSubscriptionID, Credential{}, newTestClientOptions(backend))
+	assert.NoError(t, err)
+
+	_, err = client.Get(ctx, "rg", "lb", nil)
+	var respErr *azcore.ResponseError
+	if assert.True(t, errors.As(err, &respErr), "unexpected error: %v", err) {
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+		assert.Equal(t, "ResourceNotFound", respErr.ErrorCode)
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, "rg", "lb", armnetwork.LoadBalancer{
+		Location: ptr.To("eastus"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{{Name: ptr.To("pool")}},
+		},
+	}, nil)
+	assert.NoError(t, err)
+	created, err := poller.PollUntilDone(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testLBID, ptr.Deref(created.ID, ""))
+	assert.Equal(t, "lb", ptr.Deref(created.Name, ""))
+	assert.Equal(t, armnetwork.ProvisioningStateSucceeded, *created.Properties.ProvisioningState)
+	assert.Equal(t, testLBID+"/backendAddressPools/pool", ptr.Deref(created.Properties.BackendAddressPools[0].ID, ""))
+
+	// the backend pools put by their own client are kept in the load balancer
+	poolClient, err := armnetwork.NewLoadBalancerBackendAddressPoolsClient(testSubscriptionID, Credential{}, newTestClientOptions(backend))
+	assert.NoError(t, err)
+	poolPoller, err := poolClient.BeginCreateOrUpdate(ctx, "rg", "lb", "pool-2", armnetwork.BackendAddressPool{}, nil)
+	assert.NoError(t, err)
+	_, err = poolPoller.PollUntilDone(ctx, nil)
+	assert.NoError(t, err)
+	lb, err := client.Get(ctx, "rg", "LB", nil)
+	assert.NoError(t, err)
+	assert.Len(t, lb.Properties.BackendAddressPools, 2)
+	pool, err := poolClient.Get(ctx, "rg", "lb", "pool", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "pool", ptr.Deref(pool.Name, ""))
+
+	// the stale etag is rejected
+	status, _ := backend.serve(http.MethodPut, testLBID, *created.Etag, []byte(`{}`))
+	assert.Equal(t, http.StatusPreconditionFailed, status)
+	status, _ = backend.serve(http.MethodPut, testLBID, *lb.Etag, []byte(`{}`))
+	assert.Equal(t, http.StatusOK, status)
+
+	pager := client.NewListPager("rg", nil)
+	page, err := pager.NextPage(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, page.Value, 1)
+	allPager := client.NewListAllPager(nil)
+	page2, err := allPager.NextPage(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, page2.Value, 1)
+
+	deletePoller, err := client.BeginDelete(ctx, "rg", "lb", nil)
+	assert.NoError(t, err)
+	_, err = deletePoller.PollUntilDone(ctx, nil)
+	assert.NoError(t, err)
+	found, err := backend.Get(testLBID, &armnetwork.LoadBalancer{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestBackendScaleSetVMs(t *testing.T) {
+	backend := NewBackend()
+	vmssID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss"
+	assert.NoError(t, backend.Put(vmssID, map[string]any{"location": "eastus"}))
+	assert.NoError(t, backend.Put(vmssID+"/virtualMachines/0", map[string]any{"instanceId": "0"}))
+	assert.NoError(t, backend.Put(vmssID+"/virtualMachines/1", map[string]any{"instanceId": "1"}))
+
+	// the VMs are listed from the requests of the collection
+	status, payload := backend.serve(http.MethodGet, vmssID+"/virtualMachines", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, string(payload), vmssID+"/virtualMachines/0")
+	assert.Contains(t, string(payload), vmssID+"/virtualMachines/1")
+
+	status, _ = backend.serve(http.MethodPatch, vmssID, "", []byte(`{"tags":{"a":"b"}}`))
+	assert.Equal(t, http.StatusOK, status)
+	var vmss map[string]any
+	found, err := backend.Get(vmssID, &vmss)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "eastus", vmss["location"])
+	assert.Equal(t, map[string]any{"a": "b"}, vmss["tags"])
+
+	// the VMs are deleted with the scale set
+	status, _ = backend.serve(http.MethodDelete, vmssID, "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	found, err = backend.Get(vmssID+"/virtualMachines/0", &map[string]any{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, backend.resources)
+}
+
+func TestBackendLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"id":"`+testLBID+`","location":"eastus"}]`), 0600))
+
+	backend := NewBackend()
+	assert.NoError(t, backend.LoadFile(path))
+	var lb armnetwork.LoadBalancer
+	found, err := backend.Get(testLBID, &lb)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "eastus", ptr.Deref(lb.Location, ""))
+
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"location":"eastus"}]`), 0600))
+	assert.ErrorContains(t, NewBackend().LoadFile(path), "has no id")
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates of the Azure cloud provider. The experimental behaviors of
+// the reconciliations are rolled out and rolled back per cluster by the azureFeatureGates of the cloud
+// config or by --azure-feature-gates, which takes precedence.
+package features
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// PrivateLinkService enables the private link services of the services annotated with
+	// service.beta.kubernetes.io/azure-pls-create. The existing private link services are left as they
+	// are when it is disabled.
+	PrivateLinkService featuregate.Feature = "PrivateLinkService"
+
+	// IPBasedBackendPool enables the IP-based backend pools of loadBalancerBackendPoolConfigurationType
+	// nodeIP. The NIC-based backend pools of nodeIPConfiguration are used when it is disabled, and the
+	// backend pools are migrated to them.
+	IPBasedBackendPool featuregate.Feature = "IPBasedBackendPool"
+
+	// SecurityRuleUpdates updates the changed security rules of a security group one by one instead of
+	// the whole security group.
+	SecurityRuleUpdates featuregate.Feature = "SecurityRuleUpdates"
+)
+
+// defaultFeatureGates are the Azure features with their defaults and maturity.
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	PrivateLinkService:  {Default: true, PreRelease: featuregate.Beta},
+	IPBasedBackendPool:  {Default: true, PreRelease: featuregate.Beta},
+	SecurityRuleUpdates: {Default: true, PreRelease: featuregate.Beta},
+}
+
+var (
+	overridesLock sync.Mutex
+	// overrides are the feature gates set by --azure-feature-gates.
+	overrides map[string]bool
+)
+
+// NewFeatureGate returns the feature gate of the Azure features with their defaults.
+func NewFeatureGate() featuregate.MutableFeatureGate {
+	featureGate := featuregate.NewFeatureGate()
+	if err := featureGate.Add(defaultFeatureGates); err != nil {
+		// should not happen as the features are known
+		panic(fmt.Sprintf("failed to add the Azure feature gates: %v", err))
+	}
+	return featureGate
+}
+
+// SetOverrides sets the feature gates taking precedence over the ones of the cloud config. An error is
+// returned if any of the features is unknown.
+func SetOverrides(featureGates map[string]bool) error {
+	if err := NewFeatureGate().SetFromMap(featureGates); err != nil {
+		return err
+	}
+
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	overrides = make(map[string]bool, len(featureGates))
+	for name, enabled := range featureGates {
+		overrides[name] = enabled
+	}
+	return nil
+}
+
+// New returns the feature gate of the Azure features set by the feature gates of the cloud config and
+// the overrides.
+func New(featureGates map[string]bool) (featuregate.FeatureGate, error) {
+	featureGate := NewFeatureGate()
+	if err := featureGate.SetFromMap(featureGates); err != nil {
+		return nil, fmt.Errorf("invalid azureFeatureGates: %w", err)
+	}
+
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	if err := featureGate.SetFromMap(overrides); err != nil {
+		return nil, fmt.Errorf("invalid --azure-feature-gates: %w", err)
+	}
+	return featureGate, nil
+}
+
+// Enabled returns true if the feature is enabled in the feature gate, or by default if it is nil.
+func Enabled(featureGate featuregate.FeatureGate, feature featuregate.Feature) bool {
+	if featureGate == nil {
+		return defaultFeatureGates[feature].Default
+	}
+	return featureGate.Enabled(feature)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SetOverrides(nil))
+	}()
+
+	featureGate, err := New(nil)
+	assert.NoError(t, err)
+	assert.True(t, featureGate.Enabled(PrivateLinkService))
+	assert.True(t, featureGate.Enabled(IPBasedBackendPool))
+
+	featureGate, err = New(map[string]bool{string(PrivateLinkService): false, string(IPBasedBackendPool): false})
+	assert.NoError(t, err)
+	assert.False(t, featureGate.Enabled(PrivateLinkService))
+	assert.False(t, featureGate.Enabled(IPBasedBackendPool))
+
+	// the overrides take precedence over the feature gates of the cloud config
+	assert.NoError(t, SetOverrides(map[string]bool{string(IPBasedBackendPool): true}))
+	featureGate, err = New(map[string]bool{string(PrivateLinkService): false, string(IPBasedBackendPool): false})
+	assert.NoError(t, err)
+	assert.False(t, featureGate.Enabled(PrivateLinkService))
+	assert.True(t, featureGate.Enabled(IPBasedBackendPool))
+
+	_, err = New(map[string]bool{"Unknown": true})
+	assert.ErrorContains(t, err, "invalid azureFeatureGates")
+	assert.Error(t, SetOverrides(map[string]bool{"Unknown": true}))
+}
+
+func TestEnabled(t *testing.T) {
+	assert.True(t, Enabled(nil, PrivateLinkService), "the default should be used without a feature gate")
+
+	featureGate, err := New(map[string]bool{string(SecurityRuleUpdates): false})
+	assert.NoError(t, err)
+	assert.False(t, Enabled(featureGate, SecurityRuleUpdates))
+}
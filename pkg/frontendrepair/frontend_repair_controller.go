@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontendrepair implements the controller recreating the load balancer frontends and the public
+// IPs of the services deleted out of band, which otherwise leave the services with invalid ingress IPs
+// until they are changed.
+package frontendrepair
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	// maxRepairBackoff is the max delay between two repairs of a service which fail to repair it.
+	maxRepairBackoff = 2 * time.Hour
+)
+
+var (
+	servicesWithMissingFrontends = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "services_with_missing_frontends",
+			Help:           "Number of the services whose load balancer frontends are found missing by the last check of the frontend repair controller",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	frontendRepairCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "load_balancer_frontend_repairs_total",
+			Help:           "Number of the reconciliations of the services whose load balancer frontends are missing",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+
+	registerMetricsOnce sync.Once
+)
+
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(servicesWithMissingFrontends)
+		legacyregistry.MustRegister(frontendRepairCount)
+	})
+}
+
+// Repairer finds and repairs the services whose load balancer frontends are missing.
+type Repairer interface {
+	// GetServicesWithMissingFrontends returns the ingress IPs of the services which are not found on
+	// the frontends of their load balancers, by the keys of the services.
+	GetServicesWithMissingFrontends(ctx context.Context, clusterName string) (map[string][]string, error)
+	// RepairServiceFrontend recreates the missing frontends of the service, and updates the status of
+	// the service if its ingress IPs are reallocated. It does nothing if the frontends exist.
+	RepairServiceFrontend(ctx context.Context, clusterName, key string, nodes []*v1.Node) error
+}
+
+// Controller checks the frontends of the services every sync period, and repairs the services found with
+// missing frontends by two checks in a row, so the services being reconciled by the service controller
+// are not repaired. The repairs of a service which keep failing are backed off exponentially.
+type Controller struct {
+	repairer              Repairer
+	clusterName           string
+	syncPeriod            time.Duration
+	nodeLister            corelisters.NodeLister
+	nodeInformerSynced    cache.InformerSynced
+	serviceInformerSynced cache.InformerSynced
+
+	// missing are the ingress IPs of the services with missing frontends found by the last check.
+	missing map[string][]string
+	backoff *flowcontrol.Backoff
+}
+
+// NewController creates a new Controller.
+func NewController(
+	nodeInformer coreinformers.NodeInformer,
+	serviceInformerSynced cache.InformerSynced,
+	repairer Repairer,
+	clusterName string,
+	syncPeriod time.Duration,
+) *Controller {
+	return &Controller{
+		repairer:              repairer,
+		clusterName:           clusterName,
+		syncPeriod:            syncPeriod,
+		nodeLister:            nodeInformer.Lister(),
+		nodeInformerSynced:    nodeInformer.Informer().HasSynced,
+		serviceInformerSynced: serviceInformerSynced,
+		missing:               map[string][]string{},
+		backoff:               flowcontrol.NewBackOff(syncPeriod, maxRepairBackoff),
+	}
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.Info("Starting load balancer frontend repair controller")
+	defer klog.Info("Shutting down load balancer frontend repair controller")
+
+	if !cache.WaitForNamedCacheSync("load-balancer-frontend-repair", ctx.Done(), c.nodeInformerSynced, c.serviceInformerSynced) {
+		return
+	}
+	registerMetrics()
+
+	wait.UntilWithContext(ctx, c.sync, c.syncPeriod)
+}
+
+// sync checks the frontends of the services, and repairs the services whose frontends are still missing
+// since the last check.
+func (c *Controller) sync(ctx context.Context) {
+	missing, err := c.repairer.GetServicesWithMissingFrontends(ctx, c.clusterName)
+	if err != nil {
+		klog.Errorf("Failed to check the load balancer frontends of the services: %v", err)
+		return
+	}
+
+	servicesWithMissingFrontends.Set(float64(len(missing)))
+	for key := range c.missing {
+		if _, found := missing[key]; !found {
+			c.backoff.DeleteEntry(key)
+		}
+	}
+
+	var nodes []*v1.Node
+	now := c.backoff.Clock.Now()
+	for key, ips := range missing {
+		if _, found := c.missing[key]; !found {
+			klog.V(2).Infof("The load balancer frontends of ingress IPs %v of service %s are missing, they are repaired if they are still missing in the next check", ips, key)
+			continue
+		}
+		if c.backoff.IsInBackOffSinceUpdate(key, now) {
+			klog.V(4).Infof("Skipping the repair of service %s in backoff", key)
+			continue
+		}
+		if nodes == nil {
+			if nodes, err = c.nodeLister.List(labels.Everything()); err != nil {
+				klog.Errorf("Failed to list the nodes: %v", err)
+				break
+			}
+		}
+
+		klog.V(2).Infof("Repairing service %s whose load balancer frontends of ingress IPs %v are missing", key, ips)
+		result := "succeeded"
+		if err := c.repairer.RepairServiceFrontend(ctx, c.clusterName, key, nodes); err != nil {
+			klog.Errorf("Failed to repair the load balancer frontends of service %s: %v", key, err)
+			result = "failed"
+		}
+		// the repair is backed off until the frontends are found, which also stops the repairs of the
+		// services whose reconciliations succeed but don't recreate the frontends
+		c.backoff.Next(key, now)
+		frontendRepairCount.WithLabelValues(result).Inc()
+	}
+	c.missing = missing
+	c.backoff.GC()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontendrepair
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/flowcontrol"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeRepairer struct {
+	missing   map[string][]string
+	repairErr error
+	repaired  []string
+	nodes     []*v1.Node
+}
+
+func (r *fakeRepairer) GetServicesWithMissingFrontends(_ context.Context, clusterName string) (map[string][]string, error) {
+	if clusterName != "kubernetes" {
+		return nil, errors.New("unexpected cluster name")
+	}
+	return r.missing, nil
+}
+
+func (r *fakeRepairer) RepairServiceFrontend(_ context.Context, _, key string, nodes []*v1.Node) error {
+	r.repaired = append(r.repaired, key)
+	r.nodes = nodes
+	return r.repairErr
+}
+
+func newTestController(t *testing.T, repairer Repairer) (*Controller, *testingclock.FakeClock) {
+	registerMetrics()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(node), 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	assert.NoError(t, nodeInformer.Informer().GetIndexer().Add(node))
+
+	clock := testingclock.NewFakeClock(time.Now())
+	c := NewController(nodeInformer, func() bool { return true }, repairer, "kubernetes", time.Minute)
+	c.backoff = flowcontrol.NewFakeBackOff(time.Minute, maxRepairBackoff, clock)
+	return c, clock
+}
+
+func TestSyncRepairsServicesWithPersistentlyMissingFrontends(t *testing.T) {
+	repairer := &fakeRepairer{missing: map[string][]string{"default/svc": {"1.2.3.4"}}}
+	c, clock := newTestController(t, repairer)
+
+	// the service is repaired if its frontends are still missing in the next check
+	c.sync(context.Background())
+	assert.Empty(t, repairer.repaired)
+
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"default/svc"}, repairer.repaired)
+	assert.Len(t, repairer.nodes, 1)
+
+	// the service whose frontends are found is not repaired, and is checked again from scratch
+	repairer.missing = map[string][]string{"default/other": {"1.2.3.5"}}
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"default/svc"}, repairer.repaired)
+
+	repairer.missing = map[string][]string{"default/svc": {"1.2.3.4"}}
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"default/svc", "default/svc"}, repairer.repaired)
+}
+
+func TestSyncBacksOffRepairs(t *testing.T) {
+	repairer := &fakeRepairer{missing: map[string][]string{"default/svc": {"1.2.3.4"}}, repairErr: errors.New("error")}
+	c, clock := newTestController(t, repairer)
+
+	c.sync(context.Background())
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, 1, len(repairer.repaired))
+
+	// the first repair is backed off by a sync period, and the next ones twice as long as the previous ones
+	for _, step := range []time.Duration{30 * time.Second, 30 * time.Second, time.Minute, time.Minute} {
+		clock.Step(step)
+		c.sync(context.Background())
+	}
+	assert.Equal(t, 3, len(repairer.repaired))
+}
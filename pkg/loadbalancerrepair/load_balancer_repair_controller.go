@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loadbalancerrepair implements the controller repairing the unhealthy load balancers,
+// which otherwise stay unhealthy until a service change triggers their reconciliation.
+package loadbalancerrepair
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	// maxRepairBackoff is the max delay between two repairs of a load balancer which fail to repair it.
+	maxRepairBackoff = 2 * time.Hour
+)
+
+var (
+	unhealthyLoadBalancers = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "unhealthy_load_balancers",
+			Help:           "Unhealthy load balancers found by the last check of the load balancer repair controller",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"load_balancer", "reason"},
+	)
+	loadBalancerRepairCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "load_balancer_repairs_total",
+			Help:           "Number of the corrective updates of the unhealthy load balancers",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"load_balancer", "reason", "result"},
+	)
+
+	registerMetricsOnce sync.Once
+)
+
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(unhealthyLoadBalancers)
+		legacyregistry.MustRegister(loadBalancerRepairCount)
+	})
+}
+
+// Repairer finds and repairs the unhealthy load balancers.
+type Repairer interface {
+	// GetUnhealthyLoadBalancers returns the reasons of the unhealthy load balancers of the cluster
+	// by their names.
+	GetUnhealthyLoadBalancers(ctx context.Context, clusterName string) (map[string]string, error)
+	// RepairLoadBalancer issues a corrective update of the load balancer. It does nothing if the
+	// load balancer is healthy.
+	RepairLoadBalancer(ctx context.Context, name string) error
+}
+
+// Controller checks the load balancers of the cluster every sync period, and repairs the ones found
+// unhealthy by two checks in a row, so the transient states of the load balancers being reconciled
+// are not repaired. The repairs of a load balancer which keep failing are backed off exponentially.
+type Controller struct {
+	repairer        Repairer
+	clusterName     string
+	syncPeriod      time.Duration
+	informersSynced []cache.InformerSynced
+
+	// unhealthy are the unhealthy load balancers found by the last check.
+	unhealthy map[string]string
+	backoff   *flowcontrol.Backoff
+}
+
+// NewController creates a new Controller. The load balancers are checked after the informers the
+// repairer reads are synced.
+func NewController(repairer Repairer, clusterName string, syncPeriod time.Duration, informersSynced ...cache.InformerSynced) *Controller {
+	return &Controller{
+		repairer:        repairer,
+		clusterName:     clusterName,
+		syncPeriod:      syncPeriod,
+		informersSynced: informersSynced,
+		unhealthy:       map[string]string{},
+		backoff:         flowcontrol.NewBackOff(syncPeriod, maxRepairBackoff),
+	}
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.Info("Starting load balancer repair controller")
+	defer klog.Info("Shutting down load balancer repair controller")
+
+	if !cache.WaitForNamedCacheSync("load-balancer-repair", ctx.Done(), c.informersSynced...) {
+		return
+	}
+	registerMetrics()
+
+	wait.UntilWithContext(ctx, c.sync, c.syncPeriod)
+}
+
+// sync checks the load balancers, and repairs the ones which are still unhealthy since the last check.
+func (c *Controller) sync(ctx context.Context) {
+	unhealthy, err := c.repairer.GetUnhealthyLoadBalancers(ctx, c.clusterName)
+	if err != nil {
+		klog.Errorf("Failed to check the load balancers: %v", err)
+		return
+	}
+
+	unhealthyLoadBalancers.Reset()
+	for name, reason := range unhealthy {
+		unhealthyLoadBalancers.WithLabelValues(name, reason).Set(1)
+	}
+	for name := range c.unhealthy {
+		if _, found := unhealthy[name]; !found {
+			klog.V(2).Infof("Load balancer %s is healthy again", name)
+			c.backoff.DeleteEntry(name)
+		}
+	}
+
+	now := c.backoff.Clock.Now()
+	for name, reason := range unhealthy {
+		if _, found := c.unhealthy[name]; !found {
+			klog.V(2).Infof("Load balancer %s is unhealthy: %s, it is repaired if it is still unhealthy in the next check", name, reason)
+			continue
+		}
+		if c.backoff.IsInBackOffSinceUpdate(name, now) {
+			klog.V(4).Infof("Skipping the repair of load balancer %s in backoff", name)
+			continue
+		}
+
+		klog.V(2).Infof("Repairing load balancer %s which is unhealthy: %s", name, reason)
+		result := "succeeded"
+		if err := c.repairer.RepairLoadBalancer(ctx, name); err != nil {
+			klog.Errorf("Failed to repair load balancer %s: %v", name, err)
+			result = "failed"
+		}
+		// the repair is backed off until the load balancer is found healthy, which also stops
+		// the repairs of the load balancers whose updates succeed but don't fix them
+		c.backoff.Next(name, now)
+		loadBalancerRepairCount.WithLabelValues(name, reason, result).Inc()
+	}
+	c.unhealthy = unhealthy
+	c.backoff.GC()
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancerrepair
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/flowcontrol"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeRepairer struct {
+	unhealthy map[string]string
+	repairErr error
+	repaired  []string
+}
+
+func (r *fakeRepairer) GetUnhealthyLoadBalancers(_ context.Context, clusterName string) (map[string]string, error) {
+	if clusterName != "kubernetes" {
+		return nil, errors.New("unexpected cluster name")
+	}
+	return r.unhealthy, nil
+}
+
+func (r *fakeRepairer) RepairLoadBalancer(_ context.Context, name string) error {
+	r.repaired = append(r.repaired, name)
+	return r.repairErr
+}
+
+func newTestController(repairer Repairer) (*Controller, *testingclock.FakeClock) {
+	registerMetrics()
+	clock := testingclock.NewFakeClock(time.Now())
+	c := NewController(repairer, "kubernetes", time.Minute)
+	c.backoff = flowcontrol.NewFakeBackOff(time.Minute, maxRepairBackoff, clock)
+	return c, clock
+}
+
+func TestSyncRepairsPersistentlyUnhealthyLoadBalancers(t *testing.T) {
+	repairer := &fakeRepairer{unhealthy: map[string]string{"kubernetes": "failed"}}
+	c, clock := newTestController(repairer)
+
+	// the load balancer is repaired if it is still unhealthy in the next check
+	c.sync(context.Background())
+	assert.Empty(t, repairer.repaired)
+
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"kubernetes"}, repairer.repaired)
+
+	// the load balancer found healthy is not repaired, and is checked again from scratch
+	repairer.unhealthy = map[string]string{"kubernetes-internal": "orphaned_resources"}
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"kubernetes"}, repairer.repaired)
+
+	repairer.unhealthy = map[string]string{"kubernetes": "failed"}
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, []string{"kubernetes", "kubernetes"}, repairer.repaired)
+}
+
+func TestSyncBacksOffRepairs(t *testing.T) {
+	repairer := &fakeRepairer{unhealthy: map[string]string{"kubernetes": "failed"}, repairErr: errors.New("error")}
+	c, clock := newTestController(repairer)
+
+	c.sync(context.Background())
+	clock.Step(time.Minute)
+	c.sync(context.Background())
+	assert.Equal(t, 1, len(repairer.repaired))
+
+	// the first repair is backed off by a sync period, and the next ones twice as long as the previous ones
+	for _, step := range []time.Duration{30 * time.Second, 30 * time.Second, time.Minute, time.Minute} {
+		clock.Step(step)
+		c.sync(context.Background())
+	}
+	assert.Equal(t, 3, len(repairer.repaired))
+}
@@ -27,9 +27,14 @@ type Format string
 const (
 	JSON Format = "json"
 	Text Format = "text"
+	// Logfmt writes the logs as key=value pairs, see https://brandur.org/logfmt.
+	Logfmt Format = "logfmt"
 )
 
 const (
+	// flagLoggingFormat is named after the flag of the logging options of kubernetes component-base.
+	flagLoggingFormat = "logging-format"
+	// flagLogFormat is deprecated in favor of flagLoggingFormat.
 	flagLogFormat         = "log-format"
 	flagLogFlushFrequency = "log-flush-frequency"
 
@@ -38,7 +43,9 @@ const (
 )
 
 func BindCLIFlags(fs *pflag.FlagSet) {
-	fs.String(flagLogFormat, "text", "The log format to use. One of: text, json.")
+	fs.String(flagLoggingFormat, string(DefaultFormat), "The log format to use. One of: text, json, logfmt.")
+	fs.String(flagLogFormat, string(DefaultFormat), "The log format to use. One of: text, json, logfmt.")
+	_ = fs.MarkDeprecated(flagLogFormat, "use --"+flagLoggingFormat+" instead")
 	// log-flush-frequency is registered in kubernetes component-base.
 }
 
@@ -50,16 +57,21 @@ type Options struct {
 func OptionsFromCLIFlags(fs *pflag.FlagSet) *Options {
 	var o Options
 
-	o.Format = Format(fs.Lookup(flagLogFormat).Value.String())
-	if o.Format != JSON && o.Format != Text {
-		SetupError("Invalid log format", flagLogFormat, o.Format)
+	// The deprecated flag is honored only if the new one is not set.
+	formatFlag := fs.Lookup(flagLoggingFormat)
+	if deprecated := fs.Lookup(flagLogFormat); deprecated != nil && deprecated.Changed && !formatFlag.Changed {
+		formatFlag = deprecated
+	}
+	o.Format = Format(formatFlag.Value.String())
+	if o.Format != JSON && o.Format != Text && o.Format != Logfmt {
+		SetupError("Invalid log format", formatFlag.Name, o.Format)
 		o.Format = DefaultFormat
 	}
 
 	freq, err := fs.GetDuration(flagLogFlushFrequency)
 	if err != nil {
 		SetupError("Invalid log flush frequency", flagLogFlushFrequency, err)
-		o.FlushFrequency = DefaultFlushFrequency
+		freq = DefaultFlushFrequency
 	}
 	o.FlushFrequency = freq
 
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsFromCLIFlags(t *testing.T) {
+	for _, tc := range []struct {
+		desc           string
+		args           []string
+		expectedFormat Format
+	}{
+		{
+			desc:           "the text format is used by default",
+			expectedFormat: Text,
+		},
+		{
+			desc:           "the logfmt format is selected by --logging-format",
+			args:           []string{"--logging-format=logfmt"},
+			expectedFormat: Logfmt,
+		},
+		{
+			desc:           "the deprecated --log-format is honored",
+			args:           []string{"--log-format=json"},
+			expectedFormat: JSON,
+		},
+		{
+			desc:           "--logging-format takes precedence over the deprecated --log-format",
+			args:           []string{"--log-format=json", "--logging-format=logfmt"},
+			expectedFormat: Logfmt,
+		},
+		{
+			desc:           "an invalid format falls back to the default",
+			args:           []string{"--logging-format=yaml"},
+			expectedFormat: DefaultFormat,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			fs.Duration(flagLogFlushFrequency, time.Second, "")
+			BindCLIFlags(fs)
+			assert.NoError(t, fs.Parse(tc.args))
+
+			opts := OptionsFromCLIFlags(fs)
+			assert.Equal(t, tc.expectedFormat, opts.Format)
+			assert.Equal(t, time.Second, opts.FlushFrequency)
+		})
+	}
+}
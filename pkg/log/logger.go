@@ -76,11 +76,7 @@ func Setup(opts *Options) {
 	log.SetFlags(0)
 	klog.StartFlushDaemon(opts.FlushFrequency)
 
-	if opts.Format != JSON {
-		return
-	}
-
-	logger := slog.New(NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	handlerOpts := &slog.HandlerOptions{
 		Level:     slog.Level(-127), // Set to minimum level; actual filtering is handled by klog.V(N)
 		AddSource: true,
 		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
@@ -92,8 +88,18 @@ func Setup(opts *Options) {
 				return attr
 			}
 		},
-	}))
+	}
+	var handler slog.Handler
+	switch opts.Format {
+	case JSON:
+		handler = NewJSONHandler(os.Stdout, handlerOpts)
+	case Logfmt:
+		handler = NewLogfmtHandler(os.Stdout, handlerOpts)
+	default:
+		return
+	}
 
+	logger := slog.New(handler)
 	slog.SetDefault(logger)
 	klog.SetSlogLogger(logger)
 }
@@ -29,7 +29,7 @@ const (
 )
 
 type SLogJSONHandler struct {
-	handler *slog.JSONHandler
+	handler slog.Handler
 }
 
 func NewJSONHandler(w io.Writer, opts *slog.HandlerOptions) *SLogJSONHandler {
@@ -43,8 +43,46 @@ func (h *SLogJSONHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *SLogJSONHandler) Handle(ctx context.Context, record slog.Record) error {
-	// Extract verbosity from negative log levels and set it as an attribute.
-	// This is necessary because slog will convert negative levels to DEBUG+N.
+	return h.handler.Handle(ctx, withVerbosity(record))
+}
+
+func (h *SLogJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SLogJSONHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *SLogJSONHandler) WithGroup(name string) slog.Handler {
+	return &SLogJSONHandler{handler: h.handler.WithGroup(name)}
+}
+
+type SLogLogfmtHandler struct {
+	handler slog.Handler
+}
+
+func NewLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) *SLogLogfmtHandler {
+	return &SLogLogfmtHandler{
+		handler: slog.NewTextHandler(w, opts),
+	}
+}
+
+func (h *SLogLogfmtHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SLogLogfmtHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, withVerbosity(record))
+}
+
+func (h *SLogLogfmtHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SLogLogfmtHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *SLogLogfmtHandler) WithGroup(name string) slog.Handler {
+	return &SLogLogfmtHandler{handler: h.handler.WithGroup(name)}
+}
+
+// withVerbosity extracts the verbosity from the negative log levels and sets it as an attribute.
+// This is necessary because slog will convert negative levels to DEBUG+N.
+func withVerbosity(record slog.Record) slog.Record {
 	if record.Level < 0 {
 		verbosity := int(-record.Level)
 		record.Level = slog.LevelInfo
@@ -52,14 +90,5 @@ func (h *SLogJSONHandler) Handle(ctx context.Context, record slog.Record) error
 	} else {
 		record.AddAttrs(slog.Int(VerbosityKey, 0))
 	}
-
-	return h.handler.Handle(ctx, record)
-}
-
-func (h *SLogJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return h.handler.WithAttrs(attrs)
-}
-
-func (h *SLogJSONHandler) WithGroup(name string) slog.Handler {
-	return h.handler.WithGroup(name)
+	return record
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLogHandlersWithValues(t *testing.T) {
+	opts := &slog.HandlerOptions{
+		Level: slog.Level(-127),
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}
+	for _, tc := range []struct {
+		desc       string
+		newHandler func(w io.Writer) slog.Handler
+		expected   string
+	}{
+		{
+			desc:       "json",
+			newHandler: func(w io.Writer) slog.Handler { return NewJSONHandler(w, opts) },
+			expected:   `{"level":"INFO","msg":"synced","service":"default/svc","logger":"controller","nodes":3,"v":2}` + "\n",
+		},
+		{
+			desc:       "logfmt",
+			newHandler: func(w io.Writer) slog.Handler { return NewLogfmtHandler(w, opts) },
+			expected:   "level=INFO msg=synced service=default/svc logger=controller nodes=3 v=2\n",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logr.FromSlogHandler(tc.newHandler(&buf))
+
+			// the handlers derived by WithValues and WithName keep the verbosity
+			logger.WithValues("service", "default/svc").WithName("controller").V(2).Info("synced", "nodes", 3)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+var (
+	workqueueMetricsProvidersLock sync.Mutex
+	workqueueMetricsProviders     = map[string]*workqueueMetricsProvider{}
+)
+
+// workqueueMetricsProvider provides the standard workqueue metrics under the subsystem of a controller, so the
+// backlog of the controller can be told apart from the queues of the other controllers with the same names.
+type workqueueMetricsProvider struct {
+	depth                   *metrics.GaugeVec
+	adds                    *metrics.CounterVec
+	latency                 *metrics.HistogramVec
+	workDuration            *metrics.HistogramVec
+	unfinished              *metrics.GaugeVec
+	longestRunningProcessor *metrics.GaugeVec
+	retries                 *metrics.CounterVec
+}
+
+// NewWorkqueueMetricsProvider returns the workqueue metrics provider of the controller, the metrics are named
+// cloudprovider_azure_<subsystem>_workqueue_<key> after the keys of the standard workqueue metrics and labeled
+// by the names of the queues. The metrics of a subsystem are registered once and shared by its queues.
+func NewWorkqueueMetricsProvider(subsystem string) workqueue.MetricsProvider {
+	workqueueMetricsProvidersLock.Lock()
+	defer workqueueMetricsProvidersLock.Unlock()
+
+	if provider, ok := workqueueMetricsProviders[subsystem]; ok {
+		return provider
+	}
+	provider := newWorkqueueMetricsProvider(subsystem + "_workqueue")
+	legacyregistry.MustRegister(
+		provider.depth,
+		provider.adds,
+		provider.latency,
+		provider.workDuration,
+		provider.unfinished,
+		provider.longestRunningProcessor,
+		provider.retries,
+	)
+	workqueueMetricsProviders[subsystem] = provider
+	return provider
+}
+
+func newWorkqueueMetricsProvider(subsystem string) *workqueueMetricsProvider {
+	return &workqueueMetricsProvider{
+		depth: metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "depth",
+			Help:           "Current depth of the workqueue",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		adds: metrics.NewCounterVec(&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "adds_total",
+			Help:           "Total number of adds handled by the workqueue",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		latency: metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "queue_duration_seconds",
+			Help:           "How long in seconds an item stays in the workqueue before being requested",
+			Buckets:        metrics.ExponentialBuckets(10e-9, 10, 12),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		workDuration: metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "work_duration_seconds",
+			Help:           "How long in seconds processing an item from the workqueue takes",
+			Buckets:        metrics.ExponentialBuckets(10e-9, 10, 12),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		unfinished: metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "unfinished_work_seconds",
+			Help:           "How many seconds of work is in progress and hasn't been observed by work_duration",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		longestRunningProcessor: metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "longest_running_processor_seconds",
+			Help:           "How many seconds has the longest running processor of the workqueue been running",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+		retries: metrics.NewCounterVec(&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Subsystem:      subsystem,
+			Name:           "retries_total",
+			Help:           "Total number of retries handled by the workqueue",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"name"}),
+	}
+}
+
+// NewDepthMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+// NewAddsMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+// NewLatencyMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+// NewWorkDurationMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+// NewUnfinishedWorkSecondsMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinished.WithLabelValues(name)
+}
+
+// NewLongestRunningProcessorSecondsMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunningProcessor.WithLabelValues(name)
+}
+
+// NewRetriesMetric implements workqueue.MetricsProvider.
+func (p *workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
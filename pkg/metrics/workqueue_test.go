@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestNewWorkqueueMetricsProvider(t *testing.T) {
+	provider := NewWorkqueueMetricsProvider("test_controller")
+	// the metrics of a subsystem are registered once
+	assert.Same(t, provider, NewWorkqueueMetricsProvider("test_controller"))
+
+	queue := workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[string]{
+		Name:            "test",
+		MetricsProvider: provider,
+	})
+	defer queue.ShutDown()
+	metricsProvider := provider.(*workqueueMetricsProvider)
+
+	queue.Add("item")
+	depth, err := testutil.GetGaugeMetricValue(metricsProvider.depth.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), depth)
+	adds, err := testutil.GetCounterMetricValue(metricsProvider.adds.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), adds)
+
+	item, _ := queue.Get()
+	queue.Done(item)
+	depth, err = testutil.GetGaugeMetricValue(metricsProvider.depth.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), depth)
+	count, err := testutil.GetHistogramMetricCount(metricsProvider.latency.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+	count, err = testutil.GetHistogramMetricCount(metricsProvider.workDuration.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	// the queues of another subsystem are reported separately
+	other := NewWorkqueueMetricsProvider("other_controller").(*workqueueMetricsProvider)
+	adds, err = testutil.GetCounterMetricValue(other.adds.WithLabelValues("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), adds)
+}
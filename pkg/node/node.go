@@ -33,11 +33,28 @@ type IMDSNodeProvider struct {
 	azure *azureprovider.Cloud
 }
 
+// NodeAddressOptions are the options of the node addresses. The IMDS node provider takes them from
+// the flags of cloud-node-manager since it runs without a cloud config file.
+type NodeAddressOptions struct {
+	// Source is the nodeAddressSource of the cloud config.
+	Source string
+	// Order is the nodeAddressOrder of the cloud config.
+	Order string
+	// IPFamilies is the nodeAddressIPFamilies of the cloud config.
+	IPFamilies string
+	// ExcludePublicIPs is the excludeNodePublicIPs of the cloud config.
+	ExcludePublicIPs bool
+}
+
 // NewIMDSNodeProvider creates a new IMDSNodeProvider.
-func NewIMDSNodeProvider(ctx context.Context) *IMDSNodeProvider {
+func NewIMDSNodeProvider(ctx context.Context, addressOptions NodeAddressOptions) *IMDSNodeProvider {
 	az, err := azureprovider.NewCloud(ctx, nil, &config.Config{
-		UseInstanceMetadata: true,
-		VMType:              "vmss",
+		UseInstanceMetadata:   true,
+		VMType:                "vmss",
+		NodeAddressSource:     addressOptions.Source,
+		NodeAddressOrder:      addressOptions.Order,
+		NodeAddressIPFamilies: addressOptions.IPFamilies,
+		ExcludeNodePublicIPs:  addressOptions.ExcludePublicIPs,
 	}, false)
 	if err != nil {
 		klog.Fatalf("Failed to initialize Azure cloud provider: %v", err)
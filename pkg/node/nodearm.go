@@ -75,8 +75,9 @@ func (np *ARMNodeProvider) InstanceType(ctx context.Context, name types.NodeName
 // In most cases, this method is called from the kubelet querying a local metadata service to acquire its zone.
 // If the node is not running with availability zones, then it will fall back to fault domain.
 func (np *ARMNodeProvider) GetZone(ctx context.Context, name types.NodeName) (cloudprovider.Zone, error) {
-	// Needed for cloud-node-manager on windows nodes where hostname of the pod is different from node name
-	if runtime.GOOS == "windows" {
+	// Needed for cloud-node-manager on windows nodes where hostname of the pod is different from node name,
+	// unless the zone is read from IMDS in the instance metadata only mode.
+	if runtime.GOOS == "windows" && !np.azure.InstanceMetadataOnly {
 		return np.azure.GetZoneByNodeName(ctx, name)
 	}
 
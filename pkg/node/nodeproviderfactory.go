@@ -22,12 +22,13 @@ import (
 	nodemanager "sigs.k8s.io/cloud-provider-azure/pkg/nodemanager"
 )
 
-// NewNodeProvider returns a node provider depending on the use case
-func NewNodeProvider(ctx context.Context, useMetadata bool, cloudConfigFilePath string) nodemanager.NodeProvider {
+// NewNodeProvider returns a node provider depending on the use case. The address options only apply
+// to the IMDS node provider, the ARM node provider reads them from the cloud config file.
+func NewNodeProvider(ctx context.Context, useMetadata bool, cloudConfigFilePath string, addressOptions NodeAddressOptions) nodemanager.NodeProvider {
 	var nodeProvider nodemanager.NodeProvider
 
 	if useMetadata {
-		nodeProvider = NewIMDSNodeProvider(ctx)
+		nodeProvider = NewIMDSNodeProvider(ctx, addressOptions)
 	} else {
 		nodeProvider = NewARMNodeProvider(ctx, cloudConfigFilePath)
 	}
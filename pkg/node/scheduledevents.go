@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodemanager"
+	azureprovider "sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// IMDSScheduledEventsProvider implements nodemanager.ScheduledEventsProvider.
+type IMDSScheduledEventsProvider struct {
+	metadata *azureprovider.InstanceMetadataService
+}
+
+// NewIMDSScheduledEventsProvider creates a new IMDSScheduledEventsProvider.
+func NewIMDSScheduledEventsProvider() (*IMDSScheduledEventsProvider, error) {
+	return newIMDSScheduledEventsProvider(consts.ImdsServer)
+}
+
+func newIMDSScheduledEventsProvider(imdsServer string) (*IMDSScheduledEventsProvider, error) {
+	metadata, err := azureprovider.NewInstanceMetadataService(imdsServer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IMDSScheduledEventsProvider{
+		metadata: metadata,
+	}, nil
+}
+
+// GetScheduledEvents returns the scheduled events of the VM the program is running on.
+// IMDS returns the events of all VMs in the same availability set or scale set placement
+// group, so the events are filtered by the name of the VM.
+func (p *IMDSScheduledEventsProvider) GetScheduledEvents(ctx context.Context) ([]nodemanager.ScheduledEvent, error) {
+	metadata, err := p.metadata.GetMetadata(ctx, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Compute == nil || metadata.Compute.Name == "" {
+		return nil, errors.New("failure of getting the vm name from instance metadata")
+	}
+
+	scheduledEvents, err := p.metadata.GetScheduledEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []nodemanager.ScheduledEvent
+	for _, event := range scheduledEvents.Events {
+		for _, resource := range event.Resources {
+			if strings.EqualFold(resource, metadata.Compute.Name) {
+				events = append(events, nodemanager.ScheduledEvent{
+					ID:          event.EventID,
+					Type:        event.EventType,
+					NotBefore:   event.NotBefore,
+					Description: event.Description,
+				})
+				break
+			}
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodeannotator implements the controller labeling the nodes with the hardware
+// details of their VMs.
+package nodeannotator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/priorityqueue"
+)
+
+// HardwareLabelsProvider returns the labels describing the hardware of the VM of a node.
+type HardwareLabelsProvider interface {
+	// GetNodeHardwareLabels returns the hardware labels of the node. It returns nil if the
+	// node is not managed by the cloud provider.
+	GetNodeHardwareLabels(ctx context.Context, node *v1.Node) (map[string]string, error)
+}
+
+// Controller labels the nodes with the hardware details of their VMs, so the schedulers and
+// the autoscalers can target the hardware without a DaemonSet. The nodes are labeled when they
+// are added or their provider IDs change, and again after every sync period.
+type Controller struct {
+	kubeClient         clientset.Interface
+	nodeLister         corelisters.NodeLister
+	nodeInformerSynced cache.InformerSynced
+	labelsProvider     HardwareLabelsProvider
+
+	// labelKeys are the keys of the labels managed by the controller. The managed labels not
+	// returned by the provider are removed from the node.
+	labelKeys  []string
+	syncPeriod time.Duration
+	queue      priorityqueue.RateLimitingInterface[string]
+}
+
+// NewController creates a new Controller.
+func NewController(
+	nodeInformer coreinformers.NodeInformer,
+	kubeClient clientset.Interface,
+	labelsProvider HardwareLabelsProvider,
+	labelKeys []string,
+	syncPeriod time.Duration) *Controller {
+	c := &Controller{
+		kubeClient:         kubeClient,
+		nodeLister:         nodeInformer.Lister(),
+		nodeInformerSynced: nodeInformer.Informer().HasSynced,
+		labelsProvider:     labelsProvider,
+		labelKeys:          labelKeys,
+		syncPeriod:         syncPeriod,
+		queue:              priorityqueue.NewRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string](), "node-annotator", metrics.NewWorkqueueMetricsProvider("node_annotator_controller")),
+	}
+
+	_, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		// the new nodes and the nodes whose VMs changed are labeled before the periodic relabeling
+		AddFunc: func(obj interface{}) {
+			if node, ok := obj.(*v1.Node); ok {
+				c.queue.AddWithPriority(node.Name, priorityqueue.PriorityHigh)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, ok := oldObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := newObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			if oldNode.Spec.ProviderID != newNode.Spec.ProviderID {
+				c.queue.AddWithPriority(newNode.Name, priorityqueue.PriorityHigh)
+			}
+		},
+	})
+
+	return c
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting node annotator controller")
+	defer klog.Info("Shutting down node annotator controller")
+
+	if !cache.WaitForNamedCacheSync("node-annotator", ctx.Done(), c.nodeInformerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	// relabel all nodes periodically, the hardware of a VM can change after it is resized
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		nodes, err := c.nodeLister.List(labels.Everything())
+		if err != nil {
+			klog.Errorf("Failed to list nodes: %v", err)
+			return
+		}
+		for _, node := range nodes {
+			c.queue.AddWithPriority(node.Name, priorityqueue.PriorityLow)
+		}
+	}, c.syncPeriod)
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	nodeName, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(nodeName)
+
+	if err := c.syncNode(ctx, nodeName); err != nil {
+		klog.Errorf("Failed to sync the hardware labels of node %s: %v", nodeName, err)
+		c.queue.AddRateLimited(nodeName)
+		return true
+	}
+	c.queue.Forget(nodeName)
+	return true
+}
+
+// syncNode patches the hardware labels of the node.
+func (c *Controller) syncNode(ctx context.Context, nodeName string) error {
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	expected, err := c.labelsProvider.GetNodeHardwareLabels(ctx, node)
+	if err != nil {
+		return err
+	}
+	if expected == nil {
+		klog.V(4).Infof("Skipping node %s which is not managed by the cloud provider", nodeName)
+		return nil
+	}
+
+	labelsToPatch := make(map[string]interface{})
+	for _, key := range c.labelKeys {
+		value, expectedOK := expected[key]
+		current, currentOK := node.Labels[key]
+		switch {
+		case expectedOK && (!currentOK || current != value):
+			labelsToPatch[key] = value
+		case !expectedOK && currentOK:
+			// a nil value removes the label
+			labelsToPatch[key] = nil
+		}
+	}
+	if len(labelsToPatch) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labelsToPatch,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("Patching the hardware labels of node %s: %s", nodeName, string(patch))
+	if _, err := c.kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch the labels of node %s: %w", nodeName, err)
+	}
+	return nil
+}
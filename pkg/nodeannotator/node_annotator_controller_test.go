@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeannotator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeHardwareLabelsProvider struct {
+	labels map[string]string
+	err    error
+}
+
+func (p *fakeHardwareLabelsProvider) GetNodeHardwareLabels(_ context.Context, _ *v1.Node) (map[string]string, error) {
+	return p.labels, p.err
+}
+
+func TestSyncNode(t *testing.T) {
+	labelKeys := []string{"example.com/accelerated-networking", "example.com/proximity-placement-group"}
+
+	for _, tc := range []struct {
+		desc           string
+		nodeLabels     map[string]string
+		providerLabels map[string]string
+		providerErr    error
+		expectedLabels map[string]string
+		expectedErr    bool
+	}{
+		{
+			desc:           "should add the hardware labels",
+			nodeLabels:     map[string]string{"foo": "bar"},
+			providerLabels: map[string]string{"example.com/accelerated-networking": "true", "example.com/proximity-placement-group": "ppg"},
+			expectedLabels: map[string]string{"foo": "bar", "example.com/accelerated-networking": "true", "example.com/proximity-placement-group": "ppg"},
+		},
+		{
+			desc:           "should update and remove the hardware labels",
+			nodeLabels:     map[string]string{"foo": "bar", "example.com/accelerated-networking": "false", "example.com/proximity-placement-group": "ppg"},
+			providerLabels: map[string]string{"example.com/accelerated-networking": "true", "example.com/unknown": "value"},
+			expectedLabels: map[string]string{"foo": "bar", "example.com/accelerated-networking": "true"},
+		},
+		{
+			desc:           "should not change unmanaged nodes",
+			nodeLabels:     map[string]string{"example.com/proximity-placement-group": "ppg"},
+			expectedLabels: map[string]string{"example.com/proximity-placement-group": "ppg"},
+		},
+		{
+			desc:           "should return the error of the provider",
+			nodeLabels:     map[string]string{"foo": "bar"},
+			providerErr:    errors.New("arm error"),
+			expectedLabels: map[string]string{"foo": "bar"},
+			expectedErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node0", Labels: tc.nodeLabels}}
+			client := fake.NewSimpleClientset(node)
+			factory := informers.NewSharedInformerFactory(client, 0)
+			nodeInformer := factory.Core().V1().Nodes()
+			assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+
+			c := NewController(nodeInformer, client, &fakeHardwareLabelsProvider{labels: tc.providerLabels, err: tc.providerErr}, labelKeys, time.Hour)
+			err := c.syncNode(context.TODO(), "node0")
+			assert.Equal(t, tc.expectedErr, err != nil)
+
+			updatedNode, err := client.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedLabels, updatedNode.Labels)
+		})
+	}
+
+	t.Run("should ignore nodes not found", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		factory := informers.NewSharedInformerFactory(client, 0)
+		c := NewController(factory.Core().V1().Nodes(), client, &fakeHardwareLabelsProvider{}, labelKeys, time.Hour)
+		assert.NoError(t, c.syncNode(context.TODO(), "node0"))
+	})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodedeletion implements the controller taking the nodes marked for deletion, e.g. by
+// cluster-autoscaler during a scale-down, out of the load balancers and the routes before their VMs
+// are deleted.
+package nodedeletion
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+)
+
+// syncKey is the only key in the queue, since all the marked nodes are reconciled at once.
+const syncKey = "node-deletion"
+
+// Reconciler reconciles the load balancers and the routes of the nodes marked for deletion.
+type Reconciler interface {
+	// IsNodeMarkedForDeletion returns true if the node has any of the node deletion taints.
+	IsNodeMarkedForDeletion(node *v1.Node) bool
+	// ReconcileNodesMarkedForDeletion removes the nodes marked for deletion from the backend pools of the
+	// load balancers and puts the unmarked nodes back, and deletes the routes of the marked nodes if
+	// deleteRoutes is true.
+	ReconcileNodesMarkedForDeletion(ctx context.Context, clusterName string, nodes []*v1.Node, deleteRoutes bool) error
+}
+
+// Controller reconciles the nodes when they are marked for deletion or unmarked, which the node sync of
+// the service controller ignores, so the nodes are taken out of the load balancers before the VMs are
+// deleted. The failed reconciliations are retried with backoff.
+type Controller struct {
+	reconciler            Reconciler
+	clusterName           string
+	deleteRoutes          bool
+	nodeLister            corelisters.NodeLister
+	nodeInformerSynced    cache.InformerSynced
+	serviceInformerSynced cache.InformerSynced
+
+	queue workqueue.TypedRateLimitingInterface[string]
+}
+
+// NewController creates a new Controller.
+func NewController(
+	nodeInformer coreinformers.NodeInformer,
+	serviceInformerSynced cache.InformerSynced,
+	reconciler Reconciler,
+	clusterName string,
+	deleteRoutes bool,
+) *Controller {
+	c := &Controller{
+		reconciler:            reconciler,
+		clusterName:           clusterName,
+		deleteRoutes:          deleteRoutes,
+		nodeLister:            nodeInformer.Lister(),
+		nodeInformerSynced:    nodeInformer.Informer().HasSynced,
+		serviceInformerSynced: serviceInformerSynced,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{
+				Name:            "node-deletion",
+				MetricsProvider: metrics.NewWorkqueueMetricsProvider("node_deletion_controller"),
+			}),
+	}
+
+	_, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: c.updateNode,
+	})
+
+	return c
+}
+
+// updateNode queues a reconciliation if the node is marked for deletion or unmarked. The added and
+// deleted nodes are handled by the node sync of the service controller and the route controller.
+func (c *Controller) updateNode(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := newObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	if c.reconciler.IsNodeMarkedForDeletion(oldNode) != c.reconciler.IsNodeMarkedForDeletion(newNode) {
+		c.queue.Add(syncKey)
+	}
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting node deletion controller")
+	defer klog.Info("Shutting down node deletion controller")
+
+	if !cache.WaitForNamedCacheSync("node-deletion", ctx.Done(), c.nodeInformerSynced, c.serviceInformerSynced) {
+		return
+	}
+
+	// the nodes marked before the start are reconciled
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list the nodes: %v", err)
+	}
+	for _, node := range nodes {
+		if c.reconciler.IsNodeMarkedForDeletion(node) {
+			c.queue.Add(syncKey)
+			break
+		}
+	}
+
+	// a single worker, the marked nodes are reconciled at once
+	wait.UntilWithContext(ctx, c.runWorker, time.Second)
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx); err != nil {
+		klog.Errorf("Failed to reconcile the nodes marked for deletion: %v", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync reconciles the load balancers and the routes with the current nodes.
+func (c *Controller) sync(ctx context.Context) error {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	return c.reconciler.ReconcileNodesMarkedForDeletion(ctx, c.clusterName, nodes, c.deleteRoutes)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedeletion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testTaintKey = "ToBeDeletedByClusterAutoscaler"
+
+type fakeReconciler struct {
+	err          error
+	nodes        [][]string
+	deleteRoutes bool
+}
+
+func (*fakeReconciler) IsNodeMarkedForDeletion(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == testTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeReconciler) ReconcileNodesMarkedForDeletion(_ context.Context, clusterName string, nodes []*v1.Node, deleteRoutes bool) error {
+	if clusterName != "kubernetes" {
+		return errors.New("unexpected cluster name")
+	}
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	r.nodes = append(r.nodes, names)
+	r.deleteRoutes = deleteRoutes
+	return r.err
+}
+
+func TestProcessNextItem(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	reconciler := &fakeReconciler{}
+	c := NewController(nodeInformer, func() bool { return true }, reconciler, "kubernetes", true)
+	defer c.queue.ShutDown()
+
+	// the node events are handled by the informer, which is not started in the test
+	assert.NoError(t, nodeInformer.Informer().GetIndexer().Add(node))
+	c.queue.Add(syncKey)
+	assert.True(t, c.processNextItem(context.Background()))
+	assert.Equal(t, [][]string{{"node-a"}}, reconciler.nodes)
+	assert.True(t, reconciler.deleteRoutes)
+	assert.Equal(t, 0, c.queue.Len())
+
+	// the failed reconciliation is retried
+	reconciler.err = errors.New("failed")
+	c.queue.Add(syncKey)
+	assert.True(t, c.processNextItem(context.Background()))
+	assert.Equal(t, 1, c.queue.NumRequeues(syncKey))
+}
+
+func TestUpdateNode(t *testing.T) {
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	c := NewController(informerFactory.Core().V1().Nodes(), func() bool { return true }, &fakeReconciler{}, "kubernetes", false)
+	defer c.queue.ShutDown()
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	markedNode := node.DeepCopy()
+	markedNode.Spec.Taints = []v1.Taint{{Key: testTaintKey, Effect: v1.TaintEffectNoSchedule}}
+	relabeledNode := node.DeepCopy()
+	relabeledNode.Labels = map[string]string{"foo": "bar"}
+
+	c.updateNode(node, relabeledNode)
+	assert.Equal(t, 0, c.queue.Len())
+
+	c.updateNode(node, markedNode)
+	assert.Equal(t, 1, c.queue.Len())
+	key, _ := c.queue.Get()
+	c.queue.Done(key)
+
+	c.updateNode(markedNode, node)
+	assert.Equal(t, 1, c.queue.Len())
+}
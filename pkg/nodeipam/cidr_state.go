@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeipam
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam"
+)
+
+// CIDRStateHandler exports the pod CIDR assignments of the nodes on GET, e.g. to seed the allocator
+// with --node-cidr-state-file when migrating from the NodeIPAM of kube-controller-manager, so the nodes
+// re-registered during the switchover get their previous pod CIDRs back instead of reallocated ones.
+func (nc *Controller) CIDRStateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		state, err := nc.exportCIDRState()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(state); err != nil {
+			klog.Errorf("CIDRStateHandler: failed to encode the response: %v", err)
+		}
+	})
+}
+
+func (nc *Controller) exportCIDRState() (*ipam.CIDRState, error) {
+	nodes, err := nc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	state := &ipam.CIDRState{Nodes: make([]ipam.NodeCIDRs, 0, len(nodes))}
+	for _, node := range nodes {
+		if len(node.Spec.PodCIDRs) == 0 {
+			continue
+		}
+		state.Nodes = append(state.Nodes, ipam.NodeCIDRs{Name: node.Name, PodCIDRs: node.Spec.PodCIDRs})
+	}
+	sort.Slice(state.Nodes, func(i, j int) bool {
+		return state.Nodes[i].Name < state.Nodes[j].Name
+	})
+	return state, nil
+}
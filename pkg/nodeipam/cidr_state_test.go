@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeipam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	netutils "k8s.io/utils/net"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam"
+	providerazure "sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+func TestCIDRStateHandler(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-d"}, Spec: v1.NodeSpec{PodCIDR: "10.0.5.0/24", PodCIDRs: []string{"10.0.5.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: v1.NodeSpec{PodCIDR: "10.0.1.0/24", PodCIDRs: []string{"10.0.1.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+	}
+	clientSet := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), time.Duration(0))
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	for _, node := range nodes {
+		_, err := clientSet.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+	}
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.0.0.0/16"})
+	nc, err := NewNodeIpamController(nodeInformer, &providerazure.Cloud{}, clientSet, clusterCIDRs, nil, nil, nil, []int{24}, nil, nil, 0, "", "", ipam.RangeAllocatorType, nil)
+	assert.NoError(t, err)
+	handler := nc.CIDRStateHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/controllers/node-ipam", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var state ipam.CIDRState
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+	assert.Equal(t, []ipam.NodeCIDRs{
+		{Name: "node-a", PodCIDRs: []string{"10.0.1.0/24"}},
+		{Name: "node-d", PodCIDRs: []string{"10.0.5.0/24"}},
+	}, state.Nodes)
+
+	// The pod CIDRs can only be seeded with --node-cidr-state-file when the controller starts.
+	body := rec.Body.String()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/controllers/node-ipam", strings.NewReader(body)))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/debug/controllers/node-ipam", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
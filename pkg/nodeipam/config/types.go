@@ -32,4 +32,25 @@ type NodeIPAMControllerConfiguration struct {
 	// NodeCIDRMaskSizeIPv6 is the mask size for IPv6 node cidr in dual-stack cluster.
 	// This can be used only with dual stack clusters and is incompatible with single stack clusters.
 	NodeCIDRMaskSizeIPv6 int32
+	// NodePoolCIDRMaskSizes are the mask sizes for the IPv4 node cidrs of the node pools.
+	// The first one matching the labels of the node is used, and the nodes matching none of them
+	// use the default mask size.
+	NodePoolCIDRMaskSizes []NodePoolCIDRMaskSize
+	// CIDRUtilizationThreshold is the percentage of the allocated node cidrs of a cluster cidr to record
+	// a warning event at, 0 disables it.
+	CIDRUtilizationThreshold int32
+	// PodSubnetName is the name of the pod subnet dedicated to the node CIDRs in the virtual network of the cluster.
+	// This is only used by the SubnetAllocator.
+	PodSubnetName string
+	// NodeCIDRStateFile is the path of the file of the pod CIDRs of the nodes to assign back to the nodes
+	// registered without pod CIDRs. This is only used by the RangeAllocator and SubnetAllocator.
+	NodeCIDRStateFile string
+}
+
+// NodePoolCIDRMaskSize is the mask size for the IPv4 node cidrs of the nodes matching the label selector.
+type NodePoolCIDRMaskSize struct {
+	// NodeSelector is the label selector of the nodes in the node pool, e.g. agentpool=small.
+	NodeSelector string
+	// NodeCIDRMaskSize is the mask size for the IPv4 node cidrs of the node pool.
+	NodeCIDRMaskSize int32
 }
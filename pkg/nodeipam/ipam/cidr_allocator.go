@@ -43,6 +43,9 @@ const (
 	// CloudAllocatorType is the allocator that uses cloud platform
 	// support to do node CIDR range allocations.
 	CloudAllocatorType CIDRAllocatorType = "CloudAllocator"
+	// SubnetAllocatorType is the allocator that allocates node CIDR ranges
+	// from the address prefixes of the pod subnet dedicated to the node CIDRs.
+	SubnetAllocatorType CIDRAllocatorType = "SubnetAllocator"
 )
 
 // TODO: figure out the good setting for those constants.
@@ -84,6 +87,35 @@ type CIDRAllocatorParams struct {
 	SecondaryServiceCIDR *net.IPNet
 	// NodeCIDRMaskSizes is list of node cidr mask sizes
 	NodeCIDRMaskSizes []int
+	// NodePoolCIDRMaskSizes is list of IPv4 node cidr mask sizes of the node pools
+	NodePoolCIDRMaskSizes []NodePoolCIDRMaskSize
+	// AdditionalClusterCIDRs are the cluster cidrs the node cidrs are allocated from once the cluster cidr
+	// of the same ip family is exhausted
+	AdditionalClusterCIDRs []*net.IPNet
+	// NodePoolClusterCIDRs are the cluster cidrs the node cidrs of the node pools are allocated from
+	NodePoolClusterCIDRs []NodePoolClusterCIDR
+	// CIDRUtilizationThreshold is the percentage of the allocated node cidrs of a cluster cidr to record a
+	// warning event at, 0 disables it. This is only used by the RangeAllocator and SubnetAllocator.
+	CIDRUtilizationThreshold int
+	// PodSubnetName is the name of the pod subnet dedicated to the node cidrs they are allocated from
+	PodSubnetName string
+	// NodeCIDRStateFile is the file of the pod cidrs of the nodes to assign back to them instead of
+	// allocating new ones. This is only used by the RangeAllocator and SubnetAllocator.
+	NodeCIDRStateFile string
+	// SyncObserver is called with the result of each update of the pod cidrs of a node, nil ignores them
+	SyncObserver func(err error)
+}
+
+// NodePoolCIDRMaskSize is the IPv4 node cidr mask size of the nodes matching the selector.
+type NodePoolCIDRMaskSize struct {
+	Selector         labels.Selector
+	NodeCIDRMaskSize int
+}
+
+// NodePoolClusterCIDR is the cluster cidrs of the nodes matching the selector, at most one per ip family.
+type NodePoolClusterCIDR struct {
+	Selector     labels.Selector
+	ClusterCIDRs []*net.IPNet
 }
 
 // New creates a new CIDR range allocator.
@@ -98,6 +130,8 @@ func New(kubeClient clientset.Interface, cloud cloudprovider.Interface, nodeInfo
 		return NewCIDRRangeAllocator(kubeClient, nodeInformer, allocatorParams, nodeList)
 	case CloudAllocatorType:
 		return NewCloudCIDRAllocator(kubeClient, cloud, nodeInformer, allocatorParams, nodeList)
+	case SubnetAllocatorType:
+		return NewSubnetCIDRAllocator(kubeClient, cloud, nodeInformer, allocatorParams, nodeList)
 	default:
 		return nil, fmt.Errorf("invalid CIDR allocator type: %v", allocatorType)
 	}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+)
+
+// CIDRState is the pod CIDR assignments of the nodes.
+type CIDRState struct {
+	Nodes []NodeCIDRs `json:"nodes"`
+}
+
+// NodeCIDRs is the pod CIDRs assigned to a node.
+type NodeCIDRs struct {
+	Name     string   `json:"name"`
+	PodCIDRs []string `json:"podCIDRs"`
+}
+
+// ReadCIDRStateFile reads the pod CIDR assignments of the nodes from the file, in the format
+// exported by the node-ipam debugging endpoint.
+func ReadCIDRStateFile(path string) (*CIDRState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cidr state file %s: %w", path, err)
+	}
+	var state CIDRState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode the cidr state file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// seedCIDRs occupies the pod cidrs of the nodes in the cidr state file, so they are assigned back to
+// the nodes re-registered without pod cidrs instead of reallocated ones, e.g. when migrating from the
+// NodeIPAM of kube-controller-manager. The pod cidrs of the nodes that already have pod cidrs, and the
+// ones overlapping with the pod cidrs of other nodes are skipped. The seeded cidrs stay occupied until
+// the allocator is restarted without them.
+func (r *rangeAllocator) seedCIDRs(path string, nodeList *v1.NodeList) error {
+	state, err := ReadCIDRStateFile(path)
+	if err != nil {
+		return err
+	}
+
+	var occupiedCIDRs []*net.IPNet
+	nodesWithCIDRs := make(map[string]bool)
+	if nodeList != nil {
+		for _, node := range nodeList.Items {
+			if len(node.Spec.PodCIDRs) == 0 {
+				continue
+			}
+			nodesWithCIDRs[node.Name] = true
+			podCIDRs, err := netutils.ParseCIDRs(node.Spec.PodCIDRs)
+			if err != nil {
+				continue
+			}
+			occupiedCIDRs = append(occupiedCIDRs, podCIDRs...)
+		}
+	}
+
+	r.seededCIDRs = make(map[string][]*net.IPNet)
+	for _, nodeCIDRs := range state.Nodes {
+		if nodesWithCIDRs[nodeCIDRs.Name] {
+			klog.V(4).Infof("seedCIDRs: node %s already has pod CIDRs, ignoring the seeded ones %v", nodeCIDRs.Name, nodeCIDRs.PodCIDRs)
+			continue
+		}
+		if _, found := r.seededCIDRs[nodeCIDRs.Name]; found {
+			klog.Warningf("seedCIDRs: node %s is seeded more than once, ignoring the pod CIDRs %v", nodeCIDRs.Name, nodeCIDRs.PodCIDRs)
+			continue
+		}
+		podCIDRs, err := r.parseSeededCIDRs(nodeCIDRs.PodCIDRs, occupiedCIDRs)
+		if err != nil {
+			klog.Warningf("seedCIDRs: ignoring the pod CIDRs %v of node %s: %v", nodeCIDRs.PodCIDRs, nodeCIDRs.Name, err)
+			continue
+		}
+		for i, podCIDR := range podCIDRs {
+			if err := r.cidrSetOf(i, podCIDR).Occupy(podCIDR); err != nil {
+				r.releaseCIDRs(nodeCIDRs.Name, podCIDRs[:i])
+				podCIDRs = nil
+				klog.Warningf("seedCIDRs: ignoring the pod CIDRs %v of node %s: %v", nodeCIDRs.PodCIDRs, nodeCIDRs.Name, err)
+				break
+			}
+		}
+		if podCIDRs == nil {
+			continue
+		}
+		klog.V(2).Infof("seedCIDRs: seeded pod CIDRs %v of node %s", nodeCIDRs.PodCIDRs, nodeCIDRs.Name)
+		occupiedCIDRs = append(occupiedCIDRs, podCIDRs...)
+		r.seededCIDRs[nodeCIDRs.Name] = podCIDRs
+	}
+	return nil
+}
+
+// parseSeededCIDRs parses the seeded pod cidrs of a node, which must be one for each cluster cidr in
+// the same order, and must not overlap with the occupied cidrs.
+func (r *rangeAllocator) parseSeededCIDRs(cidrs []string, occupiedCIDRs []*net.IPNet) ([]*net.IPNet, error) {
+	podCIDRs, err := netutils.ParseCIDRs(cidrs)
+	if err != nil {
+		return nil, err
+	}
+	if len(podCIDRs) != len(r.cidrSets) {
+		return nil, fmt.Errorf("expected %d pod CIDRs for cluster CIDRs %v", len(r.cidrSets), r.clusterCIDRs)
+	}
+	for i, podCIDR := range podCIDRs {
+		if idx, ok := r.cidrSetIndex(podCIDR); !ok || idx != i {
+			return nil, fmt.Errorf("pod CIDR %v doesn't match the cluster CIDR %v", podCIDR, r.clusterCIDRs[i])
+		}
+		for _, occupied := range occupiedCIDRs {
+			if occupied.Contains(podCIDR.IP) || podCIDR.Contains(occupied.IP) {
+				return nil, fmt.Errorf("pod CIDR %v overlaps with the pod CIDR %v of another node", podCIDR, occupied)
+			}
+		}
+	}
+	return podCIDRs, nil
+}
+
+// takeSeededCIDRs returns the seeded pod cidrs of the node and forgets them, or nil if there is none.
+func (r *rangeAllocator) takeSeededCIDRs(nodeName string) []*net.IPNet {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	podCIDRs := r.seededCIDRs[nodeName]
+	delete(r.seededCIDRs, nodeName)
+	return podCIDRs
+}
@@ -204,6 +204,13 @@ func (s *CidrSet) indexToCIDRBlock(index, nodeMaskSize int) *net.IPNet {
 	}
 }
 
+// Usage returns the fraction of the allocated CIDRs.
+func (s *CidrSet) Usage() float64 {
+	s.Lock()
+	defer s.Unlock()
+	return float64(s.allocatedCIDRs) / float64(s.maxCIDRs)
+}
+
 // AllocateNext allocates the next free CIDR range. This will set the range
 // as occupied and return the allocated range.
 func (s *CidrSet) AllocateNext() (*net.IPNet, error) {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -76,6 +77,9 @@ type cloudCIDRAllocator struct {
 	clusterCIDRs               []*net.IPNet
 
 	nodeNamePodCIDRsMap map[string][]string
+
+	// syncObserver is called with the result of each update of the pod cidrs of a node
+	syncObserver func(err error)
 }
 
 var _ CIDRAllocator = (*cloudCIDRAllocator)(nil)
@@ -98,6 +102,8 @@ func NewCloudCIDRAllocator(
 	klog.V(0).Infof("Sending events to api server.")
 	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: client.CoreV1().Events("")})
 
+	registerCIDRAllocatorMetrics()
+
 	az, ok := cloud.(*providerazure.Cloud)
 	if !ok {
 		err := fmt.Errorf("cloudCIDRAllocator does not support %v provider", cloud.ProviderName())
@@ -116,6 +122,7 @@ func NewCloudCIDRAllocator(
 		maxSubnetMaskSizes:         make([]int, len(allocatorParams.ClusterCIDRs)),
 		clusterCIDRs:               allocatorParams.ClusterCIDRs,
 		nodeNamePodCIDRsMap:        make(map[string][]string),
+		syncObserver:               allocatorParams.SyncObserver,
 	}
 
 	// update the node subnet mask size
@@ -288,7 +295,11 @@ func (ca *cloudCIDRAllocator) worker(ctx context.Context) {
 				klog.Warning("Channel nodeUpdateChannel was unexpectedly closed")
 				return
 			}
-			if err := ca.updateCIDRsAllocation(workItem); err != nil {
+			err := ca.updateCIDRsAllocation(workItem)
+			if ca.syncObserver != nil {
+				ca.syncObserver(err)
+			}
+			if err != nil {
 				// Requeue the failed node for update again.
 				ca.nodeUpdateChannel <- workItem
 			}
@@ -385,12 +396,14 @@ func (ca *cloudCIDRAllocator) AllocateOrOccupyCIDR(node *v1.Node) error {
 	allocated := nodeReservedCIDRs{
 		nodeName:       node.Name,
 		allocatedCIDRs: make([]*net.IPNet, len(ca.cidrSets)),
+		startTime:      time.Now(),
 	}
 
 	for i := range ca.cidrSets {
 		podCIDR, err := ca.cidrSets[i].AllocateNextWithNodeMaskSize(ca.nodeNameSubnetMaskSizesMap[node.Name][i])
 		if err != nil {
 			ca.removeNodeFromProcessing(node.Name)
+			cidrAllocationFailures.WithLabelValues("CIDRNotAvailable").Inc()
 			nodeutil.RecordNodeStatusChange(ca.recorder, node, "CIDRNotAvailable")
 			return fmt.Errorf("failed to allocate cidr from cluster cidr at idx:%v: %w", i, err)
 		}
@@ -448,11 +461,13 @@ func (ca *cloudCIDRAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) erro
 	// If we reached here, it means that the node has no CIDR currently assigned. So we set it.
 	for i := 0; i < cidrUpdateRetries; i++ {
 		if err = utilnode.PatchNodeCIDRs(ca.client, types.NodeName(node.Name), cidrsString); err == nil {
+			cidrAllocationDuration.Observe(time.Since(data.startTime).Seconds())
 			return nil
 		}
 	}
 	// failed release back to the pool
 	klog.Errorf("Failed to update node %v PodCIDR to %v after multiple attempts: %v", node.Name, cidrsString, err)
+	cidrAllocationFailures.WithLabelValues("CIDRAssignmentFailed").Inc()
 	nodeutil.RecordNodeStatusChange(ca.recorder, node, "CIDRAssignmentFailed")
 	// We accept the fact that we may leak CIDRs here. This is safer than releasing
 	// them in case when we don't know if request went through.
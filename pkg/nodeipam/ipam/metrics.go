@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const nodeIpamSubsystem = "node_ipam_controller"
+
+var (
+	cidrAllocationFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      nodeIpamSubsystem,
+			Name:           "cidr_allocation_failures_total",
+			Help:           "Counter measuring total number of failed node CIDR allocations by reason.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"reason"},
+	)
+	cidrAllocationDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      nodeIpamSubsystem,
+			Name:           "cidr_allocation_duration_seconds",
+			Help:           "Latency in seconds from allocating the CIDRs of a node to assigning them to the node.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.01, 2, 15),
+		},
+	)
+)
+
+var registerMetrics sync.Once
+
+// registerCIDRAllocatorMetrics registers the metrics of the CIDR allocators.
+func registerCIDRAllocatorMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(cidrAllocationFailures)
+		legacyregistry.MustRegister(cidrAllocationDuration)
+	})
+}
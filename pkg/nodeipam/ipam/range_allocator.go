@@ -18,13 +18,16 @@ package ipam
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -36,6 +39,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/cidrset"
 	nodeutil "sigs.k8s.io/cloud-provider-azure/pkg/util/controller/node"
@@ -47,6 +51,17 @@ import (
 type nodeReservedCIDRs struct {
 	allocatedCIDRs []*net.IPNet
 	nodeName       string
+	// startTime is when the allocation of the node started
+	startTime time.Time
+}
+
+// nodePoolCIDRSet tracks a cluster cidr of the nodes matching the selector.
+type nodePoolCIDRSet struct {
+	selector labels.Selector
+	// idx is the index of the cluster cidr of the same ip family
+	idx         int
+	clusterCIDR *net.IPNet
+	cidrSet     *cidrset.CidrSet
 }
 
 type rangeAllocator struct {
@@ -55,6 +70,29 @@ type rangeAllocator struct {
 	clusterCIDRs []*net.IPNet
 	// for each entry in clusterCIDRs we maintain a list of what is used and what is not
 	cidrSets []*cidrset.CidrSet
+	// nodeCIDRMaskSizes are the default node cidr mask sizes of the clusterCIDRs
+	nodeCIDRMaskSizes []int
+	// cidrSetMaskSizes are the mask sizes of the blocks tracked by the cidrSets. The cidrSet of an IPv4
+	// cluster cidr tracks the smallest node cidrs of the node pools, and the larger node cidrs are
+	// allocated as multiple contiguous blocks.
+	cidrSetMaskSizes []int
+	// nodePoolCIDRMaskSizes are the IPv4 node cidr mask sizes of the node pools
+	nodePoolCIDRMaskSizes []NodePoolCIDRMaskSize
+	// additionalClusterCIDRs are the additional cluster cidrs by the index of the cluster cidr of the same
+	// ip family, and additionalCIDRSets track them. The pod cidrs are allocated from them in order once
+	// the cidrSet of the cluster cidr is exhausted.
+	additionalClusterCIDRs [][]*net.IPNet
+	additionalCIDRSets     [][]*cidrset.CidrSet
+	// nodePoolCIDRSets track the cluster cidrs of the node pools. The pod cidrs of the nodes matching a
+	// node pool are only allocated from the cluster cidrs of the node pool in the ip families it has.
+	nodePoolCIDRSets []nodePoolCIDRSet
+	// cidrUtilizationThreshold is the percentage of the allocated node cidrs of a cluster cidr to record
+	// a warning event at, and highUtilizationCIDRs are the cluster cidrs reaching it.
+	cidrUtilizationThreshold int
+	highUtilizationCIDRs     sets.String
+	// seededCIDRs are the occupied pod cidrs of the cidr state file to assign back to the nodes
+	// without pod cidrs, by the node name
+	seededCIDRs map[string][]*net.IPNet
 	// nodeLister is able to list/get nodes and is populated by the shared informer passed to controller
 	nodeLister corelisters.NodeLister
 	// nodesSynced returns true if the node shared informer has been synced at least once.
@@ -66,6 +104,8 @@ type rangeAllocator struct {
 	// Keep a set of nodes that are currently being processed to avoid races in CIDR allocation
 	lock              sync.Mutex
 	nodesInProcessing sets.String
+	// syncObserver is called with the result of each update of the pod cidrs of a node
+	syncObserver func(err error)
 }
 
 // NewCIDRRangeAllocator returns a CIDRAllocator to allocate CIDRs for node (one from each of clusterCIDRs)
@@ -84,36 +124,80 @@ func NewCIDRRangeAllocator(client clientset.Interface, nodeInformer informers.No
 	klog.V(0).Infof("Sending events to api server.")
 	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: client.CoreV1().Events("")})
 
+	registerCIDRAllocatorMetrics()
+
 	// create a cidrSet for each cidr we operate on
 	// cidrSet are mapped to clusterCIDR by index
 	cidrSets := make([]*cidrset.CidrSet, len(allocatorParams.ClusterCIDRs))
+	cidrSetMaskSizes := make([]int, len(allocatorParams.ClusterCIDRs))
 	for idx, cidr := range allocatorParams.ClusterCIDRs {
-		cidrSet, err := cidrset.NewCIDRSet(cidr, allocatorParams.NodeCIDRMaskSizes[idx])
+		maskSize := allocatorParams.NodeCIDRMaskSizes[idx]
+		if !netutils.IsIPv6CIDR(cidr) {
+			clusterMaskSize, _ := cidr.Mask.Size()
+			for _, nodePool := range allocatorParams.NodePoolCIDRMaskSizes {
+				if nodePool.NodeCIDRMaskSize < clusterMaskSize || nodePool.NodeCIDRMaskSize > 32 {
+					return nil, fmt.Errorf("node cidr mask size %d of the node pool %q is out of the range of cluster cidr %v", nodePool.NodeCIDRMaskSize, nodePool.Selector, cidr)
+				}
+				maskSize = max(maskSize, nodePool.NodeCIDRMaskSize)
+			}
+		}
+		cidrSet, err := cidrset.NewCIDRSet(cidr, maskSize)
 		if err != nil {
 			return nil, err
 		}
 		cidrSets[idx] = cidrSet
+		cidrSetMaskSizes[idx] = maskSize
+	}
+
+	additionalClusterCIDRs, additionalCIDRSets, err := newAdditionalCIDRSets(allocatorParams.ClusterCIDRs, allocatorParams.AdditionalClusterCIDRs, cidrSetMaskSizes)
+	if err != nil {
+		return nil, err
+	}
+	nodePoolCIDRSets, err := newNodePoolCIDRSets(allocatorParams.ClusterCIDRs, allocatorParams.AdditionalClusterCIDRs, allocatorParams.NodePoolClusterCIDRs, cidrSetMaskSizes)
+	if err != nil {
+		return nil, err
 	}
 
 	ra := &rangeAllocator{
-		client:                client,
-		clusterCIDRs:          allocatorParams.ClusterCIDRs,
-		cidrSets:              cidrSets,
-		nodeLister:            nodeInformer.Lister(),
-		nodesSynced:           nodeInformer.Informer().HasSynced,
-		nodeCIDRUpdateChannel: make(chan nodeReservedCIDRs, cidrUpdateQueueSize),
-		recorder:              recorder,
-		nodesInProcessing:     sets.NewString(),
+		client:                   client,
+		clusterCIDRs:             allocatorParams.ClusterCIDRs,
+		cidrSets:                 cidrSets,
+		nodeCIDRMaskSizes:        allocatorParams.NodeCIDRMaskSizes,
+		cidrSetMaskSizes:         cidrSetMaskSizes,
+		nodePoolCIDRMaskSizes:    allocatorParams.NodePoolCIDRMaskSizes,
+		additionalClusterCIDRs:   additionalClusterCIDRs,
+		additionalCIDRSets:       additionalCIDRSets,
+		nodePoolCIDRSets:         nodePoolCIDRSets,
+		cidrUtilizationThreshold: allocatorParams.CIDRUtilizationThreshold,
+		highUtilizationCIDRs:     sets.NewString(),
+		nodeLister:               nodeInformer.Lister(),
+		nodesSynced:              nodeInformer.Informer().HasSynced,
+		nodeCIDRUpdateChannel:    make(chan nodeReservedCIDRs, cidrUpdateQueueSize),
+		recorder:                 recorder,
+		nodesInProcessing:        sets.NewString(),
+		syncObserver:             allocatorParams.SyncObserver,
 	}
 
 	if allocatorParams.ServiceCIDR != nil {
 		filterOutServiceRange(ra.clusterCIDRs, ra.cidrSets, allocatorParams.ServiceCIDR)
+		for idx := range ra.additionalCIDRSets {
+			filterOutServiceRange(ra.additionalClusterCIDRs[idx], ra.additionalCIDRSets[idx], allocatorParams.ServiceCIDR)
+		}
+		for _, nodePool := range ra.nodePoolCIDRSets {
+			filterOutServiceRange([]*net.IPNet{nodePool.clusterCIDR}, []*cidrset.CidrSet{nodePool.cidrSet}, allocatorParams.ServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Service CIDR provided. Skipping filtering out service addresses.")
 	}
 
 	if allocatorParams.SecondaryServiceCIDR != nil {
 		filterOutServiceRange(ra.clusterCIDRs, ra.cidrSets, allocatorParams.SecondaryServiceCIDR)
+		for idx := range ra.additionalCIDRSets {
+			filterOutServiceRange(ra.additionalClusterCIDRs[idx], ra.additionalCIDRSets[idx], allocatorParams.SecondaryServiceCIDR)
+		}
+		for _, nodePool := range ra.nodePoolCIDRSets {
+			filterOutServiceRange([]*net.IPNet{nodePool.clusterCIDR}, []*cidrset.CidrSet{nodePool.cidrSet}, allocatorParams.SecondaryServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Secondary Service CIDR provided. Skipping filtering out secondary service addresses.")
 	}
@@ -139,6 +223,12 @@ func NewCIDRRangeAllocator(client clientset.Interface, nodeInformer informers.No
 		}
 	}
 
+	if allocatorParams.NodeCIDRStateFile != "" {
+		if err := ra.seedCIDRs(allocatorParams.NodeCIDRStateFile, nodeList); err != nil {
+			return nil, err
+		}
+	}
+
 	_, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: nodeutil.CreateAddNodeHandler(ra.AllocateOrOccupyCIDR),
 		UpdateFunc: nodeutil.CreateUpdateNodeHandler(func(_, newNode *v1.Node) error {
@@ -172,6 +262,85 @@ func NewCIDRRangeAllocator(client clientset.Interface, nodeInformer informers.No
 	return ra, nil
 }
 
+// newAdditionalCIDRSets groups the additional cluster cidrs by the index of the cluster cidr of the same
+// ip family, and creates their cidrSets with the mask size of the cidrSet of that cluster cidr.
+func newAdditionalCIDRSets(clusterCIDRs, additionalClusterCIDRs []*net.IPNet, cidrSetMaskSizes []int) ([][]*net.IPNet, [][]*cidrset.CidrSet, error) {
+	cidrs := make([][]*net.IPNet, len(clusterCIDRs))
+	cidrSets := make([][]*cidrset.CidrSet, len(clusterCIDRs))
+	for _, cidr := range additionalClusterCIDRs {
+		idx := -1
+		for i, clusterCIDR := range clusterCIDRs {
+			if clusterCIDR.Contains(cidr.IP) || cidr.Contains(clusterCIDR.IP) {
+				return nil, nil, fmt.Errorf("additional cluster cidr %v overlaps with cluster cidr %v", cidr, clusterCIDR)
+			}
+			if idx < 0 && netutils.IsIPv6CIDR(clusterCIDR) == netutils.IsIPv6CIDR(cidr) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return nil, nil, fmt.Errorf("additional cluster cidr %v has no cluster cidr of the same ip family", cidr)
+		}
+		for _, existing := range cidrs[idx] {
+			if existing.Contains(cidr.IP) || cidr.Contains(existing.IP) {
+				return nil, nil, fmt.Errorf("additional cluster cidr %v overlaps with additional cluster cidr %v", cidr, existing)
+			}
+		}
+		if maskSize, _ := cidr.Mask.Size(); maskSize > cidrSetMaskSizes[idx] {
+			return nil, nil, fmt.Errorf("mask size of additional cluster cidr %v must be less than or equal to the node cidr mask size %d", cidr, cidrSetMaskSizes[idx])
+		}
+		cidrSet, err := cidrset.NewCIDRSet(cidr, cidrSetMaskSizes[idx])
+		if err != nil {
+			return nil, nil, err
+		}
+		cidrs[idx] = append(cidrs[idx], cidr)
+		cidrSets[idx] = append(cidrSets[idx], cidrSet)
+	}
+	return cidrs, cidrSets, nil
+}
+
+// newNodePoolCIDRSets creates the cidrSets of the cluster cidrs of the node pools with the mask size of the
+// cidrSet of the cluster cidr of the same ip family.
+func newNodePoolCIDRSets(clusterCIDRs, additionalClusterCIDRs []*net.IPNet, nodePools []NodePoolClusterCIDR, cidrSetMaskSizes []int) ([]nodePoolCIDRSet, error) {
+	var nodePoolCIDRSets []nodePoolCIDRSet
+	for _, nodePool := range nodePools {
+		for _, cidr := range nodePool.ClusterCIDRs {
+			idx := -1
+			for i, clusterCIDR := range clusterCIDRs {
+				if netutils.IsIPv6CIDR(clusterCIDR) == netutils.IsIPv6CIDR(cidr) {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return nil, fmt.Errorf("cluster cidr %v of node pool %q has no cluster cidr of the same ip family", cidr, nodePool.Selector)
+			}
+			existing := append(append([]*net.IPNet{}, clusterCIDRs...), additionalClusterCIDRs...)
+			for _, other := range nodePoolCIDRSets {
+				existing = append(existing, other.clusterCIDR)
+			}
+			for _, other := range existing {
+				if other.Contains(cidr.IP) || cidr.Contains(other.IP) {
+					return nil, fmt.Errorf("cluster cidr %v of node pool %q overlaps with cluster cidr %v", cidr, nodePool.Selector, other)
+				}
+			}
+			if maskSize, _ := cidr.Mask.Size(); maskSize > cidrSetMaskSizes[idx] {
+				return nil, fmt.Errorf("mask size of cluster cidr %v of node pool %q must be less than or equal to the node cidr mask size %d", cidr, nodePool.Selector, cidrSetMaskSizes[idx])
+			}
+			cidrSet, err := cidrset.NewCIDRSet(cidr, cidrSetMaskSizes[idx])
+			if err != nil {
+				return nil, err
+			}
+			nodePoolCIDRSets = append(nodePoolCIDRSets, nodePoolCIDRSet{
+				selector:    nodePool.Selector,
+				idx:         idx,
+				clusterCIDR: cidr,
+				cidrSet:     cidrSet,
+			})
+		}
+	}
+	return nodePoolCIDRSets, nil
+}
+
 func (r *rangeAllocator) Run(ctx context.Context) {
 	defer utilruntime.HandleCrash()
 
@@ -197,7 +366,11 @@ func (r *rangeAllocator) worker(ctx context.Context) {
 				klog.Warning("Channel nodeCIDRUpdateChannel was unexpectedly closed")
 				return
 			}
-			if workItem, err := r.updateCIDRsAllocation(workItem); err != nil {
+			workItem, err := r.updateCIDRsAllocation(workItem)
+			if r.syncObserver != nil {
+				r.syncObserver(err)
+			}
+			if err != nil {
 				// Requeue the failed node for update again.
 				r.nodeCIDRUpdateChannel <- workItem
 			}
@@ -226,10 +399,7 @@ func (r *rangeAllocator) removeNodeFromProcessing(nodeName string) {
 // marks node.PodCIDRs[...] as used in allocator's tracked cidrSet
 func (r *rangeAllocator) occupyCIDRs(node *v1.Node) error {
 	defer r.removeNodeFromProcessing(node.Name)
-	if len(node.Spec.PodCIDRs) == 0 {
-		return nil
-	}
-	for idx, cidr := range node.Spec.PodCIDRs {
+	for _, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse node %s, CIDR %s", node.Name, node.Spec.PodCIDR)
@@ -237,17 +407,67 @@ func (r *rangeAllocator) occupyCIDRs(node *v1.Node) error {
 		// If node has a pre allocate cidr that does not exist in our cidrs.
 		// This will happen if cluster went from dualstack(multi cidrs) to non-dualstack
 		// then we have now way of locking it
-		if idx >= len(r.cidrSets) {
-			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, idx)
+		idx, ok := r.cidrSetIndex(podCIDR)
+		if !ok {
+			return fmt.Errorf("node:%s has an allocated cidr: %v of the ip family that does not exist in cluster cidrs configuration", node.Name, cidr)
 		}
 
-		if err := r.cidrSets[idx].Occupy(podCIDR); err != nil {
+		if err := r.cidrSetOf(idx, podCIDR).Occupy(podCIDR); err != nil {
 			return fmt.Errorf("failed to mark cidr[%v] at idx [%v] as occupied for node: %v: %w", podCIDR, idx, node.Name, err)
 		}
 	}
 	return nil
 }
 
+// cidrSetIndex returns the index of the cluster cidr the pod cidr is allocated from, which is the
+// first cluster cidr of the same ip family containing the pod cidr, or the first one of the same ip family.
+func (r *rangeAllocator) cidrSetIndex(podCIDR *net.IPNet) (int, bool) {
+	idx := -1
+	for i, clusterCIDR := range r.clusterCIDRs {
+		if netutils.IsIPv6CIDR(clusterCIDR) != netutils.IsIPv6CIDR(podCIDR) {
+			continue
+		}
+		if clusterCIDR.Contains(podCIDR.IP) {
+			return i, true
+		}
+		if idx < 0 {
+			idx = i
+		}
+	}
+	return idx, idx >= 0
+}
+
+// cidrSetOf returns the cidrSet of the additional or node pool cluster cidr containing the pod cidr, or the
+// one of the cluster cidr at the index.
+func (r *rangeAllocator) cidrSetOf(idx int, podCIDR *net.IPNet) *cidrset.CidrSet {
+	if !r.clusterCIDRs[idx].Contains(podCIDR.IP) {
+		for i, cidr := range r.additionalClusterCIDRs[idx] {
+			if cidr.Contains(podCIDR.IP) {
+				return r.additionalCIDRSets[idx][i]
+			}
+		}
+		for _, nodePool := range r.nodePoolCIDRSets {
+			if nodePool.idx == idx && nodePool.clusterCIDR.Contains(podCIDR.IP) {
+				return nodePool.cidrSet
+			}
+		}
+	}
+	return r.cidrSets[idx]
+}
+
+// releaseCIDRs releases the reserved cidrs back to the cidrSets they are allocated from.
+func (r *rangeAllocator) releaseCIDRs(nodeName string, cidrs []*net.IPNet) {
+	for _, cidr := range cidrs {
+		idx, ok := r.cidrSetIndex(cidr)
+		if !ok {
+			continue
+		}
+		if releaseErr := r.cidrSetOf(idx, cidr).Release(cidr); releaseErr != nil {
+			klog.Errorf("Error releasing allocated CIDR %v at index %d for node %v: %v", cidr, idx, nodeName, releaseErr)
+		}
+	}
+}
+
 // WARNING: If you're adding any return calls or defer any more work from this
 // function you have to make sure to update nodesInProcessing properly with the
 // disposition of the node when the work is done.
@@ -261,19 +481,30 @@ func (r *rangeAllocator) AllocateOrOccupyCIDR(node *v1.Node) error {
 	}
 
 	if len(node.Spec.PodCIDRs) > 0 {
+		// The pod cidrs can't be changed once they are set, so the nodes created before the cluster is
+		// converted to dual-stack keep the pod cidrs of a single ip family.
+		if len(node.Spec.PodCIDRs) < len(r.cidrSets) {
+			klog.Warningf("Node %v has pod CIDRs %v of fewer ip families than the cluster CIDRs and can't be converted to dual-stack", node.Name, node.Spec.PodCIDRs)
+			r.recorder.Eventf(&v1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: node.Name, UID: node.UID}, v1.EventTypeWarning, "CIDRNotConvertible",
+				"Node %s has pod CIDRs %v and can't be converted to dual-stack, recreate it to get the pod CIDRs of all ip families", node.Name, node.Spec.PodCIDRs)
+		}
 		return r.occupyCIDRs(node)
 	}
 
 	// allocate pod cidrs
-	allocatedCIDRs, err := r.allocatePodCIDRs()
+	startTime := time.Now()
+	allocatedCIDRs, err := r.allocatePodCIDRs(node)
 	if err != nil {
 		r.removeNodeFromProcessing(node.Name)
+		cidrAllocationFailures.WithLabelValues("CIDRNotAvailable").Inc()
 		nodeutil.RecordNodeStatusChange(r.recorder, node, "CIDRNotAvailable")
 		return fmt.Errorf("failed to allocate cidr for node %s: %w", node.Name, err)
 	}
+	r.checkCIDRUtilization(node)
 	allocated := nodeReservedCIDRs{
 		nodeName:       node.Name,
 		allocatedCIDRs: allocatedCIDRs,
+		startTime:      startTime,
 	}
 
 	// queue the assignment
@@ -288,7 +519,7 @@ func (r *rangeAllocator) ReleaseCIDR(node *v1.Node) error {
 		return nil
 	}
 
-	for idx, cidr := range node.Spec.PodCIDRs {
+	for _, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse CIDR %s on Node %v: %w", cidr, node.Name, err)
@@ -297,18 +528,60 @@ func (r *rangeAllocator) ReleaseCIDR(node *v1.Node) error {
 		// If node has a pre allocate cidr that does not exist in our cidrs.
 		// This will happen if cluster went from dualstack(multi cidrs) to non-dualstack
 		// then we have now way of locking it
-		if idx >= len(r.cidrSets) {
-			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, idx)
+		idx, ok := r.cidrSetIndex(podCIDR)
+		if !ok {
+			return fmt.Errorf("node:%s has an allocated cidr: %v of the ip family that does not exist in cluster cidrs configuration", node.Name, cidr)
 		}
 
 		klog.V(4).Infof("release CIDR %s for node:%v", cidr, node.Name)
-		if err = r.cidrSets[idx].Release(podCIDR); err != nil {
+		if err = r.cidrSetOf(idx, podCIDR).Release(podCIDR); err != nil {
 			return fmt.Errorf("error when releasing CIDR %v: %w", cidr, err)
 		}
 	}
+	r.checkCIDRUtilization(nil)
 	return nil
 }
 
+// checkCIDRUtilization records a warning event on the node when the allocation for it makes a cluster cidr
+// reach the utilization threshold. The event is recorded again after the utilization drops below the
+// threshold and reaches it again.
+func (r *rangeAllocator) checkCIDRUtilization(node *v1.Node) {
+	if r.cidrUtilizationThreshold <= 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	check := func(cidr *net.IPNet, cidrSet *cidrset.CidrSet) {
+		utilization := cidrSet.Usage() * 100
+		if utilization < float64(r.cidrUtilizationThreshold) {
+			r.highUtilizationCIDRs.Delete(cidr.String())
+			return
+		}
+		if r.highUtilizationCIDRs.Has(cidr.String()) || node == nil {
+			return
+		}
+		r.highUtilizationCIDRs.Insert(cidr.String())
+		klog.Warningf("Cluster CIDR %v is %.1f%% allocated, reaching the threshold %d%%", cidr, utilization, r.cidrUtilizationThreshold)
+		ref := &v1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       node.Name,
+			UID:        node.UID,
+		}
+		r.recorder.Eventf(ref, v1.EventTypeWarning, "CIDRUtilizationHigh", "Cluster CIDR %v is %.1f%% allocated, reaching the threshold %d%%", cidr, utilization, r.cidrUtilizationThreshold)
+	}
+	for idx := range r.cidrSets {
+		check(r.clusterCIDRs[idx], r.cidrSets[idx])
+		for i := range r.additionalCIDRSets[idx] {
+			check(r.additionalClusterCIDRs[idx][i], r.additionalCIDRSets[idx][i])
+		}
+	}
+	for _, nodePool := range r.nodePoolCIDRSets {
+		check(nodePool.clusterCIDR, nodePool.cidrSet)
+	}
+}
+
 // Marks all CIDRs with subNetMaskSize that belongs to serviceCIDR as used across all cidrs
 // so that they won't be assignable.
 func filterOutServiceRange(clusterCIDRs []*net.IPNet, cidrSets []*cidrset.CidrSet, serviceCIDR *net.IPNet) {
@@ -330,24 +603,64 @@ func filterOutServiceRange(clusterCIDRs []*net.IPNet, cidrSets []*cidrset.CidrSe
 	}
 }
 
-func (r *rangeAllocator) allocatePodCIDRs() ([]*net.IPNet, error) {
-	allocatedCIDRs := make([]*net.IPNet, len(r.cidrSets))
+// allocatePodCIDRs allocates a cidr for the node from each cluster cidr, or returns the seeded pod cidrs
+// of the node if there are any.
+func (r *rangeAllocator) allocatePodCIDRs(node *v1.Node) ([]*net.IPNet, error) {
+	if seededCIDRs := r.takeSeededCIDRs(node.Name); seededCIDRs != nil {
+		klog.V(2).Infof("Assigning the seeded pod CIDRs %v to node %s", seededCIDRs, node.Name)
+		return seededCIDRs, nil
+	}
+	allocatedCIDRs := make([]*net.IPNet, 0, len(r.cidrSets))
 	for idx := range r.cidrSets {
-		podCIDR, err := r.cidrSets[idx].AllocateNext()
+		podCIDR, err := r.allocateNext(node, idx, r.nodeCIDRMaskSize(node, idx))
 		if err != nil {
-			for i := 0; i < idx; i++ {
-				if releaseErr := r.cidrSets[idx].Release(allocatedCIDRs[i]); releaseErr != nil {
-					// continue releasing the rest
-					klog.Errorf("Error releasing allocated CIDR at index %d for node: %v", i, releaseErr)
-				}
-			}
+			r.releaseCIDRs("", allocatedCIDRs)
 			return nil, fmt.Errorf("failed to allocate cidr from cluster cidr at idx:%v: %w", idx, err)
 		}
-		allocatedCIDRs[idx] = podCIDR
+		allocatedCIDRs = append(allocatedCIDRs, podCIDR)
 	}
 	return allocatedCIDRs, nil
 }
 
+// allocateNext allocates a pod cidr with the mask size from the cluster cidr of the ip family at the index
+// of the first node pool matching the node. If there is none, it is allocated from the cluster cidr at the
+// index, or from its additional cluster cidrs once it is exhausted.
+func (r *rangeAllocator) allocateNext(node *v1.Node, idx, maskSize int) (*net.IPNet, error) {
+	cidrSets := append([]*cidrset.CidrSet{r.cidrSets[idx]}, r.additionalCIDRSets[idx]...)
+	for _, nodePool := range r.nodePoolCIDRSets {
+		if nodePool.idx == idx && nodePool.selector.Matches(labels.Set(node.Labels)) {
+			cidrSets = []*cidrset.CidrSet{nodePool.cidrSet}
+			break
+		}
+	}
+	var err error
+	for _, cidrSet := range cidrSets {
+		var podCIDR *net.IPNet
+		if maskSize != r.cidrSetMaskSizes[idx] {
+			podCIDR, err = cidrSet.AllocateNextWithNodeMaskSize(maskSize)
+		} else {
+			podCIDR, err = cidrSet.AllocateNext()
+		}
+		if !errors.Is(err, cidrset.ErrCIDRRangeNoCIDRsRemaining) {
+			return podCIDR, err
+		}
+	}
+	return nil, err
+}
+
+// nodeCIDRMaskSize returns the mask size of the node cidr allocated from the cluster cidr at the index,
+// which is the one of the first node pool matching the node for IPv4 cluster cidrs.
+func (r *rangeAllocator) nodeCIDRMaskSize(node *v1.Node, idx int) int {
+	if !netutils.IsIPv6CIDR(r.clusterCIDRs[idx]) {
+		for _, nodePool := range r.nodePoolCIDRMaskSizes {
+			if nodePool.Selector.Matches(labels.Set(node.Labels)) {
+				return nodePool.NodeCIDRMaskSize
+			}
+		}
+	}
+	return r.nodeCIDRMaskSizes[idx]
+}
+
 // updateCIDRsAllocation assigns CIDR to Node and sends an update to the API server.
 func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRetry nodeReservedCIDRs, err error) {
 	var node *v1.Node
@@ -371,62 +684,50 @@ func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRe
 
 	// this happens when node patch fails, we release the CIDRs allocated and retry
 	if data.allocatedCIDRs == nil {
-		allocatedCIDRs, err := r.allocatePodCIDRs()
+		allocatedCIDRs, err := r.allocatePodCIDRs(node)
 		if err != nil {
+			cidrAllocationFailures.WithLabelValues("CIDRNotAvailable").Inc()
 			nodeutil.RecordNodeStatusChange(r.recorder, node, "CIDRNotAvailable")
 			return data, fmt.Errorf("failed to allocate cidr for node %s: %w", data.nodeName, err)
 		}
+		r.checkCIDRUtilization(node)
 		data.allocatedCIDRs = allocatedCIDRs
 	}
-	cidrsString := cidrsAsString(data.allocatedCIDRs)
+	reservedCIDRs := cidrsAsString(data.allocatedCIDRs)
 
 	// if cidr list matches the proposed.
 	// then we possibly updated this node
 	// and just failed to ack the success.
-	if len(node.Spec.PodCIDRs) == len(data.allocatedCIDRs) {
-		match := true
-		for idx, cidr := range cidrsString {
-			if node.Spec.PodCIDRs[idx] != cidr {
-				match = false
-				break
-			}
-		}
-		if match {
-			klog.V(4).Infof("Node %v already has allocated CIDR %v. It matches the proposed one.", node.Name, data.allocatedCIDRs)
-			return data, nil
-		}
+	if len(node.Spec.PodCIDRs) == len(reservedCIDRs) && sets.NewString(node.Spec.PodCIDRs...).HasAll(reservedCIDRs...) {
+		klog.V(4).Infof("Node %v already has allocated CIDR %v. It matches the proposed one.", node.Name, data.allocatedCIDRs)
+		return data, nil
 	}
 
 	// node has cidrs, release the reserved
 	if len(node.Spec.PodCIDRs) != 0 {
 		klog.Errorf("Node %v already has a CIDR allocated %v. Releasing the new one.", node.Name, node.Spec.PodCIDRs)
-		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := r.cidrSets[idx].Release(cidr); releaseErr != nil {
-				klog.Errorf("Error when releasing CIDR idx:%v value: %v err:%v", idx, cidr, releaseErr)
-			}
-		}
+		r.releaseCIDRs(node.Name, data.allocatedCIDRs)
 		return data, nil
 	}
 
 	// If we reached here, it means that the node has no CIDR currently assigned. So we set it.
+	cidrsString := reservedCIDRs
 	for i := 0; i < cidrUpdateRetries; i++ {
 		if err = utilnode.PatchNodeCIDRs(r.client, types.NodeName(node.Name), cidrsString); err == nil {
+			cidrAllocationDuration.Observe(time.Since(data.startTime).Seconds())
 			return data, nil
 		}
 	}
 	// failed release back to the pool
 	klog.Errorf("Failed to update node %v PodCIDR to %v after multiple attempts: %v", node.Name, cidrsString, err)
+	cidrAllocationFailures.WithLabelValues("CIDRAssignmentFailed").Inc()
 	nodeutil.RecordNodeStatusChange(r.recorder, node, "CIDRAssignmentFailed")
 	// We accept the fact that we may leak CIDRs here. This is safer than releasing
 	// them in case when we don't know if request went through.
 	// NodeController restart will return all falsely allocated CIDRs to the pool.
 	if !apierrors.IsServerTimeout(err) {
 		klog.Errorf("CIDR assignment for node %v failed: %v. Releasing allocated CIDR", node.Name, err)
-		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := r.cidrSets[idx].Release(cidr); releaseErr != nil {
-				klog.Errorf("Error releasing allocated CIDR for node %v: %v", node.Name, releaseErr)
-			}
-		}
+		r.releaseCIDRs(node.Name, data.allocatedCIDRs)
 		data.allocatedCIDRs = nil
 	}
 	return data, err
@@ -19,15 +19,20 @@ package ipam
 import (
 	"context"
 	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	netutils "k8s.io/utils/net"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/util/controller/testutil"
 )
@@ -602,6 +607,405 @@ func TestAllocateOrOccupyCIDRSuccess(t *testing.T) {
 	}
 }
 
+func TestOccupySingleStackCIDRInDualStackCluster(t *testing.T) {
+	_, clusterCIDRv4, _ := net.ParseCIDR("10.10.0.0/16")
+	_, clusterCIDRv6, _ := net.ParseCIDR("ace:cab:deca::/48")
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node0",
+				},
+				Spec: v1.NodeSpec{
+					PodCIDRs: []string{"10.10.0.0/24"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1",
+				},
+				Spec: v1.NodeSpec{
+					PodCIDRs: []string{"ace:cab:deca::/64", "10.10.1.0/24"},
+				},
+			},
+		},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{clusterCIDRv4, clusterCIDRv6},
+		NodeCIDRMaskSizes: []int{24, 64},
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil)
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+	rangeAllocator.nodesSynced = alwaysReady
+	recorder := testutil.NewFakeRecorder()
+	rangeAllocator.recorder = recorder
+
+	for _, node := range fakeNodeHandler.Existing {
+		if err := allocator.AllocateOrOccupyCIDR(node); err != nil {
+			t.Errorf("unexpected error in AllocateOrOccupyCIDR: %v", err)
+		}
+	}
+
+	// The pod CIDRs can't be changed, so the single-stack node is not updated and gets a warning event.
+	if updatedNodes := fakeNodeHandler.GetUpdatedNodesCopy(); len(updatedNodes) != 0 {
+		t.Errorf("unexpected updated nodes: %v", updatedNodes)
+	}
+	var eventNodeNames []string
+	for _, event := range recorder.Events {
+		if event.Reason == "CIDRNotConvertible" {
+			eventNodeNames = append(eventNodeNames, event.InvolvedObject.Name)
+		}
+	}
+	if len(eventNodeNames) != 1 || eventNodeNames[0] != "node0" {
+		t.Errorf("expected a CIDRNotConvertible event of node0, found %v", eventNodeNames)
+	}
+
+	// The pod CIDRs of both nodes are occupied regardless of their order.
+	allocated, err := rangeAllocator.allocatePodCIDRs(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}})
+	if err != nil {
+		t.Fatalf("unexpected error in allocatePodCIDRs: %v", err)
+	}
+	if expected := []string{"10.10.2.0/24", "ace:cab:deca:1::/64"}; len(allocated) != 2 || allocated[0].String() != expected[0] || allocated[1].String() != expected[1] {
+		t.Errorf("expected CIDRs %v, found %v", expected, allocated)
+	}
+}
+
+func TestSeedCIDRsFromCIDRStateFile(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR("10.10.0.0/16")
+	existingNode := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+		Spec:       v1.NodeSpec{PodCIDRs: []string{"10.10.0.0/24"}},
+	}
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing:  []*v1.Node{&existingNode},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	stateFile := filepath.Join(t.TempDir(), "cidr-state.json")
+	state := `{"nodes":[
+{"name":"node0","podCIDRs":["10.10.5.0/24"]},
+{"name":"node1","podCIDRs":["10.10.1.0/24"]},
+{"name":"node2","podCIDRs":["10.10.0.128/25"]},
+{"name":"node3","podCIDRs":["10.20.0.0/24"]}
+]}`
+	if err := os.WriteFile(stateFile, []byte(state), 0600); err != nil {
+		t.Fatalf("failed to write the cidr state file: %v", err)
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{clusterCIDR},
+		NodeCIDRMaskSizes: []int{24},
+		NodeCIDRStateFile: stateFile,
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, &v1.NodeList{Items: []v1.Node{existingNode}})
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+
+	// Only the seed of node1 is kept: node0 has pod CIDRs, the seed of node2 overlaps with them and
+	// the one of node3 is out of the cluster CIDR.
+	for _, tc := range []struct {
+		nodeName string
+		expected string
+	}{
+		{"node1", "10.10.1.0/24"},
+		{"node2", "10.10.2.0/24"},
+		{"node3", "10.10.3.0/24"},
+		{"node1", "10.10.4.0/24"},
+	} {
+		allocated, err := rangeAllocator.allocatePodCIDRs(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: tc.nodeName}})
+		if err != nil {
+			t.Fatalf("unexpected error in allocatePodCIDRs: %v", err)
+		}
+		if len(allocated) != 1 || allocated[0].String() != tc.expected {
+			t.Errorf("expected CIDR %s for %s, found %v", tc.expected, tc.nodeName, allocated)
+		}
+	}
+
+	allocatorParams.NodeCIDRStateFile = filepath.Join(t.TempDir(), "not-found.json")
+	if _, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil); err == nil {
+		t.Errorf("expected an error for the missing cidr state file")
+	}
+}
+
+func TestAllocateNodePoolCIDRMaskSizes(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR("10.10.0.0/22")
+	small, _ := labels.Parse("agentpool=small")
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "small0", Labels: map[string]string{"agentpool": "small"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "large0", Labels: map[string]string{"agentpool": "large"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "small1", Labels: map[string]string{"agentpool": "small"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "large1", Labels: map[string]string{"agentpool": "large"}}},
+		},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:          []*net.IPNet{clusterCIDR},
+		NodeCIDRMaskSizes:     []int{24},
+		NodePoolCIDRMaskSizes: []NodePoolCIDRMaskSize{{Selector: small, NodeCIDRMaskSize: 26}},
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil)
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+	rangeAllocator.nodesSynced = alwaysReady
+	rangeAllocator.recorder = testutil.NewFakeRecorder()
+	go allocator.Run(context.Background())
+
+	for _, node := range fakeNodeHandler.Existing {
+		if err := allocator.AllocateOrOccupyCIDR(node); err != nil {
+			t.Errorf("unexpected error in AllocateOrOccupyCIDR: %v", err)
+		}
+	}
+	if err := waitForUpdatedNodeWithTimeout(fakeNodeHandler, len(fakeNodeHandler.Existing), wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("timeout while waiting for Node update: %v", err)
+	}
+
+	// The nodes of the small pool share a /24 without overlapping the /24s of the other nodes.
+	expectedPodCIDRs := map[string]string{
+		"small0": "10.10.0.0/26",
+		"large0": "10.10.1.0/24",
+		"small1": "10.10.0.64/26",
+		"large1": "10.10.2.0/24",
+	}
+	for _, updatedNode := range fakeNodeHandler.GetUpdatedNodesCopy() {
+		if len(updatedNode.Spec.PodCIDRs) != 1 || updatedNode.Spec.PodCIDRs[0] != expectedPodCIDRs[updatedNode.Name] {
+			t.Errorf("expected CIDR %v for node %s, found %v", expectedPodCIDRs[updatedNode.Name], updatedNode.Name, updatedNode.Spec.PodCIDRs)
+		}
+	}
+
+	// The large pool gets the released /24 instead of the /24 partially released by the small pool.
+	for _, cidr := range []string{"10.10.1.0/24", "10.10.0.64/26"} {
+		_, podCIDR, _ := net.ParseCIDR(cidr)
+		if err := rangeAllocator.cidrSets[0].Release(podCIDR); err != nil {
+			t.Fatalf("unexpected error when releasing CIDR %v: %v", cidr, err)
+		}
+	}
+	allocated, err := rangeAllocator.allocatePodCIDRs(fakeNodeHandler.Existing[1])
+	if err != nil || len(allocated) != 1 || allocated[0].String() != "10.10.1.0/24" {
+		t.Errorf("expected CIDR 10.10.1.0/24 for the large pool, found %v, %v", allocated, err)
+	}
+}
+
+func TestAllocateFromAdditionalClusterCIDRs(t *testing.T) {
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.10.0.0/23"})
+	additionalClusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.20.0.0/23", "10.30.0.0/24"})
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node0"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node3"}},
+		},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	existingNode := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+		Spec:       v1.NodeSpec{PodCIDR: "10.20.0.0/24", PodCIDRs: []string{"10.20.0.0/24"}},
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:           clusterCIDRs,
+		AdditionalClusterCIDRs: additionalClusterCIDRs,
+		NodeCIDRMaskSizes:      []int{24},
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, &v1.NodeList{Items: []v1.Node{existingNode}})
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+	rangeAllocator.nodesSynced = alwaysReady
+	rangeAllocator.recorder = testutil.NewFakeRecorder()
+	go allocator.Run(context.Background())
+
+	for _, node := range fakeNodeHandler.Existing {
+		if err := allocator.AllocateOrOccupyCIDR(node); err != nil {
+			t.Errorf("unexpected error in AllocateOrOccupyCIDR: %v", err)
+		}
+	}
+	if err := waitForUpdatedNodeWithTimeout(fakeNodeHandler, len(fakeNodeHandler.Existing), wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("timeout while waiting for Node update: %v", err)
+	}
+
+	// The additional cluster cidrs are used in order once the cluster cidr is exhausted, skipping the
+	// pod cidr of the existing node.
+	expectedPodCIDRs := map[string]string{
+		"node0": "10.10.0.0/24",
+		"node1": "10.10.1.0/24",
+		"node2": "10.20.1.0/24",
+		"node3": "10.30.0.0/24",
+	}
+	for _, updatedNode := range fakeNodeHandler.GetUpdatedNodesCopy() {
+		if len(updatedNode.Spec.PodCIDRs) != 1 || updatedNode.Spec.PodCIDRs[0] != expectedPodCIDRs[updatedNode.Name] {
+			t.Errorf("expected CIDR %v for node %s, found %v", expectedPodCIDRs[updatedNode.Name], updatedNode.Name, updatedNode.Spec.PodCIDRs)
+		}
+	}
+
+	newNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node4"}}
+	if _, err := rangeAllocator.allocatePodCIDRs(newNode); err == nil {
+		t.Errorf("expected an error when all the cluster cidrs are exhausted")
+	}
+	if err := allocator.ReleaseCIDR(&existingNode); err != nil {
+		t.Fatalf("unexpected error in ReleaseCIDR: %v", err)
+	}
+	allocated, err := rangeAllocator.allocatePodCIDRs(newNode)
+	if err != nil || len(allocated) != 1 || allocated[0].String() != "10.20.0.0/24" {
+		t.Errorf("expected the released CIDR 10.20.0.0/24, found %v, %v", allocated, err)
+	}
+}
+
+func TestAllocateNodePoolClusterCIDRs(t *testing.T) {
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.10.0.0/23", "fd00::/112"})
+	windowsCIDRs, _ := netutils.ParseCIDRs([]string{"10.40.0.0/24"})
+	windows, _ := labels.Parse("kubernetes.io/os=windows")
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "linux0", Labels: map[string]string{"kubernetes.io/os": "linux"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "windows0", Labels: map[string]string{"kubernetes.io/os": "windows"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "linux1", Labels: map[string]string{"kubernetes.io/os": "linux"}}},
+		},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:         clusterCIDRs,
+		NodeCIDRMaskSizes:    []int{24, 120},
+		NodePoolClusterCIDRs: []NodePoolClusterCIDR{{Selector: windows, ClusterCIDRs: windowsCIDRs}},
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil)
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+	rangeAllocator.nodesSynced = alwaysReady
+	rangeAllocator.recorder = testutil.NewFakeRecorder()
+	go allocator.Run(context.Background())
+
+	for _, node := range fakeNodeHandler.Existing {
+		if err := allocator.AllocateOrOccupyCIDR(node); err != nil {
+			t.Errorf("unexpected error in AllocateOrOccupyCIDR: %v", err)
+		}
+	}
+	if err := waitForUpdatedNodeWithTimeout(fakeNodeHandler, len(fakeNodeHandler.Existing), wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("timeout while waiting for Node update: %v", err)
+	}
+
+	// The windows node gets the IPv4 cidr from its node pool, and the IPv6 cidr from the cluster cidr.
+	expectedPodCIDRs := map[string][]string{
+		"linux0":   {"10.10.0.0/24", "fd00::/120"},
+		"windows0": {"10.40.0.0/24", "fd00::100/120"},
+		"linux1":   {"10.10.1.0/24", "fd00::200/120"},
+	}
+	for _, updatedNode := range fakeNodeHandler.GetUpdatedNodesCopy() {
+		if !reflect.DeepEqual(updatedNode.Spec.PodCIDRs, expectedPodCIDRs[updatedNode.Name]) {
+			t.Errorf("expected CIDRs %v for node %s, found %v", expectedPodCIDRs[updatedNode.Name], updatedNode.Name, updatedNode.Spec.PodCIDRs)
+		}
+	}
+
+	// The windows nodes do not fall back to the cluster cidr once the node pool cidr is exhausted.
+	if _, err := rangeAllocator.allocatePodCIDRs(fakeNodeHandler.Existing[1]); err == nil {
+		t.Errorf("expected an error when the cluster cidr of the node pool is exhausted")
+	}
+	if err := allocator.ReleaseCIDR(&v1.Node{Spec: v1.NodeSpec{PodCIDRs: expectedPodCIDRs["windows0"]}}); err != nil {
+		t.Fatalf("unexpected error in ReleaseCIDR: %v", err)
+	}
+	allocated, err := rangeAllocator.allocatePodCIDRs(fakeNodeHandler.Existing[1])
+	if err != nil || len(allocated) != 2 || allocated[0].String() != "10.40.0.0/24" {
+		t.Errorf("expected the released CIDR 10.40.0.0/24, found %v, %v", allocated, err)
+	}
+
+	for _, tc := range []struct {
+		nodePoolCIDRs []string
+		expectedErr   string
+	}{
+		{[]string{"10.10.1.0/24"}, "cluster cidr 10.10.1.0/24 of node pool \"kubernetes.io/os=windows\" overlaps with cluster cidr 10.10.0.0/23"},
+		{[]string{"10.40.0.0/25"}, "mask size of cluster cidr 10.40.0.0/25 of node pool \"kubernetes.io/os=windows\" must be less than or equal to the node cidr mask size 24"},
+	} {
+		nodePoolCIDRs, _ := netutils.ParseCIDRs(tc.nodePoolCIDRs)
+		allocatorParams.NodePoolClusterCIDRs = []NodePoolClusterCIDR{{Selector: windows, ClusterCIDRs: nodePoolCIDRs}}
+		if _, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil); err == nil || err.Error() != tc.expectedErr {
+			t.Errorf("expected error %q for node pool cidrs %v, found %v", tc.expectedErr, tc.nodePoolCIDRs, err)
+		}
+	}
+}
+
+func TestCIDRUtilizationEvent(t *testing.T) {
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.10.0.0/22"})
+	fakeNodeHandler := &testutil.FakeNodeHandler{Clientset: fake.NewSimpleClientset()}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), CIDRAllocatorParams{
+		ClusterCIDRs:             clusterCIDRs,
+		NodeCIDRMaskSizes:        []int{24},
+		CIDRUtilizationThreshold: 50,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create CIDRRangeAllocator with error %v", err)
+	}
+	rangeAllocator := allocator.(*rangeAllocator)
+	recorder := testutil.NewFakeRecorder()
+	rangeAllocator.recorder = recorder
+
+	utilizationEvents := func() []string {
+		var nodeNames []string
+		for _, event := range recorder.Events {
+			if event.Reason == "CIDRUtilizationHigh" {
+				nodeNames = append(nodeNames, event.InvolvedObject.Name)
+			}
+		}
+		return nodeNames
+	}
+	allocate := func(nodeName string) {
+		if err := allocator.AllocateOrOccupyCIDR(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}); err != nil {
+			t.Fatalf("unexpected error in AllocateOrOccupyCIDR: %v", err)
+		}
+	}
+
+	// The event is recorded once when the utilization reaches the threshold.
+	for _, nodeName := range []string{"node0", "node1", "node2"} {
+		allocate(nodeName)
+	}
+	if events := utilizationEvents(); len(events) != 1 || events[0] != "node1" {
+		t.Errorf("expected a utilization event on node1, found %v", events)
+	}
+
+	// The event is recorded again after the utilization drops below the threshold.
+	for _, cidr := range []string{"10.10.0.0/24", "10.10.1.0/24"} {
+		if err := allocator.ReleaseCIDR(&v1.Node{Spec: v1.NodeSpec{PodCIDRs: []string{cidr}}}); err != nil {
+			t.Fatalf("unexpected error in ReleaseCIDR: %v", err)
+		}
+	}
+	allocate("node3")
+	if events := utilizationEvents(); len(events) != 2 || events[1] != "node3" {
+		t.Errorf("expected utilization events on node1 and node3, found %v", events)
+	}
+}
+
+func TestNewCIDRRangeAllocatorWithInvalidAdditionalClusterCIDRs(t *testing.T) {
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.10.0.0/16"})
+	for _, tc := range []struct {
+		additionalClusterCIDRs []string
+		expectedErr            string
+	}{
+		{[]string{"10.10.128.0/17"}, "additional cluster cidr 10.10.128.0/17 overlaps with cluster cidr 10.10.0.0/16"},
+		{[]string{"10.20.0.0/16", "10.20.1.0/24"}, "additional cluster cidr 10.20.1.0/24 overlaps with additional cluster cidr 10.20.0.0/16"},
+		{[]string{"fd00::/64"}, "additional cluster cidr fd00::/64 has no cluster cidr of the same ip family"},
+		{[]string{"10.20.0.0/25"}, "mask size of additional cluster cidr 10.20.0.0/25 must be less than or equal to the node cidr mask size 24"},
+	} {
+		additionalClusterCIDRs, _ := netutils.ParseCIDRs(tc.additionalClusterCIDRs)
+		fakeNodeHandler := &testutil.FakeNodeHandler{Clientset: fake.NewSimpleClientset()}
+		_, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), CIDRAllocatorParams{
+			ClusterCIDRs:           clusterCIDRs,
+			AdditionalClusterCIDRs: additionalClusterCIDRs,
+			NodeCIDRMaskSizes:      []int{24},
+		}, nil)
+		if err == nil || err.Error() != tc.expectedErr {
+			t.Errorf("expected error %q for additional cluster cidrs %v, found %v", tc.expectedErr, tc.additionalClusterCIDRs, err)
+		}
+	}
+}
+
 func TestAllocateOrOccupyCIDRFailure(t *testing.T) {
 	testCases := []testCase{
 		{
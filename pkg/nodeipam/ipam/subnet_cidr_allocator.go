@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+	informers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+
+	providerazure "sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// podSubnetAddressPrefixesGetter returns the address prefixes of the pod subnet.
+type podSubnetAddressPrefixesGetter interface {
+	GetPodSubnetAddressPrefixes(ctx context.Context, subnetName string) ([]string, error)
+}
+
+// NewSubnetCIDRAllocator creates a CIDR allocator allocating the node CIDRs from the address prefixes of the
+// pod subnet instead of the whole cluster CIDRs. Nothing is reserved through the network API, the allocated
+// blocks are only tracked on the node pod CIDRs like in the range allocator, so the subnet must be dedicated to
+// the node CIDRs. The allocation of Azure CNI in delegated subnets is not supported, and those subnets are rejected.
+// Each cluster CIDR is replaced by the first address prefix of the pod subnet in it, and the blocks containing
+// the addresses Azure reserves in the subnet are never allocated.
+func NewSubnetCIDRAllocator(client clientset.Interface, cloud cloudprovider.Interface, nodeInformer informers.NodeInformer, allocatorParams CIDRAllocatorParams, nodeList *v1.NodeList) (CIDRAllocator, error) {
+	az, ok := cloud.(*providerazure.Cloud)
+	if !ok {
+		return nil, fmt.Errorf("subnetCIDRAllocator does not support %v provider", cloud.ProviderName())
+	}
+	return newSubnetCIDRAllocator(client, az, nodeInformer, allocatorParams, nodeList)
+}
+
+func newSubnetCIDRAllocator(client clientset.Interface, getter podSubnetAddressPrefixesGetter, nodeInformer informers.NodeInformer, allocatorParams CIDRAllocatorParams, nodeList *v1.NodeList) (CIDRAllocator, error) {
+	if allocatorParams.PodSubnetName == "" {
+		return nil, fmt.Errorf("subnetCIDRAllocator requires the pod subnet name")
+	}
+	prefixes, err := getter.GetPodSubnetAddressPrefixes(context.Background(), allocatorParams.PodSubnetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the address prefixes of pod subnet %s: %w", allocatorParams.PodSubnetName, err)
+	}
+	subnetCIDRs, err := netutils.ParseCIDRs(prefixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the address prefixes of pod subnet %s: %w", allocatorParams.PodSubnetName, err)
+	}
+
+	podSubnetParams := allocatorParams
+	if len(allocatorParams.AdditionalClusterCIDRs) > 0 {
+		klog.Warningf("newSubnetCIDRAllocator: ignoring the additional cluster cidrs %v, the node cidrs are allocated from pod subnet %s", allocatorParams.AdditionalClusterCIDRs, allocatorParams.PodSubnetName)
+		podSubnetParams.AdditionalClusterCIDRs = nil
+	}
+	if len(allocatorParams.NodePoolClusterCIDRs) > 0 {
+		klog.Warningf("newSubnetCIDRAllocator: ignoring the node pool cluster cidrs, the node cidrs are allocated from pod subnet %s", allocatorParams.PodSubnetName)
+		podSubnetParams.NodePoolClusterCIDRs = nil
+	}
+	podSubnetParams.ClusterCIDRs = make([]*net.IPNet, len(allocatorParams.ClusterCIDRs))
+	for idx, clusterCIDR := range allocatorParams.ClusterCIDRs {
+		subnetCIDR := getSubnetCIDRInClusterCIDR(subnetCIDRs, clusterCIDR)
+		if subnetCIDR == nil {
+			return nil, fmt.Errorf("pod subnet %s has no address prefix in cluster cidr %v", allocatorParams.PodSubnetName, clusterCIDR)
+		}
+		if maskSize, _ := subnetCIDR.Mask.Size(); maskSize > allocatorParams.NodeCIDRMaskSizes[idx] {
+			return nil, fmt.Errorf("address prefix %v of pod subnet %s is smaller than the node cidr mask size %d", subnetCIDR, allocatorParams.PodSubnetName, allocatorParams.NodeCIDRMaskSizes[idx])
+		}
+		klog.V(2).Infof("subnetCIDRAllocator: allocating the node cidrs of cluster cidr %v from pod subnet address prefix %v", clusterCIDR, subnetCIDR)
+		podSubnetParams.ClusterCIDRs[idx] = subnetCIDR
+	}
+
+	allocator, err := NewCIDRRangeAllocator(client, nodeInformer, podSubnetParams, nodeList)
+	if err != nil {
+		return nil, err
+	}
+	ra := allocator.(*rangeAllocator)
+	for idx, subnetCIDR := range podSubnetParams.ClusterCIDRs {
+		for _, reserved := range getSubnetReservedCIDRs(subnetCIDR) {
+			if err := ra.cidrSets[idx].Occupy(reserved); err != nil {
+				return nil, fmt.Errorf("failed to occupy the reserved addresses %v of pod subnet %s: %w", reserved, allocatorParams.PodSubnetName, err)
+			}
+		}
+	}
+	return ra, nil
+}
+
+// getSubnetCIDRInClusterCIDR returns the first subnet cidr in the cluster cidr.
+func getSubnetCIDRInClusterCIDR(subnetCIDRs []*net.IPNet, clusterCIDR *net.IPNet) *net.IPNet {
+	clusterMaskSize, _ := clusterCIDR.Mask.Size()
+	for _, subnetCIDR := range subnetCIDRs {
+		maskSize, _ := subnetCIDR.Mask.Size()
+		if netutils.IsIPv6CIDR(subnetCIDR) == netutils.IsIPv6CIDR(clusterCIDR) && maskSize >= clusterMaskSize && clusterCIDR.Contains(subnetCIDR.IP) {
+			return subnetCIDR
+		}
+	}
+	return nil
+}
+
+// getSubnetReservedCIDRs returns the cidrs of the addresses Azure reserves in the subnet,
+// which are the first four addresses and the last one.
+func getSubnetReservedCIDRs(subnetCIDR *net.IPNet) []*net.IPNet {
+	first := subnetCIDR.IP.Mask(subnetCIDR.Mask)
+	last := make(net.IP, len(first))
+	for i := range first {
+		last[i] = first[i] | ^subnetCIDR.Mask[i]
+	}
+	bits := 8 * len(first)
+	return []*net.IPNet{
+		{IP: first, Mask: net.CIDRMask(bits-2, bits)},
+		{IP: last, Mask: net.CIDRMask(bits, bits)},
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/controller/testutil"
+)
+
+type fakePodSubnetAddressPrefixesGetter map[string][]string
+
+func (f fakePodSubnetAddressPrefixesGetter) GetPodSubnetAddressPrefixes(_ context.Context, subnetName string) ([]string, error) {
+	return f[subnetName], nil
+}
+
+func TestSubnetCIDRAllocator(t *testing.T) {
+	_, clusterCIDRv4, _ := net.ParseCIDR("10.0.0.0/8")
+	_, clusterCIDRv6, _ := net.ParseCIDR("fd00::/48")
+	getter := fakePodSubnetAddressPrefixesGetter{
+		"pods":  {"192.168.0.0/24", "10.1.0.0/26", "fd00:0:0:4::/62"},
+		"small": {"10.1.0.0/28"},
+	}
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing:  []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node0"}}},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	params := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{clusterCIDRv4, clusterCIDRv6},
+		NodeCIDRMaskSizes: []int{28, 64},
+		PodSubnetName:     "pods",
+	}
+
+	allocator, err := newSubnetCIDRAllocator(fakeNodeHandler, getter, getFakeNodeInformer(fakeNodeHandler), params, nil)
+	assert.NoError(t, err)
+	ra := allocator.(*rangeAllocator)
+	assert.Equal(t, []string{"10.1.0.0/26", "fd00:0:0:4::/62"}, cidrsAsString(ra.clusterCIDRs))
+
+	// The blocks with the first four and the last addresses reserved by Azure are skipped.
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node0"}}
+	var allocated []string
+	for {
+		cidrs, err := ra.allocatePodCIDRs(node)
+		if err != nil {
+			break
+		}
+		allocated = append(allocated, cidrsAsString(cidrs)...)
+	}
+	assert.Equal(t, []string{"10.1.0.16/28", "fd00:0:0:5::/64", "10.1.0.32/28", "fd00:0:0:6::/64"}, allocated)
+
+	params.PodSubnetName = "small"
+	_, err = newSubnetCIDRAllocator(fakeNodeHandler, getter, getFakeNodeInformer(fakeNodeHandler), params, nil)
+	assert.EqualError(t, err, "pod subnet small has no address prefix in cluster cidr fd00::/48")
+
+	params.ClusterCIDRs, params.NodeCIDRMaskSizes = []*net.IPNet{clusterCIDRv4}, []int{24}
+	_, err = newSubnetCIDRAllocator(fakeNodeHandler, getter, getFakeNodeInformer(fakeNodeHandler), params, nil)
+	assert.EqualError(t, err, "address prefix 10.1.0.0/28 of pod subnet small is smaller than the node cidr mask size 24")
+}
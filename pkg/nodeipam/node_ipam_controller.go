@@ -108,16 +108,24 @@ func RegisterMetricAndTrackRateLimiterUsage(ownerName string, _ flowcontrol.Rate
 // sync instances from cloudprovider.
 // This method returns an error if it is unable to initialize the CIDR bitmap with
 // podCIDRs it has already allocated to nodes. Since we don't allow podCIDR changes
-// currently, this should be handled as a fatal error.
+// currently, this should be handled as a fatal error. The syncObserver, if not nil, is
+// called with the result of each update of the pod CIDRs of a node.
 func NewNodeIpamController(
 	nodeInformer coreinformers.NodeInformer,
 	cloud cloudprovider.Interface,
 	kubeClient clientset.Interface,
 	clusterCIDRs []*net.IPNet,
+	additionalClusterCIDRs []*net.IPNet,
 	serviceCIDR *net.IPNet,
 	secondaryServiceCIDR *net.IPNet,
 	nodeCIDRMaskSizes []int,
-	allocatorType ipam.CIDRAllocatorType) (*Controller, error) {
+	nodePoolCIDRMaskSizes []ipam.NodePoolCIDRMaskSize,
+	nodePoolClusterCIDRs []ipam.NodePoolClusterCIDR,
+	cidrUtilizationThreshold int,
+	podSubnetName string,
+	nodeCIDRStateFile string,
+	allocatorType ipam.CIDRAllocatorType,
+	syncObserver func(err error)) (*Controller, error) {
 
 	if kubeClient == nil {
 		klog.Fatalf("kubeClient is nil when starting Controller")
@@ -169,10 +177,17 @@ func NewNodeIpamController(
 	var err error
 
 	allocatorParams := ipam.CIDRAllocatorParams{
-		ClusterCIDRs:         clusterCIDRs,
-		ServiceCIDR:          ic.serviceCIDR,
-		SecondaryServiceCIDR: ic.secondaryServiceCIDR,
-		NodeCIDRMaskSizes:    nodeCIDRMaskSizes,
+		ClusterCIDRs:             clusterCIDRs,
+		AdditionalClusterCIDRs:   additionalClusterCIDRs,
+		ServiceCIDR:              ic.serviceCIDR,
+		SecondaryServiceCIDR:     ic.secondaryServiceCIDR,
+		NodeCIDRMaskSizes:        nodeCIDRMaskSizes,
+		NodePoolCIDRMaskSizes:    nodePoolCIDRMaskSizes,
+		NodePoolClusterCIDRs:     nodePoolClusterCIDRs,
+		CIDRUtilizationThreshold: cidrUtilizationThreshold,
+		PodSubnetName:            podSubnetName,
+		NodeCIDRStateFile:        nodeCIDRStateFile,
+		SyncObserver:             syncObserver,
 	}
 
 	ic.cidrAllocator, err = ipam.New(kubeClient, cloud, nodeInformer, ic.allocatorType, allocatorParams)
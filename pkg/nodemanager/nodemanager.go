@@ -208,12 +208,57 @@ func (cnc *CloudNodeController) UpdateNodeStatus(ctx context.Context) {
 		klog.Errorf("Error reconciling node address for node %q, err: %v", node.Name, err)
 	}
 
+	err = cnc.reconcileNodeZone(ctx, node)
+	if err != nil {
+		klog.Errorf("Error reconciling node zone for node %q, err: %v", node.Name, err)
+	}
+
 	err = cnc.reconcileNodeLabels(node)
 	if err != nil {
 		klog.Errorf("Error reconciling node labels for node %q, err: %v", node.Name, err)
 	}
 }
 
+// reconcileNodeZone updates the topology labels of the node if they don't match the zone reported
+// by the cloud provider, e.g. after the VM is recreated in another zone by a SKU migration.
+func (cnc *CloudNodeController) reconcileNodeZone(ctx context.Context, node *v1.Node) error {
+	// The topology labels of the nodes still tainted are set by the initialization.
+	if GetCloudTaint(node.Spec.Taints) != nil {
+		return nil
+	}
+
+	zone, err := cnc.getZoneByName(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	labelsToUpdate := map[string]string{}
+	addLabelIfMismatched := func(key, value string) {
+		if value != "" && node.Labels[key] != value {
+			labelsToUpdate[key] = value
+		}
+	}
+	addLabelIfMismatched(v1.LabelZoneFailureDomainStable, zone.FailureDomain)
+	addLabelIfMismatched(v1.LabelZoneRegionStable, zone.Region)
+	if cnc.enableBetaTopologyLabels {
+		addLabelIfMismatched(v1.LabelZoneFailureDomain, zone.FailureDomain)
+		addLabelIfMismatched(v1.LabelZoneRegion, zone.Region)
+	}
+	if len(labelsToUpdate) == 0 {
+		return nil
+	}
+
+	klog.Warningf("The topology labels of node %q don't match the zone %q in region %q, updating labels %v", node.Name, zone.FailureDomain, zone.Region, labelsToUpdate)
+	cnc.recorder.Eventf(node, v1.EventTypeWarning, "TopologyLabelsMismatched",
+		"The topology labels %s=%q, %s=%q don't match the zone %q in region %q reported by the cloud provider, updating the labels",
+		v1.LabelZoneFailureDomainStable, node.Labels[v1.LabelZoneFailureDomainStable], v1.LabelZoneRegionStable, node.Labels[v1.LabelZoneRegionStable],
+		zone.FailureDomain, zone.Region)
+	if !cloudnodeutil.AddOrUpdateLabelsOnNode(cnc.kubeClient, labelsToUpdate, node) {
+		return fmt.Errorf("failed update topology labels for node %+v", node)
+	}
+	return nil
+}
+
 // reconcileNodeLabels reconciles node labels transitioning from beta to GA
 func (cnc *CloudNodeController) reconcileNodeLabels(node *v1.Node) error {
 	if node.Labels == nil {
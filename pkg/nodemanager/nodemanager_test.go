@@ -658,6 +658,7 @@ func TestUpdateNodeAddresses(t *testing.T) {
 			Address: "10.0.0.1",
 		},
 	}, nil)
+	mockNP.EXPECT().GetZone(ctx, types.NodeName("node0")).Return(cloudprovider.Zone{}, nil)
 	cloudNodeController.UpdateNodeStatus(ctx)
 	updatedNodes := fnh.GetUpdatedNodesCopy()
 	assert.Equal(t, 2, len(updatedNodes[0].Status.Addresses), "Node Addresses not correctly updated")
@@ -876,6 +877,97 @@ func Test_reconcileNodeLabels(t *testing.T) {
 	}
 }
 
+func Test_reconcileNodeZone(t *testing.T) {
+	testcases := []struct {
+		name                     string
+		labels                   map[string]string
+		taints                   []v1.Taint
+		zone                     cloudprovider.Zone
+		enableBetaTopologyLabels bool
+		expectGetZone            bool
+		expectedLabels           map[string]string
+		expectedEvent            bool
+	}{
+		{
+			name: "should not update the labels matching the zone",
+			labels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-1",
+				v1.LabelZoneRegionStable:        "eastus",
+			},
+			zone:          cloudprovider.Zone{FailureDomain: "eastus-1", Region: "eastus"},
+			expectGetZone: true,
+			expectedLabels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-1",
+				v1.LabelZoneRegionStable:        "eastus",
+			},
+		},
+		{
+			name: "should update the labels of the VM moved to another zone",
+			labels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-1",
+				v1.LabelZoneRegionStable:        "eastus",
+				v1.LabelZoneFailureDomain:       "eastus-1",
+				v1.LabelZoneRegion:              "eastus",
+			},
+			zone:                     cloudprovider.Zone{FailureDomain: "eastus-2", Region: "eastus"},
+			enableBetaTopologyLabels: true,
+			expectGetZone:            true,
+			expectedLabels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-2",
+				v1.LabelZoneRegionStable:        "eastus",
+				v1.LabelZoneFailureDomain:       "eastus-2",
+				v1.LabelZoneRegion:              "eastus",
+			},
+			expectedEvent: true,
+		},
+		{
+			name: "should not update the labels of the nodes not initialized",
+			labels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-1",
+			},
+			taints: []v1.Taint{{Key: cloudproviderapi.TaintExternalCloudProvider, Value: "true", Effect: v1.TaintEffectNoSchedule}},
+			expectedLabels: map[string]string{
+				v1.LabelZoneFailureDomainStable: "eastus-1",
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			testNode := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node01",
+					Labels: test.labels,
+				},
+				Spec: v1.NodeSpec{Taints: test.taints},
+			}
+
+			clientset := fake.NewSimpleClientset(testNode)
+			mockNP := mocknodeprovider.NewMockNodeProvider(ctrl)
+			if test.expectGetZone {
+				mockNP.EXPECT().GetZone(gomock.Any(), types.NodeName("node01")).Return(test.zone, nil)
+			}
+			recorder := record.NewFakeRecorder(10)
+			cnc := &CloudNodeController{
+				kubeClient:               clientset,
+				nodeProvider:             mockNP,
+				recorder:                 recorder,
+				enableBetaTopologyLabels: test.enableBetaTopologyLabels,
+			}
+
+			assert.NoError(t, cnc.reconcileNodeZone(context.TODO(), testNode))
+
+			actualNode, err := clientset.CoreV1().Nodes().Get(context.TODO(), "node01", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedLabels, actualNode.Labels)
+			assert.Equal(t, test.expectedEvent, len(recorder.Events) == 1)
+		})
+	}
+}
+
 // Tests that node address changes are detected correctly
 func TestNodeAddressesChangeDetected(t *testing.T) {
 	testcases := []struct {
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	cloudnodeutil "k8s.io/cloud-provider/node/helpers"
+	nodeutil "k8s.io/component-helpers/node/util"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ScheduledEventTypePreempt is the type of the scheduled event of the eviction of a spot VM.
+	ScheduledEventTypePreempt = "Preempt"
+	// ScheduledEventTypeFreeze is the type of the scheduled event of pausing the VM for a few
+	// seconds, e.g. during a live migration or a host update preserving the memory.
+	ScheduledEventTypeFreeze = "Freeze"
+	// ScheduledEventTypeReboot is the type of the scheduled event of rebooting the VM.
+	ScheduledEventTypeReboot = "Reboot"
+	// ScheduledEventTypeRedeploy is the type of the scheduled event of moving the VM to another
+	// host, which loses the data on the temporary disk.
+	ScheduledEventTypeRedeploy = "Redeploy"
+
+	// PreemptionScheduledTaintKey is the key of the taint added to the node when the VM is
+	// going to be preempted. The value is the ID of the scheduled event.
+	PreemptionScheduledTaintKey = "kubernetes.azure.com/preemption-scheduled"
+	// NodePreemptionScheduled is the type of the node condition set when the VM is going to be preempted.
+	NodePreemptionScheduled v1.NodeConditionType = "PreemptionScheduled"
+	// NodeAzureMaintenanceScheduled is the type of the node condition set when the VM has a
+	// scheduled Freeze, Reboot or Redeploy event.
+	NodeAzureMaintenanceScheduled v1.NodeConditionType = "AzureMaintenanceScheduled"
+)
+
+// maintenanceEventTypes are the types of the scheduled events reported by the
+// AzureMaintenanceScheduled condition.
+var maintenanceEventTypes = []string{ScheduledEventTypeFreeze, ScheduledEventTypeReboot, ScheduledEventTypeRedeploy}
+
+// ScheduledEvent is a scheduled maintenance event of the VM.
+type ScheduledEvent struct {
+	// ID is the ID of the event.
+	ID string
+	// Type is the type of the event, e.g. Preempt.
+	Type string
+	// NotBefore is the time after which the event can start, e.g. "Mon, 19 Sep 2016 18:29:47 GMT".
+	NotBefore string
+	// Description is the description of the event.
+	Description string
+}
+
+// ScheduledEventsProvider defines the interfaces for scheduled events provider.
+type ScheduledEventsProvider interface {
+	// GetScheduledEvents returns the scheduled events of the VM the program is running on.
+	GetScheduledEvents(ctx context.Context) ([]ScheduledEvent, error)
+}
+
+// ScheduledEventsController watches the scheduled events of the VM of the node, and taints
+// the node when the VM is going to be preempted. Spot VMs get at least 30 seconds of notice
+// before the eviction, so the taint keeps new pods away from the node and optionally evicts
+// the running pods before the VM is gone. The scheduled maintenance events of the VM are
+// reported by the AzureMaintenanceScheduled condition, so other controllers, e.g. the
+// descheduler, can move the workloads before the maintenance starts.
+type ScheduledEventsController struct {
+	nodeName       string
+	nodeInformer   coreinformers.NodeInformer
+	kubeClient     clientset.Interface
+	recorder       record.EventRecorder
+	eventsProvider ScheduledEventsProvider
+
+	pollInterval time.Duration
+	taintEffect  v1.TaintEffect
+}
+
+// NewScheduledEventsController creates a ScheduledEventsController object. The node is tainted
+// with NoExecute instead of NoSchedule if drain is true, which evicts the pods not tolerating
+// the taint from the node.
+func NewScheduledEventsController(
+	nodeName string,
+	nodeInformer coreinformers.NodeInformer,
+	kubeClient clientset.Interface,
+	recorder record.EventRecorder,
+	eventsProvider ScheduledEventsProvider,
+	pollInterval time.Duration,
+	drain bool) *ScheduledEventsController {
+	taintEffect := v1.TaintEffectNoSchedule
+	if drain {
+		taintEffect = v1.TaintEffectNoExecute
+	}
+
+	return &ScheduledEventsController{
+		nodeName:       nodeName,
+		nodeInformer:   nodeInformer,
+		kubeClient:     kubeClient,
+		recorder:       recorder,
+		eventsProvider: eventsProvider,
+		pollInterval:   pollInterval,
+		taintEffect:    taintEffect,
+	}
+}
+
+// Run polls the scheduled events of the VM. This call is blocking so should be called
+// via a goroutine
+func (sec *ScheduledEventsController) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := sec.reconcile(ctx); err != nil {
+			klog.Errorf("Error reconciling scheduled events for node %q, err: %v", sec.nodeName, err)
+		}
+	}, sec.pollInterval)
+}
+
+// reconcile updates the node by the scheduled events of the VM.
+func (sec *ScheduledEventsController) reconcile(ctx context.Context) error {
+	node, err := sec.nodeInformer.Lister().Get(sec.nodeName)
+	if err != nil {
+		// If node not found, just ignore it.
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	events, err := sec.eventsProvider.GetScheduledEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled events: %w", err)
+	}
+
+	if err := sec.reconcilePreemption(node, events); err != nil {
+		return err
+	}
+	return sec.reconcileMaintenance(node, events)
+}
+
+// reconcilePreemption taints the node and sets the PreemptionScheduled condition if the VM has a
+// scheduled preemption, and removes them if it does not have one anymore.
+func (sec *ScheduledEventsController) reconcilePreemption(node *v1.Node, events []ScheduledEvent) error {
+	var preemption *ScheduledEvent
+	for i := range events {
+		if strings.EqualFold(events[i].Type, ScheduledEventTypePreempt) {
+			preemption = &events[i]
+			break
+		}
+	}
+
+	existingTaint := getPreemptionScheduledTaint(node.Spec.Taints)
+	if preemption == nil {
+		if existingTaint == nil {
+			return nil
+		}
+		klog.Infof("The scheduled preemption %s of node %q is gone, removing the taint", existingTaint.Value, sec.nodeName)
+		if err := cloudnodeutil.RemoveTaintOffNode(sec.kubeClient, sec.nodeName, node, existingTaint); err != nil {
+			return err
+		}
+		return sec.setPreemptionScheduledCondition(v1.ConditionFalse, "PreemptionCanceled", "The VM does not have a scheduled preemption")
+	}
+
+	if existingTaint != nil && existingTaint.Value == preemption.ID && existingTaint.Effect == sec.taintEffect {
+		return nil
+	}
+
+	klog.Warningf("Node %q is going to be preempted not before %q, tainting the node with effect %s", sec.nodeName, preemption.NotBefore, sec.taintEffect)
+	sec.recorder.Eventf(node, v1.EventTypeWarning, "PreemptionScheduled",
+		"The VM is going to be preempted not before %s, tainting the node with effect %s", preemption.NotBefore, sec.taintEffect)
+	if existingTaint != nil && existingTaint.Effect != sec.taintEffect {
+		if err := cloudnodeutil.RemoveTaintOffNode(sec.kubeClient, sec.nodeName, node, existingTaint); err != nil {
+			return err
+		}
+	}
+	if err := cloudnodeutil.AddOrUpdateTaintOnNode(sec.kubeClient, sec.nodeName, &v1.Taint{
+		Key:    PreemptionScheduledTaintKey,
+		Value:  preemption.ID,
+		Effect: sec.taintEffect,
+	}); err != nil {
+		return err
+	}
+	return sec.setPreemptionScheduledCondition(v1.ConditionTrue, ScheduledEventTypePreempt,
+		fmt.Sprintf("The VM is going to be preempted not before %s", preemption.NotBefore))
+}
+
+// reconcileMaintenance sets the AzureMaintenanceScheduled condition to true with the types and
+// start times of the scheduled maintenance events of the VM, and to false after the events are
+// gone. The condition is only updated when it changes.
+func (sec *ScheduledEventsController) reconcileMaintenance(node *v1.Node, events []ScheduledEvent) error {
+	var maintenances []ScheduledEvent
+	for _, event := range events {
+		for _, eventType := range maintenanceEventTypes {
+			if strings.EqualFold(event.Type, eventType) {
+				maintenances = append(maintenances, event)
+				break
+			}
+		}
+	}
+
+	_, existingCondition := nodeutil.GetNodeCondition(&node.Status, NodeAzureMaintenanceScheduled)
+	if len(maintenances) == 0 {
+		if existingCondition == nil || existingCondition.Status == v1.ConditionFalse {
+			return nil
+		}
+		klog.Infof("The scheduled maintenance of node %q is gone", sec.nodeName)
+		return sec.setNodeCondition(NodeAzureMaintenanceScheduled, v1.ConditionFalse, "MaintenanceCompleted", "The VM does not have a scheduled maintenance")
+	}
+
+	descriptions := make([]string, 0, len(maintenances))
+	for _, maintenance := range maintenances {
+		description := fmt.Sprintf("%s %s", maintenance.Type, maintenance.ID)
+		if maintenance.NotBefore != "" {
+			description = fmt.Sprintf("%s not before %s", description, maintenance.NotBefore)
+		}
+		descriptions = append(descriptions, description)
+	}
+	reason := maintenances[0].Type
+	message := fmt.Sprintf("The VM has scheduled maintenance events: %s", strings.Join(descriptions, "; "))
+	if existingCondition != nil && existingCondition.Status == v1.ConditionTrue &&
+		existingCondition.Reason == reason && existingCondition.Message == message {
+		return nil
+	}
+
+	klog.Warningf("Node %q has scheduled maintenance events: %s", sec.nodeName, strings.Join(descriptions, "; "))
+	sec.recorder.Eventf(node, v1.EventTypeWarning, "AzureMaintenanceScheduled", message)
+	return sec.setNodeCondition(NodeAzureMaintenanceScheduled, v1.ConditionTrue, reason, message)
+}
+
+func (sec *ScheduledEventsController) setPreemptionScheduledCondition(status v1.ConditionStatus, reason, message string) error {
+	return sec.setNodeCondition(NodePreemptionScheduled, status, reason, message)
+}
+
+func (sec *ScheduledEventsController) setNodeCondition(conditionType v1.NodeConditionType, status v1.ConditionStatus, reason, message string) error {
+	currentTime := metav1.Now()
+	return nodeutil.SetNodeCondition(sec.kubeClient, types.NodeName(sec.nodeName), v1.NodeCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  currentTime,
+		LastTransitionTime: currentTime,
+	})
+}
+
+func getPreemptionScheduledTaint(taints []v1.Taint) *v1.Taint {
+	for i := range taints {
+		if taints[i].Key == PreemptionScheduledTaintKey {
+			return &taints[i]
+		}
+	}
+	return nil
+}
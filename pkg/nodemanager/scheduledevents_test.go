@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	nodeutil "k8s.io/component-helpers/node/util"
+)
+
+type fakeScheduledEventsProvider struct {
+	events []ScheduledEvent
+	err    error
+}
+
+func (p *fakeScheduledEventsProvider) GetScheduledEvents(_ context.Context) ([]ScheduledEvent, error) {
+	return p.events, p.err
+}
+
+func TestScheduledEventsControllerReconcile(t *testing.T) {
+	preemption := ScheduledEvent{
+		ID:        "f020ba2e-3bc0-4c40-a10b-86575a9eabd5",
+		Type:      ScheduledEventTypePreempt,
+		NotBefore: "Mon, 19 Sep 2016 18:29:47 GMT",
+	}
+
+	for _, tc := range []struct {
+		desc              string
+		taints            []v1.Taint
+		events            []ScheduledEvent
+		providerErr       error
+		drain             bool
+		expectedTaint     *v1.Taint
+		expectedCondition v1.ConditionStatus
+		expectedErr       bool
+	}{
+		{
+			desc:   "should not taint the node without a scheduled preemption",
+			events: []ScheduledEvent{{ID: "reboot", Type: "Reboot"}},
+		},
+		{
+			desc:              "should taint the node with NoSchedule on a scheduled preemption",
+			events:            []ScheduledEvent{preemption},
+			expectedTaint:     &v1.Taint{Key: PreemptionScheduledTaintKey, Value: preemption.ID, Effect: v1.TaintEffectNoSchedule},
+			expectedCondition: v1.ConditionTrue,
+		},
+		{
+			desc:              "should taint the node with NoExecute on a scheduled preemption if drain is enabled",
+			events:            []ScheduledEvent{preemption},
+			drain:             true,
+			taints:            []v1.Taint{{Key: PreemptionScheduledTaintKey, Value: preemption.ID, Effect: v1.TaintEffectNoSchedule}},
+			expectedTaint:     &v1.Taint{Key: PreemptionScheduledTaintKey, Value: preemption.ID, Effect: v1.TaintEffectNoExecute},
+			expectedCondition: v1.ConditionTrue,
+		},
+		{
+			desc:              "should remove the taint after the scheduled preemption is gone",
+			taints:            []v1.Taint{{Key: PreemptionScheduledTaintKey, Value: preemption.ID, Effect: v1.TaintEffectNoSchedule}},
+			expectedCondition: v1.ConditionFalse,
+		},
+		{
+			desc:        "should return the error of the provider",
+			providerErr: errors.New("imds error"),
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+				Spec:       v1.NodeSpec{Taints: tc.taints},
+			}
+			client := fake.NewSimpleClientset(node)
+			factory := informers.NewSharedInformerFactory(client, 0)
+			nodeInformer := factory.Core().V1().Nodes()
+			assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+
+			sec := NewScheduledEventsController("node0", nodeInformer, client, record.NewFakeRecorder(10),
+				&fakeScheduledEventsProvider{events: tc.events, err: tc.providerErr}, time.Second, tc.drain)
+			err := sec.reconcile(context.TODO())
+			assert.Equal(t, tc.expectedErr, err != nil)
+
+			updatedNode, err := client.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaint, getPreemptionScheduledTaint(updatedNode.Spec.Taints))
+			_, condition := nodeutil.GetNodeCondition(&updatedNode.Status, NodePreemptionScheduled)
+			if tc.expectedCondition == "" {
+				assert.Nil(t, condition)
+			} else if assert.NotNil(t, condition) {
+				assert.Equal(t, tc.expectedCondition, condition.Status)
+			}
+		})
+	}
+}
+
+func TestScheduledEventsControllerReconcileMaintenance(t *testing.T) {
+	reboot := ScheduledEvent{
+		ID:        "reboot",
+		Type:      ScheduledEventTypeReboot,
+		NotBefore: "Mon, 19 Sep 2016 18:29:47 GMT",
+	}
+	rebootMessage := "The VM has scheduled maintenance events: Reboot reboot not before Mon, 19 Sep 2016 18:29:47 GMT"
+
+	for _, tc := range []struct {
+		desc              string
+		conditions        []v1.NodeCondition
+		events            []ScheduledEvent
+		expectedCondition *v1.NodeCondition
+		expectedEvents    int
+	}{
+		{
+			desc:   "should not set the condition without a scheduled maintenance",
+			events: []ScheduledEvent{{ID: "terminate", Type: "Terminate"}},
+		},
+		{
+			desc:              "should set the condition on a scheduled maintenance",
+			events:            []ScheduledEvent{reboot, {ID: "freeze", Type: ScheduledEventTypeFreeze}},
+			expectedCondition: &v1.NodeCondition{Status: v1.ConditionTrue, Reason: ScheduledEventTypeReboot, Message: rebootMessage + "; Freeze freeze"},
+			expectedEvents:    1,
+		},
+		{
+			desc:              "should not update the condition if the scheduled maintenance does not change",
+			events:            []ScheduledEvent{reboot},
+			conditions:        []v1.NodeCondition{{Type: NodeAzureMaintenanceScheduled, Status: v1.ConditionTrue, Reason: ScheduledEventTypeReboot, Message: rebootMessage}},
+			expectedCondition: &v1.NodeCondition{Status: v1.ConditionTrue, Reason: ScheduledEventTypeReboot, Message: rebootMessage},
+		},
+		{
+			desc:              "should set the condition to false after the scheduled maintenance is gone",
+			conditions:        []v1.NodeCondition{{Type: NodeAzureMaintenanceScheduled, Status: v1.ConditionTrue, Reason: ScheduledEventTypeReboot, Message: rebootMessage}},
+			expectedCondition: &v1.NodeCondition{Status: v1.ConditionFalse, Reason: "MaintenanceCompleted", Message: "The VM does not have a scheduled maintenance"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+				Status:     v1.NodeStatus{Conditions: tc.conditions},
+			}
+			client := fake.NewSimpleClientset(node)
+			factory := informers.NewSharedInformerFactory(client, 0)
+			nodeInformer := factory.Core().V1().Nodes()
+			assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+			recorder := record.NewFakeRecorder(10)
+
+			sec := NewScheduledEventsController("node0", nodeInformer, client, recorder,
+				&fakeScheduledEventsProvider{events: tc.events}, time.Second, false)
+			assert.NoError(t, sec.reconcile(context.TODO()))
+
+			updatedNode, err := client.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			_, condition := nodeutil.GetNodeCondition(&updatedNode.Status, NodeAzureMaintenanceScheduled)
+			if tc.expectedCondition == nil {
+				assert.Nil(t, condition)
+			} else if assert.NotNil(t, condition) {
+				assert.Equal(t, tc.expectedCondition.Status, condition.Status)
+				assert.Equal(t, tc.expectedCondition.Reason, condition.Reason)
+				assert.Equal(t, tc.expectedCondition.Message, condition.Message)
+			}
+			assert.Len(t, recorder.Events, tc.expectedEvents)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodepooloutbound implements the controller keeping the nodes of the node pools in the backend
+// pools of the outbound rules which assign the outbound public IPs of the node pools.
+package nodepooloutbound
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// syncKey is the only key in the queue, since all the node pools are reconciled at once.
+const syncKey = "node-pool-outbound"
+
+// Reconciler reconciles the outbound rules of the node pools.
+type Reconciler interface {
+	// ReconcileNodePoolOutbound reconciles the outbound rules of the node pools of the cluster, and the
+	// backend pools backing them with the nodes.
+	ReconcileNodePoolOutbound(ctx context.Context, clusterName string, nodes []*v1.Node) error
+}
+
+// Controller reconciles the outbound rules of the node pools when the nodes are added, deleted or
+// relabeled, and after every sync period to pick up the changes of the node pool configurations and
+// the drifts of the load balancer. The failed reconciliations are retried with backoff.
+type Controller struct {
+	reconciler         Reconciler
+	clusterName        string
+	nodeLister         corelisters.NodeLister
+	nodeInformerSynced cache.InformerSynced
+
+	syncPeriod time.Duration
+	queue      workqueue.TypedRateLimitingInterface[string]
+}
+
+// NewController creates a new Controller.
+func NewController(nodeInformer coreinformers.NodeInformer, reconciler Reconciler, clusterName string, syncPeriod time.Duration) *Controller {
+	c := &Controller{
+		reconciler:         reconciler,
+		clusterName:        clusterName,
+		nodeLister:         nodeInformer.Lister(),
+		nodeInformerSynced: nodeInformer.Informer().HasSynced,
+		syncPeriod:         syncPeriod,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "node-pool-outbound"}),
+	}
+
+	_, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(_ interface{}) {
+			c.queue.Add(syncKey)
+		},
+		// the status of the nodes is updated every few seconds, only the label changes move the nodes
+		// between the node pools
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, ok := oldObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := newObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(oldNode.Labels, newNode.Labels) {
+				c.queue.Add(syncKey)
+			}
+		},
+		DeleteFunc: func(_ interface{}) {
+			c.queue.Add(syncKey)
+		},
+	})
+
+	return c
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting node pool outbound controller")
+	defer klog.Info("Shutting down node pool outbound controller")
+
+	if !cache.WaitForNamedCacheSync("node-pool-outbound", ctx.Done(), c.nodeInformerSynced) {
+		return
+	}
+
+	// a single worker, the node pools are reconciled at once
+	go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		c.queue.Add(syncKey)
+	}, c.syncPeriod)
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx); err != nil {
+		klog.Errorf("Failed to reconcile the outbound rules of the node pools: %v", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync reconciles the outbound rules of the node pools with the current nodes.
+func (c *Controller) sync(ctx context.Context) error {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	return c.reconciler.ReconcileNodePoolOutbound(ctx, c.clusterName, nodes)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepooloutbound
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeReconciler struct {
+	err   error
+	nodes [][]string
+}
+
+func (r *fakeReconciler) ReconcileNodePoolOutbound(_ context.Context, clusterName string, nodes []*v1.Node) error {
+	if clusterName != "kubernetes" {
+		return errors.New("unexpected cluster name")
+	}
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	r.nodes = append(r.nodes, names)
+	return r.err
+}
+
+func TestProcessNextItem(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"pool": "a"}}}
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	reconciler := &fakeReconciler{}
+	c := NewController(nodeInformer, reconciler, "kubernetes", time.Minute)
+	defer c.queue.ShutDown()
+
+	// the node events are handled by the informer, which is not started in the test
+	assert.NoError(t, nodeInformer.Informer().GetIndexer().Add(node))
+	c.queue.Add(syncKey)
+	assert.True(t, c.processNextItem(context.Background()))
+	assert.Equal(t, [][]string{{"node-a"}}, reconciler.nodes)
+	assert.Equal(t, 0, c.queue.Len())
+
+	// the failed reconciliation is retried
+	reconciler.err = errors.New("failed")
+	c.queue.Add(syncKey)
+	assert.True(t, c.processNextItem(context.Background()))
+	assert.Equal(t, 1, c.queue.NumRequeues(syncKey))
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodeproviderid implements the controller backfilling the provider IDs of the
+// nodes registered without one.
+package nodeproviderid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/priorityqueue"
+)
+
+// ProviderIDResolver resolves the provider ID of a node.
+type ProviderIDResolver interface {
+	// GetNodeProviderID returns the provider ID of the node. It returns
+	// cloudprovider.InstanceNotFound if the VM of the node is not found, and "" if the
+	// node is not managed by the cloud provider.
+	GetNodeProviderID(ctx context.Context, node *v1.Node) (string, error)
+}
+
+// Controller sets the provider IDs of the nodes registered by kubelets running without
+// --cloud-provider=external, which would otherwise stay unmanaged forever. The nodes without
+// a provider ID are resolved when they are added, and again after every sync period if their
+// VMs were not found.
+type Controller struct {
+	kubeClient         clientset.Interface
+	nodeLister         corelisters.NodeLister
+	nodeInformerSynced cache.InformerSynced
+	resolver           ProviderIDResolver
+
+	syncPeriod time.Duration
+	queue      priorityqueue.RateLimitingInterface[string]
+}
+
+// NewController creates a new Controller.
+func NewController(
+	nodeInformer coreinformers.NodeInformer,
+	kubeClient clientset.Interface,
+	resolver ProviderIDResolver,
+	syncPeriod time.Duration) *Controller {
+	c := &Controller{
+		kubeClient:         kubeClient,
+		nodeLister:         nodeInformer.Lister(),
+		nodeInformerSynced: nodeInformer.Informer().HasSynced,
+		resolver:           resolver,
+		syncPeriod:         syncPeriod,
+		queue:              priorityqueue.NewRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string](), "node-provider-id", metrics.NewWorkqueueMetricsProvider("node_provider_id_controller")),
+	}
+
+	_, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		// updates are not handled because the status of the nodes is updated every few seconds,
+		// the nodes are retried by the periodic resync instead
+		AddFunc: func(obj interface{}) {
+			c.enqueueNode(obj, priorityqueue.PriorityHigh)
+		},
+	})
+
+	return c
+}
+
+// enqueueNode adds the node to the queue if it has no provider ID. The new nodes are resolved
+// before the ones retried by the periodic resync.
+func (c *Controller) enqueueNode(obj interface{}, priority priorityqueue.Priority) {
+	if node, ok := obj.(*v1.Node); ok && node.Spec.ProviderID == "" {
+		c.queue.AddWithPriority(node.Name, priority)
+	}
+}
+
+// Run starts the controller. This call is blocking so should be called via a goroutine.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting node provider ID controller")
+	defer klog.Info("Shutting down node provider ID controller")
+
+	if !cache.WaitForNamedCacheSync("node-provider-id", ctx.Done(), c.nodeInformerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	// retry the nodes whose VMs were not found, they may be created after the nodes registered
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		nodes, err := c.nodeLister.List(labels.Everything())
+		if err != nil {
+			klog.Errorf("Failed to list nodes: %v", err)
+			return
+		}
+		for _, node := range nodes {
+			c.enqueueNode(node, priorityqueue.PriorityLow)
+		}
+	}, c.syncPeriod)
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	nodeName, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(nodeName)
+
+	if err := c.syncNode(ctx, nodeName); err != nil {
+		klog.Errorf("Failed to backfill the provider ID of node %s: %v", nodeName, err)
+		c.queue.AddRateLimited(nodeName)
+		return true
+	}
+	c.queue.Forget(nodeName)
+	return true
+}
+
+// syncNode patches the provider ID of the node if it is empty.
+func (c *Controller) syncNode(ctx context.Context, nodeName string) error {
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if node.Spec.ProviderID != "" {
+		return nil
+	}
+
+	providerID, err := c.resolver.GetNodeProviderID(ctx, node)
+	if err != nil {
+		if errors.Is(err, cloudprovider.InstanceNotFound) {
+			// retried after the sync period instead of the rate limited backoff
+			klog.V(2).Infof("Skipping node %s whose VM is not found", nodeName)
+			return nil
+		}
+		return err
+	}
+	if providerID == "" {
+		klog.V(4).Infof("Skipping node %s which is not managed by the cloud provider", nodeName)
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"providerID": providerID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("Setting the provider ID of node %s to %s", nodeName, providerID)
+	if _, err := c.kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch the provider ID of node %s: %w", nodeName, err)
+	}
+	return nil
+}
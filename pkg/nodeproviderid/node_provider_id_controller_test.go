@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeproviderid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+type fakeProviderIDResolver struct {
+	providerID string
+	err        error
+	calls      int
+}
+
+func (r *fakeProviderIDResolver) GetNodeProviderID(_ context.Context, _ *v1.Node) (string, error) {
+	r.calls++
+	return r.providerID, r.err
+}
+
+func TestSyncNode(t *testing.T) {
+	const providerID = "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/node0"
+
+	for _, tc := range []struct {
+		desc               string
+		nodeProviderID     string
+		resolver           *fakeProviderIDResolver
+		expectedProviderID string
+		expectedCalls      int
+		expectedErr        bool
+	}{
+		{
+			desc:               "should set the provider ID of the node",
+			resolver:           &fakeProviderIDResolver{providerID: providerID},
+			expectedProviderID: providerID,
+			expectedCalls:      1,
+		},
+		{
+			desc:               "should not resolve the nodes with a provider ID",
+			nodeProviderID:     "azure:///foo",
+			resolver:           &fakeProviderIDResolver{providerID: providerID},
+			expectedProviderID: "azure:///foo",
+		},
+		{
+			desc:          "should not change unmanaged nodes",
+			resolver:      &fakeProviderIDResolver{},
+			expectedCalls: 1,
+		},
+		{
+			desc:          "should ignore nodes whose VMs are not found",
+			resolver:      &fakeProviderIDResolver{err: cloudprovider.InstanceNotFound},
+			expectedCalls: 1,
+		},
+		{
+			desc:          "should return the error of the resolver",
+			resolver:      &fakeProviderIDResolver{err: errors.New("arm error")},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+				Spec:       v1.NodeSpec{ProviderID: tc.nodeProviderID},
+			}
+			client := fake.NewSimpleClientset(node)
+			factory := informers.NewSharedInformerFactory(client, 0)
+			nodeInformer := factory.Core().V1().Nodes()
+			assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+
+			c := NewController(nodeInformer, client, tc.resolver, time.Hour)
+			err := c.syncNode(context.TODO(), "node0")
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedCalls, tc.resolver.calls)
+
+			updatedNode, err := client.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedProviderID, updatedNode.Spec.ProviderID)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylog
+
+// Generate mocks for the repository interface
+//go:generate mockgen -destination=./mock_repo.go -package=activitylog -copyright_file ../../../hack/boilerplate/boilerplate.generatego.txt -source=repo.go Repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	moduleName    = "activitylog"
+	moduleVersion = "v1.0.0"
+	apiVersion    = "2015-04-01"
+
+	statusSucceeded = "Succeeded"
+)
+
+// Event is a succeeded write or delete operation on a resource recorded in the activity log.
+type Event struct {
+	// ID is the unique ID of the event.
+	ID            string
+	ResourceID    string
+	OperationName string
+	Timestamp     time.Time
+}
+
+type Repository interface {
+	// ListEvents returns the succeeded write and delete events of the resources in the resource group
+	// since the given time.
+	ListEvents(ctx context.Context, resourceGroup string, since time.Time) ([]Event, error)
+}
+
+type repo struct {
+	client         *arm.Client
+	subscriptionID string
+}
+
+func NewRepo(subscriptionID string, credential azcore.TokenCredential, options *arm.ClientOptions) (Repository, error) {
+	client, err := arm.NewClient(moduleName, moduleVersion, credential, options)
+	if err != nil {
+		return nil, err
+	}
+	return &repo{client: client, subscriptionID: subscriptionID}, nil
+}
+
+type eventList struct {
+	Value    []eventData `json:"value"`
+	NextLink string      `json:"nextLink"`
+}
+
+type eventData struct {
+	EventDataID    string         `json:"eventDataId"`
+	ResourceID     string         `json:"resourceId"`
+	OperationName  localizedValue `json:"operationName"`
+	Status         localizedValue `json:"status"`
+	EventTimestamp time.Time      `json:"eventTimestamp"`
+}
+
+type localizedValue struct {
+	Value string `json:"value"`
+}
+
+func (r *repo) ListEvents(ctx context.Context, resourceGroup string, since time.Time) ([]Event, error) {
+	query := url.Values{}
+	query.Set("api-version", apiVersion)
+	query.Set("$filter", fmt.Sprintf("eventTimestamp ge '%s' and resourceGroupName eq '%s'", since.UTC().Format(time.RFC3339), resourceGroup))
+	query.Set("$select", "eventDataId,resourceId,operationName,status,eventTimestamp")
+	nextLink := fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.Insights/eventtypes/management/values?%s",
+		strings.TrimSuffix(r.client.Endpoint(), "/"), url.PathEscape(r.subscriptionID), query.Encode())
+
+	var events []Event
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+		req.Raw().Header.Set("Accept", "application/json")
+		resp, err := r.client.Pipeline().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, runtime.NewResponseError(resp)
+		}
+		var page eventList
+		if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+			return nil, err
+		}
+		for _, e := range page.Value {
+			if !strings.EqualFold(e.Status.Value, statusSucceeded) || e.ResourceID == "" {
+				continue
+			}
+			operation := strings.ToLower(e.OperationName.Value)
+			if !strings.HasSuffix(operation, "/write") && !strings.HasSuffix(operation, "/delete") {
+				continue
+			}
+			events = append(events, Event{
+				ID:            e.EventDataID,
+				ResourceID:    e.ResourceID,
+				OperationName: e.OperationName.Value,
+				Timestamp:     e.EventTimestamp,
+			})
+		}
+		nextLink = page.NextLink
+	}
+	return events, nil
+}
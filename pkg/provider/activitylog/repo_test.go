@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestRepo_ListEvents(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"value":[{"eventDataId":"event2","resourceId":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm2","operationName":{"value":"Microsoft.Compute/virtualMachines/delete"},"status":{"value":"Succeeded"},"eventTimestamp":"2026-01-01T00:02:00Z"}]}`)
+			return
+		}
+		assert.Equal(t, "/subscriptions/sub/providers/Microsoft.Insights/eventtypes/management/values", r.URL.Path)
+		assert.Equal(t, "eventTimestamp ge '2026-01-01T00:00:00Z' and resourceGroupName eq 'rg'", r.URL.Query().Get("$filter"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		fmt.Fprintf(w, `{"value":[
+{"eventDataId":"event1","resourceId":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1","operationName":{"value":"Microsoft.Compute/virtualMachines/write"},"status":{"value":"Succeeded"},"eventTimestamp":"2026-01-01T00:01:00Z"},
+{"resourceId":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1","operationName":{"value":"Microsoft.Compute/virtualMachines/write"},"status":{"value":"Started"},"eventTimestamp":"2026-01-01T00:01:00Z"},
+{"resourceId":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1","operationName":{"value":"Microsoft.Compute/virtualMachines/start/action"},"status":{"value":"Succeeded"},"eventTimestamp":"2026-01-01T00:01:00Z"}
+],"nextLink":"%s/next?page=2"}`, server.URL)
+	}))
+	defer server.Close()
+
+	r, err := NewRepo("sub", fakeCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Endpoint: server.URL, Audience: server.URL},
+				},
+			},
+			Transport: server.Client(),
+		},
+	})
+	assert.NoError(t, err)
+
+	events, err := r.ListEvents(context.Background(), "rg", since)
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{
+		{
+			ID:            "event1",
+			ResourceID:    "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1",
+			OperationName: "Microsoft.Compute/virtualMachines/write",
+			Timestamp:     time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC),
+		},
+		{
+			ID:            "event2",
+			ResourceID:    "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm2",
+			OperationName: "Microsoft.Compute/virtualMachines/delete",
+			Timestamp:     time.Date(2026, 1, 1, 0, 2, 0, 0, time.UTC),
+		},
+	}, events)
+}
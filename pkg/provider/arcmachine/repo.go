@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arcmachine
+
+// Generate mocks for the repository interface
+//go:generate mockgen -destination=./mock_repo.go -package=arcmachine -copyright_file ../../../hack/boilerplate/boilerplate.generatego.txt -source=repo.go Repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	moduleName    = "arcmachine"
+	moduleVersion = "v1.0.0"
+	apiVersion    = "2022-12-27"
+)
+
+// Machine is an Azure Arc-enabled server of the Hybrid Compute API.
+type Machine struct {
+	ID       string
+	Name     string
+	Location string
+	// Status is the connectivity status of the Arc agent, e.g. Connected, Disconnected or Expired.
+	Status string
+}
+
+type Repository interface {
+	// Get returns the machine of the resource ID, or nil if the machine is not found.
+	Get(ctx context.Context, resourceID string) (*Machine, error)
+}
+
+type repo struct {
+	client *arm.Client
+}
+
+func NewRepo(credential azcore.TokenCredential, options *arm.ClientOptions) (Repository, error) {
+	client, err := arm.NewClient(moduleName, moduleVersion, credential, options)
+	if err != nil {
+		return nil, err
+	}
+	return &repo{client: client}, nil
+}
+
+type machineData struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		Status string `json:"status"`
+	} `json:"properties"`
+}
+
+func (r *repo) Get(ctx context.Context, resourceID string) (*Machine, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s?api-version=%s",
+		strings.TrimSuffix(r.client.Endpoint(), "/"), strings.TrimPrefix(resourceID, "/"), apiVersion))
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	resp, err := r.client.Pipeline().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.HasStatusCode(resp, http.StatusNotFound) {
+		return nil, nil
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, runtime.NewResponseError(resp)
+	}
+	var data machineData
+	if err := runtime.UnmarshalAsJSON(resp, &data); err != nil {
+		return nil, err
+	}
+	return &Machine{
+		ID:       data.ID,
+		Name:     data.Name,
+		Location: data.Location,
+		Status:   data.Properties.Status,
+	}, nil
+}
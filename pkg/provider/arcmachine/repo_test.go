@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arcmachine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestRepo_Get(t *testing.T) {
+	const machineID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.HybridCompute/machines/machine1"
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiVersion, r.URL.Query().Get("api-version"))
+		if r.URL.Path != machineID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"%s","name":"machine1","location":"eastus","properties":{"status":"Connected"}}`, machineID)
+	}))
+	defer server.Close()
+
+	r, err := NewRepo(fakeCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Endpoint: server.URL, Audience: server.URL},
+				},
+			},
+			Transport: server.Client(),
+		},
+	})
+	assert.NoError(t, err)
+
+	machine, err := r.Get(context.Background(), machineID)
+	assert.NoError(t, err)
+	assert.Equal(t, &Machine{ID: machineID, Name: "machine1", Location: "eastus", Status: "Connected"}, machine)
+
+	machine, err = r.Get(context.Background(), "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.HybridCompute/machines/machine2")
+	assert.NoError(t, err)
+	assert.Nil(t, machine)
+}
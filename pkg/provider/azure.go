@@ -19,38 +19,56 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
-	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	cloudproviderapi "k8s.io/cloud-provider/api"
 	cloudnodeutil "k8s.io/cloud-provider/node/helpers"
+	"k8s.io/component-base/featuregate"
 	nodeutil "k8s.io/component-helpers/node/util"
 	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
 
+	statusv1alpha1 "sigs.k8s.io/cloud-provider-azure/pkg/apis/status/v1alpha1"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/armauth"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/configloader"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/features"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/activitylog"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/arcmachine"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/privatelinkservice"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/resourcesku"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/securitygroup"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/subnet"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/zone"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/eventrecorder"
 	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
 	"sigs.k8s.io/cloud-provider-azure/pkg/util/taints"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version"
@@ -94,8 +112,28 @@ type Cloud struct {
 	VMSet                   VMSet
 	LoadBalancerBackendPool BackendPool
 
+	// armClientOptions are the additional options of all the ARM clients, e.g. the transport of the fake backend
+	armClientOptions []func(option *arm.ClientOptions)
+
+	// credentialSetClientFactories holds the client factories of the credential sets by their lower case subscription IDs
+	credentialSetClientFactories map[string]azclient.ClientFactory
+	// computeCredential and networkCredential are set if the auth provider is created from the config,
+	// they wrap the credentials of the auth provider so that the client secret can be rotated in place.
+	computeCredential *armauth.RotatableCredential
+	networkCredential *armauth.RotatableCredential
+	// credentialSecretsLock guards the client secret and the client certificate password of the config, which
+	// are replaced by RotateCredentials while the controllers are running
+	credentialSecretsLock sync.RWMutex
+	// configWithoutSecrets is the JSON of the config the cloud is initialized from without the client secrets
+	// and the rate limits
+	configWithoutSecrets []byte
+	// clientFactoryConfigs are the configs of the client factories, through which the rate limits are tuned in place
+	clientFactoryConfigs []*azclient.ClientFactoryConfig
+
 	// ipv6DualStack allows overriding for unit testing.  It's normally initialized from featuregates
 	ipv6DualStackEnabled bool
+	// backendPoolNICNamePattern is the compiled LoadBalancerBackendPoolNICNamePattern, nil if it is not set.
+	backendPoolNICNamePattern *regexp.Regexp
 	// Lock for access to node caches, includes nodeZones, nodeResourceGroups, and unmanagedNodes.
 	nodeCachesLock sync.RWMutex
 	// nodeNames holds current nodes for tracking added nodes in VM caches.
@@ -105,8 +143,24 @@ type Cloud struct {
 	nodeZones map[string]*utilsets.IgnoreCaseSet
 	// nodeResourceGroups holds nodes external resource groups
 	nodeResourceGroups map[string]string
+	// nodeProviderIDResourceGroups holds the resource groups in the provider IDs of the nodes
+	// without the external resource group label, if they are not the configured resource group.
+	nodeProviderIDResourceGroups map[string]string
+	// nodeRouteIPConfigurations holds the route-ip-configuration annotations of the nodes.
+	nodeRouteIPConfigurations map[string]string
+	// nodesWithOutdatedRouteNextHops holds the nodes whose routes may have outdated next hops, i.e. the nodes
+	// added with the next hops overridden, e.g. after restarts, and the ones whose route-ip-configuration
+	// annotations changed. ListRoutes checks their routes.
+	nodesWithOutdatedRouteNextHops *utilsets.IgnoreCaseSet
 	// unmanagedNodes holds a list of nodes not managed by Azure cloud provider.
 	unmanagedNodes *utilsets.IgnoreCaseSet
+	// windowsNodeNames holds the nodes labeled as Windows nodes.
+	windowsNodeNames *utilsets.IgnoreCaseSet
+	// nodesMarkedForDeletion holds the nodes with the node deletion taints.
+	nodesMarkedForDeletion *utilsets.IgnoreCaseSet
+	// reconciledNodesMarkedForDeletion holds the nodes marked for deletion when the load balancers were last
+	// reconciled by ReconcileNodesMarkedForDeletion, it is only accessed by the node deletion controller
+	reconciledNodesMarkedForDeletion sets.Set[string]
 	// excludeLoadBalancerNodes holds a list of nodes that should be excluded from LoadBalancer.
 	excludeLoadBalancerNodes   *utilsets.IgnoreCaseSet
 	nodePrivateIPs             map[string]*utilsets.IgnoreCaseSet
@@ -124,7 +178,6 @@ type Cloud struct {
 	refreshZonesLock sync.RWMutex
 
 	KubeClient         clientset.Interface
-	eventBroadcaster   record.EventBroadcaster
 	eventRecorder      record.EventRecorder
 	routeUpdater       batchProcessor
 	backendPoolUpdater batchProcessor
@@ -136,14 +189,73 @@ type Cloud struct {
 	plsRepo        privatelinkservice.Repository
 	subnetRepo     subnet.Repository
 	routeTableRepo routetable.Repository
+	// activityLogRepo is set only if the caches are invalidated by the activity log
+	activityLogRepo activitylog.Repository
+	arcMachineRepo  arcmachine.Repository
+	// resourceSKURepo is used to label the nodes with the hardware of their VM sizes
+	resourceSKURepo resourcesku.Repository
 	// public ip cache
 	// key: [resourceGroupName]
 	// Value: sync.Map of [pipName]*PublicIPAddress
 	pipCache azcache.Resource
+	// nodeIdentityCache is used to resolve the provider IDs of the nodes registered without one
+	// key: [resourceGroupName]
+	// Value: *nodeIdentityEntry
+	nodeIdentityCache azcache.Resource
+	// routeTableSubnetCache is used to assign the nodes to the route tables of their subnets
+	// key: [subnetName]
+	// Value: []netip.Prefix
+	routeTableSubnetCache azcache.Resource
+	// routeNodeInterfacesCache is used to check the next hops of the routes overridden by the next hop
+	// policies or the IP configurations
+	// key: [nodeName]
+	// Value: []*armnetwork.Interface
+	routeNodeInterfacesCache azcache.Resource
+	// internalLoadBalancerVNetCache is used to discover the subnets of the internal load balancers
+	// key: [vnetResourceGroup]
+	// Value: *armnetwork.VirtualNetwork
+	internalLoadBalancerVNetCache azcache.Resource
 	// Add service lister to always get latest service
 	serviceLister corelisters.ServiceLister
-	// node-sync-loop routine and service-reconcile routine should not update LoadBalancer at the same time
-	serviceReconcileLock sync.Mutex
+	// nodeLister is used to get the latest readiness of the nodes
+	nodeLister corelisters.NodeLister
+	// podIndexer indexes the pods by their nodes, it is set only if the nodes of deleted VMs are drained
+	podIndexer cache.Indexer
+	// podInformerDisabled keeps SetInformers from setting up the pod informer
+	podInformerDisabled bool
+	// featureGates are the Azure feature gates of the cloud, the defaults are used if it is nil
+	featureGates featuregate.FeatureGate
+	// informerFactory is the informer factory passed to SetInformers
+	informerFactory informers.SharedInformerFactory
+	// loadBalancerClasses are the load balancer classes of the services reconciled besides the ones without a class
+	loadBalancerClasses sets.Set[string]
+	// controllerStatuses returns the syncs of the controllers reported in the status, it is set by
+	// SetControllerStatuses
+	controllerStatuses func() []statusv1alpha1.ControllerStatus
+	// eventPolicy is the aggregation policy of the events recorded by the cloud, it is set by SetEventPolicy
+	// and the default policy is used if it is nil
+	eventPolicy *eventrecorder.Policy
+	// warmStandby is true until the cloud kept warm on a non-leader replica is initialized by the leader
+	warmStandby atomic.Bool
+	// armRequestBudget accounts the ARM requests to the callers, it is set only if the budgets are configured
+	armRequestBudget *armRequestBudget
+	// nodeEligibilityRequeuer is set only if the node age or readiness gates the backend pools
+	nodeEligibilityRequeuer *nodeEligibilityRequeuer
+	// nodeScaleWaveTracker is set only if NodeScaleWaveThreshold is set
+	nodeScaleWaveTracker *nodeScaleWaveTracker
+	// node-sync-loop routine and service-reconcile routine should not update LoadBalancer at the same time,
+	// the deletions and the service changes go before the node sync updates
+	serviceReconcileLock priorityLock
+	// serviceReconcileBackoff delays or parks the reconciliation of failing services
+	serviceReconcileBackoff *serviceReconcileBackoff
+	// lbOperationTracker tracks the asynchronous load balancer updates, it is set only if they are enabled
+	lbOperationTracker *lbOperationTracker
+	// serviceProvisioningTracker measures the provisioning time of the load balancers of the services
+	serviceProvisioningTracker *serviceProvisioningTracker
+	// probeTransitions tracks the switches of the health probes of the load balancing rules
+	probeTransitions *probeTransitionTracker
+	// routeMetrics reports the convergence of the routes, it is set only in the cloud controller manager
+	routeMetrics *routeMetricsTracker
 
 	// multipleStandardLoadBalancerConfigurationsSynced make sure the `reconcileMultipleStandardLoadBalancerConfigurations`
 	// runs only once every time the cloud provide restarts.
@@ -160,15 +272,28 @@ type Cloud struct {
 
 // NewCloud returns a Cloud with initialized clients
 func NewCloud(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, config *azureconfig.Config, callFromCCM bool) (cloudprovider.Interface, error) {
+	az, err := newCloud(ctx, clientBuilder, config, callFromCCM)
+	if err != nil {
+		return nil, err
+	}
+	return az, nil
+}
+
+// newCloud returns a Cloud with initialized clients, the setups are applied before the initialization.
+func newCloud(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, config *azureconfig.Config, callFromCCM bool, setups ...func(az *Cloud)) (*Cloud, error) {
 	az := &Cloud{
-		nodeNames:                  utilsets.NewString(),
-		nodeZones:                  map[string]*utilsets.IgnoreCaseSet{},
-		nodeResourceGroups:         map[string]string{},
-		unmanagedNodes:             utilsets.NewString(),
-		routeCIDRs:                 map[string]string{},
-		excludeLoadBalancerNodes:   utilsets.NewString(),
-		nodePrivateIPs:             map[string]*utilsets.IgnoreCaseSet{},
-		nodePrivateIPToNodeNameMap: map[string]string{},
+		nodeNames:                    utilsets.NewString(),
+		nodeZones:                    map[string]*utilsets.IgnoreCaseSet{},
+		nodeResourceGroups:           map[string]string{},
+		nodeProviderIDResourceGroups: map[string]string{},
+		unmanagedNodes:               utilsets.NewString(),
+		routeCIDRs:                   map[string]string{},
+		excludeLoadBalancerNodes:     utilsets.NewString(),
+		nodePrivateIPs:               map[string]*utilsets.IgnoreCaseSet{},
+		nodePrivateIPToNodeNameMap:   map[string]string{},
+	}
+	for _, setup := range setups {
+		setup(az)
 	}
 
 	err := az.InitializeCloudFromConfig(ctx, config, false, callFromCCM)
@@ -224,15 +349,20 @@ func NewCloudFromConfigFile(ctx context.Context, clientBuilder cloudprovider.Con
 	return cloud, nil
 }
 
-func NewCloudFromSecret(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, secretName, secretNamespace, cloudConfigKey string) (cloudprovider.Interface, error) {
-	config, err := configloader.Load[azureconfig.Config](ctx, &configloader.K8sSecretLoaderConfig{
+// LoadConfigFromSecret loads the cloud config from the secret.
+func LoadConfigFromSecret(ctx context.Context, kubeClient clientset.Interface, secretName, secretNamespace, cloudConfigKey string) (*azureconfig.Config, error) {
+	return configloader.Load[azureconfig.Config](ctx, &configloader.K8sSecretLoaderConfig{
 		K8sSecretConfig: configloader.K8sSecretConfig{
 			SecretName:      secretName,
 			SecretNamespace: secretNamespace,
 			CloudConfigKey:  cloudConfigKey,
 		},
-		KubeClient: clientBuilder.ClientOrDie("cloud-provider-azure"),
+		KubeClient: kubeClient,
 	}, nil)
+}
+
+func NewCloudFromSecret(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, secretName, secretNamespace, cloudConfigKey string) (cloudprovider.Interface, error) {
+	config, err := LoadConfigFromSecret(ctx, clientBuilder.ClientOrDie("cloud-provider-azure"), secretName, secretNamespace, cloudConfigKey)
 	if err != nil {
 		return nil, fmt.Errorf("NewCloudFromSecret: failed to get config from secret %s/%s: %w", secretNamespace, secretName, err)
 	}
@@ -258,6 +388,16 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		// should not reach here
 		return fmt.Errorf("InitializeCloudFromConfig: cannot initialize from nil config")
 	}
+	configWithoutSecrets, err := getConfigWithoutSecrets(config)
+	if err != nil {
+		return err
+	}
+	az.configWithoutSecrets = configWithoutSecrets
+
+	az.featureGates, err = features.New(config.AzureFeatureGates)
+	if err != nil {
+		return err
+	}
 
 	if config.RouteTableResourceGroup == "" {
 		config.RouteTableResourceGroup = config.ResourceGroup
@@ -284,6 +424,18 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		return fmt.Errorf("disableAvailabilitySetNodes %v is only supported when vmType is 'vmss'", config.DisableAvailabilitySetNodes)
 	}
 
+	if err := validateARMRequestBudgets(config.ARMRequestBudgetsPerHour); err != nil {
+		return err
+	}
+
+	if err := validateARMAuditWebhookURL(config.ARMAuditWebhookURL); err != nil {
+		return err
+	}
+
+	if config.CacheSnapshotPath != "" && config.CacheSnapshotConfigMap != "" {
+		return fmt.Errorf("cacheSnapshotPath and cacheSnapshotConfigMap can't be set together")
+	}
+
 	if config.CloudConfigType == "" {
 		// The default cloud config type is cloudConfigTypeMerge.
 		config.CloudConfigType = configloader.CloudConfigTypeMerge
@@ -310,6 +462,12 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 			return fmt.Errorf("loadBalancerBackendPoolConfigurationType %s is not supported, supported values are %v", config.LoadBalancerBackendPoolConfigurationType, supportedLoadBalancerBackendPoolConfigurationTypes.UnsortedList())
 		}
 	}
+	if strings.EqualFold(config.LoadBalancerBackendPoolConfigurationType, consts.LoadBalancerBackendPoolConfigurationTypeNodeIP) &&
+		!features.Enabled(az.featureGates, features.IPBasedBackendPool) {
+		klog.Warningf("InitializeCloudFromConfig: feature gate %s is disabled, using loadBalancerBackendPoolConfigurationType %s instead of %s",
+			features.IPBasedBackendPool, consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration, config.LoadBalancerBackendPoolConfigurationType)
+		config.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration
+	}
 
 	if config.ClusterServiceLoadBalancerHealthProbeMode == "" {
 		config.ClusterServiceLoadBalancerHealthProbeMode = consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort
@@ -322,6 +480,163 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 			return fmt.Errorf("clusterServiceLoadBalancerHealthProbeMode %s is not supported, supported values are %v", config.ClusterServiceLoadBalancerHealthProbeMode, supportedClusterServiceLoadBalancerHealthProbeModes.UnsortedList())
 		}
 	}
+	if config.LoadBalancerResourceNamingScheme == "" {
+		config.LoadBalancerResourceNamingScheme = consts.LoadBalancerResourceNamingSchemeLegacy
+	} else {
+		supportedLoadBalancerResourceNamingSchemes := utilsets.NewString(
+			strings.ToLower(consts.LoadBalancerResourceNamingSchemeLegacy),
+			strings.ToLower(consts.LoadBalancerResourceNamingSchemeHashed),
+		)
+		if !supportedLoadBalancerResourceNamingSchemes.Has(strings.ToLower(config.LoadBalancerResourceNamingScheme)) {
+			return fmt.Errorf("loadBalancerResourceNamingScheme %s is not supported, supported values are %v", config.LoadBalancerResourceNamingScheme, supportedLoadBalancerResourceNamingSchemes.UnsortedList())
+		}
+	}
+	if err := validateNodeAddressIPFamilies(config.NodeAddressIPFamilies); err != nil {
+		return err
+	}
+	if config.NodeAddressSource == "" {
+		config.NodeAddressSource = consts.NodeAddressSourceIMDS
+	} else {
+		supportedNodeAddressSources := utilsets.NewString(
+			strings.ToLower(consts.NodeAddressSourceIMDS),
+			strings.ToLower(consts.NodeAddressSourceARM),
+		)
+		if !supportedNodeAddressSources.Has(strings.ToLower(config.NodeAddressSource)) {
+			return fmt.Errorf("nodeAddressSource %s is not supported, supported values are %v", config.NodeAddressSource, supportedNodeAddressSources.UnsortedList())
+		}
+	}
+	if config.InstanceMetadataOnly {
+		if !config.UseInstanceMetadata {
+			return fmt.Errorf("instanceMetadataOnly requires useInstanceMetadata to be enabled")
+		}
+		if !strings.EqualFold(config.NodeAddressSource, consts.NodeAddressSourceIMDS) {
+			return fmt.Errorf("instanceMetadataOnly requires the nodeAddressSource %s, got %s", consts.NodeAddressSourceIMDS, config.NodeAddressSource)
+		}
+	}
+	if config.NodeAddressOrder == "" {
+		config.NodeAddressOrder = consts.NodeAddressOrderInternalIPFirst
+	} else {
+		supportedNodeAddressOrders := utilsets.NewString(
+			strings.ToLower(consts.NodeAddressOrderInternalIPFirst),
+			strings.ToLower(consts.NodeAddressOrderExternalIPFirst),
+		)
+		if !supportedNodeAddressOrders.Has(strings.ToLower(config.NodeAddressOrder)) {
+			return fmt.Errorf("nodeAddressOrder %s is not supported, supported values are %v", config.NodeAddressOrder, supportedNodeAddressOrders.UnsortedList())
+		}
+	}
+	if config.DeallocatedVMPolicy == "" {
+		config.DeallocatedVMPolicy = consts.DeallocatedVMPolicyShutdown
+	} else {
+		supportedDeallocatedVMPolicies := utilsets.NewString(
+			strings.ToLower(consts.DeallocatedVMPolicyShutdown),
+			strings.ToLower(consts.DeallocatedVMPolicyDeleted),
+		)
+		if !supportedDeallocatedVMPolicies.Has(strings.ToLower(config.DeallocatedVMPolicy)) {
+			return fmt.Errorf("deallocatedVMPolicy %s is not supported, supported values are %v", config.DeallocatedVMPolicy, supportedDeallocatedVMPolicies.UnsortedList())
+		}
+	}
+	if config.RouteDriftReconciliationMode != "" {
+		supportedRouteDriftReconciliationModes := utilsets.NewString(
+			strings.ToLower(consts.RouteDriftReconciliationModeAudit),
+			strings.ToLower(consts.RouteDriftReconciliationModeRepair),
+		)
+		if !supportedRouteDriftReconciliationModes.Has(strings.ToLower(config.RouteDriftReconciliationMode)) {
+			return fmt.Errorf("routeDriftReconciliationMode %s is not supported, supported values are %v", config.RouteDriftReconciliationMode, supportedRouteDriftReconciliationModes.UnsortedList())
+		}
+	}
+	if config.SecurityGroupManagementMode != "" && getSecurityGroupManagementModeStrictness(config.SecurityGroupManagementMode) < 0 {
+		return fmt.Errorf("securityGroupManagementMode %s is not supported, supported values are %v", config.SecurityGroupManagementMode, securityGroupManagementModes)
+	}
+	for i := range config.RouteNextHopPolicies {
+		policy := &config.RouteNextHopPolicies[i]
+		if len(policy.SubnetNames) == 0 {
+			return fmt.Errorf("routeNextHopPolicies[%d] does not have subnetNames", i)
+		}
+		if policy.NextHopType == "" {
+			policy.NextHopType = string(armnetwork.RouteNextHopTypeVirtualAppliance)
+		}
+		switch {
+		case strings.EqualFold(policy.NextHopType, string(armnetwork.RouteNextHopTypeVirtualAppliance)):
+			policy.NextHopType = string(armnetwork.RouteNextHopTypeVirtualAppliance)
+			if net.ParseIP(policy.NextHopIPAddress) == nil {
+				return fmt.Errorf("routeNextHopPolicies[%d] requires a valid nextHopIPAddress for next hop type %s", i, policy.NextHopType)
+			}
+		case strings.EqualFold(policy.NextHopType, string(armnetwork.RouteNextHopTypeVirtualNetworkGateway)):
+			policy.NextHopType = string(armnetwork.RouteNextHopTypeVirtualNetworkGateway)
+			if policy.NextHopIPAddress != "" {
+				return fmt.Errorf("routeNextHopPolicies[%d] cannot have nextHopIPAddress for next hop type %s", i, policy.NextHopType)
+			}
+		default:
+			return fmt.Errorf("routeNextHopPolicies[%d] nextHopType %s is not supported, supported values are %v", i, policy.NextHopType,
+				[]armnetwork.RouteNextHopType{armnetwork.RouteNextHopTypeVirtualAppliance, armnetwork.RouteNextHopTypeVirtualNetworkGateway})
+		}
+	}
+	for _, cidr := range config.AdditionalClusterCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("additionalClusterCIDRs %s is not a valid CIDR: %w", cidr, err)
+		}
+	}
+	for i, nodePool := range config.NodePoolClusterCIDRs {
+		if _, err := labels.Parse(nodePool.NodeSelector); err != nil {
+			return fmt.Errorf("nodePoolClusterCIDRs[%d] has an invalid nodeSelector %q: %w", i, nodePool.NodeSelector, err)
+		}
+		if len(nodePool.ClusterCIDRs) == 0 {
+			return fmt.Errorf("nodePoolClusterCIDRs[%d] does not have clusterCIDRs", i)
+		}
+		cidrs := make([]*net.IPNet, 0, len(nodePool.ClusterCIDRs))
+		for _, cidr := range nodePool.ClusterCIDRs {
+			_, clusterCIDR, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("nodePoolClusterCIDRs[%d] clusterCIDR %s is not a valid CIDR: %w", i, cidr, err)
+			}
+			cidrs = append(cidrs, clusterCIDR)
+		}
+		if len(cidrs) > 2 {
+			return fmt.Errorf("nodePoolClusterCIDRs[%d] has more than one cluster CIDR per IP family", i)
+		}
+		if len(cidrs) == 2 {
+			if dualStack, _ := netutils.IsDualStackCIDRs(cidrs); !dualStack {
+				return fmt.Errorf("nodePoolClusterCIDRs[%d] has more than one cluster CIDR per IP family", i)
+			}
+		}
+	}
+	if err := validateNodePoolOutboundConfigurations(config); err != nil {
+		return err
+	}
+	if err := validateRegionalLoadBalancerConfigurations(config); err != nil {
+		return err
+	}
+	if az.backendPoolNICNamePattern, err = compileBackendPoolNICNamePattern(config); err != nil {
+		return err
+	}
+	if err := validateWindowsNodeConfiguration(config); err != nil {
+		return err
+	}
+	if strings.EqualFold(config.RouteDriftReconciliationMode, consts.RouteDriftReconciliationModeRepair) && config.RouteNamePrefix == "" {
+		return fmt.Errorf("routeDriftReconciliationMode %s requires routeNamePrefix to tell the user-defined routes", config.RouteDriftReconciliationMode)
+	}
+	if config.FailOnUserRouteConflict && config.RouteNamePrefix == "" {
+		return fmt.Errorf("failOnUserRouteConflict requires routeNamePrefix to tell the user-defined routes")
+	}
+	if config.RouteTableShardCount < 0 {
+		return fmt.Errorf("routeTableShardCount %d cannot be negative", config.RouteTableShardCount)
+	}
+	if (config.RouteTableShardCount > 1 || len(config.RouteTableShardSubnetNames) > 0) && len(config.RouteTableShardSubnetNames) != max(config.RouteTableShardCount, 1) {
+		return fmt.Errorf("routeTableShardSubnetNames has %d subnets, but routeTableShardCount is %d", len(config.RouteTableShardSubnetNames), config.RouteTableShardCount)
+	}
+	credentialSetSubscriptionIDs := utilsets.NewString()
+	for i, set := range config.CredentialSets {
+		if set.SubscriptionID == "" {
+			return fmt.Errorf("credentialSets[%d] does not have subscriptionId", i)
+		}
+		if credentialSetSubscriptionIDs.Has(set.SubscriptionID) {
+			return fmt.Errorf("credentialSets[%d] has a duplicate subscriptionId %s", i, set.SubscriptionID)
+		}
+		credentialSetSubscriptionIDs.Insert(set.SubscriptionID)
+		if set.TenantID != "" && !strings.EqualFold(set.TenantID, config.GetTenantID()) && azclient.IsMultiTenant(&config.ARMClientConfig) {
+			return fmt.Errorf("credentialSets[%d] in tenant %s cannot be combined with networkResourceTenantID", i, set.TenantID)
+		}
+	}
 	if config.ClusterServiceSharedLoadBalancerHealthProbePort == 0 {
 		config.ClusterServiceSharedLoadBalancerHealthProbePort = consts.ClusterServiceLoadBalancerHealthProbeDefaultPort
 	}
@@ -334,6 +649,9 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		return err
 	}
 	resourceRequestBackoff := az.setCloudProviderBackoffDefaults(config)
+	if err := validateRateLimitConfiguration(config); err != nil {
+		return err
+	}
 
 	err = az.setLBDefaults(config)
 	if err != nil {
@@ -347,6 +665,15 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 	if err != nil {
 		return err
 	}
+	// IMDS uses the transport settings of ARM and AAD, but it is link-local and never accessed
+	// through a proxy, including the proxy of the environment.
+	transport, err := azclient.GetTransport(&config.ARMClientConfig)
+	if err != nil {
+		return err
+	}
+	imdsTransport := transport.Clone()
+	imdsTransport.Proxy = nil
+	az.Metadata.transport = imdsTransport
 
 	if az.MaximumLoadBalancerRuleCount == 0 {
 		az.MaximumLoadBalancerRuleCount = consts.MaximumLoadBalancerRuleCount
@@ -387,6 +714,15 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		if err != nil {
 			return err
 		}
+		// The credentials are rotated in place when the client secret changes
+		if authProvider.ComputeCredential != nil {
+			az.computeCredential = armauth.NewRotatableCredential(authProvider.ComputeCredential)
+			authProvider.ComputeCredential = az.computeCredential
+		}
+		if authProvider.NetworkCredential != nil {
+			az.networkCredential = armauth.NewRotatableCredential(authProvider.NetworkCredential)
+			authProvider.NetworkCredential = az.networkCredential
+		}
 		az.AuthProvider = authProvider
 	}
 	if az.AuthProvider.GetAzIdentity() == nil {
@@ -396,6 +732,10 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		if !config.UseInstanceMetadata && config.CloudConfigType == configloader.CloudConfigTypeFile {
 			return fmt.Errorf("useInstanceMetadata must be enabled without Azure credentials")
 		}
+		// The node addresses can only be read from IMDS without credentials
+		if strings.EqualFold(config.NodeAddressSource, consts.NodeAddressSourceARM) {
+			return fmt.Errorf("nodeAddressSource %s requires Azure credentials", config.NodeAddressSource)
+		}
 
 		klog.V(2).Infof("Azure cloud provider is starting without credentials")
 	}
@@ -406,38 +746,99 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 	}
 
 	if az.ComputeClientFactory == nil && az.AuthProvider != nil {
+		az.clientFactoryConfigs = nil
 		var (
 			computeCred = az.AuthProvider.GetAzIdentity()
 			networkCred = az.AuthProvider.GetNetworkAzIdentity() // It would fallback to compute credential if network credential is not set
 		)
 
+		var (
+			credentialSetAuthProviders []*azclient.AuthProvider
+			auxiliaryCreds             []azcore.TokenCredential
+		)
+		credentialSetAuthProviders, auxiliaryCreds, err = az.newCredentialSetAuthProviders()
+		if err != nil {
+			return err
+		}
+		// the GETs of the network and the compute clients are coalesced and cached by the shared read cache
+		readCacheOption := az.newReadCacheClientOption()
+		commonClientOptions := append([]func(option *arm.ClientOptions){readCacheOption}, az.armClientOptions...)
+		// the requests not served by the read cache are counted against the budgets
+		az.armRequestBudget = newARMRequestBudget(config.ARMRequestBudgetsPerHour)
+		if az.armRequestBudget != nil {
+			commonClientOptions = append(commonClientOptions, az.armRequestBudget.clientOption())
+		}
+		if injector := getARMFaultInjector(); injector != nil {
+			klog.Warningf("InitializeCloudFromConfig: injecting the faults into the ARM requests for testing")
+			commonClientOptions = append(commonClientOptions, injector.clientOption())
+		}
+		if trace.Enabled() {
+			commonClientOptions = append(commonClientOptions, trace.ARMClientOption)
+		}
+		var auditOption func(option *arm.ClientOptions)
+		auditOption, err = newARMAuditClientOption(ctx, config)
+		if err != nil {
+			return err
+		}
+		if auditOption != nil {
+			commonClientOptions = append(commonClientOptions, auditOption)
+		}
+		networkClientOptions := append([]func(option *arm.ClientOptions){}, commonClientOptions...)
+		if len(auxiliaryCreds) > 0 {
+			// The network resources of the cluster may reference the resources in the tenants of the credential sets
+			networkClientOptions = append(networkClientOptions, func(option *arm.ClientOptions) {
+				option.PerRetryPolicies = append(option.PerRetryPolicies, armauth.NewAuxiliaryAuthPolicy(
+					auxiliaryCreds,
+					azclient.DefaultTokenScopeFor(clientOps.Cloud),
+				))
+			})
+		}
+
 		networkSubscriptionID := az.getNetworkResourceSubscriptionID() // It would also fallback to compute subscription ID if network subscription ID is not set
-		az.NetworkClientFactory, err = newARMClientFactory(&azclient.ClientFactoryConfig{
-			SubscriptionID: networkSubscriptionID,
-		}, &az.ARMClientConfig, clientOps.Cloud, networkCred)
+		az.NetworkClientFactory, err = newARMClientFactory(az.newClientFactoryConfig(networkSubscriptionID),
+			&az.ARMClientConfig, clientOps.Cloud, networkCred, networkClientOptions...)
 		if err != nil {
 			return err
 		}
 		klog.InfoS("Setting up ARM client factory for network resources", "subscriptionID", networkSubscriptionID)
 
-		az.ComputeClientFactory, err = newARMClientFactory(&azclient.ClientFactoryConfig{
-			SubscriptionID: az.SubscriptionID,
-		}, &az.ARMClientConfig, clientOps.Cloud, computeCred, az.AuthProvider.AdditionalComputeClientOptions...)
+		computeClientOptions := append(commonClientOptions, az.AuthProvider.AdditionalComputeClientOptions...)
+		az.ComputeClientFactory, err = newARMClientFactory(az.newClientFactoryConfig(az.SubscriptionID),
+			&az.ARMClientConfig, clientOps.Cloud, computeCred, computeClientOptions...)
 		if err != nil {
 			return err
 		}
 		klog.InfoS("Setting up ARM client factory for compute resources", "subscriptionID", az.SubscriptionID)
+
+		az.credentialSetClientFactories = make(map[string]azclient.ClientFactory, len(az.CredentialSets))
+		for i := range az.CredentialSets {
+			set := &az.CredentialSets[i]
+			var factory azclient.ClientFactory
+			factory, err = newARMClientFactory(az.newClientFactoryConfig(set.SubscriptionID),
+				az.getCredentialSetARMClientConfig(set), clientOps.Cloud, credentialSetAuthProviders[i].GetAzIdentity(), commonClientOptions...)
+			if err != nil {
+				return fmt.Errorf("credentialSets[%d]: %w", i, err)
+			}
+			az.credentialSetClientFactories[strings.ToLower(set.SubscriptionID)] = factory
+			klog.InfoS("Setting up ARM client factory for credential set", "subscriptionID", set.SubscriptionID)
+		}
 	}
 
 	networkClientFactory := az.NetworkClientFactory
 
 	if az.nsgRepo == nil {
+		// the security groups are updated as a whole without the security rule client
+		var securityRuleClient securityruleclient.Interface
+		if az.featureEnabled(features.SecurityRuleUpdates) {
+			securityRuleClient = networkClientFactory.GetSecurityRuleClient()
+		}
 		az.nsgRepo, err = securitygroup.NewSecurityGroupRepo(
 			az.SecurityGroupResourceGroup,
 			az.SecurityGroupName,
 			az.NsgCacheTTLInSeconds,
 			az.DisableAPICallCache,
 			networkClientFactory.GetSecurityGroupClient(),
+			securityRuleClient,
 		)
 		if err != nil {
 			return err
@@ -464,6 +865,45 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		}
 	}
 
+	if (az.activityLogRepo == nil || az.arcMachineRepo == nil || az.resourceSKURepo == nil) && az.AuthProvider.GetAzIdentity() != nil {
+		var resourceClientOption *arm.ClientOptions
+		resourceClientOption, err = azclient.GetDefaultResourceClientOption(&az.ARMClientConfig)
+		if err != nil {
+			return err
+		}
+		for _, option := range az.armClientOptions {
+			option(resourceClientOption)
+		}
+		if az.activityLogRepo == nil && az.ActivityLogCacheInvalidationIntervalInSeconds > 0 {
+			az.activityLogRepo, err = activitylog.NewRepo(az.SubscriptionID, az.AuthProvider.GetAzIdentity(), resourceClientOption)
+			if err != nil {
+				return err
+			}
+		}
+		if az.arcMachineRepo == nil {
+			az.arcMachineRepo, err = arcmachine.NewRepo(az.AuthProvider.GetAzIdentity(), resourceClientOption)
+			if err != nil {
+				return err
+			}
+		}
+		if az.resourceSKURepo == nil {
+			az.resourceSKURepo, err = resourcesku.NewRepo(az.SubscriptionID, az.AuthProvider.GetAzIdentity(), resourceClientOption, resourceSKUCacheTTL)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	az.serviceReconcileBackoff = newServiceReconcileBackoff(
+		time.Duration(az.ServiceReconcileBackoffBaseDelayInSeconds)*time.Second,
+		time.Duration(az.ServiceReconcileBackoffMaxDelayInSeconds)*time.Second,
+		az.ServiceReconcileCircuitBreakerThreshold,
+		time.Duration(az.ServiceReconcileCircuitBreakerProbeIntervalInSeconds)*time.Second,
+	)
+	az.lbOperationTracker = newLBOperationTracker(az.EnableAsyncLoadBalancerUpdate)
+	az.serviceProvisioningTracker = newServiceProvisioningTracker()
+	az.probeTransitions = newProbeTransitionTracker()
+
 	if az.routeTableRepo == nil {
 		az.routeTableRepo, err = routetable.NewRepo(networkClientFactory.GetRouteTableClient(), az.RouteTableResourceGroup, time.Duration(az.RouteTableCacheTTLInSeconds)*time.Second, az.DisableAPICallCache)
 		if err != nil {
@@ -481,13 +921,39 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *config.C
 		if az.RouteUpdateIntervalInSeconds == 0 {
 			az.RouteUpdateIntervalInSeconds = consts.DefaultRouteUpdateIntervalInSeconds
 		}
+		az.routeMetrics = newRouteMetricsTracker()
 		az.routeUpdater = newDelayedRouteUpdater(az, time.Duration(az.RouteUpdateIntervalInSeconds)*time.Second)
-		go az.routeUpdater.run(ctx)
+		go az.routeUpdater.run(withARMCaller(ctx, armCallerRoute))
+
+		// start the route drift reconciler.
+		if az.RouteDriftReconciliationMode != "" {
+			if az.RouteDriftReconciliationIntervalInSeconds == 0 {
+				az.RouteDriftReconciliationIntervalInSeconds = consts.DefaultRouteDriftReconciliationIntervalInSeconds
+			}
+			go az.runRouteDriftReconciler(withARMCaller(ctx, armCallerBackground), time.Duration(az.RouteDriftReconciliationIntervalInSeconds)*time.Second)
+		}
 
 		// start backend pool updater.
-		if az.UseMultipleStandardLoadBalancers() {
+		if az.UseLocalServiceBackendPools() {
 			az.backendPoolUpdater = newLoadBalancerBackendPoolUpdater(az, time.Duration(az.LoadBalancerBackendPoolUpdateIntervalInSeconds)*time.Second)
-			go az.backendPoolUpdater.run(ctx)
+			go az.backendPoolUpdater.run(withARMCaller(ctx, armCallerService))
+		}
+
+		// start the requeuer updating the backend pools when nodes age in or leave them.
+		if az.isNodeBackendPoolGatingEnabled() {
+			az.nodeEligibilityRequeuer = newNodeEligibilityRequeuer(az)
+			go az.nodeEligibilityRequeuer.run(ctx)
+		}
+
+		// start the tracker deferring the node sync updates during the scale waves of the nodes.
+		if az.NodeScaleWaveThreshold > 0 {
+			az.nodeScaleWaveTracker = newNodeScaleWaveTracker(az)
+			go az.nodeScaleWaveTracker.run(withARMCaller(ctx, armCallerService))
+		}
+
+		// start the cache invalidator polling the activity log.
+		if az.activityLogRepo != nil {
+			go az.runActivityLogCacheInvalidator(withARMCaller(ctx, armCallerBackground), time.Duration(az.ActivityLogCacheInvalidationIntervalInSeconds)*time.Second)
 		}
 
 		// Azure Stack does not support zone at the moment
@@ -557,6 +1023,26 @@ func (az *Cloud) initCaches() (err error) {
 		return err
 	}
 
+	az.nodeIdentityCache, err = az.newNodeIdentityCache()
+	if err != nil {
+		return err
+	}
+
+	az.routeTableSubnetCache, err = az.newRouteTableSubnetCache()
+	if err != nil {
+		return err
+	}
+
+	az.routeNodeInterfacesCache, err = az.newRouteNodeInterfacesCache()
+	if err != nil {
+		return err
+	}
+
+	az.internalLoadBalancerVNetCache, err = az.newInternalLoadBalancerVNetCache()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -623,12 +1109,36 @@ func (az *Cloud) setCloudProviderBackoffDefaults(config *azureconfig.Config) wai
 	return resourceRequestBackoff
 }
 
+// featureEnabled returns true if the Azure feature is enabled for the cloud.
+func (az *Cloud) featureEnabled(feature featuregate.Feature) bool {
+	return features.Enabled(az.featureGates, feature)
+}
+
+// SetEventPolicy sets the aggregation policy of the events recorded by the cloud. It must be called before
+// Initialize.
+func (az *Cloud) SetEventPolicy(policy eventrecorder.Policy) {
+	az.eventPolicy = &policy
+}
+
 // Initialize passes a Kubernetes clientBuilder interface to the cloud provider
-func (az *Cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder, _ <-chan struct{}) {
+func (az *Cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
 	az.KubeClient = clientBuilder.ClientOrDie("azure-cloud-provider")
-	az.eventBroadcaster = record.NewBroadcaster()
-	az.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: az.KubeClient.CoreV1().Events("")})
-	az.eventRecorder = az.eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "azure-cloud-provider"})
+	az.warmStandby.Store(false)
+	ctx := wait.ContextForChannel(stop)
+	eventPolicy := eventrecorder.DefaultPolicy()
+	if az.eventPolicy != nil {
+		eventPolicy = *az.eventPolicy
+	}
+	az.eventRecorder = eventrecorder.New(ctx, az.KubeClient, "azure-cloud-provider", eventPolicy)
+	az.startCacheSnapshots(ctx)
+	if az.StatusReportIntervalInSeconds > 0 {
+		statusClient, err := dynamic.NewForConfig(clientBuilder.ConfigOrDie("azure-cloud-provider"))
+		if err != nil {
+			klog.Errorf("Initialize: failed to create the client of the status: %v", err)
+			return
+		}
+		go az.runStatusReporter(withARMCaller(ctx, armCallerBackground), statusClient, time.Duration(az.StatusReportIntervalInSeconds)*time.Second)
+	}
 }
 
 // LoadBalancer returns a balancer interface. Also returns true if the interface is supported, false otherwise.
@@ -683,6 +1193,11 @@ func (az *Cloud) ProviderName() string {
 
 // SetInformers sets informers for Azure cloud provider.
 func (az *Cloud) SetInformers(informerFactory informers.SharedInformerFactory) {
+	if az.informerFactory == informerFactory {
+		// the informers are set up already when the cloud is kept warm on a non-leader replica
+		return
+	}
+	az.informerFactory = informerFactory
 	klog.Infof("Setting up informers for Azure cloud provider")
 	nodeInformer := informerFactory.Core().V1().Nodes().Informer()
 	_, _ = nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -690,12 +1205,15 @@ func (az *Cloud) SetInformers(informerFactory informers.SharedInformerFactory) {
 			node := obj.(*v1.Node)
 			az.updateNodeCaches(nil, node)
 			az.updateNodeTaint(node)
+			az.deleteVMNotFoundCacheForNode(node.Name)
+			az.nodeScaleWaveTracker.onNodeAdd(node, time.Now())
 		},
 		UpdateFunc: func(prev, obj interface{}) {
 			prevNode := prev.(*v1.Node)
 			newNode := obj.(*v1.Node)
 			az.updateNodeCaches(prevNode, newNode)
 			az.updateNodeTaint(newNode)
+			az.nodeEligibilityRequeuer.onNodeUpdate(prevNode, newNode)
 		},
 		DeleteFunc: func(obj interface{}) {
 			node, isNode := obj.(*v1.Node)
@@ -714,6 +1232,7 @@ func (az *Cloud) SetInformers(informerFactory informers.SharedInformerFactory) {
 				}
 			}
 			az.updateNodeCaches(node, nil)
+			az.nodeScaleWaveTracker.onNodeChange(false, time.Now())
 
 			klog.V(4).Infof("Removing node %s from VMSet cache.", node.Name)
 			_ = az.VMSet.DeleteCacheForNode(context.Background(), node.Name)
@@ -722,8 +1241,16 @@ func (az *Cloud) SetInformers(informerFactory informers.SharedInformerFactory) {
 	az.nodeInformerSynced = nodeInformer.HasSynced
 
 	az.serviceLister = informerFactory.Core().V1().Services().Lister()
+	az.nodeLister = informerFactory.Core().V1().Nodes().Lister()
+	if az.NodeDeletionGracePeriodInSeconds > 0 && !az.podInformerDisabled {
+		az.setUpPodInformer(informerFactory)
+	}
 
 	az.setUpEndpointSlicesInformer(informerFactory)
+
+	if ss, ok := az.VMSet.(*ScaleSet); ok && !az.DisableAPICallCache {
+		go ss.warmUpCaches(context.Background(), nodeInformer.HasSynced)
+	}
 }
 
 // updateNodeCaches updates local cache for node's zones and external resource groups.
@@ -749,6 +1276,8 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 		if ok {
 			delete(az.nodeResourceGroups, prevNode.ObjectMeta.Name)
 		}
+		delete(az.nodeProviderIDResourceGroups, prevNode.ObjectMeta.Name)
+		delete(az.nodeRouteIPConfigurations, prevNode.ObjectMeta.Name)
 
 		managed, ok := prevNode.ObjectMeta.Labels[consts.ManagedByAzureLabel]
 		isNodeManagedByCloudProvider := !ok || !strings.EqualFold(managed, consts.NotManagedByAzureLabelValue)
@@ -761,6 +1290,12 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 			az.unmanagedNodes.Delete(prevNode.ObjectMeta.Name)
 		}
 
+		// Remove from windowsNodeNames cache
+		az.windowsNodeNames.Delete(prevNode.ObjectMeta.Name)
+
+		// Remove from nodesMarkedForDeletion cache
+		az.nodesMarkedForDeletion.Delete(prevNode.ObjectMeta.Name)
+
 		// Remove from nodePrivateIPs cache.
 		for _, address := range getNodePrivateIPAddresses(prevNode) {
 			klog.V(6).Infof("removing IP address %s of the node %s", address, prevNode.Name)
@@ -773,6 +1308,7 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 			az.excludeLoadBalancerNodes.Insert(prevNode.ObjectMeta.Name)
 			az.nodesWithCorrectLoadBalancerByPrimaryVMSet.Delete(strings.ToLower(prevNode.ObjectMeta.Name))
 			delete(az.nodePrivateIPs, strings.ToLower(prevNode.Name))
+			az.nodesWithOutdatedRouteNextHops.Delete(prevNode.ObjectMeta.Name)
 		}
 	}
 
@@ -790,6 +1326,22 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 		newRG, ok := newNode.ObjectMeta.Labels[consts.ExternalResourceGroupLabel]
 		if ok && len(newRG) > 0 {
 			az.nodeResourceGroups[newNode.ObjectMeta.Name] = strings.ToLower(newRG)
+		} else {
+			az.updateNodeProviderIDResourceGroup(newNode)
+		}
+
+		// Add to nodeRouteIPConfigurations cache.
+		if ipConfigurations := newNode.ObjectMeta.Annotations[consts.RouteIPConfigurationAnnotation]; len(ipConfigurations) > 0 {
+			if az.nodeRouteIPConfigurations == nil {
+				az.nodeRouteIPConfigurations = map[string]string{}
+			}
+			az.nodeRouteIPConfigurations[newNode.ObjectMeta.Name] = ipConfigurations
+		}
+
+		// Add to nodesWithOutdatedRouteNextHops cache.
+		if prevNode == nil && az.hasRouteNextHopOverrides(newNode) ||
+			prevNode != nil && prevNode.ObjectMeta.Annotations[consts.RouteIPConfigurationAnnotation] != newNode.ObjectMeta.Annotations[consts.RouteIPConfigurationAnnotation] {
+			az.nodesWithOutdatedRouteNextHops = utilsets.SafeInsert(az.nodesWithOutdatedRouteNextHops, newNode.ObjectMeta.Name)
 		}
 
 		_, hasExcludeBalancerLabel := newNode.ObjectMeta.Labels[v1.LabelNodeExcludeBalancers]
@@ -801,6 +1353,17 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 			az.unmanagedNodes.Insert(newNode.ObjectMeta.Name)
 		}
 
+		// Update windowsNodeNames cache
+		if isWindowsNode(newNode) {
+			az.windowsNodeNames = utilsets.SafeInsert(az.windowsNodeNames, newNode.ObjectMeta.Name)
+		}
+
+		// Update nodesMarkedForDeletion cache
+		isNodeMarkedForDeletion := az.IsNodeMarkedForDeletion(newNode)
+		if isNodeMarkedForDeletion {
+			az.nodesMarkedForDeletion = utilsets.SafeInsert(az.nodesMarkedForDeletion, newNode.ObjectMeta.Name)
+		}
+
 		// Update excludeLoadBalancerNodes cache
 		switch {
 		case !isNodeManagedByCloudProvider:
@@ -811,8 +1374,12 @@ func (az *Cloud) updateNodeCaches(prevNode, newNode *v1.Node) {
 			az.excludeLoadBalancerNodes.Insert(newNode.ObjectMeta.Name)
 			klog.V(6).Infof("excluding Node %q from LoadBalancer because it has exclude-from-external-load-balancers label", newNode.ObjectMeta.Name)
 
+		case isNodeMarkedForDeletion:
+			az.excludeLoadBalancerNodes.Insert(newNode.ObjectMeta.Name)
+			klog.V(6).Infof("excluding Node %q from LoadBalancer because it is marked for deletion", newNode.ObjectMeta.Name)
+
 		default:
-			// Nodes not falling into the three cases above are valid backends and
+			// Nodes not falling into the cases above are valid backends and
 			// should not appear in excludeLoadBalancerNodes cache.
 			az.excludeLoadBalancerNodes.Delete(newNode.ObjectMeta.Name)
 		}
@@ -903,10 +1470,36 @@ func (az *Cloud) GetNodeResourceGroup(nodeName string) (string, error) {
 		return cachedRG, nil
 	}
 
+	// Return the resource group in the provider ID if it is not the configured one.
+	if providerIDRG, ok := az.nodeProviderIDResourceGroups[nodeName]; ok {
+		return providerIDRG, nil
+	}
+
 	// Return resource group from cloud provider options.
 	return az.ResourceGroup, nil
 }
 
+// updateNodeProviderIDResourceGroup caches the resource group in the provider ID of the node
+// if it is not the configured resource group, so the VMs of node pools in other resource groups
+// can be found without the external resource group label. Nodes in other subscriptions are
+// ignored because the clients only work with the configured subscription.
+// It should be called with nodeCachesLock held.
+func (az *Cloud) updateNodeProviderIDResourceGroup(node *v1.Node) {
+	subscriptionID, resourceGroup, ok := getSubscriptionAndResourceGroupFromProviderID(node.Spec.ProviderID)
+	if !ok || strings.EqualFold(resourceGroup, az.ResourceGroup) {
+		return
+	}
+	if az.SubscriptionID != "" && !strings.EqualFold(subscriptionID, az.SubscriptionID) {
+		klog.Warningf("updateNodeProviderIDResourceGroup: node %s is in subscription %s instead of %s, which is not supported", node.Name, subscriptionID, az.SubscriptionID)
+		return
+	}
+
+	if az.nodeProviderIDResourceGroups == nil {
+		az.nodeProviderIDResourceGroups = map[string]string{}
+	}
+	az.nodeProviderIDResourceGroups[node.Name] = strings.ToLower(resourceGroup)
+}
+
 // GetNodeNames returns a set of all node names in the k8s cluster.
 func (az *Cloud) GetNodeNames() (*utilsets.IgnoreCaseSet, error) {
 	// Kubelet won't set az.nodeInformerSynced, return nil.
@@ -940,6 +1533,9 @@ func (az *Cloud) GetResourceGroups() (*utilsets.IgnoreCaseSet, error) {
 	for _, rg := range az.nodeResourceGroups {
 		resourceGroups.Insert(rg)
 	}
+	for _, rg := range az.nodeProviderIDResourceGroups {
+		resourceGroups.Insert(rg)
+	}
 
 	return resourceGroups, nil
 }
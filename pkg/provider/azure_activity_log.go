@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	resourceTypeVirtualMachines          = "Microsoft.Compute/virtualMachines"
+	resourceTypeVirtualMachineScaleSets  = "Microsoft.Compute/virtualMachineScaleSets"
+	resourceTypeVirtualMachineScaleSetVM = "Microsoft.Compute/virtualMachineScaleSets/virtualMachines"
+
+	// activityLogIngestionLag is how long events can take to show up in the activity log after their
+	// timestamps. Each poll lists the events of this window again, so the late events are not missed.
+	activityLogIngestionLag = 15 * time.Minute
+)
+
+// runActivityLogCacheInvalidator polls the activity log of the resource groups every interval and
+// removes the VMs and VMSS VMs written or deleted since the last poll from the caches, so they are
+// refreshed on the next read instead of after the cache TTL.
+func (az *Cloud) runActivityLogCacheInvalidator(ctx context.Context, interval time.Duration) {
+	klog.V(2).Infof("runActivityLogCacheInvalidator: polling the activity log every %s", interval)
+	since := time.Now()
+	seenEventIDs := make(map[string]time.Time)
+	err := wait.PollUntilContextCancel(ctx, interval, false, func(ctx context.Context) (bool, error) {
+		// the events since the last poll are listed by the next poll after a skipped one
+		if !az.armRequestBudget.allowBackground() {
+			klog.V(2).Infof("runActivityLogCacheInvalidator: skipped as the ARM request budget is nearly consumed")
+			return false, nil
+		}
+		since = az.invalidateCachesByActivityLog(ctx, since, seenEventIDs)
+		return false, nil
+	})
+	klog.V(2).Infof("runActivityLogCacheInvalidator: stopped with error: %s", err.Error())
+}
+
+// invalidateCachesByActivityLog invalidates the caches of the resources in the events of all resource
+// groups since the given time, and returns the time to list the events from on the next poll. The
+// next poll overlaps with this one by activityLogIngestionLag, and the events already handled are
+// skipped by their IDs in seenEventIDs.
+func (az *Cloud) invalidateCachesByActivityLog(ctx context.Context, since time.Time, seenEventIDs map[string]time.Time) time.Time {
+	now := time.Now()
+	resourceGroups, err := az.GetResourceGroups()
+	if err != nil {
+		klog.Errorf("invalidateCachesByActivityLog: failed to get the resource groups: %v", err)
+		return since
+	}
+
+	succeeded := true
+	for _, resourceGroup := range resourceGroups.UnsortedList() {
+		events, err := az.activityLogRepo.ListEvents(ctx, resourceGroup, since)
+		if err != nil {
+			klog.Errorf("invalidateCachesByActivityLog: failed to list the activity log events of resource group %s since %s: %v", resourceGroup, since, err)
+			succeeded = false
+			continue
+		}
+		for _, event := range events {
+			if event.ID != "" {
+				if _, seen := seenEventIDs[event.ID]; seen {
+					continue
+				}
+				seenEventIDs[event.ID] = event.Timestamp
+			}
+			klog.V(4).Infof("invalidateCachesByActivityLog: invalidating the cache of %s after %s", event.ResourceID, event.OperationName)
+			az.invalidateCacheByResourceID(event.ResourceID)
+		}
+	}
+	if !succeeded {
+		return since
+	}
+
+	next := now.Add(-activityLogIngestionLag)
+	if next.Before(since) {
+		next = since
+	}
+	// the events before the next poll are not listed again
+	for id, timestamp := range seenEventIDs {
+		if timestamp.Before(next) {
+			delete(seenEventIDs, id)
+		}
+	}
+	return next
+}
+
+// invalidateCacheByResourceID removes the VM or the VMSS VMs of the resource from the caches.
+func (az *Cloud) invalidateCacheByResourceID(resourceID string) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		klog.V(4).Infof("invalidateCacheByResourceID: failed to parse resource ID %s: %v", resourceID, err)
+		return
+	}
+
+	switch {
+	case strings.EqualFold(id.ResourceType.String(), resourceTypeVirtualMachines):
+		_ = az.vmCache.Delete(strings.ToLower(id.Name))
+	case strings.EqualFold(id.ResourceType.String(), resourceTypeVirtualMachineScaleSets):
+		if ss, ok := az.VMSet.(*ScaleSet); ok {
+			_ = ss.vmssCache.Delete(consts.VMSSKey)
+			_ = ss.vmssVMCache.Delete(getVMSSVMCacheKey(id.ResourceGroupName, id.Name))
+		}
+	case strings.EqualFold(id.ResourceType.String(), resourceTypeVirtualMachineScaleSetVM):
+		if ss, ok := az.VMSet.(*ScaleSet); ok && id.Parent != nil {
+			_ = ss.vmssVMCache.Delete(getVMSSVMCacheKey(id.ResourceGroupName, id.Parent.Name))
+		}
+	}
+}
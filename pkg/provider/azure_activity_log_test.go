@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/activitylog"
+)
+
+func TestInvalidateCachesByActivityLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	ss.VMSet = ss
+	repo := activitylog.NewMockRepository(ctrl)
+	ss.activityLogRepo = repo
+
+	vmssVMCacheKey := getVMSSVMCacheKey("rg", "vmss")
+	otherVMSSVMCacheKey := getVMSSVMCacheKey("rg", "vmss-other")
+	ss.vmCache.Update("vm1", &sync.Map{})
+	ss.vmCache.Update("vm2", &sync.Map{})
+	ss.vmssVMCache.Update(vmssVMCacheKey, &sync.Map{})
+	ss.vmssVMCache.Update(otherVMSSVMCacheKey, &sync.Map{})
+
+	ss.nodeInformerSynced = func() bool { return true }
+	ss.nodeResourceGroups = map[string]string{"node": "rg-other"}
+
+	vm1Event := activitylog.Event{
+		ID:            "event1",
+		ResourceID:    "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/VM1",
+		OperationName: "Microsoft.Compute/virtualMachines/write",
+		Timestamp:     time.Now(),
+	}
+	since := time.Now().Add(-time.Minute)
+	seenEventIDs := make(map[string]time.Time)
+	repo.EXPECT().ListEvents(gomock.Any(), "rg", since).Return([]activitylog.Event{
+		vm1Event,
+		{ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0", OperationName: "Microsoft.Compute/virtualMachineScaleSets/virtualMachines/write"},
+		{ResourceID: "invalid", OperationName: "Microsoft.Compute/virtualMachines/write"},
+	}, nil)
+	repo.EXPECT().ListEvents(gomock.Any(), "rg-other", since).Return(nil, nil)
+	next := ss.invalidateCachesByActivityLog(context.TODO(), since, seenEventIDs)
+	assert.Equal(t, since, next, "should not move past the ingestion lag")
+
+	for key, expected := range map[string]bool{"vm1": false, "vm2": true} {
+		_, exists, err := ss.vmCache.GetStore().GetByKey(key)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, exists, key)
+	}
+	for key, expected := range map[string]bool{vmssVMCacheKey: false, otherVMSSVMCacheKey: true} {
+		_, exists, err := ss.vmssVMCache.GetStore().GetByKey(key)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, exists, key)
+	}
+
+	// the events seen by the overlapping poll are skipped
+	ss.vmCache.Update("vm1", &sync.Map{})
+	repo.EXPECT().ListEvents(gomock.Any(), "rg", since).Return([]activitylog.Event{vm1Event}, nil)
+	repo.EXPECT().ListEvents(gomock.Any(), "rg-other", since).Return(nil, errors.New("list error"))
+	assert.Equal(t, since, ss.invalidateCachesByActivityLog(context.TODO(), since, seenEventIDs), "should retry the events since the last poll on errors")
+	_, exists, err := ss.vmCache.GetStore().GetByKey("vm1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// the next poll starts the ingestion lag before now, and forgets the events before it
+	since = time.Now().Add(-time.Hour)
+	seenEventIDs["old"] = since
+	repo.EXPECT().ListEvents(gomock.Any(), "rg", since).Return(nil, nil)
+	repo.EXPECT().ListEvents(gomock.Any(), "rg-other", since).Return(nil, nil)
+	next = ss.invalidateCachesByActivityLog(context.TODO(), since, seenEventIDs)
+	assert.WithinDuration(t, time.Now().Add(-activityLogIngestionLag), next, 5*time.Second)
+	assert.Equal(t, map[string]time.Time{"event1": vm1Event.Timestamp}, seenEventIDs)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/arcmachine"
+)
+
+const arcMachineProviderIDPrefix = "azure-arc://"
+
+var arcMachineProviderIDRE = regexp.MustCompile(`(?i)^azure-arc:///subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.HybridCompute/machines/[^/]+$`)
+
+// isArcMachineProviderID returns true if the provider ID is of an Azure Arc-enabled machine, which is in format
+// 'azure-arc:///subscriptions/<id>/resourceGroups/<rg>/providers/Microsoft.HybridCompute/machines/<name>'.
+func isArcMachineProviderID(providerID string) bool {
+	return arcMachineProviderIDRE.MatchString(providerID)
+}
+
+// getArcMachineByProviderID returns the Arc-enabled machine of the provider ID, or nil if it is not found.
+func (az *Cloud) getArcMachineByProviderID(ctx context.Context, providerID string) (*arcmachine.Machine, error) {
+	if az.arcMachineRepo == nil {
+		// arcMachineRepo == nil indicates credentials are not provided.
+		return nil, fmt.Errorf("no credentials provided for Azure cloud provider")
+	}
+	return az.arcMachineRepo.Get(ctx, strings.TrimPrefix(providerID, arcMachineProviderIDPrefix))
+}
+
+// arcMachineExistsByProviderID returns true if the Arc-enabled machine of the provider ID exists. The
+// machines disconnected from Azure still exist, so the edge nodes are kept in the cluster.
+func (az *Cloud) arcMachineExistsByProviderID(ctx context.Context, providerID string) (bool, error) {
+	machine, err := az.getArcMachineByProviderID(ctx, providerID)
+	if err != nil {
+		return false, err
+	}
+	if machine == nil {
+		klog.V(2).Infof("arcMachineExistsByProviderID: Arc-enabled machine %q is not found", providerID)
+		return false, nil
+	}
+	return true, nil
+}
+
+// getArcMachineMetadata returns the metadata of the Arc-enabled machine of the provider ID. The Hybrid
+// Compute API doesn't report the instance type, the zone and the addresses of the machines.
+func (az *Cloud) getArcMachineMetadata(ctx context.Context, providerID string) (*cloudprovider.InstanceMetadata, error) {
+	machine, err := az.getArcMachineByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if machine == nil {
+		return nil, cloudprovider.InstanceNotFound
+	}
+	return &cloudprovider.InstanceMetadata{
+		ProviderID: providerID,
+		Region:     strings.ToLower(machine.Location),
+	}, nil
+}
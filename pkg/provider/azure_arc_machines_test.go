@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cloudprovider "k8s.io/cloud-provider"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/arcmachine"
+)
+
+func TestIsArcMachineProviderID(t *testing.T) {
+	assert.True(t, isArcMachineProviderID("azure-arc:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.HybridCompute/machines/machine1"))
+	assert.False(t, isArcMachineProviderID("azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1"))
+	assert.False(t, isArcMachineProviderID("azure-arc:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1"))
+}
+
+func TestArcMachineInstances(t *testing.T) {
+	const (
+		machineID  = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.HybridCompute/machines/machine1"
+		providerID = "azure-arc://" + machineID
+	)
+
+	for _, tc := range []struct {
+		desc             string
+		machine          *arcmachine.Machine
+		expectedExists   bool
+		expectedMetadata *cloudprovider.InstanceMetadata
+		expectedErr      error
+	}{
+		{
+			desc:             "should report the existing Arc-enabled machines",
+			machine:          &arcmachine.Machine{ID: machineID, Name: "machine1", Location: "EastUS", Status: "Disconnected"},
+			expectedExists:   true,
+			expectedMetadata: &cloudprovider.InstanceMetadata{ProviderID: providerID, Region: "eastus"},
+		},
+		{
+			desc:        "should report the Arc-enabled machines not found",
+			expectedErr: cloudprovider.InstanceNotFound,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			repo := arcmachine.NewMockRepository(ctrl)
+			repo.EXPECT().Get(gomock.Any(), machineID).Return(tc.machine, nil).Times(2)
+			az.arcMachineRepo = repo
+
+			exists, err := az.InstanceExistsByProviderID(context.TODO(), providerID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedExists, exists)
+
+			shutdown, err := az.InstanceShutdownByProviderID(context.TODO(), providerID)
+			assert.NoError(t, err)
+			assert.False(t, shutdown)
+
+			metadata, err := az.InstanceMetadata(context.TODO(), &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine1"},
+				Spec:       v1.NodeSpec{ProviderID: providerID},
+			})
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedMetadata, metadata)
+		})
+	}
+}
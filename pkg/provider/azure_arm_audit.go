@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/audit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+// newARMAuditClientOption returns the client option recording the mutating ARM calls to the audit log and the
+// audit webhook, or nil if neither is configured. The records are sent until the context is done.
+func newARMAuditClientOption(ctx context.Context, config *config.Config) (func(option *arm.ClientOptions), error) {
+	var sinks audit.MultiSink
+	if config.ARMAuditLogPath != "" {
+		// the file is left open for the lifetime of the process
+		file, err := os.OpenFile(config.ARMAuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the ARM audit log: %w", err)
+		}
+		sinks = append(sinks, audit.NewWriterSink(file))
+	}
+	if config.ARMAuditWebhookURL != "" {
+		sinks = append(sinks, audit.NewWebhookSink(ctx, config.ARMAuditWebhookURL))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	auditPolicy := audit.NewPolicy(sinks)
+	return func(option *arm.ClientOptions) {
+		option.PerCallPolicies = append(option.PerCallPolicies, auditPolicy)
+	}, nil
+}
+
+func validateARMAuditWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("armAuditWebhookURL %q is invalid: %w", webhookURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("armAuditWebhookURL %q is not an http(s) URL", webhookURL)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+func TestNewARMAuditClientOption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	option, err := newARMAuditClientOption(ctx, &config.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, option)
+
+	option, err = newARMAuditClientOption(ctx, &config.Config{
+		ARMAuditLogPath:    filepath.Join(t.TempDir(), "audit.log"),
+		ARMAuditWebhookURL: "https://audit.example.com",
+	})
+	assert.NoError(t, err)
+	if !assert.NotNil(t, option) {
+		return
+	}
+	clientOptions := &arm.ClientOptions{}
+	option(clientOptions)
+	assert.Len(t, clientOptions.PerCallPolicies, 1)
+
+	_, err = newARMAuditClientOption(ctx, &config.Config{ARMAuditLogPath: filepath.Join(t.TempDir(), "missing", "audit.log")})
+	assert.Error(t, err)
+}
+
+func TestValidateARMAuditWebhookURL(t *testing.T) {
+	assert.NoError(t, validateARMAuditWebhookURL(""))
+	assert.NoError(t, validateARMAuditWebhookURL("https://audit.example.com/records"))
+	assert.Error(t, validateARMAuditWebhookURL("audit.example.com"))
+	assert.Error(t, validateARMAuditWebhookURL("ftp://audit.example.com"))
+}
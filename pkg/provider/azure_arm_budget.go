@@ -0,0 +1,216 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	armmetrics "sigs.k8s.io/cloud-provider-azure/pkg/azclient/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// The callers the ARM requests are accounted to.
+const (
+	// armCallerService is the caller of the load balancer reconciliations of the services.
+	armCallerService = "service"
+	// armCallerNode is the caller of the node lifecycle checks and the node metadata.
+	armCallerNode = "node"
+	// armCallerRoute is the caller of the route reconciliations.
+	armCallerRoute = "route"
+	// armCallerBackground is the caller of the low priority background reconciliations, which
+	// are skipped when the budget is nearly consumed.
+	armCallerBackground = "background"
+	// armCallerOther is the caller of the requests not attributed to the others.
+	armCallerOther = "other"
+
+	// armRequestBudgetBackgroundThreshold is the share of the total budget consumed in the hour
+	// after which the background reconciliations are skipped.
+	armRequestBudgetBackgroundThreshold = 0.9
+)
+
+var (
+	armCallers = []string{armCallerService, armCallerNode, armCallerRoute, armCallerBackground, armCallerOther}
+
+	armRequestCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "arm_requests_total",
+			Help:           "Number of ARM requests sent by the callers, including the retries",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"caller"},
+	)
+	armRequestBudgetUsage = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "arm_request_budget_usage_ratio",
+			Help:           "Ratio of the hourly ARM request budget of the callers consumed in the current hour",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"caller"},
+	)
+
+	registerARMRequestBudgetMetricsOnce sync.Once
+)
+
+func registerARMRequestBudgetMetrics() {
+	registerARMRequestBudgetMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(armRequestCount)
+		legacyregistry.MustRegister(armRequestBudgetUsage)
+	})
+}
+
+// withARMCaller returns a context accounting the ARM requests sent with it to the caller. The
+// caller is recorded in the metrics of the ARM requests too.
+func withARMCaller(ctx context.Context, caller string) context.Context {
+	return armmetrics.WithCaller(ctx, caller)
+}
+
+func armCallerFromContext(ctx context.Context) string {
+	if caller, ok := armmetrics.CallerFromContext(ctx); ok {
+		return caller
+	}
+	return armCallerOther
+}
+
+// validateARMRequestBudgets validates the budgets in the config.
+func validateARMRequestBudgets(budgets map[string]int) error {
+	for caller, budget := range budgets {
+		known := false
+		for _, c := range armCallers {
+			known = known || c == caller
+		}
+		if !known {
+			return fmt.Errorf("armRequestBudgetsPerHour: unknown caller %q, supported callers are %v", caller, armCallers)
+		}
+		if budget < 0 {
+			return fmt.Errorf("armRequestBudgetsPerHour: the budget of caller %q is negative", caller)
+		}
+	}
+	return nil
+}
+
+// armRequestBudget counts the ARM requests of the callers in the current hour against their
+// hourly budgets. It is a pipeline policy of the ARM clients counting every attempt, as the
+// retries consume the quota too. The requests served by the read cache are not counted. A nil
+// armRequestBudget counts nothing and never throttles.
+type armRequestBudget struct {
+	lock    sync.Mutex
+	budgets map[string]int
+	total   int
+	// window is the start of the current hour, and counts are the requests in it.
+	window time.Time
+	counts map[string]int
+	// exceeded are the callers already reported to exceed their budgets in the window.
+	exceeded map[string]bool
+
+	now func() time.Time
+}
+
+// newARMRequestBudget returns the accounting of the budgets, or nil if there are no budgets.
+func newARMRequestBudget(budgets map[string]int) *armRequestBudget {
+	if len(budgets) == 0 {
+		return nil
+	}
+	registerARMRequestBudgetMetrics()
+	b := &armRequestBudget{
+		budgets:  budgets,
+		counts:   make(map[string]int),
+		exceeded: make(map[string]bool),
+		now:      time.Now,
+	}
+	for _, budget := range budgets {
+		b.total += budget
+	}
+	return b
+}
+
+// clientOption returns the client option counting the requests of the ARM clients.
+func (b *armRequestBudget) clientOption() func(option *arm.ClientOptions) {
+	return func(option *arm.ClientOptions) {
+		option.PerRetryPolicies = append(option.PerRetryPolicies, b)
+	}
+}
+
+// Do implements policy.Policy.
+func (b *armRequestBudget) Do(req *policy.Request) (*http.Response, error) {
+	b.count(armCallerFromContext(req.Raw().Context()))
+	return req.Next()
+}
+
+func (b *armRequestBudget) count(caller string) {
+	armRequestCount.WithLabelValues(caller).Inc()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rotateWindow()
+	b.counts[caller]++
+	budget, found := b.budgets[caller]
+	if !found || budget == 0 {
+		return
+	}
+	armRequestBudgetUsage.WithLabelValues(caller).Set(float64(b.counts[caller]) / float64(budget))
+	if b.counts[caller] > budget && !b.exceeded[caller] {
+		b.exceeded[caller] = true
+		klog.Warningf("armRequestBudget: caller %s exceeded its budget of %d ARM requests in the hour since %s", caller, budget, b.window)
+	}
+}
+
+// rotateWindow resets the counts when the hour changes. It is called with the lock held.
+func (b *armRequestBudget) rotateWindow() {
+	window := b.now().Truncate(time.Hour)
+	if window.Equal(b.window) {
+		return
+	}
+	b.window = window
+	b.counts = make(map[string]int)
+	b.exceeded = make(map[string]bool)
+	for caller := range b.budgets {
+		armRequestBudgetUsage.WithLabelValues(caller).Set(0)
+	}
+}
+
+// allowBackground reports whether the background reconciliations can run, which is unless the
+// background budget or the threshold of the total budget is consumed in the current hour. A
+// budget of 0 is unlimited, as in count.
+func (b *armRequestBudget) allowBackground() bool {
+	if b == nil {
+		return true
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rotateWindow()
+
+	if budget := b.budgets[armCallerBackground]; budget > 0 && b.counts[armCallerBackground] >= budget {
+		return false
+	}
+	used := 0
+	for _, count := range b.counts {
+		used += count
+	}
+	return b.total == 0 || float64(used) < armRequestBudgetBackgroundThreshold*float64(b.total)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeARMBudgetTransport struct{}
+
+func (fakeARMBudgetTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestValidateARMRequestBudgets(t *testing.T) {
+	assert.NoError(t, validateARMRequestBudgets(nil))
+	assert.NoError(t, validateARMRequestBudgets(map[string]int{"service": 1000, "background": 100}))
+	assert.Error(t, validateARMRequestBudgets(map[string]int{"gc": 100}))
+	assert.Error(t, validateARMRequestBudgets(map[string]int{"node": -1}))
+}
+
+func TestARMRequestBudgetCountsRequests(t *testing.T) {
+	b := newARMRequestBudget(map[string]int{armCallerService: 10})
+	options := &arm.ClientOptions{}
+	b.clientOption()(options)
+	options.Transport = fakeARMBudgetTransport{}
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &options.ClientOptions)
+
+	send := func(ctx context.Context) {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, "https://management.azure.com/subscriptions/sub")
+		assert.NoError(t, err)
+		_, err = pl.Do(req)
+		assert.NoError(t, err)
+	}
+	send(withARMCaller(context.Background(), armCallerService))
+	send(withARMCaller(context.Background(), armCallerService))
+	send(context.Background())
+	assert.Equal(t, map[string]int{armCallerService: 2, armCallerOther: 1}, b.counts)
+
+	// the nil budget never throttles
+	var nilBudget *armRequestBudget
+	assert.True(t, nilBudget.allowBackground())
+	assert.Nil(t, newARMRequestBudget(nil))
+}
+
+func TestARMRequestBudgetAllowBackground(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+	b := newARMRequestBudget(map[string]int{armCallerService: 16, armCallerBackground: 4})
+	b.now = func() time.Time { return now }
+
+	// the background budget is consumed
+	for i := 0; i < 3; i++ {
+		b.count(armCallerBackground)
+	}
+	assert.True(t, b.allowBackground())
+	b.count(armCallerBackground)
+	assert.False(t, b.allowBackground())
+
+	// the budgets are renewed every hour
+	now = now.Add(time.Hour)
+	assert.True(t, b.allowBackground())
+
+	// 90% of the total budget is consumed by the other callers
+	for i := 0; i < 17; i++ {
+		b.count(armCallerService)
+	}
+	assert.True(t, b.allowBackground())
+	b.count(armCallerNode)
+	assert.False(t, b.allowBackground())
+	assert.True(t, b.exceeded[armCallerService])
+
+	// the background budget of 0 is unlimited
+	b = newARMRequestBudget(map[string]int{armCallerBackground: 0})
+	b.count(armCallerBackground)
+	assert.True(t, b.allowBackground())
+}
+
+func TestReconcileRouteDriftsSkippedByBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.armRequestBudget = newARMRequestBudget(map[string]int{armCallerBackground: 1})
+	az.armRequestBudget.count(armCallerBackground)
+	// no route tables are listed
+	az.reconcileRouteDrifts(context.Background())
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// The faults injected into the ARM requests.
+const (
+	armFaultLatency     = "latency"
+	armFaultThrottle    = "throttle"
+	armFaultServerError = "server_error"
+)
+
+var (
+	armInjectedFaultCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "arm_injected_faults_total",
+			Help:           "Number of faults injected into the ARM requests for testing",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"fault"},
+	)
+
+	registerARMFaultInjectionMetricsOnce sync.Once
+
+	// armFaultInjection is the fault injector of the clouds created afterwards, it is nil unless the
+	// fault injection is configured.
+	armFaultInjection     *armFaultInjector
+	armFaultInjectionLock sync.Mutex
+)
+
+func registerARMFaultInjectionMetrics() {
+	registerARMFaultInjectionMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(armInjectedFaultCount)
+	})
+}
+
+// armFaultInjectionConfig is the config of the faults injected into the ARM requests, in YAML or JSON.
+type armFaultInjectionConfig struct {
+	// Rules are matched in order, the first rule matching a request applies.
+	Rules []armFaultInjectionRule `json:"rules"`
+}
+
+// armFaultInjectionRule injects the faults into the ARM requests matching it.
+type armFaultInjectionRule struct {
+	// Methods are the HTTP methods of the requests, any method if empty.
+	Methods []string `json:"methods,omitempty"`
+	// ResourceTypes are the types of the resources of the requests, e.g. loadBalancers or
+	// virtualMachineScaleSets/virtualMachines, compared case-insensitively. Any type if empty.
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	// Latency delays the requests.
+	Latency metav1.Duration `json:"latency,omitempty"`
+	// ThrottleRatio is the ratio of the requests rejected with 429 Too Many Requests.
+	ThrottleRatio float64 `json:"throttleRatio,omitempty"`
+	// RetryAfter is the Retry-After of the throttled requests, 1s by default.
+	RetryAfter metav1.Duration `json:"retryAfter,omitempty"`
+	// ServerErrorRatio is the ratio of the requests failed with ServerErrorStatusCode.
+	ServerErrorRatio float64 `json:"serverErrorRatio,omitempty"`
+	// ServerErrorStatusCode is the status code of the server errors, 500 by default.
+	ServerErrorStatusCode int `json:"serverErrorStatusCode,omitempty"`
+}
+
+func (r *armFaultInjectionRule) validate() error {
+	if r.Latency.Duration < 0 || r.RetryAfter.Duration < 0 {
+		return fmt.Errorf("latency and retryAfter must not be negative")
+	}
+	if r.ThrottleRatio < 0 || r.ServerErrorRatio < 0 || r.ThrottleRatio+r.ServerErrorRatio > 1 {
+		return fmt.Errorf("throttleRatio and serverErrorRatio must not be negative, and their sum must not exceed 1")
+	}
+	if r.ServerErrorStatusCode != 0 && (r.ServerErrorStatusCode < http.StatusInternalServerError || r.ServerErrorStatusCode > 599) {
+		return fmt.Errorf("serverErrorStatusCode %d is not a 5xx status code", r.ServerErrorStatusCode)
+	}
+	return nil
+}
+
+func (r *armFaultInjectionRule) matches(method, resourceType string) bool {
+	methodMatched := len(r.Methods) == 0
+	for _, m := range r.Methods {
+		methodMatched = methodMatched || strings.EqualFold(m, method)
+	}
+	typeMatched := len(r.ResourceTypes) == 0
+	for _, t := range r.ResourceTypes {
+		typeMatched = typeMatched || strings.EqualFold(t, resourceType)
+	}
+	return methodMatched && typeMatched
+}
+
+// SetARMFaultInjectionConfigFile loads the config of the latency and the errors injected into the ARM
+// requests of the clouds created afterwards, which reproduces the throttling and the failures of ARM
+// for testing. An empty path disables the fault injection. It must never be used in production.
+func SetARMFaultInjectionConfigFile(path string) error {
+	var injector *armFaultInjector
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the ARM fault injection config: %w", err)
+		}
+		var config armFaultInjectionConfig
+		if err := yaml.UnmarshalStrict(content, &config); err != nil {
+			return fmt.Errorf("failed to parse the ARM fault injection config %s: %w", path, err)
+		}
+		if injector, err = newARMFaultInjector(config); err != nil {
+			return fmt.Errorf("invalid ARM fault injection config %s: %w", path, err)
+		}
+	}
+
+	armFaultInjectionLock.Lock()
+	defer armFaultInjectionLock.Unlock()
+	armFaultInjection = injector
+	return nil
+}
+
+func getARMFaultInjector() *armFaultInjector {
+	armFaultInjectionLock.Lock()
+	defer armFaultInjectionLock.Unlock()
+	return armFaultInjection
+}
+
+// armFaultInjector is a pipeline policy of the ARM clients injecting the faults into the requests by the
+// rules of the config. It is a per-retry policy in front of the transport, so every attempt may fail
+// like it does when ARM is throttling or failing, and the retries and the backoffs of the clients and
+// the reconciliations are exercised.
+type armFaultInjector struct {
+	rules []armFaultInjectionRule
+
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+func newARMFaultInjector(config armFaultInjectionConfig) (*armFaultInjector, error) {
+	for i := range config.Rules {
+		if err := config.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	registerARMFaultInjectionMetrics()
+	return &armFaultInjector{
+		rules: config.Rules,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+	}, nil
+}
+
+// clientOption returns the client option injecting the faults into the requests of the ARM clients.
+func (f *armFaultInjector) clientOption() func(option *arm.ClientOptions) {
+	return func(option *arm.ClientOptions) {
+		option.PerRetryPolicies = append(option.PerRetryPolicies, f)
+	}
+}
+
+// Do implements policy.Policy.
+func (f *armFaultInjector) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	rule := f.match(raw.Method, armRequestResourceType(raw.URL.Path))
+	if rule == nil {
+		return req.Next()
+	}
+
+	if rule.Latency.Duration > 0 {
+		armInjectedFaultCount.WithLabelValues(armFaultLatency).Inc()
+		timer := time.NewTimer(rule.Latency.Duration)
+		select {
+		case <-raw.Context().Done():
+			timer.Stop()
+			return nil, raw.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	f.lock.Lock()
+	p := f.rand.Float64()
+	f.lock.Unlock()
+	switch {
+	case p < rule.ThrottleRatio:
+		armInjectedFaultCount.WithLabelValues(armFaultThrottle).Inc()
+		retryAfter := rule.RetryAfter.Duration
+		if retryAfter == 0 {
+			retryAfter = time.Second
+		}
+		resp := newARMFaultResponse(raw, http.StatusTooManyRequests, "TooManyRequests")
+		resp.Header.Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		klog.V(4).Infof("armFaultInjector: throttling %s %s", raw.Method, raw.URL.Path)
+		return resp, nil
+	case p < rule.ThrottleRatio+rule.ServerErrorRatio:
+		armInjectedFaultCount.WithLabelValues(armFaultServerError).Inc()
+		statusCode := rule.ServerErrorStatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		klog.V(4).Infof("armFaultInjector: failing %s %s with %d", raw.Method, raw.URL.Path, statusCode)
+		return newARMFaultResponse(raw, statusCode, "InternalServerError"), nil
+	}
+	return req.Next()
+}
+
+func (f *armFaultInjector) match(method, resourceType string) *armFaultInjectionRule {
+	for i := range f.rules {
+		if f.rules[i].matches(method, resourceType) {
+			return &f.rules[i]
+		}
+	}
+	return nil
+}
+
+func newARMFaultResponse(req *http.Request, statusCode int, code string) *http.Response {
+	body := fmt.Sprintf(`{"error":{"code":%q,"message":"the fault is injected for testing"}}`, code)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("x-ms-error-code", code)
+	return &http.Response{
+		StatusCode:    statusCode,
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// armRequestResourceType returns the type of the resource of the ARM request path without the provider
+// namespace, e.g. loadBalancers for /subscriptions/{id}/resourceGroups/{rg}/providers/Microsoft.Network/loadBalancers/{name}.
+func armRequestResourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if !strings.EqualFold(segment, "providers") {
+			continue
+		}
+		var types []string
+		for j := i + 2; j < len(segments); j += 2 {
+			types = append(types, segments[j])
+		}
+		return strings.Join(types, "/")
+	}
+	if len(segments) > 2 {
+		return segments[2]
+	}
+	return ""
+}
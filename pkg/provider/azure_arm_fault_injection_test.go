@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testARMFaultInjectionURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network"
+
+func TestSetARMFaultInjectionConfigFile(t *testing.T) {
+	defer func() { assert.NoError(t, SetARMFaultInjectionConfigFile("")) }()
+	dir := t.TempDir()
+	writeConfig := func(content string) string {
+		path := filepath.Join(dir, "faults.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	assert.NoError(t, SetARMFaultInjectionConfigFile(writeConfig(`
+rules:
+- methods: [PUT]
+  resourceTypes: [loadBalancers]
+  latency: 100ms
+  throttleRatio: 0.5
+  retryAfter: 10s
+`)))
+	injector := getARMFaultInjector()
+	if assert.NotNil(t, injector) && assert.Len(t, injector.rules, 1) {
+		assert.Equal(t, 100*time.Millisecond, injector.rules[0].Latency.Duration)
+		assert.Equal(t, 10*time.Second, injector.rules[0].RetryAfter.Duration)
+	}
+
+	assert.ErrorContains(t, SetARMFaultInjectionConfigFile(writeConfig(`
+rules:
+- throttleRatio: 0.5
+  serverErrorRatio: 0.6
+`)), "rule 0")
+	assert.ErrorContains(t, SetARMFaultInjectionConfigFile(writeConfig(`
+rules:
+- serverErrorRatio: 1
+  serverErrorStatusCode: 404
+`)), "not a 5xx status code")
+	assert.Error(t, SetARMFaultInjectionConfigFile(writeConfig(`
+rules:
+- throttle: 1
+`)))
+	assert.Error(t, SetARMFaultInjectionConfigFile(filepath.Join(dir, "not-found.yaml")))
+	// the injector is kept if the config is invalid
+	assert.Equal(t, injector, getARMFaultInjector())
+
+	assert.NoError(t, SetARMFaultInjectionConfigFile(""))
+	assert.Nil(t, getARMFaultInjector())
+}
+
+func TestARMFaultInjector(t *testing.T) {
+	injector, err := newARMFaultInjector(armFaultInjectionConfig{
+		Rules: []armFaultInjectionRule{
+			{
+				Methods:       []string{http.MethodPut},
+				ResourceTypes: []string{"loadBalancers"},
+				ThrottleRatio: 1,
+				RetryAfter:    metav1.Duration{Duration: 1500 * time.Millisecond},
+			},
+			{
+				ResourceTypes:         []string{"loadBalancers", "publicIPAddresses"},
+				ServerErrorRatio:      1,
+				ServerErrorStatusCode: http.StatusServiceUnavailable,
+			},
+			{
+				ResourceTypes: []string{"routeTables/routes"},
+				Latency:       metav1.Duration{Duration: time.Hour},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	options := &arm.ClientOptions{ClientOptions: policy.ClientOptions{Retry: policy.RetryOptions{MaxRetries: -1}}}
+	injector.clientOption()(options)
+	options.Transport = fakeARMBudgetTransport{}
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &options.ClientOptions)
+
+	send := func(ctx context.Context, method, path string) (*http.Response, error) {
+		req, err := runtime.NewRequest(ctx, method, testARMFaultInjectionURL+path)
+		assert.NoError(t, err)
+		return pl.Do(req)
+	}
+
+	resp, err := send(context.Background(), http.MethodPut, "/loadBalancers/lb")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+	// the first matching rule applies
+	resp, err = send(context.Background(), http.MethodGet, "/loadBalancers/lb")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "InternalServerError", resp.Header.Get("x-ms-error-code"))
+
+	resp, err = send(context.Background(), http.MethodGet, "/networkSecurityGroups/nsg")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the latency is cut short by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = send(ctx, http.MethodPut, "/routeTables/rt/routes/route")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestARMRequestResourceType(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb":                             "loadBalancers",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers":                                "loadBalancers",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines": "virtualMachineScaleSets/virtualMachines",
+		"/subscriptions/sub/providers/Microsoft.Network/locations/eastus/operations/op":                                 "locations/operations",
+		"/subscriptions/sub/resourceGroups/rg":                                                                          "resourceGroups",
+		"/subscriptions/sub":                                                                                            "",
+	} {
+		assert.Equal(t, expected, armRequestResourceType(path), path)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+// compileBackendPoolNICNamePattern validates the selection of the NIC joining the backend pools, and returns the
+// compiled loadBalancerBackendPoolNICNamePattern, or nil if it is not set.
+func compileBackendPoolNICNamePattern(cfg *config.Config) (*regexp.Regexp, error) {
+	if cfg.LoadBalancerBackendPoolNICNamePattern == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(cfg.LoadBalancerBackendPoolNICNamePattern)
+	if err != nil {
+		return nil, fmt.Errorf("loadBalancerBackendPoolNICNamePattern %q is not a valid regular expression: %w", cfg.LoadBalancerBackendPoolNICNamePattern, err)
+	}
+	return pattern, nil
+}
+
+// getBackendPoolInterfaceID returns the ID of the NIC of the VM which joins the backend pools, which is the
+// primary NIC unless loadBalancerBackendPoolNICNamePattern is set.
+func (az *Cloud) getBackendPoolInterfaceID(machine *armcompute.VirtualMachine) (string, error) {
+	if az.backendPoolNICNamePattern == nil || len(machine.Properties.NetworkProfile.NetworkInterfaces) == 1 {
+		return getPrimaryInterfaceID(machine)
+	}
+
+	for _, ref := range machine.Properties.NetworkProfile.NetworkInterfaces {
+		if ref == nil || ref.ID == nil {
+			continue
+		}
+		nicName, err := getLastSegment(*ref.ID, "/")
+		if err != nil {
+			return "", err
+		}
+		if az.backendPoolNICNamePattern.MatchString(nicName) {
+			return *ref.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to find a nic matching %q for the vm. vmname=%q", az.backendPoolNICNamePattern, ptr.Deref(machine.Name, ""))
+}
+
+// getBackendPoolNetworkInterfaceConfiguration returns the network interface configuration of the VMSS VM or VMSS
+// which joins the backend pools, which is the primary one unless loadBalancerBackendPoolNICNamePattern is set.
+func (az *Cloud) getBackendPoolNetworkInterfaceConfiguration(networkConfigurations []*armcompute.VirtualMachineScaleSetNetworkConfiguration, resource string) (*armcompute.VirtualMachineScaleSetNetworkConfiguration, error) {
+	if az.backendPoolNICNamePattern == nil || len(networkConfigurations) == 1 {
+		return getPrimaryNetworkInterfaceConfiguration(networkConfigurations, resource)
+	}
+
+	for _, networkConfig := range networkConfigurations {
+		if networkConfig != nil && az.backendPoolNICNamePattern.MatchString(ptr.Deref(networkConfig.Name, "")) {
+			return networkConfig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find a network configuration matching %q for the VMSS VM or VMSS %q", az.backendPoolNICNamePattern, resource)
+}
+
+// removeBackendPoolFromPrimaryInterface removes the backend pool from the primary NIC of the VM if another NIC of
+// it joins the backend pools, since the primary NIC may have joined them before loadBalancerBackendPoolNICNamePattern
+// was set. getPrimaryInterface is only called in that case.
+func (az *Cloud) removeBackendPoolFromPrimaryInterface(
+	ctx context.Context,
+	service *v1.Service,
+	nic *armnetwork.Interface,
+	backendPoolID string,
+	getPrimaryInterface func() (*armnetwork.Interface, error),
+) error {
+	if az.backendPoolNICNamePattern == nil || nic.Properties == nil || ptr.Deref(nic.Properties.Primary, false) {
+		return nil
+	}
+	primaryNIC, err := getPrimaryInterface()
+	if err != nil {
+		return err
+	}
+	if primaryNIC.Properties == nil || strings.EqualFold(ptr.Deref(primaryNIC.ID, ""), ptr.Deref(nic.ID, "")) {
+		return nil
+	}
+
+	updated := false
+	for _, ipConfig := range primaryNIC.Properties.IPConfigurations {
+		if ipConfig == nil || ipConfig.Properties == nil {
+			continue
+		}
+		pools := ipConfig.Properties.LoadBalancerBackendAddressPools
+		newPools := make([]*armnetwork.BackendAddressPool, 0, len(pools))
+		for _, pool := range pools {
+			if pool != nil && !strings.EqualFold(ptr.Deref(pool.ID, ""), backendPoolID) {
+				newPools = append(newPools, pool)
+			}
+		}
+		if len(newPools) != len(pools) {
+			ipConfig.Properties.LoadBalancerBackendAddressPools = newPools
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+
+	klog.V(3).Infof("nicupdate(%s): nic(%s) - removing the backend pool %s from the primary nic", getServiceName(service), ptr.Deref(primaryNIC.Name, ""), backendPoolID)
+	return az.CreateOrUpdateInterface(ctx, service, primaryNIC)
+}
+
+// removeBackendPoolFromOtherNetworkConfigurations removes the backend pool from the network interface configurations
+// other than networkConfig which joins the backend pools, e.g. the primary one which joined them before
+// loadBalancerBackendPoolNICNamePattern was set. It returns if any network interface configuration is changed.
+func (az *Cloud) removeBackendPoolFromOtherNetworkConfigurations(
+	networkConfigurations []*armcompute.VirtualMachineScaleSetNetworkConfiguration,
+	networkConfig *armcompute.VirtualMachineScaleSetNetworkConfiguration,
+	backendPoolID string,
+) bool {
+	if az.backendPoolNICNamePattern == nil {
+		return false
+	}
+
+	updated := false
+	for _, other := range networkConfigurations {
+		if other == nil || other == networkConfig || other.Properties == nil {
+			continue
+		}
+		for _, ipConfig := range other.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.Properties == nil {
+				continue
+			}
+			pools := ipConfig.Properties.LoadBalancerBackendAddressPools
+			newPools := make([]*armcompute.SubResource, 0, len(pools))
+			for _, pool := range pools {
+				if pool != nil && !strings.EqualFold(ptr.Deref(pool.ID, ""), backendPoolID) {
+					newPools = append(newPools, pool)
+				}
+			}
+			if len(newPools) != len(pools) {
+				ipConfig.Properties.LoadBalancerBackendAddressPools = newPools
+				updated = true
+			}
+		}
+	}
+	return updated
+}
+
+// getBackendPoolIPConfig returns the IP configuration of the NIC which joins the backend pool of the IP family,
+// which is the primary one of IPv4 unless loadBalancerBackendPoolIPConfigurationName is set.
+func (az *Cloud) getBackendPoolIPConfig(nic *armnetwork.Interface, ipv6 bool) (*armnetwork.InterfaceIPConfiguration, error) {
+	if ipv6 {
+		return getIPConfigByIPFamily(nic, ipv6)
+	}
+	if az.LoadBalancerBackendPoolIPConfigurationName != "" && nic.Properties.IPConfigurations != nil && len(nic.Properties.IPConfigurations) > 1 {
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			if strings.EqualFold(ptr.Deref(ipConfig.Name, ""), az.LoadBalancerBackendPoolIPConfigurationName) {
+				return ipConfig, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to find the ipconfig %q. nicname=%q", az.LoadBalancerBackendPoolIPConfigurationName, ptr.Deref(nic.Name, ""))
+	}
+	if az.ipv6DualStackEnabled {
+		return getIPConfigByIPFamily(nic, ipv6)
+	}
+	return getPrimaryIPConfig(nic)
+}
+
+// isBackendPoolIPConfig returns if the IPv4 IP configuration of the NIC is the one joining the backend pools.
+func (az *Cloud) isBackendPoolIPConfig(nic *armnetwork.Interface, ipConfig *armnetwork.InterfaceIPConfiguration) bool {
+	if az.LoadBalancerBackendPoolIPConfigurationName != "" && len(nic.Properties.IPConfigurations) > 1 {
+		return strings.EqualFold(ptr.Deref(ipConfig.Name, ""), az.LoadBalancerBackendPoolIPConfigurationName)
+	}
+	return ipConfig.Properties != nil && ptr.Deref(ipConfig.Properties.Primary, false)
+}
+
+// getBackendPoolIPConfigFromVMSSNetworkConfig returns the IP configuration of the network interface configuration
+// which joins the backend pool, which is the primary one of IPv4 unless loadBalancerBackendPoolIPConfigurationName is set.
+func (az *Cloud) getBackendPoolIPConfigFromVMSSNetworkConfig(config *armcompute.VirtualMachineScaleSetNetworkConfiguration, backendPoolID, resource string) (*armcompute.VirtualMachineScaleSetIPConfiguration, error) {
+	ipConfigurations := config.Properties.IPConfigurations
+	if az.LoadBalancerBackendPoolIPConfigurationName == "" || isBackendPoolIPv6(backendPoolID) || len(ipConfigurations) == 1 {
+		return getPrimaryIPConfigFromVMSSNetworkConfig(config, backendPoolID, resource)
+	}
+
+	for _, ipConfig := range ipConfigurations {
+		if ipConfig != nil && strings.EqualFold(ptr.Deref(ipConfig.Name, ""), az.LoadBalancerBackendPoolIPConfigurationName) {
+			return ipConfig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find the IP configuration %q for the VMSS VM or VMSS %q", az.LoadBalancerBackendPoolIPConfigurationName, resource)
+}
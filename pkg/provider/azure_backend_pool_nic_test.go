@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/interfaceclient/mock_interfaceclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+func TestCompileBackendPoolNICNamePattern(t *testing.T) {
+	pattern, err := compileBackendPoolNICNamePattern(&config.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, pattern)
+
+	pattern, err = compileBackendPoolNICNamePattern(&config.Config{LoadBalancerBackendPoolNICNamePattern: "-lb-nic$"})
+	assert.NoError(t, err)
+	assert.True(t, pattern.MatchString("vm-lb-nic"))
+
+	_, err = compileBackendPoolNICNamePattern(&config.Config{LoadBalancerBackendPoolNICNamePattern: "("})
+	assert.ErrorContains(t, err, "loadBalancerBackendPoolNICNamePattern \"(\" is not a valid regular expression")
+}
+
+func TestGetBackendPoolInterfaceID(t *testing.T) {
+	nicID := func(name string) string {
+		return "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/" + name
+	}
+	machine := &armcompute.VirtualMachine{
+		Name: ptr.To("vm"),
+		Properties: &armcompute.VirtualMachineProperties{
+			NetworkProfile: &armcompute.NetworkProfile{
+				NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
+					{ID: ptr.To(nicID("vm-dpdk")), Properties: &armcompute.NetworkInterfaceReferenceProperties{Primary: ptr.To(true)}},
+					{ID: ptr.To(nicID("vm-lb"))},
+				},
+			},
+		},
+	}
+	singleNICMachine := &armcompute.VirtualMachine{
+		Name: ptr.To("vm"),
+		Properties: &armcompute.VirtualMachineProperties{
+			NetworkProfile: &armcompute.NetworkProfile{
+				NetworkInterfaces: []*armcompute.NetworkInterfaceReference{{ID: ptr.To(nicID("vm-nic"))}},
+			},
+		},
+	}
+
+	az := &Cloud{}
+	id, err := az.getBackendPoolInterfaceID(machine)
+	assert.NoError(t, err)
+	assert.Equal(t, nicID("vm-dpdk"), id)
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("-lb$")
+	id, err = az.getBackendPoolInterfaceID(machine)
+	assert.NoError(t, err)
+	assert.Equal(t, nicID("vm-lb"), id)
+
+	// the single NIC is used regardless of the pattern
+	id, err = az.getBackendPoolInterfaceID(singleNICMachine)
+	assert.NoError(t, err)
+	assert.Equal(t, nicID("vm-nic"), id)
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("-storage$")
+	_, err = az.getBackendPoolInterfaceID(machine)
+	assert.EqualError(t, err, "failed to find a nic matching \"-storage$\" for the vm. vmname=\"vm\"")
+}
+
+func TestGetBackendPoolNetworkInterfaceConfiguration(t *testing.T) {
+	networkConfigs := []*armcompute.VirtualMachineScaleSetNetworkConfiguration{
+		{Name: ptr.To("dpdk"), Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{Primary: ptr.To(true)}},
+		{Name: ptr.To("lb"), Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{Primary: ptr.To(false)}},
+	}
+
+	az := &Cloud{}
+	networkConfig, err := az.getBackendPoolNetworkInterfaceConfiguration(networkConfigs, "vmss")
+	assert.NoError(t, err)
+	assert.Equal(t, networkConfigs[0], networkConfig)
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("^lb$")
+	networkConfig, err = az.getBackendPoolNetworkInterfaceConfiguration(networkConfigs, "vmss")
+	assert.NoError(t, err)
+	assert.Equal(t, networkConfigs[1], networkConfig)
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("^storage$")
+	_, err = az.getBackendPoolNetworkInterfaceConfiguration(networkConfigs, "vmss")
+	assert.EqualError(t, err, "failed to find a network configuration matching \"^storage$\" for the VMSS VM or VMSS \"vmss\"")
+}
+
+func TestGetBackendPoolIPConfig(t *testing.T) {
+	nic := &armnetwork.Interface{
+		Name: ptr.To("nic"),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{
+					Name: ptr.To("ipconfig1"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                 ptr.To(true),
+						PrivateIPAddress:        ptr.To("10.0.0.4"),
+						PrivateIPAddressVersion: ptr.To(armnetwork.IPVersionIPv4),
+					},
+				},
+				{
+					Name: ptr.To("lb"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                 ptr.To(false),
+						PrivateIPAddress:        ptr.To("10.0.0.5"),
+						PrivateIPAddressVersion: ptr.To(armnetwork.IPVersionIPv4),
+					},
+				},
+				{
+					Name: ptr.To("ipv6"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                 ptr.To(false),
+						PrivateIPAddress:        ptr.To("fd00::4"),
+						PrivateIPAddressVersion: ptr.To(armnetwork.IPVersionIPv6),
+					},
+				},
+			},
+		},
+	}
+	ipConfigs := nic.Properties.IPConfigurations
+
+	az := &Cloud{}
+	ipConfig, err := az.getBackendPoolIPConfig(nic, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ipConfigs[0], ipConfig)
+	assert.True(t, az.isBackendPoolIPConfig(nic, ipConfigs[0]))
+	assert.False(t, az.isBackendPoolIPConfig(nic, ipConfigs[1]))
+
+	az.LoadBalancerBackendPoolIPConfigurationName = "lb"
+	ipConfig, err = az.getBackendPoolIPConfig(nic, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ipConfigs[1], ipConfig)
+	assert.False(t, az.isBackendPoolIPConfig(nic, ipConfigs[0]))
+	assert.True(t, az.isBackendPoolIPConfig(nic, ipConfigs[1]))
+
+	// the IPv6 backend pools use the IPv6 IP configuration
+	ipConfig, err = az.getBackendPoolIPConfig(nic, true)
+	assert.NoError(t, err)
+	assert.Equal(t, ipConfigs[2], ipConfig)
+
+	az.LoadBalancerBackendPoolIPConfigurationName = "storage"
+	_, err = az.getBackendPoolIPConfig(nic, false)
+	assert.EqualError(t, err, "failed to find the ipconfig \"storage\". nicname=\"nic\"")
+}
+
+func TestGetBackendPoolIPConfigFromVMSSNetworkConfig(t *testing.T) {
+	backendPoolID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/backendAddressPools/kubernetes"
+	networkConfig := &armcompute.VirtualMachineScaleSetNetworkConfiguration{
+		Name: ptr.To("lb"),
+		Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+			IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
+				{Name: ptr.To("ipconfig1"), Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{Primary: ptr.To(true)}},
+				{Name: ptr.To("lb"), Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{Primary: ptr.To(false)}},
+			},
+		},
+	}
+	ipConfigs := networkConfig.Properties.IPConfigurations
+
+	az := &Cloud{}
+	ipConfig, err := az.getBackendPoolIPConfigFromVMSSNetworkConfig(networkConfig, backendPoolID, "vmss")
+	assert.NoError(t, err)
+	assert.Equal(t, ipConfigs[0], ipConfig)
+
+	az.LoadBalancerBackendPoolIPConfigurationName = "lb"
+	ipConfig, err = az.getBackendPoolIPConfigFromVMSSNetworkConfig(networkConfig, backendPoolID, "vmss")
+	assert.NoError(t, err)
+	assert.Equal(t, ipConfigs[1], ipConfig)
+
+	az.LoadBalancerBackendPoolIPConfigurationName = "storage"
+	_, err = az.getBackendPoolIPConfigFromVMSSNetworkConfig(networkConfig, backendPoolID, "vmss")
+	assert.EqualError(t, err, "failed to find the IP configuration \"storage\" for the VMSS VM or VMSS \"vmss\"")
+}
+
+func TestRemoveBackendPoolFromPrimaryInterface(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backendPoolID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/backendAddressPools/kubernetes"
+	otherPoolID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/backendAddressPools/other"
+	service := &v1.Service{}
+	lbNIC := &armnetwork.Interface{
+		ID:         ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/vm-lb"),
+		Name:       ptr.To("vm-lb"),
+		Properties: &armnetwork.InterfacePropertiesFormat{Primary: ptr.To(false)},
+	}
+	newPrimaryNIC := func() *armnetwork.Interface {
+		return &armnetwork.Interface{
+			ID:   ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/vm-dpdk"),
+			Name: ptr.To("vm-dpdk"),
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				Primary: ptr.To(true),
+				IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+					{
+						Name: ptr.To("ipconfig1"),
+						Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{
+								{ID: ptr.To(backendPoolID)},
+								{ID: ptr.To(otherPoolID)},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	getPrimaryInterface := func() (*armnetwork.Interface, error) {
+		return newPrimaryNIC(), nil
+	}
+
+	az := GetTestCloud(ctrl)
+	interfaceClient := az.NetworkClientFactory.GetInterfaceClient().(*mock_interfaceclient.MockInterface)
+
+	// the primary NIC is not read without loadBalancerBackendPoolNICNamePattern
+	assert.NoError(t, az.removeBackendPoolFromPrimaryInterface(context.Background(), service, lbNIC, backendPoolID, nil))
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("-lb$")
+	interfaceClient.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), "vm-dpdk", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, nic armnetwork.Interface) (*armnetwork.Interface, error) {
+			pools := nic.Properties.IPConfigurations[0].Properties.LoadBalancerBackendAddressPools
+			assert.Len(t, pools, 1)
+			assert.Equal(t, otherPoolID, *pools[0].ID)
+			return &nic, nil
+		}).Times(1)
+	assert.NoError(t, az.removeBackendPoolFromPrimaryInterface(context.Background(), service, lbNIC, backendPoolID, getPrimaryInterface))
+
+	// the primary NIC not in the backend pool is not updated
+	assert.NoError(t, az.removeBackendPoolFromPrimaryInterface(context.Background(), service, lbNIC, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/backendAddressPools/another", getPrimaryInterface))
+
+	// nothing is removed when the primary NIC itself joins the backend pool
+	assert.NoError(t, az.removeBackendPoolFromPrimaryInterface(context.Background(), service, newPrimaryNIC(), backendPoolID, nil))
+}
+
+func TestRemoveBackendPoolFromOtherNetworkConfigurations(t *testing.T) {
+	backendPoolID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/backendAddressPools/kubernetes"
+	newNetworkConfig := func(name string, primary bool) *armcompute.VirtualMachineScaleSetNetworkConfiguration {
+		return &armcompute.VirtualMachineScaleSetNetworkConfiguration{
+			Name: ptr.To(name),
+			Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+				Primary: ptr.To(primary),
+				IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
+					{
+						Name: ptr.To("ipconfig1"),
+						Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+							LoadBalancerBackendAddressPools: []*armcompute.SubResource{{ID: ptr.To(backendPoolID)}},
+						},
+					},
+				},
+			},
+		}
+	}
+	networkConfigs := []*armcompute.VirtualMachineScaleSetNetworkConfiguration{newNetworkConfig("dpdk", true), newNetworkConfig("lb", false)}
+
+	az := &Cloud{}
+	assert.False(t, az.removeBackendPoolFromOtherNetworkConfigurations(networkConfigs, networkConfigs[1], backendPoolID))
+	assert.Len(t, networkConfigs[0].Properties.IPConfigurations[0].Properties.LoadBalancerBackendAddressPools, 1)
+
+	az.backendPoolNICNamePattern = regexp.MustCompile("^lb$")
+	assert.True(t, az.removeBackendPoolFromOtherNetworkConfigurations(networkConfigs, networkConfigs[1], backendPoolID))
+	assert.Empty(t, networkConfigs[0].Properties.IPConfigurations[0].Properties.LoadBalancerBackendAddressPools)
+	assert.Len(t, networkConfigs[1].Properties.IPConfigurations[0].Properties.LoadBalancerBackendAddressPools, 1)
+
+	// nothing left to remove
+	assert.False(t, az.removeBackendPoolFromOtherNetworkConfigurations(networkConfigs, networkConfigs[1], backendPoolID))
+}
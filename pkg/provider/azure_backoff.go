@@ -17,10 +17,15 @@ limitations under the License.
 package provider
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
 )
 
 var (
@@ -47,3 +52,18 @@ func (az *Cloud) Event(obj runtime.Object, eventType, reason, message string) {
 		az.eventRecorder.Event(obj, eventType, reason, message)
 	}
 }
+
+// getAzureErrorEventMessage returns the event message of a failed Azure operation.
+// The ARM error code, correlation ID and request ID are included when available so the failure
+// can be traced without the controller logs.
+func getAzureErrorEventMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) || rerr == nil {
+		return err.Error()
+	}
+	correlationID, requestID := errutils.ARMRequestIDs(err)
+	return fmt.Sprintf("ErrorCode: %s, CorrelationID: %s, RequestID: %s, Message: %s", rerr.ErrorCode, correlationID, requestID, err.Error())
+}
@@ -17,10 +17,15 @@ limitations under the License.
 package provider
 
 import (
+	"errors"
+	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -37,3 +42,21 @@ func TestRequestBackoff(t *testing.T) {
 	assert.Equal(t, wait.Backoff{Steps: 3}, backoff)
 
 }
+
+func TestGetAzureErrorEventMessage(t *testing.T) {
+	assert.Equal(t, "", getAzureErrorEventMessage(nil))
+	assert.Equal(t, "plain error", getAzureErrorEventMessage(errors.New("plain error")))
+
+	header := http.Header{}
+	header.Set(consts.CorrelationRequestIDHeaderKey, "correlation-id")
+	header.Set(consts.RequestIDHeaderKey, "request-id")
+	rerr := &azcore.ResponseError{
+		ErrorCode:   "InvalidResourceReference",
+		StatusCode:  http.StatusBadRequest,
+		RawResponse: &http.Response{Header: header},
+	}
+	message := getAzureErrorEventMessage(rerr)
+	assert.Contains(t, message, "ErrorCode: InvalidResourceReference")
+	assert.Contains(t, message, "CorrelationID: correlation-id")
+	assert.Contains(t, message, "RequestID: request-id")
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"golang.org/x/sync/errgroup"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	// maxCacheSnapshotAge is the age of the snapshots not loaded anymore, as most of the cached
+	// resources would be refreshed right away.
+	maxCacheSnapshotAge = time.Hour
+	// cacheSnapshotConfigMapKey is the key of the snapshot in the binary data of the ConfigMap.
+	cacheSnapshotConfigMapKey = "snapshot"
+	// maxCacheSnapshotConfigMapSize is the size limit of the snapshots saved to the ConfigMap,
+	// below the 1MiB limit of the objects in etcd.
+	maxCacheSnapshotConfigMapSize = 1000 * 1024
+	// cacheSnapshotSaveTimeout is the timeout of saving the last snapshot when the leader stops.
+	cacheSnapshotSaveTimeout = 10 * time.Second
+)
+
+// cacheSnapshot is the content of the VM, VMSS, VMSS VM and load balancer caches saved by the
+// leader, so a newly elected leader doesn't start with cold caches.
+type cacheSnapshot struct {
+	CreatedOn       time.Time                             `json:"createdOn"`
+	VirtualMachines map[string]*armcompute.VirtualMachine `json:"virtualMachines,omitempty"`
+	LoadBalancers   map[string]*armnetwork.LoadBalancer   `json:"loadBalancers,omitempty"`
+	// ScaleSets are the entries of the VMSS cache by the names of the scale sets.
+	ScaleSets map[string]*VMSSEntry `json:"scaleSets,omitempty"`
+	// ScaleSetVMs are the entries of the VMSS VM cache by the cache keys of the scale sets and
+	// the node names.
+	ScaleSetVMs map[string]map[string]*VMSSVirtualMachineEntry `json:"scaleSetVMs,omitempty"`
+}
+
+func (s *cacheSnapshot) isEmpty() bool {
+	return len(s.VirtualMachines) == 0 && len(s.LoadBalancers) == 0 && len(s.ScaleSets) == 0 && len(s.ScaleSetVMs) == 0
+}
+
+// cacheSnapshotStore loads and saves the encoded cache snapshots.
+type cacheSnapshotStore interface {
+	// load returns nil if there is no snapshot.
+	load(ctx context.Context) ([]byte, error)
+	save(ctx context.Context, data []byte) error
+}
+
+// fileCacheSnapshotStore keeps the snapshot in a file.
+type fileCacheSnapshotStore struct {
+	path string
+}
+
+func (s *fileCacheSnapshotStore) load(_ context.Context) ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// save replaces the file by renaming, so the readers never see a partially written snapshot.
+func (s *fileCacheSnapshotStore) save(_ context.Context, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// configMapCacheSnapshotStore keeps the snapshot in the binary data of a ConfigMap.
+type configMapCacheSnapshotStore struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+}
+
+func (s *configMapCacheSnapshotStore) load(ctx context.Context) ([]byte, error) {
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return configMap.BinaryData[cacheSnapshotConfigMapKey], nil
+}
+
+func (s *configMapCacheSnapshotStore) save(ctx context.Context, data []byte) error {
+	if len(data) > maxCacheSnapshotConfigMapSize {
+		return fmt.Errorf("the snapshot of %d bytes exceeds the size limit %d of ConfigMap %s/%s", len(data), maxCacheSnapshotConfigMapSize, s.namespace, s.name)
+	}
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+			BinaryData: map[string][]byte{cacheSnapshotConfigMapKey: data},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	configMap = configMap.DeepCopy()
+	if configMap.BinaryData == nil {
+		configMap.BinaryData = map[string][]byte{}
+	}
+	configMap.BinaryData[cacheSnapshotConfigMapKey] = data
+	_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// newCacheSnapshotStore returns the store of the cache snapshots in the config, or nil if the
+// snapshots are disabled.
+func (az *Cloud) newCacheSnapshotStore() (cacheSnapshotStore, error) {
+	if az.DisableAPICallCache {
+		return nil, nil
+	}
+	if az.CacheSnapshotPath != "" {
+		return &fileCacheSnapshotStore{path: az.CacheSnapshotPath}, nil
+	}
+	if az.CacheSnapshotConfigMap != "" {
+		namespace, name, err := cache.SplitMetaNamespaceKey(az.CacheSnapshotConfigMap)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cacheSnapshotConfigMap %q: %w", az.CacheSnapshotConfigMap, err)
+		}
+		if namespace == "" {
+			namespace = metav1.NamespaceSystem
+		}
+		return &configMapCacheSnapshotStore{kubeClient: az.KubeClient, namespace: namespace, name: name}, nil
+	}
+	return nil, nil
+}
+
+// startCacheSnapshots restores the caches from the snapshot in the store, and then saves the
+// snapshot of the caches every interval until the context is done. It is called when the cloud
+// provider is initialized by the leader, before the controllers are started.
+func (az *Cloud) startCacheSnapshots(ctx context.Context) {
+	store, err := az.newCacheSnapshotStore()
+	if err != nil {
+		klog.Errorf("startCacheSnapshots: %v", err)
+		return
+	}
+	if store == nil {
+		return
+	}
+
+	az.loadCacheSnapshot(ctx, store)
+
+	interval := time.Duration(az.CacheSnapshotIntervalInSeconds) * time.Second
+	if interval <= 0 {
+		interval = consts.DefaultCacheSnapshotIntervalInSeconds * time.Second
+	}
+	go func() {
+		klog.V(2).Infof("startCacheSnapshots: saving the snapshot of the caches every %s", interval)
+		_ = wait.PollUntilContextCancel(ctx, interval, false, func(ctx context.Context) (bool, error) {
+			az.saveCacheSnapshot(ctx, store)
+			return false, nil
+		})
+		// save the latest caches for the next leader
+		saveCtx, cancel := context.WithTimeout(context.Background(), cacheSnapshotSaveTimeout)
+		defer cancel()
+		az.saveCacheSnapshot(saveCtx, store)
+	}()
+}
+
+// loadCacheSnapshot restores the caches from the snapshot in the store, and fetches the restored
+// resources again in the background. The resources failed to be fetched are removed from the
+// caches, so they are fetched again when they are read.
+func (az *Cloud) loadCacheSnapshot(ctx context.Context, store cacheSnapshotStore) {
+	data, err := store.load(ctx)
+	if err != nil {
+		klog.Errorf("loadCacheSnapshot: failed to load the snapshot: %v", err)
+		return
+	}
+	if data == nil {
+		klog.V(2).Infof("loadCacheSnapshot: no snapshot is found")
+		return
+	}
+	snapshot, err := decodeCacheSnapshot(data)
+	if err != nil {
+		klog.Errorf("loadCacheSnapshot: failed to decode the snapshot: %v", err)
+		return
+	}
+	if age := time.Since(snapshot.CreatedOn); age > maxCacheSnapshotAge {
+		klog.V(2).Infof("loadCacheSnapshot: skip the snapshot created %s ago", age)
+		return
+	}
+
+	restored := az.restoreCacheSnapshot(snapshot)
+	klog.V(2).Infof("loadCacheSnapshot: restored %d cache entries from the snapshot created at %s", len(restored), snapshot.CreatedOn)
+	go az.revalidateCacheEntries(withARMCaller(ctx, armCallerBackground), restored)
+}
+
+// saveCacheSnapshot saves the snapshot of the caches to the store. The empty snapshots are not
+// saved, so the ones of the previous leader are kept until the caches are filled.
+func (az *Cloud) saveCacheSnapshot(ctx context.Context, store cacheSnapshotStore) {
+	snapshot := az.takeCacheSnapshot()
+	if snapshot.isEmpty() {
+		return
+	}
+	data, err := encodeCacheSnapshot(snapshot)
+	if err != nil {
+		klog.Errorf("saveCacheSnapshot: failed to encode the snapshot: %v", err)
+		return
+	}
+	if err := store.save(ctx, data); err != nil {
+		klog.Errorf("saveCacheSnapshot: failed to save the snapshot: %v", err)
+		return
+	}
+	klog.V(4).Infof("saveCacheSnapshot: saved the snapshot of %d bytes", len(data))
+}
+
+// cachedData returns the data of the entries of the cache, skipping the resources not found.
+func cachedData(c azcache.Resource) map[string]interface{} {
+	store := c.GetStore()
+	if store == nil {
+		return nil
+	}
+	result := make(map[string]interface{})
+	for _, obj := range store.List() {
+		entry := obj.(*azcache.AzureCacheEntry)
+		entry.Lock.Lock()
+		data := entry.Data
+		entry.Lock.Unlock()
+		if data != nil {
+			result[entry.Key] = data
+		}
+	}
+	return result
+}
+
+func (az *Cloud) takeCacheSnapshot() *cacheSnapshot {
+	snapshot := &cacheSnapshot{
+		CreatedOn:       time.Now().UTC(),
+		VirtualMachines: make(map[string]*armcompute.VirtualMachine),
+		LoadBalancers:   make(map[string]*armnetwork.LoadBalancer),
+		ScaleSets:       make(map[string]*VMSSEntry),
+		ScaleSetVMs:     make(map[string]map[string]*VMSSVirtualMachineEntry),
+	}
+	if az.vmCache != nil {
+		for key, data := range cachedData(az.vmCache) {
+			if vm, ok := data.(*armcompute.VirtualMachine); ok {
+				snapshot.VirtualMachines[key] = vm
+			}
+		}
+	}
+	if az.lbCache != nil {
+		for key, data := range cachedData(az.lbCache) {
+			if lb, ok := data.(*armnetwork.LoadBalancer); ok {
+				snapshot.LoadBalancers[key] = lb
+			}
+		}
+	}
+	if ss, ok := az.VMSet.(*ScaleSet); ok {
+		if data, ok := cachedData(ss.vmssCache)[consts.VMSSKey].(*sync.Map); ok {
+			data.Range(func(key, value interface{}) bool {
+				snapshot.ScaleSets[key.(string)] = value.(*VMSSEntry)
+				return true
+			})
+		}
+		for key, data := range cachedData(ss.vmssVMCache) {
+			virtualMachines, ok := data.(*sync.Map)
+			if !ok {
+				continue
+			}
+			entries := make(map[string]*VMSSVirtualMachineEntry)
+			virtualMachines.Range(func(nodeName, value interface{}) bool {
+				entries[nodeName.(string)] = value.(*VMSSVirtualMachineEntry)
+				return true
+			})
+			snapshot.ScaleSetVMs[key] = entries
+		}
+	}
+	return snapshot
+}
+
+// cacheEntryRef is a key of a cache restored from a snapshot.
+type cacheEntryRef struct {
+	cache azcache.Resource
+	key   string
+}
+
+// restoreCacheSnapshot fills the caches with the snapshot as if the resources were just fetched,
+// and returns the restored entries.
+func (az *Cloud) restoreCacheSnapshot(snapshot *cacheSnapshot) []cacheEntryRef {
+	var restored []cacheEntryRef
+	restore := func(c azcache.Resource, key string, data interface{}) {
+		if c == nil || c.GetStore() == nil {
+			return
+		}
+		c.Update(key, data)
+		restored = append(restored, cacheEntryRef{cache: c, key: key})
+	}
+
+	for key, vm := range snapshot.VirtualMachines {
+		restore(az.vmCache, key, vm)
+	}
+	for key, lb := range snapshot.LoadBalancers {
+		restore(az.lbCache, key, lb)
+	}
+	if ss, ok := az.VMSet.(*ScaleSet); ok {
+		if len(snapshot.ScaleSets) > 0 {
+			scaleSets := &sync.Map{}
+			for name, entry := range snapshot.ScaleSets {
+				scaleSets.Store(name, entry)
+			}
+			restore(ss.vmssCache, consts.VMSSKey, scaleSets)
+		}
+		for key, entries := range snapshot.ScaleSetVMs {
+			virtualMachines := &sync.Map{}
+			for nodeName, entry := range entries {
+				virtualMachines.Store(nodeName, entry)
+			}
+			restore(ss.vmssVMCache, key, virtualMachines)
+		}
+	}
+	return restored
+}
+
+// revalidateCacheEntries fetches the restored entries again, at most CacheWarmupConcurrency at a
+// time, and removes the ones failed to be fetched. The entries left when the ARM request budget is
+// nearly consumed are refreshed after the cache TTL.
+func (az *Cloud) revalidateCacheEntries(ctx context.Context, entries []cacheEntryRef) {
+	start := time.Now()
+	var g errgroup.Group
+	g.SetLimit(az.cacheWarmupConcurrency())
+	for _, entry := range entries {
+		g.Go(func() error {
+			if !az.armRequestBudget.allowBackground() {
+				return nil
+			}
+			if _, err := entry.cache.Get(ctx, entry.key, azcache.CacheReadTypeForceRefresh); err != nil {
+				klog.Warningf("revalidateCacheEntries: failed to refresh the cache of %s: %v", entry.key, err)
+				_ = entry.cache.Delete(entry.key)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	klog.V(2).Infof("revalidateCacheEntries: refreshed %d cache entries in %s", len(entries), time.Since(start))
+}
+
+func encodeCacheSnapshot(snapshot *cacheSnapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeCacheSnapshot(data []byte) (*cacheSnapshot, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	snapshot := &cacheSnapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
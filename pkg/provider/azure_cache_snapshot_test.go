@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetclient/mock_virtualmachinescalesetclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetvmclient/mock_virtualmachinescalesetvmclient"
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+func newTestCacheSnapshotScaleSet(t *testing.T, ctrl *gomock.Controller) *ScaleSet {
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	ss.Cloud.VMSet = ss
+	return ss
+}
+
+func TestCacheSnapshotRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vmList := []string{"vmssee6c2000000", "vmssee6c2000001"}
+	ss := newTestCacheSnapshotScaleSet(t, ctrl)
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	expectedScaleSet := buildTestVMSS(testVMSSName, "vmssee6c2")
+	mockVMSSClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachineScaleSet{expectedScaleSet}, nil).Times(1)
+	expectedVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, vmList, "", false)
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedVMs, nil).Times(1)
+
+	_, err := ss.getVmssVM(context.TODO(), "vmssee6c2000001", azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	ss.lbCache.Set("lb", &armnetwork.LoadBalancer{Name: ptr.To("lb")})
+
+	store := &fileCacheSnapshotStore{path: filepath.Join(t.TempDir(), "snapshot")}
+	data, err := store.load(context.TODO())
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	ss.saveCacheSnapshot(context.TODO(), store)
+	data, err = store.load(context.TODO())
+	assert.NoError(t, err)
+	snapshot, err := decodeCacheSnapshot(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, snapshot.ScaleSets, 1)
+	assert.Len(t, snapshot.ScaleSetVMs[getVMSSVMCacheKey("rg", testVMSSName)], 2)
+	assert.Len(t, snapshot.LoadBalancers, 1)
+
+	// the restored caches serve the reads without calling ARM
+	restoredSS := newTestCacheSnapshotScaleSet(t, ctrl)
+	restored := restoredSS.restoreCacheSnapshot(snapshot)
+	assert.Len(t, restored, 3)
+	vm, err := restoredSS.getVmssVM(context.TODO(), "vmssee6c2000001", azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, testVMSSName, vm.VMSSName)
+	assert.Equal(t, ptr.Deref(expectedVMs[1].InstanceID, ""), vm.InstanceID)
+	lb, exists, err := restoredSS.getAzureLoadBalancer(context.TODO(), "lb", azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "lb", ptr.Deref(lb.Name, ""))
+
+	// the restored entries are fetched again, and removed if they fail to be fetched
+	restoredVMSSClient := restoredSS.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	restoredVMSSVMClient := restoredSS.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	restoredLBClient := restoredSS.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	restoredVMSSClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachineScaleSet{expectedScaleSet}, nil).Times(1)
+	restoredVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedVMs, nil).Times(1)
+	restoredLBClient.EXPECT().Get(gomock.Any(), gomock.Any(), "lb", gomock.Any()).Return(nil, errors.New("get failed")).Times(1)
+	restoredSS.revalidateCacheEntries(context.TODO(), restored)
+	_, exists, err = restoredSS.lbCache.GetStore().GetByKey("lb")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLoadCacheSnapshotSkipsStaleSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	data, err := encodeCacheSnapshot(&cacheSnapshot{
+		CreatedOn:     time.Now().Add(-2 * maxCacheSnapshotAge),
+		LoadBalancers: map[string]*armnetwork.LoadBalancer{"lb": {Name: ptr.To("lb")}},
+	})
+	assert.NoError(t, err)
+	store := &fileCacheSnapshotStore{path: filepath.Join(t.TempDir(), "snapshot")}
+	assert.NoError(t, store.save(context.TODO(), data))
+
+	az.loadCacheSnapshot(context.TODO(), store)
+	_, exists, err := az.lbCache.GetStore().GetByKey("lb")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestConfigMapCacheSnapshotStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.KubeClient = fake.NewSimpleClientset()
+	az.CacheSnapshotConfigMap = "snapshot"
+	store, err := az.newCacheSnapshotStore()
+	assert.NoError(t, err)
+	if !assert.IsType(t, &configMapCacheSnapshotStore{}, store) {
+		return
+	}
+	assert.Equal(t, "kube-system", store.(*configMapCacheSnapshotStore).namespace)
+
+	data, err := store.load(context.TODO())
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	// the ConfigMap is created and then updated
+	assert.NoError(t, store.save(context.TODO(), []byte("first")))
+	assert.NoError(t, store.save(context.TODO(), []byte("second")))
+	data, err = store.load(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+
+	assert.Error(t, store.save(context.TODO(), make([]byte, maxCacheSnapshotConfigMapSize+1)))
+}
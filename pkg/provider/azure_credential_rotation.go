@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+var (
+	// ErrCredentialsNotRotatable indicates that the cloud has to be re-initialized to apply the config.
+	ErrCredentialsNotRotatable = errors.New("credentials are not rotatable in place")
+)
+
+// getConfigWithoutSecrets returns the JSON of the config without the client secrets and the rate limits, which
+// tells whether two configs differ in anything other than the client secrets and the rate limits.
+func getConfigWithoutSecrets(config *azureconfig.Config) ([]byte, error) {
+	c := *config
+	c.AADClientSecret = ""
+	c.AADClientCertPassword = ""
+	c.CloudProviderRateLimitConfig = ratelimit.CloudProviderRateLimitConfig{}
+	return json.Marshal(c)
+}
+
+// RotateCredentials rebuilds the credentials from the client secret of the config and swaps them into the
+// ARM clients in place, and tunes the rate limiters of the ARM clients to the rate limits of the config.
+// It returns ErrCredentialsNotRotatable if the config changes anything other than the client secrets and
+// the rate limits, in which case the cloud has to be re-initialized from the config, and rejects the rate
+// limits failing validateRateLimits before anything is changed. The secrets of the config are replaced under
+// credentialSecretsLock, so they can be read concurrently, e.g. through getClientCertPassword.
+func (az *Cloud) RotateCredentials(config *azureconfig.Config) error {
+	az.credentialSecretsLock.Lock()
+	defer az.credentialSecretsLock.Unlock()
+
+	if az.computeCredential == nil {
+		return fmt.Errorf("%w: the cloud is not initialized with credentials from the config", ErrCredentialsNotRotatable)
+	}
+	configWithoutSecrets, err := getConfigWithoutSecrets(config)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(configWithoutSecrets, az.configWithoutSecrets) {
+		return fmt.Errorf("%w: the config changes other than the client secrets and the rate limits", ErrCredentialsNotRotatable)
+	}
+	if !reflect.DeepEqual(config.CloudProviderRateLimitConfig, az.CloudProviderRateLimitConfig) {
+		if err := validateRateLimits(config); err != nil {
+			return err
+		}
+		az.UpdateRateLimits(config)
+	}
+	if config.AADClientSecret == az.AADClientSecret && config.AADClientCertPassword == az.AADClientCertPassword {
+		return nil
+	}
+
+	authProvider, err := azclient.NewAuthProvider(&config.ARMClientConfig, &config.AzureAuthConfig)
+	if err != nil {
+		return err
+	}
+	if authProvider.ComputeCredential == nil || (authProvider.NetworkCredential == nil) != (az.networkCredential == nil) {
+		return fmt.Errorf("%w: the credentials of the config do not match the current ones", ErrCredentialsNotRotatable)
+	}
+
+	az.computeCredential.Rotate(authProvider.ComputeCredential)
+	if az.networkCredential != nil {
+		az.networkCredential.Rotate(authProvider.NetworkCredential)
+	}
+	az.AADClientSecret = config.AADClientSecret
+	az.AADClientCertPassword = config.AADClientCertPassword
+	klog.Infof("RotateCredentials: rotated the credentials of the ARM clients")
+	return nil
+}
+
+// getClientCertPassword returns the password of the client certificate of the config, which is rotated in place by
+// RotateCredentials.
+func (az *Cloud) getClientCertPassword() string {
+	az.credentialSecretsLock.RLock()
+	defer az.credentialSecretsLock.RUnlock()
+
+	return az.AADClientCertPassword
+}
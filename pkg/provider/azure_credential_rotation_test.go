@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/zone"
+)
+
+func TestRotateCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newConfig := func(secret string) *config.Config {
+		c := &config.Config{}
+		c.TenantID = "tenant-id"
+		c.SubscriptionID = "subscription-id"
+		c.AADClientID = "client-id"
+		c.AADClientSecret = secret
+		return c
+	}
+
+	az := GetTestCloud(ctrl)
+	err := az.RotateCredentials(newConfig("secret"))
+	assert.ErrorIs(t, err, ErrCredentialsNotRotatable)
+
+	zoneMock := az.zoneRepo.(*zone.MockRepository)
+	zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+	az.AuthProvider = nil
+	az.ComputeClientFactory = nil
+	az.NetworkClientFactory = nil
+	assert.NoError(t, az.InitializeCloudFromConfig(context.Background(), newConfig("secret"), false, true))
+	assert.NotNil(t, az.computeCredential)
+	assert.Equal(t, az.computeCredential, az.AuthProvider.GetAzIdentity())
+	assert.Nil(t, az.networkCredential)
+
+	// the same config is a no-op
+	assert.NoError(t, az.RotateCredentials(newConfig("secret")))
+
+	// the secrets are read concurrently, e.g. by the status reporter
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = az.getClientCertPassword()
+	}()
+	rotated := newConfig("new-secret")
+	rotated.AADClientCertPassword = "password"
+	assert.NoError(t, az.RotateCredentials(rotated))
+	wg.Wait()
+	assert.Equal(t, "new-secret", az.AADClientSecret)
+	assert.Equal(t, "password", az.getClientCertPassword())
+	assert.Equal(t, az.computeCredential, az.AuthProvider.GetAzIdentity())
+
+	changedConfig := newConfig("newer-secret")
+	changedConfig.ResourceGroup = "rg"
+	err = az.RotateCredentials(changedConfig)
+	assert.ErrorIs(t, err, ErrCredentialsNotRotatable)
+	assert.Equal(t, "new-secret", az.AADClientSecret)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+// getCredentialSetARMClientConfig returns the ARM client config of the credential set, which is
+// the one of the cluster in the tenant of the credential set.
+func (az *Cloud) getCredentialSetARMClientConfig(set *config.CredentialSet) *azclient.ARMClientConfig {
+	armConfig := az.ARMClientConfig
+	if set.TenantID != "" {
+		armConfig.TenantID = set.TenantID
+	}
+	armConfig.NetworkResourceTenantID = ""
+	return &armConfig
+}
+
+// newCredentialSetAuthProviders creates the auth providers of the credential sets in order. It also returns the
+// credentials of the credential sets in other tenants, which issue the auxiliary tokens of the network resources.
+func (az *Cloud) newCredentialSetAuthProviders() ([]*azclient.AuthProvider, []azcore.TokenCredential, error) {
+	var (
+		authProviders  = make([]*azclient.AuthProvider, 0, len(az.CredentialSets))
+		auxiliaryCreds []azcore.TokenCredential
+	)
+	for i := range az.CredentialSets {
+		set := &az.CredentialSets[i]
+		authProvider, err := azclient.NewAuthProvider(az.getCredentialSetARMClientConfig(set), &set.AzureAuthConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("credentialSets[%d]: %w", i, err)
+		}
+		if authProvider.GetAzIdentity() == nil {
+			return nil, nil, fmt.Errorf("credentialSets[%d] of subscription %s does not have credentials", i, set.SubscriptionID)
+		}
+		authProviders = append(authProviders, authProvider)
+		if set.TenantID != "" && !strings.EqualFold(set.TenantID, az.GetTenantID()) {
+			auxiliaryCreds = append(auxiliaryCreds, authProvider.GetAzIdentity())
+		}
+	}
+	return authProviders, auxiliaryCreds, nil
+}
+
+// getNetworkClientFactoryForResource returns the client factory of the credential set of the subscription
+// in the resource ID, or the network client factory if no credential set matches. It is only used to get
+// the network interfaces of the nodes, the other ARM calls use the client factories of the cluster.
+func (az *Cloud) getNetworkClientFactoryForResource(resourceID string) azclient.ClientFactory {
+	if len(az.credentialSetClientFactories) == 0 {
+		return az.NetworkClientFactory
+	}
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return az.NetworkClientFactory
+	}
+	if factory, ok := az.credentialSetClientFactories[strings.ToLower(id.SubscriptionID)]; ok {
+		return factory
+	}
+	return az.NetworkClientFactory
+}
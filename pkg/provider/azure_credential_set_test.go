@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/mock_azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+func TestNewCredentialSetAuthProviders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.TenantID = "tenant"
+	az.CredentialSets = []config.CredentialSet{
+		{
+			SubscriptionID: "sub-same-tenant",
+			AzureAuthConfig: azclient.AzureAuthConfig{
+				AADClientID:     "client-1",
+				AADClientSecret: "secret-1",
+			},
+		},
+		{
+			SubscriptionID: "sub-other-tenant",
+			TenantID:       "other-tenant",
+			AzureAuthConfig: azclient.AzureAuthConfig{
+				AADClientID:     "client-2",
+				AADClientSecret: "secret-2",
+			},
+		},
+	}
+
+	authProviders, auxiliaryCreds, err := az.newCredentialSetAuthProviders()
+	assert.NoError(t, err)
+	assert.Len(t, authProviders, 2)
+	assert.Equal(t, 1, len(auxiliaryCreds))
+	assert.Equal(t, authProviders[1].GetAzIdentity(), auxiliaryCreds[0])
+	assert.Equal(t, "other-tenant", az.getCredentialSetARMClientConfig(&az.CredentialSets[1]).TenantID)
+	assert.Equal(t, "tenant", az.getCredentialSetARMClientConfig(&az.CredentialSets[0]).TenantID)
+
+	az.CredentialSets = append(az.CredentialSets, config.CredentialSet{SubscriptionID: "sub-without-credentials"})
+	_, _, err = az.newCredentialSetAuthProviders()
+	assert.ErrorContains(t, err, "credentialSets[2] of subscription sub-without-credentials does not have credentials")
+}
+
+func TestGetNetworkClientFactoryForResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	nicID := "/subscriptions/Other-Sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic"
+	assert.Equal(t, az.NetworkClientFactory, az.getNetworkClientFactoryForResource(nicID))
+
+	factory := mock_azclient.NewMockClientFactory(ctrl)
+	az.credentialSetClientFactories = map[string]azclient.ClientFactory{"other-sub": factory}
+	assert.Equal(t, factory, az.getNetworkClientFactoryForResource(nicID))
+	assert.Equal(t, factory, az.getNetworkClientFactoryForResource(nicID+"/ipConfigurations/ipconfig1"))
+	assert.Equal(t, az.NetworkClientFactory, az.getNetworkClientFactoryForResource("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic"))
+	assert.Equal(t, az.NetworkClientFactory, az.getNetworkClientFactoryForResource("invalid"))
+}
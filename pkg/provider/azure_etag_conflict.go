@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientretry "k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// maxETagConflictRetries is the number of times a read-modify-write of a load balancer, security
+// group or route table is retried when the resource is modified concurrently.
+const maxETagConflictRetries = 3
+
+// etagConflictBackoff is the backoff between the retries of the ETag conflicts.
+var etagConflictBackoff = wait.Backoff{
+	Steps:    maxETagConflictRetries + 1,
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// isETagConflict returns true if the write is rejected because the ETag sent in the If-Match
+// header doesn't match, i.e. the resource was modified since it was read.
+func isETagConflict(err error) bool {
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.StatusCode == http.StatusPreconditionFailed
+}
+
+// retryOnETagConflict runs the read-modify-write fn, and runs it again while it fails with an
+// ETag conflict, up to maxETagConflictRetries times. The writes of the load balancers, security
+// groups and route tables carry the ETag of the resource read, so the changes made meanwhile by
+// other tooling are not reverted silently. The cached resource is invalidated on the conflict,
+// so fn reads the resource again and applies its changes on top of the concurrent ones.
+func retryOnETagConflict(ctx context.Context, resource string, fn func() error) error {
+	return clientretry.OnError(etagConflictBackoff, func(err error) bool {
+		if !isETagConflict(err) || ctx.Err() != nil {
+			return false
+		}
+		klog.V(2).InfoS("retryOnETagConflict: the resource is modified concurrently, reading it again and retrying", "resource", resource, "error", err)
+		return true
+	}, fn)
+}
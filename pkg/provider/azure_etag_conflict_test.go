@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRetryOnETagConflict(t *testing.T) {
+	defer func(backoff wait.Backoff) { etagConflictBackoff = backoff }(etagConflictBackoff)
+	etagConflictBackoff.Duration = time.Millisecond
+
+	conflictErr := &azcore.ResponseError{StatusCode: http.StatusPreconditionFailed}
+	for _, tc := range []struct {
+		desc          string
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			desc:          "succeeded",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			desc:          "retried after conflicts",
+			errs:          []error{conflictErr, conflictErr, nil},
+			expectedCalls: 3,
+		},
+		{
+			desc:          "other errors are not retried",
+			errs:          []error{&azcore.ResponseError{StatusCode: http.StatusBadRequest}},
+			expectedCalls: 1,
+			expectedErr:   &azcore.ResponseError{StatusCode: http.StatusBadRequest},
+		},
+		{
+			desc:          "conflicts are retried a bounded number of times",
+			errs:          []error{conflictErr, conflictErr, conflictErr, conflictErr, nil},
+			expectedCalls: maxETagConflictRetries + 1,
+			expectedErr:   conflictErr,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			calls := 0
+			err := retryOnETagConflict(context.Background(), "resource", func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := retryOnETagConflict(ctx, "resource", func() error {
+		calls++
+		return conflictErr
+	})
+	assert.Equal(t, conflictErr, err)
+	assert.Equal(t, 1, calls)
+}
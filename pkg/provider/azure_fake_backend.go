@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/fakeazure"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+// The defaults of the config of the cloud backed by the fake backend.
+const (
+	fakeBackendSubscriptionID = "00000000-0000-0000-0000-000000000000"
+	fakeBackendLocation       = "eastus"
+	fakeBackendResourceGroup  = "fake-rg"
+	fakeBackendVnetName       = "fake-vnet"
+	fakeBackendSubnetName     = "fake-subnet"
+	fakeBackendSecurityGroup  = "fake-nsg"
+	fakeBackendRouteTable     = "fake-routetable"
+)
+
+// NewFakeCloudFromConfigFile returns a Cloud whose ARM clients are served by an in-memory fake backend
+// instead of Azure, so the cloud provider runs without an Azure subscription. The config file is
+// optional. The resources of the resources file, e.g. the VMs or the scale sets of the nodes, are
+// loaded into the backend if it is set.
+func NewFakeCloudFromConfigFile(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, configFilePath, resourcesFilePath string, callFromCCM bool) (cloudprovider.Interface, error) {
+	config := &azureconfig.Config{}
+	if configFilePath != "" {
+		configFile, err := os.Open(configFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the cloud config %s: %w", configFilePath, err)
+		}
+		defer configFile.Close()
+		if config, err = azureconfig.ParseConfig(configFile); err != nil {
+			return nil, fmt.Errorf("failed to parse the cloud config %s: %w", configFilePath, err)
+		}
+	}
+
+	backend := fakeazure.NewBackend()
+	if resourcesFilePath != "" {
+		if err := backend.LoadFile(resourcesFilePath); err != nil {
+			return nil, fmt.Errorf("failed to load the resources of the fake backend: %w", err)
+		}
+	}
+	az, err := newFakeCloud(ctx, clientBuilder, config, backend, callFromCCM)
+	if err != nil {
+		return nil, fmt.Errorf("could not init cloud provider azure with the fake backend: %w", err)
+	}
+	return az, nil
+}
+
+// newFakeCloud returns a Cloud backed by the fake backend. The subscription, the location, the resource
+// group and the network resources missing from the config are set to the fake ones, and the resources
+// the cloud provider expects to exist, e.g. the virtual network and the route table, are created in
+// the backend if they are not there yet.
+func newFakeCloud(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, config *azureconfig.Config, backend *fakeazure.Backend, callFromCCM bool) (*Cloud, error) {
+	setFakeBackendConfigDefaults(config)
+	if err := seedFakeBackend(backend, config); err != nil {
+		return nil, err
+	}
+
+	klog.Warningf("newFakeCloud: the cloud provider is backed by the in-memory fake backend, no Azure resources are changed")
+	return newCloud(ctx, clientBuilder, config, callFromCCM, func(az *Cloud) {
+		az.AuthProvider = &azclient.AuthProvider{ComputeCredential: fakeazure.Credential{}}
+		az.armClientOptions = []func(option *arm.ClientOptions){backend.ClientOption}
+	})
+}
+
+func setFakeBackendConfigDefaults(config *azureconfig.Config) {
+	if config.SubscriptionID == "" {
+		config.SubscriptionID = fakeBackendSubscriptionID
+	}
+	if config.Location == "" {
+		config.Location = fakeBackendLocation
+	}
+	if config.ResourceGroup == "" {
+		config.ResourceGroup = fakeBackendResourceGroup
+	}
+	if config.VnetName == "" {
+		config.VnetName = fakeBackendVnetName
+	}
+	if config.SubnetName == "" {
+		config.SubnetName = fakeBackendSubnetName
+	}
+	if config.SecurityGroupName == "" {
+		config.SecurityGroupName = fakeBackendSecurityGroup
+	}
+	if config.RouteTableName == "" {
+		config.RouteTableName = fakeBackendRouteTable
+	}
+}
+
+// seedFakeBackend creates the resource group, the zones of the location, and the virtual network, the
+// security group and the route table of the config in the backend, unless they are loaded already.
+func seedFakeBackend(backend *fakeazure.Backend, config *azureconfig.Config) error {
+	networkSubscriptionID := config.SubscriptionID
+	if config.UsesNetworkResourceInDifferentSubscription() {
+		networkSubscriptionID = config.NetworkResourceSubscriptionID
+	}
+	resourceGroupID := func(subscriptionID, resourceGroup string) string {
+		if resourceGroup == "" {
+			resourceGroup = config.ResourceGroup
+		}
+		return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
+	}
+
+	resources := []struct {
+		id       string
+		resource any
+	}{
+		{
+			id:       resourceGroupID(config.SubscriptionID, config.ResourceGroup),
+			resource: map[string]any{"location": config.Location},
+		},
+		{
+			id: fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Compute", config.SubscriptionID),
+			resource: map[string]any{
+				"namespace": "Microsoft.Compute",
+				"resourceTypes": []any{map[string]any{
+					"resourceType": "virtualMachines",
+					"locations":    []string{config.Location},
+					"zoneMappings": []any{map[string]any{
+						"location": config.Location,
+						"zones":    []string{"1", "2", "3"},
+					}},
+				}},
+			},
+		},
+		{
+			id: resourceGroupID(networkSubscriptionID, config.VnetResourceGroup) + "/providers/Microsoft.Network/virtualNetworks/" + config.VnetName,
+			resource: armnetwork.VirtualNetwork{
+				Location: ptr.To(config.Location),
+				Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+					AddressSpace: &armnetwork.AddressSpace{AddressPrefixes: []*string{ptr.To("10.0.0.0/8")}},
+					Subnets: []*armnetwork.Subnet{{
+						Name:       ptr.To(config.SubnetName),
+						Properties: &armnetwork.SubnetPropertiesFormat{AddressPrefix: ptr.To("10.224.0.0/16")},
+					}},
+				},
+			},
+		},
+		{
+			id: resourceGroupID(networkSubscriptionID, config.SecurityGroupResourceGroup) + "/providers/Microsoft.Network/networkSecurityGroups/" + config.SecurityGroupName,
+			resource: armnetwork.SecurityGroup{
+				Location:   ptr.To(config.Location),
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{SecurityRules: []*armnetwork.SecurityRule{}},
+			},
+		},
+		{
+			id: resourceGroupID(networkSubscriptionID, config.RouteTableResourceGroup) + "/providers/Microsoft.Network/routeTables/" + config.RouteTableName,
+			resource: armnetwork.RouteTable{
+				Location:   ptr.To(config.Location),
+				Properties: &armnetwork.RouteTablePropertiesFormat{Routes: []*armnetwork.Route{}},
+			},
+		},
+	}
+	for _, r := range resources {
+		var existing map[string]any
+		found, err := backend.Get(r.id, &existing)
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+		if err := backend.Put(r.id, r.resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
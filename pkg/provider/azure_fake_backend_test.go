@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/fakeazure"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+func TestFakeCloudEnsureLoadBalancer(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeazure.NewBackend()
+	az, err := newFakeCloud(ctx, nil, &azureconfig.Config{LoadBalancerSKU: consts.LoadBalancerSKUStandard}, backend, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, fakeBackendSubscriptionID, az.SubscriptionID)
+	assert.Equal(t, fakeBackendResourceGroup, az.ResourceGroup)
+	az.KubeClient = fake.NewSimpleClientset()
+	az.eventRecorder = &record.FakeRecorder{}
+	informerFactory := informers.NewSharedInformerFactory(az.KubeClient, 0)
+	az.serviceLister = informerFactory.Core().V1().Services().Lister()
+	informerFactory.Start(wait.NeverStop)
+	informerFactory.WaitForCacheSync(wait.NeverStop)
+
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	status, err := az.EnsureLoadBalancer(ctx, testClusterName, &service, []*v1.Node{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the public IP is allocated by the backend
+	if assert.Len(t, status.Ingress, 1) {
+		assert.Equal(t, "20.0.0.1", status.Ingress[0].IP)
+	}
+
+	var lb armnetwork.LoadBalancer
+	found, err := backend.Get("/subscriptions/"+fakeBackendSubscriptionID+"/resourceGroups/"+fakeBackendResourceGroup+"/providers/Microsoft.Network/loadBalancers/"+testClusterName, &lb)
+	assert.NoError(t, err)
+	if assert.True(t, found) {
+		assert.Len(t, lb.Properties.LoadBalancingRules, 1)
+	}
+
+	assert.NoError(t, az.EnsureLoadBalancerDeleted(ctx, testClusterName, &service))
+	var pip armnetwork.PublicIPAddress
+	found, err = backend.Get("/subscriptions/"+fakeBackendSubscriptionID+"/resourceGroups/"+fakeBackendResourceGroup+"/providers/Microsoft.Network/publicIPAddresses/"+testClusterName+"-"+az.getDefaultFrontendIPConfigName(&service), &pip)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -40,6 +40,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetclient/mock_virtualmachinescalesetclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetvmclient/mock_virtualmachinescalesetvmclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualnetworkclient/mock_virtualnetworkclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualnetworklinkclient/mock_virtualnetworklinkclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
@@ -103,15 +104,16 @@ func GetTestCloud(ctrl *gomock.Controller) (az *Cloud) {
 			VMType:                                   consts.VMTypeStandard,
 			LoadBalancerBackendPoolConfigurationType: consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration,
 		},
-		nodeZones:                map[string]*utilsets.IgnoreCaseSet{},
-		nodeInformerSynced:       func() bool { return true },
-		nodeResourceGroups:       map[string]string{},
-		unmanagedNodes:           utilsets.NewString(),
-		excludeLoadBalancerNodes: utilsets.NewString(),
-		nodePrivateIPs:           map[string]*utilsets.IgnoreCaseSet{},
-		routeCIDRs:               map[string]string{},
-		eventRecorder:            &record.FakeRecorder{},
-		Environment:              &azclient.Environment{},
+		nodeZones:                    map[string]*utilsets.IgnoreCaseSet{},
+		nodeInformerSynced:           func() bool { return true },
+		nodeResourceGroups:           map[string]string{},
+		nodeProviderIDResourceGroups: map[string]string{},
+		unmanagedNodes:               utilsets.NewString(),
+		excludeLoadBalancerNodes:     utilsets.NewString(),
+		nodePrivateIPs:               map[string]*utilsets.IgnoreCaseSet{},
+		routeCIDRs:                   map[string]string{},
+		eventRecorder:                &record.FakeRecorder{},
+		Environment:                  &azclient.Environment{},
 	}
 	clientFactory := mock_azclient.NewMockClientFactory(ctrl)
 	az.ComputeClientFactory = clientFactory
@@ -142,6 +144,8 @@ func GetTestCloud(ctrl *gomock.Controller) (az *Cloud) {
 	clientFactory.EXPECT().GetSecurityGroupClient().Return(securtyGrouptrack2Client).AnyTimes()
 	mockPrivateDNSClient := mock_privatezoneclient.NewMockInterface(ctrl)
 	clientFactory.EXPECT().GetPrivateZoneClient().Return(mockPrivateDNSClient).AnyTimes()
+	virtualNetworkClient := mock_virtualnetworkclient.NewMockInterface(ctrl)
+	clientFactory.EXPECT().GetVirtualNetworkClient().Return(virtualNetworkClient).AnyTimes()
 	virtualNetworkLinkClient := mock_virtualnetworklinkclient.NewMockInterface(ctrl)
 	clientFactory.EXPECT().GetVirtualNetworkLinkClient().Return(virtualNetworkLinkClient).AnyTimes()
 	subnetTrack2Client := mock_subnetclient.NewMockInterface(ctrl)
@@ -157,8 +161,12 @@ func GetTestCloud(ctrl *gomock.Controller) (az *Cloud) {
 	}
 	az.VMSet, _ = newAvailabilitySet(az)
 	az.vmCache, _ = az.newVMCache()
+	az.nodeIdentityCache, _ = az.newNodeIdentityCache()
+	az.routeTableSubnetCache, _ = az.newRouteTableSubnetCache()
+	az.routeNodeInterfacesCache, _ = az.newRouteNodeInterfacesCache()
+	az.internalLoadBalancerVNetCache, _ = az.newInternalLoadBalancerVNetCache()
 	az.lbCache, _ = az.newLBCache()
-	az.nsgRepo, _ = securitygroup.NewSecurityGroupRepo(az.SecurityGroupResourceGroup, az.SecurityGroupName, az.NsgCacheTTLInSeconds, az.Config.DisableAPICallCache, securtyGrouptrack2Client)
+	az.nsgRepo, _ = securitygroup.NewSecurityGroupRepo(az.SecurityGroupResourceGroup, az.SecurityGroupName, az.NsgCacheTTLInSeconds, az.Config.DisableAPICallCache, securtyGrouptrack2Client, nil)
 	az.subnetRepo = subnet.NewMockRepository(ctrl)
 	az.pipCache, _ = az.newPIPCache()
 	az.LoadBalancerBackendPool = NewMockBackendPool(ctrl)
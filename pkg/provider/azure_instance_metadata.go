@@ -102,10 +102,30 @@ type LoadBalancerMetadata struct {
 	LoadBalancer *LoadbalancerProfile `json:"loadbalancer,omitempty"`
 }
 
+// ScheduledEvent represents a scheduled event of the VMs in IMDS.
+type ScheduledEvent struct {
+	EventID      string   `json:"EventId,omitempty"`
+	EventType    string   `json:"EventType,omitempty"`
+	ResourceType string   `json:"ResourceType,omitempty"`
+	Resources    []string `json:"Resources,omitempty"`
+	EventStatus  string   `json:"EventStatus,omitempty"`
+	NotBefore    string   `json:"NotBefore,omitempty"`
+	Description  string   `json:"Description,omitempty"`
+	EventSource  string   `json:"EventSource,omitempty"`
+}
+
+// ScheduledEventsMetadata represents the scheduled events metadata.
+type ScheduledEventsMetadata struct {
+	DocumentIncarnation int              `json:"DocumentIncarnation,omitempty"`
+	Events              []ScheduledEvent `json:"Events,omitempty"`
+}
+
 // InstanceMetadataService knows how to query the Azure instance metadata server.
 type InstanceMetadataService struct {
 	imdsServer string
 	imsCache   azcache.Resource
+	// transport is the transport of the requests to IMDS, the default transport is used if it is nil.
+	transport http.RoundTripper
 }
 
 // NewInstanceMetadataService creates an instance of the InstanceMetadataService accessor object.
@@ -192,7 +212,7 @@ func (ims *InstanceMetadataService) getInstanceMetadata(_ string) (*InstanceMeta
 	q.Add("api-version", consts.ImdsInstanceAPIVersion)
 	req.URL.RawQuery = q.Encode()
 
-	client := &http.Client{Timeout: time.Minute}
+	client := &http.Client{Transport: ims.transport, Timeout: time.Minute}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -230,7 +250,7 @@ func (ims *InstanceMetadataService) getLoadBalancerMetadata() (*LoadBalancerMeta
 	q.Add("api-version", consts.ImdsLoadBalancerAPIVersion)
 	req.URL.RawQuery = q.Encode()
 
-	client := &http.Client{Timeout: time.Minute}
+	client := &http.Client{Transport: ims.transport, Timeout: time.Minute}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -255,6 +275,45 @@ func (ims *InstanceMetadataService) getLoadBalancerMetadata() (*LoadBalancerMeta
 	return &obj, nil
 }
 
+// GetScheduledEvents gets the scheduled events of the VMs from IMDS. The events are not cached
+// because they have to be handled within minutes, or seconds for the preemption of spot VMs.
+func (ims *InstanceMetadataService) GetScheduledEvents(ctx context.Context) (*ScheduledEventsMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", ims.imdsServer+consts.ImdsScheduledEventsURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Metadata", "True")
+	req.Header.Add("User-Agent", "golang/kubernetes-cloud-provider")
+
+	q := req.URL.Query()
+	q.Add("api-version", consts.ImdsScheduledEventsAPIVersion)
+	req.URL.RawQuery = q.Encode()
+
+	client := &http.Client{Transport: ims.transport, Timeout: time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failure of getting scheduled events with response %q", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := ScheduledEventsMetadata{}
+	err = json.Unmarshal(data, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
 // GetMetadata gets instance metadata from cache.
 // crt determines if we can get data from stalled cache/need fresh if cache expired.
 func (ims *InstanceMetadataService) GetMetadata(ctx context.Context, crt azcache.AzureCacheReadType) (*InstanceMetadata, error) {
@@ -150,3 +150,37 @@ func TestGetPlatformSubFaultDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestGetScheduledEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metadata/scheduledevents", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "True", r.Header.Get("Metadata"))
+		fmt.Fprint(w, `{"DocumentIncarnation":2,"Events":[{"EventId":"event0","EventType":"Preempt","ResourceType":"VirtualMachine","Resources":["vm0"],"EventStatus":"Scheduled","NotBefore":"Mon, 19 Sep 2016 18:29:47 GMT"}]}`)
+	}))
+	go func() {
+		_ = http.Serve(listener, mux)
+	}()
+	defer listener.Close()
+
+	ims, err := NewInstanceMetadataService("http://" + listener.Addr().String())
+	assert.NoError(t, err)
+
+	events, err := ims.GetScheduledEvents(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, &ScheduledEventsMetadata{
+		DocumentIncarnation: 2,
+		Events: []ScheduledEvent{
+			{
+				EventID:      "event0",
+				EventType:    "Preempt",
+				ResourceType: "VirtualMachine",
+				Resources:    []string{"vm0"},
+				EventStatus:  "Scheduled",
+				NotBefore:    "Mon, 19 Sep 2016 18:29:47 GMT",
+			},
+		},
+	}, events)
+}
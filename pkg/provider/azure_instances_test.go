@@ -131,6 +131,7 @@ func TestInstanceID(t *testing.T) {
 		useInstanceMetadata bool
 		useCustomImsCache   bool
 		nilVMSet            bool
+		metadataOnly        bool
 		expectedErrMsg      error
 	}{
 		{
@@ -174,6 +175,16 @@ func TestInstanceID(t *testing.T) {
 			useInstanceMetadata: true,
 			expectedID:          "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm2",
 		},
+		{
+			name:                "InstanceID should report error if node is not local instance in the instance metadata only mode",
+			vmList:              []string{"vm2"},
+			nodeName:            "vm2",
+			metadataName:        "vm1",
+			vmType:              consts.VMTypeStandard,
+			useInstanceMetadata: true,
+			metadataOnly:        true,
+			expectedErrMsg:      newRemoteInstanceMetadataError("vm2"),
+		},
 		{
 			name:         "InstanceID should get instanceID from Azure API if cloud.UseInstanceMetadata is false",
 			vmList:       []string{"vm2"},
@@ -226,6 +237,7 @@ func TestInstanceID(t *testing.T) {
 		}
 		cloud.Config.VMType = test.vmType
 		cloud.Config.UseInstanceMetadata = test.useInstanceMetadata
+		cloud.Config.InstanceMetadataOnly = test.metadataOnly
 		listener, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
 			t.Errorf("Test [%s] unexpected error: %v", test.name, err)
@@ -464,6 +476,7 @@ func TestNodeAddresses(t *testing.T) {
 		useInstanceMetadata bool
 		useCustomImsCache   bool
 		nilVMSet            bool
+		nodeAddressSource   string
 		expectedErrMsg      error
 	}{
 		{
@@ -533,6 +546,16 @@ func TestNodeAddresses(t *testing.T) {
 			vmType:          consts.VMTypeStandard,
 			expectedAddress: expectedNodeAddress,
 		},
+		{
+			name:                "NodeAddresses should get IP addresses from Azure API if nodeAddressSource is arm",
+			nodeName:            "vm1",
+			metadataName:        "vm1",
+			vmType:              consts.VMTypeStandard,
+			ipV4:                "10.240.0.1",
+			useInstanceMetadata: true,
+			nodeAddressSource:   consts.NodeAddressSourceARM,
+			expectedAddress:     expectedNodeAddress,
+		},
 		{
 			name:                "NodeAddresses should get IP addresses from local IMDS if node's name is equal to metadataName",
 			nodeName:            "vm1",
@@ -611,6 +634,7 @@ func TestNodeAddresses(t *testing.T) {
 		}
 		cloud.Config.VMType = test.vmType
 		cloud.Config.UseInstanceMetadata = test.useInstanceMetadata
+		cloud.Config.NodeAddressSource = test.nodeAddressSource
 		listener, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
 			t.Errorf("Test [%s] unexpected error: %v", test.name, err)
@@ -667,6 +691,73 @@ func TestNodeAddresses(t *testing.T) {
 	}
 }
 
+func TestInstanceExistsByProviderIDDeallocatedVMPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name                string
+		powerState          string
+		deallocatedVMPolicy string
+		expected            bool
+	}{
+		{
+			name:                "InstanceExistsByProviderID should return true for deallocated VMs with shutdown policy",
+			powerState:          "PowerState/deallocated",
+			deallocatedVMPolicy: consts.DeallocatedVMPolicyShutdown,
+			expected:            true,
+		},
+		{
+			name:                "InstanceExistsByProviderID should return false for deallocated VMs with deleted policy",
+			powerState:          "PowerState/deallocated",
+			deallocatedVMPolicy: consts.DeallocatedVMPolicyDeleted,
+			expected:            false,
+		},
+		{
+			name:                "InstanceExistsByProviderID should return false for deallocating VMs with deleted policy",
+			powerState:          "PowerState/deallocating",
+			deallocatedVMPolicy: consts.DeallocatedVMPolicyDeleted,
+			expected:            false,
+		},
+		{
+			name:                "InstanceExistsByProviderID should return true for stopped VMs with deleted policy",
+			powerState:          "PowerState/stopped",
+			deallocatedVMPolicy: consts.DeallocatedVMPolicyDeleted,
+			expected:            true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cloud := GetTestCloud(ctrl)
+			cloud.DeallocatedVMPolicy = test.deallocatedVMPolicy
+
+			expectedVMs := setTestVirtualMachines(cloud, map[string]string{"vm1": test.powerState}, false)
+			mockVMsClient := cloud.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+			mockVMsClient.EXPECT().Get(gomock.Any(), cloud.ResourceGroup, "vm1", gomock.Any()).Return(expectedVMs[0], nil).AnyTimes()
+
+			exist, err := cloud.InstanceExistsByProviderID(context.Background(), "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, exist)
+		})
+	}
+}
+
+func TestNodeLifecycleInInstanceMetadataOnlyMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+	cloud.UseInstanceMetadata = true
+	cloud.InstanceMetadataOnly = true
+	providerID := "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1"
+
+	// no ARM requests are expected by the mock clients
+	exist, err := cloud.InstanceExistsByProviderID(context.Background(), providerID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	shutdown, err := cloud.InstanceShutdownByProviderID(context.Background(), providerID)
+	assert.NoError(t, err)
+	assert.False(t, shutdown)
+}
+
 func TestInstanceExistsByProviderID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -878,6 +969,51 @@ func TestCurrentNodeName(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestIsCurrentInstance(t *testing.T) {
+	for _, tc := range []struct {
+		desc           string
+		vmType         string
+		metadataVMName string
+		expected       bool
+	}{
+		{
+			desc:           "should compare the node name with the vm name for availability set nodes",
+			vmType:         consts.VMTypeStandard,
+			metadataVMName: "vm1",
+			expected:       true,
+		},
+		{
+			desc:           "should compare the node name with the hostname for vmss nodes",
+			vmType:         consts.VMTypeVMSS,
+			metadataVMName: "vmss_0",
+			expected:       true,
+		},
+		{
+			desc:           "should compare the node name with the hostname for vmss flex nodes",
+			vmType:         consts.VMTypeVmssFlex,
+			metadataVMName: "vmssflex_1a2b3c4d",
+			expected:       true,
+		},
+		{
+			desc:           "should return false if the hostname is different",
+			vmType:         consts.VMTypeStandard,
+			metadataVMName: "vm2",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cloud := GetTestCloud(ctrl)
+			cloud.VMType = tc.vmType
+			t.Setenv(nodeNameEnvironmentName, "vm1")
+
+			isCurrent, err := cloud.isCurrentInstance("vm1", tc.metadataVMName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, isCurrent)
+		})
+	}
+}
+
 func TestInstanceMetadata(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -953,6 +1089,43 @@ func TestInstanceMetadata(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedMetadata, *meta)
 	})
+
+	t.Run("instance metadata only", func(t *testing.T) {
+		cloud := GetTestCloud(ctrl)
+		cloud.UseInstanceMetadata = true
+		cloud.InstanceMetadataOnly = true
+		cloud.NodeAddressSource = consts.NodeAddressSourceIMDS
+		// ARM can't be read without the VMSet
+		cloud.VMSet = nil
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer listener.Close()
+		mux := http.NewServeMux()
+		mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"compute":{"name":"vm","vmSize":"Standard_D2s_v3","location":"westus2","zone":"1",`+
+				`"subscriptionId":"subscription","resourceGroupName":"rg"},`+
+				`"network":{"interface":[{"ipv4":{"ipAddress":[{"privateIpAddress":"1.2.3.4"}]}}]}}`)
+		}))
+		go func() {
+			_ = http.Serve(listener, mux)
+		}()
+		cloud.Metadata, err = NewInstanceMetadataService("http://" + listener.Addr().String() + "/")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		meta, err := cloud.InstanceMetadata(context.Background(), &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Standard_D2s_v3", meta.InstanceType)
+		assert.Equal(t, "westus2-1", meta.Zone)
+		assert.Equal(t, "westus2", meta.Region)
+	})
 }
 
 func TestCloud_InstanceExists(t *testing.T) {
@@ -45,6 +45,15 @@ var (
 )
 
 func (az *Cloud) addressGetter(ctx context.Context, nodeName types.NodeName) ([]v1.NodeAddress, error) {
+	if az.isNodeAddressDualStack() {
+		addresses, err := az.getDualStackNodeAddresses(ctx, nodeName)
+		if err != nil {
+			klog.V(2).Infof("NodeAddresses(%s) failed to get dual-stack addresses: %v", nodeName, err)
+			return nil, err
+		}
+		return az.applyNodeAddressOptions(addresses), nil
+	}
+
 	ip, publicIP, err := az.getIPForMachine(ctx, nodeName)
 	if err != nil {
 		klog.V(2).Infof("NodeAddresses(%s) abort backoff: %v", nodeName, err)
@@ -61,7 +70,7 @@ func (az *Cloud) addressGetter(ctx context.Context, nodeName types.NodeName) ([]
 			Address: publicIP,
 		})
 	}
-	return addresses, nil
+	return az.applyNodeAddressOptions(addresses), nil
 }
 
 // NodeAddresses returns the addresses of the specified instance.
@@ -76,7 +85,7 @@ func (az *Cloud) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.N
 		return nil, nil
 	}
 
-	if az.UseInstanceMetadata {
+	if az.UseInstanceMetadata && !strings.EqualFold(az.NodeAddressSource, consts.NodeAddressSourceARM) {
 		metadata, err := az.Metadata.GetMetadata(ctx, azcache.CacheReadTypeDefault)
 		if err != nil {
 			return nil, err
@@ -93,6 +102,9 @@ func (az *Cloud) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.N
 
 		// Not local instance, get addresses from Azure ARM API.
 		if !isLocalInstance {
+			if az.InstanceMetadataOnly {
+				return nil, newRemoteInstanceMetadataError(name)
+			}
 			if az.VMSet != nil {
 				return az.addressGetter(ctx, name)
 			}
@@ -101,7 +113,11 @@ func (az *Cloud) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.N
 			return nil, fmt.Errorf("no credentials provided for Azure cloud provider")
 		}
 
-		return az.getLocalInstanceNodeAddresses(metadata.Network.Interface, string(name))
+		addresses, err := az.getLocalInstanceNodeAddresses(metadata.Network.Interface, string(name))
+		if err != nil {
+			return nil, err
+		}
+		return az.applyNodeAddressOptions(addresses), nil
 	}
 
 	return az.addressGetter(ctx, name)
@@ -181,11 +197,21 @@ func (az *Cloud) NodeAddressesByProviderID(ctx context.Context, providerID strin
 
 // InstanceExistsByProviderID returns true if the instance with the given provider id still exists and is running.
 // If false is returned with no error, the instance will be immediately deleted by the cloud controller manager.
+// It always returns true in the instance metadata only mode, so the nodes of the deleted VMs are never removed.
 func (az *Cloud) InstanceExistsByProviderID(ctx context.Context, providerID string) (bool, error) {
 	if providerID == "" {
 		return false, errNodeNotInitialized
 	}
 
+	if az.InstanceMetadataOnly {
+		klog.V(4).Infof("InstanceExistsByProviderID: assuming node %q exists in the instance metadata only mode", providerID)
+		return true, nil
+	}
+
+	if isArcMachineProviderID(providerID) {
+		return az.arcMachineExistsByProviderID(ctx, providerID)
+	}
+
 	// Returns true for unmanaged nodes because azure cloud provider always assumes them exists.
 	if az.IsNodeUnmanagedByProviderID(providerID) {
 		klog.V(4).Infof("InstanceExistsByProviderID: assuming unmanaged node %q exists", providerID)
@@ -213,14 +239,46 @@ func (az *Cloud) InstanceExistsByProviderID(ctx context.Context, providerID stri
 		return false, err
 	}
 
+	if strings.EqualFold(az.DeallocatedVMPolicy, consts.DeallocatedVMPolicyDeleted) {
+		deallocated, err := az.isNodeDeallocated(ctx, string(name))
+		if err != nil {
+			return false, err
+		}
+		if deallocated {
+			klog.V(2).Infof("InstanceExistsByProviderID: reporting node %q of a deallocated VM as deleted", name)
+			return false, nil
+		}
+	}
+
 	return true, nil
 }
 
+// isNodeDeallocated returns true if the VM of the node is deallocated or being deallocated.
+func (az *Cloud) isNodeDeallocated(ctx context.Context, nodeName string) (bool, error) {
+	powerStatus, err := az.VMSet.GetPowerStatusByNodeName(ctx, nodeName)
+	if err != nil {
+		if errors.Is(err, cloudprovider.InstanceNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	status := strings.ToLower(powerStatus)
+	return status == consts.VMPowerStateDeallocated || status == consts.VMPowerStateDeallocating, nil
+}
+
 // InstanceShutdownByProviderID returns true if the instance is in safe state to detach volumes
 func (az *Cloud) InstanceShutdownByProviderID(ctx context.Context, providerID string) (bool, error) {
 	if providerID == "" {
 		return false, nil
 	}
+	// The Arc agents don't report the power states of the machines.
+	if isArcMachineProviderID(providerID) {
+		return false, nil
+	}
+	// The power states can only be read from ARM.
+	if az.InstanceMetadataOnly {
+		return false, nil
+	}
 	if az.VMSet == nil {
 		// vmSet == nil indicates credentials are not provided.
 		return false, fmt.Errorf("no credentials provided for Azure cloud provider")
@@ -263,12 +321,18 @@ func (az *Cloud) InstanceShutdownByProviderID(ctx context.Context, providerID st
 	return provisioningSucceeded && (status == consts.VMPowerStateStopped || status == consts.VMPowerStateDeallocated || status == consts.VMPowerStateDeallocating), nil
 }
 
+// newRemoteInstanceMetadataError returns the error of reading the metadata of another instance than the
+// local one in the instance metadata only mode, which can only be read from ARM.
+func newRemoteInstanceMetadataError(name types.NodeName) error {
+	return fmt.Errorf("node %q is not the local instance, its metadata can't be read from the instance metadata service and reading ARM is disabled by instanceMetadataOnly", name)
+}
+
 func (az *Cloud) isCurrentInstance(name types.NodeName, metadataVMName string) (bool, error) {
 	var err error
 	nodeName := mapNodeNameToVMName(name)
 
-	// VMSS vmName is not same with hostname, use hostname instead.
-	if az.VMType == consts.VMTypeVMSS {
+	// The names of VMSS and VMSS Flex VMs are not the same with the hostname, use hostname instead.
+	if az.VMType == consts.VMTypeVMSS || az.VMType == consts.VMTypeVmssFlex {
 		metadataVMName, err = os.Hostname()
 		if err != nil {
 			return false, err
@@ -316,6 +380,9 @@ func (az *Cloud) InstanceID(ctx context.Context, name types.NodeName) (string, e
 
 		// Not local instance, get instanceID from Azure ARM API.
 		if !isLocalInstance {
+			if az.InstanceMetadataOnly {
+				return "", newRemoteInstanceMetadataError(name)
+			}
 			if az.VMSet != nil {
 				return az.VMSet.GetInstanceIDByNodeName(ctx, nodeName)
 			}
@@ -403,6 +470,9 @@ func (az *Cloud) InstanceType(ctx context.Context, name types.NodeName) (string,
 			return "", err
 		}
 		if !isLocalInstance {
+			if az.InstanceMetadataOnly {
+				return "", newRemoteInstanceMetadataError(name)
+			}
 			if az.VMSet != nil {
 				return az.VMSet.GetInstanceTypeByNodeName(ctx, string(name))
 			}
@@ -414,6 +484,10 @@ func (az *Cloud) InstanceType(ctx context.Context, name types.NodeName) (string,
 		if metadata.Compute.VMSize != "" {
 			return metadata.Compute.VMSize, nil
 		}
+		if az.InstanceMetadataOnly {
+			_ = az.Metadata.imsCache.Delete(consts.MetadataCacheKey)
+			return "", fmt.Errorf("get empty VM size from instance metadata service")
+		}
 	}
 
 	if az.VMSet == nil {
@@ -24,16 +24,28 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace/attributes"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
 )
 
 var _ cloudprovider.InstancesV2 = (*Cloud)(nil)
 
 // InstanceExists returns true if the instance for the given node exists according to the cloud provider.
 // Use the node.name or node.spec.providerID field to find the node in the cloud provider.
-func (az *Cloud) InstanceExists(ctx context.Context, node *v1.Node) (bool, error) {
+func (az *Cloud) InstanceExists(ctx context.Context, node *v1.Node) (_ bool, err error) {
+	ctx = withARMCaller(ctx, armCallerNode)
 	if node == nil {
 		return false, nil
 	}
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "InstanceExists")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Node(node.Name))
+
 	unmanaged, err := az.IsNodeUnmanaged(node.Name)
 	if err != nil {
 		return false, err
@@ -57,15 +69,30 @@ func (az *Cloud) InstanceExists(ctx context.Context, node *v1.Node) (bool, error
 		}
 	}
 
-	return az.InstanceExistsByProviderID(ctx, providerID)
+	exists, err := az.InstanceExistsByProviderID(ctx, providerID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return true, az.cancelNodeDeletion(ctx, node)
+	}
+	return az.delayNodeDeletion(ctx, node)
 }
 
 // InstanceShutdown returns true if the instance is shutdown according to the cloud provider.
 // Use the node.name or node.spec.providerID field to find the node in the cloud provider.
-func (az *Cloud) InstanceShutdown(ctx context.Context, node *v1.Node) (bool, error) {
+func (az *Cloud) InstanceShutdown(ctx context.Context, node *v1.Node) (_ bool, err error) {
+	ctx = withARMCaller(ctx, armCallerNode)
 	if node == nil {
 		return false, nil
 	}
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "InstanceShutdown")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Node(node.Name))
+
 	unmanaged, err := az.IsNodeUnmanaged(node.Name)
 	if err != nil {
 		return false, err
@@ -95,11 +122,19 @@ func (az *Cloud) InstanceShutdown(ctx context.Context, node *v1.Node) (bool, err
 // InstanceMetadata returns the instance's metadata. The values returned in InstanceMetadata are
 // translated into specific fields in the Node object on registration.
 // Use the node.name or node.spec.providerID field to find the node in the cloud provider.
-func (az *Cloud) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudprovider.InstanceMetadata, error) {
+func (az *Cloud) InstanceMetadata(ctx context.Context, node *v1.Node) (_ *cloudprovider.InstanceMetadata, err error) {
+	ctx = withARMCaller(ctx, armCallerNode)
 	meta := cloudprovider.InstanceMetadata{}
 	if node == nil {
 		return &meta, nil
 	}
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "InstanceMetadata")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Node(node.Name))
+
 	unmanaged, err := az.IsNodeUnmanaged(node.Name)
 	if err != nil {
 		return &meta, err
@@ -108,6 +143,9 @@ func (az *Cloud) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudpro
 		klog.V(4).Infof("InstanceMetadata: omitting unmanaged node %q", node.Name)
 		return &meta, nil
 	}
+	if isArcMachineProviderID(node.Spec.ProviderID) {
+		return az.getArcMachineMetadata(ctx, node.Spec.ProviderID)
+	}
 
 	if node.Spec.ProviderID != "" {
 		meta.ProviderID = node.Spec.ProviderID
@@ -134,7 +172,13 @@ func (az *Cloud) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudpro
 	}
 	meta.NodeAddresses = nodeAddresses
 
-	zone, err := az.GetZoneByNodeName(ctx, types.NodeName(node.Name))
+	var zone cloudprovider.Zone
+	if az.InstanceMetadataOnly {
+		// The instance type has been read from the instance metadata, so the node is the local instance.
+		zone, err = az.GetZone(ctx)
+	} else {
+		zone, err = az.GetZoneByNodeName(ctx, types.NodeName(node.Name))
+	}
 	if err != nil {
 		klog.Errorf("InstanceMetadata: failed to get the node zone of %s: %v", node.Name, err)
 		return &cloudprovider.InstanceMetadata{}, err
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+const (
+	// azureReservedIPCountPerSubnet is the number of addresses Azure reserves in each subnet.
+	azureReservedIPCountPerSubnet = 5
+	// internalLoadBalancerVNetCacheTTL is the TTL of the VNet the subnets of the internal load balancers are
+	// discovered from.
+	internalLoadBalancerVNetCacheTTL = 2 * time.Minute
+)
+
+// newInternalLoadBalancerVNetCache returns the cache of the VNet of the cluster by its resource group, which
+// lists the subnets of the internal load balancers in its tag.
+func (az *Cloud) newInternalLoadBalancerVNetCache() (azcache.Resource, error) {
+	getter := func(ctx context.Context, vnetResourceGroup string) (interface{}, error) {
+		return az.NetworkClientFactory.GetVirtualNetworkClient().Get(ctx, vnetResourceGroup, az.VnetName, nil)
+	}
+	return azcache.NewTimedCache(internalLoadBalancerVNetCacheTTL, getter, az.Config.DisableAPICallCache)
+}
+
+// shouldDiscoverInternalLoadBalancerSubnet returns true if the subnet of the internal
+// load balancer frontend should be discovered from the VNet tag.
+func (az *Cloud) shouldDiscoverInternalLoadBalancerSubnet(subnetName *string) bool {
+	return subnetName == nil && az.InternalLoadBalancerSubnetTagKey != ""
+}
+
+// getSubnetAvailableIPCount returns the number of unused IPv4 addresses in the subnet.
+func getSubnetAvailableIPCount(subnet *armnetwork.Subnet) int64 {
+	if subnet == nil || subnet.Properties == nil {
+		return 0
+	}
+
+	cidrs := make([]*string, 0)
+	if subnet.Properties.AddressPrefix != nil {
+		cidrs = append(cidrs, subnet.Properties.AddressPrefix)
+	}
+	cidrs = append(cidrs, subnet.Properties.AddressPrefixes...)
+
+	var total int64
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(ptr.Deref(cidr, ""))
+		if err != nil {
+			log.Background().WithName("getSubnetAvailableIPCount").Error(err, "Failed to parse the ip cidr", "cidr", ptr.Deref(cidr, ""))
+			continue
+		}
+		if !prefix.Addr().Is4() || prefix.Bits() > 29 {
+			continue
+		}
+		total += int64(1)<<(32-prefix.Bits()) - azureReservedIPCountPerSubnet
+	}
+
+	available := total - int64(len(subnet.Properties.IPConfigurations))
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// discoverInternalLoadBalancerSubnet selects the subnet for the internal load balancer
+// frontend among the subnets listed in the VNet tag. The subnet with the most available
+// addresses wins. It returns nil if the VNet does not have the tag.
+func (az *Cloud) discoverInternalLoadBalancerSubnet(ctx context.Context, vnetResourceGroup string) (*armnetwork.Subnet, error) {
+	logger := log.FromContextOrBackground(ctx).WithName("discoverInternalLoadBalancerSubnet").WithValues("vnet", az.VnetName)
+	cached, err := az.internalLoadBalancerVNetCache.Get(ctx, vnetResourceGroup, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, fmt.Errorf("discoverInternalLoadBalancerSubnet: failed to get vnet %s/%s: %w", vnetResourceGroup, az.VnetName, err)
+	}
+	vnet, _ := cached.(*armnetwork.VirtualNetwork)
+	if vnet == nil || vnet.Properties == nil {
+		return nil, nil
+	}
+
+	var tagValue *string
+	for key, value := range vnet.Tags {
+		if strings.EqualFold(key, az.InternalLoadBalancerSubnetTagKey) {
+			tagValue = value
+			break
+		}
+	}
+	if tagValue == nil {
+		logger.V(4).Info("The vnet does not have the tag", "tag", az.InternalLoadBalancerSubnetTagKey)
+		return nil, nil
+	}
+
+	var (
+		selected          *armnetwork.Subnet
+		selectedAvailable int64
+		candidates        []string
+	)
+	for _, name := range strings.Split(*tagValue, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		candidates = append(candidates, name)
+		for _, subnet := range vnet.Properties.Subnets {
+			if !strings.EqualFold(ptr.Deref(subnet.Name, ""), name) {
+				continue
+			}
+			available := getSubnetAvailableIPCount(subnet)
+			logger.V(4).Info("Available addresses of the subnet", "subnet", name, "available", available)
+			if available > selectedAvailable {
+				selected = subnet
+				selectedAvailable = available
+			}
+			break
+		}
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("discoverInternalLoadBalancerSubnet: none of the subnets %v listed in tag %s of vnet %s has available addresses",
+			candidates, az.InternalLoadBalancerSubnetTagKey, az.VnetName)
+	}
+
+	logger.V(2).Info("Selected the subnet", "subnet", ptr.Deref(selected.Name, ""), "available", selectedAvailable)
+	return selected, nil
+}
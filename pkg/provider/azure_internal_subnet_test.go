@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualnetworkclient/mock_virtualnetworkclient"
+)
+
+func TestGetSubnetAvailableIPCount(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		subnet   *armnetwork.Subnet
+		expected int64
+	}{
+		{
+			desc: "should return 0 for nil subnet",
+		},
+		{
+			desc: "should exclude reserved and used addresses",
+			subnet: &armnetwork.Subnet{
+				Properties: &armnetwork.SubnetPropertiesFormat{
+					AddressPrefix:    ptr.To("10.0.0.0/28"),
+					IPConfigurations: []*armnetwork.IPConfiguration{{}, {}},
+				},
+			},
+			expected: 9,
+		},
+		{
+			desc: "should ignore IPv6 prefixes",
+			subnet: &armnetwork.Subnet{
+				Properties: &armnetwork.SubnetPropertiesFormat{
+					AddressPrefixes: []*string{ptr.To("10.0.0.0/24"), ptr.To("fd00::/64")},
+				},
+			},
+			expected: 251,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getSubnetAvailableIPCount(tc.subnet))
+		})
+	}
+}
+
+func TestDiscoverInternalLoadBalancerSubnet(t *testing.T) {
+	fullSubnet := &armnetwork.Subnet{
+		Name: ptr.To("full"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix:    ptr.To("10.0.0.0/29"),
+			IPConfigurations: []*armnetwork.IPConfiguration{{}, {}, {}},
+		},
+	}
+	smallSubnet := &armnetwork.Subnet{
+		Name:       ptr.To("small"),
+		Properties: &armnetwork.SubnetPropertiesFormat{AddressPrefix: ptr.To("10.0.1.0/28")},
+	}
+	largeSubnet := &armnetwork.Subnet{
+		Name:       ptr.To("large"),
+		Properties: &armnetwork.SubnetPropertiesFormat{AddressPrefix: ptr.To("10.0.2.0/24")},
+	}
+
+	for _, tc := range []struct {
+		desc           string
+		tags           map[string]*string
+		expectedSubnet *armnetwork.Subnet
+		expectedErr    bool
+	}{
+		{
+			desc: "should return nil if the vnet does not have the tag",
+		},
+		{
+			desc:           "should select the subnet with the most available addresses",
+			tags:           map[string]*string{"Kubernetes.io-internal-lb-subnets": ptr.To("small, large,unknown")},
+			expectedSubnet: largeSubnet,
+		},
+		{
+			desc:        "should return an error if no listed subnet has available addresses",
+			tags:        map[string]*string{"kubernetes.io-internal-lb-subnets": ptr.To("full")},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			az.InternalLoadBalancerSubnetTagKey = "kubernetes.io-internal-lb-subnets"
+
+			vnetClient := az.NetworkClientFactory.GetVirtualNetworkClient().(*mock_virtualnetworkclient.MockInterface)
+			vnetClient.EXPECT().Get(gomock.Any(), "rg", "vnet", gomock.Any()).Return(&armnetwork.VirtualNetwork{
+				Tags: tc.tags,
+				Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+					Subnets: []*armnetwork.Subnet{fullSubnet, smallSubnet, largeSubnet},
+				},
+			}, nil)
+
+			subnet, err := az.discoverInternalLoadBalancerSubnet(context.TODO(), "rg")
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedSubnet, subnet)
+
+			// The vnet is read from the cache afterwards.
+			subnet, err = az.discoverInternalLoadBalancerSubnet(context.TODO(), "rg")
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedSubnet, subnet)
+		})
+	}
+}
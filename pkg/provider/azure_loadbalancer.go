@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/cloud-provider/api"
 	servicehelpers "k8s.io/cloud-provider/service/helpers"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -90,10 +91,15 @@ func (az *Cloud) existsPip(ctx context.Context, clusterName string, service *v1.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager.
 // TODO: Break this up into different interfaces (LB, etc) when we have more than one type of service
 func (az *Cloud) GetLoadBalancer(ctx context.Context, clusterName string, service *v1.Service) (status *v1.LoadBalancerStatus, exists bool, err error) {
+	ctx = withARMCaller(ctx, armCallerService)
 	const Operation = "GetLoadBalancer"
 
 	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), Operation)
-	defer func() { span.Observe(ctx, err) }()
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Service(service)...)
 
 	logger := log.FromContextOrBackground(ctx).WithName(Operation).WithValues("service", service.Name)
 	ctx = log.NewContext(ctx, logger)
@@ -137,6 +143,22 @@ func (az *Cloud) reconcileService(ctx context.Context, clusterName string, servi
 
 	logger.V(2).Info("Start reconciling Service", "lb", az.GetLoadBalancerName(ctx, clusterName, service))
 
+	if err := az.resumeLBOperation(ctx, service); err != nil {
+		return nil, err
+	}
+
+	for _, notice := range az.getLBRuleNotices(service) {
+		logger.V(2).Info(notice.message, "reason", notice.reason)
+		az.Event(service, notice.eventType, notice.reason, notice.message)
+	}
+
+	eligibleNodes, requeueAfter := az.filterNodesEligibleForLoadBalancer(nodes)
+	az.nodeEligibilityRequeuer.track(clusterName, service, nodes, requeueAfter)
+	nodes, err := az.filterNodesInLoadBalancerDedicatedHostGroups(ctx, eligibleNodes)
+	if err != nil {
+		logger.Error(err, "Failed to filter the nodes by dedicated host groups")
+		return nil, err
+	}
 	lb, needRetry, err := az.reconcileLoadBalancer(ctx, clusterName, service, nodes, true /* wantLb */)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile LoadBalancer")
@@ -185,9 +207,21 @@ func (az *Cloud) reconcileService(ctx context.Context, clusterName string, servi
 		return nil, err
 	}
 
+	regionalIngress, err := az.reconcileRegionalFrontends(ctx, clusterName, service, nodes, true /* wantLb */)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile regional frontends")
+		return nil, err
+	}
+	if len(regionalIngress) > 0 {
+		if lbStatus == nil {
+			lbStatus = &v1.LoadBalancerStatus{}
+		}
+		lbStatus.Ingress = append(lbStatus.Ingress, regionalIngress...)
+	}
+
 	lbName := strings.ToLower(ptr.Deref(lb.Name, ""))
 	key := strings.ToLower(getServiceName(service))
-	if az.UseMultipleStandardLoadBalancers() && isLocalService(service) {
+	if az.UseLocalServiceBackendPools() && isLocalService(service) {
 		az.localServiceNameToServiceInfoMap.Store(key, newServiceInfo(getServiceIPFamily(service), lbName))
 		// There are chances that the endpointslice changes after EnsureHostsInPool, so
 		// need to check endpointslice for a second time.
@@ -213,16 +247,21 @@ func (az *Cloud) reconcileService(ctx context.Context, clusterName string, servi
 // polling at a fixed rate is preferred over backing off exponentially in
 // order to minimize latency.
 func (az *Cloud) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (lbStatus *v1.LoadBalancerStatus, err error) {
+	ctx = withARMCaller(ctx, armCallerService)
 	// When a client updates the internal load balancer annotation,
 	// the service may be switched from an internal LB to a public one, or vice versa.
 	// Here we'll firstly ensure service do not lie in the opposite LB.
 	const Operation = "EnsureLoadBalancer"
 
 	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), Operation, attributes.FeatureOfService(service)...)
-	defer func() { span.Observe(ctx, err) }()
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Service(service)...)
 
 	// Serialize service reconcile process
-	az.serviceReconcileLock.Lock()
+	az.serviceReconcileLock.Lock(reconcilePriorityService)
 	defer az.serviceReconcileLock.Unlock()
 
 	var (
@@ -278,9 +317,14 @@ func (az *Cloud) EnsureLoadBalancer(ctx context.Context, clusterName string, ser
 		}
 	}()
 
-	lbStatus, err = az.reconcileService(ctx, clusterName, service, nodes)
+	lbStatus, err = az.reconcileServiceWithBackoff(ctx, clusterName, service, nodes)
+	if delay, pending := az.probeTransitions.pending(service); err == nil && pending {
+		// the rules are switched to the new health probes by the requeued reconciliation
+		err = api.NewRetryError(fmt.Sprintf("waiting for the new health probes of service %s to probe the backends", svcName), delay)
+	}
+	az.serviceProvisioningTracker.observe(service, lbStatus, err)
 	if err != nil {
-		return nil, err
+		return nil, az.handleManagementLockError(service, "EnsureLoadBalancer", errutils.WithARMRequestIDs(err))
 	}
 
 	isOperationSucceeded = true
@@ -310,14 +354,16 @@ func (az *Cloud) getLatestService(serviceName string, deepcopy bool) (*v1.Servic
 // parameters as read-only and not modify them.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (az *Cloud) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) error {
+	ctx = withARMCaller(ctx, armCallerService)
 	const Operation = "UpdateLoadBalancer"
 
 	var err error
 	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), Operation, attributes.FeatureOfService(service)...)
 	defer func() { span.Observe(ctx, err) }()
+	span.Inner().SetAttributes(attributes.Service(service)...)
 
 	// Serialize service reconcile process
-	az.serviceReconcileLock.Lock()
+	az.serviceReconcileLock.Lock(reconcilePriorityNodeSync)
 	defer az.serviceReconcileLock.Unlock()
 
 	var (
@@ -348,6 +394,12 @@ func (az *Cloud) UpdateLoadBalancer(ctx context.Context, clusterName string, ser
 		return nil
 	}
 
+	if az.nodeScaleWaveTracker.deferUpdate(ctx, clusterName, service, nodes) {
+		isOperationSucceeded = true
+		logger.V(2).Info("Deferring until the scale wave of the nodes ends")
+		return nil
+	}
+
 	shouldUpdateLB, err := az.shouldUpdateLoadBalancer(ctx, clusterName, service, nodes)
 	if err != nil {
 		return err
@@ -394,9 +446,12 @@ func (az *Cloud) UpdateLoadBalancer(ctx context.Context, clusterName string, ser
 		}()
 	}
 
+	// The node updates are not subject to the per-service backoff, so that the backend
+	// pools of a parked service still follow the nodes.
 	_, err = az.reconcileService(ctx, clusterName, service, nodes)
 	if err != nil {
-		return err
+		err = errutils.WithARMRequestIDs(err)
+		return az.handleManagementLockError(service, "UpdateLoadBalancer", err)
 	}
 
 	isOperationSucceeded = true
@@ -412,13 +467,19 @@ func (az *Cloud) UpdateLoadBalancer(ctx context.Context, clusterName string, ser
 // Implementations must treat the *v1.Service parameter as read-only and not modify it.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (az *Cloud) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) (err error) {
+	ctx = withARMCaller(ctx, armCallerService)
 	const Operation = "EnsureLoadBalancerDeleted"
 
 	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), Operation, attributes.FeatureOfService(service)...)
-	defer func() { span.Observe(ctx, err) }()
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+		err = az.handleManagementLockError(service, Operation, err)
+	}()
+	span.Inner().SetAttributes(attributes.Service(service)...)
 
 	// Serialize service reconcile process
-	az.serviceReconcileLock.Lock()
+	az.serviceReconcileLock.Lock(reconcilePriorityDeletion)
 	defer az.serviceReconcileLock.Unlock()
 
 	var (
@@ -504,10 +565,18 @@ func (az *Cloud) EnsureLoadBalancerDeleted(ctx context.Context, clusterName stri
 		return err
 	}
 
-	if az.UseMultipleStandardLoadBalancers() && isLocalService(service) {
+	if _, err = az.reconcileRegionalFrontends(ctx, clusterName, service, nil, false /* wantLb */); err != nil {
+		return err
+	}
+
+	if az.UseLocalServiceBackendPools() && isLocalService(service) {
 		key := strings.ToLower(svcName)
 		az.localServiceNameToServiceInfoMap.Delete(key)
 	}
+	az.serviceReconcileBackoff.forget(service)
+	az.nodeEligibilityRequeuer.forget(service)
+	az.serviceProvisioningTracker.forget(service)
+	az.probeTransitions.forget(service)
 
 	isOperationSucceeded = true
 
@@ -856,7 +925,7 @@ func (az *Cloud) getServiceLoadBalancer(
 				ptr.Deref(existingLB.Name, ""),
 			)
 
-			if isLocalService(service) && az.UseMultipleStandardLoadBalancers() {
+			if az.useServiceBackendPool(service) {
 				// No need for the endpoint slice informer to update the backend pool
 				// for the service because the main loop will delete the old backend pool
 				// and create a new one in the new load balancer.
@@ -1060,6 +1129,9 @@ func (az *Cloud) getServiceLoadBalancerStatus(ctx context.Context, service *v1.S
 			klog.V(2).Infof("getServiceLoadBalancerStatus gets ingress IP %q from frontendIPConfiguration %q for service %q", ptr.Deref(lbIP, ""), ptr.Deref(ipConfiguration.Name, ""), serviceName)
 
 			lbIngresses = append(lbIngresses, v1.LoadBalancerIngress{IP: ptr.Deref(lbIP, "")})
+			if _, isAdditional := az.getAdditionalFrontendIPConfigPIPName(service, ipConfiguration); isAdditional {
+				continue
+			}
 			lbIPsPrimaryPIPs = append(lbIPsPrimaryPIPs, ptr.Deref(lbIP, ""))
 			fipConfigs = append(fipConfigs, ipConfiguration)
 		}
@@ -1164,6 +1236,11 @@ func (az *Cloud) ensurePublicIPExists(ctx context.Context, service *v1.Service,
 				return nil, err
 			}
 		}
+		if isUserAssignedPIP {
+			if err = az.adoptUserAssignedPublicIP(service, pipResourceGroup, pip, isIPv6); err != nil {
+				return nil, err
+			}
+		}
 
 		if pip.Tags == nil {
 			pip.Tags = make(map[string]*string)
@@ -1181,6 +1258,10 @@ func (az *Cloud) ensurePublicIPExists(ctx context.Context, service *v1.Service,
 			}
 		}
 
+		if owns && !isUserAssignedPIP && az.UseStandardLoadBalancer() && !az.HasExtendedLocation() {
+			az.checkPIPZones(ctx, service, pip)
+		}
+
 		// return if pip exist and dns label is the same
 		if strings.EqualFold(getDomainNameLabel(pip), domainNameLabel) {
 			if existingServiceName := getServiceFromPIPDNSTags(pip.Tags); existingServiceName != "" && strings.EqualFold(existingServiceName, serviceName) {
@@ -1258,7 +1339,7 @@ func (az *Cloud) ensurePublicIPExists(ctx context.Context, service *v1.Service,
 			// skip adding zone info since edge zones doesn't support multiple availability zones.
 			if !az.HasExtendedLocation() {
 				// only add zone information for the new standard pips
-				zones, err := az.getRegionZonesBackoff(ctx, ptr.Deref(pip.Location, ""))
+				zones, err := az.getServicePIPZones(ctx, service, ptr.Deref(pip.Location, ""))
 				if err != nil {
 					return nil, err
 				}
@@ -1698,6 +1779,9 @@ func (az *Cloud) findFrontendIPConfigsOfService(
 	fipsOfServiceMap := map[bool]*armnetwork.FrontendIPConfiguration{}
 	for _, config := range fipConfigs {
 		config := config
+		if _, isAdditional := az.getAdditionalFrontendIPConfigPIPName(service, config); isAdditional {
+			continue
+		}
 		owns, _, fipIPVersion := az.serviceOwnsFrontendIP(ctx, config, service)
 		if owns {
 			var fipIsIPv6 bool
@@ -1762,7 +1846,7 @@ func (az *Cloud) reconcileMultipleStandardLoadBalancerConfigurations(
 	rulePrefixToSVCNameMap := make(map[string]string)
 	for _, svc := range svcs.Items {
 		svc := svc
-		if strings.EqualFold(string(svc.Spec.Type), string(v1.ServiceTypeLoadBalancer)) {
+		if strings.EqualFold(string(svc.Spec.Type), string(v1.ServiceTypeLoadBalancer)) && az.isServiceOfLoadBalancerClasses(&svc) {
 			prefix := az.GetLoadBalancerName(ctx, "", &svc)
 			svcName := getServiceName(&svc)
 			rulePrefixToSVCNameMap[strings.ToLower(prefix)] = svcName
@@ -1806,7 +1890,20 @@ func (az *Cloud) reconcileMultipleStandardLoadBalancerConfigurations(
 // This also reconciles the Service's Ports with the LoadBalancer config.
 // This entails adding rules/probes for expected Ports and removing stale rules/ports.
 // nodes only used if wantLb is true
+// The load balancer is read and reconciled again if it's modified concurrently.
 func (az *Cloud) reconcileLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node, wantLb bool) (*armnetwork.LoadBalancer, bool /*needRetry*/, error) {
+	var (
+		lb        *armnetwork.LoadBalancer
+		needRetry bool
+	)
+	err := retryOnETagConflict(ctx, fmt.Sprintf("load balancer of service %s", getServiceName(service)), func() (err error) {
+		lb, needRetry, err = az.reconcileLoadBalancerOnce(ctx, clusterName, service, nodes, wantLb)
+		return err
+	})
+	return lb, needRetry, err
+}
+
+func (az *Cloud) reconcileLoadBalancerOnce(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node, wantLb bool) (*armnetwork.LoadBalancer, bool /*needRetry*/, error) {
 	isBackendPoolPreConfigured := az.isBackendPoolPreConfigured(service)
 	serviceName := getServiceName(service)
 	klog.V(2).Infof("reconcileLoadBalancer for service(%s) - wantLb(%t): started", serviceName, wantLb)
@@ -1822,9 +1919,10 @@ func (az *Cloud) reconcileLoadBalancer(ctx context.Context, clusterName string,
 	}
 
 	// Delete backend pools for local service if:
-	// 1. the cluster is migrating from multi-slb to single-slb,
-	// 2. the service is changed from local to cluster.
-	if !az.UseMultipleStandardLoadBalancers() || !isLocalService(service) {
+	// 1. the cluster stops using local service backend pools, e.g., migrating from multi-slb to single-slb,
+	// 2. the service is changed from local to cluster,
+	// unless the service keeps a backend pool of its own because it is pinned to a zone.
+	if !az.useServiceBackendPool(service) {
 		existingLBs, err = az.cleanupLocalServiceBackendPool(ctx, service, nodes, existingLBs, clusterName)
 		if err != nil {
 			klog.Errorf("reconcileLoadBalancer: failed to cleanup local service backend pool for service %q, error: %s", serviceName, err.Error())
@@ -1890,54 +1988,15 @@ func (az *Cloud) reconcileLoadBalancer(ctx context.Context, clusterName string,
 	}
 
 	// update probes/rules
-	for _, ownedFIPConfig := range ownedFIPConfigs {
-		if ownedFIPConfig == nil {
-			continue
-		}
-		if ownedFIPConfig.ID == nil {
-			return nil, false, fmt.Errorf("reconcileLoadBalancer for service (%s)(%t): nil ID for frontend IP config", serviceName, wantLb)
-		}
-
-		var isIPv6 bool
-		var err error
-		_, _, fipIPVersion := az.serviceOwnsFrontendIP(ctx, ownedFIPConfig, service)
-		if fipIPVersion != nil {
-			isIPv6 = fipIPVersion == to.Ptr(armnetwork.IPVersionIPv6)
-		} else {
-			if isIPv6, err = az.isFIPIPv6(service, ownedFIPConfig); err != nil {
-				return nil, false, err
-			}
-		}
-		lbFrontendIPConfigIDs[isIPv6] = *ownedFIPConfig.ID
-	}
-
-	var expectedProbes []*armnetwork.Probe
-	var expectedRules []*armnetwork.LoadBalancingRule
-	getExpectedLBRule := func(isIPv6 bool) error {
-		expectedProbesSingleStack, expectedRulesSingleStack, err := az.getExpectedLBRules(service, lbFrontendIPConfigIDs[isIPv6], lbBackendPoolIDs[isIPv6], lbName, isIPv6)
-		if err != nil {
-			return err
-		}
-		expectedProbes = append(expectedProbes, expectedProbesSingleStack...)
-		expectedRules = append(expectedRules, expectedRulesSingleStack...)
-		return nil
+	if err := az.setOwnedFrontendIPConfigIDs(ctx, service, serviceName, wantLb, ownedFIPConfigs, lbFrontendIPConfigIDs); err != nil {
+		return nil, false, err
 	}
-	v4Enabled, v6Enabled := getIPFamiliesEnabled(service)
-	if wantLb && v4Enabled {
-		if err = az.checkLoadBalancerResourcesConflicts(lb, lbFrontendIPConfigIDs[false], service); err != nil {
-			return nil, false, err
-		}
-		if err := getExpectedLBRule(consts.IPVersionIPv4); err != nil {
-			return nil, false, err
-		}
+	expectedProbes, expectedRules, err := az.getExpectedLBRulesAndProbesForService(lb, service, lbName, wantLb, lbFrontendIPConfigIDs, lbBackendPoolIDs)
+	if err != nil {
+		return nil, false, err
 	}
-	if wantLb && v6Enabled {
-		if err = az.checkLoadBalancerResourcesConflicts(lb, lbFrontendIPConfigIDs[true], service); err != nil {
-			return nil, false, err
-		}
-		if err := getExpectedLBRule(consts.IPVersionIPv6); err != nil {
-			return nil, false, err
-		}
+	if wantLb {
+		expectedProbes, expectedRules = az.reconcileProbeTransition(lb, service, expectedProbes, expectedRules)
 	}
 
 	if changed := az.reconcileLBProbes(lb, service, serviceName, wantLb, expectedProbes); changed {
@@ -2008,6 +2067,15 @@ func (az *Cloud) reconcileLoadBalancer(ctx context.Context, clusterName string,
 		}
 	}
 
+	// The backend pool of a local or zonal service is only used by the service, and it is not removed
+	// with the cluster backend pools, so it is deleted with the service once its rules are removed.
+	if !wantLb {
+		if err := az.deleteServiceBackendPools(ctx, service, lb); err != nil {
+			klog.ErrorS(err, "reconcileLoadBalancer: failed to delete the backend pools of the service", "service", serviceName, "lb", lbName)
+			return nil, false, err
+		}
+	}
+
 	if wantLb && nodes != nil && !isBackendPoolPreConfigured {
 		// Add the machines to the backend pool if they're not already
 		vmSetName := az.mapLoadBalancerNameToVMSet(lbName, clusterName)
@@ -2057,6 +2125,8 @@ func (az *Cloud) reconcileBackendPoolHosts(
 ) (*armnetwork.LoadBalancer, error) {
 	var res *armnetwork.LoadBalancer
 	res = currentLB
+	// The VMSS VMs joining the backend pools are updated once for all the backend pools.
+	ctx, vmssVMUpdates := withVMSSVMUpdateBatch(ctx)
 	for _, lb := range lbs {
 		lb := lb
 		lbName := ptr.Deref(lb.Name, "")
@@ -2083,6 +2153,9 @@ func (az *Cloud) reconcileBackendPoolHosts(
 			res = lb
 		}
 	}
+	if err := vmssVMUpdates.flush(ctx); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
@@ -2424,6 +2497,88 @@ func (az *Cloud) reconcileMultipleStandardLoadBalancerConfigurationStatus(wantLb
 	}
 }
 
+// setOwnedFrontendIPConfigIDs sets the IDs of the frontend IP configurations owned by the service
+// to lbFrontendIPConfigIDs by IP family.
+func (az *Cloud) setOwnedFrontendIPConfigIDs(
+	ctx context.Context,
+	service *v1.Service,
+	serviceName string,
+	wantLb bool,
+	ownedFIPConfigs []*armnetwork.FrontendIPConfiguration,
+	lbFrontendIPConfigIDs map[bool]string,
+) error {
+	for _, ownedFIPConfig := range ownedFIPConfigs {
+		if ownedFIPConfig == nil {
+			continue
+		}
+		if ownedFIPConfig.ID == nil {
+			return fmt.Errorf("reconcileLoadBalancer for service (%s)(%t): nil ID for frontend IP config", serviceName, wantLb)
+		}
+
+		var isIPv6 bool
+		var err error
+		_, _, fipIPVersion := az.serviceOwnsFrontendIP(ctx, ownedFIPConfig, service)
+		if fipIPVersion != nil {
+			isIPv6 = fipIPVersion == to.Ptr(armnetwork.IPVersionIPv6)
+		} else {
+			if isIPv6, err = az.isFIPIPv6(service, ownedFIPConfig); err != nil {
+				return err
+			}
+		}
+		lbFrontendIPConfigIDs[isIPv6] = *ownedFIPConfig.ID
+	}
+	return nil
+}
+
+// getExpectedLBRulesAndProbesForService returns the expected probes and load balancing rules
+// of the service on the load balancer for all enabled IP families.
+func (az *Cloud) getExpectedLBRulesAndProbesForService(
+	lb *armnetwork.LoadBalancer,
+	service *v1.Service,
+	lbName string,
+	wantLb bool,
+	lbFrontendIPConfigIDs, lbBackendPoolIDs map[bool]string,
+) ([]*armnetwork.Probe, []*armnetwork.LoadBalancingRule, error) {
+	var expectedProbes []*armnetwork.Probe
+	var expectedRules []*armnetwork.LoadBalancingRule
+	if !wantLb {
+		return expectedProbes, expectedRules, nil
+	}
+
+	getExpectedLBRule := func(isIPv6 bool) error {
+		expectedProbesSingleStack, expectedRulesSingleStack, err := az.getExpectedLBRules(service, lbFrontendIPConfigIDs[isIPv6], lbBackendPoolIDs[isIPv6], lbName, isIPv6)
+		if err != nil {
+			return err
+		}
+		expectedProbes = append(expectedProbes, expectedProbesSingleStack...)
+		expectedRules = append(expectedRules, expectedRulesSingleStack...)
+		return nil
+	}
+	v4Enabled, v6Enabled := getIPFamiliesEnabled(service)
+	if v4Enabled {
+		if err := az.checkLoadBalancerResourcesConflicts(lb, lbFrontendIPConfigIDs[false], service); err != nil {
+			return nil, nil, err
+		}
+		if err := getExpectedLBRule(consts.IPVersionIPv4); err != nil {
+			return nil, nil, err
+		}
+		additionalRules, err := az.getExpectedAdditionalFrontendLBRules(lb, service, expectedRules)
+		if err != nil {
+			return nil, nil, err
+		}
+		expectedRules = append(expectedRules, additionalRules...)
+	}
+	if v6Enabled {
+		if err := az.checkLoadBalancerResourcesConflicts(lb, lbFrontendIPConfigIDs[true], service); err != nil {
+			return nil, nil, err
+		}
+		if err := getExpectedLBRule(consts.IPVersionIPv6); err != nil {
+			return nil, nil, err
+		}
+	}
+	return expectedProbes, expectedRules, nil
+}
+
 func (az *Cloud) reconcileLBProbes(lb *armnetwork.LoadBalancer, service *v1.Service, serviceName string, wantLb bool, expectedProbes []*armnetwork.Probe) bool {
 	expectedProbes, _ = az.keepSharedProbe(service, *lb, expectedProbes, wantLb)
 
@@ -2578,9 +2733,6 @@ func (az *Cloud) reconcileFrontendIPConfigs(
 
 		if isInternal {
 			subnetName := getInternalSubnet(service)
-			if subnetName == nil {
-				subnetName = &az.SubnetName
-			}
 
 			vnetResourceGroup := ""
 			if len(az.VnetResourceGroup) > 0 {
@@ -2589,11 +2741,24 @@ func (az *Cloud) reconcileFrontendIPConfigs(
 				vnetResourceGroup = az.ResourceGroup
 			}
 
-			subnet, err = az.subnetRepo.Get(ctx, vnetResourceGroup, az.VnetName, *subnetName)
-			if existsSubnet, err = errutils.CheckResourceExistsFromAzcoreError(err); !existsSubnet && err != nil {
-				return nil, toDeleteConfigs, false, err
-			} else if !existsSubnet {
-				return nil, toDeleteConfigs, false, fmt.Errorf("ensure(%s): lb(%s) - failed to get subnet: %s/%s", serviceName, lbName, az.VnetName, *subnetName)
+			if az.shouldDiscoverInternalLoadBalancerSubnet(subnetName) {
+				subnet, err = az.discoverInternalLoadBalancerSubnet(ctx, vnetResourceGroup)
+				if err != nil {
+					return nil, toDeleteConfigs, false, err
+				}
+				existsSubnet = subnet != nil
+			}
+			if subnetName == nil {
+				subnetName = &az.SubnetName
+			}
+
+			if !existsSubnet {
+				subnet, err = az.subnetRepo.Get(ctx, vnetResourceGroup, az.VnetName, *subnetName)
+				if existsSubnet, err = errutils.CheckResourceExistsFromAzcoreError(err); !existsSubnet && err != nil {
+					return nil, toDeleteConfigs, false, err
+				} else if !existsSubnet {
+					return nil, toDeleteConfigs, false, fmt.Errorf("ensure(%s): lb(%s) - failed to get subnet: %s/%s", serviceName, lbName, az.VnetName, *subnetName)
+				}
 			}
 		}
 
@@ -2605,6 +2770,19 @@ func (az *Cloud) reconcileFrontendIPConfigs(
 				continue
 			}
 			klog.V(4).Infof("reconcileFrontendIPConfigs for service (%s): checking owned frontend IP configuration %s", serviceName, ptr.Deref(config.Name, ""))
+			if pipName, isAdditional := az.getAdditionalFrontendIPConfigPIPName(service, config); isAdditional {
+				isFipChanged, err := az.isAdditionalFrontendIPChanged(ctx, service, config, pipName)
+				if err != nil {
+					return nil, toDeleteConfigs, false, err
+				}
+				if isFipChanged {
+					klog.V(2).Infof("reconcileLoadBalancer for service (%s)(%t): lb frontendconfig(%s) - dropping", serviceName, wantLb, *config.Name)
+					toDeleteConfigs = append(toDeleteConfigs, newConfigs[i])
+					newConfigs = append(newConfigs[:i], newConfigs[i+1:]...)
+					dirtyConfigs = true
+				}
+				continue
+			}
 			var isIPv6 bool
 			var err error
 			if fipIPVersion != nil {
@@ -2726,6 +2904,15 @@ func (az *Cloud) reconcileFrontendIPConfigs(
 				return nil, toDeleteConfigs, false, err
 			}
 		}
+
+		var additionalFIPChanged bool
+		newConfigs, additionalFIPChanged, err = az.ensureAdditionalFrontendIPConfigs(ctx, clusterName, service, lb, newConfigs)
+		if err != nil {
+			return nil, toDeleteConfigs, false, err
+		}
+		if additionalFIPChanged {
+			dirtyConfigs = true
+		}
 	}
 
 	if dirtyConfigs {
@@ -2865,6 +3052,72 @@ func lbRuleConflictsWithPort(rule *armnetwork.LoadBalancingRule, frontendIPConfi
 		*rule.Properties.FrontendPort == port.Port
 }
 
+// hasSCTPPort returns true if any port of the service uses SCTP.
+func hasSCTPPort(service *v1.Service) bool {
+	for _, port := range service.Spec.Ports {
+		if port.Protocol == v1.ProtocolSCTP {
+			return true
+		}
+	}
+	return false
+}
+
+// isHAModeRuleSupported returns true if the load balancer of the service supports the HA ports rule,
+// which is only supported on the standard load balancer in internal mode.
+func (az *Cloud) isHAModeRuleSupported(service *v1.Service) bool {
+	return consts.IsK8sServiceUsingInternalLoadBalancer(service) && az.UseStandardLoadBalancer()
+}
+
+// lbRuleNotice is a notice about the load balancing rules of a service, which are built
+// differently from what the service asks for.
+type lbRuleNotice struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// getLBRuleNotices returns the notices about the load balancing rules built by getExpectedLBRules.
+// They are reported by events when the service is reconciled, and by the lint of the service.
+func (az *Cloud) getLBRuleNotices(service *v1.Service) []lbRuleNotice {
+	var notices []lbRuleNotice
+	if consts.IsK8sServiceHasHAModeEnabled(service) && !az.isHAModeRuleSupported(service) {
+		notices = append(notices, lbRuleNotice{
+			eventType: v1.EventTypeWarning,
+			reason:    "UnsupportedHighAvailabilityPorts",
+			message: fmt.Sprintf("annotation %s is only supported on standard loadbalancer in internal mode, ignoring it",
+				consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts),
+		})
+	}
+	if !hasSCTPPort(service) {
+		return notices
+	}
+
+	if az.isHAModeRuleSupported(service) {
+		if !consts.IsK8sServiceHasHAModeEnabled(service) {
+			notices = append(notices, lbRuleNotice{
+				eventType: v1.EventTypeNormal,
+				reason:    "SCTPUsingHighAvailabilityPorts",
+				message:   "SCTP ports are served by a high availability ports rule forwarding all the ports of the service",
+			})
+		}
+		return notices
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Protocol != v1.ProtocolSCTP {
+			continue
+		}
+		if isNoLBRuleRequired, _ := consts.IsLBRuleOnK8sServicePortDisabled(service.Annotations, port.Port); isNoLBRuleRequired {
+			continue
+		}
+		notices = append(notices, lbRuleNotice{
+			eventType: v1.EventTypeWarning,
+			reason:    "UnsupportedSCTPPort",
+			message:   fmt.Sprintf("SCTP port %d is only supported on standard loadbalancer in internal mode, skipping it", port.Port),
+		})
+	}
+	return notices
+}
+
 // buildLBRules
 // for following SKU: basic loadbalancer vs standard load balancer
 // for following scenario: internal vs external
@@ -2904,18 +3157,27 @@ func (az *Cloud) getExpectedLBRules(
 	}
 
 	var useSharedProbe bool
-	if az.useSharedLoadBalancerHealthProbeMode() &&
-		!strings.EqualFold(string(service.Spec.ExternalTrafficPolicy), string(v1.ServiceExternalTrafficPolicyLocal)) {
-		nodeEndpointHealthprobe = az.buildClusterServiceSharedProbe()
-		useSharedProbe = true
+	if !strings.EqualFold(string(service.Spec.ExternalTrafficPolicy), string(v1.ServiceExternalTrafficPolicyLocal)) {
+		clusterServiceProbe, isSharedProbe, err := az.buildClusterServiceHealthProbe(service, isIPv6)
+		if err != nil {
+			return nil, nil, err
+		}
+		if clusterServiceProbe != nil {
+			nodeEndpointHealthprobe = clusterServiceProbe
+			useSharedProbe = isSharedProbe
+		}
 	}
 
 	// In HA mode, lb forward traffic of all port to backend
 	// HA mode is only supported on standard loadbalancer SKU in internal mode
-	if consts.IsK8sServiceUsingInternalLoadBalancer(service) &&
-		az.UseStandardLoadBalancer() &&
-		consts.IsK8sServiceHasHAModeEnabled(service) {
-
+	// The ignored annotation is reported by getLBRuleNotices.
+	useHAModeRule := consts.IsK8sServiceHasHAModeEnabled(service) && az.isHAModeRuleSupported(service)
+	// Azure LB has no SCTP rules, the SCTP traffic can only be forwarded by the HA ports rule,
+	// which serves all the ports of the service.
+	if !useHAModeRule && hasSCTPPort(service) && az.isHAModeRuleSupported(service) {
+		useHAModeRule = true
+	}
+	if useHAModeRule {
 		lbRuleName := az.getloadbalancerHAmodeRuleName(service, isIPv6)
 		klog.V(2).Infof("getExpectedLBRules lb name (%s) rule name (%s)", lbName, lbRuleName)
 
@@ -2969,8 +3231,9 @@ func (az *Cloud) getExpectedLBRules(
 				klog.V(2).Infof("getExpectedLBRules lb name (%s) rule name (%s) no lb rule required", lbName, lbRuleName)
 				continue
 			}
-			if port.Protocol == v1.ProtocolSCTP && !(az.UseStandardLoadBalancer() && consts.IsK8sServiceUsingInternalLoadBalancer(service)) {
-				return expectedProbes, expectedRules, fmt.Errorf("SCTP is only supported on standard loadbalancer in internal mode")
+			// the skipped SCTP ports are reported by getLBRuleNotices
+			if port.Protocol == v1.ProtocolSCTP {
+				continue
 			}
 
 			transportProto, _, _, err := getProtocolsFromKubernetesProtocol(port.Protocol)
@@ -3010,7 +3273,7 @@ func (az *Cloud) getExpectedLBRules(
 					}
 				}
 			}
-			if consts.IsK8sServiceDisableLoadBalancerFloatingIP(service) {
+			if az.isFloatingIPDisabled(service) {
 				props.BackendPort = ptr.To(port.NodePort)
 				props.EnableFloatingIP = ptr.To(false)
 			}
@@ -3019,11 +3282,68 @@ func (az *Cloud) getExpectedLBRules(
 				Properties: props,
 			})
 		}
+		if isMixedProtocolService(service) {
+			attachTCPProbesToUDPRules(service, expectedRules)
+		}
 	}
 
 	return expectedProbes, expectedRules, nil
 }
 
+// isMixedProtocolService returns true if the service has both TCP and UDP ports.
+func isMixedProtocolService(service *v1.Service) bool {
+	var hasTCP, hasUDP bool
+	for _, port := range service.Spec.Ports {
+		switch port.Protocol {
+		case v1.ProtocolTCP:
+			hasTCP = true
+		case v1.ProtocolUDP:
+			hasUDP = true
+		}
+	}
+	return hasTCP && hasUDP
+}
+
+// isTCPProbeRequestedForUDPPort returns true if the health probe protocol of the UDP port is
+// set to TCP, by the port annotation or else by the service annotation.
+func isTCPProbeRequestedForUDPPort(service *v1.Service, port int32) bool {
+	protocol, err := consts.GetHealthProbeConfigOfPortFromK8sSvcAnnotation(service.Annotations, port, consts.HealthProbeParamsProtocol)
+	if err != nil || protocol == nil {
+		protocol, err = consts.GetAttributeValueInSvcAnnotation(service.Annotations, consts.ServiceAnnotationLoadBalancerHealthProbeProtocol)
+	}
+	return err == nil && protocol != nil && strings.EqualFold(strings.TrimSpace(*protocol), string(armnetwork.ProbeProtocolTCP))
+}
+
+// attachTCPProbesToUDPRules lets the UDP rules without a health probe reuse the probe of
+// the TCP rule on the same frontend port, if the health probe protocol of the UDP port is
+// set to TCP. Azure load balancer cannot probe UDP ports, so a mixed protocol service, e.g.,
+// DNS on TCP and UDP 53, can share the TCP probe between the two protocols instead of sending
+// the UDP traffic to unhealthy backends. The UDP rules of services that do not opt in are
+// left without a probe.
+func attachTCPProbesToUDPRules(service *v1.Service, rules []*armnetwork.LoadBalancingRule) {
+	tcpProbes := make(map[int32]*armnetwork.SubResource)
+	for _, rule := range rules {
+		if rule.Properties.Probe != nil &&
+			strings.EqualFold(string(ptr.Deref(rule.Properties.Protocol, "")), string(armnetwork.TransportProtocolTCP)) {
+			tcpProbes[ptr.Deref(rule.Properties.FrontendPort, 0)] = rule.Properties.Probe
+		}
+	}
+	for _, rule := range rules {
+		if rule.Properties.Probe != nil ||
+			!strings.EqualFold(string(ptr.Deref(rule.Properties.Protocol, "")), string(armnetwork.TransportProtocolUDP)) {
+			continue
+		}
+		frontendPort := ptr.Deref(rule.Properties.FrontendPort, 0)
+		if !isTCPProbeRequestedForUDPPort(service, frontendPort) {
+			continue
+		}
+		if probe, ok := tcpProbes[frontendPort]; ok {
+			klog.V(4).Infof("attachTCPProbesToUDPRules: rule %s uses probe %s", ptr.Deref(rule.Name, ""), ptr.Deref(probe.ID, ""))
+			rule.Properties.Probe = &armnetwork.SubResource{ID: probe.ID}
+		}
+	}
+}
+
 // getDefaultLoadBalancingRulePropertiesFormat returns the loadbalancing rule for one port
 func (az *Cloud) getExpectedLoadBalancingRulePropertiesForPort(
 	service *v1.Service,
@@ -3098,11 +3418,26 @@ func (az *Cloud) getExpectedHAModeLoadBalancingRuleProperties(
 
 // This reconciles the Network Security Group similar to how the LB is reconciled.
 // This entails adding required, missing SecurityRules and removing stale rules.
+// The security group is read and reconciled again if it's modified concurrently.
 func (az *Cloud) reconcileSecurityGroup(
 	ctx context.Context,
 	clusterName string, service *v1.Service,
 	lbName string,
 	lbIPs []string, wantLb bool,
+) (*armnetwork.SecurityGroup, error) {
+	var sg *armnetwork.SecurityGroup
+	err := retryOnETagConflict(ctx, fmt.Sprintf("security group of service %s", getServiceName(service)), func() (err error) {
+		sg, err = az.reconcileSecurityGroupOnce(ctx, clusterName, service, lbName, lbIPs, wantLb)
+		return err
+	})
+	return sg, err
+}
+
+func (az *Cloud) reconcileSecurityGroupOnce(
+	ctx context.Context,
+	clusterName string, service *v1.Service,
+	lbName string,
+	lbIPs []string, wantLb bool,
 ) (*armnetwork.SecurityGroup, error) {
 	logger := log.FromContextOrBackground(ctx).WithName("reconcileSecurityGroup").
 		WithValues("load-balancer", lbName).
@@ -3110,7 +3445,12 @@ func (az *Cloud) reconcileSecurityGroup(
 	logger.V(2).Info("Starting")
 	ctx = log.NewContext(ctx, logger)
 
-	if wantLb && len(lbIPs) == 0 {
+	// The disabled mode never adds the rules of the service, but still removes the ones created before it was
+	// disabled, so that they are not left behind when the service changes or is deleted.
+	managementMode := az.getSecurityGroupManagementMode(service)
+	disabled := managementMode == consts.SecurityGroupManagementModeDisabled
+
+	if wantLb && !disabled && len(lbIPs) == 0 {
 		return nil, fmt.Errorf("no load balancer IP for setting up security rules for service %s", service.Name)
 	}
 
@@ -3131,6 +3471,12 @@ func (az *Cloud) reconcileSecurityGroup(
 			// When deleting LB, we don't need to validate the annotation
 			opts = append(opts, loadbalancer.WithEventEmitter(az.Event))
 		}
+		if az.isFloatingIPDisabled(service) {
+			opts = append(opts, loadbalancer.WithFloatingIPDisabled())
+		}
+		if managementMode == consts.SecurityGroupManagementModeAppendOnly || disabled {
+			opts = append(opts, loadbalancer.WithAppendOnly())
+		}
 		accessControl, err = loadbalancer.NewAccessControl(logger, service, sg, opts...)
 		if err != nil {
 			logger.Error(err, "Failed to parse access control configuration for service")
@@ -3139,7 +3485,7 @@ func (az *Cloud) reconcileSecurityGroup(
 	}
 
 	var (
-		disableFloatingIP                                = consts.IsK8sServiceDisableLoadBalancerFloatingIP(service)
+		disableFloatingIP                                = az.isFloatingIPDisabled(service)
 		lbIPAddresses, _                                 = iputil.ParseAddresses(lbIPs)
 		lbIPv4Addresses, lbIPv6Addresses                 = iputil.GroupAddressesByFamily(lbIPAddresses)
 		additionalIPv4Addresses, additionalIPv6Addresses = iputil.GroupAddressesByFamily(additionalIPs)
@@ -3160,6 +3506,14 @@ func (az *Cloud) reconcileSecurityGroup(
 		var backendIPv4List, backendIPv6List []string
 		if lbFound {
 			backendIPv4List, backendIPv6List = az.LoadBalancerBackendPool.GetBackendPrivateIPs(ctx, clusterName, service, lb)
+
+			additionalFrontendIPs, err := az.getAdditionalFrontendIPs(ctx, service, lb)
+			if err != nil {
+				logger.Error(err, "Failed to get additional frontend IPs")
+				return nil, err
+			}
+			additionalFrontendIPAddresses, _ := iputil.ParseAddresses(additionalFrontendIPs)
+			lbIPv4Addresses = append(lbIPv4Addresses, additionalFrontendIPAddresses...)
 		}
 		backendIPv4Addresses, _ = iputil.ParseAddresses(backendIPv4List)
 		backendIPv6Addresses, _ = iputil.ParseAddresses(backendIPv6List)
@@ -3193,7 +3547,7 @@ func (az *Cloud) reconcileSecurityGroup(
 		}
 	}
 
-	if wantLb {
+	if wantLb && !disabled {
 		err := accessControl.PatchSecurityGroup(dstIPv4Addresses, dstIPv6Addresses)
 		if err != nil {
 			logger.Error(err, "Failed to patch security group")
@@ -3207,7 +3561,7 @@ func (az *Cloud) reconcileSecurityGroup(
 		logger.Error(err, "Failed to get security group after patching")
 		return nil, err
 	}
-	if az.ensureSecurityGroupTagged(rv) {
+	if !disabled && az.ensureSecurityGroupTagged(rv) {
 		updated = true
 	}
 
@@ -3217,6 +3571,7 @@ func (az *Cloud) reconcileSecurityGroup(
 		err := az.nsgRepo.CreateOrUpdateSecurityGroup(ctx, rv)
 		if err != nil {
 			logger.Error(err, "Failed to update security group")
+			az.Event(service, v1.EventTypeWarning, "CreateOrUpdateSecurityGroup", getAzureErrorEventMessage(err))
 			return nil, err
 		}
 		logger.V(5).Info("CreateOrUpdateSecurityGroup end")
@@ -3284,19 +3639,9 @@ func shouldReleaseExistingOwnedPublicIP(
 
 // ensurePIPTagged ensures the public IP of the service is tagged as configured
 func (az *Cloud) ensurePIPTagged(service *v1.Service, pip *armnetwork.PublicIPAddress) bool {
-	configTags := parseTags(az.Tags, az.TagsMap)
-	annotationTags := make(map[string]*string)
+	configTags := az.getServiceResourceTags(service)
 	if _, ok := service.Annotations[consts.ServiceAnnotationAzurePIPTags]; ok {
-		annotationTags = parseTags(service.Annotations[consts.ServiceAnnotationAzurePIPTags], map[string]string{})
-	}
-
-	for k, v := range annotationTags {
-		found, key := findKeyInMapCaseInsensitive(configTags, k)
-		if !found {
-			configTags[k] = v
-		} else if !strings.EqualFold(ptr.Deref(v, ""), ptr.Deref(configTags[key], "")) {
-			configTags[key] = v
-		}
+		mergeTagsCaseInsensitive(configTags, parseTags(service.Annotations[consts.ServiceAnnotationAzurePIPTags], map[string]string{}))
 	}
 
 	// include the cluster name and service names tags when comparing
@@ -3419,6 +3764,8 @@ func (az *Cloud) reconcilePublicIP(ctx context.Context, pips []*armnetwork.Publi
 		return nil, err
 	}
 
+	az.untrackUserAssignedPublicIPs(service, pipResourceGroup, pips, wantLb && !isInternal, desiredPipName, isIPv6)
+
 	var deleteFuncs, updateFuncs []func() error
 	for _, pip := range pipsToBeUpdated {
 		pipCopy := *pip
@@ -3506,6 +3853,10 @@ func (az *Cloud) getPublicIPUpdates(
 		// Now, let's perform additional analysis to determine if we should release the public ips we have found.
 		// We can only let them go if (a) they are owned by this service and (b) they meet the criteria for deletion.
 		owns, isUserAssignedPIP := serviceOwnsPublicIP(service, pip, clusterName)
+		if owns && wantLb && !isIPv6 && isServiceAdditionalPIPName(service, pipName) {
+			// The additional public IPs are still being used by the service.
+			continue
+		}
 		if owns {
 			var (
 				serviceReferences     = parsePIPServiceTag(ptr.To(getServiceFromPIPServiceTags(pip.Tags)))
@@ -3970,7 +4321,8 @@ func unbindServiceFromPIP(
 	return existingServiceNames, err
 }
 
-// ensureLoadBalancerTagged ensures every load balancer in the resource group is tagged as configured
+// ensureLoadBalancerTagged ensures every load balancer in the resource group is tagged as configured.
+// The azure-resource-tags annotation is not applied because the load balancer is shared by the services.
 func (az *Cloud) ensureLoadBalancerTagged(lb *armnetwork.LoadBalancer) bool {
 	if az.Tags == "" && len(az.TagsMap) == 0 {
 		return false
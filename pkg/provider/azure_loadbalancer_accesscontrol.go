@@ -25,7 +25,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
-	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/log"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/loadbalancer"
 	fnutil "sigs.k8s.io/cloud-provider-azure/pkg/util/collectionutil"
@@ -44,10 +43,8 @@ func filterServicesByIngressIPs(services []*v1.Service, ips []netip.Addr) []*v1.
 	}, services)
 }
 
-func filterServicesByDisableFloatingIP(services []*v1.Service) []*v1.Service {
-	return fnutil.Filter(func(svc *v1.Service) bool {
-		return consts.IsK8sServiceDisableLoadBalancerFloatingIP(svc)
-	}, services)
+func (az *Cloud) filterServicesByDisableFloatingIP(services []*v1.Service) []*v1.Service {
+	return fnutil.Filter(az.isFloatingIPDisabled, services)
 }
 
 // listSharedIPPortMapping lists the shared IP port mapping for the service excluding the service itself.
@@ -74,10 +71,13 @@ func (az *Cloud) listSharedIPPortMapping(
 		}
 		logger.V(5).Info("Listed all services", "num-all-services", len(services))
 
+		// The services of the other load balancer classes are not on the Azure load balancers
+		services = fnutil.Filter(az.isServiceOfLoadBalancerClasses, services)
+
 		// Filter services by ingress IPs or backend node pool IPs (when disable floating IP)
-		if consts.IsK8sServiceDisableLoadBalancerFloatingIP(svc) {
+		if az.isFloatingIPDisabled(svc) {
 			logger.V(5).Info("Filter service by disableFloatingIP")
-			services = filterServicesByDisableFloatingIP(services)
+			services = az.filterServicesByDisableFloatingIP(services)
 		} else {
 			logger.V(5).Info("Filter service by external IPs")
 			services = filterServicesByIngressIPs(services, ingressIPs)
@@ -92,7 +92,7 @@ func (az *Cloud) listSharedIPPortMapping(
 			continue
 		}
 
-		portsByProtocol, err := loadbalancer.SecurityRuleDestinationPortsByProtocol(s)
+		portsByProtocol, err := loadbalancer.SecurityRuleDestinationPortsByProtocolWithFloatingIP(s, az.isFloatingIPDisabled(s))
 		if err != nil {
 			return nil, fmt.Errorf("fetch security rule dst ports for %s: %w", s.Name, err)
 		}
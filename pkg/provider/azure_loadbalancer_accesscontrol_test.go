@@ -2911,6 +2911,81 @@ func TestCloud_reconcileSecurityGroup(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("clean rules - when security group management is disabled", func(t *testing.T) {
+		var (
+			ctrl                    = gomock.NewController(t)
+			az                      = GetTestCloud(ctrl)
+			securityGroupClient     = az.NetworkClientFactory.GetSecurityGroupClient().(*mock_securitygroupclient.MockInterface)
+			loadBalancerClient      = az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+			loadBalancerBackendPool = az.LoadBalancerBackendPool.(*MockBackendPool)
+			loadBalancer            = azureFx.LoadBalancer().Build()
+
+			allowedIPv4Ranges = fx.RandomIPv4PrefixStrings(3)
+			svc               = k8sFx.Service().WithAllowedIPRanges(allowedIPv4Ranges...).Build()
+		)
+		defer ctrl.Finish()
+		svc.Annotations[consts.ServiceAnnotationSecurityGroupManagementMode] = consts.SecurityGroupManagementModeDisabled
+
+		var (
+			noiseRules = azureFx.NoiseSecurityRules()
+			staleRules = []*armnetwork.SecurityRule{
+				azureFx.
+					AllowSecurityRule(armnetwork.SecurityRuleProtocolTCP, iputil.IPv4, allowedIPv4Ranges, k8sFx.Service().TCPPorts()).
+					WithPriority(507).
+					WithDestination(azureFx.LoadBalancer().IPv4Addresses()...). // should remove the rule
+					Build(),
+			}
+			userRules = []*armnetwork.SecurityRule{
+				{
+					Name: ptr.To("foo"),
+					Properties: &armnetwork.SecurityRulePropertiesFormat{
+						Protocol:                   to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+						Access:                     to.Ptr(armnetwork.SecurityRuleAccessAllow),
+						Direction:                  to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+						SourcePortRange:            ptr.To("*"),
+						SourceAddressPrefixes:      to.SliceOfPtrs("foo"),
+						DestinationPortRanges:      to.SliceOfPtrs("4000", "6000"),
+						DestinationAddressPrefixes: to.SliceOfPtrs(azureFx.LoadBalancer().Addresses()...), // should keep the rule
+						Priority:                   ptr.To(int32(4003)),
+					},
+				},
+			}
+		)
+		securityGroup := azureFx.SecurityGroup().WithRules(
+			append(append(noiseRules, userRules...), staleRules...),
+		).Build()
+
+		securityGroupClient.EXPECT().
+			Get(gomock.Any(), az.ResourceGroup, az.SecurityGroupName).
+			Return(securityGroup, nil).
+			Times(1)
+		securityGroupClient.EXPECT().
+			CreateOrUpdate(gomock.Any(), az.ResourceGroup, az.SecurityGroupName, gomock.Any()).
+			DoAndReturn(func(
+				_ context.Context,
+				_, _ string,
+				properties armnetwork.SecurityGroup,
+			) (*armnetwork.SecurityGroup, error) {
+				// no rule of the service is added
+				testutil.ExpectExactSecurityRules(t, &properties, append(noiseRules, userRules...))
+				return nil, nil
+			}).Times(1)
+		loadBalancerClient.EXPECT().
+			Get(gomock.Any(), az.ResourceGroup, *loadBalancer.Name, gomock.Any()).
+			Return(loadBalancer, nil).
+			Times(1)
+		loadBalancerBackendPool.EXPECT().
+			GetBackendPrivateIPs(gomock.Any(), ClusterName, &svc, loadBalancer).
+			Return(
+				azureFx.LoadBalancer().BackendPoolIPv4Addresses(),
+				azureFx.LoadBalancer().BackendPoolIPv6Addresses(),
+			).
+			Times(1)
+
+		_, err := az.reconcileSecurityGroup(ctx, ClusterName, &svc, *loadBalancer.Name, azureFx.LoadBalancer().Addresses(), EnsureLB)
+		assert.NoError(t, err)
+	})
+
 	t.Run("negative cases", func(t *testing.T) {
 		t.Run("with both `service.beta.kubernetes.io/azure-allowed-ip-ranges` and `spec.loadBalancerSourceRanges` specified", func(t *testing.T) {
 			var (
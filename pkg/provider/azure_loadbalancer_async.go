@@ -0,0 +1,276 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cloud-provider/api"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+const (
+	// lbOperationRequeueInterval is the delay after which a service waiting for a load balancer
+	// update is reconciled again, in case the update of the service at the end is missed.
+	lbOperationRequeueInterval = 15 * time.Second
+	// lbOperationPollFrequency is the polling frequency of the asynchronous load balancer updates.
+	lbOperationPollFrequency = 5 * time.Second
+)
+
+// pendingLBOperation is the asynchronous load balancer update recorded on the service.
+type pendingLBOperation struct {
+	LoadBalancerName string `json:"loadBalancerName"`
+	ResumeToken      string `json:"resumeToken"`
+}
+
+// lbOperation is an asynchronous load balancer update being polled.
+type lbOperation struct {
+	// service is the key of the service whose reconciliation started the update.
+	service string
+	// done is closed when the polling finishes.
+	done chan struct{}
+}
+
+// lbOperationTracker tracks the asynchronous load balancer updates by the load balancer name.
+// A load balancer has at most one update in progress, the reconciliations of the services
+// using it are requeued until the update finishes.
+type lbOperationTracker struct {
+	lock       sync.Mutex
+	operations map[string]*lbOperation
+}
+
+// newLBOperationTracker creates a new lbOperationTracker. It returns nil if the asynchronous
+// load balancer updates are disabled.
+func newLBOperationTracker(enabled bool) *lbOperationTracker {
+	if !enabled {
+		return nil
+	}
+	return &lbOperationTracker{
+		operations: make(map[string]*lbOperation),
+	}
+}
+
+func (t *lbOperationTracker) get(lbName string) *lbOperation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.operations[strings.ToLower(lbName)]
+}
+
+// add tracks the operation of the load balancer. It returns false if the load balancer
+// already has an operation in progress.
+func (t *lbOperationTracker) add(lbName string, op *lbOperation) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := strings.ToLower(lbName)
+	if _, found := t.operations[key]; found {
+		return false
+	}
+	t.operations[key] = op
+	return true
+}
+
+func (t *lbOperationTracker) remove(lbName string, op *lbOperation) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := strings.ToLower(lbName)
+	if t.operations[key] == op {
+		delete(t.operations, key)
+	}
+}
+
+// newLBOperationInProgressError returns the error which requeues the reconciliation of a
+// service while the load balancer is being updated.
+func newLBOperationInProgressError(lbName string) error {
+	return api.NewRetryError(fmt.Sprintf("load balancer %s is being updated asynchronously", lbName), lbOperationRequeueInterval)
+}
+
+// createOrUpdateLBAsync starts the load balancer update and polls it in the background. It
+// returns a RetryError once the update is accepted, so the worker is not blocked by the
+// polling, and the service is reconciled again when the update finishes.
+func (az *Cloud) createOrUpdateLBAsync(ctx context.Context, service *v1.Service, lb armnetwork.LoadBalancer) error {
+	lbName := ptr.Deref(lb.Name, "")
+	if az.lbOperationTracker.get(lbName) != nil {
+		return newLBOperationInProgressError(lbName)
+	}
+
+	rgName := az.getLoadBalancerResourceGroup()
+	poller, err := az.NetworkClientFactory.GetLoadBalancerClient().BeginCreateOrUpdate(ctx, rgName, lbName, lb, nil)
+	if err != nil {
+		return az.handleCreateOrUpdateLBError(ctx, service, lb, err)
+	}
+	// Invalidate the cache right after updating
+	_ = az.lbCache.Delete(lbName)
+
+	if poller.Done() {
+		if _, err := poller.Result(ctx); err != nil {
+			return az.handleCreateOrUpdateLBError(ctx, service, lb, err)
+		}
+		return nil
+	}
+
+	token, err := poller.ResumeToken()
+	if err != nil {
+		return fmt.Errorf("failed to get the resume token of the update of load balancer %s: %w", lbName, err)
+	}
+	op := &lbOperation{service: getServiceName(service), done: make(chan struct{})}
+	if !az.lbOperationTracker.add(lbName, op) {
+		return newLBOperationInProgressError(lbName)
+	}
+	recordErr := az.setPendingLBOperation(ctx, service, &pendingLBOperation{LoadBalancerName: lbName, ResumeToken: token})
+	log.FromContextOrBackground(ctx).WithName("createOrUpdateLBAsync").V(2).Info("Polling the update of the load balancer", "loadBalancer", lbName, "service", op.service)
+	go az.pollLBOperation(service, lb, poller, op)
+
+	// The update goes on, but it cannot be resumed by another controller without the record,
+	// so the error is returned to reconcile the service again.
+	if recordErr != nil {
+		return fmt.Errorf("failed to record the update of load balancer %s on service %s: %w", lbName, op.service, recordErr)
+	}
+	return newLBOperationInProgressError(lbName)
+}
+
+// pollLBOperation polls the load balancer update until it finishes, and then removes the
+// pending operation from the service, which requeues the reconciliation of the service.
+func (az *Cloud) pollLBOperation(service *v1.Service, lb armnetwork.LoadBalancer, poller *runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse], op *lbOperation) {
+	lbName := ptr.Deref(lb.Name, "")
+	defer close(op.done)
+	defer az.lbOperationTracker.remove(lbName, op)
+
+	ctx := context.Background()
+	logger := log.Background().WithName("pollLBOperation").WithValues("loadBalancer", lbName, "service", op.service)
+	_, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
+		Frequency: lbOperationPollFrequency,
+	})
+	_ = az.lbCache.Delete(lbName)
+	if err != nil {
+		logger.Error(err, "The update of the load balancer failed")
+		_ = az.handleCreateOrUpdateLBError(ctx, service, lb, err)
+	} else {
+		logger.V(2).Info("The update of the load balancer finished")
+	}
+
+	if err := az.setPendingLBOperation(ctx, service, nil); err != nil {
+		logger.Error(err, "Failed to remove the pending operation from the service")
+	}
+}
+
+// resumeLBOperation resumes polling the load balancer update recorded on the service, e.g. by
+// the controller before a restart or a leader change. It returns a RetryError while the update
+// is in progress, and nil if there is no update to wait for.
+func (az *Cloud) resumeLBOperation(ctx context.Context, service *v1.Service) error {
+	if az.lbOperationTracker == nil {
+		return nil
+	}
+	value, found := service.Annotations[consts.ServiceAnnotationLoadBalancerPendingOperation]
+	if !found {
+		return nil
+	}
+
+	serviceName := getServiceName(service)
+	logger := log.FromContextOrBackground(ctx).WithName("resumeLBOperation").WithValues("service", serviceName)
+	var pending pendingLBOperation
+	if err := json.Unmarshal([]byte(value), &pending); err != nil || pending.LoadBalancerName == "" || pending.ResumeToken == "" {
+		logger.Info("Removing the invalid pending operation from the service", "pendingOperation", value)
+		return az.setPendingLBOperation(ctx, service, nil)
+	}
+	lbName := pending.LoadBalancerName
+	logger = logger.WithValues("loadBalancer", lbName)
+	if az.lbOperationTracker.get(lbName) != nil {
+		return newLBOperationInProgressError(lbName)
+	}
+
+	lb := armnetwork.LoadBalancer{Name: ptr.To(lbName)}
+	poller, err := az.NetworkClientFactory.GetLoadBalancerClient().BeginCreateOrUpdate(ctx, az.getLoadBalancerResourceGroup(), lbName, lb, &armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions{
+		ResumeToken: pending.ResumeToken,
+	})
+	if err == nil && !poller.Done() {
+		_, err = poller.Poll(ctx)
+	}
+	if err != nil {
+		// The operation is gone or the token is stale, the reconciliation finds out the
+		// current state of the load balancer anyway.
+		logger.Error(err, "Failed to resume the update of the load balancer, dropping it")
+		_ = az.lbCache.Delete(lbName)
+		return az.setPendingLBOperation(ctx, service, nil)
+	}
+
+	if poller.Done() {
+		_ = az.lbCache.Delete(lbName)
+		if _, err := poller.Result(ctx); err != nil {
+			logger.Error(err, "The update of the load balancer failed")
+			_ = az.handleCreateOrUpdateLBError(ctx, service, lb, err)
+		}
+		return az.setPendingLBOperation(ctx, service, nil)
+	}
+
+	op := &lbOperation{service: serviceName, done: make(chan struct{})}
+	if !az.lbOperationTracker.add(lbName, op) {
+		return newLBOperationInProgressError(lbName)
+	}
+	logger.V(2).Info("Resumed polling the update of the load balancer")
+	go az.pollLBOperation(service, lb, poller, op)
+
+	return newLBOperationInProgressError(lbName)
+}
+
+// setPendingLBOperation records the pending load balancer update on the service, or removes
+// it if pending is nil. The service is patched even if its copy does not have the annotation,
+// since the copy is taken before the update starts.
+func (az *Cloud) setPendingLBOperation(ctx context.Context, service *v1.Service, pending *pendingLBOperation) error {
+	if az.KubeClient == nil || service == nil || service.Name == "" {
+		return nil
+	}
+	var value interface{}
+	if pending != nil {
+		data, err := json.Marshal(pending)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				consts.ServiceAnnotationLoadBalancerPendingOperation: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = az.KubeClient.CoreV1().Services(service.Namespace).Patch(ctx, service.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/cloud-provider/api"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	testLBOperationLBURL     = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb?api-version=2024-05-01"
+	testLBOperationStatusURL = "https://management.azure.com/subscriptions/sub/providers/Microsoft.Network/locations/eastus/operations/op?api-version=2024-05-01"
+)
+
+// fakeLBOperationTransport serves the status of the asynchronous load balancer update from
+// the statuses channel, so the tests control when the update finishes.
+type fakeLBOperationTransport struct {
+	statuses chan string
+}
+
+func (t *fakeLBOperationTransport) Do(req *http.Request) (*http.Response, error) {
+	body := `{"name":"lb"}`
+	if strings.Contains(req.URL.Path, "/operations/") {
+		body = `{"status":"` + <-t.statuses + `"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func newTestLBOperationPoller(t *testing.T, transport *fakeLBOperationTransport) *runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse] {
+	req, err := http.NewRequest(http.MethodPut, testLBOperationLBURL, nil)
+	assert.NoError(t, err)
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Azure-Asyncoperation": []string{testLBOperationStatusURL}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{Transport: transport})
+	poller, err := runtime.NewPoller[armnetwork.LoadBalancersClientCreateOrUpdateResponse](resp, pl, nil)
+	assert.NoError(t, err)
+	return poller
+}
+
+func getTestPendingLBOperation(t *testing.T, az *Cloud) (string, bool) {
+	svc, err := az.KubeClient.CoreV1().Services("default").Get(context.Background(), "svc", metav1.GetOptions{})
+	assert.NoError(t, err)
+	value, found := svc.Annotations[consts.ServiceAnnotationLoadBalancerPendingOperation]
+	return value, found
+}
+
+func TestCreateOrUpdateLBAsync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.lbOperationTracker = newLBOperationTracker(true)
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+	az.KubeClient = fake.NewSimpleClientset(service)
+
+	transport := &fakeLBOperationTransport{statuses: make(chan string)}
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().BeginCreateOrUpdate(gomock.Any(), az.ResourceGroup, "lb", gomock.Any(), nil).Return(newTestLBOperationPoller(t, transport), nil).Times(1)
+
+	lb := armnetwork.LoadBalancer{Name: ptr.To("lb")}
+	err := az.CreateOrUpdateLB(context.Background(), service, lb)
+	var retryErr *api.RetryError
+	if !assert.True(t, errors.As(err, &retryErr), "unexpected error: %v", err) {
+		return
+	}
+	assert.True(t, isServiceReconcileErrorRetriable(err))
+
+	op := az.lbOperationTracker.get("LB")
+	if !assert.NotNil(t, op) {
+		return
+	}
+	assert.Equal(t, "default/svc", op.service)
+	value, found := getTestPendingLBOperation(t, az)
+	assert.True(t, found)
+	var pending pendingLBOperation
+	assert.NoError(t, json.Unmarshal([]byte(value), &pending))
+	assert.Equal(t, "lb", pending.LoadBalancerName)
+	assert.NotEmpty(t, pending.ResumeToken)
+
+	// The load balancer is not updated again while the update is in progress.
+	err = az.CreateOrUpdateLB(context.Background(), service, lb)
+	assert.True(t, errors.As(err, &retryErr), "unexpected error: %v", err)
+
+	transport.statuses <- "Succeeded"
+	<-op.done
+	assert.Nil(t, az.lbOperationTracker.get("lb"))
+	_, found = getTestPendingLBOperation(t, az)
+	assert.False(t, found)
+}
+
+func TestCreateOrUpdateLBAsyncRecordFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.lbOperationTracker = newLBOperationTracker(true)
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+	kubeClient := fake.NewSimpleClientset(service)
+	kubeClient.PrependReactor("patch", "services", func(_ k8stesting.Action) (bool, k8sruntime.Object, error) {
+		return true, nil, errors.New("patch error")
+	})
+	az.KubeClient = kubeClient
+
+	transport := &fakeLBOperationTransport{statuses: make(chan string)}
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().BeginCreateOrUpdate(gomock.Any(), az.ResourceGroup, "lb", gomock.Any(), nil).Return(newTestLBOperationPoller(t, transport), nil).Times(1)
+
+	// the service is reconciled again if the update cannot be recorded
+	err := az.CreateOrUpdateLB(context.Background(), service, armnetwork.LoadBalancer{Name: ptr.To("lb")})
+	assert.ErrorContains(t, err, "failed to record the update of load balancer lb on service default/svc: patch error")
+	var retryErr *api.RetryError
+	assert.False(t, errors.As(err, &retryErr))
+
+	// and the update is still polled
+	op := az.lbOperationTracker.get("lb")
+	if !assert.NotNil(t, op) {
+		return
+	}
+	transport.statuses <- "Succeeded"
+	<-op.done
+	assert.Nil(t, az.lbOperationTracker.get("lb"))
+}
+
+func TestResumeLBOperation(t *testing.T) {
+	pendingValue, err := json.Marshal(pendingLBOperation{LoadBalancerName: "lb", ResumeToken: "token"})
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		desc         string
+		annotation   *string
+		status       string
+		resumeErr    error
+		expectResume bool
+		expectRetry  bool
+	}{
+		{
+			desc: "no pending operation",
+		},
+		{
+			desc:       "invalid pending operation is removed",
+			annotation: ptr.To("invalid"),
+		},
+		{
+			desc:         "finished operation is removed",
+			annotation:   ptr.To(string(pendingValue)),
+			status:       "Succeeded",
+			expectResume: true,
+		},
+		{
+			desc:         "stale operation is removed",
+			annotation:   ptr.To(string(pendingValue)),
+			resumeErr:    errors.New("invalid token"),
+			expectResume: true,
+		},
+		{
+			desc:         "operation in progress is polled in the background",
+			annotation:   ptr.To(string(pendingValue)),
+			status:       "InProgress",
+			expectResume: true,
+			expectRetry:  true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			az := GetTestCloud(ctrl)
+			az.lbOperationTracker = newLBOperationTracker(true)
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: map[string]string{}}}
+			if tc.annotation != nil {
+				service.Annotations[consts.ServiceAnnotationLoadBalancerPendingOperation] = *tc.annotation
+			}
+			az.KubeClient = fake.NewSimpleClientset(service)
+
+			transport := &fakeLBOperationTransport{statuses: make(chan string, 1)}
+			if tc.status != "" {
+				transport.statuses <- tc.status
+			}
+			mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+			if tc.expectResume {
+				var poller *runtime.Poller[armnetwork.LoadBalancersClientCreateOrUpdateResponse]
+				if tc.resumeErr == nil {
+					poller = newTestLBOperationPoller(t, transport)
+				}
+				mockLBClient.EXPECT().BeginCreateOrUpdate(gomock.Any(), az.ResourceGroup, "lb", gomock.Any(), &armnetwork.LoadBalancersClientBeginCreateOrUpdateOptions{ResumeToken: "token"}).Return(poller, tc.resumeErr)
+			}
+
+			err := az.resumeLBOperation(context.Background(), service)
+			if !tc.expectRetry {
+				assert.NoError(t, err)
+				_, found := getTestPendingLBOperation(t, az)
+				assert.False(t, found)
+				assert.Nil(t, az.lbOperationTracker.get("lb"))
+				return
+			}
+
+			var retryErr *api.RetryError
+			if !assert.True(t, errors.As(err, &retryErr), "unexpected error: %v", err) {
+				return
+			}
+			op := az.lbOperationTracker.get("lb")
+			if !assert.NotNil(t, op) {
+				return
+			}
+			// Another reconciliation waits for the same operation.
+			assert.Error(t, az.resumeLBOperation(context.Background(), service))
+
+			transport.statuses <- "Succeeded"
+			<-op.done
+			_, found := getTestPendingLBOperation(t, az)
+			assert.False(t, found)
+			assert.Nil(t, az.lbOperationTracker.get("lb"))
+		})
+	}
+}
@@ -413,22 +413,29 @@ func (bi *backendPoolTypeNodeIP) EnsureHostsInPool(ctx context.Context, service
 		numOfAdd, numOfDelete int
 		activeNodes           *utilsets.IgnoreCaseSet
 	)
-	if bi.UseMultipleStandardLoadBalancers() {
-		if !isLocalService(service) {
-			activeNodes = bi.getActiveNodesByLoadBalancerName(lbName)
-		} else {
-			key := strings.ToLower(getServiceName(service))
-			si, found := bi.getLocalServiceInfo(key)
-			if found && !strings.EqualFold(si.lbName, lbName) {
-				klog.V(4).InfoS("EnsureHostsInPool: the service is not on the load balancer",
-					"service", key,
-					"previous load balancer", lbName,
-					"current load balancer", si.lbName)
-				return nil
-			}
-			activeNodes = bi.getLocalServiceEndpointsNodeNames(service)
+	useLocalServiceBackendPool := bi.UseLocalServiceBackendPools() && isLocalService(service)
+	if useLocalServiceBackendPool {
+		key := strings.ToLower(getServiceName(service))
+		si, found := bi.getLocalServiceInfo(key)
+		if found && !strings.EqualFold(si.lbName, lbName) {
+			klog.V(4).InfoS("EnsureHostsInPool: the service is not on the load balancer",
+				"service", key,
+				"previous load balancer", lbName,
+				"current load balancer", si.lbName)
+			return nil
 		}
-
+		activeNodes = bi.getLocalServiceEndpointsNodeNames(service)
+	} else if bi.UseMultipleStandardLoadBalancers() {
+		activeNodes = bi.getActiveNodesByLoadBalancerName(lbName)
+	}
+	useZonalServiceBackendPool := bi.useZonalServiceBackendPool(service)
+	if useZonalServiceBackendPool {
+		// the nodes out of the zone are removed from the backend pool like the deleted ones
+		nodes = bi.filterNodesByZone(nodes, getServiceZone(service))
+	}
+	// The backend pool can be emptied if only a subset of the nodes is expected in it.
+	allowEmptyPool := bi.UseMultipleStandardLoadBalancers() || useLocalServiceBackendPool || useZonalServiceBackendPool
+	if allowEmptyPool {
 		if isNICPool(backendPool) {
 			klog.V(4).InfoS("EnsureHostsInPool: skipping NIC-based backend pool", "backendPoolName", ptr.Deref(backendPool.Name, ""))
 			return nil
@@ -465,11 +472,9 @@ func (bi *backendPoolTypeNodeIP) EnsureHostsInPool(ctx context.Context, service
 				nodePrivateIPsSet.Insert(privateIP)
 			}
 
-			if bi.UseMultipleStandardLoadBalancers() {
-				if activeNodes != nil && !activeNodes.Has(node.Name) {
-					klog.V(4).Infof("bi.EnsureHostsInPool: node %s should not be in load balancer %q", node.Name, lbName)
-					continue
-				}
+			if activeNodes != nil && !activeNodes.Has(node.Name) {
+				klog.V(4).Infof("bi.EnsureHostsInPool: node %s should not be in load balancer %q", node.Name, lbName)
+				continue
 			}
 
 			if !existingIPs.Has(privateIP) {
@@ -489,7 +494,7 @@ func (bi *backendPoolTypeNodeIP) EnsureHostsInPool(ctx context.Context, service
 				nodeIPsToBeDeleted = append(nodeIPsToBeDeleted, ip)
 				changed = true
 				numOfDelete++
-			} else if bi.UseMultipleStandardLoadBalancers() && activeNodes != nil {
+			} else if activeNodes != nil {
 				nodeName, ok := bi.nodePrivateIPToNodeNameMap[ip]
 				if !ok {
 					klog.Warningf("bi.EnsureHostsInPool: cannot find node name for private IP %s", ip)
@@ -503,7 +508,7 @@ func (bi *backendPoolTypeNodeIP) EnsureHostsInPool(ctx context.Context, service
 				}
 			}
 		}
-		removeNodeIPAddressesFromBackendPool(backendPool, nodeIPsToBeDeleted, false, bi.UseMultipleStandardLoadBalancers(), true)
+		removeNodeIPAddressesFromBackendPool(backendPool, nodeIPsToBeDeleted, false, allowEmptyPool, true)
 	}
 	if changed {
 		klog.V(2).Infof("bi.EnsureHostsInPool: updating backend pool %s of load balancer %s to add %d nodes and remove %d nodes", lbBackendPoolName, lbName, numOfAdd, numOfDelete)
@@ -874,7 +879,7 @@ func hasIPAddressInBackendPool(backendPool *armnetwork.BackendAddressPool, ipAdd
 func removeNodeIPAddressesFromBackendPool(
 	backendPool *armnetwork.BackendAddressPool,
 	nodeIPAddresses []string,
-	removeAll, UseMultipleStandardLoadBalancers, isNodeIP bool,
+	removeAll, allowEmptyPool, isNodeIP bool,
 ) bool {
 	changed := false
 	nodeIPsSet := utilsets.NewString(nodeIPAddresses...)
@@ -913,9 +918,9 @@ func removeNodeIPAddressesFromBackendPool(
 		return changed
 	}
 
-	// Allow the pool to be empty when EnsureHostsInPool for multiple standard load balancers clusters,
-	// or one node could occur in multiple backend pools.
-	if len(addresses) == 0 && !UseMultipleStandardLoadBalancers {
+	// Allow the pool to be empty when EnsureHostsInPool for multiple standard load balancers clusters
+	// or local service backend pools, where one node could occur in multiple backend pools.
+	if len(addresses) == 0 && !allowEmptyPool {
 		klog.V(2).Info("removeNodeIPAddressFromBackendPool: the pool is empty or will be empty after removing the unwanted IP addresses, skipping the removal")
 		changed = false
 	} else if changed {
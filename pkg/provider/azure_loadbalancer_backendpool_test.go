@@ -111,6 +111,7 @@ func TestEnsureHostsInPoolNodeIP(t *testing.T) {
 		desc                string
 		backendPool         *armnetwork.BackendAddressPool
 		multiSLBConfigs     []config.MultipleStandardLoadBalancerConfiguration
+		localServicePools   bool
 		local               bool
 		notFound            bool
 		skip                bool
@@ -337,6 +338,65 @@ func TestEnsureHostsInPoolNodeIP(t *testing.T) {
 			},
 			cache: true,
 		},
+		{
+			desc:              "local service with local service backend pools on single standard load balancer",
+			local:             true,
+			localServicePools: true,
+			backendPool: &armnetwork.BackendAddressPool{
+				Name: ptr.To("default-svc-1"),
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+					LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{},
+				},
+			},
+			expectedBackendPool: &armnetwork.BackendAddressPool{
+				Name: ptr.To("default-svc-1"),
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+					VirtualNetwork: &armnetwork.SubResource{ID: ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet")},
+					LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{
+						{
+							Name: ptr.To("vmss-0"),
+							Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+								IPAddress: ptr.To("10.0.0.2"),
+							},
+						},
+						{
+							Name: ptr.To("vmss-1"),
+							Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+								IPAddress: ptr.To("10.0.0.1"),
+							},
+						},
+					},
+				},
+			},
+			cache: true,
+		},
+		{
+			desc:              "local service without endpoints should empty the local service backend pool on single standard load balancer",
+			local:             true,
+			localServicePools: true,
+			backendPool: &armnetwork.BackendAddressPool{
+				Name: ptr.To("empty-svc-1"),
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+					LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{
+						{
+							Name: ptr.To("vmss-0"),
+							Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+								IPAddress: ptr.To("10.0.0.2"),
+							},
+						},
+					},
+				},
+			},
+			expectedBackendPool: &armnetwork.BackendAddressPool{
+				Name: ptr.To("empty-svc-1"),
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+					VirtualNetwork:               &armnetwork.SubResource{ID: ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet")},
+					LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{},
+				},
+			},
+			cache:     true,
+			namespace: "empty",
+		},
 		{
 			desc:  "local service in another namespace",
 			local: true,
@@ -403,6 +463,11 @@ func TestEnsureHostsInPoolNodeIP(t *testing.T) {
 				}
 			}
 
+			if tc.localServicePools {
+				az.EnableLocalServiceBackendPools = true
+				az.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIP
+			}
+
 			backendpoolClient := az.NetworkClientFactory.GetBackendAddressPoolClient().(*mock_backendaddresspoolclient.MockInterface)
 			if !tc.notFound && !tc.skip {
 				backendpoolClient.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
@@ -423,6 +488,7 @@ func TestEnsureHostsInPoolNodeIP(t *testing.T) {
 				kubeClient = fake.NewSimpleClientset()
 				az.endpointSlicesCache.Store("default/eps", eps)
 				az.endpointSlicesCache.Store("another/eps", epsInAnotherNamespace)
+				az.endpointSlicesCache.Store("empty/eps", getTestEndpointSlice("eps", "empty", "svc-1"))
 			}
 			az.KubeClient = kubeClient
 			az.nodePrivateIPs = map[string]*utilsets.IgnoreCaseSet{
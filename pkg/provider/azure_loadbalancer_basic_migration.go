@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// migrateBasicLoadBalancer moves the frontends of a basic load balancer to the
+// standard load balancers of the cluster. A NIC can't be in the backend pools of
+// both a basic and a standard load balancer, and a public IP can't be attached to
+// both, so the basic load balancer has to be removed first. To keep the downtime
+// to the recreation of the rules, the public IPs of its frontends are:
+//  1. changed to static allocation, so their addresses are kept when they are
+//     detached from the basic load balancer;
+//  2. upgraded to the standard SKU once the basic load balancer is removed.
+//
+// The rules of the services are recreated on the standard load balancers when
+// the services are reconciled, and the upgraded public IPs are reused there.
+func (az *Cloud) migrateBasicLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, lb *armnetwork.LoadBalancer) error {
+	lbName := ptr.Deref(lb.Name, "")
+	pipIDs := getLoadBalancerPublicIPIDs(lb)
+	az.Event(service, v1.EventTypeNormal, "MigratingBasicLoadBalancer",
+		fmt.Sprintf("Moving basic load balancer %s with %d public IPs to a standard load balancer", lbName, len(pipIDs)))
+
+	fail := func(step string, err error) error {
+		klog.ErrorS(err, "migrateBasicLoadBalancer: failed to migrate basic load balancer", "loadBalancerName", lbName, "step", step)
+		az.Event(service, v1.EventTypeWarning, "MigrateBasicLoadBalancerFailed",
+			fmt.Sprintf("Failed to migrate basic load balancer %s, could not %s: %v", lbName, step, err))
+		return err
+	}
+
+	for _, pipID := range pipIDs {
+		if err := az.updateBasicLoadBalancerPublicIP(ctx, service, pipID, reserveBasicPublicIP); err != nil {
+			return fail("reserve its public IPs", err)
+		}
+	}
+	if len(pipIDs) > 0 {
+		az.Event(service, v1.EventTypeNormal, "MigratingBasicLoadBalancer",
+			fmt.Sprintf("Reserved the addresses of the public IPs of basic load balancer %s", lbName))
+	}
+
+	if err := az.safeDeleteLoadBalancer(ctx, *lb, clusterName, service); err != nil {
+		return fail("remove it", err)
+	}
+	az.Event(service, v1.EventTypeNormal, "MigratingBasicLoadBalancer",
+		fmt.Sprintf("Removed basic load balancer %s", lbName))
+
+	for _, pipID := range pipIDs {
+		if err := az.updateBasicLoadBalancerPublicIP(ctx, service, pipID, upgradeBasicPublicIP); err != nil {
+			return fail("upgrade its public IPs", err)
+		}
+	}
+
+	az.Event(service, v1.EventTypeNormal, "MigratedBasicLoadBalancer",
+		fmt.Sprintf("Moved basic load balancer %s, the rules are recreated on the standard load balancer when the services are reconciled", lbName))
+	return nil
+}
+
+// getLoadBalancerPublicIPIDs returns the IDs of the public IPs of the frontends of the load balancer.
+func getLoadBalancerPublicIPIDs(lb *armnetwork.LoadBalancer) []string {
+	if lb.Properties == nil {
+		return nil
+	}
+	var pipIDs []string
+	for _, fip := range lb.Properties.FrontendIPConfigurations {
+		if fip == nil || fip.Properties == nil || fip.Properties.PublicIPAddress == nil {
+			continue
+		}
+		if pipID := ptr.Deref(fip.Properties.PublicIPAddress.ID, ""); pipID != "" {
+			pipIDs = append(pipIDs, pipID)
+		}
+	}
+	return pipIDs
+}
+
+// reserveBasicPublicIP changes a dynamic public IP to static allocation, so its
+// address is kept when it is detached from the load balancer.
+func reserveBasicPublicIP(pip *armnetwork.PublicIPAddress) bool {
+	if pip.Properties == nil {
+		pip.Properties = &armnetwork.PublicIPAddressPropertiesFormat{}
+	}
+	if strings.EqualFold(string(ptr.Deref(pip.Properties.PublicIPAllocationMethod, "")), string(armnetwork.IPAllocationMethodStatic)) {
+		return false
+	}
+	pip.Properties.PublicIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
+	return true
+}
+
+// upgradeBasicPublicIP upgrades a detached public IP to the standard SKU.
+func upgradeBasicPublicIP(pip *armnetwork.PublicIPAddress) bool {
+	if pip.SKU != nil && strings.EqualFold(string(ptr.Deref(pip.SKU.Name, "")), string(armnetwork.PublicIPAddressSKUNameStandard)) {
+		return false
+	}
+	pip.SKU = &armnetwork.PublicIPAddressSKU{Name: ptr.To(armnetwork.PublicIPAddressSKUNameStandard)}
+	return true
+}
+
+// updateBasicLoadBalancerPublicIP gets the public IP and updates it if mutate changes it.
+func (az *Cloud) updateBasicLoadBalancerPublicIP(
+	ctx context.Context, service *v1.Service, pipID string, mutate func(*armnetwork.PublicIPAddress) bool,
+) error {
+	resourceID, err := arm.ParseResourceID(pipID)
+	if err != nil {
+		return fmt.Errorf("invalid public IP ID %q: %w", pipID, err)
+	}
+	pip, err := az.NetworkClientFactory.GetPublicIPAddressClient().Get(ctx, resourceID.ResourceGroupName, resourceID.Name, nil)
+	if err != nil {
+		return err
+	}
+	if !mutate(pip) {
+		return nil
+	}
+	klog.V(2).InfoS("updateBasicLoadBalancerPublicIP: updating the public IP", "publicIPName", resourceID.Name, "resourceGroup", resourceID.ResourceGroupName)
+	return az.CreateOrUpdatePIP(service, resourceID.ResourceGroupName, pip)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func newTestBasicLoadBalancer() *armnetwork.LoadBalancer {
+	return &armnetwork.LoadBalancer{
+		Name: ptr.To("kubernetes"),
+		SKU:  &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameBasic)},
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{
+					Name: ptr.To("fip"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.PublicIPAddress{
+							ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+						},
+					},
+				},
+			},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{{ID: ptr.To("pool-id")}},
+		},
+	}
+}
+
+func newTestBasicPublicIP() *armnetwork.PublicIPAddress {
+	return &armnetwork.PublicIPAddress{
+		Name: ptr.To("pip"),
+		SKU:  &armnetwork.PublicIPAddressSKU{Name: ptr.To(armnetwork.PublicIPAddressSKUNameBasic)},
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			IPAddress:                ptr.To("1.2.3.4"),
+			PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+		},
+	}
+}
+
+func getTestEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	return events
+}
+
+func TestMigrateBasicLoadBalancer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should keep the public IPs and report the progress", func(t *testing.T) {
+		az := GetTestCloud(ctrl)
+		az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+		recorder := record.NewFakeRecorder(10)
+		az.eventRecorder = recorder
+		mockVMSet := NewMockVMSet(ctrl)
+		mockVMSet.EXPECT().EnsureBackendPoolDeleted(gomock.Any(), gomock.Any(), []string{"pool-id"}, gomock.Any(), gomock.Any(), true).Return(true, nil)
+		az.VMSet = mockVMSet
+		mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+		mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+
+		reserved := newTestBasicPublicIP()
+		reserved.Properties.PublicIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
+		upgraded := newTestBasicPublicIP()
+		upgraded.Properties.PublicIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
+		upgraded.SKU.Name = ptr.To(armnetwork.PublicIPAddressSKUNameStandard)
+		gomock.InOrder(
+			mockPIPClient.EXPECT().Get(gomock.Any(), "rg", "pip", gomock.Any()).Return(newTestBasicPublicIP(), nil),
+			mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "pip", *reserved).Return(nil, nil),
+			mockLBClient.EXPECT().Delete(gomock.Any(), az.ResourceGroup, "kubernetes").Return(nil),
+			mockPIPClient.EXPECT().Get(gomock.Any(), "rg", "pip", gomock.Any()).Return(reserved, nil),
+			mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "pip", *upgraded).Return(nil, nil),
+		)
+
+		assert.NoError(t, az.migrateBasicLoadBalancer(context.Background(), testClusterName, &v1.Service{}, newTestBasicLoadBalancer()))
+		assert.Equal(t, []string{
+			"Normal MigratingBasicLoadBalancer Moving basic load balancer kubernetes with 1 public IPs to a standard load balancer",
+			"Normal MigratingBasicLoadBalancer Reserved the addresses of the public IPs of basic load balancer kubernetes",
+			"Normal MigratingBasicLoadBalancer Removed basic load balancer kubernetes",
+			"Normal MigratedBasicLoadBalancer Moved basic load balancer kubernetes, the rules are recreated on the standard load balancer when the services are reconciled",
+		}, getTestEvents(recorder))
+	})
+
+	t.Run("should report the failed step", func(t *testing.T) {
+		az := GetTestCloud(ctrl)
+		az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+		recorder := record.NewFakeRecorder(10)
+		az.eventRecorder = recorder
+		mockVMSet := NewMockVMSet(ctrl)
+		mockVMSet.EXPECT().EnsureBackendPoolDeleted(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), true).Return(false, errors.New("pool error"))
+		az.VMSet = mockVMSet
+		mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+		reserved := newTestBasicPublicIP()
+		reserved.Properties.PublicIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
+		mockPIPClient.EXPECT().Get(gomock.Any(), "rg", "pip", gomock.Any()).Return(reserved, nil)
+
+		err := az.migrateBasicLoadBalancer(context.Background(), testClusterName, &v1.Service{}, newTestBasicLoadBalancer())
+		assert.ErrorContains(t, err, "pool error")
+		assert.Equal(t, []string{
+			"Normal MigratingBasicLoadBalancer Moving basic load balancer kubernetes with 1 public IPs to a standard load balancer",
+			"Normal MigratingBasicLoadBalancer Reserved the addresses of the public IPs of basic load balancer kubernetes",
+			"Warning MigrateBasicLoadBalancerFailed Failed to migrate basic load balancer kubernetes, could not remove it: safeDeleteLoadBalancer: failed to EnsureBackendPoolDeleted: pool error",
+		}, getTestEvents(recorder))
+	})
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// SetLoadBalancerClasses sets the load balancer classes of the services reconciled besides the
+// services without a class. The services of the other classes belong to the other load balancer
+// implementations in the cluster, and are never taken into account. It must be called before
+// the services are reconciled.
+func (az *Cloud) SetLoadBalancerClasses(classes []string) {
+	az.loadBalancerClasses = sets.New(classes...)
+}
+
+// isServiceOfLoadBalancerClasses returns true if the service has no load balancer class, or
+// one of the classes set by SetLoadBalancerClasses.
+func (az *Cloud) isServiceOfLoadBalancerClasses(service *v1.Service) bool {
+	return service.Spec.LoadBalancerClass == nil || az.loadBalancerClasses.Has(*service.Spec.LoadBalancerClass)
+}
+
+// wantsLoadBalancer returns true if the service is a LoadBalancer service reconciled by the cloud.
+func (az *Cloud) wantsLoadBalancer(service *v1.Service) bool {
+	return service.Spec.Type == v1.ServiceTypeLoadBalancer && az.isServiceOfLoadBalancerClasses(service)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestWantsLoadBalancer(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		classes     []string
+		serviceType v1.ServiceType
+		class       *string
+		expected    bool
+	}{
+		{
+			desc:        "LoadBalancer service without a class",
+			serviceType: v1.ServiceTypeLoadBalancer,
+			expected:    true,
+		},
+		{
+			desc:        "ClusterIP service without a class",
+			serviceType: v1.ServiceTypeClusterIP,
+		},
+		{
+			desc:        "LoadBalancer service of another class",
+			serviceType: v1.ServiceTypeLoadBalancer,
+			class:       ptr.To("metallb.io/metallb"),
+		},
+		{
+			desc:        "LoadBalancer service of an accepted class",
+			classes:     []string{"example.com/azure"},
+			serviceType: v1.ServiceTypeLoadBalancer,
+			class:       ptr.To("example.com/azure"),
+			expected:    true,
+		},
+		{
+			desc:        "LoadBalancer service of a class other than the accepted ones",
+			classes:     []string{"example.com/azure"},
+			serviceType: v1.ServiceTypeLoadBalancer,
+			class:       ptr.To("metallb.io/metallb"),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			az := GetTestCloud(ctrl)
+			az.SetLoadBalancerClasses(tc.classes)
+			service := &v1.Service{Spec: v1.ServiceSpec{Type: tc.serviceType, LoadBalancerClass: tc.class}}
+			assert.Equal(t, tc.expected, az.wantsLoadBalancer(service))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// getServiceAdditionalPIPNames returns the additional public IP names set by the
+// azure-additional-pip-names annotation, followed by the name of the public IP allocated
+// from the azure-additional-pip-prefix-id annotation. Additional public IPs are only
+// supported on external services.
+func getServiceAdditionalPIPNames(service *v1.Service) []string {
+	if service == nil || requiresInternalLoadBalancer(service) {
+		return nil
+	}
+
+	var pipNames []string
+	candidates := strings.Split(service.Annotations[consts.ServiceAnnotationAdditionalPIPNames], ",")
+	candidates = append(candidates, getServiceAdditionalPrefixPIPName(service))
+	for _, pipName := range candidates {
+		pipName = strings.TrimSpace(pipName)
+		if pipName == "" {
+			continue
+		}
+		var duplicated bool
+		for _, existing := range pipNames {
+			if strings.EqualFold(existing, pipName) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			pipNames = append(pipNames, pipName)
+		}
+	}
+	return pipNames
+}
+
+// getServiceAdditionalPrefixPIPName returns the name of the public IP allocated from the
+// prefix set by the azure-additional-pip-prefix-id annotation, or "" if it is not set.
+func getServiceAdditionalPrefixPIPName(service *v1.Service) string {
+	if service == nil || requiresInternalLoadBalancer(service) {
+		return ""
+	}
+	id := strings.TrimSpace(service.Annotations[consts.ServiceAnnotationAdditionalPIPPrefixID])
+	if id == "" {
+		return ""
+	}
+	prefixName, err := getLastSegment(id, "/")
+	if err != nil {
+		klog.ErrorS(err, "getServiceAdditionalPrefixPIPName: invalid public IP prefix ID", "service", getServiceName(service), "publicIPPrefixID", id)
+		return ""
+	}
+	pipName := fmt.Sprintf("%s-%s", cloudprovider.DefaultLoadBalancerName(service), prefixName)
+	return truncateLoadBalancerResourceName(pipName, consts.PIPPrefixNameMaxLength)
+}
+
+// isServiceAdditionalPIPName returns true if the public IP is one of the service's additional public IPs.
+func isServiceAdditionalPIPName(service *v1.Service, pipName string) bool {
+	for _, name := range getServiceAdditionalPIPNames(service) {
+		if strings.EqualFold(name, pipName) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateLoadBalancerResourceName cuts the name to maxLength. If the cut name does
+// not end with a letter or '_', the last char is replaced with "_".
+func truncateLoadBalancerResourceName(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	name = name[:maxLength]
+	if !unicode.IsLetter(rune(name[len(name)-1])) && name[len(name)-1] != '_' {
+		name = name[:len(name)-1] + "_"
+	}
+	return name
+}
+
+// getAdditionalFrontendIPConfigName returns the name of the frontend IP configuration
+// of the given additional public IP.
+func (az *Cloud) getAdditionalFrontendIPConfigName(service *v1.Service, pipName string) string {
+	baseName := az.GetLoadBalancerName(context.TODO(), "", service)
+	return truncateLoadBalancerResourceName(fmt.Sprintf("%s-%s", baseName, pipName), consts.FrontendIPConfigNameMaxLength)
+}
+
+// getAdditionalFrontendLBRuleName returns the name of the load balancing rule that
+// mirrors the given rule on the frontend of the additional public IP.
+func getAdditionalFrontendLBRuleName(ruleName, pipName string) string {
+	return truncateLoadBalancerResourceName(fmt.Sprintf("%s-%s", ruleName, pipName), consts.LoadBalancerRuleNameMaxLength)
+}
+
+// getAdditionalFrontendIPConfigPIPName returns the additional public IP name if the
+// frontend IP configuration is created for one of the service's additional public IPs.
+func (az *Cloud) getAdditionalFrontendIPConfigPIPName(service *v1.Service, fip *armnetwork.FrontendIPConfiguration) (string, bool) {
+	if fip == nil {
+		return "", false
+	}
+	for _, pipName := range getServiceAdditionalPIPNames(service) {
+		if strings.EqualFold(ptr.Deref(fip.Name, ""), az.getAdditionalFrontendIPConfigName(service, pipName)) {
+			return pipName, true
+		}
+	}
+	return "", false
+}
+
+// isAdditionalFrontendIPChanged returns true if the frontend IP configuration does not
+// reference the additional public IP it is created for.
+func (az *Cloud) isAdditionalFrontendIPChanged(ctx context.Context, service *v1.Service, fip *armnetwork.FrontendIPConfiguration, pipName string) (bool, error) {
+	pip, existsPip, err := az.getPublicIPAddress(ctx, az.getPublicIPAddressResourceGroup(service), pipName, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return false, err
+	}
+	if !existsPip {
+		return true, nil
+	}
+	return fip.Properties == nil ||
+		fip.Properties.PublicIPAddress == nil ||
+		!strings.EqualFold(ptr.Deref(pip.ID, ""), ptr.Deref(fip.Properties.PublicIPAddress.ID, "")), nil
+}
+
+// ensureAdditionalFrontendIPConfigs appends the missing frontend IP configurations of
+// the service's additional public IPs to fipConfigs. The public IPs must exist.
+func (az *Cloud) ensureAdditionalFrontendIPConfigs(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	lb *armnetwork.LoadBalancer,
+	fipConfigs []*armnetwork.FrontendIPConfiguration,
+) ([]*armnetwork.FrontendIPConfiguration, bool, error) {
+	var changed bool
+	serviceName := getServiceName(service)
+	for _, pipName := range getServiceAdditionalPIPNames(service) {
+		fipConfigName := az.getAdditionalFrontendIPConfigName(service, pipName)
+		var found bool
+		for _, fipConfig := range fipConfigs {
+			if strings.EqualFold(ptr.Deref(fipConfig.Name, ""), fipConfigName) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		pip, err := az.ensureAdditionalPublicIPExists(ctx, clusterName, service, pipName)
+		if err != nil {
+			return fipConfigs, changed, err
+		}
+		fipConfigs = append(fipConfigs, &armnetwork.FrontendIPConfiguration{
+			Name: ptr.To(fipConfigName),
+			ID:   ptr.To(az.getFrontendIPConfigID(ptr.Deref(lb.Name, ""), fipConfigName)),
+			Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+				PublicIPAddress: &armnetwork.PublicIPAddress{ID: pip.ID},
+			},
+		})
+		klog.V(2).InfoS("ensureAdditionalFrontendIPConfigs: adding the frontend ip config of the additional public IP", "service", serviceName, "frontendIPConfig", fipConfigName, "pip", pipName)
+		changed = true
+	}
+	return fipConfigs, changed, nil
+}
+
+// ensureAdditionalPublicIPExists ensures the additional public IP of the service. The public IP
+// allocated from the additional public IP prefix is created if it is missing, the others must exist.
+func (az *Cloud) ensureAdditionalPublicIPExists(ctx context.Context, clusterName string, service *v1.Service, pipName string) (*armnetwork.PublicIPAddress, error) {
+	if !strings.EqualFold(pipName, getServiceAdditionalPrefixPIPName(service)) {
+		return az.ensurePublicIPExists(ctx, service, pipName, "", clusterName, true, false, false)
+	}
+
+	// Allocate the public IP the same way as the primary one from the azure-pip-prefix-id annotation.
+	prefixService := service.DeepCopy()
+	prefixService.Annotations[consts.ServiceAnnotationPIPPrefixIDDualStack[false]] = service.Annotations[consts.ServiceAnnotationAdditionalPIPPrefixID]
+	return az.ensurePublicIPExists(ctx, prefixService, pipName, "", clusterName, false, false, false)
+}
+
+// getExpectedAdditionalFrontendLBRules returns a copy of the IPv4 load balancing rules
+// for each frontend IP configuration of the service's additional public IPs.
+func (az *Cloud) getExpectedAdditionalFrontendLBRules(
+	lb *armnetwork.LoadBalancer,
+	service *v1.Service,
+	rules []*armnetwork.LoadBalancingRule,
+) ([]*armnetwork.LoadBalancingRule, error) {
+	lbName := ptr.Deref(lb.Name, "")
+	var expectedRules []*armnetwork.LoadBalancingRule
+	for _, pipName := range getServiceAdditionalPIPNames(service) {
+		fipConfigID := az.getFrontendIPConfigID(lbName, az.getAdditionalFrontendIPConfigName(service, pipName))
+		if err := az.checkLoadBalancerResourcesConflicts(lb, fipConfigID, service); err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if rule.Properties == nil {
+				continue
+			}
+			props := *rule.Properties
+			props.FrontendIPConfiguration = &armnetwork.SubResource{ID: ptr.To(fipConfigID)}
+			expectedRules = append(expectedRules, &armnetwork.LoadBalancingRule{
+				Name:       ptr.To(getAdditionalFrontendLBRuleName(ptr.Deref(rule.Name, ""), pipName)),
+				Properties: &props,
+			})
+		}
+	}
+	return expectedRules, nil
+}
+
+// getAdditionalFrontendIPs returns the IP addresses of the additional public IPs
+// referenced by the service's frontend IP configurations on the load balancer.
+func (az *Cloud) getAdditionalFrontendIPs(ctx context.Context, service *v1.Service, lb *armnetwork.LoadBalancer) ([]string, error) {
+	if lb == nil || lb.Properties == nil {
+		return nil, nil
+	}
+
+	var ips []string
+	for _, fipConfig := range lb.Properties.FrontendIPConfigurations {
+		pipName, ok := az.getAdditionalFrontendIPConfigPIPName(service, fipConfig)
+		if !ok {
+			continue
+		}
+		pip, existsPip, err := az.getPublicIPAddress(ctx, az.getPublicIPAddressResourceGroup(service), pipName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return nil, err
+		}
+		if existsPip && pip.Properties != nil && ptr.Deref(pip.Properties.IPAddress, "") != "" {
+			ips = append(ips, *pip.Properties.IPAddress)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	servicehelpers "k8s.io/cloud-provider/service/helpers"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+// GetServicesWithMissingFrontends returns the ingress IPs in the status of the LoadBalancer services which
+// are not found on the frontend IP configurations of their load balancers, by the keys of the services.
+// The frontends and the public IPs deleted out of band are not recreated until the services are changed,
+// since the service controller doesn't reconcile the load balancers periodically.
+func (az *Cloud) GetServicesWithMissingFrontends(ctx context.Context, clusterName string) (map[string][]string, error) {
+	if az.serviceLister == nil {
+		return nil, nil
+	}
+	ctx = withARMCaller(ctx, armCallerBackground)
+	logger := log.FromContextOrBackground(ctx).WithName("GetServicesWithMissingFrontends")
+	services, err := az.serviceLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("list the services: %w", err)
+	}
+
+	var existingLBs []*armnetwork.LoadBalancer
+	listed := false
+	missing := make(map[string][]string)
+	for _, service := range services {
+		if !az.hasFrontendToCheck(service) {
+			continue
+		}
+		// the load balancers are only listed if there are services to check
+		if !listed {
+			if !az.armRequestBudget.allowBackground() {
+				logger.V(2).Info("Skipped as the ARM request budget is nearly consumed")
+				return nil, nil
+			}
+			if existingLBs, err = az.listLoadBalancersOfCluster(ctx); err != nil {
+				return nil, err
+			}
+			listed = true
+		}
+		missingIPs, err := az.getMissingFrontendIPs(ctx, clusterName, service, existingLBs)
+		if err != nil {
+			logger.Error(err, "Failed to check the frontends of the service", "service", getServiceName(service))
+			continue
+		}
+		if len(missingIPs) > 0 {
+			missing[getServiceName(service)] = missingIPs
+		}
+	}
+	return missing, nil
+}
+
+// RepairServiceFrontend reconciles the load balancer of the service to recreate its missing frontend IP
+// configurations and public IPs, and updates the ingress IPs in the status of the service if they are
+// reallocated. The frontends are checked again before, and nothing is done if they are found.
+func (az *Cloud) RepairServiceFrontend(ctx context.Context, clusterName, key string, nodes []*v1.Node) error {
+	if az.serviceLister == nil {
+		return fmt.Errorf("RepairServiceFrontend: the service informer is not set")
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	service, err := az.serviceLister.Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !az.hasFrontendToCheck(service) {
+		return nil
+	}
+
+	checkCtx := withARMCaller(ctx, armCallerBackground)
+	existingLBs, err := az.listLoadBalancersOfCluster(checkCtx)
+	if err != nil {
+		return err
+	}
+	missingIPs, err := az.getMissingFrontendIPs(checkCtx, clusterName, service, existingLBs)
+	if err != nil || len(missingIPs) == 0 {
+		return err
+	}
+
+	log.FromContextOrBackground(ctx).WithName("RepairServiceFrontend").V(2).Info("Reconciling the load balancer of the service whose frontends are missing", "service", key, "ingressIPs", missingIPs)
+	// the public IPs deleted out of band may still be cached
+	_ = az.pipCache.Delete(az.getPublicIPAddressResourceGroup(service))
+	status, err := az.EnsureLoadBalancer(ctx, clusterName, service, az.getLoadBalancerNodes(nodes))
+	if err != nil {
+		az.Event(service, v1.EventTypeWarning, "FrontendRepairFailed", fmt.Sprintf("Failed to recreate the load balancer frontends of ingress IPs %s deleted out of band: %v", strings.Join(missingIPs, ", "), err))
+		return fmt.Errorf("reconcile the load balancer of service %s: %w", key, err)
+	}
+
+	oldIPs := getLoadBalancerStatusIPs(&service.Status.LoadBalancer)
+	if status == nil || servicehelpers.LoadBalancerStatusEqual(&service.Status.LoadBalancer, status) {
+		az.Event(service, v1.EventTypeNormal, "FrontendRepaired", fmt.Sprintf("Recreated the load balancer frontends of ingress IPs %s deleted out of band", strings.Join(missingIPs, ", ")))
+		return nil
+	}
+	// the service controller only updates the status after its own reconciliations
+	if az.KubeClient == nil {
+		return fmt.Errorf("update the status of service %s: the kube client is not set", key)
+	}
+	updated := service.DeepCopy()
+	updated.Status.LoadBalancer = *status
+	if _, err := servicehelpers.PatchService(az.KubeClient.CoreV1(), service, updated); err != nil {
+		return fmt.Errorf("update the status of service %s: %w", key, err)
+	}
+	az.Event(service, v1.EventTypeNormal, "FrontendRepaired", fmt.Sprintf("Recreated the load balancer frontends deleted out of band, the ingress IPs are changed from %s to %s",
+		strings.Join(oldIPs, ", "), strings.Join(getLoadBalancerStatusIPs(status), ", ")))
+	return nil
+}
+
+// hasFrontendToCheck returns true if the service is a LoadBalancer service with ingress IPs in its status,
+// which is not being deleted.
+func (az *Cloud) hasFrontendToCheck(service *v1.Service) bool {
+	return az.wantsLoadBalancer(service) && service.DeletionTimestamp == nil &&
+		len(getLoadBalancerStatusIPs(&service.Status.LoadBalancer)) > 0
+}
+
+// listLoadBalancersOfCluster lists the load balancers in the load balancer resource group.
+func (az *Cloud) listLoadBalancersOfCluster(ctx context.Context) ([]*armnetwork.LoadBalancer, error) {
+	rgName := az.getLoadBalancerResourceGroup()
+	lbs, err := az.NetworkClientFactory.GetLoadBalancerClient().List(ctx, rgName)
+	if err != nil {
+		if exist, checkErr := errutils.CheckResourceExistsFromAzcoreError(err); !exist && checkErr == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list the load balancers in resource group %s: %w", rgName, errutils.WithARMRequestIDs(err))
+	}
+	return az.excludeRegionalLoadBalancers(lbs), nil
+}
+
+// getMissingFrontendIPs returns the ingress IPs in the status of the service which are not found on the
+// frontend IP configurations of its load balancer.
+func (az *Cloud) getMissingFrontendIPs(ctx context.Context, clusterName string, service *v1.Service, existingLBs []*armnetwork.LoadBalancer) ([]string, error) {
+	_, _, status, _, _, _, err := az.getServiceLoadBalancer(ctx, service, clusterName, nil, false, existingLBs)
+	if err != nil {
+		return nil, err
+	}
+	frontendIPs := sets.New(getLoadBalancerStatusIPs(status)...)
+	regionalIPs, err := az.getRegionalFrontendIPs(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	frontendIPs.Insert(regionalIPs...)
+	var missingIPs []string
+	for _, ip := range getLoadBalancerStatusIPs(&service.Status.LoadBalancer) {
+		if !frontendIPs.Has(ip) {
+			missingIPs = append(missingIPs, ip)
+		}
+	}
+	return missingIPs, nil
+}
+
+// getLoadBalancerStatusIPs returns the ingress IPs of the load balancer status.
+func getLoadBalancerStatusIPs(status *v1.LoadBalancerStatus) []string {
+	if status == nil {
+		return nil
+	}
+	var ips []string
+	for _, ingress := range status.Ingress {
+		if ingress.IP != "" {
+			ips = append(ips, ingress.IP)
+		}
+	}
+	return ips
+}
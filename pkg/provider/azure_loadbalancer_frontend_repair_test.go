@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+)
+
+func newTestFrontendRepairService(name, ingressIP string) *v1.Service {
+	service := getInternalTestService(name, 80)
+	if ingressIP != "" {
+		service.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: ingressIP}}
+	}
+	return &service
+}
+
+func getTestFrontendRepairCloud(ctrl *gomock.Controller, services ...*v1.Service) *Cloud {
+	az := GetTestCloud(ctrl)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, service := range services {
+		_ = indexer.Add(service)
+	}
+	az.serviceLister = corelisters.NewServiceLister(indexer)
+	return az
+}
+
+func newTestFrontendRepairLB() *armnetwork.LoadBalancer {
+	return &armnetwork.LoadBalancer{
+		Name: ptr.To("testCluster-internal"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{
+					Name: ptr.To("aservice1"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PrivateIPAddress: ptr.To("10.0.0.6"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetServicesWithMissingFrontends(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterIPService := newTestFrontendRepairService("service4", "10.0.0.9")
+	clusterIPService.Spec.Type = v1.ServiceTypeClusterIP
+	az := getTestFrontendRepairCloud(ctrl,
+		newTestFrontendRepairService("service1", "10.0.0.6"),
+		newTestFrontendRepairService("service2", "10.0.0.7"),
+		// the services without ingress IPs are not provisioned yet
+		newTestFrontendRepairService("service3", ""),
+		clusterIPService,
+	)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{newTestFrontendRepairLB()}, nil)
+
+	missing, err := az.GetServicesWithMissingFrontends(context.Background(), testClusterName)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"default/service2": {"10.0.0.7"}}, missing)
+}
+
+func TestGetServicesWithMissingFrontendsSkipsListWithoutServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := getTestFrontendRepairCloud(ctrl, newTestFrontendRepairService("service3", ""))
+	missing, err := az.GetServicesWithMissingFrontends(context.Background(), testClusterName)
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestRepairServiceFrontend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := getTestFrontendRepairCloud(ctrl,
+		newTestFrontendRepairService("service1", "10.0.0.6"),
+		newTestFrontendRepairService("service2", "10.0.0.7"),
+	)
+	recorder := record.NewFakeRecorder(10)
+	az.eventRecorder = recorder
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+
+	// the deleted services are not repaired
+	assert.NoError(t, az.RepairServiceFrontend(context.Background(), testClusterName, "default/deleted", nil))
+
+	// the service is not reconciled if its frontends are found again
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{newTestFrontendRepairLB()}, nil)
+	assert.NoError(t, az.RepairServiceFrontend(context.Background(), testClusterName, "default/service1", nil))
+	assert.Empty(t, recorder.Events)
+
+	// the failed reconciliation of the service with missing frontends is reported by an event
+	gomock.InOrder(
+		mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{newTestFrontendRepairLB()}, nil),
+		mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return(nil, errors.New("list error")).AnyTimes(),
+	)
+	err := az.RepairServiceFrontend(context.Background(), testClusterName, "default/service2", nil)
+	assert.ErrorContains(t, err, "list error")
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	assert.Contains(t, events, "Warning FrontendRepairFailed Failed to recreate the load balancer frontends of ingress IPs 10.0.0.7 deleted out of band: reconcileLoadBalancer: failed to list managed LB: list error")
+}
+
+func TestGetLoadBalancerStatusIPs(t *testing.T) {
+	assert.Nil(t, getLoadBalancerStatusIPs(nil))
+	assert.Equal(t, []string{"10.0.0.6", "fd00::6"}, getLoadBalancerStatusIPs(&v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "10.0.0.6"}, {Hostname: "example.com"}, {IP: "fd00::6"}},
+	}))
+}
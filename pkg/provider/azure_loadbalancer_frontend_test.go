@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestGetServiceAdditionalPIPNames(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			desc: "should return nil when the annotation is not set",
+		},
+		{
+			desc: "should trim spaces and dedup names",
+			annotations: map[string]string{
+				consts.ServiceAnnotationAdditionalPIPNames: "pip1, pip2,,PIP1 ",
+			},
+			expected: []string{"pip1", "pip2"},
+		},
+		{
+			desc: "should append the public IP allocated from the prefix",
+			annotations: map[string]string{
+				consts.ServiceAnnotationAdditionalPIPNames:    "pip1",
+				consts.ServiceAnnotationAdditionalPIPPrefixID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPPrefixes/prefix1",
+			},
+			expected: []string{"pip1", "atest1-prefix1"},
+		},
+		{
+			desc: "should return nil for internal services",
+			annotations: map[string]string{
+				consts.ServiceAnnotationAdditionalPIPNames:   "pip1",
+				consts.ServiceAnnotationLoadBalancerInternal: consts.TrueAnnotationValue,
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := getTestService("test1", "TCP", tc.annotations, false, 80)
+			assert.Equal(t, tc.expected, getServiceAdditionalPIPNames(&svc))
+		})
+	}
+}
+
+func TestGetAdditionalFrontendIPConfigName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	svc := getTestService("test1", "TCP", nil, false, 80)
+	assert.Equal(t, "atest1-pip1", az.getAdditionalFrontendIPConfigName(&svc, "pip1"))
+
+	name := az.getAdditionalFrontendIPConfigName(&svc, strings.Repeat("p", 100)+"-1")
+	assert.Len(t, name, consts.FrontendIPConfigNameMaxLength)
+	assert.True(t, strings.HasPrefix(name, "atest1-"))
+
+	fip := &armnetwork.FrontendIPConfiguration{Name: ptr.To("atest1-pip1")}
+	_, ok := az.getAdditionalFrontendIPConfigPIPName(&svc, fip)
+	assert.False(t, ok)
+
+	svc.Annotations[consts.ServiceAnnotationAdditionalPIPNames] = "pip1"
+	pipName, ok := az.getAdditionalFrontendIPConfigPIPName(&svc, fip)
+	assert.True(t, ok)
+	assert.Equal(t, "pip1", pipName)
+}
+
+func TestEnsureAdditionalPublicIPExistsFromPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.regionZonesMap = map[string][]string{az.Location: {}}
+
+	prefixID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPPrefixes/prefix1"
+	svc := getTestService("test1", "TCP", map[string]string{
+		consts.ServiceAnnotationAdditionalPIPPrefixID: prefixID,
+	}, false, 80)
+	pipName := getServiceAdditionalPrefixPIPName(&svc)
+	assert.Equal(t, "atest1-prefix1", pipName)
+
+	mockPIPsClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockPIPsClient.EXPECT().List(gomock.Any(), "rg").Return([]*armnetwork.PublicIPAddress{}, nil).AnyTimes()
+	var createdPIP armnetwork.PublicIPAddress
+	mockPIPsClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", pipName, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, pip armnetwork.PublicIPAddress) (*armnetwork.PublicIPAddress, error) {
+			createdPIP = pip
+			return nil, nil
+		})
+	mockPIPsClient.EXPECT().Get(gomock.Any(), "rg", pipName, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ string, _ *string) (*armnetwork.PublicIPAddress, error) {
+			return &createdPIP, nil
+		})
+
+	pip, err := az.ensureAdditionalPublicIPExists(context.TODO(), testClusterName, &svc, pipName)
+	assert.NoError(t, err)
+	assert.NotNil(t, pip)
+	if assert.NotNil(t, createdPIP.Properties) && assert.NotNil(t, createdPIP.Properties.PublicIPPrefix) {
+		assert.Equal(t, prefixID, ptr.Deref(createdPIP.Properties.PublicIPPrefix.ID, ""))
+	}
+	assert.Equal(t, "default/test1", ptr.Deref(createdPIP.Tags[consts.ServiceTagKey], ""))
+	// the service itself should not be changed
+	assert.NotContains(t, svc.Annotations, consts.ServiceAnnotationPIPPrefixIDDualStack[false])
+}
+
+func TestGetExpectedAdditionalFrontendLBRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	svc := getTestService("test1", "TCP", map[string]string{
+		consts.ServiceAnnotationAdditionalPIPNames: "pip1,pip2",
+	}, false, 80)
+	lb := &armnetwork.LoadBalancer{
+		Name:       ptr.To("lb"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{},
+	}
+	rules := []*armnetwork.LoadBalancingRule{
+		{
+			Name: ptr.To("atest1-TCP-80"),
+			Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+				Protocol:     to.Ptr(armnetwork.TransportProtocolTCP),
+				FrontendPort: ptr.To(int32(80)),
+				FrontendIPConfiguration: &armnetwork.SubResource{
+					ID: ptr.To(az.getFrontendIPConfigID("lb", "atest1")),
+				},
+			},
+		},
+	}
+
+	additionalRules, err := az.getExpectedAdditionalFrontendLBRules(lb, &svc, rules)
+	assert.NoError(t, err)
+	assert.Len(t, additionalRules, 2)
+	for i, pipName := range []string{"pip1", "pip2"} {
+		assert.Equal(t, "atest1-TCP-80-"+pipName, ptr.Deref(additionalRules[i].Name, ""))
+		assert.Equal(t, az.getFrontendIPConfigID("lb", "atest1-"+pipName), ptr.Deref(additionalRules[i].Properties.FrontendIPConfiguration.ID, ""))
+		assert.Equal(t, int32(80), ptr.Deref(additionalRules[i].Properties.FrontendPort, 0))
+	}
+	// the original rule should not be changed
+	assert.Equal(t, az.getFrontendIPConfigID("lb", "atest1"), ptr.Deref(rules[0].Properties.FrontendIPConfiguration.ID, ""))
+}
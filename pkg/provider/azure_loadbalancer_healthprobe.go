@@ -31,18 +31,104 @@ import (
 )
 
 func (az *Cloud) buildClusterServiceSharedProbe() *armnetwork.Probe {
+	probeInterval, numberOfProbes := az.getDefaultHealthProbeIntervalAndNumOfProbe(nil)
 	return &armnetwork.Probe{
 		Name: ptr.To(consts.SharedProbeName),
 		Properties: &armnetwork.ProbePropertiesFormat{
 			Protocol:          to.Ptr(armnetwork.ProbeProtocolHTTP),
 			Port:              ptr.To(az.ClusterServiceSharedLoadBalancerHealthProbePort),
 			RequestPath:       ptr.To(az.ClusterServiceSharedLoadBalancerHealthProbePath),
-			IntervalInSeconds: ptr.To(consts.HealthProbeDefaultProbeInterval),
-			ProbeThreshold:    ptr.To(consts.HealthProbeDefaultNumOfProbe),
+			IntervalInSeconds: ptr.To(probeInterval),
+			ProbeThreshold:    ptr.To(numberOfProbes),
 		},
 	}
 }
 
+// buildClusterServiceHealthProbe returns the health probe used by all the load balancing rules of the
+// cluster service, and whether it is the shared probe. It is the probe on the node port of the
+// health probe node port annotation if set, or else the shared probe in the shared health probe mode,
+// which is set by the health probe mode annotation or else by the cloud config. It returns nil if the
+// node ports of the service are probed one by one.
+func (az *Cloud) buildClusterServiceHealthProbe(service *v1.Service, isIPv6 bool) (*armnetwork.Probe, bool, error) {
+	mode, err := consts.GetAttributeValueInSvcAnnotation(service.Annotations, consts.ServiceAnnotationLoadBalancerHealthProbeMode, func(val *string) error {
+		if !strings.EqualFold(*val, consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort) &&
+			!strings.EqualFold(*val, consts.ClusterServiceLoadBalancerHealthProbeModeShared) {
+			return fmt.Errorf("health probe mode %q is not supported, supported values are %q and %q", *val,
+				consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort, consts.ClusterServiceLoadBalancerHealthProbeModeShared)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse annotation %s: %w", consts.ServiceAnnotationLoadBalancerHealthProbeMode, err)
+	}
+
+	nodePort, err := consts.Getint32ValueFromK8sSvcAnnotation(service.Annotations, consts.ServiceAnnotationLoadBalancerHealthProbeNodePort, func(val *int32) error {
+		if *val < 1 || *val > 65535 {
+			return fmt.Errorf("port %d is out of range", *val)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse annotation %s: %w", consts.ServiceAnnotationLoadBalancerHealthProbeNodePort, err)
+	}
+
+	if nodePort != nil {
+		if mode != nil && strings.EqualFold(*mode, consts.ClusterServiceLoadBalancerHealthProbeModeShared) {
+			return nil, false, fmt.Errorf("annotation %s cannot be used together with the %s health probe mode",
+				consts.ServiceAnnotationLoadBalancerHealthProbeNodePort, consts.ClusterServiceLoadBalancerHealthProbeModeShared)
+		}
+		probe, err := az.buildHealthProbeForNodePort(service, *nodePort, isIPv6)
+		return probe, false, err
+	}
+
+	useSharedProbe := az.useSharedLoadBalancerHealthProbeMode()
+	if mode != nil {
+		useSharedProbe = strings.EqualFold(*mode, consts.ClusterServiceLoadBalancerHealthProbeModeShared)
+	}
+	if useSharedProbe {
+		return az.buildClusterServiceSharedProbe(), true, nil
+	}
+	return nil, false, nil
+}
+
+// buildHealthProbeForNodePort builds the health probe on the node port with the protocol and the
+// request path of the service level health probe annotations.
+func (az *Cloud) buildHealthProbeForNodePort(service *v1.Service, nodePort int32, isIPv6 bool) (*armnetwork.Probe, error) {
+	protocol, err := consts.GetAttributeValueInSvcAnnotation(service.Annotations, consts.ServiceAnnotationLoadBalancerHealthProbeProtocol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %w", consts.ServiceAnnotationLoadBalancerHealthProbeProtocol, err)
+	}
+	properties := &armnetwork.ProbePropertiesFormat{
+		Port:     ptr.To(nodePort),
+		Protocol: to.Ptr(armnetwork.ProbeProtocolTCP),
+	}
+	if protocol != nil {
+		switch p := strings.TrimSpace(*protocol); {
+		case strings.EqualFold(p, string(armnetwork.ProtocolHTTP)):
+			properties.Protocol = to.Ptr(armnetwork.ProbeProtocolHTTP)
+		case strings.EqualFold(p, string(armnetwork.ProtocolHTTPS)) && az.UseStandardLoadBalancer():
+			// HTTPS probe is only supported in standard loadbalancer
+			properties.Protocol = to.Ptr(armnetwork.ProbeProtocolHTTPS)
+		}
+	}
+	if *properties.Protocol != armnetwork.ProbeProtocolTCP {
+		path, err := consts.GetAttributeValueInSvcAnnotation(service.Annotations, consts.ServiceAnnotationLoadBalancerHealthProbeRequestPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse annotation %s: %w", consts.ServiceAnnotationLoadBalancerHealthProbeRequestPath, err)
+		}
+		properties.RequestPath = ptr.To(ptr.Deref(path, consts.HealthProbeDefaultRequestPath))
+	}
+
+	properties.IntervalInSeconds, properties.ProbeThreshold, err = az.getHealthProbeConfigProbeIntervalAndNumOfProbe(service, nodePort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse health probe config for node port %d: %w", nodePort, err)
+	}
+	return &armnetwork.Probe{
+		Name:       ptr.To(az.getLoadBalancerRuleName(service, v1.ProtocolTCP, nodePort, isIPv6)),
+		Properties: properties,
+	}, nil
+}
+
 // buildHealthProbeRulesForPort
 // for following SKU: basic loadbalancer vs standard load balancer
 // for following protocols: TCP HTTP HTTPS(SLB only)
@@ -223,7 +309,8 @@ func (az *Cloud) getHealthProbeConfigProbeIntervalAndNumOfProbe(serviceManifest
 // getHealthProbeConfigProbeInterval get probe interval in seconds
 // minimum probe interval in seconds is 5. ref: https://docs.microsoft.com/en-us/rest/api/load-balancer/load-balancers/create-or-update#probe
 // if probeInterval is not set, set it to default instead ref: https://docs.microsoft.com/en-us/rest/api/load-balancer/load-balancers/create-or-update#probe
-func (*Cloud) getHealthProbeConfigProbeInterval(serviceManifest *v1.Service, port int32) (*int32, error) {
+// the default is from the Windows node configuration if there are Windows nodes
+func (az *Cloud) getHealthProbeConfigProbeInterval(serviceManifest *v1.Service, port int32) (*int32, error) {
 	var probeIntervalValidator = func(val *int32) error {
 		const (
 			MinimumProbeIntervalInSecond = 5
@@ -244,7 +331,8 @@ func (*Cloud) getHealthProbeConfigProbeInterval(serviceManifest *v1.Service, por
 	}
 
 	if probeInterval == nil {
-		probeInterval = ptr.To(consts.HealthProbeDefaultProbeInterval)
+		defaultProbeInterval, _ := az.getDefaultHealthProbeIntervalAndNumOfProbe(serviceManifest)
+		probeInterval = ptr.To(defaultProbeInterval)
 	}
 	return probeInterval, nil
 }
@@ -252,7 +340,8 @@ func (*Cloud) getHealthProbeConfigProbeInterval(serviceManifest *v1.Service, por
 // getHealthProbeConfigNumOfProbe get number of probes
 // minimum number of unhealthy responses is 2. ref: https://docs.microsoft.com/en-us/rest/api/load-balancer/load-balancers/create-or-update#probe
 // if numberOfProbes is not set, set it to default instead ref: https://docs.microsoft.com/en-us/rest/api/load-balancer/load-balancers/create-or-update#probe
-func (*Cloud) getHealthProbeConfigNumOfProbe(serviceManifest *v1.Service, port int32) (*int32, error) {
+// the default is from the Windows node configuration if there are Windows nodes
+func (az *Cloud) getHealthProbeConfigNumOfProbe(serviceManifest *v1.Service, port int32) (*int32, error) {
 	var numOfProbeValidator = func(val *int32) error {
 		const (
 			MinimumNumOfProbe = 2
@@ -273,7 +362,8 @@ func (*Cloud) getHealthProbeConfigNumOfProbe(serviceManifest *v1.Service, port i
 	}
 
 	if numberOfProbes == nil {
-		numberOfProbes = ptr.To(consts.HealthProbeDefaultNumOfProbe)
+		_, defaultNumOfProbe := az.getDefaultHealthProbeIntervalAndNumOfProbe(serviceManifest)
+		numberOfProbes = ptr.To(defaultNumOfProbe)
 	}
 	return numberOfProbes, nil
 }
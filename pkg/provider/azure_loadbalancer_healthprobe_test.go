@@ -539,3 +539,113 @@ func TestShouldKeepSharedProbe(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildClusterServiceHealthProbe(t *testing.T) {
+	sharedProbe := &armnetwork.Probe{
+		Name: ptr.To(consts.SharedProbeName),
+		Properties: &armnetwork.ProbePropertiesFormat{
+			Protocol:          to.Ptr(armnetwork.ProbeProtocolHTTP),
+			Port:              ptr.To(int32(consts.ClusterServiceLoadBalancerHealthProbeDefaultPort)),
+			RequestPath:       ptr.To(consts.ClusterServiceLoadBalancerHealthProbeDefaultPath),
+			IntervalInSeconds: ptr.To(consts.HealthProbeDefaultProbeInterval),
+			ProbeThreshold:    ptr.To(consts.HealthProbeDefaultNumOfProbe),
+		},
+	}
+	for _, tc := range []struct {
+		desc           string
+		clusterMode    string
+		annotations    map[string]string
+		expectedProbe  *armnetwork.Probe
+		expectedShared bool
+		expectedErr    bool
+	}{
+		{
+			desc:        "no probe in the servicenodeport mode",
+			clusterMode: consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort,
+		},
+		{
+			desc:           "shared probe in the shared mode",
+			clusterMode:    consts.ClusterServiceLoadBalancerHealthProbeModeShared,
+			expectedProbe:  sharedProbe,
+			expectedShared: true,
+		},
+		{
+			desc:           "shared probe by the annotation",
+			clusterMode:    consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort,
+			annotations:    map[string]string{consts.ServiceAnnotationLoadBalancerHealthProbeMode: "Shared"},
+			expectedProbe:  sharedProbe,
+			expectedShared: true,
+		},
+		{
+			desc:        "opt out of the shared mode by the annotation",
+			clusterMode: consts.ClusterServiceLoadBalancerHealthProbeModeShared,
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerHealthProbeMode: consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort},
+		},
+		{
+			desc:        "probe on the node port overriding the shared mode",
+			clusterMode: consts.ClusterServiceLoadBalancerHealthProbeModeShared,
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerHealthProbeNodePort: "30000"},
+			expectedProbe: &armnetwork.Probe{
+				Name: ptr.To("atest1-TCP-30000"),
+				Properties: &armnetwork.ProbePropertiesFormat{
+					Protocol:          to.Ptr(armnetwork.ProbeProtocolTCP),
+					Port:              ptr.To(int32(30000)),
+					IntervalInSeconds: ptr.To(consts.HealthProbeDefaultProbeInterval),
+					ProbeThreshold:    ptr.To(consts.HealthProbeDefaultNumOfProbe),
+				},
+			},
+		},
+		{
+			desc:        "http probe on the node port",
+			clusterMode: consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort,
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerHealthProbeNodePort:    "10256",
+				consts.ServiceAnnotationLoadBalancerHealthProbeProtocol:    "http",
+				consts.ServiceAnnotationLoadBalancerHealthProbeRequestPath: "/healthz",
+				consts.ServiceAnnotationLoadBalancerHealthProbeInterval:    "10",
+			},
+			expectedProbe: &armnetwork.Probe{
+				Name: ptr.To("atest1-TCP-10256"),
+				Properties: &armnetwork.ProbePropertiesFormat{
+					Protocol:          to.Ptr(armnetwork.ProbeProtocolHTTP),
+					Port:              ptr.To(int32(10256)),
+					RequestPath:       ptr.To("/healthz"),
+					IntervalInSeconds: ptr.To(int32(10)),
+					ProbeThreshold:    ptr.To(consts.HealthProbeDefaultNumOfProbe),
+				},
+			},
+		},
+		{
+			desc:        "invalid mode",
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerHealthProbeMode: "foo"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid node port",
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerHealthProbeNodePort: "70000"},
+			expectedErr: true,
+		},
+		{
+			desc: "node port in the shared mode annotation",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerHealthProbeMode:     consts.ClusterServiceLoadBalancerHealthProbeModeShared,
+				consts.ServiceAnnotationLoadBalancerHealthProbeNodePort: "30000",
+			},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			az := GetTestCloud(ctrl)
+			az.ClusterServiceLoadBalancerHealthProbeMode = tc.clusterMode
+			az.ClusterServiceSharedLoadBalancerHealthProbePort = consts.ClusterServiceLoadBalancerHealthProbeDefaultPort
+			az.ClusterServiceSharedLoadBalancerHealthProbePath = consts.ClusterServiceLoadBalancerHealthProbeDefaultPath
+			svc := getTestService("test1", v1.ProtocolTCP, tc.annotations, false, 80)
+
+			probe, shared, err := az.buildClusterServiceHealthProbe(&svc, false)
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedProbe, probe)
+			assert.Equal(t, tc.expectedShared, shared)
+		})
+	}
+}
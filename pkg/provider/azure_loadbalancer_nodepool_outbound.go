@@ -0,0 +1,523 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+const (
+	// nodePoolOutboundResourcePrefix is the prefix of the names of the outbound rules, the backend pools
+	// and the frontend IP configurations managed for the node pool outbound configurations.
+	nodePoolOutboundResourcePrefix = "nodepool-outbound-"
+
+	// defaultNodePoolOutboundIdleTimeoutInMinutes is the default idle timeout of the node pool outbound rules.
+	defaultNodePoolOutboundIdleTimeoutInMinutes = 4
+	// maxNodePoolOutboundIdleTimeoutInMinutes is the max idle timeout of the outbound rules.
+	maxNodePoolOutboundIdleTimeoutInMinutes = 120
+	// maxNodePoolAllocatedOutboundPorts is the max number of the SNAT ports allocated to each backend IP.
+	maxNodePoolAllocatedOutboundPorts = 64000
+)
+
+// nodePoolOutboundService is the placeholder service the backend pools of the node pool outbound rules
+// are updated for, which is only used in the logs and metrics of the VM sets.
+var nodePoolOutboundService = &v1.Service{}
+
+// validateNodePoolOutboundConfigurations validates the node pool outbound configurations, and sets the
+// default idle timeouts.
+func validateNodePoolOutboundConfigurations(cfg *config.Config) error {
+	if len(cfg.NodePoolOutboundConfigurations) == 0 {
+		return nil
+	}
+	if !cfg.UseSingleStandardLoadBalancer() {
+		return errors.New("nodePoolOutboundConfigurations requires a single standard load balancer")
+	}
+	if !strings.EqualFold(cfg.LoadBalancerBackendPoolConfigurationType, consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration) {
+		return fmt.Errorf("nodePoolOutboundConfigurations requires loadBalancerBackendPoolConfigurationType %s", consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration)
+	}
+
+	names := sets.New[string]()
+	for i := range cfg.NodePoolOutboundConfigurations {
+		nodePool := &cfg.NodePoolOutboundConfigurations[i]
+		if nodePool.Name == "" {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] does not have a name", i)
+		}
+		if names.Has(strings.ToLower(nodePool.Name)) {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] has a duplicate name %s", i, nodePool.Name)
+		}
+		names.Insert(strings.ToLower(nodePool.Name))
+		if _, err := labels.Parse(nodePool.NodeSelector); err != nil {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] has an invalid nodeSelector %q: %w", i, nodePool.NodeSelector, err)
+		}
+		if len(nodePool.PublicIPAddressIDs) == 0 && len(nodePool.PublicIPPrefixIDs) == 0 {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] does not have publicIPAddressIDs or publicIPPrefixIDs", i)
+		}
+		frontendNames := sets.New[string]()
+		for _, ids := range []struct {
+			resourceType string
+			ids          []string
+		}{
+			{resourceType: "publicIPAddresses", ids: nodePool.PublicIPAddressIDs},
+			{resourceType: "publicIPPrefixes", ids: nodePool.PublicIPPrefixIDs},
+		} {
+			for _, id := range ids.ids {
+				resourceID, err := arm.ParseResourceID(id)
+				if err != nil || !strings.EqualFold(resourceID.ResourceType.Types[len(resourceID.ResourceType.Types)-1], ids.resourceType) {
+					return fmt.Errorf("nodePoolOutboundConfigurations[%d] has an invalid %s ID %q", i, ids.resourceType, id)
+				}
+				frontendName := strings.ToLower(getNodePoolOutboundFrontendName(nodePool.Name, id))
+				if frontendNames.Has(frontendName) {
+					return fmt.Errorf("nodePoolOutboundConfigurations[%d] has more than one public IP or prefix named %s", i, resourceID.Name)
+				}
+				frontendNames.Insert(frontendName)
+			}
+		}
+		if nodePool.AllocatedOutboundPorts < 0 || nodePool.AllocatedOutboundPorts > maxNodePoolAllocatedOutboundPorts || nodePool.AllocatedOutboundPorts%8 != 0 {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] allocatedOutboundPorts %d must be a multiple of 8 from 0 to %d", i, nodePool.AllocatedOutboundPorts, maxNodePoolAllocatedOutboundPorts)
+		}
+		if nodePool.IdleTimeoutInMinutes == 0 {
+			nodePool.IdleTimeoutInMinutes = defaultNodePoolOutboundIdleTimeoutInMinutes
+		}
+		if nodePool.IdleTimeoutInMinutes < defaultNodePoolOutboundIdleTimeoutInMinutes || nodePool.IdleTimeoutInMinutes > maxNodePoolOutboundIdleTimeoutInMinutes {
+			return fmt.Errorf("nodePoolOutboundConfigurations[%d] idleTimeoutInMinutes %d must be from %d to %d", i, nodePool.IdleTimeoutInMinutes, defaultNodePoolOutboundIdleTimeoutInMinutes, maxNodePoolOutboundIdleTimeoutInMinutes)
+		}
+	}
+	return nil
+}
+
+// getNodePoolOutboundResourceName returns the name of the outbound rule and the backend pool of the node pool.
+func getNodePoolOutboundResourceName(nodePoolName string) string {
+	return nodePoolOutboundResourcePrefix + nodePoolName
+}
+
+// getNodePoolOutboundFrontendName returns the name of the frontend IP configuration of the public IP or
+// prefix of the node pool.
+func getNodePoolOutboundFrontendName(nodePoolName, resourceID string) string {
+	return getNodePoolOutboundResourceName(nodePoolName) + "-" + resourceID[strings.LastIndex(resourceID, "/")+1:]
+}
+
+// isNodePoolOutboundResourceName returns true if the load balancer resource is managed for the node pool
+// outbound configurations.
+func isNodePoolOutboundResourceName(name *string) bool {
+	return strings.HasPrefix(strings.ToLower(ptr.Deref(name, "")), nodePoolOutboundResourcePrefix)
+}
+
+// ReconcileNodePoolOutbound reconciles the outbound rules of the node pool outbound configurations on the
+// single standard load balancer of the cluster, and the backend pools backing them with the nodes of the
+// node pools. The nodes are decoupled from the backend pools of the node pools they are not in anymore,
+// and the managed resources of the removed configurations are deleted. The configurations are rejected
+// while another outbound rule, e.g. the default outbound rule of the cluster, uses the backend pools of
+// the cluster: the nodes must stay in them for the load balancing rules, and an IP configuration can
+// only be used by one outbound rule.
+func (az *Cloud) ReconcileNodePoolOutbound(ctx context.Context, clusterName string, nodes []*v1.Node) error {
+	if !az.UseSingleStandardLoadBalancer() {
+		return nil
+	}
+	ctx = withARMCaller(ctx, armCallerBackground)
+	lbName := clusterName
+	if az.LoadBalancerName != "" {
+		lbName = az.LoadBalancerName
+	}
+	if len(az.NodePoolOutboundConfigurations) == 0 {
+		// the cached load balancer is checked for the resources of the removed configurations
+		lb, exists, err := az.getAzureLoadBalancer(ctx, lbName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return err
+		}
+		if !exists || !hasNodePoolOutboundResources(lb) {
+			return nil
+		}
+	}
+
+	// the outbound rules are updated after the reconciliations of the services updating the load balancer
+	az.serviceReconcileLock.Lock(reconcilePriorityNodeSync)
+	defer az.serviceReconcileLock.Unlock()
+
+	lb, exists, err := az.getAzureLoadBalancer(ctx, lbName, azcache.CacheReadTypeForceRefresh)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if len(az.NodePoolOutboundConfigurations) == 0 {
+			return nil
+		}
+		lb = az.newNodePoolOutboundLoadBalancer(lbName)
+	}
+	if lb.Properties == nil {
+		lb.Properties = &armnetwork.LoadBalancerPropertiesFormat{}
+	}
+
+	nodePoolNodes, nodePoolNames, err := az.getNodePoolOutboundNodes(nodes)
+	if err != nil {
+		return err
+	}
+	if len(nodePoolNodes) > 0 {
+		if ruleName, poolName, found := getClusterBackendPoolOutboundRule(lb, clusterName); found {
+			return fmt.Errorf("nodePoolOutboundConfigurations conflict with outbound rule %s of load balancer %s: "+
+				"the nodes of the node pools are in its backend pool %s, remove the outbound rule to use the node pool outbound configurations", ruleName, lbName, poolName)
+		}
+	}
+	vmSetName := az.mapLoadBalancerNameToVMSet(lbName, clusterName)
+	decoupled, err := az.decoupleNodePoolOutboundBackendPools(ctx, lb, nodePoolNames, vmSetName)
+	if err != nil {
+		return err
+	}
+	if decoupled {
+		if lb, exists, err = az.getAzureLoadBalancer(ctx, lbName, azcache.CacheReadTypeForceRefresh); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("load balancer %s not found", lbName)
+		}
+	}
+
+	if az.reconcileNodePoolOutboundResources(lb, lbName) {
+		log.FromContextOrBackground(ctx).WithName("ReconcileNodePoolOutbound").V(2).Info("Updating the node pool outbound rules of the load balancer", "loadBalancer", lbName)
+		_, err = az.NetworkClientFactory.GetLoadBalancerClient().CreateOrUpdate(ctx, az.getLoadBalancerResourceGroup(), lbName, cleanupSubnetInFrontendIPConfigurations(lb))
+		_ = az.lbCache.Delete(lbName)
+		if err != nil {
+			return fmt.Errorf("update load balancer %s: %w", lbName, errutils.WithARMRequestIDs(err))
+		}
+	}
+
+	for _, nodePool := range az.NodePoolOutboundConfigurations {
+		if len(nodePoolNodes[nodePool.Name]) == 0 {
+			continue
+		}
+		backendPoolID := az.getBackendPoolID(lbName, getNodePoolOutboundResourceName(nodePool.Name))
+		if err := az.VMSet.EnsureHostsInPool(ctx, nodePoolOutboundService, nodePoolNodes[nodePool.Name], backendPoolID, vmSetName); err != nil {
+			return fmt.Errorf("ensure the nodes of node pool %s in backend pool %s: %w", nodePool.Name, backendPoolID, err)
+		}
+	}
+	return nil
+}
+
+// getClusterBackendPoolOutboundRule returns the name of the outbound rule not managed for the node pool
+// outbound configurations which uses a backend pool of the cluster, and the name of the backend pool.
+func getClusterBackendPoolOutboundRule(lb *armnetwork.LoadBalancer, clusterName string) (string, string, bool) {
+	poolNames := sets.New[string]()
+	for _, poolName := range getBackendPoolNames(clusterName) {
+		poolNames.Insert(strings.ToLower(poolName))
+	}
+	for _, rule := range lb.Properties.OutboundRules {
+		if rule == nil || isNodePoolOutboundResourceName(rule.Name) || rule.Properties == nil || rule.Properties.BackendAddressPool == nil {
+			continue
+		}
+		poolID := ptr.Deref(rule.Properties.BackendAddressPool.ID, "")
+		poolName := poolID[strings.LastIndex(poolID, "/")+1:]
+		if poolNames.Has(strings.ToLower(poolName)) {
+			return ptr.Deref(rule.Name, ""), poolName, true
+		}
+	}
+	return "", "", false
+}
+
+// newNodePoolOutboundLoadBalancer returns the standard load balancer created for the outbound rules
+// before any service is exposed by it.
+func (az *Cloud) newNodePoolOutboundLoadBalancer(lbName string) *armnetwork.LoadBalancer {
+	lb := &armnetwork.LoadBalancer{
+		Name:       ptr.To(lbName),
+		Location:   ptr.To(az.Location),
+		SKU:        &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameStandard)},
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{},
+	}
+	if az.HasExtendedLocation() {
+		var typ *armnetwork.ExtendedLocationTypes
+		if getExtendedLocationTypeFromString(az.ExtendedLocationType) == armnetwork.ExtendedLocationTypesEdgeZone {
+			typ = ptr.To(armnetwork.ExtendedLocationTypesEdgeZone)
+		}
+		lb.ExtendedLocation = &armnetwork.ExtendedLocation{
+			Name: ptr.To(az.ExtendedLocationName),
+			Type: typ,
+		}
+	}
+	return lb
+}
+
+// getNodePoolOutboundNodes returns the nodes of the node pools by the names of the node pools, and the
+// names of the node pools by the lower case names of the nodes. The nodes excluded from the load balancer
+// are not in any node pool.
+func (az *Cloud) getNodePoolOutboundNodes(nodes []*v1.Node) (map[string][]*v1.Node, map[string]string, error) {
+	selectors := make([]labels.Selector, 0, len(az.NodePoolOutboundConfigurations))
+	for _, nodePool := range az.NodePoolOutboundConfigurations {
+		// the selectors are validated when the config is loaded
+		selector, err := labels.Parse(nodePool.NodeSelector)
+		if err != nil {
+			return nil, nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+
+	nodePoolNodes := make(map[string][]*v1.Node)
+	nodePoolNames := make(map[string]string)
+	for _, node := range nodes {
+		if az.ExcludeMasterNodesFromStandardLB() && isControlPlaneNode(node) {
+			continue
+		}
+		excluded, err := az.ShouldNodeExcludedFromLoadBalancer(node.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if excluded {
+			continue
+		}
+		for i, selector := range selectors {
+			if selector.Matches(labels.Set(node.Labels)) {
+				name := az.NodePoolOutboundConfigurations[i].Name
+				nodePoolNodes[name] = append(nodePoolNodes[name], node)
+				nodePoolNames[strings.ToLower(node.Name)] = name
+				break
+			}
+		}
+	}
+	return nodePoolNodes, nodePoolNames, nil
+}
+
+// decoupleNodePoolOutboundBackendPools decouples the nodes from the managed backend pools of the node pools
+// they are not in, and all the nodes from the backend pools of the removed configurations. It returns true
+// if any node is decoupled.
+func (az *Cloud) decoupleNodePoolOutboundBackendPools(ctx context.Context, lb *armnetwork.LoadBalancer, nodePoolNames map[string]string, vmSetName string) (bool, error) {
+	poolNames := sets.New[string]()
+	for _, nodePool := range az.NodePoolOutboundConfigurations {
+		poolNames.Insert(strings.ToLower(getNodePoolOutboundResourceName(nodePool.Name)))
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("decoupleNodePoolOutboundBackendPools")
+	decoupled := false
+	for _, bp := range lb.Properties.BackendAddressPools {
+		if bp == nil || !isNodePoolOutboundResourceName(bp.Name) || bp.Properties == nil || len(bp.Properties.BackendIPConfigurations) == 0 {
+			continue
+		}
+		poolName := strings.ToLower(ptr.Deref(bp.Name, ""))
+		if !poolNames.Has(poolName) {
+			logger.V(2).Info("Decoupling the nodes from the backend pool of a removed node pool", "backendPool", poolName)
+			updated, err := az.VMSet.EnsureBackendPoolDeleted(ctx, nodePoolOutboundService, []string{ptr.Deref(bp.ID, "")}, vmSetName, []*armnetwork.BackendAddressPool{bp}, true)
+			if err != nil {
+				return false, err
+			}
+			decoupled = decoupled || updated
+			continue
+		}
+
+		var ipConfigs []*armnetwork.InterfaceIPConfiguration
+		for _, ipConfig := range bp.Properties.BackendIPConfigurations {
+			ipConfigID := ptr.Deref(ipConfig.ID, "")
+			nodeName, _, err := az.VMSet.GetNodeNameByIPConfigurationID(ctx, ipConfigID)
+			if err != nil && !errors.Is(err, cloudprovider.InstanceNotFound) {
+				return false, err
+			}
+			nodePoolName, found := nodePoolNames[strings.ToLower(nodeName)]
+			if err != nil || !found || !strings.EqualFold(getNodePoolOutboundResourceName(nodePoolName), poolName) {
+				ipConfigs = append(ipConfigs, &armnetwork.InterfaceIPConfiguration{ID: ptr.To(ipConfigID)})
+			}
+		}
+		if len(ipConfigs) == 0 {
+			continue
+		}
+		logger.V(2).Info("Decoupling the IP configurations from the backend pool", "ipConfigurations", len(ipConfigs), "backendPool", poolName)
+		updated, err := az.VMSet.EnsureBackendPoolDeleted(ctx, nodePoolOutboundService, []string{ptr.Deref(bp.ID, "")}, vmSetName, []*armnetwork.BackendAddressPool{
+			{
+				ID:         bp.ID,
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{BackendIPConfigurations: ipConfigs},
+			},
+		}, false)
+		if err != nil {
+			return false, err
+		}
+		decoupled = decoupled || updated
+	}
+	return decoupled, nil
+}
+
+// hasNodePoolOutboundResources returns true if the load balancer has any resource managed for the node
+// pool outbound configurations.
+func hasNodePoolOutboundResources(lb *armnetwork.LoadBalancer) bool {
+	if lb.Properties == nil {
+		return false
+	}
+	for _, rule := range lb.Properties.OutboundRules {
+		if rule != nil && isNodePoolOutboundResourceName(rule.Name) {
+			return true
+		}
+	}
+	for _, bp := range lb.Properties.BackendAddressPools {
+		if bp != nil && isNodePoolOutboundResourceName(bp.Name) {
+			return true
+		}
+	}
+	for _, fip := range lb.Properties.FrontendIPConfigurations {
+		if fip != nil && isNodePoolOutboundResourceName(fip.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileNodePoolOutboundResources updates the managed frontend IP configurations, backend pools and
+// outbound rules of the load balancer to the node pool outbound configurations. The other resources of
+// the load balancer are kept. It returns true if the load balancer is changed.
+func (az *Cloud) reconcileNodePoolOutboundResources(lb *armnetwork.LoadBalancer, lbName string) bool {
+	var (
+		fips  []*armnetwork.FrontendIPConfiguration
+		pools []*armnetwork.BackendAddressPool
+		rules []*armnetwork.OutboundRule
+	)
+	for _, nodePool := range az.NodePoolOutboundConfigurations {
+		name := getNodePoolOutboundResourceName(nodePool.Name)
+		var fipIDs []*armnetwork.SubResource
+		addFrontend := func(id string, properties *armnetwork.FrontendIPConfigurationPropertiesFormat) {
+			fipName := getNodePoolOutboundFrontendName(nodePool.Name, id)
+			fips = append(fips, &armnetwork.FrontendIPConfiguration{
+				Name:       ptr.To(fipName),
+				Properties: properties,
+			})
+			fipIDs = append(fipIDs, &armnetwork.SubResource{ID: ptr.To(az.getFrontendIPConfigID(lbName, fipName))})
+		}
+		for _, id := range nodePool.PublicIPAddressIDs {
+			addFrontend(id, &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To(id)}})
+		}
+		for _, id := range nodePool.PublicIPPrefixIDs {
+			addFrontend(id, &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPPrefix: &armnetwork.SubResource{ID: ptr.To(id)}})
+		}
+
+		pools = append(pools, &armnetwork.BackendAddressPool{Name: ptr.To(name)})
+		rules = append(rules, &armnetwork.OutboundRule{
+			Name: ptr.To(name),
+			Properties: &armnetwork.OutboundRulePropertiesFormat{
+				Protocol:                 ptr.To(armnetwork.LoadBalancerOutboundRuleProtocolAll),
+				EnableTCPReset:           ptr.To(true),
+				AllocatedOutboundPorts:   ptr.To(nodePool.AllocatedOutboundPorts),
+				IdleTimeoutInMinutes:     ptr.To(nodePool.IdleTimeoutInMinutes),
+				FrontendIPConfigurations: fipIDs,
+				BackendAddressPool:       &armnetwork.SubResource{ID: ptr.To(az.getBackendPoolID(lbName, name))},
+			},
+		})
+	}
+
+	var fipsChanged, poolsChanged, rulesChanged bool
+	lb.Properties.FrontendIPConfigurations, fipsChanged = reconcileNodePoolOutboundList(lb.Properties.FrontendIPConfigurations, fips,
+		func(fip *armnetwork.FrontendIPConfiguration) *string { return fip.Name },
+		func(existing, wanted *armnetwork.FrontendIPConfiguration) bool {
+			return existing.Properties != nil &&
+				getPublicIPAddressID(existing.Properties.PublicIPAddress) == getPublicIPAddressID(wanted.Properties.PublicIPAddress) &&
+				getSubResourceID(existing.Properties.PublicIPPrefix) == getSubResourceID(wanted.Properties.PublicIPPrefix)
+		})
+	lb.Properties.BackendAddressPools, poolsChanged = reconcileNodePoolOutboundList(lb.Properties.BackendAddressPools, pools,
+		func(bp *armnetwork.BackendAddressPool) *string { return bp.Name },
+		func(_, _ *armnetwork.BackendAddressPool) bool { return true })
+	lb.Properties.OutboundRules, rulesChanged = reconcileNodePoolOutboundList(lb.Properties.OutboundRules, rules,
+		func(rule *armnetwork.OutboundRule) *string { return rule.Name },
+		isEqualNodePoolOutboundRule)
+	return fipsChanged || poolsChanged || rulesChanged
+}
+
+// reconcileNodePoolOutboundList replaces the managed resources in the existing ones with the wanted ones.
+// The existing resources equal to the wanted ones are kept as they are. It returns true if any resource
+// is added, updated or removed.
+func reconcileNodePoolOutboundList[T any](existing, wanted []*T, name func(*T) *string, equal func(existing, wanted *T) bool) ([]*T, bool) {
+	wantedByName := make(map[string]*T, len(wanted))
+	for _, resource := range wanted {
+		wantedByName[strings.ToLower(ptr.Deref(name(resource), ""))] = resource
+	}
+
+	changed := false
+	result := make([]*T, 0, len(existing)+len(wanted))
+	for _, resource := range existing {
+		if resource == nil || !isNodePoolOutboundResourceName(name(resource)) {
+			result = append(result, resource)
+			continue
+		}
+		key := strings.ToLower(ptr.Deref(name(resource), ""))
+		wantedResource, found := wantedByName[key]
+		switch {
+		case !found:
+			changed = true
+		case equal(resource, wantedResource):
+			result = append(result, resource)
+		default:
+			result = append(result, wantedResource)
+			changed = true
+		}
+		delete(wantedByName, key)
+	}
+	for _, resource := range wanted {
+		if _, found := wantedByName[strings.ToLower(ptr.Deref(name(resource), ""))]; found {
+			result = append(result, resource)
+			changed = true
+		}
+	}
+	return result, changed
+}
+
+// isEqualNodePoolOutboundRule returns true if the existing outbound rule has the wanted properties.
+func isEqualNodePoolOutboundRule(existing, wanted *armnetwork.OutboundRule) bool {
+	if existing.Properties == nil {
+		return false
+	}
+	e, w := existing.Properties, wanted.Properties
+	if ptr.Deref(e.Protocol, "") != ptr.Deref(w.Protocol, "") ||
+		ptr.Deref(e.EnableTCPReset, false) != ptr.Deref(w.EnableTCPReset, false) ||
+		ptr.Deref(e.AllocatedOutboundPorts, 0) != ptr.Deref(w.AllocatedOutboundPorts, 0) ||
+		ptr.Deref(e.IdleTimeoutInMinutes, 0) != ptr.Deref(w.IdleTimeoutInMinutes, 0) ||
+		getSubResourceID(e.BackendAddressPool) != getSubResourceID(w.BackendAddressPool) ||
+		len(e.FrontendIPConfigurations) != len(w.FrontendIPConfigurations) {
+		return false
+	}
+	fipIDs := sets.New[string]()
+	for _, fip := range e.FrontendIPConfigurations {
+		fipIDs.Insert(getSubResourceID(fip))
+	}
+	for _, fip := range w.FrontendIPConfigurations {
+		if !fipIDs.Has(getSubResourceID(fip)) {
+			return false
+		}
+	}
+	return true
+}
+
+// getSubResourceID returns the lower case ID of the referenced resource.
+func getSubResourceID(subResource *armnetwork.SubResource) string {
+	if subResource == nil {
+		return ""
+	}
+	return strings.ToLower(ptr.Deref(subResource.ID, ""))
+}
+
+// getPublicIPAddressID returns the lower case ID of the referenced public IP.
+func getPublicIPAddressID(pip *armnetwork.PublicIPAddress) string {
+	if pip == nil {
+		return ""
+	}
+	return strings.ToLower(ptr.Deref(pip.ID, ""))
+}
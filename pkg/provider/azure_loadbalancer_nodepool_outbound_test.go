@@ -0,0 +1,273 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+const (
+	testNodePoolOutboundPIPID    = "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/tenant-a"
+	testNodePoolOutboundPrefixID = "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/publicIPPrefixes/tenant-a-prefix"
+)
+
+func TestValidateNodePoolOutboundConfigurations(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		modify      func(cfg *config.Config)
+		expectedErr string
+	}{
+		{
+			desc: "valid configurations",
+		},
+		{
+			desc:        "basic load balancer",
+			modify:      func(cfg *config.Config) { cfg.LoadBalancerSKU = consts.LoadBalancerSKUBasic },
+			expectedErr: "requires a single standard load balancer",
+		},
+		{
+			desc: "node IP backend pools",
+			modify: func(cfg *config.Config) {
+				cfg.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIP
+			},
+			expectedErr: "requires loadBalancerBackendPoolConfigurationType nodeIPConfiguration",
+		},
+		{
+			desc:        "duplicate name",
+			modify:      func(cfg *config.Config) { cfg.NodePoolOutboundConfigurations[1].Name = "Tenant-A" },
+			expectedErr: "nodePoolOutboundConfigurations[1] has a duplicate name Tenant-A",
+		},
+		{
+			desc:        "invalid node selector",
+			modify:      func(cfg *config.Config) { cfg.NodePoolOutboundConfigurations[0].NodeSelector = "pool in" },
+			expectedErr: "nodePoolOutboundConfigurations[0] has an invalid nodeSelector",
+		},
+		{
+			desc: "no public IPs",
+			modify: func(cfg *config.Config) {
+				cfg.NodePoolOutboundConfigurations[0].PublicIPAddressIDs = nil
+				cfg.NodePoolOutboundConfigurations[0].PublicIPPrefixIDs = nil
+			},
+			expectedErr: "nodePoolOutboundConfigurations[0] does not have publicIPAddressIDs or publicIPPrefixIDs",
+		},
+		{
+			desc: "prefix ID as public IP ID",
+			modify: func(cfg *config.Config) {
+				cfg.NodePoolOutboundConfigurations[0].PublicIPAddressIDs = []string{testNodePoolOutboundPrefixID}
+			},
+			expectedErr: "nodePoolOutboundConfigurations[0] has an invalid publicIPAddresses ID",
+		},
+		{
+			desc:        "invalid allocated outbound ports",
+			modify:      func(cfg *config.Config) { cfg.NodePoolOutboundConfigurations[0].AllocatedOutboundPorts = 1001 },
+			expectedErr: "allocatedOutboundPorts 1001 must be a multiple of 8",
+		},
+		{
+			desc:        "invalid idle timeout",
+			modify:      func(cfg *config.Config) { cfg.NodePoolOutboundConfigurations[0].IdleTimeoutInMinutes = 121 },
+			expectedErr: "idleTimeoutInMinutes 121 must be from 4 to 120",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := &config.Config{
+				LoadBalancerSKU:                          consts.LoadBalancerSKUStandard,
+				LoadBalancerBackendPoolConfigurationType: consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration,
+				NodePoolOutboundConfigurations: []config.NodePoolOutboundConfiguration{
+					{Name: "tenant-a", NodeSelector: "pool=a", PublicIPAddressIDs: []string{testNodePoolOutboundPIPID}, PublicIPPrefixIDs: []string{testNodePoolOutboundPrefixID}},
+					{Name: "tenant-b", NodeSelector: "pool=b", PublicIPPrefixIDs: []string{testNodePoolOutboundPrefixID}, IdleTimeoutInMinutes: 30},
+				},
+			}
+			if tc.modify != nil {
+				tc.modify(cfg)
+			}
+			err := validateNodePoolOutboundConfigurations(cfg)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int32(defaultNodePoolOutboundIdleTimeoutInMinutes), cfg.NodePoolOutboundConfigurations[0].IdleTimeoutInMinutes)
+			assert.Equal(t, int32(30), cfg.NodePoolOutboundConfigurations[1].IdleTimeoutInMinutes)
+		})
+	}
+}
+
+func TestReconcileNodePoolOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.NodePoolOutboundConfigurations = []config.NodePoolOutboundConfiguration{
+		{Name: "tenant-a", NodeSelector: "pool=a", PublicIPAddressIDs: []string{testNodePoolOutboundPIPID}, IdleTimeoutInMinutes: 4},
+	}
+	mockVMSet := NewMockVMSet(ctrl)
+	az.VMSet = mockVMSet
+	mockVMSet.EXPECT().GetPrimaryVMSetName().Return("vmss").AnyTimes()
+
+	lbName := testClusterName
+	poolID := az.getBackendPoolID(lbName, "nodepool-outbound-tenant-a")
+	removedPoolID := az.getBackendPoolID(lbName, "nodepool-outbound-removed")
+	ipConfigA := "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/node-a/ipConfigurations/ipconfig1"
+	ipConfigB := "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/node-b/ipConfigurations/ipconfig1"
+	existingLB := &armnetwork.LoadBalancer{
+		Name: ptr.To(lbName),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{Name: ptr.To("outbound")},
+				{Name: ptr.To("nodepool-outbound-removed-pip"), Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To("pip")}}},
+			},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{Name: ptr.To(lbName), ID: ptr.To(az.getBackendPoolID(lbName, lbName))},
+				{
+					Name: ptr.To("nodepool-outbound-tenant-a"),
+					ID:   ptr.To(poolID),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						BackendIPConfigurations: []*armnetwork.InterfaceIPConfiguration{{ID: ptr.To(ipConfigA)}, {ID: ptr.To(ipConfigB)}},
+					},
+				},
+				{
+					Name: ptr.To("nodepool-outbound-removed"),
+					ID:   ptr.To(removedPoolID),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						BackendIPConfigurations: []*armnetwork.InterfaceIPConfiguration{{ID: ptr.To(ipConfigB)}},
+					},
+				},
+			},
+			OutboundRules: []*armnetwork.OutboundRule{
+				{Name: ptr.To("aksOutboundRule")},
+				{Name: ptr.To("nodepool-outbound-removed")},
+			},
+		},
+	}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"pool": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"pool": "b"}}},
+	}
+
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, lbName, gomock.Any()).Return(existingLB, nil).Times(2)
+	mockVMSet.EXPECT().GetNodeNameByIPConfigurationID(gomock.Any(), ipConfigA).Return("node-a", "vmss", nil)
+	mockVMSet.EXPECT().GetNodeNameByIPConfigurationID(gomock.Any(), ipConfigB).Return("node-b", "vmss", nil)
+	// the nodes are decoupled from the backend pool of the removed node pool, and from the ones of
+	// the node pools they are not in
+	mockVMSet.EXPECT().EnsureBackendPoolDeleted(gomock.Any(), gomock.Any(), []string{removedPoolID}, "vmss", gomock.Any(), true).Return(true, nil)
+	mockVMSet.EXPECT().EnsureBackendPoolDeleted(gomock.Any(), gomock.Any(), []string{poolID}, "vmss", gomock.Any(), false).
+		DoAndReturn(func(_ context.Context, _ *v1.Service, _ []string, _ string, pools []*armnetwork.BackendAddressPool, _ bool) (bool, error) {
+			assert.Equal(t, []*armnetwork.InterfaceIPConfiguration{{ID: ptr.To(ipConfigB)}}, pools[0].Properties.BackendIPConfigurations)
+			return true, nil
+		})
+	mockLBClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, lbName, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, lb armnetwork.LoadBalancer) (*armnetwork.LoadBalancer, error) {
+			fipID := az.getFrontendIPConfigID(lbName, "nodepool-outbound-tenant-a-tenant-a")
+			assert.Equal(t, []*armnetwork.FrontendIPConfiguration{
+				{Name: ptr.To("outbound")},
+				{
+					Name:       ptr.To("nodepool-outbound-tenant-a-tenant-a"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To(testNodePoolOutboundPIPID)}},
+				},
+			}, lb.Properties.FrontendIPConfigurations)
+			assert.Len(t, lb.Properties.BackendAddressPools, 2)
+			assert.Equal(t, poolID, ptr.Deref(lb.Properties.BackendAddressPools[1].ID, ""))
+			assert.Equal(t, []*armnetwork.OutboundRule{
+				{Name: ptr.To("aksOutboundRule")},
+				{
+					Name: ptr.To("nodepool-outbound-tenant-a"),
+					Properties: &armnetwork.OutboundRulePropertiesFormat{
+						Protocol:                 ptr.To(armnetwork.LoadBalancerOutboundRuleProtocolAll),
+						EnableTCPReset:           ptr.To(true),
+						AllocatedOutboundPorts:   ptr.To(int32(0)),
+						IdleTimeoutInMinutes:     ptr.To(int32(4)),
+						FrontendIPConfigurations: []*armnetwork.SubResource{{ID: ptr.To(fipID)}},
+						BackendAddressPool:       &armnetwork.SubResource{ID: ptr.To(poolID)},
+					},
+				},
+			}, lb.Properties.OutboundRules)
+			return &lb, nil
+		})
+	mockVMSet.EXPECT().EnsureHostsInPool(gomock.Any(), gomock.Any(), nodes[:1], poolID, "vmss").Return(nil)
+
+	assert.NoError(t, az.ReconcileNodePoolOutbound(context.Background(), testClusterName, nodes))
+}
+
+func TestReconcileNodePoolOutboundWithoutConfigurations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	// the load balancer without managed resources is not refreshed or updated
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, testClusterName, gomock.Any()).Return(&armnetwork.LoadBalancer{
+		Name: ptr.To(testClusterName),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			OutboundRules: []*armnetwork.OutboundRule{{Name: ptr.To("aksOutboundRule")}},
+		},
+	}, nil).Times(1)
+
+	assert.NoError(t, az.ReconcileNodePoolOutbound(context.Background(), testClusterName, nil))
+}
+
+func TestReconcileNodePoolOutboundConflictsWithClusterOutboundRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.NodePoolOutboundConfigurations = []config.NodePoolOutboundConfiguration{
+		{Name: "tenant-a", NodeSelector: "pool=a", PublicIPAddressIDs: []string{testNodePoolOutboundPIPID}, IdleTimeoutInMinutes: 4},
+	}
+	lbName := testClusterName
+	existingLB := &armnetwork.LoadBalancer{
+		Name: ptr.To(lbName),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{Name: ptr.To(lbName), ID: ptr.To(az.getBackendPoolID(lbName, lbName))},
+			},
+			OutboundRules: []*armnetwork.OutboundRule{
+				{
+					Name: ptr.To("aksOutboundRule"),
+					Properties: &armnetwork.OutboundRulePropertiesFormat{
+						BackendAddressPool: &armnetwork.SubResource{ID: ptr.To(az.getBackendPoolID(lbName, lbName))},
+					},
+				},
+			},
+		},
+	}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"pool": "a"}}},
+	}
+
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	// the load balancer is not updated
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, lbName, gomock.Any()).Return(existingLB, nil).Times(1)
+
+	err := az.ReconcileNodePoolOutbound(context.Background(), testClusterName, nodes)
+	assert.ErrorContains(t, err, "conflict with outbound rule aksOutboundRule")
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	loadBalancerResourceKindRule  = "rule"
+	loadBalancerResourceKindProbe = "probe"
+	loadBalancerResourceKindTags  = "tags"
+
+	loadBalancerResourceActionAdd    = "add"
+	loadBalancerResourceActionUpdate = "update"
+	loadBalancerResourceActionDelete = "delete"
+)
+
+// LoadBalancerResourceChange is a change the next reconciliation would make to a sub-resource
+// of the load balancer.
+type LoadBalancerResourceChange struct {
+	// Kind is the kind of the sub-resource: rule, probe or tags.
+	Kind string `json:"kind"`
+	// Name is the name of the sub-resource.
+	Name string `json:"name,omitempty"`
+	// Action is add, update or delete.
+	Action string `json:"action"`
+	// Expected is the sub-resource expected by the service.
+	Expected interface{} `json:"expected,omitempty"`
+	// Actual is the sub-resource on the load balancer in ARM.
+	Actual interface{} `json:"actual,omitempty"`
+}
+
+// LoadBalancerPreview is the difference between the load balancer expected by a service and
+// the load balancer in ARM.
+type LoadBalancerPreview struct {
+	// Service is the namespaced name of the service.
+	Service string `json:"service"`
+	// LoadBalancer is the name of the load balancer hosting the service.
+	LoadBalancer string `json:"loadBalancer,omitempty"`
+	// Changes are the changes the next reconciliation would make to the load balancer.
+	Changes []LoadBalancerResourceChange `json:"changes,omitempty"`
+	// Message explains the preview when it has no changes to show.
+	Message string `json:"message,omitempty"`
+}
+
+// PreviewLoadBalancer returns the changes the next reconciliation of the service would make to
+// the load balancing rules, health probes and tags of its load balancer, without applying them.
+// The frontend IP configurations and backend pools are not previewed.
+func (az *Cloud) PreviewLoadBalancer(ctx context.Context, clusterName string, service *v1.Service) (*LoadBalancerPreview, error) {
+	serviceName := getServiceName(service)
+	wantLb := az.wantsLoadBalancer(service) && service.DeletionTimestamp == nil
+	preview := &LoadBalancerPreview{Service: serviceName}
+
+	existingLBs, err := az.ListLB(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("PreviewLoadBalancer: failed to list load balancers: %w", err)
+	}
+
+	var (
+		lb              *armnetwork.LoadBalancer
+		ownedFIPConfigs []*armnetwork.FrontendIPConfiguration
+	)
+	for _, existingLB := range existingLBs {
+		if existingLB == nil || existingLB.Properties == nil {
+			continue
+		}
+		for _, fip := range existingLB.Properties.FrontendIPConfigurations {
+			if owns, _, _ := az.serviceOwnsFrontendIP(ctx, fip, service); owns {
+				ownedFIPConfigs = append(ownedFIPConfigs, fip)
+			}
+		}
+		if len(ownedFIPConfigs) > 0 {
+			lb = existingLB
+			break
+		}
+	}
+	if lb == nil {
+		if wantLb {
+			preview.Message = "the service does not have a frontend IP configuration on any load balancer, it will be created by the next reconciliation"
+		} else {
+			preview.Message = "the service does not have a frontend IP configuration on any load balancer"
+		}
+		return preview, nil
+	}
+
+	lbName := ptr.Deref(lb.Name, "")
+	preview.LoadBalancer = lbName
+	lbBackendPoolIDs := az.getBackendPoolIDsForService(service, clusterName, lbName)
+	lbFrontendIPConfigNames := az.getFrontendIPConfigNames(service)
+	lbFrontendIPConfigIDs := map[bool]string{
+		consts.IPVersionIPv4: az.getFrontendIPConfigID(lbName, lbFrontendIPConfigNames[consts.IPVersionIPv4]),
+		consts.IPVersionIPv6: az.getFrontendIPConfigID(lbName, lbFrontendIPConfigNames[consts.IPVersionIPv6]),
+	}
+	if err := az.setOwnedFrontendIPConfigIDs(ctx, service, serviceName, wantLb, ownedFIPConfigs, lbFrontendIPConfigIDs); err != nil {
+		return nil, err
+	}
+	expectedProbes, expectedRules, err := az.getExpectedLBRulesAndProbesForService(lb, service, lbName, wantLb, lbFrontendIPConfigIDs, lbBackendPoolIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// reconcile a copy of the load balancer so the cached one is not changed
+	previewLB := &armnetwork.LoadBalancer{
+		Name: lb.Name,
+		Tags: maps.Clone(lb.Tags),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			Probes:             slices.Clone(lb.Properties.Probes),
+			LoadBalancingRules: slices.Clone(lb.Properties.LoadBalancingRules),
+		},
+	}
+	az.reconcileLBProbes(previewLB, service, serviceName, wantLb, expectedProbes)
+	az.reconcileLBRules(previewLB, service, serviceName, wantLb, expectedRules)
+	az.ensureLoadBalancerTagged(previewLB)
+
+	preview.Changes = append(preview.Changes, diffLoadBalancerSubResources(loadBalancerResourceKindRule,
+		lb.Properties.LoadBalancingRules, previewLB.Properties.LoadBalancingRules,
+		func(rule *armnetwork.LoadBalancingRule) string { return ptr.Deref(rule.Name, "") })...)
+	preview.Changes = append(preview.Changes, diffLoadBalancerSubResources(loadBalancerResourceKindProbe,
+		lb.Properties.Probes, previewLB.Properties.Probes,
+		func(probe *armnetwork.Probe) string { return ptr.Deref(probe.Name, "") })...)
+	if !reflect.DeepEqual(lb.Tags, previewLB.Tags) && (len(lb.Tags) > 0 || len(previewLB.Tags) > 0) {
+		preview.Changes = append(preview.Changes, LoadBalancerResourceChange{
+			Kind:     loadBalancerResourceKindTags,
+			Action:   loadBalancerResourceActionUpdate,
+			Expected: previewLB.Tags,
+			Actual:   lb.Tags,
+		})
+	}
+	if len(preview.Changes) == 0 {
+		preview.Message = "the load balancing rules, health probes and tags of the load balancer are up to date"
+	}
+	return preview, nil
+}
+
+// diffLoadBalancerSubResources returns the changes between the actual and the reconciled
+// sub-resources. The reconciliation keeps the actual objects it does not change, so an object
+// with the same name but a different address is updated.
+func diffLoadBalancerSubResources[T any](kind string, actual, reconciled []*T, getName func(*T) string) []LoadBalancerResourceChange {
+	var changes []LoadBalancerResourceChange
+	actualByName := make(map[string]*T, len(actual))
+	for _, resource := range actual {
+		actualByName[strings.ToLower(getName(resource))] = resource
+	}
+	reconciledByName := make(map[string]*T, len(reconciled))
+	for _, resource := range reconciled {
+		name := getName(resource)
+		reconciledByName[strings.ToLower(name)] = resource
+		existing, ok := actualByName[strings.ToLower(name)]
+		switch {
+		case !ok:
+			changes = append(changes, LoadBalancerResourceChange{Kind: kind, Name: name, Action: loadBalancerResourceActionAdd, Expected: resource})
+		case existing != resource:
+			changes = append(changes, LoadBalancerResourceChange{Kind: kind, Name: name, Action: loadBalancerResourceActionUpdate, Expected: resource, Actual: existing})
+		}
+	}
+	for _, resource := range actual {
+		name := getName(resource)
+		if _, ok := reconciledByName[strings.ToLower(name)]; !ok {
+			changes = append(changes, LoadBalancerResourceChange{Kind: kind, Name: name, Action: loadBalancerResourceActionDelete, Actual: resource})
+		}
+	}
+	return changes
+}
+
+// LoadBalancerPreviewHandler returns the handler serving the load balancer preview of the
+// service given by the namespace and name query parameters. It should be served behind
+// authentication and authorization.
+func (az *Cloud) LoadBalancerPreviewHandler(clusterName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		namespace, name := r.URL.Query().Get("namespace"), r.URL.Query().Get("name")
+		if namespace == "" || name == "" {
+			http.Error(w, "the namespace and name query parameters are required", http.StatusBadRequest)
+			return
+		}
+		if az.serviceLister == nil {
+			http.Error(w, "the service informer is not ready", http.StatusServiceUnavailable)
+			return
+		}
+
+		service, err := az.serviceLister.Services(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		preview, err := az.PreviewLoadBalancer(r.Context(), clusterName, service.DeepCopy())
+		if err != nil {
+			klog.Errorf("LoadBalancerPreviewHandler: failed to preview load balancer of service %s/%s: %v", namespace, name, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(preview); err != nil {
+			klog.Errorf("LoadBalancerPreviewHandler: failed to encode the preview of service %s/%s: %v", namespace, name, err)
+		}
+	})
+}
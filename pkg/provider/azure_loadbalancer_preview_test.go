@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestPreviewLoadBalancer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	svc := getTestService("test1", v1.ProtocolTCP, map[string]string{
+		consts.ServiceAnnotationLoadBalancerInternal: consts.TrueAnnotationValue,
+	}, false, 80)
+	stalePort := int32(8080)
+	lb := &armnetwork.LoadBalancer{
+		Name: ptr.To("testCluster-internal"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{
+					Name: ptr.To("atest1"),
+					ID:   ptr.To(az.getFrontendIPConfigID("testCluster-internal", "atest1")),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PrivateIPAddress: ptr.To("10.0.0.4"),
+					},
+				},
+			},
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
+				{
+					Name: ptr.To("atest1-TCP-8080"),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						Protocol:     to.Ptr(armnetwork.TransportProtocolTCP),
+						FrontendPort: &stalePort,
+					},
+				},
+				{
+					Name: ptr.To("other-TCP-80"),
+				},
+			},
+			Probes: []*armnetwork.Probe{
+				{Name: ptr.To("atest1-TCP-8080")},
+			},
+		},
+	}
+	lbClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	lbClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{lb}, nil)
+
+	preview, err := az.PreviewLoadBalancer(context.TODO(), testClusterName, &svc)
+	assert.NoError(t, err)
+	assert.Equal(t, "default/test1", preview.Service)
+	assert.Equal(t, "testCluster-internal", preview.LoadBalancer)
+
+	actions := make(map[string]string)
+	for _, change := range preview.Changes {
+		actions[change.Kind+"/"+change.Name] = change.Action
+	}
+	assert.Equal(t, map[string]string{
+		"rule/atest1-TCP-80":    "add",
+		"rule/atest1-TCP-8080":  "delete",
+		"probe/atest1-TCP-80":   "add",
+		"probe/atest1-TCP-8080": "delete",
+	}, actions)
+	// the load balancer should not be changed
+	assert.Len(t, lb.Properties.LoadBalancingRules, 2)
+	assert.Len(t, lb.Properties.Probes, 1)
+}
+
+func TestPreviewLoadBalancerNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	lbClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	lbClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return(nil, nil)
+
+	preview, err := az.PreviewLoadBalancer(context.TODO(), testClusterName, &svc)
+	assert.NoError(t, err)
+	assert.Empty(t, preview.LoadBalancer)
+	assert.Empty(t, preview.Changes)
+	assert.NotEmpty(t, preview.Message)
+}
+
+func TestLoadBalancerPreviewHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	handler := az.LoadBalancerPreviewHandler(testClusterName)
+
+	for _, tc := range []struct {
+		desc           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{
+			desc:           "should reject methods other than GET",
+			method:         http.MethodPost,
+			url:            "/?namespace=default&name=test1",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:           "should require the namespace and name",
+			method:         http.MethodGet,
+			url:            "/?namespace=default",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "should return not found for unknown services",
+			method:         http.MethodGet,
+			url:            "/?namespace=default&name=test1",
+			expectedStatus: http.StatusNotFound,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.url, nil))
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
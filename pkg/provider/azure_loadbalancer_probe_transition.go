@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+const (
+	// probeTransitionSettleTime is the time the load balancer takes to start probing with the new health
+	// probes after it is updated, on top of the probes needed to mark the backends healthy.
+	probeTransitionSettleTime = 30 * time.Second
+)
+
+// probeTransition is a switch of the health probes of the load balancing rules of a service in progress.
+type probeTransition struct {
+	// probeIDs are the lower case IDs of the new probes.
+	probeIDs sets.Set[string]
+	// readyAt is the time the rules can be switched to the new probes.
+	readyAt time.Time
+}
+
+// probeTransitionTracker makes the switches of the health probes of the load balancing rules make-before-break,
+// e.g. when the externalTrafficPolicy of a service is changed between Cluster and Local, which switches its rules
+// between the probes of the service ports and the probe of the health check node port. Switching a rule to a new
+// probe at once drops the connections until the new probe marks the backends healthy, so the new probes are added
+// to the load balancer first while the rules keep the current probes, and the rules are switched after the new
+// probes have probed the backends. A nil probeTransitionTracker switches the probes at once.
+type probeTransitionTracker struct {
+	lock        sync.Mutex
+	transitions map[string]*probeTransition
+
+	now func() time.Time
+}
+
+func newProbeTransitionTracker() *probeTransitionTracker {
+	return &probeTransitionTracker{
+		transitions: make(map[string]*probeTransition),
+		now:         time.Now,
+	}
+}
+
+// pending returns the time left before the rules of the service can be switched to the new probes, if a
+// switch is in progress.
+func (t *probeTransitionTracker) pending(service *v1.Service) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	transition, found := t.transitions[strings.ToLower(getServiceName(service))]
+	if !found {
+		return 0, false
+	}
+	// the requeued reconciliation switches the rules, it is not requeued at once if the time is up
+	return max(transition.readyAt.Sub(t.now()), time.Second), true
+}
+
+// forget stops tracking the service.
+func (t *probeTransitionTracker) forget(service *v1.Service) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.transitions, strings.ToLower(getServiceName(service)))
+}
+
+// reconcileProbeTransition returns the expected probes and rules of the service to reconcile the load balancer
+// to. The expected rules switching to new probes keep the current probes until the new probes are ready, and
+// the current probes are kept for them. The switch starts when the new probes are first expected, and the rules
+// are switched after the new probes have been on the load balancer for the probe interval times the probe
+// threshold, plus the settle time.
+func (az *Cloud) reconcileProbeTransition(
+	lb *armnetwork.LoadBalancer,
+	service *v1.Service,
+	expectedProbes []*armnetwork.Probe,
+	expectedRules []*armnetwork.LoadBalancingRule,
+) ([]*armnetwork.Probe, []*armnetwork.LoadBalancingRule) {
+	t := az.probeTransitions
+	if t == nil || lb.Properties == nil {
+		return expectedProbes, expectedRules
+	}
+	lbName := ptr.Deref(lb.Name, "")
+	serviceName := getServiceName(service)
+
+	existingProbes := make(map[string]*armnetwork.Probe)
+	for _, probe := range lb.Properties.Probes {
+		if probe != nil {
+			existingProbes[strings.ToLower(ptr.Deref(probe.ID, ""))] = probe
+		}
+	}
+	existingRules := make(map[string]*armnetwork.LoadBalancingRule)
+	for _, rule := range lb.Properties.LoadBalancingRules {
+		if rule != nil && az.serviceOwnsRule(service, ptr.Deref(rule.Name, "")) {
+			existingRules[strings.ToLower(ptr.Deref(rule.Name, ""))] = rule
+		}
+	}
+	newProbes := make(map[string]*armnetwork.Probe)
+	for _, probe := range expectedProbes {
+		newProbes[strings.ToLower(az.getLoadBalancerProbeID(lbName, ptr.Deref(probe.Name, "")))] = probe
+	}
+
+	// the current probes of the rules switching to new probes by the indexes of the rules
+	switching := make(map[int]*armnetwork.Probe)
+	newProbeIDs := sets.New[string]()
+	var ruleNames []string
+	warmUp := time.Duration(0)
+	for i, rule := range expectedRules {
+		existingRule := existingRules[strings.ToLower(ptr.Deref(rule.Name, ""))]
+		if existingRule == nil || existingRule.Properties == nil || rule.Properties == nil {
+			continue
+		}
+		currentID, newID := getSubResourceID(existingRule.Properties.Probe), getSubResourceID(rule.Properties.Probe)
+		currentProbe, newProbe := existingProbes[currentID], newProbes[newID]
+		if currentID == newID || currentProbe == nil || currentProbe.Properties == nil || newProbe == nil {
+			continue
+		}
+		switching[i] = currentProbe
+		newProbeIDs.Insert(newID)
+		ruleNames = append(ruleNames, ptr.Deref(rule.Name, ""))
+		warmUp = max(warmUp, getProbeWarmUpTime(newProbe))
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	key := strings.ToLower(serviceName)
+	transition := t.transitions[key]
+	if len(switching) == 0 {
+		delete(t.transitions, key)
+		return expectedProbes, expectedRules
+	}
+
+	// the time starts over until the new probes are on the load balancer
+	probesAdded := true
+	for id := range newProbeIDs {
+		if existingProbe := existingProbes[id]; existingProbe == nil || existingProbe.Properties == nil || !findProbe([]*armnetwork.Probe{existingProbe}, newProbes[id]) {
+			probesAdded = false
+		}
+	}
+	logger := log.Background().WithName("reconcileProbeTransition").WithValues("service", serviceName, "loadBalancer", lbName)
+	now := t.now()
+	sort.Strings(ruleNames)
+	switch {
+	case transition == nil || !transition.probeIDs.Equal(newProbeIDs):
+		transition = &probeTransition{probeIDs: newProbeIDs, readyAt: now.Add(warmUp + probeTransitionSettleTime)}
+		t.transitions[key] = transition
+		logger.V(2).Info("The lb rules switch to the new probes later", "rules", ruleNames, "probes", sets.List(newProbeIDs), "readyAt", transition.readyAt)
+		az.Event(service, v1.EventTypeNormal, "HealthProbeTransitionStarted", fmt.Sprintf(
+			"Adding new health probes to the load balancer, load balancing rules %s keep their current health probes until %s",
+			strings.Join(ruleNames, ", "), transition.readyAt.UTC().Format(time.RFC3339)))
+	case !probesAdded:
+		transition.readyAt = now.Add(warmUp + probeTransitionSettleTime)
+	case !now.Before(transition.readyAt):
+		delete(t.transitions, key)
+		logger.V(2).Info("Switching the lb rules to the new probes", "rules", ruleNames)
+		az.Event(service, v1.EventTypeNormal, "HealthProbeTransitionCompleted", fmt.Sprintf(
+			"Switching load balancing rules %s to the new health probes", strings.Join(ruleNames, ", ")))
+		return expectedProbes, expectedRules
+	}
+
+	rules := make([]*armnetwork.LoadBalancingRule, len(expectedRules))
+	copy(rules, expectedRules)
+	probes := append([]*armnetwork.Probe{}, expectedProbes...)
+	for i, currentProbe := range switching {
+		rule := *rules[i]
+		properties := *rule.Properties
+		properties.Probe = &armnetwork.SubResource{ID: currentProbe.ID}
+		rule.Properties = &properties
+		rules[i] = &rule
+		if !findProbe(probes, currentProbe) {
+			probes = append(probes, currentProbe)
+		}
+	}
+	return probes, rules
+}
+
+// getProbeWarmUpTime returns the time the probe takes to mark the backends healthy.
+func getProbeWarmUpTime(probe *armnetwork.Probe) time.Duration {
+	interval, threshold := consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe
+	if probe.Properties != nil {
+		interval = ptr.Deref(probe.Properties.IntervalInSeconds, interval)
+		threshold = ptr.Deref(probe.Properties.ProbeThreshold, threshold)
+	}
+	return time.Duration(interval*threshold) * time.Second
+}
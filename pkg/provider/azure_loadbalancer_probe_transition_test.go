@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+)
+
+func TestReconcileProbeTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	recorder := record.NewFakeRecorder(10)
+	az.eventRecorder = recorder
+	now := time.Now()
+	az.probeTransitions = newProbeTransitionTracker()
+	az.probeTransitions.now = func() time.Time { return now }
+
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "10000000-0000-0000-0000-000000000000"}}
+	ruleName := az.getLoadBalancerRuleName(service, v1.ProtocolTCP, 80, false)
+	clusterProbe := &armnetwork.Probe{
+		Name:       ptr.To(ruleName),
+		ID:         ptr.To(az.getLoadBalancerProbeID("lb", ruleName)),
+		Properties: &armnetwork.ProbePropertiesFormat{Protocol: ptr.To(armnetwork.ProbeProtocolTCP), Port: ptr.To(int32(30080))},
+	}
+	localProbeName := az.getLoadBalancerRuleName(service, v1.ProtocolTCP, 31000, false)
+	localProbe := &armnetwork.Probe{
+		Name: ptr.To(localProbeName),
+		Properties: &armnetwork.ProbePropertiesFormat{
+			Protocol:          ptr.To(armnetwork.ProbeProtocolHTTP),
+			Port:              ptr.To(int32(31000)),
+			RequestPath:       ptr.To("/healthz"),
+			IntervalInSeconds: ptr.To(int32(5)),
+			ProbeThreshold:    ptr.To(int32(2)),
+		},
+	}
+	newRule := func(probeName string) *armnetwork.LoadBalancingRule {
+		return &armnetwork.LoadBalancingRule{
+			Name: ptr.To(ruleName),
+			Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+				FrontendPort: ptr.To(int32(80)),
+				Probe:        &armnetwork.SubResource{ID: ptr.To(az.getLoadBalancerProbeID("lb", probeName))},
+			},
+		}
+	}
+	lb := &armnetwork.LoadBalancer{
+		Name: ptr.To("lb"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			Probes:             []*armnetwork.Probe{clusterProbe},
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{newRule(ruleName)},
+		},
+	}
+	expectedProbes := []*armnetwork.Probe{localProbe}
+	expectedRules := []*armnetwork.LoadBalancingRule{newRule(localProbeName)}
+
+	// the new probe is added while the rule keeps the current probe
+	probes, rules := az.reconcileProbeTransition(lb, service, expectedProbes, expectedRules)
+	assert.Equal(t, []*armnetwork.Probe{localProbe, clusterProbe}, probes)
+	assert.Equal(t, []*armnetwork.LoadBalancingRule{newRule(ruleName)}, rules)
+	assert.Equal(t, newRule(localProbeName), expectedRules[0], "the expected rules are not changed")
+	assert.Contains(t, <-recorder.Events, "HealthProbeTransitionStarted")
+	delay, pending := az.probeTransitions.pending(service)
+	assert.True(t, pending)
+	assert.Equal(t, 40*time.Second, delay)
+
+	// the time starts after the new probe is on the load balancer
+	now = now.Add(time.Minute)
+	_, rules = az.reconcileProbeTransition(lb, service, expectedProbes, expectedRules)
+	assert.Equal(t, []*armnetwork.LoadBalancingRule{newRule(ruleName)}, rules)
+	addedProbe := *localProbe
+	addedProbe.ID = ptr.To(az.getLoadBalancerProbeID("lb", localProbeName))
+	lb.Properties.Probes = append(lb.Properties.Probes, &addedProbe)
+	now = now.Add(39 * time.Second)
+	_, rules = az.reconcileProbeTransition(lb, service, expectedProbes, expectedRules)
+	assert.Equal(t, []*armnetwork.LoadBalancingRule{newRule(ruleName)}, rules)
+
+	// the rule is switched to the new probe after the new probe is ready
+	now = now.Add(time.Second)
+	probes, rules = az.reconcileProbeTransition(lb, service, expectedProbes, expectedRules)
+	assert.Equal(t, expectedProbes, probes)
+	assert.Equal(t, expectedRules, rules)
+	assert.Contains(t, <-recorder.Events, "HealthProbeTransitionCompleted")
+	_, pending = az.probeTransitions.pending(service)
+	assert.False(t, pending)
+}
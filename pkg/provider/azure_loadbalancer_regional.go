@@ -0,0 +1,483 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+// validateRegionalLoadBalancerConfigurations validates the regional load balancer configurations.
+func validateRegionalLoadBalancerConfigurations(cfg *config.Config) error {
+	if len(cfg.RegionalLoadBalancers) == 0 {
+		return nil
+	}
+	if !cfg.UseStandardLoadBalancer() {
+		return errors.New("regionalLoadBalancers requires the standard load balancer")
+	}
+
+	regions := sets.New[string]()
+	for i, regional := range cfg.RegionalLoadBalancers {
+		if regional.Region == "" {
+			return fmt.Errorf("regionalLoadBalancers[%d] does not have a region", i)
+		}
+		if strings.EqualFold(regional.Region, cfg.Location) {
+			return fmt.Errorf("regionalLoadBalancers[%d] region %s is the location of the cluster", i, regional.Region)
+		}
+		if regions.Has(strings.ToLower(regional.Region)) {
+			return fmt.Errorf("regionalLoadBalancers[%d] has a duplicate region %s", i, regional.Region)
+		}
+		regions.Insert(strings.ToLower(regional.Region))
+		resourceID, err := arm.ParseResourceID(regional.VirtualNetworkID)
+		if err != nil || !strings.EqualFold(resourceID.ResourceType.Types[len(resourceID.ResourceType.Types)-1], "virtualNetworks") {
+			return fmt.Errorf("regionalLoadBalancers[%d] has an invalid virtualNetworkID %q", i, regional.VirtualNetworkID)
+		}
+	}
+	return nil
+}
+
+// getRegionalLoadBalancerName returns the name of the load balancer of the cluster in the region.
+func getRegionalLoadBalancerName(clusterName, region string) string {
+	return fmt.Sprintf("%s-%s", clusterName, strings.ToLower(region))
+}
+
+// regionalFrontendIPConfigNamePrefix prefixes the names of the frontend IP configurations on the regional load
+// balancers, so they never match the prefix of the frontend IP configurations of the service by serviceOwnsFrontendIP.
+const regionalFrontendIPConfigNamePrefix = "regional-"
+
+// getRegionalFrontendIPConfigName returns the name of the frontend IP configuration of the service on the regional
+// load balancers.
+func (az *Cloud) getRegionalFrontendIPConfigName(service *v1.Service) string {
+	return regionalFrontendIPConfigNamePrefix + az.getDefaultFrontendIPConfigName(service)
+}
+
+// isRegionalLoadBalancer returns true if the load balancer is the load balancer of a configured region, which is
+// located in the region and named after it. The regional load balancers are only reconciled by
+// reconcileRegionalFrontends, they are never the load balancers of the services in the location of the cluster.
+func (az *Cloud) isRegionalLoadBalancer(lb *armnetwork.LoadBalancer) bool {
+	if lb == nil {
+		return false
+	}
+	name := strings.ToLower(ptr.Deref(lb.Name, ""))
+	location := strings.ReplaceAll(ptr.Deref(lb.Location, ""), " ", "")
+	for _, regional := range az.RegionalLoadBalancers {
+		region := strings.ToLower(regional.Region)
+		if strings.HasSuffix(name, "-"+region) && strings.EqualFold(location, strings.ReplaceAll(region, " ", "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeRegionalLoadBalancers returns the load balancers other than the regional load balancers.
+func (az *Cloud) excludeRegionalLoadBalancers(lbs []*armnetwork.LoadBalancer) []*armnetwork.LoadBalancer {
+	if len(az.RegionalLoadBalancers) == 0 {
+		return lbs
+	}
+	var result []*armnetwork.LoadBalancer
+	for _, lb := range lbs {
+		if !az.isRegionalLoadBalancer(lb) {
+			result = append(result, lb)
+		}
+	}
+	return result
+}
+
+// getRegionalPublicIPName returns the name of the public IP of the frontend of the service in the region.
+func (az *Cloud) getRegionalPublicIPName(service *v1.Service, region string) string {
+	return fmt.Sprintf("%s-%s", az.getRulePrefix(service), strings.ToLower(region))
+}
+
+// getServiceRegions returns the lower case regions the service gets frontends in by the annotation
+// service.beta.kubernetes.io/azure-load-balancer-regions.
+func (az *Cloud) getServiceRegions(service *v1.Service) (sets.Set[string], error) {
+	regions := sets.New[string]()
+	value, found := service.Annotations[consts.ServiceAnnotationLoadBalancerRegions]
+	if !found || strings.TrimSpace(value) == "" {
+		return regions, nil
+	}
+	if requiresInternalLoadBalancer(service) {
+		return nil, fmt.Errorf("annotation %s is not supported by the internal services", consts.ServiceAnnotationLoadBalancerRegions)
+	}
+
+	configured := sets.New[string]()
+	for _, regional := range az.RegionalLoadBalancers {
+		configured.Insert(strings.ToLower(regional.Region))
+	}
+	for _, region := range strings.Split(value, ",") {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" {
+			continue
+		}
+		if !configured.Has(region) {
+			return nil, fmt.Errorf("region %s in annotation %s is not configured by regionalLoadBalancers", region, consts.ServiceAnnotationLoadBalancerRegions)
+		}
+		regions.Insert(region)
+	}
+	return regions, nil
+}
+
+// reconcileRegionalFrontends reconciles the frontends of the service on the regional load balancers, and returns
+// the ingress of the frontends. The frontends of the regions the service doesn't select anymore are removed, as
+// well as all of them if wantLb is false.
+func (az *Cloud) reconcileRegionalFrontends(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node, wantLb bool) ([]v1.LoadBalancerIngress, error) {
+	if len(az.RegionalLoadBalancers) == 0 {
+		return nil, nil
+	}
+	regions, err := az.getServiceRegions(service)
+	if err != nil {
+		if wantLb {
+			return nil, err
+		}
+		// the frontends of all the regions are removed
+		regions = sets.New[string]()
+	}
+
+	var ingress []v1.LoadBalancerIngress
+	for _, regional := range az.RegionalLoadBalancers {
+		want := wantLb && regions.Has(strings.ToLower(regional.Region))
+		ip, err := az.reconcileRegionalFrontend(ctx, clusterName, service, nodes, regional, want)
+		if err != nil {
+			return nil, fmt.Errorf("reconcile the frontend of service %s in region %s: %w", getServiceName(service), regional.Region, err)
+		}
+		if ip != "" {
+			ingress = append(ingress, v1.LoadBalancerIngress{IP: ip})
+		}
+	}
+	return ingress, nil
+}
+
+// reconcileRegionalFrontend reconciles the public IP, the frontend IP configuration, the probes and the load
+// balancing rules of the service on the load balancer of the region, and the backend pool of the nodes in the
+// region. It returns the IP of the frontend. The load balancer is deleted once it has no frontends.
+func (az *Cloud) reconcileRegionalFrontend(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+	regional config.RegionalLoadBalancerConfiguration,
+	wantLb bool,
+) (string, error) {
+	rgName := az.getLoadBalancerResourceGroup()
+	lbName := getRegionalLoadBalancerName(clusterName, regional.Region)
+	pipName := az.getRegionalPublicIPName(service, regional.Region)
+	logger := log.FromContextOrBackground(ctx).WithName("reconcileRegionalFrontend").WithValues("service", getServiceName(service), "loadBalancer", lbName)
+	lb, exists, err := az.getAzureLoadBalancer(ctx, lbName, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return "", err
+	}
+
+	if !wantLb {
+		if exists && lb.Properties != nil && az.setRegionalServiceResources(lb, service, nil, nil, nil) {
+			if len(lb.Properties.FrontendIPConfigurations) == 0 {
+				logger.V(2).Info("Deleting the regional load balancer without frontends")
+				err = az.DeleteLB(ctx, service, lbName)
+			} else {
+				err = az.createOrUpdateRegionalLB(ctx, lb)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		_, pipExists, err := az.getPublicIPAddress(ctx, rgName, pipName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return "", err
+		}
+		if pipExists {
+			logger.V(2).Info("Deleting the public IP of the service", "pip", pipName)
+			return "", az.DeletePublicIP(service, rgName, pipName)
+		}
+		return "", nil
+	}
+
+	pip, err := az.ensureRegionalPublicIP(ctx, clusterName, service, pipName, regional.Region)
+	if err != nil {
+		return "", err
+	}
+	if !exists || lb.Properties == nil {
+		lb = &armnetwork.LoadBalancer{
+			Name:       ptr.To(lbName),
+			Location:   ptr.To(regional.Region),
+			SKU:        &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameStandard)},
+			Properties: &armnetwork.LoadBalancerPropertiesFormat{},
+		}
+	}
+
+	fipName := az.getRegionalFrontendIPConfigName(service)
+	poolName := getBackendPoolName(clusterName, consts.IPVersionIPv4)
+	probes, rules, err := az.getExpectedLBRules(service, az.getFrontendIPConfigID(lbName, fipName), az.getBackendPoolID(lbName, poolName), lbName, consts.IPVersionIPv4)
+	if err != nil {
+		return "", err
+	}
+	frontend := &armnetwork.FrontendIPConfiguration{
+		Name: ptr.To(fipName),
+		Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+			PublicIPAddress: &armnetwork.PublicIPAddress{ID: pip.ID},
+		},
+	}
+	changed := az.setRegionalServiceResources(lb, service, frontend, probes, rules)
+	if setRegionalBackendPool(lb, poolName, regional, nodes) {
+		changed = true
+	}
+	if az.ensureLoadBalancerTagged(lb) {
+		changed = true
+	}
+	if changed {
+		logger.V(2).Info("Updating the regional load balancer")
+		if err := az.createOrUpdateRegionalLB(ctx, lb); err != nil {
+			return "", err
+		}
+	}
+	return ptr.Deref(pip.Properties.IPAddress, ""), nil
+}
+
+// ensureRegionalPublicIP creates the static public IP of the frontend of the service in the region if it
+// doesn't exist, and ensures it is tagged as configured.
+func (az *Cloud) ensureRegionalPublicIP(ctx context.Context, clusterName string, service *v1.Service, pipName, region string) (*armnetwork.PublicIPAddress, error) {
+	rgName := az.getLoadBalancerResourceGroup()
+	logger := log.FromContextOrBackground(ctx).WithName("ensureRegionalPublicIP").WithValues("service", getServiceName(service), "pip", pipName)
+	pip, exists, err := az.getPublicIPAddress(ctx, rgName, pipName, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if az.ensurePIPTagged(service, pip) {
+			logger.V(2).Info("Updating the tags of the public IP")
+			if err := az.CreateOrUpdatePIP(service, rgName, pip); err != nil {
+				return nil, err
+			}
+		} else if pip.Properties != nil && ptr.Deref(pip.Properties.IPAddress, "") != "" {
+			return pip, nil
+		}
+	} else {
+		// the service tag is not set, so the public IP is not reconciled as the public IP of the service
+		// on the load balancer of the cluster
+		pip = &armnetwork.PublicIPAddress{
+			Name:     ptr.To(pipName),
+			Location: ptr.To(region),
+			SKU:      &armnetwork.PublicIPAddressSKU{Name: ptr.To(armnetwork.PublicIPAddressSKUNameStandard)},
+			Tags:     map[string]*string{consts.ClusterNameKey: ptr.To(clusterName)},
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
+				PublicIPAddressVersion:   ptr.To(armnetwork.IPVersionIPv4),
+			},
+		}
+		az.ensurePIPTagged(service, pip)
+		logger.V(2).Info("Creating the public IP", "region", region)
+		if err := az.CreateOrUpdatePIP(service, rgName, pip); err != nil {
+			return nil, err
+		}
+	}
+	pip, exists, err = az.getPublicIPAddress(ctx, rgName, pipName, azcache.CacheReadTypeForceRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || pip.Properties == nil || ptr.Deref(pip.Properties.IPAddress, "") == "" {
+		return nil, fmt.Errorf("public IP %s is not allocated yet", pipName)
+	}
+	return pip, nil
+}
+
+// createOrUpdateRegionalLB updates the regional load balancer synchronously.
+func (az *Cloud) createOrUpdateRegionalLB(ctx context.Context, lb *armnetwork.LoadBalancer) error {
+	name := ptr.Deref(lb.Name, "")
+	_, err := az.NetworkClientFactory.GetLoadBalancerClient().CreateOrUpdate(ctx, az.getLoadBalancerResourceGroup(), name, *lb)
+	_ = az.lbCache.Delete(name)
+	if err != nil {
+		return fmt.Errorf("update regional load balancer %s: %w", name, errutils.WithARMRequestIDs(err))
+	}
+	return nil
+}
+
+// getRegionalFrontendIPs returns the IPs of the public IPs of the frontends of the service on the regional
+// load balancers.
+func (az *Cloud) getRegionalFrontendIPs(ctx context.Context, service *v1.Service) ([]string, error) {
+	regions, err := az.getServiceRegions(service)
+	if err != nil || regions.Len() == 0 {
+		return nil, err
+	}
+	var ips []string
+	for _, region := range sets.List(regions) {
+		pip, exists, err := az.getPublicIPAddress(ctx, az.getLoadBalancerResourceGroup(), az.getRegionalPublicIPName(service, region), azcache.CacheReadTypeDefault)
+		if err != nil {
+			return nil, err
+		}
+		if exists && pip.Properties != nil && ptr.Deref(pip.Properties.IPAddress, "") != "" {
+			ips = append(ips, *pip.Properties.IPAddress)
+		}
+	}
+	return ips, nil
+}
+
+// setRegionalServiceResources sets the frontend IP configuration, the probes and the load balancing rules of
+// the service on the regional load balancer, or removes them if the frontend is nil. It returns true if the
+// load balancer is changed.
+func (az *Cloud) setRegionalServiceResources(
+	lb *armnetwork.LoadBalancer,
+	service *v1.Service,
+	frontend *armnetwork.FrontendIPConfiguration,
+	probes []*armnetwork.Probe,
+	rules []*armnetwork.LoadBalancingRule,
+) bool {
+	prefix := strings.ToLower(az.getRulePrefix(service))
+	owned := func(name *string) bool {
+		return strings.HasPrefix(strings.ToLower(ptr.Deref(name, "")), prefix)
+	}
+	// the frontends named without the regional prefix are the ones created before it, and are replaced
+	ownedFrontend := func(name *string) bool {
+		return owned(name) || strings.HasPrefix(strings.ToLower(ptr.Deref(name, "")), regionalFrontendIPConfigNamePrefix+prefix)
+	}
+	changed := false
+
+	var fips []*armnetwork.FrontendIPConfiguration
+	frontendFound := false
+	for _, fip := range lb.Properties.FrontendIPConfigurations {
+		if ownedFrontend(fip.Name) {
+			if frontend == nil || !equalRegionalFrontend(fip, frontend) {
+				changed = true
+				continue
+			}
+			frontendFound = true
+		}
+		fips = append(fips, fip)
+	}
+	if frontend != nil && !frontendFound {
+		fips = append(fips, frontend)
+		changed = true
+	}
+
+	var updatedRules []*armnetwork.LoadBalancingRule
+	for _, rule := range lb.Properties.LoadBalancingRules {
+		if owned(rule.Name) && !findRule(rules, rule, true) {
+			changed = true
+			continue
+		}
+		updatedRules = append(updatedRules, rule)
+	}
+	for _, rule := range rules {
+		if !findRule(updatedRules, rule, true) {
+			updatedRules = append(updatedRules, rule)
+			changed = true
+		}
+	}
+
+	// the probes which are not owned, e.g. the shared probe, are replaced by the expected ones of the same names
+	var updatedProbes []*armnetwork.Probe
+	for _, probe := range lb.Properties.Probes {
+		if (owned(probe.Name) || hasProbeName(probes, probe.Name)) && !findProbe(probes, probe) {
+			changed = true
+			continue
+		}
+		updatedProbes = append(updatedProbes, probe)
+	}
+	for _, probe := range probes {
+		if !findProbe(updatedProbes, probe) {
+			updatedProbes = append(updatedProbes, probe)
+			changed = true
+		}
+	}
+
+	if changed {
+		lb.Properties.FrontendIPConfigurations = fips
+		lb.Properties.LoadBalancingRules = updatedRules
+		lb.Properties.Probes = updatedProbes
+	}
+	return changed
+}
+
+// equalRegionalFrontend returns true if the frontend IP configurations have the same name and public IP.
+func equalRegionalFrontend(fip, expected *armnetwork.FrontendIPConfiguration) bool {
+	return strings.EqualFold(ptr.Deref(fip.Name, ""), ptr.Deref(expected.Name, "")) &&
+		fip.Properties != nil && fip.Properties.PublicIPAddress != nil &&
+		strings.EqualFold(ptr.Deref(fip.Properties.PublicIPAddress.ID, ""), ptr.Deref(expected.Properties.PublicIPAddress.ID, ""))
+}
+
+// hasProbeName returns true if any of the probes has the name.
+func hasProbeName(probes []*armnetwork.Probe, name *string) bool {
+	for _, probe := range probes {
+		if strings.EqualFold(ptr.Deref(probe.Name, ""), ptr.Deref(name, "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// setRegionalBackendPool sets the IP-based backend pool of the regional load balancer to the IPs of the nodes
+// labeled with the region. It returns true if the load balancer is changed.
+func setRegionalBackendPool(lb *armnetwork.LoadBalancer, poolName string, regional config.RegionalLoadBalancerConfiguration, nodes []*v1.Node) bool {
+	var addresses []*armnetwork.LoadBalancerBackendAddress
+	expectedIPs := sets.New[string]()
+	for _, node := range nodes {
+		if !strings.EqualFold(node.Labels[v1.LabelTopologyRegion], regional.Region) {
+			continue
+		}
+		ip := getNodePrivateIPAddress(node, consts.IPVersionIPv4)
+		if ip == "" || expectedIPs.Has(ip) {
+			continue
+		}
+		expectedIPs.Insert(ip)
+		addresses = append(addresses, &armnetwork.LoadBalancerBackendAddress{
+			Name: ptr.To(node.Name),
+			Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+				IPAddress:      ptr.To(ip),
+				VirtualNetwork: &armnetwork.SubResource{ID: ptr.To(regional.VirtualNetworkID)},
+			},
+		})
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return ptr.Deref(addresses[i].Name, "") < ptr.Deref(addresses[j].Name, "")
+	})
+
+	var pool *armnetwork.BackendAddressPool
+	for _, existing := range lb.Properties.BackendAddressPools {
+		if strings.EqualFold(ptr.Deref(existing.Name, ""), poolName) {
+			pool = existing
+			break
+		}
+	}
+	if pool == nil {
+		pool = &armnetwork.BackendAddressPool{Name: ptr.To(poolName)}
+		lb.Properties.BackendAddressPools = append(lb.Properties.BackendAddressPools, pool)
+	} else if pool.Properties != nil {
+		existingIPs := sets.New[string]()
+		for _, address := range pool.Properties.LoadBalancerBackendAddresses {
+			if address.Properties != nil {
+				existingIPs.Insert(ptr.Deref(address.Properties.IPAddress, ""))
+			}
+		}
+		if existingIPs.Equal(expectedIPs) {
+			return false
+		}
+	}
+	pool.Properties = &armnetwork.BackendAddressPoolPropertiesFormat{LoadBalancerBackendAddresses: addresses}
+	return true
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+const testRegionalVNetID = "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet-westus2"
+
+func TestValidateRegionalLoadBalancerConfigurations(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		regionals   []config.RegionalLoadBalancerConfiguration
+		sku         string
+		expectedErr string
+	}{
+		{
+			desc:      "valid configurations",
+			regionals: []config.RegionalLoadBalancerConfiguration{{Region: "westus2", VirtualNetworkID: testRegionalVNetID}},
+		},
+		{
+			desc:        "basic load balancer",
+			regionals:   []config.RegionalLoadBalancerConfiguration{{Region: "westus2", VirtualNetworkID: testRegionalVNetID}},
+			sku:         consts.LoadBalancerSKUBasic,
+			expectedErr: "requires the standard load balancer",
+		},
+		{
+			desc:        "location of the cluster",
+			regionals:   []config.RegionalLoadBalancerConfiguration{{Region: "EastUS", VirtualNetworkID: testRegionalVNetID}},
+			expectedErr: "is the location of the cluster",
+		},
+		{
+			desc: "duplicate regions",
+			regionals: []config.RegionalLoadBalancerConfiguration{
+				{Region: "westus2", VirtualNetworkID: testRegionalVNetID},
+				{Region: "WestUS2", VirtualNetworkID: testRegionalVNetID},
+			},
+			expectedErr: "duplicate region",
+		},
+		{
+			desc:        "invalid virtual network ID",
+			regionals:   []config.RegionalLoadBalancerConfiguration{{Region: "westus2", VirtualNetworkID: "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"}},
+			expectedErr: "invalid virtualNetworkID",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := &config.Config{Location: "eastus", RegionalLoadBalancers: tc.regionals}
+			cfg.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+			if tc.sku != "" {
+				cfg.LoadBalancerSKU = tc.sku
+			}
+			err := validateRegionalLoadBalancerConfigurations(cfg)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func getTestRegionalCloud(ctrl *gomock.Controller) *Cloud {
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.RegionalLoadBalancers = []config.RegionalLoadBalancerConfiguration{
+		{Region: "westus2", VirtualNetworkID: testRegionalVNetID},
+		{Region: "centralus", VirtualNetworkID: testRegionalVNetID},
+	}
+	return az
+}
+
+func TestGetServiceRegions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	regions, err := az.getServiceRegions(&service)
+	assert.NoError(t, err)
+	assert.Empty(t, regions)
+
+	service.Annotations[consts.ServiceAnnotationLoadBalancerRegions] = " WestUS2, ,centralus"
+	regions, err = az.getServiceRegions(&service)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"westus2", "centralus"}, regions.UnsortedList())
+
+	service.Annotations[consts.ServiceAnnotationLoadBalancerRegions] = "westeurope"
+	_, err = az.getServiceRegions(&service)
+	assert.ErrorContains(t, err, "is not configured")
+
+	internalService := getInternalTestService("service", 80)
+	internalService.Annotations[consts.ServiceAnnotationLoadBalancerRegions] = "westus2"
+	_, err = az.getServiceRegions(&internalService)
+	assert.ErrorContains(t, err, "not supported by the internal services")
+}
+
+func TestSetRegionalServiceResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	other := getTestService("other", v1.ProtocolTCP, nil, false, 80)
+	lbName := "kubernetes-westus2"
+	getResources := func(service *v1.Service) (*armnetwork.FrontendIPConfiguration, []*armnetwork.Probe, []*armnetwork.LoadBalancingRule) {
+		fipName := az.getRegionalFrontendIPConfigName(service)
+		probes, rules, err := az.getExpectedLBRules(service, az.getFrontendIPConfigID(lbName, fipName), az.getBackendPoolID(lbName, "kubernetes"), lbName, false)
+		assert.NoError(t, err)
+		return &armnetwork.FrontendIPConfiguration{
+			Name:       ptr.To(fipName),
+			Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To("pip-" + service.Name)}},
+		}, probes, rules
+	}
+
+	lb := &armnetwork.LoadBalancer{Name: ptr.To(lbName), Properties: &armnetwork.LoadBalancerPropertiesFormat{}}
+	fip, probes, rules := getResources(&other)
+	assert.True(t, az.setRegionalServiceResources(lb, &other, fip, probes, rules))
+	fip, probes, rules = getResources(&service)
+	assert.True(t, az.setRegionalServiceResources(lb, &service, fip, probes, rules))
+	assert.Len(t, lb.Properties.FrontendIPConfigurations, 2)
+	assert.Len(t, lb.Properties.LoadBalancingRules, 2)
+
+	// the resources are not changed again
+	fip, probes, rules = getResources(&service)
+	assert.False(t, az.setRegionalServiceResources(lb, &service, fip, probes, rules))
+
+	// the resources of the other services are kept when the service is removed
+	assert.True(t, az.setRegionalServiceResources(lb, &service, nil, nil, nil))
+	assert.Len(t, lb.Properties.FrontendIPConfigurations, 1)
+	assert.Equal(t, az.getRegionalFrontendIPConfigName(&other), ptr.Deref(lb.Properties.FrontendIPConfigurations[0].Name, ""))
+	assert.Len(t, lb.Properties.LoadBalancingRules, 1)
+	assert.False(t, az.setRegionalServiceResources(lb, &service, nil, nil, nil))
+}
+
+func TestSetRegionalServiceResourcesRenamesFrontend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	// the regional frontend never matches the prefix of the frontends of the service on the cluster load balancer
+	assert.False(t, strings.HasPrefix(az.getRegionalFrontendIPConfigName(&service), az.GetLoadBalancerName(context.Background(), "", &service)))
+
+	// the frontend created with the name of the frontend on the cluster load balancer is replaced
+	lb := &armnetwork.LoadBalancer{Name: ptr.To("kubernetes-westus2"), Properties: &armnetwork.LoadBalancerPropertiesFormat{
+		FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{{Name: ptr.To(az.getDefaultFrontendIPConfigName(&service))}},
+	}}
+	frontend := &armnetwork.FrontendIPConfiguration{
+		Name:       ptr.To(az.getRegionalFrontendIPConfigName(&service)),
+		Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To("pip")}},
+	}
+	assert.True(t, az.setRegionalServiceResources(lb, &service, frontend, nil, nil))
+	assert.Equal(t, []*armnetwork.FrontendIPConfiguration{frontend}, lb.Properties.FrontendIPConfigurations)
+	assert.False(t, az.setRegionalServiceResources(lb, &service, frontend, nil, nil))
+}
+
+func TestExcludeRegionalLoadBalancers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+
+	clusterLB := &armnetwork.LoadBalancer{Name: ptr.To("kubernetes"), Location: ptr.To(az.Location)}
+	regionalLB := &armnetwork.LoadBalancer{Name: ptr.To("kubernetes-westus2"), Location: ptr.To("westus2")}
+	// a load balancer named after the region in the location of the cluster is not regional
+	namedLB := &armnetwork.LoadBalancer{Name: ptr.To("kubernetes-westus2"), Location: ptr.To(az.Location)}
+	assert.True(t, az.isRegionalLoadBalancer(regionalLB))
+	assert.Equal(t, []*armnetwork.LoadBalancer{clusterLB, namedLB}, az.excludeRegionalLoadBalancers([]*armnetwork.LoadBalancer{clusterLB, regionalLB, namedLB}))
+}
+
+func newTestRegionalNode(name, region, ip string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelTopologyRegion: region}},
+		Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: ip}}},
+	}
+}
+
+func TestSetRegionalBackendPool(t *testing.T) {
+	regional := config.RegionalLoadBalancerConfiguration{Region: "westus2", VirtualNetworkID: testRegionalVNetID}
+	nodes := []*v1.Node{
+		newTestRegionalNode("node2", "westus2", "10.1.0.5"),
+		newTestRegionalNode("node1", "WestUS2", "10.1.0.4"),
+		newTestRegionalNode("node0", "eastus", "10.0.0.4"),
+	}
+	lb := &armnetwork.LoadBalancer{Properties: &armnetwork.LoadBalancerPropertiesFormat{}}
+
+	assert.True(t, setRegionalBackendPool(lb, "kubernetes", regional, nodes))
+	assert.Len(t, lb.Properties.BackendAddressPools, 1)
+	addresses := lb.Properties.BackendAddressPools[0].Properties.LoadBalancerBackendAddresses
+	assert.Len(t, addresses, 2)
+	assert.Equal(t, "node1", ptr.Deref(addresses[0].Name, ""))
+	assert.Equal(t, "10.1.0.4", ptr.Deref(addresses[0].Properties.IPAddress, ""))
+	assert.Equal(t, testRegionalVNetID, ptr.Deref(addresses[0].Properties.VirtualNetwork.ID, ""))
+
+	assert.False(t, setRegionalBackendPool(lb, "kubernetes", regional, nodes))
+	assert.True(t, setRegionalBackendPool(lb, "kubernetes", regional, nodes[1:]))
+	assert.Len(t, lb.Properties.BackendAddressPools[0].Properties.LoadBalancerBackendAddresses, 1)
+}
+
+func TestReconcileRegionalFrontends(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+
+	service := getTestService("service", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationLoadBalancerRegions: "westus2"}, false, 80)
+	pipName := az.getRegionalPublicIPName(&service, "westus2")
+	pip := &armnetwork.PublicIPAddress{
+		Name:       ptr.To(pipName),
+		ID:         ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/" + pipName),
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.0.0.1")},
+	}
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
+	// the regional load balancer is created with the frontend of the selected region only
+	var created armnetwork.LoadBalancer
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "kubernetes-westus2", gomock.Any()).Return(nil, notFound)
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "kubernetes-centralus", gomock.Any()).Return(nil, notFound).AnyTimes()
+	mockPIPClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.PublicIPAddress{pip}, nil).AnyTimes()
+	mockLBClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, "kubernetes-westus2", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, lb armnetwork.LoadBalancer) (*armnetwork.LoadBalancer, error) {
+			created = lb
+			return &lb, nil
+		})
+
+	nodes := []*v1.Node{newTestRegionalNode("node1", "westus2", "10.1.0.4")}
+	ingress, err := az.reconcileRegionalFrontends(context.Background(), "kubernetes", &service, nodes, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.LoadBalancerIngress{{IP: "20.0.0.1"}}, ingress)
+	assert.Equal(t, "westus2", ptr.Deref(created.Location, ""))
+	assert.Len(t, created.Properties.FrontendIPConfigurations, 1)
+	assert.Equal(t, pip.ID, created.Properties.FrontendIPConfigurations[0].Properties.PublicIPAddress.ID)
+	assert.Len(t, created.Properties.LoadBalancingRules, 1)
+	assert.Len(t, created.Properties.BackendAddressPools[0].Properties.LoadBalancerBackendAddresses, 1)
+
+	// the load balancer without frontends is deleted with the public IP when the service is deleted
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "kubernetes-westus2", gomock.Any()).Return(&created, nil)
+	mockLBClient.EXPECT().Delete(gomock.Any(), az.ResourceGroup, "kubernetes-westus2").Return(nil)
+	mockPIPClient.EXPECT().Delete(gomock.Any(), az.ResourceGroup, pipName).Return(nil)
+	ingress, err = az.reconcileRegionalFrontends(context.Background(), "kubernetes", &service, nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, ingress)
+}
+
+func TestReconcileRegionalFrontendsTagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := getTestRegionalCloud(ctrl)
+	az.TagsMap = map[string]string{"team": "network"}
+
+	service := getTestService("service", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationLoadBalancerRegions: "westus2"}, false, 80)
+	pipName := az.getRegionalPublicIPName(&service, "westus2")
+	pip := &armnetwork.PublicIPAddress{
+		Name:       ptr.To(pipName),
+		ID:         ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/" + pipName),
+		Tags:       map[string]*string{consts.ClusterNameKey: ptr.To("kubernetes")},
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.0.0.1")},
+	}
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
+	// the existing public IP and the created load balancer are tagged as configured
+	var created armnetwork.LoadBalancer
+	var updatedPIP armnetwork.PublicIPAddress
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, gomock.Any(), gomock.Any()).Return(nil, notFound).AnyTimes()
+	mockPIPClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.PublicIPAddress{pip}, nil).AnyTimes()
+	mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, pipName, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, pip armnetwork.PublicIPAddress) (*armnetwork.PublicIPAddress, error) {
+			updatedPIP = pip
+			return &pip, nil
+		})
+	mockLBClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, "kubernetes-westus2", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, lb armnetwork.LoadBalancer) (*armnetwork.LoadBalancer, error) {
+			created = lb
+			return &lb, nil
+		})
+
+	nodes := []*v1.Node{newTestRegionalNode("node1", "westus2", "10.1.0.4")}
+	_, err := az.reconcileRegionalFrontends(context.Background(), "kubernetes", &service, nodes, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*string{
+		consts.ClusterNameKey: ptr.To("kubernetes"),
+		"team":                ptr.To("network"),
+	}, updatedPIP.Tags)
+	assert.Equal(t, map[string]*string{"team": ptr.To("network")}, created.Tags)
+}
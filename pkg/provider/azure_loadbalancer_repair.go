@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+const (
+	// loadBalancerUnhealthyReasonFailed is the reason of the load balancers in the Failed provisioning state.
+	loadBalancerUnhealthyReasonFailed = "failed"
+	// loadBalancerUnhealthyReasonOrphanedResources is the reason of the load balancers with rules, probes or
+	// frontend IP configurations of the services which do not exist anymore.
+	loadBalancerUnhealthyReasonOrphanedResources = "orphaned_resources"
+)
+
+// serviceResourceNameRE matches the names of the load balancer resources created for the services, which
+// start with the 32 characters long default load balancer name of the service.
+var serviceResourceNameRE = regexp.MustCompile(`^(?i)(a[0-9a-f]{31})`)
+
+// GetUnhealthyLoadBalancers returns the reasons of the unhealthy load balancers of the cluster by their
+// names. A load balancer is unhealthy if it is in the Failed provisioning state, or it has orphaned
+// resources of the services which do not exist anymore. Only the standard load balancers are checked.
+func (az *Cloud) GetUnhealthyLoadBalancers(ctx context.Context, clusterName string) (map[string]string, error) {
+	if !az.UseStandardLoadBalancer() || az.serviceLister == nil {
+		return nil, nil
+	}
+	ctx = withARMCaller(ctx, armCallerBackground)
+	if !az.armRequestBudget.allowBackground() {
+		log.FromContextOrBackground(ctx).WithName("GetUnhealthyLoadBalancers").V(2).Info("Skipped as the ARM request budget is nearly consumed")
+		return nil, nil
+	}
+	rgName := az.getLoadBalancerResourceGroup()
+	lbs, err := az.NetworkClientFactory.GetLoadBalancerClient().List(ctx, rgName)
+	if err != nil {
+		return nil, fmt.Errorf("list the load balancers in resource group %s: %w", rgName, errutils.WithARMRequestIDs(err))
+	}
+	lbs = az.excludeRegionalLoadBalancers(lbs)
+	owners, err := az.getServiceResourceNamePrefixes()
+	if err != nil {
+		return nil, err
+	}
+
+	managedLBNames := az.getManagedStandardLBNames(clusterName)
+	unhealthy := make(map[string]string)
+	for _, lb := range lbs {
+		name := ptr.Deref(lb.Name, "")
+		if !managedLBNames.Has(strings.ToLower(trimSuffixIgnoreCase(name, consts.InternalLoadBalancerNameSuffix))) || lb.Properties == nil {
+			continue
+		}
+		switch {
+		case ptr.Deref(lb.Properties.ProvisioningState, "") == armnetwork.ProvisioningStateFailed:
+			unhealthy[name] = loadBalancerUnhealthyReasonFailed
+		case removeOrphanedLBResources(lb, owners):
+			unhealthy[name] = loadBalancerUnhealthyReasonOrphanedResources
+		}
+	}
+	return unhealthy, nil
+}
+
+// RepairLoadBalancer re-issues the update of the load balancer in the Failed provisioning state, with
+// the orphaned resources of the services removed. The load balancer is refreshed before it is checked,
+// and it is not updated if it is healthy or it is being updated asynchronously.
+func (az *Cloud) RepairLoadBalancer(ctx context.Context, name string) error {
+	ctx = withARMCaller(ctx, armCallerBackground)
+	logger := log.FromContextOrBackground(ctx).WithName("RepairLoadBalancer").WithValues("loadBalancer", name)
+	if az.lbOperationTracker != nil && az.lbOperationTracker.get(name) != nil {
+		logger.V(2).Info("Skipping the load balancer which is being updated")
+		return nil
+	}
+
+	// the repair goes after the reconciliations of the services updating the load balancers
+	az.serviceReconcileLock.Lock(reconcilePriorityNodeSync)
+	defer az.serviceReconcileLock.Unlock()
+
+	lb, exists, err := az.getAzureLoadBalancer(ctx, name, azcache.CacheReadTypeForceRefresh)
+	if err != nil {
+		return err
+	}
+	if !exists || lb.Properties == nil {
+		return nil
+	}
+	owners, err := az.getServiceResourceNamePrefixes()
+	if err != nil {
+		return err
+	}
+	failed := ptr.Deref(lb.Properties.ProvisioningState, "") == armnetwork.ProvisioningStateFailed
+	orphaned := removeOrphanedLBResources(lb, owners)
+	if !failed && !orphaned {
+		return nil
+	}
+
+	logger.V(2).Info("Updating the load balancer", "failed", failed, "orphanedResourcesRemoved", orphaned)
+	updated := cleanupSubnetInFrontendIPConfigurations(lb)
+	_, err = az.NetworkClientFactory.GetLoadBalancerClient().CreateOrUpdate(ctx, az.getLoadBalancerResourceGroup(), name, updated)
+	_ = az.lbCache.Delete(name)
+	if err != nil {
+		return fmt.Errorf("update load balancer %s: %w", name, errutils.WithARMRequestIDs(err))
+	}
+	return nil
+}
+
+// getManagedStandardLBNames returns the lower case names of the standard load balancers of the cluster,
+// without the suffix of the internal ones.
+func (az *Cloud) getManagedStandardLBNames(clusterName string) sets.Set[string] {
+	if az.LoadBalancerName != "" {
+		clusterName = az.LoadBalancerName
+	}
+	names := sets.New(strings.ToLower(clusterName))
+	for _, multiSLBConfig := range az.MultipleStandardLoadBalancerConfigurations {
+		names.Insert(strings.ToLower(multiSLBConfig.Name))
+	}
+	return names
+}
+
+// getServiceResourceNamePrefixes returns the lower case prefixes of the names of the load balancer
+// resources of the services which want load balancers.
+func (az *Cloud) getServiceResourceNamePrefixes() (sets.Set[string], error) {
+	services, err := az.serviceLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("list the services: %w", err)
+	}
+	prefixes := sets.New[string]()
+	for _, service := range services {
+		if az.wantsLoadBalancer(service) {
+			prefixes.Insert(strings.ToLower(az.getRulePrefix(service)))
+		}
+	}
+	return prefixes, nil
+}
+
+// isOrphanedServiceResourceName returns true if the load balancer resource is created for a service
+// which is not in the owners.
+func isOrphanedServiceResourceName(name string, owners sets.Set[string]) bool {
+	matches := serviceResourceNameRE.FindStringSubmatch(name)
+	return len(matches) == 2 && !owners.Has(strings.ToLower(matches[1]))
+}
+
+// removeOrphanedLBResources removes the load balancing rules, the probes and the frontend IP configurations
+// of the services not in the owners from the load balancer. The probes and the frontend IP configurations
+// still referenced by the other rules are kept. It returns true if any resource is removed.
+func removeOrphanedLBResources(lb *armnetwork.LoadBalancer, owners sets.Set[string]) bool {
+	removed := false
+	referencedIDs := sets.New[string]()
+	reference := func(subResource *armnetwork.SubResource) {
+		if subResource != nil {
+			referencedIDs.Insert(strings.ToLower(ptr.Deref(subResource.ID, "")))
+		}
+	}
+
+	var rules []*armnetwork.LoadBalancingRule
+	for _, rule := range lb.Properties.LoadBalancingRules {
+		if rule != nil && isOrphanedServiceResourceName(ptr.Deref(rule.Name, ""), owners) {
+			removed = true
+			continue
+		}
+		rules = append(rules, rule)
+		if rule != nil && rule.Properties != nil {
+			reference(rule.Properties.FrontendIPConfiguration)
+			reference(rule.Properties.Probe)
+		}
+	}
+	if removed {
+		lb.Properties.LoadBalancingRules = rules
+	}
+	for _, rule := range lb.Properties.OutboundRules {
+		if rule != nil && rule.Properties != nil {
+			for _, fip := range rule.Properties.FrontendIPConfigurations {
+				reference(fip)
+			}
+		}
+	}
+	for _, rule := range lb.Properties.InboundNatRules {
+		if rule != nil && rule.Properties != nil {
+			reference(rule.Properties.FrontendIPConfiguration)
+		}
+	}
+	for _, pool := range lb.Properties.InboundNatPools {
+		if pool != nil && pool.Properties != nil {
+			reference(pool.Properties.FrontendIPConfiguration)
+		}
+	}
+
+	isOrphaned := func(name, id *string) bool {
+		return isOrphanedServiceResourceName(ptr.Deref(name, ""), owners) && !referencedIDs.Has(strings.ToLower(ptr.Deref(id, "")))
+	}
+	var probesRemoved bool
+	var probes []*armnetwork.Probe
+	for _, probe := range lb.Properties.Probes {
+		if probe != nil && isOrphaned(probe.Name, probe.ID) {
+			probesRemoved = true
+			continue
+		}
+		probes = append(probes, probe)
+	}
+	if probesRemoved {
+		lb.Properties.Probes = probes
+	}
+	var fipsRemoved bool
+	var fips []*armnetwork.FrontendIPConfiguration
+	for _, fip := range lb.Properties.FrontendIPConfigurations {
+		if fip != nil && isOrphaned(fip.Name, fip.ID) {
+			fipsRemoved = true
+			continue
+		}
+		fips = append(fips, fip)
+	}
+	if fipsRemoved {
+		lb.Properties.FrontendIPConfigurations = fips
+	}
+	return removed || probesRemoved || fipsRemoved
+}
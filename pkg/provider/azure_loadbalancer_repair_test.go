@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+const (
+	testRepairServicePrefix  = "a1000000000000000000000000000000"
+	testRepairOrphanedPrefix = "a2000000000000000000000000000000"
+)
+
+func newTestRepairLB(name string, state armnetwork.ProvisioningState, prefixes ...string) *armnetwork.LoadBalancer {
+	lbID := "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/" + name
+	lb := &armnetwork.LoadBalancer{
+		Name: ptr.To(name),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			ProvisioningState: ptr.To(state),
+			OutboundRules: []*armnetwork.OutboundRule{
+				{
+					Name: ptr.To("aksOutboundRule"),
+					Properties: &armnetwork.OutboundRulePropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.SubResource{{ID: ptr.To(lbID + "/frontendIPConfigurations/outbound")}},
+					},
+				},
+			},
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{{Name: ptr.To("outbound"), ID: ptr.To(lbID + "/frontendIPConfigurations/outbound")}},
+		},
+	}
+	for _, prefix := range prefixes {
+		fipID := lbID + "/frontendIPConfigurations/" + prefix
+		probeID := lbID + "/probes/" + prefix + "-TCP-80"
+		lb.Properties.FrontendIPConfigurations = append(lb.Properties.FrontendIPConfigurations, &armnetwork.FrontendIPConfiguration{Name: ptr.To(prefix), ID: ptr.To(fipID)})
+		lb.Properties.Probes = append(lb.Properties.Probes, &armnetwork.Probe{Name: ptr.To(prefix + "-TCP-80"), ID: ptr.To(probeID)})
+		lb.Properties.LoadBalancingRules = append(lb.Properties.LoadBalancingRules, &armnetwork.LoadBalancingRule{
+			Name: ptr.To(prefix + "-TCP-80"),
+			Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+				FrontendIPConfiguration: &armnetwork.SubResource{ID: ptr.To(fipID)},
+				Probe:                   &armnetwork.SubResource{ID: ptr.To(probeID)},
+			},
+		})
+	}
+	return lb
+}
+
+func getTestRepairCloud(ctrl *gomock.Controller) *Cloud {
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = indexer.Add(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "10000000-0000-0000-0000-000000000000"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+	})
+	az.serviceLister = corelisters.NewServiceLister(indexer)
+	return az
+}
+
+func TestGetUnhealthyLoadBalancers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := getTestRepairCloud(ctrl)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{
+		newTestRepairLB("kubernetes", armnetwork.ProvisioningStateSucceeded, testRepairServicePrefix),
+		newTestRepairLB("kubernetes-internal", armnetwork.ProvisioningStateSucceeded, testRepairServicePrefix, testRepairOrphanedPrefix),
+		newTestRepairLB("failed", armnetwork.ProvisioningStateFailed),
+		newTestRepairLB("Kubernetes-Failed", armnetwork.ProvisioningStateFailed),
+	}, nil)
+	az.MultipleStandardLoadBalancerConfigurations = []config.MultipleStandardLoadBalancerConfiguration{{Name: "kubernetes-failed"}}
+
+	unhealthy, err := az.GetUnhealthyLoadBalancers(context.Background(), "kubernetes")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kubernetes-internal": loadBalancerUnhealthyReasonOrphanedResources,
+		"Kubernetes-Failed":   loadBalancerUnhealthyReasonFailed,
+	}, unhealthy)
+}
+
+func TestRepairLoadBalancer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := getTestRepairCloud(ctrl)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "kubernetes", gomock.Any()).
+		Return(newTestRepairLB("kubernetes", armnetwork.ProvisioningStateFailed, testRepairServicePrefix, testRepairOrphanedPrefix), nil)
+	mockLBClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, "kubernetes", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, lb armnetwork.LoadBalancer) (*armnetwork.LoadBalancer, error) {
+			// the orphaned resources are removed from the updated load balancer
+			assert.Equal(t, *newTestRepairLB("kubernetes", armnetwork.ProvisioningStateFailed, testRepairServicePrefix).Properties, *lb.Properties)
+			return &lb, nil
+		})
+	assert.NoError(t, az.RepairLoadBalancer(context.Background(), "kubernetes"))
+
+	// the healthy load balancer is not updated
+	mockLBClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "kubernetes", gomock.Any()).
+		Return(newTestRepairLB("kubernetes", armnetwork.ProvisioningStateSucceeded, testRepairServicePrefix), nil)
+	assert.NoError(t, az.RepairLoadBalancer(context.Background(), "kubernetes"))
+}
+
+func TestRemoveOrphanedLBResourcesKeepsReferencedResources(t *testing.T) {
+	lb := newTestRepairLB("kubernetes", armnetwork.ProvisioningStateSucceeded, testRepairOrphanedPrefix)
+	// the frontend of the orphaned service is shared by the rule of the existing service
+	lb.Properties.LoadBalancingRules = append(lb.Properties.LoadBalancingRules, &armnetwork.LoadBalancingRule{
+		Name: ptr.To(testRepairServicePrefix + "-TCP-443"),
+		Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+			FrontendIPConfiguration: lb.Properties.LoadBalancingRules[0].Properties.FrontendIPConfiguration,
+		},
+	})
+
+	assert.True(t, removeOrphanedLBResources(lb, sets.New(testRepairServicePrefix)))
+	assert.Equal(t, []string{testRepairServicePrefix + "-TCP-443"}, getTestRepairNames(lb.Properties.LoadBalancingRules))
+	assert.Empty(t, lb.Properties.Probes)
+	assert.Len(t, lb.Properties.FrontendIPConfigurations, 2)
+
+	assert.False(t, removeOrphanedLBResources(lb, sets.New(testRepairServicePrefix)))
+}
+
+func getTestRepairNames(rules []*armnetwork.LoadBalancingRule) []string {
+	var names []string
+	for _, rule := range rules {
+		names = append(names, ptr.Deref(rule.Name, ""))
+	}
+	return names
+}
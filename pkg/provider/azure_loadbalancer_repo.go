@@ -50,7 +50,7 @@ func (az *Cloud) DeleteLB(ctx context.Context, service *v1.Service, lbName strin
 	}
 
 	klog.Errorf("LoadbalancerClient.Delete(%s) failed: %s", lbName, rerr.Error())
-	az.Event(service, v1.EventTypeWarning, "DeleteLoadBalancer", rerr.Error())
+	az.Event(service, v1.EventTypeWarning, "DeleteLoadBalancer", getAzureErrorEventMessage(rerr))
 	return rerr
 }
 
@@ -62,12 +62,12 @@ func (az *Cloud) ListLB(ctx context.Context, service *v1.Service) ([]*armnetwork
 		if exist, err := errutils.CheckResourceExistsFromAzcoreError(rerr); !exist && err == nil {
 			return nil, nil
 		}
-		az.Event(service, v1.EventTypeWarning, "ListLoadBalancers", rerr.Error())
+		az.Event(service, v1.EventTypeWarning, "ListLoadBalancers", getAzureErrorEventMessage(rerr))
 		klog.Errorf("LoadbalancerClient.List(%v) failure with err=%v", rgName, rerr)
 		return nil, rerr
 	}
 	klog.V(2).Infof("LoadbalancerClient.List(%v) success", rgName)
-	return allLBs, nil
+	return az.excludeRegionalLoadBalancers(allLBs), nil
 }
 
 // ListManagedLBs invokes az.NetworkClientFactory.GetLoadBalancerClient().List and filter out
@@ -130,6 +130,10 @@ func (az *Cloud) ListManagedLBs(ctx context.Context, service *v1.Service, nodes
 func (az *Cloud) CreateOrUpdateLB(ctx context.Context, service *v1.Service, lb armnetwork.LoadBalancer) error {
 	lb = cleanupSubnetInFrontendIPConfigurations(&lb)
 
+	if az.lbOperationTracker != nil {
+		return az.createOrUpdateLBAsync(ctx, service, lb)
+	}
+
 	rgName := az.getLoadBalancerResourceGroup()
 	_, err := az.NetworkClientFactory.GetLoadBalancerClient().CreateOrUpdate(ctx, rgName, ptr.Deref(lb.Name, ""), lb)
 	klog.V(10).Infof("LoadbalancerClient.CreateOrUpdate(%s): end", *lb.Name)
@@ -138,9 +142,15 @@ func (az *Cloud) CreateOrUpdateLB(ctx context.Context, service *v1.Service, lb a
 		_ = az.lbCache.Delete(*lb.Name)
 		return nil
 	}
+	return az.handleCreateOrUpdateLBError(ctx, service, lb, err)
+}
 
+// handleCreateOrUpdateLBError invalidates the caches and repairs the referenced public IP
+// according to the error of a failed load balancer update. It returns the error to retry with.
+func (az *Cloud) handleCreateOrUpdateLBError(ctx context.Context, service *v1.Service, lb armnetwork.LoadBalancer, err error) error {
 	lbJSON, _ := json.Marshal(lb)
 	klog.Warningf("LoadbalancerClient.CreateOrUpdate(%s) failed: %v, LoadBalancer request: %s", ptr.Deref(lb.Name, ""), err, string(lbJSON))
+	az.Event(service, v1.EventTypeWarning, "CreateOrUpdateLoadBalancer", getAzureErrorEventMessage(err))
 	var rerr *azcore.ResponseError
 	if !errors.As(err, &rerr) {
 		return err
@@ -411,9 +421,8 @@ func isNICPool(bp *armnetwork.BackendAddressPool) bool {
 	return false
 }
 
-// cleanupBasicLoadBalancer removes outdated basic load balancers
-// when the loadBalancerSkus is `Standard`. It ensures the backend pool of the basic
-// load balancer is empty before deleting the load balancer.
+// cleanupBasicLoadBalancer migrates outdated basic load balancers
+// when the loadBalancerSkus is `Standard`, see migrateBasicLoadBalancer.
 func (az *Cloud) cleanupBasicLoadBalancer(
 	ctx context.Context, clusterName string, service *v1.Service, existingLBs []*armnetwork.LoadBalancer,
 ) ([]*armnetwork.LoadBalancer, error) {
@@ -425,9 +434,8 @@ func (az *Cloud) cleanupBasicLoadBalancer(
 	for i := len(existingLBs) - 1; i >= 0; i-- {
 		lb := existingLBs[i]
 		if lb != nil && lb.SKU != nil && lb.SKU.Name != nil && *lb.SKU.Name == armnetwork.LoadBalancerSKUNameBasic {
-			klog.V(2).Infof("cleanupBasicLoadBalancer: found basic load balancer %q, removing it", *lb.Name)
-			if err := az.safeDeleteLoadBalancer(ctx, *lb, clusterName, service); err != nil {
-				klog.ErrorS(err, "cleanupBasicLoadBalancer: failed to delete outdated basic load balancer", "loadBalancerName", *lb.Name)
+			klog.V(2).Infof("cleanupBasicLoadBalancer: found basic load balancer %q, migrating it", *lb.Name)
+			if err := az.migrateBasicLoadBalancer(ctx, clusterName, service, lb); err != nil {
 				return nil, err
 			}
 			existingLBs = append(existingLBs[:i], existingLBs[i+1:]...)
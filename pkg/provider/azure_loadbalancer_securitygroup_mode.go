@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// securityGroupManagementModes are the security group management modes from the least to the most strict.
+var securityGroupManagementModes = []string{
+	consts.SecurityGroupManagementModeFull,
+	consts.SecurityGroupManagementModeAppendOnly,
+	consts.SecurityGroupManagementModeDisabled,
+}
+
+// getSecurityGroupManagementModeStrictness returns the index of the mode in securityGroupManagementModes,
+// or -1 if the mode is not supported.
+func getSecurityGroupManagementModeStrictness(mode string) int {
+	for i := range securityGroupManagementModes {
+		if strings.EqualFold(securityGroupManagementModes[i], mode) {
+			return i
+		}
+	}
+	return -1
+}
+
+// getSecurityGroupManagementMode returns the security group management mode of the service, which is the
+// stricter one of the cloud provider config and the service annotation. An invalid annotation is ignored
+// with a warning event.
+func (az *Cloud) getSecurityGroupManagementMode(service *v1.Service) string {
+	strictness := max(getSecurityGroupManagementModeStrictness(az.SecurityGroupManagementMode), 0)
+	if value, ok := service.Annotations[consts.ServiceAnnotationSecurityGroupManagementMode]; ok {
+		serviceStrictness := getSecurityGroupManagementModeStrictness(value)
+		if serviceStrictness < 0 {
+			warningMsg := fmt.Sprintf("annotation %s=%s is not supported, supported values are %v, ignoring it",
+				consts.ServiceAnnotationSecurityGroupManagementMode, value, securityGroupManagementModes)
+			klog.InfoS("getSecurityGroupManagementMode: invalid security group management mode", "service", getServiceName(service), "mode", value, "supportedModes", securityGroupManagementModes)
+			az.Event(service, v1.EventTypeWarning, "InvalidSecurityGroupManagementMode", warningMsg)
+		}
+		strictness = max(strictness, serviceStrictness)
+	}
+	return securityGroupManagementModes[strictness]
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestGetSecurityGroupManagementMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tc := range []struct {
+		desc            string
+		clusterMode     string
+		serviceMode     *string
+		expectedMode    string
+		expectedWarning bool
+	}{
+		{
+			desc:         "full by default",
+			expectedMode: consts.SecurityGroupManagementModeFull,
+		},
+		{
+			desc:         "cluster mode",
+			clusterMode:  "Append-Only",
+			expectedMode: consts.SecurityGroupManagementModeAppendOnly,
+		},
+		{
+			desc:         "stricter service mode",
+			clusterMode:  consts.SecurityGroupManagementModeAppendOnly,
+			serviceMode:  ptr.To(consts.SecurityGroupManagementModeDisabled),
+			expectedMode: consts.SecurityGroupManagementModeDisabled,
+		},
+		{
+			desc:         "less strict service mode",
+			clusterMode:  consts.SecurityGroupManagementModeDisabled,
+			serviceMode:  ptr.To(consts.SecurityGroupManagementModeFull),
+			expectedMode: consts.SecurityGroupManagementModeDisabled,
+		},
+		{
+			desc:            "invalid service mode",
+			clusterMode:     consts.SecurityGroupManagementModeAppendOnly,
+			serviceMode:     ptr.To("disable"),
+			expectedMode:    consts.SecurityGroupManagementModeAppendOnly,
+			expectedWarning: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := GetTestCloud(ctrl)
+			recorder := record.NewFakeRecorder(1)
+			az.eventRecorder = recorder
+			az.SecurityGroupManagementMode = tc.clusterMode
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: map[string]string{}}}
+			if tc.serviceMode != nil {
+				service.Annotations[consts.ServiceAnnotationSecurityGroupManagementMode] = *tc.serviceMode
+			}
+
+			assert.Equal(t, tc.expectedMode, az.getSecurityGroupManagementMode(service))
+			assert.Equal(t, tc.expectedWarning, len(recorder.Events) > 0)
+		})
+	}
+}
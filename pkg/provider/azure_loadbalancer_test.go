@@ -44,6 +44,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/backendaddresspoolclient/mock_backendaddresspoolclient"
@@ -2444,6 +2445,85 @@ func TestDeterminePublicIPName(t *testing.T) {
 	}
 }
 
+func TestGetLBRuleNotices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	internal := map[string]string{consts.ServiceAnnotationLoadBalancerInternal: consts.TrueAnnotationValue}
+	for _, tc := range []struct {
+		desc            string
+		service         v1.Service
+		loadBalancerSKU string
+		expected        []lbRuleNotice
+	}{
+		{
+			desc:            "the HA ports annotation is ignored by an external service",
+			service:         getTestService("test1", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts: consts.TrueAnnotationValue}, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+			expected: []lbRuleNotice{
+				{v1.EventTypeWarning, "UnsupportedHighAvailabilityPorts", "annotation service.beta.kubernetes.io/azure-load-balancer-enable-high-availability-ports is only supported on standard loadbalancer in internal mode, ignoring it"},
+			},
+		},
+		{
+			desc: "the HA ports annotation is used by an internal service",
+			service: getTestService("test1", v1.ProtocolSCTP, map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternal:                    consts.TrueAnnotationValue,
+				consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts: consts.TrueAnnotationValue,
+			}, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+		},
+		{
+			desc:            "no notice for the TCP ports",
+			service:         getTestService("test1", v1.ProtocolTCP, nil, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+		},
+		{
+			desc:            "the SCTP ports of an external service are skipped",
+			service:         getTestService("test1", v1.ProtocolSCTP, nil, false, 80, 81),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+			expected: []lbRuleNotice{
+				{v1.EventTypeWarning, "UnsupportedSCTPPort", "SCTP port 80 is only supported on standard loadbalancer in internal mode, skipping it"},
+				{v1.EventTypeWarning, "UnsupportedSCTPPort", "SCTP port 81 is only supported on standard loadbalancer in internal mode, skipping it"},
+			},
+		},
+		{
+			desc: "the SCTP ports without load balancing rules are not reported",
+			service: getTestService("test1", v1.ProtocolSCTP, map[string]string{
+				consts.BuildAnnotationKeyForPort(80, consts.PortAnnotationNoLBRule): consts.TrueAnnotationValue,
+			}, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+		},
+		{
+			desc:            "the SCTP ports of an internal service of the basic load balancer are skipped",
+			service:         getTestService("test1", v1.ProtocolSCTP, internal, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUBasic,
+			expected: []lbRuleNotice{
+				{v1.EventTypeWarning, "UnsupportedSCTPPort", "SCTP port 80 is only supported on standard loadbalancer in internal mode, skipping it"},
+			},
+		},
+		{
+			desc:            "the SCTP ports of an internal service are served by the HA ports rule",
+			service:         getTestService("test1", v1.ProtocolSCTP, internal, false, 80),
+			loadBalancerSKU: consts.LoadBalancerSKUStandard,
+			expected: []lbRuleNotice{
+				{v1.EventTypeNormal, "SCTPUsingHighAvailabilityPorts", "SCTP ports are served by a high availability ports rule forwarding all the ports of the service"},
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := GetTestCloud(ctrl)
+			az.LoadBalancerSKU = tc.loadBalancerSKU
+			recorder := record.NewFakeRecorder(10)
+			az.eventRecorder = recorder
+
+			assert.Equal(t, tc.expected, az.getLBRuleNotices(&tc.service))
+			// the rules are built without reporting the notices
+			_, _, _ = az.getExpectedLBRules(&tc.service, "frontendIPConfigID", "backendPoolID", "lbname", false)
+			assert.Empty(t, recorder.Events)
+		})
+	}
+}
+
 func TestReconcileLoadBalancerRuleCommon(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2482,10 +2562,25 @@ func TestReconcileLoadBalancerRuleCommon(t *testing.T) {
 			expectedProbes:  getDefaultTestProbes("Tcp", ""),
 		},
 		{
-			desc:            "getExpectedLBRules shall return error (slb with external mode and SCTP)",
+			desc:            "getExpectedLBRules shall skip the SCTP ports (slb with external mode and SCTP)",
 			service:         getTestServiceDualStack("test1", v1.ProtocolSCTP, map[string]string{}, 80),
 			loadBalancerSKU: "standard",
-			expectedErr:     true,
+		},
+		{
+			desc:            "getExpectedLBRules shall skip the SCTP ports (basic lb with internal mode and SCTP)",
+			service:         getTestServiceDualStack("test1", v1.ProtocolSCTP, map[string]string{consts.ServiceAnnotationLoadBalancerInternal: "true"}, 80),
+			loadBalancerSKU: "basic",
+		},
+		{
+			desc: "getExpectedLBRules shall fall back to the HA mode rule (slb with internal mode and SCTP)",
+			service: getTestServiceDualStack("test1", v1.ProtocolSCTP, map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternal: "true",
+			}, 80),
+			loadBalancerSKU: "standard",
+			expectedRules: map[bool][]*armnetwork.LoadBalancingRule{
+				consts.IPVersionIPv4: getHATestRules(true, false, v1.ProtocolSCTP, consts.IPVersionIPv4, true),
+				consts.IPVersionIPv6: getHATestRules(true, false, v1.ProtocolSCTP, consts.IPVersionIPv6, true),
+			},
 		},
 		{
 			desc:            "getExpectedLBRules shall return corresponding probe and lbRule(slb with tcp reset)",
@@ -2549,6 +2644,27 @@ func TestReconcileLoadBalancerRuleCommon(t *testing.T) {
 				consts.IPVersionIPv6: getHATestRules(true, false, v1.ProtocolSCTP, consts.IPVersionIPv6, true),
 			},
 		},
+		{
+			desc: "getExpectedLBRules shall ignore HA mode and return per-port rules (slb with HA enabled in external mode)",
+			service: getTestServiceDualStack("test1", v1.ProtocolTCP, map[string]string{
+				consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts: "true",
+			}, 80),
+			loadBalancerSKU: "standard",
+			expectedProbes:  getDefaultTestProbes("Tcp", ""),
+			expectedRules:   getDefaultTestRules(true),
+		},
+		{
+			desc: "getExpectedLBRules shall ignore HA mode and return per-port rules (blb with HA enabled in internal mode)",
+			service: getTestService("test1", v1.ProtocolTCP, map[string]string{
+				consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts: "true",
+				consts.ServiceAnnotationLoadBalancerInternal:                    "true",
+			}, false, 80),
+			loadBalancerSKU: "basic",
+			expectedProbes:  getDefaultTestProbes("Tcp", ""),
+			expectedRules: map[bool][]*armnetwork.LoadBalancingRule{
+				consts.IPVersionIPv4: {getTestRule(false, 80, consts.IPVersionIPv4)},
+			},
+		},
 		{
 			desc: "getExpectedLBRules shall return corresponding probe and lbRule (slb with HA enabled multi-ports services)",
 			service: getTestServiceDualStack("test1", v1.ProtocolTCP, map[string]string{
@@ -3138,6 +3254,56 @@ func TestGetExpectedLBRulesSharedProbe(t *testing.T) {
 	}
 }
 
+func TestGetExpectedLBRulesMixedProtocol(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	svc := getTestService("test1", v1.ProtocolTCP, map[string]string{
+		consts.BuildHealthProbeAnnotationKeyForPort(53, consts.HealthProbeParamsProtocol): "tcp",
+	}, false, 53, 80)
+	svc.Spec.Ports = append(svc.Spec.Ports,
+		v1.ServicePort{Name: "port-udp-53", Protocol: v1.ProtocolUDP, Port: 53, NodePort: getBackendPort(53)},
+		v1.ServicePort{Name: "port-udp-54", Protocol: v1.ProtocolUDP, Port: 54, NodePort: getBackendPort(54)},
+	)
+
+	probes, rules, err := az.getExpectedLBRules(&svc, "frontendIPConfigID", "backendPoolID", "lbname", consts.IPVersionIPv4)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(probes))
+	assert.Equal(t, 4, len(rules))
+
+	rulesByName := make(map[string]*armnetwork.LoadBalancingRule)
+	for _, rule := range rules {
+		rulesByName[*rule.Name] = rule
+	}
+	assert.Equal(t, armnetwork.TransportProtocolUDP, *rulesByName["atest1-UDP-53"].Properties.Protocol)
+	assert.Equal(t, az.getLoadBalancerProbeID("lbname", "atest1-TCP-53"), *rulesByName["atest1-UDP-53"].Properties.Probe.ID)
+	assert.Equal(t, az.getLoadBalancerProbeID("lbname", "atest1-TCP-53"), *rulesByName["atest1-TCP-53"].Properties.Probe.ID)
+	assert.Equal(t, az.getLoadBalancerProbeID("lbname", "atest1-TCP-80"), *rulesByName["atest1-TCP-80"].Properties.Probe.ID)
+	assert.Nil(t, rulesByName["atest1-UDP-54"].Properties.Probe)
+
+	// the UDP rules are left without a probe if the service does not opt in
+	delete(svc.Annotations, consts.BuildHealthProbeAnnotationKeyForPort(53, consts.HealthProbeParamsProtocol))
+	_, rules, err = az.getExpectedLBRules(&svc, "frontendIPConfigID", "backendPoolID", "lbname", consts.IPVersionIPv4)
+	assert.NoError(t, err)
+	for _, rule := range rules {
+		if *rule.Properties.Protocol == armnetwork.TransportProtocolUDP {
+			assert.Nil(t, rule.Properties.Probe)
+		}
+	}
+
+	// the service annotation also opts in
+	svc.Annotations[consts.ServiceAnnotationLoadBalancerHealthProbeProtocol] = "Tcp"
+	_, rules, err = az.getExpectedLBRules(&svc, "frontendIPConfigID", "backendPoolID", "lbname", consts.IPVersionIPv4)
+	assert.NoError(t, err)
+	for _, rule := range rules {
+		if *rule.Name == "atest1-UDP-53" {
+			assert.Equal(t, az.getLoadBalancerProbeID("lbname", "atest1-TCP-53"), *rule.Properties.Probe.ID)
+		}
+	}
+}
+
 // getDefaultTestRules returns dualstack rules.
 func getDefaultTestRules(enableTCPReset bool) map[bool][]*armnetwork.LoadBalancingRule {
 	return map[bool][]*armnetwork.LoadBalancingRule{
@@ -4762,7 +4928,8 @@ func TestReconcilePublicIPsCommon(t *testing.T) {
 					},
 				},
 			},
-			expectedCreateOrUpdateCount: 0,
+			// the user-assigned PIPs are tagged with the service
+			expectedCreateOrUpdateCount: 2,
 			expectedDeleteCount:         2,
 		},
 		{
@@ -5387,7 +5554,7 @@ func TestEnsurePublicIPExistsCommon(t *testing.T) {
 			},
 			expectedPIP: &armnetwork.PublicIPAddress{
 				Name: ptr.To("pip1"),
-				Tags: map[string]*string{"a": ptr.To("b")},
+				Tags: map[string]*string{"a": ptr.To("b"), consts.AdoptedByServicesKey: ptr.To("default/test1")},
 				ID:   ptr.To(expectedPIPID),
 				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
 					PublicIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv4),
@@ -5397,6 +5564,7 @@ func TestEnsurePublicIPExistsCommon(t *testing.T) {
 			additionalAnnotations: map[string]string{
 				consts.ServiceAnnotationAzurePIPTags: "a=c",
 			},
+			shouldPutPIP: true,
 		},
 	}
 
@@ -6263,6 +6431,27 @@ func TestEnsurePIPTagged(t *testing.T) {
 		assert.True(t, changed)
 		assert.Equal(t, expectedPIP, pip)
 	})
+
+	t.Run("ensurePIPTagged should apply the resource tags with lower precedence than the pip tags", func(t *testing.T) {
+		cloud.SystemTags = ""
+		cloud.TagsMap = nil
+		cloud.Tags = ""
+		service := v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					consts.ServiceAnnotationAzureResourceTags: "team=payments,costcenter=123",
+					consts.ServiceAnnotationAzurePIPTags:      "costcenter=456",
+				},
+			},
+		}
+		pip := armnetwork.PublicIPAddress{Tags: map[string]*string{}}
+		changed := cloud.ensurePIPTagged(&service, &pip)
+		assert.True(t, changed)
+		assert.Equal(t, map[string]*string{
+			"team":       ptr.To("payments"),
+			"costcenter": ptr.To("456"),
+		}, pip.Tags)
+	})
 }
 
 func TestEnsureLoadBalancerTagged(t *testing.T) {
@@ -6863,6 +7052,8 @@ func TestReconcileFrontendIPConfigs(t *testing.T) {
 			for _, pip := range tc.existingPIPs {
 				mockPIPClient.EXPECT().Get(gomock.Any(), "rg", *pip.Name, gomock.Any()).Return(pip, nil).MaxTimes(1)
 			}
+			// the user-assigned PIPs are tagged with the service
+			mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 			service := tc.service
 			isDualStack := isServiceDualStack(&service)
@@ -9101,3 +9292,38 @@ func fakeEnsureHostsInPool() func(context.Context, *v1.Service, []*v1.Node, stri
 		return nil
 	}
 }
+
+func TestReconcileLBRulesMigrateToHashedNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	legacyName := az.getLoadBalancerRuleName(&svc, v1.ProtocolTCP, 80, false)
+	az.LoadBalancerResourceNamingScheme = consts.LoadBalancerResourceNamingSchemeHashed
+	hashedName := az.getLoadBalancerRuleName(&svc, v1.ProtocolTCP, 80, false)
+	assert.NotEqual(t, legacyName, hashedName)
+
+	props := &armnetwork.LoadBalancingRulePropertiesFormat{
+		Protocol:     to.Ptr(armnetwork.TransportProtocolTCP),
+		FrontendPort: ptr.To(int32(80)),
+		BackendPort:  ptr.To(int32(80)),
+	}
+	lb := &armnetwork.LoadBalancer{
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
+				{Name: ptr.To(legacyName), Properties: props},
+				{Name: ptr.To("other-TCP-80"), Properties: props},
+			},
+		},
+	}
+	expectedRules := []*armnetwork.LoadBalancingRule{{Name: ptr.To(hashedName), Properties: props}}
+
+	changed := az.reconcileLBRules(lb, &svc, "default/test1", true, expectedRules)
+	assert.True(t, changed)
+	var names []string
+	for _, rule := range lb.Properties.LoadBalancingRules {
+		names = append(names, ptr.Deref(rule.Name, ""))
+	}
+	assert.ElementsMatch(t, []string{"other-TCP-80", hashedName}, names)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+const (
+	// minWindowsNodeHealthProbeIntervalInSeconds is the min interval of the health probes.
+	minWindowsNodeHealthProbeIntervalInSeconds = 5
+	// minWindowsNodeHealthProbeNumOfProbe is the min number of the failed probes marking a node unhealthy.
+	minWindowsNodeHealthProbeNumOfProbe = 2
+	// maxHealthProbeTotalTimeInSeconds is the exclusive upper bound of the interval times the number of probes.
+	maxHealthProbeTotalTimeInSeconds = 120
+)
+
+// validateWindowsNodeConfiguration validates the health probe defaults of the Windows node configuration.
+func validateWindowsNodeConfiguration(cfg *config.Config) error {
+	windowsNodes := cfg.WindowsNodes
+	if windowsNodes == nil {
+		return nil
+	}
+	if windowsNodes.HealthProbeIntervalInSeconds < 0 ||
+		windowsNodes.HealthProbeIntervalInSeconds > 0 && windowsNodes.HealthProbeIntervalInSeconds < minWindowsNodeHealthProbeIntervalInSeconds {
+		return fmt.Errorf("windowsNodes.healthProbeIntervalInSeconds must be at least %d, actual value: %d",
+			minWindowsNodeHealthProbeIntervalInSeconds, windowsNodes.HealthProbeIntervalInSeconds)
+	}
+	if windowsNodes.HealthProbeNumOfProbe < 0 ||
+		windowsNodes.HealthProbeNumOfProbe > 0 && windowsNodes.HealthProbeNumOfProbe < minWindowsNodeHealthProbeNumOfProbe {
+		return fmt.Errorf("windowsNodes.healthProbeNumOfProbe must be at least %d, actual value: %d",
+			minWindowsNodeHealthProbeNumOfProbe, windowsNodes.HealthProbeNumOfProbe)
+	}
+	interval, numOfProbe := windowsNodes.HealthProbeIntervalInSeconds, windowsNodes.HealthProbeNumOfProbe
+	if interval == 0 {
+		interval = consts.HealthProbeDefaultProbeInterval
+	}
+	if numOfProbe == 0 {
+		numOfProbe = consts.HealthProbeDefaultNumOfProbe
+	}
+	if interval*numOfProbe >= maxHealthProbeTotalTimeInSeconds {
+		return fmt.Errorf("windowsNodes.healthProbeIntervalInSeconds times windowsNodes.healthProbeNumOfProbe must be less than %d",
+			maxHealthProbeTotalTimeInSeconds)
+	}
+	return nil
+}
+
+// isWindowsNode returns true if the node is labeled as a Windows node.
+func isWindowsNode(node *v1.Node) bool {
+	return strings.EqualFold(node.Labels[v1.LabelOSStable], "windows")
+}
+
+// hasWindowsNodes returns true if the Windows node configuration is set, and any Windows node not
+// excluded from the load balancers is in the load balancer of the service. With multiple standard
+// load balancers, only the active nodes of the load balancer of the service are in it. Otherwise,
+// or if service is nil or not placed on a load balancer yet, every node is.
+func (az *Cloud) hasWindowsNodes(service *v1.Service) bool {
+	if az.WindowsNodes == nil {
+		return false
+	}
+	lbNodes := az.getLoadBalancerActiveNodesOfService(service)
+
+	az.nodeCachesLock.RLock()
+	defer az.nodeCachesLock.RUnlock()
+	for _, nodeName := range az.windowsNodeNames.UnsortedList() {
+		if az.excludeLoadBalancerNodes.Has(nodeName) {
+			continue
+		}
+		if lbNodes == nil || lbNodes.Has(nodeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLoadBalancerActiveNodesOfService returns the active nodes of the load balancer the service is
+// placed on with multiple standard load balancers, or nil if they are not known.
+func (az *Cloud) getLoadBalancerActiveNodesOfService(service *v1.Service) *utilsets.IgnoreCaseSet {
+	if service == nil || !az.UseMultipleStandardLoadBalancers() {
+		return nil
+	}
+	lbName := az.getServiceCurrentLoadBalancerName(service)
+	if lbName == "" {
+		return nil
+	}
+	for _, multiSLBConfig := range az.MultipleStandardLoadBalancerConfigurations {
+		if strings.EqualFold(multiSLBConfig.Name, lbName) {
+			if multiSLBConfig.ActiveNodes == nil {
+				return utilsets.NewString()
+			}
+			return multiSLBConfig.ActiveNodes
+		}
+	}
+	return nil
+}
+
+// getDefaultHealthProbeIntervalAndNumOfProbe returns the health probe settings of the load balancer of
+// the service used unless the service annotations set them. The settings of the probes shared by the
+// services are returned if service is nil.
+func (az *Cloud) getDefaultHealthProbeIntervalAndNumOfProbe(service *v1.Service) (int32, int32) {
+	interval, numOfProbe := consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe
+	if az.hasWindowsNodes(service) {
+		if az.WindowsNodes.HealthProbeIntervalInSeconds > 0 {
+			interval = az.WindowsNodes.HealthProbeIntervalInSeconds
+		}
+		if az.WindowsNodes.HealthProbeNumOfProbe > 0 {
+			numOfProbe = az.WindowsNodes.HealthProbeNumOfProbe
+		}
+	}
+	return interval, numOfProbe
+}
+
+// isFloatingIPDisabled returns true if the floating IPs of the load balancing rules of the service are
+// disabled, by the service annotation or by the Windows node configuration if there are Windows nodes
+// in the load balancer of the service.
+func (az *Cloud) isFloatingIPDisabled(service *v1.Service) bool {
+	if consts.IsK8sServiceDisableLoadBalancerFloatingIP(service) {
+		return true
+	}
+	return az.WindowsNodes != nil && az.WindowsNodes.DisableFloatingIP && az.hasWindowsNodes(service)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+func TestValidateWindowsNodeConfiguration(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		windowsNodes *config.WindowsNodeConfiguration
+		expectedErr  bool
+	}{
+		{
+			desc: "no configuration",
+		},
+		{
+			desc:         "longer probes",
+			windowsNodes: &config.WindowsNodeConfiguration{HealthProbeIntervalInSeconds: 15, HealthProbeNumOfProbe: 4},
+		},
+		{
+			desc:         "floating IP disabled only",
+			windowsNodes: &config.WindowsNodeConfiguration{DisableFloatingIP: true},
+		},
+		{
+			desc:         "too short interval",
+			windowsNodes: &config.WindowsNodeConfiguration{HealthProbeIntervalInSeconds: 3},
+			expectedErr:  true,
+		},
+		{
+			desc:         "too few probes",
+			windowsNodes: &config.WindowsNodeConfiguration{HealthProbeNumOfProbe: 1},
+			expectedErr:  true,
+		},
+		{
+			desc:         "too long in total with the default number of probes",
+			windowsNodes: &config.WindowsNodeConfiguration{HealthProbeIntervalInSeconds: 60},
+			expectedErr:  true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateWindowsNodeConfiguration(&config.Config{WindowsNodes: tc.windowsNodes})
+			assert.Equal(t, tc.expectedErr, err != nil, err)
+		})
+	}
+}
+
+func TestWindowsNodeDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.WindowsNodes = &config.WindowsNodeConfiguration{
+		HealthProbeIntervalInSeconds: 15,
+		HealthProbeNumOfProbe:        4,
+		DisableFloatingIP:            true,
+	}
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	annotatedService := getTestService("annotated", v1.ProtocolTCP, map[string]string{
+		consts.ServiceAnnotationLoadBalancerHealthProbeInterval: "10",
+	}, false, 80)
+	windowsNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "windows-node",
+			Labels: map[string]string{v1.LabelOSStable: "windows"},
+		},
+	}
+
+	assertDefaults := func(expectedInterval, expectedNumOfProbe int32, expectedFloatingIPDisabled bool) {
+		t.Helper()
+		interval, numOfProbe, err := az.getHealthProbeConfigProbeIntervalAndNumOfProbe(&service, 80)
+		assert.NoError(t, err)
+		assert.Equal(t, ptr.To(expectedInterval), interval)
+		assert.Equal(t, ptr.To(expectedNumOfProbe), numOfProbe)
+		assert.Equal(t, expectedFloatingIPDisabled, az.isFloatingIPDisabled(&service))
+
+		// the annotations take precedence
+		interval, numOfProbe, err = az.getHealthProbeConfigProbeIntervalAndNumOfProbe(&annotatedService, 80)
+		assert.NoError(t, err)
+		assert.Equal(t, ptr.To(int32(10)), interval)
+		assert.Equal(t, ptr.To(expectedNumOfProbe), numOfProbe)
+	}
+
+	// Linux only cluster
+	az.updateNodeCaches(nil, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "linux-node"}})
+	assertDefaults(consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe, false)
+
+	// mixed OS cluster
+	az.updateNodeCaches(nil, windowsNode)
+	assertDefaults(15, 4, true)
+	sharedProbe := az.buildClusterServiceSharedProbe()
+	assert.Equal(t, ptr.To(int32(15)), sharedProbe.Properties.IntervalInSeconds)
+	assert.Equal(t, ptr.To(int32(4)), sharedProbe.Properties.ProbeThreshold)
+
+	// the Windows nodes excluded from the load balancers are ignored
+	excludedWindowsNode := windowsNode.DeepCopy()
+	excludedWindowsNode.Labels[v1.LabelNodeExcludeBalancers] = "true"
+	az.updateNodeCaches(windowsNode, excludedWindowsNode)
+	assertDefaults(consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe, false)
+
+	az.updateNodeCaches(excludedWindowsNode, windowsNode)
+	assertDefaults(15, 4, true)
+
+	// with multiple standard load balancers, only the Windows nodes in the load balancer of the service count
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.MultipleStandardLoadBalancerConfigurations = []config.MultipleStandardLoadBalancerConfiguration{
+		{
+			Name: "linux",
+			MultipleStandardLoadBalancerConfigurationStatus: config.MultipleStandardLoadBalancerConfigurationStatus{
+				ActiveServices: utilsets.NewString("default/service", "default/annotated"),
+				ActiveNodes:    utilsets.NewString("linux-node"),
+			},
+		},
+		{
+			Name: "windows",
+			MultipleStandardLoadBalancerConfigurationStatus: config.MultipleStandardLoadBalancerConfigurationStatus{
+				ActiveServices: utilsets.NewString("default/windows"),
+				ActiveNodes:    utilsets.NewString("windows-node"),
+			},
+		},
+	}
+	assertDefaults(consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe, false)
+	windowsService := getTestService("windows", v1.ProtocolTCP, nil, false, 80)
+	assert.True(t, az.isFloatingIPDisabled(&windowsService))
+	// the probes shared by the services count all the nodes
+	sharedProbe = az.buildClusterServiceSharedProbe()
+	assert.Equal(t, ptr.To(int32(15)), sharedProbe.Properties.IntervalInSeconds)
+	// the services not placed yet count all the nodes
+	az.MultipleStandardLoadBalancerConfigurations[0].ActiveServices = utilsets.NewString()
+	assertDefaults(15, 4, true)
+	az.MultipleStandardLoadBalancerConfigurations = nil
+
+	az.updateNodeCaches(windowsNode, nil)
+	assertDefaults(consts.HealthProbeDefaultProbeInterval, consts.HealthProbeDefaultNumOfProbe, false)
+}
@@ -432,18 +432,18 @@ func getLocalServiceBackendPoolName(serviceName string, ipv6 bool) string {
 }
 
 // getBackendPoolNameForService determine the expected backend pool name
-// by checking the external traffic policy of the service.
+// by checking the external traffic policy and the zone of the service.
 func (az *Cloud) getBackendPoolNameForService(service *v1.Service, clusterName string, ipv6 bool) string {
-	if !isLocalService(service) || !az.UseMultipleStandardLoadBalancers() {
+	if !az.useServiceBackendPool(service) {
 		return getBackendPoolName(clusterName, ipv6)
 	}
 	return getLocalServiceBackendPoolName(getServiceName(service), ipv6)
 }
 
 // getBackendPoolNamesForService determine the expected backend pool names
-// by checking the external traffic policy of the service.
+// by checking the external traffic policy and the zone of the service.
 func (az *Cloud) getBackendPoolNamesForService(service *v1.Service, clusterName string) map[bool]string {
-	if !isLocalService(service) || !az.UseMultipleStandardLoadBalancers() {
+	if !az.useServiceBackendPool(service) {
 		return getBackendPoolNames(clusterName)
 	}
 	return map[bool]string{
@@ -453,9 +453,9 @@ func (az *Cloud) getBackendPoolNamesForService(service *v1.Service, clusterName
 }
 
 // getBackendPoolIDsForService determine the expected backend pool IDs
-// by checking the external traffic policy of the service.
+// by checking the external traffic policy and the zone of the service.
 func (az *Cloud) getBackendPoolIDsForService(service *v1.Service, clusterName, lbName string) map[bool]string {
-	if !isLocalService(service) || !az.UseMultipleStandardLoadBalancers() {
+	if !az.useServiceBackendPool(service) {
 		return az.getBackendPoolIDs(clusterName, lbName)
 	}
 	return map[bool]string{
@@ -552,6 +552,26 @@ func (az *Cloud) cleanupLocalServiceBackendPool(
 	return lbs, nil
 }
 
+// deleteServiceBackendPools deletes the backend pools of a local service or a service pinned to a zone from
+// its load balancer when the service is deleted. It must be called after the rules of the service referencing
+// the backend pools are removed. The load balancer deleted without frontends is skipped.
+func (az *Cloud) deleteServiceBackendPools(ctx context.Context, service *v1.Service, lb *armnetwork.LoadBalancer) error {
+	if !az.useServiceBackendPool(service) || lb == nil || lb.Properties == nil || len(lb.Properties.FrontendIPConfigurations) == 0 {
+		return nil
+	}
+	lbName := ptr.Deref(lb.Name, "")
+	serviceName := getServiceName(service)
+	for _, bp := range lb.Properties.BackendAddressPools {
+		bpName := ptr.Deref(bp.Name, "")
+		if localServiceOwnsBackendPool(serviceName, bpName) {
+			if err := az.DeleteLBBackendPool(ctx, lbName, bpName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // checkAndApplyLocalServiceBackendPoolUpdates if the IPs in the backend pool are aligned
 // with the corresponding endpointslice, and update the backend pool if necessary.
 func (az *Cloud) checkAndApplyLocalServiceBackendPoolUpdates(lb armnetwork.LoadBalancer, service *v1.Service) error {
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cloud-provider/api"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+// managementLockRetryDelay is the delay before retrying the reconciliation of a service blocked
+// by a management lock, which is only going away when the lock is removed by the operator.
+const managementLockRetryDelay = 5 * time.Minute
+
+var (
+	managementLockBlockedOperationCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "management_lock_blocked_operations_total",
+			Help:           "Number of operations blocked by the read-only or delete management locks on the Azure resources",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "locked_scope"},
+	)
+
+	registerManagementLockMetricsOnce sync.Once
+)
+
+func registerManagementLockMetrics() {
+	registerManagementLockMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(managementLockBlockedOperationCount)
+	})
+}
+
+// handleManagementLockError reports the operation of the service blocked by a management lock
+// with an event naming the locked scopes and the blocked operations metric. The error is turned
+// into a retry error with a long delay, so the service is not retried hot until the lock is removed.
+// Other errors are returned as is.
+func (az *Cloud) handleManagementLockError(service *v1.Service, operation string, err error) error {
+	scopes, locked := errutils.GetLockedScopes(err)
+	if !locked {
+		return err
+	}
+	registerManagementLockMetrics()
+	if len(scopes) == 0 {
+		managementLockBlockedOperationCount.WithLabelValues(operation, "").Inc()
+	}
+	for _, scope := range scopes {
+		managementLockBlockedOperationCount.WithLabelValues(operation, strings.ToLower(scope)).Inc()
+	}
+
+	lockedScopes := "the resources"
+	if len(scopes) > 0 {
+		lockedScopes = strings.Join(scopes, ", ")
+	}
+	msg := fmt.Sprintf("%s is blocked by the read-only or delete management lock on %s, remove the lock to proceed, retrying in %s",
+		operation, lockedScopes, managementLockRetryDelay)
+	klog.InfoS("handleManagementLockError: the operation is blocked by the management lock", "service", getServiceName(service), "operation", operation, "lockedScopes", scopes, "retryAfter", managementLockRetryDelay)
+	az.Event(service, v1.EventTypeWarning, "BlockedByManagementLock", msg)
+	return api.NewRetryError(fmt.Sprintf("%s: %v", msg, err), managementLockRetryDelay)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/cloud-provider/api"
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+func newScopeLockedError(t *testing.T, lockedScopes ...string) error {
+	quoted := make([]string, len(lockedScopes))
+	for i, scope := range lockedScopes {
+		quoted[i] = "'" + scope + "'"
+	}
+	body := `{"error":{"code":"ScopeLocked","message":"The scope '/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/kubernetes' ` +
+		`cannot perform write operation because following scope(s) are locked: ` + strings.Join(quoted, ",") + `. Please remove the lock and try again."}}`
+	req, err := http.NewRequest(http.MethodPut, "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/kubernetes", nil)
+	assert.NoError(t, err)
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: http.StatusConflict,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     http.Header{},
+		Request:    req,
+	})
+}
+
+func TestGetLockedScopes(t *testing.T) {
+	scopes, locked := errutils.GetLockedScopes(newScopeLockedError(t, "/subscriptions/sub/resourceGroups/rg"))
+	assert.True(t, locked)
+	assert.Equal(t, []string{"/subscriptions/sub/resourceGroups/rg"}, scopes)
+
+	scopes, locked = errutils.GetLockedScopes(newScopeLockedError(t, "/subscriptions/sub/resourceGroups/rg", "/subscriptions/sub"))
+	assert.True(t, locked)
+	assert.Equal(t, []string{"/subscriptions/sub/resourceGroups/rg", "/subscriptions/sub"}, scopes)
+
+	_, locked = errutils.GetLockedScopes(errors.New("ScopeLocked"))
+	assert.False(t, locked)
+}
+
+func TestHandleManagementLockError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	recorder := record.NewFakeRecorder(10)
+	az.eventRecorder = recorder
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+
+	otherErr := errors.New("failed")
+	assert.Equal(t, otherErr, az.handleManagementLockError(&service, "EnsureLoadBalancer", otherErr))
+	assert.Empty(t, recorder.Events)
+
+	err := az.handleManagementLockError(&service, "EnsureLoadBalancer", errutils.WithARMRequestIDs(newScopeLockedError(t, "/subscriptions/sub/resourceGroups/rg")))
+	var retryErr *api.RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, managementLockRetryDelay, retryErr.RetryAfter())
+	event := <-recorder.Events
+	assert.Contains(t, event, "BlockedByManagementLock")
+	assert.Contains(t, event, "/subscriptions/sub/resourceGroups/rg")
+
+	count, err := testutil.GetCounterMetricValue(managementLockBlockedOperationCount.WithLabelValues("EnsureLoadBalancer", "/subscriptions/sub/resourcegroups/rg"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), count)
+}
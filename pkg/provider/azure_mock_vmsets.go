@@ -531,6 +531,45 @@ func (c *MockVMSetGetInstanceTypeByNodeNameCall) DoAndReturn(f func(context.Cont
 	return c
 }
 
+// GetInterfacesByNodeName mocks base method.
+func (m *MockVMSet) GetInterfacesByNodeName(ctx context.Context, nodeName string) ([]*v60.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterfacesByNodeName", ctx, nodeName)
+	ret0, _ := ret[0].([]*v60.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterfacesByNodeName indicates an expected call of GetInterfacesByNodeName.
+func (mr *MockVMSetMockRecorder) GetInterfacesByNodeName(ctx, nodeName any) *MockVMSetGetInterfacesByNodeNameCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterfacesByNodeName", reflect.TypeOf((*MockVMSet)(nil).GetInterfacesByNodeName), ctx, nodeName)
+	return &MockVMSetGetInterfacesByNodeNameCall{Call: call}
+}
+
+// MockVMSetGetInterfacesByNodeNameCall wrap *gomock.Call
+type MockVMSetGetInterfacesByNodeNameCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockVMSetGetInterfacesByNodeNameCall) Return(arg0 []*v60.Interface, arg1 error) *MockVMSetGetInterfacesByNodeNameCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockVMSetGetInterfacesByNodeNameCall) Do(f func(context.Context, string) ([]*v60.Interface, error)) *MockVMSetGetInterfacesByNodeNameCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockVMSetGetInterfacesByNodeNameCall) DoAndReturn(f func(context.Context, string) ([]*v60.Interface, error)) *MockVMSetGetInterfacesByNodeNameCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetNodeCIDRMasksByProviderID mocks base method.
 func (m *MockVMSet) GetNodeCIDRMasksByProviderID(ctx context.Context, providerID string) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,211 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// getNodeAddressIPFamilies returns the IP families of NodeAddressIPFamilies in the order of preference.
+func (az *Cloud) getNodeAddressIPFamilies() []v1.IPFamily {
+	var families []v1.IPFamily
+	for _, family := range strings.Split(az.NodeAddressIPFamilies, ",") {
+		switch {
+		case strings.EqualFold(strings.TrimSpace(family), string(v1.IPv4Protocol)):
+			families = append(families, v1.IPv4Protocol)
+		case strings.EqualFold(strings.TrimSpace(family), string(v1.IPv6Protocol)):
+			families = append(families, v1.IPv6Protocol)
+		}
+	}
+	return families
+}
+
+// validateNodeAddressIPFamilies returns an error if NodeAddressIPFamilies has unknown or duplicated families.
+func validateNodeAddressIPFamilies(nodeAddressIPFamilies string) error {
+	if strings.TrimSpace(nodeAddressIPFamilies) == "" {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, family := range strings.Split(nodeAddressIPFamilies, ",") {
+		family = strings.ToLower(strings.TrimSpace(family))
+		if family != strings.ToLower(string(v1.IPv4Protocol)) && family != strings.ToLower(string(v1.IPv6Protocol)) {
+			return fmt.Errorf("nodeAddressIPFamilies %s is not supported, supported values are %s and %s", nodeAddressIPFamilies, v1.IPv4Protocol, v1.IPv6Protocol)
+		}
+		if seen[family] {
+			return fmt.Errorf("nodeAddressIPFamilies %s has duplicated families", nodeAddressIPFamilies)
+		}
+		seen[family] = true
+	}
+	return nil
+}
+
+// isNodeAddressDualStack returns true if the node addresses of both IP families are reported.
+func (az *Cloud) isNodeAddressDualStack() bool {
+	return len(az.getNodeAddressIPFamilies()) == 2
+}
+
+// getDualStackNodeAddresses returns the private and public IPs of all IP configurations of the
+// primary NIC of the node, so the IPv6 addresses are reported along with the IPv4 ones.
+func (az *Cloud) getDualStackNodeAddresses(ctx context.Context, nodeName types.NodeName) ([]v1.NodeAddress, error) {
+	nic, err := az.VMSet.GetPrimaryInterface(ctx, string(nodeName))
+	if err != nil {
+		return nil, err
+	}
+	if nic.Properties == nil || len(nic.Properties.IPConfigurations) == 0 {
+		return nil, fmt.Errorf("nic.Properties.IPConfigurations for nic (nicname=%q) is nil", ptr.Deref(nic.Name, ""))
+	}
+
+	var internalIPs, externalIPs []v1.NodeAddress
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PrivateIPAddress == nil {
+			continue
+		}
+		internalIPs = append(internalIPs, v1.NodeAddress{Type: v1.NodeInternalIP, Address: *ipConfig.Properties.PrivateIPAddress})
+
+		if ipConfig.Properties.PublicIPAddress == nil || ipConfig.Properties.PublicIPAddress.ID == nil {
+			continue
+		}
+		publicIP, err := az.getNodePublicIPAddress(ctx, *ipConfig.Properties.PublicIPAddress.ID)
+		if err != nil {
+			return nil, err
+		}
+		if publicIP != "" {
+			externalIPs = append(externalIPs, v1.NodeAddress{Type: v1.NodeExternalIP, Address: publicIP})
+		}
+	}
+	if len(internalIPs) == 0 {
+		return nil, fmt.Errorf("no private IP is found in the primary nic of node %s", nodeName)
+	}
+
+	addresses := make([]v1.NodeAddress, 0, len(internalIPs)+len(externalIPs)+1)
+	addresses = append(addresses, internalIPs...)
+	addresses = append(addresses, v1.NodeAddress{Type: v1.NodeHostName, Address: string(nodeName)})
+	return append(addresses, externalIPs...), nil
+}
+
+// getNodePublicIPAddress returns the address of the public IP of a node IP configuration. The
+// public IPs of the uniform scale set VMs are read by the scale set APIs.
+func (az *Cloud) getNodePublicIPAddress(ctx context.Context, pipID string) (string, error) {
+	var (
+		pip       *armnetwork.PublicIPAddress
+		existsPip bool
+		err       error
+	)
+	if matches := vmssPIPConfigurationRE.FindStringSubmatch(pipID); len(matches) == 7 {
+		pip, existsPip, err = az.getVMSSPublicIPAddress(matches[1], matches[2], matches[3], matches[4], matches[5], matches[6])
+	} else {
+		var pipName string
+		pipName, err = getLastSegment(pipID, "/")
+		if err != nil {
+			return "", fmt.Errorf("failed to get the name of public IP %q: %w", pipID, err)
+		}
+		pip, existsPip, err = az.getPublicIPAddress(ctx, az.ResourceGroup, pipName, azcache.CacheReadTypeDefault)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !existsPip || pip == nil || pip.Properties == nil || pip.Properties.IPAddress == nil {
+		klog.V(4).InfoS("getNodePublicIPAddress: the public IP has no address", "publicIPID", pipID)
+		return "", nil
+	}
+	return *pip.Properties.IPAddress, nil
+}
+
+// applyNodeAddressIPFamilies drops the IPs of the families not in NodeAddressIPFamilies and sorts
+// the InternalIPs and the ExternalIPs by the order of the families. The positions of the address
+// types are kept. The addresses are returned as-is if NodeAddressIPFamilies is not set.
+func (az *Cloud) applyNodeAddressIPFamilies(addresses []v1.NodeAddress) []v1.NodeAddress {
+	families := az.getNodeAddressIPFamilies()
+	if len(families) == 0 {
+		return addresses
+	}
+	rank := make(map[v1.IPFamily]int, len(families))
+	for i, family := range families {
+		rank[family] = i
+	}
+	familyOf := func(address string) v1.IPFamily {
+		if utilnet.IsIPv6String(address) {
+			return v1.IPv6Protocol
+		}
+		return v1.IPv4Protocol
+	}
+
+	result := make([]v1.NodeAddress, 0, len(addresses))
+	for _, address := range addresses {
+		if address.Type == v1.NodeInternalIP || address.Type == v1.NodeExternalIP {
+			if _, ok := rank[familyOf(address.Address)]; !ok {
+				continue
+			}
+		}
+		result = append(result, address)
+	}
+
+	for _, addressType := range []v1.NodeAddressType{v1.NodeInternalIP, v1.NodeExternalIP} {
+		var positions []int
+		var ips []v1.NodeAddress
+		for i, address := range result {
+			if address.Type == addressType {
+				positions = append(positions, i)
+				ips = append(ips, address)
+			}
+		}
+		sort.SliceStable(ips, func(i, j int) bool {
+			return rank[familyOf(ips[i].Address)] < rank[familyOf(ips[j].Address)]
+		})
+		for i, position := range positions {
+			result[position] = ips[i]
+		}
+	}
+	return result
+}
+
+// applyNodeAddressOptions applies NodeAddressIPFamilies, ExcludeNodePublicIPs and NodeAddressOrder
+// to the node addresses.
+func (az *Cloud) applyNodeAddressOptions(addresses []v1.NodeAddress) []v1.NodeAddress {
+	addresses = az.applyNodeAddressIPFamilies(addresses)
+
+	if az.ExcludeNodePublicIPs {
+		result := make([]v1.NodeAddress, 0, len(addresses))
+		for _, address := range addresses {
+			if address.Type != v1.NodeExternalIP {
+				result = append(result, address)
+			}
+		}
+		addresses = result
+	}
+
+	if strings.EqualFold(az.NodeAddressOrder, consts.NodeAddressOrderExternalIPFirst) {
+		addresses = append([]v1.NodeAddress(nil), addresses...)
+		sort.SliceStable(addresses, func(i, j int) bool {
+			return addresses[i].Type == v1.NodeExternalIP && addresses[j].Type != v1.NodeExternalIP
+		})
+	}
+	return addresses
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestApplyNodeAddressIPFamilies(t *testing.T) {
+	addresses := []v1.NodeAddress{
+		{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+		{Type: v1.NodeInternalIP, Address: "fd00::4"},
+		{Type: v1.NodeHostName, Address: "node0"},
+		{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+		{Type: v1.NodeExternalIP, Address: "2001::4"},
+	}
+
+	for _, tc := range []struct {
+		desc                  string
+		nodeAddressIPFamilies string
+		expected              []v1.NodeAddress
+	}{
+		{
+			desc:     "should return the addresses as-is if the families are not set",
+			expected: addresses,
+		},
+		{
+			desc:                  "should sort the addresses by the families",
+			nodeAddressIPFamilies: "IPv6,IPv4",
+			expected: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "fd00::4"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+				{Type: v1.NodeHostName, Address: "node0"},
+				{Type: v1.NodeExternalIP, Address: "2001::4"},
+				{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+			},
+		},
+		{
+			desc:                  "should drop the addresses of the families not set",
+			nodeAddressIPFamilies: "ipv4",
+			expected: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+				{Type: v1.NodeHostName, Address: "node0"},
+				{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := &Cloud{}
+			az.NodeAddressIPFamilies = tc.nodeAddressIPFamilies
+			assert.Equal(t, tc.expected, az.applyNodeAddressIPFamilies(addresses))
+		})
+	}
+}
+
+func TestApplyNodeAddressOptions(t *testing.T) {
+	addresses := []v1.NodeAddress{
+		{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+		{Type: v1.NodeHostName, Address: "node0"},
+		{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+	}
+
+	for _, tc := range []struct {
+		desc                 string
+		nodeAddressOrder     string
+		excludeNodePublicIPs bool
+		expected             []v1.NodeAddress
+	}{
+		{
+			desc:             "should report the InternalIPs first by default",
+			nodeAddressOrder: consts.NodeAddressOrderInternalIPFirst,
+			expected:         addresses,
+		},
+		{
+			desc:             "should report the ExternalIPs first",
+			nodeAddressOrder: consts.NodeAddressOrderExternalIPFirst,
+			expected: []v1.NodeAddress{
+				{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+				{Type: v1.NodeHostName, Address: "node0"},
+			},
+		},
+		{
+			desc:                 "should drop the ExternalIPs",
+			nodeAddressOrder:     consts.NodeAddressOrderExternalIPFirst,
+			excludeNodePublicIPs: true,
+			expected: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+				{Type: v1.NodeHostName, Address: "node0"},
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := &Cloud{}
+			az.NodeAddressOrder = tc.nodeAddressOrder
+			az.ExcludeNodePublicIPs = tc.excludeNodePublicIPs
+			assert.Equal(t, tc.expected, az.applyNodeAddressOptions(addresses))
+		})
+	}
+}
+
+func TestValidateNodeAddressIPFamilies(t *testing.T) {
+	assert.NoError(t, validateNodeAddressIPFamilies(""))
+	assert.NoError(t, validateNodeAddressIPFamilies("IPv6, IPv4"))
+	assert.Error(t, validateNodeAddressIPFamilies("IPv4,IPv5"))
+	assert.Error(t, validateNodeAddressIPFamilies("IPv4,ipv4"))
+}
+
+func TestGetDualStackNodeAddresses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.NodeAddressIPFamilies = "IPv6,IPv4"
+
+	mockVMSet := NewMockVMSet(ctrl)
+	mockVMSet.EXPECT().GetPrimaryInterface(gomock.Any(), "vm1").Return(&armnetwork.Interface{
+		Name: ptr.To("nic"),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:          ptr.To(true),
+						PrivateIPAddress: ptr.To("10.0.0.4"),
+						PublicIPAddress:  &armnetwork.PublicIPAddress{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip-v4")},
+					},
+				},
+				{
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						PrivateIPAddress:        ptr.To("fd00::4"),
+						PrivateIPAddressVersion: ptr.To(armnetwork.IPVersionIPv6),
+						PublicIPAddress:         &armnetwork.PublicIPAddress{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip-v6")},
+					},
+				},
+			},
+		},
+	}, nil)
+	az.VMSet = mockVMSet
+
+	pipClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	pipClient.EXPECT().List(gomock.Any(), "rg").Return([]*armnetwork.PublicIPAddress{
+		{Name: ptr.To("pip-v4"), Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("1.2.3.4")}},
+		{Name: ptr.To("pip-v6"), Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("2001::4")}},
+	}, nil)
+
+	addresses, err := az.addressGetter(context.TODO(), "vm1")
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.NodeAddress{
+		{Type: v1.NodeInternalIP, Address: "fd00::4"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.4"},
+		{Type: v1.NodeHostName, Address: "vm1"},
+		{Type: v1.NodeExternalIP, Address: "2001::4"},
+		{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+	}, addresses)
+}
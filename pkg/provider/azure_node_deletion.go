@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	cloudnodeutil "k8s.io/cloud-provider/node/helpers"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	informerutil "sigs.k8s.io/cloud-provider-azure/pkg/util/informer"
+)
+
+// podNodeNameIndex is the index of the pods by spec.nodeName.
+const podNodeNameIndex = "spec.nodeName"
+
+// setUpPodInformer indexes the pods by their nodes, so the nodes of deleted VMs are checked for
+// running pods without listing the pods from the API server. Only the fields checked by isNodeDrained
+// are kept in the cache.
+func (az *Cloud) setUpPodInformer(informerFactory informers.SharedInformerFactory) {
+	logger := log.Background().WithName("setUpPodInformer")
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	if err := podInformer.SetTransform(informerutil.StripPod); err != nil {
+		logger.Error(err, "Failed to strip the pods")
+		return
+	}
+	if err := podInformer.AddIndexers(cache.Indexers{
+		podNodeNameIndex: func(obj interface{}) ([]string, error) {
+			pod, ok := obj.(*v1.Pod)
+			if !ok || pod.Spec.NodeName == "" {
+				return nil, nil
+			}
+			return []string{pod.Spec.NodeName}, nil
+		},
+	}); err != nil {
+		logger.Error(err, "Failed to index the pods by node")
+		return
+	}
+	az.podIndexer = podInformer.GetIndexer()
+}
+
+// DisablePodInformer keeps the pods from being cached, so the pods are listed from the API server
+// when the nodes of deleted VMs are drained. It must be called before SetInformers.
+func (az *Cloud) DisablePodInformer() {
+	az.podInformerDisabled = true
+}
+
+// delayNodeDeletion cordons the node of a deleted VM and taints it with the NoExecute VM deleted taint,
+// so its pods are evicted before the node is removed. It returns true, which keeps the node, until the
+// pods are evicted or NodeDeletionGracePeriodInSeconds has passed since the node was tainted.
+func (az *Cloud) delayNodeDeletion(ctx context.Context, node *v1.Node) (bool, error) {
+	gracePeriod := time.Duration(az.NodeDeletionGracePeriodInSeconds) * time.Second
+	if gracePeriod <= 0 || az.KubeClient == nil {
+		return false, nil
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("delayNodeDeletion").WithValues("node", node.Name)
+	taint := getVMDeletedTaint(node)
+	if taint == nil {
+		logger.V(2).Info("The VM of the node is deleted, draining the node before deleting it", "gracePeriod", gracePeriod)
+		if !node.Spec.Unschedulable {
+			if err := az.cordonNode(ctx, node.Name); err != nil {
+				return false, err
+			}
+		}
+		if err := cloudnodeutil.AddOrUpdateTaintOnNode(az.KubeClient, node.Name, &v1.Taint{
+			Key:       consts.VMDeletedTaintKey,
+			Effect:    v1.TaintEffectNoExecute,
+			TimeAdded: &metav1.Time{Time: time.Now()},
+		}); err != nil {
+			return false, fmt.Errorf("failed to taint node %q: %w", node.Name, err)
+		}
+		az.Event(node, v1.EventTypeWarning, "VMDeleted",
+			fmt.Sprintf("The VM of the node is deleted, draining the node for %s before deleting it", gracePeriod))
+		return true, nil
+	}
+
+	if taint.TimeAdded != nil && time.Since(taint.TimeAdded.Time) >= gracePeriod {
+		logger.V(2).Info("The grace period of the node has passed, deleting the node")
+		return false, nil
+	}
+	drained, err := az.isNodeDrained(ctx, node.Name)
+	if err != nil {
+		return false, err
+	}
+	if drained {
+		logger.V(2).Info("The pods of the node are evicted, deleting the node")
+		return false, nil
+	}
+	return true, nil
+}
+
+// cancelNodeDeletion removes the VM deleted taint from the node whose VM exists again, e.g. a VM
+// reported as deleted by the deleted deallocatedVMPolicy and started again, and uncordons the node
+// if delayNodeDeletion cordoned it.
+func (az *Cloud) cancelNodeDeletion(ctx context.Context, node *v1.Node) error {
+	taint := getVMDeletedTaint(node)
+	if taint == nil || az.KubeClient == nil {
+		return nil
+	}
+
+	log.FromContextOrBackground(ctx).WithName("cancelNodeDeletion").V(2).Info("The VM of the node exists again, cancelling the deletion of the node", "node", node.Name)
+	if err := cloudnodeutil.RemoveTaintOffNode(az.KubeClient, node.Name, node, taint); err != nil {
+		return fmt.Errorf("failed to remove the taint %s from node %q: %w", consts.VMDeletedTaintKey, node.Name, err)
+	}
+	if _, cordoned := node.Annotations[consts.VMDeletedCordonedAnnotationKey]; cordoned {
+		patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}},"spec":{"unschedulable":false}}`, consts.VMDeletedCordonedAnnotationKey))
+		if _, err := az.KubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to uncordon node %q: %w", node.Name, err)
+		}
+	}
+	az.Event(node, v1.EventTypeNormal, "VMExists", "The VM of the node exists again, the node is not deleted")
+	return nil
+}
+
+func getVMDeletedTaint(node *v1.Node) *v1.Taint {
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].Key == consts.VMDeletedTaintKey {
+			return &node.Spec.Taints[i]
+		}
+	}
+	return nil
+}
+
+// cordonNode marks the node unschedulable, and annotates it so it is uncordoned by cancelNodeDeletion.
+func (az *Cloud) cordonNode(ctx context.Context, nodeName string) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}},"spec":{"unschedulable":true}}`, consts.VMDeletedCordonedAnnotationKey))
+	if _, err := az.KubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to cordon node %q: %w", nodeName, err)
+	}
+	return nil
+}
+
+// isNodeDrained returns true if there are no pods to evict on the node. The pods being deleted,
+// terminated pods, mirror pods and DaemonSet pods are ignored, because the DaemonSet pods and
+// mirror pods are not evicted and stay until the node is removed. The pods are read from the pod
+// indexer if the informers are set.
+func (az *Cloud) isNodeDrained(ctx context.Context, nodeName string) (bool, error) {
+	var pods []*v1.Pod
+	if az.podIndexer != nil {
+		objs, err := az.podIndexer.ByIndex(podNodeNameIndex, nodeName)
+		if err != nil {
+			return false, fmt.Errorf("failed to get the pods of node %q: %w", nodeName, err)
+		}
+		for _, obj := range objs {
+			if pod, ok := obj.(*v1.Pod); ok {
+				pods = append(pods, pod)
+			}
+		}
+	} else {
+		// ResourceVersion 0 lets the API server list the pods from its cache.
+		podList, err := az.KubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+			ResourceVersion: "0",
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to list the pods of node %q: %w", nodeName, err)
+		}
+		for i := range podList.Items {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if _, isMirrorPod := pod.Annotations[v1.MirrorPodAnnotationKey]; isMirrorPod {
+			continue
+		}
+		if controller := metav1.GetControllerOf(pod); controller != nil && controller.Kind == "DaemonSet" {
+			continue
+		}
+		return false, nil
+	}
+	return true, nil
+}
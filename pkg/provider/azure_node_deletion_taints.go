@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+// getNodeDeletionTaintKeys returns the keys of the taints marking the nodes going to be deleted.
+func (az *Cloud) getNodeDeletionTaintKeys() []string {
+	if az.NodeDeletionTaintKeys == nil {
+		return []string{consts.ClusterAutoscalerToBeDeletedTaint}
+	}
+	return az.NodeDeletionTaintKeys
+}
+
+// IsNodeMarkedForDeletion returns true if the node has any of the node deletion taints.
+func (az *Cloud) IsNodeMarkedForDeletion(node *v1.Node) bool {
+	for _, key := range az.getNodeDeletionTaintKeys() {
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNodeNameMarkedForDeletion returns true if the node with the name has any of the node deletion taints.
+func (az *Cloud) isNodeNameMarkedForDeletion(nodeName string) bool {
+	az.nodeCachesLock.RLock()
+	defer az.nodeCachesLock.RUnlock()
+	return az.nodesMarkedForDeletion.Has(nodeName)
+}
+
+// getLoadBalancerNodes returns the nodes of the load balancers, as the service controller passes them.
+func (az *Cloud) getLoadBalancerNodes(nodes []*v1.Node) []*v1.Node {
+	var lbNodes []*v1.Node
+	for _, node := range nodes {
+		if _, excluded := node.Labels[v1.LabelNodeExcludeBalancers]; excluded ||
+			!node.DeletionTimestamp.IsZero() || az.IsNodeMarkedForDeletion(node) {
+			continue
+		}
+		lbNodes = append(lbNodes, node)
+	}
+	return lbNodes
+}
+
+// isServiceOfNodes returns true if the backend pools of the load balancer of the service may contain the nodes
+// marked for deletion or the nodes unmarked.
+func (az *Cloud) isServiceOfNodes(service *v1.Service, marked, unmarked sets.Set[string]) bool {
+	hasAny := func(s *utilsets.IgnoreCaseSet, nodeNames sets.Set[string]) bool {
+		for nodeName := range nodeNames {
+			if s.Has(nodeName) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if az.UseLocalServiceBackendPools() && isLocalService(service) {
+		// the backend pools of the local services only contain the nodes of their endpoints
+		endpointNodes := az.getLocalServiceEndpointsNodeNames(service)
+		return endpointNodes == nil || hasAny(endpointNodes, marked.Union(unmarked))
+	}
+	if az.UseMultipleStandardLoadBalancers() && unmarked.Len() == 0 {
+		// the marked nodes are active on the load balancers they are taken out of, while the unmarked nodes
+		// may be put on any load balancer
+		lbName := az.getServiceCurrentLoadBalancerName(service)
+		return lbName == "" || hasAny(az.getActiveNodesByLoadBalancerName(lbName), marked)
+	}
+	// the backend pools of the other services contain all the nodes
+	return true
+}
+
+// ReconcileNodesMarkedForDeletion removes the nodes marked for deletion since the last reconciliation from the
+// backend pools of the load balancers, and puts the nodes unmarked since then back, like the node sync of the
+// service controller which isn't triggered by the taints. Only the services whose backend pools may contain
+// these nodes are updated. The routes of the marked nodes are deleted if deleteRoutes is true, and CreateRoute
+// fails for them until the nodes are unmarked.
+func (az *Cloud) ReconcileNodesMarkedForDeletion(ctx context.Context, clusterName string, nodes []*v1.Node, deleteRoutes bool) error {
+	if az.serviceLister == nil {
+		return fmt.Errorf("ReconcileNodesMarkedForDeletion: the service informer is not set")
+	}
+
+	markedNodes := sets.New[string]()
+	for _, node := range nodes {
+		if az.IsNodeMarkedForDeletion(node) {
+			markedNodes.Insert(node.Name)
+		}
+	}
+	marked := markedNodes.Difference(az.reconciledNodesMarkedForDeletion)
+	unmarked := az.reconciledNodesMarkedForDeletion.Difference(markedNodes)
+
+	var errs []error
+	if marked.Len() > 0 || unmarked.Len() > 0 {
+		lbNodes := az.getLoadBalancerNodes(nodes)
+		services, err := az.serviceLister.List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			if service.Spec.Type != v1.ServiceTypeLoadBalancer || !az.isServiceOfLoadBalancerClasses(service) ||
+				!az.isServiceOfNodes(service, marked, unmarked) {
+				continue
+			}
+			if err := az.UpdateLoadBalancer(ctx, clusterName, service, lbNodes); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update the load balancer of service %s: %w", getServiceName(service), err))
+			}
+		}
+		// the nodes are reconciled again on the retry if any load balancer failed to update
+		if len(errs) == 0 {
+			az.reconciledNodesMarkedForDeletion = markedNodes
+		}
+	}
+
+	if deleteRoutes {
+		routes, err := az.ListRoutes(ctx, clusterName)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, route := range routes {
+			if !az.isNodeNameMarkedForDeletion(string(route.TargetNode)) {
+				continue
+			}
+			klog.V(2).InfoS("ReconcileNodesMarkedForDeletion: deleting the route of the node marked for deletion", "route", route.Name, "node", route.TargetNode)
+			if err := az.DeleteRoute(ctx, clusterName, route); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete the route of node %s: %w", route.TargetNode, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
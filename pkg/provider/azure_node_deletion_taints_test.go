@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	cloudprovider "k8s.io/cloud-provider"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+func TestNodesMarkedForDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.nodeInformerSynced = func() bool { return true }
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	markedNode := node.DeepCopy()
+	markedNode.Spec.Taints = []v1.Taint{{Key: consts.ClusterAutoscalerToBeDeletedTaint, Effect: v1.TaintEffectNoSchedule}}
+
+	az.updateNodeCaches(nil, node)
+	excluded, err := az.ShouldNodeExcludedFromLoadBalancer("node")
+	assert.NoError(t, err)
+	assert.False(t, excluded)
+
+	az.updateNodeCaches(node, markedNode)
+	assert.True(t, az.IsNodeMarkedForDeletion(markedNode))
+	excluded, err = az.ShouldNodeExcludedFromLoadBalancer("node")
+	assert.NoError(t, err)
+	assert.True(t, excluded)
+	// the route is not created and is retried by the route controller, the route table client is not called
+	assert.Error(t, az.CreateRoute(context.Background(), "cluster", "", &cloudprovider.Route{TargetNode: "node", DestinationCIDR: "10.244.0.0/24"}))
+
+	az.updateNodeCaches(markedNode, node)
+	excluded, err = az.ShouldNodeExcludedFromLoadBalancer("node")
+	assert.NoError(t, err)
+	assert.False(t, excluded)
+
+	// the taints are configurable, and an empty list disables them
+	az.NodeDeletionTaintKeys = []string{}
+	assert.False(t, az.IsNodeMarkedForDeletion(markedNode))
+	az.NodeDeletionTaintKeys = []string{"example.com/draining"}
+	assert.False(t, az.IsNodeMarkedForDeletion(markedNode))
+	markedNode.Spec.Taints = append(markedNode.Spec.Taints, v1.Taint{Key: "example.com/draining", Effect: v1.TaintEffectNoSchedule})
+	assert.True(t, az.IsNodeMarkedForDeletion(markedNode))
+}
+
+func TestReconcileNodesMarkedForDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	nodes := []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node"}}}
+
+	// the services other than the load balancers are not updated, the load balancer client is not called
+	clusterIPService := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	clusterIPService.Spec.Type = v1.ServiceTypeClusterIP
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(&clusterIPService), 0)
+	serviceInformer := informerFactory.Core().V1().Services()
+	assert.NoError(t, serviceInformer.Informer().GetIndexer().Add(&clusterIPService))
+	az.serviceLister = serviceInformer.Lister()
+	assert.NoError(t, az.ReconcileNodesMarkedForDeletion(context.Background(), "cluster", nodes, false))
+	assert.Empty(t, az.reconciledNodesMarkedForDeletion)
+
+	nodes[0].Spec.Taints = []v1.Taint{{Key: consts.ClusterAutoscalerToBeDeletedTaint, Effect: v1.TaintEffectNoSchedule}}
+	assert.NoError(t, az.ReconcileNodesMarkedForDeletion(context.Background(), "cluster", nodes, false))
+	assert.Equal(t, sets.New("node"), az.reconciledNodesMarkedForDeletion)
+
+	nodes[0].Spec.Taints = nil
+	assert.NoError(t, az.ReconcileNodesMarkedForDeletion(context.Background(), "cluster", nodes, false))
+	assert.Empty(t, az.reconciledNodesMarkedForDeletion)
+}
+
+func TestIsServiceOfNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	service := getTestService("service", v1.ProtocolTCP, nil, false, 80)
+	none := sets.New[string]()
+
+	// the backend pool of the single load balancer contains all the nodes
+	assert.True(t, az.isServiceOfNodes(&service, sets.New("node1"), none))
+
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.MultipleStandardLoadBalancerConfigurations = []config.MultipleStandardLoadBalancerConfiguration{
+		{
+			Name: "kubernetes",
+			MultipleStandardLoadBalancerConfigurationStatus: config.MultipleStandardLoadBalancerConfigurationStatus{
+				ActiveServices: utilsets.NewString("default/service"),
+				ActiveNodes:    utilsets.NewString("node1"),
+			},
+		},
+		{
+			Name: "lb2",
+			MultipleStandardLoadBalancerConfigurationStatus: config.MultipleStandardLoadBalancerConfigurationStatus{
+				ActiveNodes: utilsets.NewString("node2"),
+			},
+		},
+	}
+	// the marked nodes are taken out of the load balancers they are active on
+	assert.True(t, az.isServiceOfNodes(&service, sets.New("node1"), none))
+	assert.False(t, az.isServiceOfNodes(&service, sets.New("node2"), none))
+	// the unmarked nodes may be put on any load balancer
+	assert.True(t, az.isServiceOfNodes(&service, none, sets.New("node2")))
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestDelayNodeDeletion(t *testing.T) {
+	vmDeletedTaint := func(timeAdded time.Time) []v1.Taint {
+		return []v1.Taint{{Key: consts.VMDeletedTaintKey, Effect: v1.TaintEffectNoExecute, TimeAdded: &metav1.Time{Time: timeAdded}}}
+	}
+	runningPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: "node0"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	mirrorPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-pod", Namespace: "kube-system", Annotations: map[string]string{v1.MirrorPodAnnotationKey: "hash"}},
+		Spec:       v1.PodSpec{NodeName: "node0"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	succeededPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "succeeded-pod", Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: "node0"},
+		Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+	}
+	deletingPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "deleting-pod", Namespace: "default", DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{"finalizer"}},
+		Spec:       v1.PodSpec{NodeName: "node0"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	daemonSetPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "daemonset-pod",
+			Namespace:       "kube-system",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", Controller: ptr.To(true)}},
+		},
+		Spec:   v1.PodSpec{NodeName: "node0"},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+
+	for _, tc := range []struct {
+		desc             string
+		gracePeriod      int
+		unschedulable    bool
+		taints           []v1.Taint
+		pods             []runtime.Object
+		usePodIndexer    bool
+		expected         bool
+		expectedCordoned bool
+		expectedTainted  bool
+	}{
+		{
+			desc:     "should not delay the deletion without the grace period",
+			expected: false,
+		},
+		{
+			desc:             "should cordon and taint the node",
+			gracePeriod:      600,
+			pods:             []runtime.Object{runningPod},
+			expected:         true,
+			expectedCordoned: true,
+			expectedTainted:  true,
+		},
+		{
+			desc:             "should not mark the node cordoned by others to be uncordoned",
+			gracePeriod:      600,
+			unschedulable:    true,
+			pods:             []runtime.Object{runningPod},
+			expected:         true,
+			expectedCordoned: true,
+			expectedTainted:  true,
+		},
+		{
+			desc:            "should keep the node until the pods are evicted",
+			gracePeriod:     600,
+			taints:          vmDeletedTaint(time.Now()),
+			pods:            []runtime.Object{runningPod},
+			expected:        true,
+			expectedTainted: true,
+		},
+		{
+			desc:            "should delete the node after the pods are evicted",
+			gracePeriod:     600,
+			taints:          vmDeletedTaint(time.Now()),
+			pods:            []runtime.Object{mirrorPod, succeededPod, deletingPod, daemonSetPod},
+			expected:        false,
+			expectedTainted: true,
+		},
+		{
+			desc:            "should read the pods from the pod indexer",
+			gracePeriod:     600,
+			taints:          vmDeletedTaint(time.Now()),
+			pods:            []runtime.Object{runningPod},
+			usePodIndexer:   true,
+			expected:        true,
+			expectedTainted: true,
+		},
+		{
+			desc:            "should delete the node after the pods are evicted from the pod indexer",
+			gracePeriod:     600,
+			taints:          vmDeletedTaint(time.Now()),
+			pods:            []runtime.Object{mirrorPod, deletingPod, daemonSetPod},
+			usePodIndexer:   true,
+			expected:        false,
+			expectedTainted: true,
+		},
+		{
+			desc:            "should delete the node after the grace period",
+			gracePeriod:     600,
+			taints:          vmDeletedTaint(time.Now().Add(-time.Hour)),
+			pods:            []runtime.Object{runningPod},
+			expected:        false,
+			expectedTainted: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			az.NodeDeletionGracePeriodInSeconds = tc.gracePeriod
+
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+				Spec:       v1.NodeSpec{Taints: tc.taints, Unschedulable: tc.unschedulable},
+			}
+			az.KubeClient = fake.NewSimpleClientset(append(tc.pods, node)...)
+			if tc.usePodIndexer {
+				informerFactory := informers.NewSharedInformerFactory(az.KubeClient, 0)
+				az.setUpPodInformer(informerFactory)
+				informerFactory.Start(wait.NeverStop)
+				informerFactory.WaitForCacheSync(wait.NeverStop)
+				// the API server is not called
+				az.KubeClient.(*fake.Clientset).PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("unexpected list")
+				})
+			}
+
+			exists, err := az.delayNodeDeletion(context.TODO(), node)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, exists)
+
+			updatedNode, err := az.KubeClient.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCordoned, updatedNode.Spec.Unschedulable)
+			_, markedCordoned := updatedNode.Annotations[consts.VMDeletedCordonedAnnotationKey]
+			assert.Equal(t, tc.expectedCordoned && !tc.unschedulable, markedCordoned)
+			assert.Equal(t, tc.expectedTainted, len(updatedNode.Spec.Taints) == 1 && updatedNode.Spec.Taints[0].Key == consts.VMDeletedTaintKey)
+		})
+	}
+}
+
+func TestCancelNodeDeletion(t *testing.T) {
+	for _, tc := range []struct {
+		desc                  string
+		annotations           map[string]string
+		expectedUnschedulable bool
+	}{
+		{
+			desc:                  "should remove the taint and uncordon the node cordoned by delayNodeDeletion",
+			annotations:           map[string]string{consts.VMDeletedCordonedAnnotationKey: "true"},
+			expectedUnschedulable: false,
+		},
+		{
+			desc:                  "should keep the node cordoned by others",
+			expectedUnschedulable: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0", Annotations: tc.annotations},
+				Spec: v1.NodeSpec{
+					Unschedulable: true,
+					Taints:        []v1.Taint{{Key: consts.VMDeletedTaintKey, Effect: v1.TaintEffectNoExecute, TimeAdded: &metav1.Time{Time: time.Now()}}},
+				},
+			}
+			az.KubeClient = fake.NewSimpleClientset(node)
+
+			assert.NoError(t, az.cancelNodeDeletion(context.TODO(), node))
+
+			updatedNode, err := az.KubeClient.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Empty(t, updatedNode.Spec.Taints)
+			assert.Equal(t, tc.expectedUnschedulable, updatedNode.Spec.Unschedulable)
+			assert.NotContains(t, updatedNode.Annotations, consts.VMDeletedCordonedAnnotationKey)
+		})
+	}
+}
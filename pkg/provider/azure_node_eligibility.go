@@ -0,0 +1,290 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	nodeutil "k8s.io/component-helpers/node/util"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+// isNodeBackendPoolGatingEnabled returns true if the node age or readiness affects the
+// membership of the load balancer backend pools.
+func (az *Cloud) isNodeBackendPoolGatingEnabled() bool {
+	return az.LoadBalancerBackendPoolNodeMinAgeInSeconds > 0 || az.LoadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds > 0
+}
+
+// isNodeEligibleForLoadBalancer returns false if the node should be kept out of the
+// load balancer backend pools because it is too young, or it is NotReady and the minimum
+// age or the grace period excludes it. The returned duration is the time after which the
+// eligibility of the node changes on its own, or 0 if it only changes with a node update.
+func (az *Cloud) isNodeEligibleForLoadBalancer(node *v1.Node, now time.Time) (bool, time.Duration) {
+	if node == nil {
+		return false, 0
+	}
+
+	minAge := time.Duration(az.LoadBalancerBackendPoolNodeMinAgeInSeconds) * time.Second
+	agedInAt := node.CreationTimestamp.Add(minAge)
+	if minAge > 0 && now.Before(agedInAt) {
+		return false, agedInAt.Sub(now)
+	}
+
+	_, c := nodeutil.GetNodeCondition(&node.Status, v1.NodeReady)
+	if c != nil && c.Status == v1.ConditionTrue {
+		return true, 0
+	}
+
+	// A node that never reported its readiness is NotReady since it was created.
+	notReadySince := node.CreationTimestamp.Time
+	if c != nil {
+		notReadySince = c.LastTransitionTime.Time
+	}
+	if minAge > 0 && notReadySince.Before(agedInAt) {
+		// The node was not Ready when it aged in, so it has never joined the backend pools.
+		return false, 0
+	}
+
+	gracePeriod := time.Duration(az.LoadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds) * time.Second
+	if gracePeriod <= 0 {
+		// NotReady nodes are never removed without the grace period.
+		return true, 0
+	}
+	gracePeriodEnd := notReadySince.Add(gracePeriod)
+	if !now.Before(gracePeriodEnd) {
+		return false, 0
+	}
+	return true, gracePeriodEnd.Sub(now)
+}
+
+// filterNodesEligibleForLoadBalancer drops the nodes that are not eligible for the
+// load balancer backend pools. The input is returned as-is if the readiness gating is disabled.
+// The returned duration is the earliest time after which the eligibility of any node changes
+// on its own, or 0 if there is none.
+func (az *Cloud) filterNodesEligibleForLoadBalancer(nodes []*v1.Node) ([]*v1.Node, time.Duration) {
+	if nodes == nil || !az.isNodeBackendPoolGatingEnabled() {
+		return nodes, 0
+	}
+
+	now := time.Now()
+	var requeueAfter time.Duration
+	eligibleNodes := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		eligible, changeAfter := az.isNodeEligibleForLoadBalancer(node, now)
+		if changeAfter > 0 && (requeueAfter == 0 || changeAfter < requeueAfter) {
+			requeueAfter = changeAfter
+		}
+		if !eligible {
+			log.Background().WithName("filterNodesEligibleForLoadBalancer").V(4).Info("Excluding the node from LoadBalancer because it is not old enough, or it is NotReady and not in the grace period", "node", node.Name)
+			continue
+		}
+		eligibleNodes = append(eligibleNodes, node)
+	}
+	return eligibleNodes, requeueAfter
+}
+
+// filterNodesInLoadBalancerDedicatedHostGroups drops the nodes whose VMs are not in one of the
+// LoadBalancerDedicatedHostGroups. The host groups are read from the cached VMs instead of the node
+// labels, which the kubelets can change. The nodes whose VMs are not found are dropped as well.
+func (az *Cloud) filterNodesInLoadBalancerDedicatedHostGroups(ctx context.Context, nodes []*v1.Node) ([]*v1.Node, error) {
+	if nodes == nil || strings.TrimSpace(az.LoadBalancerDedicatedHostGroups) == "" {
+		return nodes, nil
+	}
+
+	hostGroups := sets.New[string]()
+	for _, name := range strings.Split(az.LoadBalancerDedicatedHostGroups, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			hostGroups.Insert(strings.ToLower(name))
+		}
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("filterNodesInLoadBalancerDedicatedHostGroups")
+	filteredNodes := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		hostGroup, err := az.getNodeDedicatedHostGroup(ctx, node)
+		if errors.Is(err, cloudprovider.InstanceNotFound) {
+			// the VM of the node is being deleted, which must not block the other nodes
+			logger.V(2).Info("Excluding the node from LoadBalancer because its VM is not found", "node", node.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !hostGroups.Has(strings.ToLower(hostGroup)) {
+			logger.V(4).Info("Excluding the node from LoadBalancer because it is not in the dedicated host groups", "node", node.Name, "hostGroups", az.LoadBalancerDedicatedHostGroups)
+			continue
+		}
+		filteredNodes = append(filteredNodes, node)
+	}
+	return filteredNodes, nil
+}
+
+// nodeEligibilityServiceInfo is what the nodeEligibilityRequeuer needs to update the load
+// balancer of a service.
+type nodeEligibilityServiceInfo struct {
+	clusterName string
+	nodes       []*v1.Node
+}
+
+// nodeEligibilityRequeuer updates the load balancers of the services when the eligibility
+// of a node for the backend pools changes. The service controller only syncs the nodes
+// when a node is added, deleted or excluded, so it misses a node that ages in, finishes
+// its NotReady grace period or becomes Ready.
+type nodeEligibilityRequeuer struct {
+	az    *Cloud
+	queue workqueue.TypedDelayingInterface[string]
+
+	lock sync.Mutex
+	// services is the last cluster name and nodes each service was reconciled with.
+	// key: <namespace>/<name>
+	services map[string]nodeEligibilityServiceInfo
+}
+
+func newNodeEligibilityRequeuer(az *Cloud) *nodeEligibilityRequeuer {
+	return &nodeEligibilityRequeuer{
+		az: az,
+		queue: workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[string]{
+			Name: "node-eligibility",
+		}),
+		services: make(map[string]nodeEligibilityServiceInfo),
+	}
+}
+
+// track records the nodes the service was reconciled with, and requeues the service
+// after requeueAfter if it is positive.
+func (r *nodeEligibilityRequeuer) track(clusterName string, service *v1.Service, nodes []*v1.Node, requeueAfter time.Duration) {
+	if r == nil {
+		return
+	}
+	key := getServiceName(service)
+	r.lock.Lock()
+	r.services[key] = nodeEligibilityServiceInfo{clusterName: clusterName, nodes: nodes}
+	r.lock.Unlock()
+
+	if requeueAfter > 0 {
+		log.Background().WithName("nodeEligibilityRequeuer").V(4).Info("Requeue the service", "service", key, "after", requeueAfter)
+		r.queue.AddAfter(key, requeueAfter)
+	}
+}
+
+// forget stops tracking the service.
+func (r *nodeEligibilityRequeuer) forget(service *v1.Service) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.services, getServiceName(service))
+}
+
+// onNodeUpdate requeues all tracked services if the update of the node changes its eligibility
+// now or later, e.g. a node becomes Ready after it aged in or enters its NotReady grace period.
+func (r *nodeEligibilityRequeuer) onNodeUpdate(prevNode, newNode *v1.Node) {
+	if r == nil || prevNode == nil || newNode == nil {
+		return
+	}
+	now := time.Now()
+	wasEligible, _ := r.az.isNodeEligibleForLoadBalancer(prevNode, now)
+	eligible, changeAfter := r.az.isNodeEligibleForLoadBalancer(newNode, now)
+	if wasEligible == eligible && changeAfter == 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for key := range r.services {
+		if wasEligible != eligible {
+			r.queue.Add(key)
+		}
+		if changeAfter > 0 {
+			r.queue.AddAfter(key, changeAfter)
+		}
+	}
+}
+
+func (r *nodeEligibilityRequeuer) run(ctx context.Context) {
+	logger := log.FromContextOrBackground(ctx).WithName("nodeEligibilityRequeuer")
+	logger.Info("Started")
+	go func() {
+		<-ctx.Done()
+		r.queue.ShutDown()
+	}()
+	for r.processNextItem(ctx) {
+	}
+	logger.Info("Stopped")
+}
+
+func (r *nodeEligibilityRequeuer) processNextItem(ctx context.Context) bool {
+	key, quit := r.queue.Get()
+	if quit {
+		return false
+	}
+	defer r.queue.Done(key)
+
+	r.lock.Lock()
+	info, found := r.services[key]
+	r.lock.Unlock()
+	if !found {
+		return true
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("nodeEligibilityRequeuer").WithValues("service", key)
+	service, serviceExists, err := r.az.getLatestService(key, true)
+	if err != nil {
+		logger.Error(err, "Failed to get the service")
+		return true
+	}
+	if !serviceExists {
+		r.lock.Lock()
+		delete(r.services, key)
+		r.lock.Unlock()
+		return true
+	}
+
+	logger.V(2).Info("Updating the load balancer of the service because the eligibility of its nodes changed")
+	if err := r.az.UpdateLoadBalancer(ctx, info.clusterName, service, r.az.getLatestNodes(info.nodes)); err != nil {
+		logger.Error(err, "Failed to update the load balancer of the service")
+	}
+	return true
+}
+
+// getLatestNodes returns the nodes from the node lister, so that the readiness is up to date.
+// Nodes not found in the lister have been deleted and are dropped.
+func (az *Cloud) getLatestNodes(nodes []*v1.Node) []*v1.Node {
+	if az.nodeLister == nil {
+		return nodes
+	}
+	latestNodes := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		latestNode, err := az.nodeLister.Get(node.Name)
+		if err != nil {
+			log.Background().WithName("getLatestNodes").V(4).Info("Dropping the node", "node", node.Name, "error", err)
+			continue
+		}
+		latestNodes = append(latestNodes, latestNode)
+	}
+	return latestNodes
+}
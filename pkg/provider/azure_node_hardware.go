@@ -0,0 +1,336 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// resourceSKUCacheTTL is the TTL of the VM sizes of a location, which rarely change.
+const resourceSKUCacheTTL = 24 * time.Hour
+
+var (
+	nodeVMSSVMProviderIDRE = regexp.MustCompile(`(?i)^azure:///subscriptions/[^/]+/resourceGroups/([^/]+)/providers/Microsoft.Compute/virtualMachineScaleSets/([^/]+)/virtualMachines/([^/]+)$`)
+	nodeVMProviderIDRE     = regexp.MustCompile(`(?i)^azure:///subscriptions/[^/]+/resourceGroups/([^/]+)/providers/Microsoft.Compute/virtualMachines/([^/]+)$`)
+)
+
+// NodeHardwareLabelKeys are the keys of the labels returned by GetNodeHardwareLabels.
+var NodeHardwareLabelKeys = []string{
+	consts.LabelAcceleratedNetworking,
+	consts.LabelUltraSSDEnabled,
+	consts.LabelProximityPlacementGroup,
+	consts.LabelDedicatedHostGroup,
+	consts.LabelDedicatedHost,
+	consts.LabelCapacityReservationGroup,
+	consts.LabelPlatformFaultDomain,
+	consts.LabelPlatformUpdateDomain,
+	consts.LabelSKUCPU,
+	consts.LabelSKUMemory,
+	consts.LabelSKUGPUCount,
+}
+
+// GetNodeHardwareLabels returns the labels describing the hardware of the VM of the node: the vCPUs,
+// memory and GPUs of the VM size, whether the primary NIC has accelerated networking, whether ultra
+// disks can be attached, the proximity placement group, dedicated host group, dedicated host and
+// capacity reservation group of the VM, and the platform fault and update domains of the VMs not in
+// uniform scale sets.
+// The VM is found by the provider ID of the node. It returns nil for nodes not managed by Azure.
+func (az *Cloud) GetNodeHardwareLabels(ctx context.Context, node *v1.Node) (map[string]string, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" || az.IsNodeUnmanagedByProviderID(providerID) {
+		return nil, nil
+	}
+
+	if matches := nodeVMSSVMProviderIDRE.FindStringSubmatch(providerID); len(matches) == 4 {
+		return az.getVMSSVMHardwareLabels(ctx, matches[1], matches[2], matches[3])
+	}
+	if matches := nodeVMProviderIDRE.FindStringSubmatch(providerID); len(matches) == 3 {
+		return az.getVMHardwareLabels(ctx, matches[1], matches[2])
+	}
+	return nil, fmt.Errorf("GetNodeHardwareLabels: unsupported provider ID %q of node %s", providerID, node.Name)
+}
+
+// getVMSSVM returns the VMSS VM and its scale set, from the caches of the scale sets if the
+// VM type is vmss.
+func (az *Cloud) getVMSSVM(ctx context.Context, resourceGroup, vmssName, instanceID string) (*armcompute.VirtualMachineScaleSetVM, *armcompute.VirtualMachineScaleSet, error) {
+	if ss, ok := az.VMSet.(*ScaleSet); ok {
+		vm, err := ss.getVmssVMByInstanceID(ctx, resourceGroup, vmssName, instanceID, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get vmss vm %s/%s/%s: %w", resourceGroup, vmssName, instanceID, err)
+		}
+		vmss, err := ss.getVMSS(ctx, vmssName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get vmss %s/%s: %w", resourceGroup, vmssName, err)
+		}
+		return vm, vmss, nil
+	}
+
+	vm, err := az.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().Get(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get vmss vm %s/%s/%s: %w", resourceGroup, vmssName, instanceID, instanceNotFoundFromError(err))
+	}
+	vmss, err := az.ComputeClientFactory.GetVirtualMachineScaleSetClient().Get(ctx, resourceGroup, vmssName, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get vmss %s/%s: %w", resourceGroup, vmssName, instanceNotFoundFromError(err))
+	}
+	return vm, vmss, nil
+}
+
+// instanceNotFoundFromError returns cloudprovider.InstanceNotFound if err is a not found error
+// of ARM, or err otherwise.
+func instanceNotFoundFromError(err error) error {
+	if exists, rerr := checkResourceExistsFromError(err); rerr == nil && !exists {
+		return cloudprovider.InstanceNotFound
+	}
+	return err
+}
+
+// getNodeDedicatedHostGroup returns the name of the dedicated host group of the VM of the node, or ""
+// if the VM is not on a dedicated host or the node is not managed by Azure. The VMs are read from
+// the caches when the VM type allows it.
+func (az *Cloud) getNodeDedicatedHostGroup(ctx context.Context, node *v1.Node) (string, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" || az.IsNodeUnmanagedByProviderID(providerID) {
+		return "", nil
+	}
+
+	var hostGroup *armcompute.SubResource
+	if matches := nodeVMSSVMProviderIDRE.FindStringSubmatch(providerID); len(matches) == 4 {
+		_, vmss, err := az.getVMSSVM(ctx, matches[1], matches[2], matches[3])
+		if err != nil {
+			return "", fmt.Errorf("getNodeDedicatedHostGroup: %w", err)
+		}
+		if vmss.Properties != nil {
+			hostGroup = vmss.Properties.HostGroup
+		}
+	} else if matches := nodeVMProviderIDRE.FindStringSubmatch(providerID); len(matches) == 3 {
+		var vm *armcompute.VirtualMachine
+		var err error
+		// the VM cache is keyed by the VM name and reads the VMs from the resource groups of
+		// the nodes, so it can only be used when the VM is in the resource group of the node.
+		if resourceGroup, rgErr := az.GetNodeResourceGroup(matches[2]); rgErr == nil && strings.EqualFold(resourceGroup, matches[1]) {
+			vm, err = az.getVirtualMachine(ctx, types.NodeName(matches[2]), azcache.CacheReadTypeDefault)
+		} else {
+			vm, err = az.ComputeClientFactory.GetVirtualMachineClient().Get(ctx, matches[1], matches[2], nil)
+			err = instanceNotFoundFromError(err)
+		}
+		if err != nil {
+			return "", fmt.Errorf("getNodeDedicatedHostGroup: failed to get vm %s/%s: %w", matches[1], matches[2], err)
+		}
+		if vm.Properties != nil {
+			hostGroup = getVMDedicatedHostGroup(vm.Properties)
+		}
+	} else {
+		return "", fmt.Errorf("getNodeDedicatedHostGroup: unsupported provider ID %q of node %s", providerID, node.Name)
+	}
+
+	if hostGroup == nil || hostGroup.ID == nil {
+		return "", nil
+	}
+	return getLastSegment(*hostGroup.ID, "/")
+}
+
+// getVMDedicatedHostGroup returns the dedicated host group of the VM, which is the parent of the
+// dedicated host if the VM is placed on a host directly.
+func getVMDedicatedHostGroup(properties *armcompute.VirtualMachineProperties) *armcompute.SubResource {
+	if properties.HostGroup != nil || properties.Host == nil {
+		return properties.HostGroup
+	}
+	// the host group is the parent of the host: .../hostGroups/<group>/hosts/<host>
+	hostID := ptr.Deref(properties.Host.ID, "")
+	if i := strings.LastIndex(strings.ToLower(hostID), "/hosts/"); i > 0 {
+		return &armcompute.SubResource{ID: ptr.To(hostID[:i])}
+	}
+	return nil
+}
+
+func (az *Cloud) getVMSSVMHardwareLabels(ctx context.Context, resourceGroup, vmssName, instanceID string) (map[string]string, error) {
+	vm, vmss, err := az.getVMSSVM(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("getVMSSVMHardwareLabels: %w", err)
+	}
+
+	labels := make(map[string]string)
+	var skuName string
+	if vm.SKU != nil {
+		skuName = ptr.Deref(vm.SKU.Name, "")
+	}
+	if err := az.setVMSKULabels(ctx, labels, ptr.Deref(vm.Location, az.Location), skuName); err != nil {
+		return nil, fmt.Errorf("getVMSSVMHardwareLabels: %w", err)
+	}
+	if vm.Properties != nil {
+		setUltraSSDEnabledLabel(labels, vm.Properties.AdditionalCapabilities)
+		if vm.Properties.NetworkProfileConfiguration != nil {
+			nics := vm.Properties.NetworkProfileConfiguration.NetworkInterfaceConfigurations
+			for _, nic := range nics {
+				if nic.Properties == nil || (len(nics) > 1 && !ptr.Deref(nic.Properties.Primary, false)) {
+					continue
+				}
+				labels[consts.LabelAcceleratedNetworking] = strconv.FormatBool(ptr.Deref(nic.Properties.EnableAcceleratedNetworking, false))
+				break
+			}
+		}
+	}
+	if vmss.Properties != nil {
+		setResourceNameLabel(labels, consts.LabelProximityPlacementGroup, vmss.Properties.ProximityPlacementGroup)
+		setResourceNameLabel(labels, consts.LabelDedicatedHostGroup, vmss.Properties.HostGroup)
+		if vmss.Properties.VirtualMachineProfile != nil {
+			setCapacityReservationGroupLabel(labels, vmss.Properties.VirtualMachineProfile.CapacityReservation)
+		}
+	}
+	return labels, nil
+}
+
+func (az *Cloud) getVMHardwareLabels(ctx context.Context, resourceGroup, vmName string) (map[string]string, error) {
+	vm, err := az.ComputeClientFactory.GetVirtualMachineClient().Get(ctx, resourceGroup, vmName, ptr.To(string(armcompute.InstanceViewTypesInstanceView)))
+	if err != nil {
+		return nil, fmt.Errorf("getVMHardwareLabels: failed to get vm %s/%s: %w", resourceGroup, vmName, err)
+	}
+
+	labels := make(map[string]string)
+	if vm.Properties == nil {
+		return labels, nil
+	}
+	if vm.Properties.HardwareProfile != nil {
+		if err := az.setVMSKULabels(ctx, labels, ptr.Deref(vm.Location, az.Location), string(ptr.Deref(vm.Properties.HardwareProfile.VMSize, ""))); err != nil {
+			return nil, fmt.Errorf("getVMHardwareLabels: %w", err)
+		}
+	}
+	setUltraSSDEnabledLabel(labels, vm.Properties.AdditionalCapabilities)
+	setResourceNameLabel(labels, consts.LabelProximityPlacementGroup, vm.Properties.ProximityPlacementGroup)
+	setResourceNameLabel(labels, consts.LabelDedicatedHostGroup, getVMDedicatedHostGroup(vm.Properties))
+	setResourceNameLabel(labels, consts.LabelDedicatedHost, vm.Properties.Host)
+	setCapacityReservationGroupLabel(labels, vm.Properties.CapacityReservation)
+	setPlatformDomainLabels(labels, vm.Properties)
+
+	if vm.Properties.NetworkProfile == nil || len(vm.Properties.NetworkProfile.NetworkInterfaces) == 0 {
+		return labels, nil
+	}
+	nicID, err := getPrimaryInterfaceID(vm)
+	if err != nil {
+		return nil, err
+	}
+	nicName, err := getLastSegment(nicID, "/")
+	if err != nil {
+		return nil, err
+	}
+	nicResourceGroup, err := extractResourceGroupByNicID(nicID)
+	if err != nil {
+		return nil, err
+	}
+	nic, err := az.NetworkClientFactory.GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getVMHardwareLabels: failed to get nic %s/%s: %w", nicResourceGroup, nicName, err)
+	}
+	if nic.Properties != nil {
+		labels[consts.LabelAcceleratedNetworking] = strconv.FormatBool(ptr.Deref(nic.Properties.EnableAcceleratedNetworking, false))
+	}
+	return labels, nil
+}
+
+// setVMSKULabels sets the labels of the vCPUs, memory in MiB and GPUs of the VM size. The
+// sizes are looked up from the cached resource SKUs of the location.
+func (az *Cloud) setVMSKULabels(ctx context.Context, labels map[string]string, location, skuName string) error {
+	if az.resourceSKURepo == nil || skuName == "" {
+		return nil
+	}
+	sku, err := az.resourceSKURepo.GetVMSKU(ctx, location, skuName)
+	if err != nil {
+		return fmt.Errorf("failed to get the resource SKU %s in %s: %w", skuName, location, err)
+	}
+	if sku == nil {
+		klog.V(4).InfoS("setVMSKULabels: the resource SKU is not found", "sku", skuName, "location", location)
+		return nil
+	}
+	if sku.VCPUs != "" {
+		labels[consts.LabelSKUCPU] = sku.VCPUs
+	}
+	if memoryGB, err := strconv.ParseFloat(sku.MemoryGB, 64); err == nil {
+		labels[consts.LabelSKUMemory] = strconv.FormatInt(int64(math.Round(memoryGB*1024)), 10)
+	}
+	gpus := sku.GPUs
+	if gpus == "" {
+		gpus = "0"
+	}
+	labels[consts.LabelSKUGPUCount] = gpus
+	return nil
+}
+
+func setUltraSSDEnabledLabel(labels map[string]string, capabilities *armcompute.AdditionalCapabilities) {
+	ultraSSDEnabled := capabilities != nil && ptr.Deref(capabilities.UltraSSDEnabled, false)
+	labels[consts.LabelUltraSSDEnabled] = strconv.FormatBool(ultraSSDEnabled)
+}
+
+func setCapacityReservationGroupLabel(labels map[string]string, capacityReservation *armcompute.CapacityReservationProfile) {
+	if capacityReservation != nil {
+		setResourceNameLabel(labels, consts.LabelCapacityReservationGroup, capacityReservation.CapacityReservationGroup)
+	}
+}
+
+// setPlatformDomainLabels sets the labels of the platform fault and update domains of the VM. The
+// domains of the VMs in availability sets are only in the instance view, while the fault domains of
+// the VMs in flexible scale sets are also in the VM properties.
+func setPlatformDomainLabels(labels map[string]string, properties *armcompute.VirtualMachineProperties) {
+	faultDomain := properties.PlatformFaultDomain
+	var updateDomain *int32
+	if properties.InstanceView != nil {
+		if properties.InstanceView.PlatformFaultDomain != nil {
+			faultDomain = properties.InstanceView.PlatformFaultDomain
+		}
+		updateDomain = properties.InstanceView.PlatformUpdateDomain
+	}
+	if faultDomain != nil {
+		labels[consts.LabelPlatformFaultDomain] = strconv.Itoa(int(*faultDomain))
+	}
+	if updateDomain != nil {
+		labels[consts.LabelPlatformUpdateDomain] = strconv.Itoa(int(*updateDomain))
+	}
+}
+
+// setResourceNameLabel sets the label to the name of the resource if the name is a valid label value.
+func setResourceNameLabel(labels map[string]string, key string, resource *armcompute.SubResource) {
+	if resource == nil || resource.ID == nil {
+		return
+	}
+	name, err := getLastSegment(*resource.ID, "/")
+	if err != nil {
+		klog.ErrorS(err, "setResourceNameLabel: failed to get the name of the resource", "resourceID", *resource.ID)
+		return
+	}
+	if errs := validation.IsValidLabelValue(name); len(errs) > 0 {
+		klog.InfoS("setResourceNameLabel: skipping the label because the name is not a valid label value", "label", key, "value", name, "errors", errs)
+		return
+	}
+	labels[key] = name
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/interfaceclient/mock_interfaceclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetclient/mock_virtualmachinescalesetclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetvmclient/mock_virtualmachinescalesetvmclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/resourcesku"
+)
+
+func TestGetNodeHardwareLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	skuRepo := resourcesku.NewMockRepository(ctrl)
+	az.resourceSKURepo = skuRepo
+
+	t.Run("should return nil for unmanaged nodes", func(t *testing.T) {
+		labels, err := az.GetNodeHardwareLabels(context.TODO(), &v1.Node{Spec: v1.NodeSpec{ProviderID: "kind://docker/kind/node0"}})
+		assert.NoError(t, err)
+		assert.Nil(t, labels)
+	})
+
+	t.Run("should return the labels of a vm", func(t *testing.T) {
+		vmClient := az.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+		vmClient.EXPECT().Get(gomock.Any(), "rg", "vm0", ptr.To(string(armcompute.InstanceViewTypesInstanceView))).Return(&armcompute.VirtualMachine{
+			Name:     ptr.To("vm0"),
+			Location: ptr.To("eastus"),
+			Properties: &armcompute.VirtualMachineProperties{
+				HardwareProfile:        &armcompute.HardwareProfile{VMSize: to.Ptr(armcompute.VirtualMachineSizeTypesStandardNC6SV3)},
+				AdditionalCapabilities: &armcompute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)},
+				InstanceView: &armcompute.VirtualMachineInstanceView{
+					PlatformFaultDomain:  ptr.To[int32](1),
+					PlatformUpdateDomain: ptr.To[int32](3),
+				},
+				ProximityPlacementGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/proximityPlacementGroups/ppg")},
+				Host:                    &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/hostGroups/hg/hosts/host0")},
+				CapacityReservation: &armcompute.CapacityReservationProfile{
+					CapacityReservationGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/capacityReservationGroups/crg")},
+				},
+				NetworkProfile: &armcompute.NetworkProfile{
+					NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
+						{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic0")},
+					},
+				},
+			},
+		}, nil)
+		skuRepo.EXPECT().GetVMSKU(gomock.Any(), "eastus", "Standard_NC6s_v3").Return(&resourcesku.VMSKU{
+			Name: "Standard_NC6s_v3", VCPUs: "6", MemoryGB: "112", GPUs: "1",
+		}, nil)
+		nicClient := az.NetworkClientFactory.GetInterfaceClient().(*mock_interfaceclient.MockInterface)
+		nicClient.EXPECT().Get(gomock.Any(), "rg", "nic0", gomock.Any()).Return(&armnetwork.Interface{
+			Properties: &armnetwork.InterfacePropertiesFormat{EnableAcceleratedNetworking: ptr.To(true)},
+		}, nil)
+
+		labels, err := az.GetNodeHardwareLabels(context.TODO(), &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm0"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm0"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			consts.LabelAcceleratedNetworking:    "true",
+			consts.LabelUltraSSDEnabled:          "true",
+			consts.LabelProximityPlacementGroup:  "ppg",
+			consts.LabelDedicatedHostGroup:       "hg",
+			consts.LabelDedicatedHost:            "host0",
+			consts.LabelCapacityReservationGroup: "crg",
+			consts.LabelPlatformFaultDomain:      "1",
+			consts.LabelPlatformUpdateDomain:     "3",
+			consts.LabelSKUCPU:                   "6",
+			consts.LabelSKUMemory:                "114688",
+			consts.LabelSKUGPUCount:              "1",
+		}, labels)
+	})
+
+	t.Run("should return the labels of a vmss vm", func(t *testing.T) {
+		vmssVMClient := az.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+		vmssVMClient.EXPECT().Get(gomock.Any(), "rg", "vmss", "0").Return(&armcompute.VirtualMachineScaleSetVM{
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				NetworkProfileConfiguration: &armcompute.VirtualMachineScaleSetVMNetworkProfileConfiguration{
+					NetworkInterfaceConfigurations: []*armcompute.VirtualMachineScaleSetNetworkConfiguration{
+						{
+							Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+								Primary:                     ptr.To(true),
+								EnableAcceleratedNetworking: ptr.To(false),
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+		vmssClient := az.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+		vmssClient.EXPECT().Get(gomock.Any(), "rg", "vmss", gomock.Any()).Return(&armcompute.VirtualMachineScaleSet{
+			Properties: &armcompute.VirtualMachineScaleSetProperties{
+				HostGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/hostGroups/hg")},
+				VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{
+					CapacityReservation: &armcompute.CapacityReservationProfile{
+						CapacityReservationGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/capacityReservationGroups/crg")},
+					},
+				},
+			},
+		}, nil)
+
+		labels, err := az.GetNodeHardwareLabels(context.TODO(), &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmss000000"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			consts.LabelAcceleratedNetworking:    "false",
+			consts.LabelUltraSSDEnabled:          "false",
+			consts.LabelDedicatedHostGroup:       "hg",
+			consts.LabelCapacityReservationGroup: "crg",
+		}, labels)
+	})
+}
+
+func TestGetNodeHardwareLabelsFromVMSSCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	skuRepo := resourcesku.NewMockRepository(ctrl)
+	ss.resourceSKURepo = skuRepo
+	ss.VMSet = ss
+
+	vmss := buildTestVMSS("vmss", "vmss")
+	vmss.Properties.ProximityPlacementGroup = &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/proximityPlacementGroups/ppg")}
+	vmssClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	vmssClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachineScaleSet{vmss}, nil).Times(1)
+	expectedVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, "vmss", "", 0, []string{"vmss000000"}, "", false)
+	vmssVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	vmssVMClient.EXPECT().ListVMInstanceView(gomock.Any(), "rg", "vmss").Return(expectedVMs, nil).Times(1)
+	skuRepo.EXPECT().GetVMSKU(gomock.Any(), ss.Location, "SKU").Return(&resourcesku.VMSKU{Name: "SKU", VCPUs: "2", MemoryGB: "3.5"}, nil).Times(2)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmss000000"},
+		Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0"},
+	}
+	expected := map[string]string{
+		consts.LabelAcceleratedNetworking:   "false",
+		consts.LabelUltraSSDEnabled:         "false",
+		consts.LabelProximityPlacementGroup: "ppg",
+		consts.LabelSKUCPU:                  "2",
+		consts.LabelSKUMemory:               "3584",
+		consts.LabelSKUGPUCount:             "0",
+	}
+	// the second call is served from the vmss and vmss vm caches
+	for i := 0; i < 2; i++ {
+		labels, err := ss.GetNodeHardwareLabels(context.TODO(), node)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, labels)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+// nodeIdentityCacheTTL is the TTL of the VM identities and NIC IPs of a resource group used
+// to resolve the provider IDs of the nodes.
+const nodeIdentityCacheTTL = 5 * time.Minute
+
+// nodeIdentityEntry is the VMs of a resource group by their identities.
+type nodeIdentityEntry struct {
+	// vmIDsBySystemUUID is the IDs of the VMs keyed by the lower case vmId.
+	vmIDsBySystemUUID map[string]string
+	// vmIDsByIP is the IDs of the VMs owning a standalone NIC with the private IP.
+	vmIDsByIP map[string][]string
+}
+
+func (az *Cloud) newNodeIdentityCache() (azcache.Resource, error) {
+	getter := func(ctx context.Context, resourceGroup string) (interface{}, error) {
+		entry := &nodeIdentityEntry{
+			vmIDsBySystemUUID: make(map[string]string),
+			vmIDsByIP:         make(map[string][]string),
+		}
+
+		vms, err := az.ListVirtualMachines(ctx, resourceGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list vms in resource group %s: %w", resourceGroup, err)
+		}
+		for _, vm := range vms {
+			if vm == nil || vm.ID == nil || vm.Properties == nil || vm.Properties.VMID == nil {
+				continue
+			}
+			entry.vmIDsBySystemUUID[strings.ToLower(*vm.Properties.VMID)] = *vm.ID
+		}
+
+		nics, err := az.NetworkClientFactory.GetInterfaceClient().List(ctx, resourceGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nics in resource group %s: %w", resourceGroup, err)
+		}
+		for _, nic := range nics {
+			if nic == nil || nic.Properties == nil || nic.Properties.VirtualMachine == nil || nic.Properties.VirtualMachine.ID == nil {
+				continue
+			}
+			for _, ipConfig := range nic.Properties.IPConfigurations {
+				if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PrivateIPAddress == nil {
+					continue
+				}
+				ip := *ipConfig.Properties.PrivateIPAddress
+				entry.vmIDsByIP[ip] = append(entry.vmIDsByIP[ip], *nic.Properties.VirtualMachine.ID)
+			}
+		}
+		return entry, nil
+	}
+	return azcache.NewTimedCache(nodeIdentityCacheTTL, getter, az.Config.DisableAPICallCache)
+}
+
+// GetNodeProviderID resolves the provider ID of a node registered without one. The VM is
+// looked up by the identity of the node: its name, from the instance metadata service for
+// the local instance or from ARM otherwise, and then its system UUID, which is the vmId of
+// the VM. The internal IPs of the node are only a fallback, matched against the cached NICs
+// in the resource group of the node, and a match is refused if the IPs belong to more than
+// one VM. It returns cloudprovider.InstanceNotFound if no VM is found, and "" for unmanaged
+// nodes.
+func (az *Cloud) GetNodeProviderID(ctx context.Context, node *v1.Node) (string, error) {
+	logger := log.FromContextOrBackground(ctx).WithName("GetNodeProviderID").WithValues("node", node.Name)
+	unmanaged, err := az.IsNodeUnmanaged(node.Name)
+	if err != nil {
+		return "", err
+	}
+	if unmanaged {
+		logger.V(4).Info("Omitting the unmanaged node")
+		return "", nil
+	}
+
+	providerID, err := cloudprovider.GetInstanceProviderID(ctx, az, types.NodeName(node.Name))
+	if err == nil {
+		return providerID, nil
+	}
+	if !errors.Is(err, cloudprovider.InstanceNotFound) {
+		return "", err
+	}
+
+	resourceGroup, err := az.GetNodeResourceGroup(node.Name)
+	if err != nil {
+		return "", err
+	}
+	cached, err := az.nodeIdentityCache.Get(ctx, resourceGroup, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return "", fmt.Errorf("GetNodeProviderID: %w", err)
+	}
+	entry := cached.(*nodeIdentityEntry)
+
+	vmID, err := getVMIDByNodeSystemUUID(node, entry)
+	if err == nil {
+		logger.V(2).Info("Found the VM of the node by its system UUID", "vmID", vmID)
+		return getProviderIDFromVMID(vmID)
+	}
+	if !errors.Is(err, cloudprovider.InstanceNotFound) {
+		return "", err
+	}
+
+	logger.V(2).Info("No VM is named after the node or has its system UUID, looking it up by its internal IPs")
+	vmID, err = getVMIDByNodeInternalIPs(node, entry)
+	if err != nil {
+		return "", err
+	}
+	logger.V(2).Info("Found the VM of the node by its internal IPs", "vmID", vmID)
+	return getProviderIDFromVMID(vmID)
+}
+
+// getVMIDByNodeSystemUUID returns the ID of the VM whose vmId is the system UUID of the node.
+// The first three fields of the SMBIOS UUID are in little-endian on some VM generations, so
+// the UUID is also matched with these fields byte-swapped.
+func getVMIDByNodeSystemUUID(node *v1.Node, entry *nodeIdentityEntry) (string, error) {
+	systemUUID := strings.ToLower(node.Status.NodeInfo.SystemUUID)
+	if systemUUID == "" {
+		return "", cloudprovider.InstanceNotFound
+	}
+	if vmID, ok := entry.vmIDsBySystemUUID[systemUUID]; ok {
+		return vmID, nil
+	}
+	if vmID, ok := entry.vmIDsBySystemUUID[swapUUIDByteOrder(systemUUID)]; ok {
+		return vmID, nil
+	}
+	return "", cloudprovider.InstanceNotFound
+}
+
+// swapUUIDByteOrder swaps the byte order of the first three fields of the UUID. It returns
+// the input if it is not a UUID.
+func swapUUIDByteOrder(uuid string) string {
+	fields := strings.Split(uuid, "-")
+	if len(fields) != 5 || len(fields[0]) != 8 || len(fields[1]) != 4 || len(fields[2]) != 4 {
+		return uuid
+	}
+	for i := 0; i < 3; i++ {
+		field := fields[i]
+		var swapped strings.Builder
+		for j := len(field) - 2; j >= 0; j -= 2 {
+			swapped.WriteString(field[j : j+2])
+		}
+		fields[i] = swapped.String()
+	}
+	return strings.Join(fields, "-")
+}
+
+// getVMIDByNodeInternalIPs returns the ID of the VM owning a NIC with the internal IPs of the
+// node. Only standalone NICs are listed, so the VMs of uniform scale sets are not found by
+// their IPs. The IPs of different VNets may overlap, so the match is refused if the IPs
+// belong to more than one VM.
+func getVMIDByNodeInternalIPs(node *v1.Node, entry *nodeIdentityEntry) (string, error) {
+	// key: lower case VM ID
+	vmIDs := make(map[string]string)
+	for _, address := range node.Status.Addresses {
+		if address.Type != v1.NodeInternalIP {
+			continue
+		}
+		for _, vmID := range entry.vmIDsByIP[address.Address] {
+			vmIDs[strings.ToLower(vmID)] = vmID
+		}
+	}
+	ids := make([]string, 0, len(vmIDs))
+	for _, vmID := range vmIDs {
+		ids = append(ids, vmID)
+	}
+	switch len(ids) {
+	case 0:
+		return "", cloudprovider.InstanceNotFound
+	case 1:
+		return ids[0], nil
+	}
+	sort.Strings(ids)
+	log.Background().WithName("GetNodeProviderID").Info("Refusing to set the provider ID of the node because its internal IPs belong to more than one VM", "node", node.Name, "vmIDs", ids)
+	return "", fmt.Errorf("internal IPs of node %s belong to more than one VM: %w", node.Name, cloudprovider.InstanceNotFound)
+}
+
+func getProviderIDFromVMID(vmID string) (string, error) {
+	vmID, err := ConvertResourceGroupNameToLower(vmID)
+	if err != nil {
+		return "", err
+	}
+	return consts.CloudProviderName + "://" + vmID, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/interfaceclient/mock_interfaceclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
+)
+
+func TestGetNodeProviderID(t *testing.T) {
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: cloudprovider.InstanceNotFound.Error()}
+	buildNIC := func(vmName, ip string) *armnetwork.Interface {
+		return &armnetwork.Interface{
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				VirtualMachine: &armnetwork.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/RG/providers/Microsoft.Compute/virtualMachines/" + vmName)},
+				IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+					{Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{PrivateIPAddress: ptr.To(ip)}},
+				},
+			},
+		}
+	}
+	nics := []*armnetwork.Interface{
+		buildNIC("vm1", "10.240.0.5"),
+		buildNIC("vm2", "10.240.0.7"),
+		buildNIC("vm3", "10.240.0.7"),
+	}
+	vms := []*armcompute.VirtualMachine{
+		{
+			ID:         ptr.To("/subscriptions/sub/resourceGroups/RG/providers/Microsoft.Compute/virtualMachines/vm4"),
+			Properties: &armcompute.VirtualMachineProperties{VMID: ptr.To("12345678-9ABC-DEF0-1234-56789abcdef0")},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc               string
+		nodeName           string
+		systemUUID         string
+		internalIP         string
+		vm                 *armcompute.VirtualMachine
+		expectList         bool
+		expectedProviderID string
+		expectedErr        error
+	}{
+		{
+			desc:               "should return the provider ID of the vm named after the node",
+			nodeName:           "vm0",
+			vm:                 &armcompute.VirtualMachine{Name: ptr.To("vm0"), ID: ptr.To("/subscriptions/sub/resourceGroups/RG/providers/Microsoft.Compute/virtualMachines/vm0")},
+			expectedProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm0",
+		},
+		{
+			desc:               "should return the provider ID of the vm with the system UUID of the node before the internal IPs",
+			nodeName:           "node4",
+			systemUUID:         "12345678-9abc-def0-1234-56789abcdef0",
+			internalIP:         "10.240.0.5",
+			expectList:         true,
+			expectedProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm4",
+		},
+		{
+			desc:               "should match the system UUID with the byte-swapped vmId",
+			nodeName:           "node4",
+			systemUUID:         "78563412-bc9a-f0de-1234-56789abcdef0",
+			expectList:         true,
+			expectedProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm4",
+		},
+		{
+			desc:               "should return the provider ID of the vm owning the internal IP of the node",
+			nodeName:           "node1",
+			systemUUID:         "00000000-0000-0000-0000-000000000000",
+			internalIP:         "10.240.0.5",
+			expectList:         true,
+			expectedProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1",
+		},
+		{
+			desc:        "should refuse the internal IP of the node if it belongs to more than one vm",
+			nodeName:    "node2",
+			internalIP:  "10.240.0.7",
+			expectList:  true,
+			expectedErr: cloudprovider.InstanceNotFound,
+		},
+		{
+			desc:        "should return InstanceNotFound if no nic has the internal IP of the node",
+			nodeName:    "node2",
+			internalIP:  "10.240.0.6",
+			expectList:  true,
+			expectedErr: cloudprovider.InstanceNotFound,
+		},
+		{
+			desc:        "should return InstanceNotFound if the node has no identity",
+			nodeName:    "node3",
+			expectList:  true,
+			expectedErr: cloudprovider.InstanceNotFound,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+
+			vmClient := az.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+			if tc.vm != nil {
+				vmClient.EXPECT().Get(gomock.Any(), "rg", tc.nodeName, gomock.Any()).Return(tc.vm, nil)
+			} else {
+				vmClient.EXPECT().Get(gomock.Any(), "rg", tc.nodeName, gomock.Any()).Return(nil, notFound)
+			}
+			if tc.expectList {
+				vmClient.EXPECT().List(gomock.Any(), "rg").Return(vms, nil)
+				nicClient := az.NetworkClientFactory.GetInterfaceClient().(*mock_interfaceclient.MockInterface)
+				nicClient.EXPECT().List(gomock.Any(), "rg").Return(nics, nil)
+			}
+
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: tc.nodeName}}
+			node.Status.NodeInfo.SystemUUID = tc.systemUUID
+			if tc.internalIP != "" {
+				node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: tc.internalIP}}
+			}
+			providerID, err := az.GetNodeProviderID(context.TODO(), node)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedProviderID, providerID)
+		})
+	}
+}
+
+func TestGetNodeProviderIDCachesLookups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: cloudprovider.InstanceNotFound.Error()}
+	vmClient := az.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	vmClient.EXPECT().Get(gomock.Any(), "rg", gomock.Any(), gomock.Any()).Return(nil, notFound).Times(2)
+	vmClient.EXPECT().List(gomock.Any(), "rg").Return(nil, nil).Times(1)
+	nicClient := az.NetworkClientFactory.GetInterfaceClient().(*mock_interfaceclient.MockInterface)
+	nicClient.EXPECT().List(gomock.Any(), "rg").Return(nil, nil).Times(1)
+
+	for _, name := range []string{"node1", "node2"} {
+		_, err := az.GetNodeProviderID(context.TODO(), &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		assert.ErrorIs(t, err, cloudprovider.InstanceNotFound)
+	}
+}
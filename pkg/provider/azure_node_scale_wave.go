@@ -0,0 +1,367 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+const (
+	defaultNodeScaleWaveWindowInSeconds = 60
+	defaultNodeScaleWaveSettleInSeconds = 30
+
+	// maxNodeScaleWaveDeferral is the longest the node sync updates are deferred during a
+	// scale wave, so the backend pools still make progress while the nodes keep changing.
+	maxNodeScaleWaveDeferral = 10 * time.Minute
+	// nodeScaleWaveCheckInterval is how often the end of a scale wave is checked.
+	nodeScaleWaveCheckInterval = 5 * time.Second
+	// nodeScaleWaveRetryInterval is how long the failed deferred updates wait to be retried
+	// when no wave is going on.
+	nodeScaleWaveRetryInterval = 30 * time.Second
+	// nodeScaleWaveVMCacheRefreshInterval is the shortest interval between the refreshes of the
+	// VMs cache of a scale set to find new nodes during a wave.
+	nodeScaleWaveVMCacheRefreshInterval = 10 * time.Second
+)
+
+// nodeScaleWaveUpdateKey marks the context of the deferred updates applied at the end of a scale wave.
+type nodeScaleWaveUpdateKey struct{}
+
+// nodeChange is a node addition or deletion.
+type nodeChange struct {
+	time     time.Time
+	added    bool
+	nodeName string
+}
+
+// nodeScaleWaveServiceInfo is what the nodeScaleWaveTracker needs to update the load
+// balancer of a deferred service.
+type nodeScaleWaveServiceInfo struct {
+	clusterName string
+	nodes       []*v1.Node
+	// added and deleted are the node changes of the wave the update is deferred by
+	added   int
+	deleted int
+}
+
+// nodeScaleWaveTracker detects the waves of node additions and deletions, e.g. a large scale-up
+// by cluster-autoscaler. The service controller updates all the load balancers whenever the
+// nodes change, so a wave of 100 nodes joining one by one updates the backend pools about 100
+// times. During a wave, the node sync updates are deferred, and each service is updated once
+// with all the nodes when the wave ends. The failed updates are kept to be retried. The deferred
+// updates are not persisted: after a restart or a leader change, the service controller
+// reconciles every service with all the nodes when it starts.
+//
+// The node status updates of the cloud node controller look up the VM of every new node. During
+// a wave, the VMs caches of the scale sets of the new nodes are refreshed once per check, so the
+// lookups of the new nodes are served from the caches instead of listing the scale sets each.
+type nodeScaleWaveTracker struct {
+	az        *Cloud
+	threshold int
+	window    time.Duration
+	settle    time.Duration
+
+	lock sync.Mutex
+	// changes are the node additions and deletions within the window before a wave
+	changes []nodeChange
+	// inWave is true from the detection of a wave until its deferred updates are applied
+	inWave     bool
+	waveStart  time.Time
+	lastChange time.Time
+	added      int
+	deleted    int
+	// services are the services whose node sync updates are deferred.
+	// key: <namespace>/<name>
+	services map[string]nodeScaleWaveServiceInfo
+	// failed are the services whose deferred updates failed, retried after retryAfter.
+	// key: <namespace>/<name>
+	failed     map[string]nodeScaleWaveServiceInfo
+	retryAfter time.Time
+	// newNodes are the nodes added during the wave whose scale sets are not refreshed yet
+	newNodes []string
+}
+
+func newNodeScaleWaveTracker(az *Cloud) *nodeScaleWaveTracker {
+	window, settle := az.NodeScaleWaveWindowInSeconds, az.NodeScaleWaveSettleInSeconds
+	if window <= 0 {
+		window = defaultNodeScaleWaveWindowInSeconds
+	}
+	if settle <= 0 {
+		settle = defaultNodeScaleWaveSettleInSeconds
+	}
+	return &nodeScaleWaveTracker{
+		az:        az,
+		threshold: az.NodeScaleWaveThreshold,
+		window:    time.Duration(window) * time.Second,
+		settle:    time.Duration(settle) * time.Second,
+		services:  make(map[string]nodeScaleWaveServiceInfo),
+		failed:    make(map[string]nodeScaleWaveServiceInfo),
+	}
+}
+
+// inScaleWave returns true if a wave is going on.
+func (t *nodeScaleWaveTracker) inScaleWave() bool {
+	if t == nil {
+		return false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.inWave
+}
+
+// onNodeAdd records the addition of a node created within the window. The older nodes are
+// listed by the informer when it starts, they are not added by a scale-up.
+func (t *nodeScaleWaveTracker) onNodeAdd(node *v1.Node, now time.Time) {
+	if t == nil || now.Sub(node.CreationTimestamp.Time) >= t.window {
+		return
+	}
+	t.recordNodeChange(node.Name, true, now)
+}
+
+// onNodeChange records a node addition or deletion, and starts a wave if there are at least
+// threshold changes within the window.
+func (t *nodeScaleWaveTracker) onNodeChange(added bool, now time.Time) {
+	if t == nil {
+		return
+	}
+	t.recordNodeChange("", added, now)
+}
+
+func (t *nodeScaleWaveTracker) recordNodeChange(nodeName string, added bool, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.lastChange = now
+	if t.inWave {
+		if added {
+			t.added++
+			if nodeName != "" {
+				t.newNodes = append(t.newNodes, nodeName)
+			}
+		} else {
+			t.deleted++
+		}
+		return
+	}
+
+	changes := t.changes[:0]
+	for _, change := range t.changes {
+		if now.Sub(change.time) < t.window {
+			changes = append(changes, change)
+		}
+	}
+	t.changes = append(changes, nodeChange{time: now, added: added, nodeName: nodeName})
+	if len(t.changes) < t.threshold {
+		return
+	}
+
+	log.Background().WithName("nodeScaleWaveTracker").Info("Detected a scale wave, deferring the node sync updates of the load balancers",
+		"nodeChanges", len(t.changes), "window", t.window)
+	t.inWave = true
+	t.waveStart = t.changes[0].time
+	t.added, t.deleted = 0, 0
+	for _, change := range t.changes {
+		if change.added {
+			t.added++
+			if change.nodeName != "" {
+				t.newNodes = append(t.newNodes, change.nodeName)
+			}
+		} else {
+			t.deleted++
+		}
+	}
+	t.changes = nil
+}
+
+// takeNewNodes returns the nodes added during the wave since the last call.
+func (t *nodeScaleWaveTracker) takeNewNodes() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	nodeNames := t.newNodes
+	t.newNodes = nil
+	return nodeNames
+}
+
+// refreshScaleSetsOfNewNodes refreshes the VMs cache of each scale set of the nodes added during
+// the wave once, unless a lookup refreshed it just now, so the node status updates of the new
+// nodes share one listing of each scale set.
+func (t *nodeScaleWaveTracker) refreshScaleSetsOfNewNodes(ctx context.Context) {
+	nodeNames := t.takeNewNodes()
+	if len(nodeNames) == 0 {
+		return
+	}
+	ss, ok := t.az.VMSet.(*ScaleSet)
+	if !ok {
+		return
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("nodeScaleWaveTracker")
+	scaleSets := make(map[string]*nodeIdentity)
+	for _, nodeName := range nodeNames {
+		node, err := ss.getNodeIdentityByNodeName(ctx, nodeName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			logger.V(4).Info("Skipping the node not in a scale set", "node", nodeName, "error", err)
+			continue
+		}
+		scaleSets[getVMSSVMCacheKey(node.resourceGroup, node.vmssName)] = node
+	}
+
+	refreshed := 0
+	for cacheKey, node := range scaleSets {
+		if ss.isVMSSVMCacheRefreshedWithin(cacheKey, nodeScaleWaveVMCacheRefreshInterval) {
+			continue
+		}
+		if _, err := ss.getVMSSVMsFromCache(ctx, node.resourceGroup, node.vmssName, azcache.CacheReadTypeForceRefresh); err != nil {
+			logger.Error(err, "Failed to refresh the VMs of the scale set", "resourceGroup", node.resourceGroup, "vmss", node.vmssName)
+			continue
+		}
+		refreshed++
+	}
+	logger.V(2).Info("Refreshed the VMs of the scale sets of the new nodes", "scaleSets", refreshed, "newNodes", len(nodeNames))
+}
+
+// deferUpdate records the nodes of the node sync update of the service and returns true if
+// the update is deferred to the end of the current wave.
+func (t *nodeScaleWaveTracker) deferUpdate(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) bool {
+	if t == nil || ctx.Value(nodeScaleWaveUpdateKey{}) != nil {
+		return false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.inWave {
+		return false
+	}
+
+	key := getServiceName(service)
+	if _, found := t.services[key]; !found {
+		t.az.Event(service, v1.EventTypeNormal, "NodeScaleWaveDeferred",
+			fmt.Sprintf("Deferring the backend pool update until the scale wave of %d node changes ends", t.added+t.deleted))
+	}
+	t.services[key] = nodeScaleWaveServiceInfo{clusterName: clusterName, nodes: nodes}
+	return true
+}
+
+// endWave ends the current wave if no node has changed for the settle duration or the updates
+// have been deferred for too long, and returns the deferred services.
+func (t *nodeScaleWaveTracker) endWave(now time.Time) (map[string]nodeScaleWaveServiceInfo, int, int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.inWave || now.Sub(t.lastChange) < t.settle && now.Sub(t.waveStart) < maxNodeScaleWaveDeferral {
+		return nil, 0, 0
+	}
+
+	services, added, deleted := t.services, t.added, t.deleted
+	for key, info := range services {
+		info.added, info.deleted = added, deleted
+		services[key] = info
+	}
+	t.inWave = false
+	t.added, t.deleted = 0, 0
+	t.services = make(map[string]nodeScaleWaveServiceInfo)
+	if now.Sub(t.lastChange) < t.settle {
+		// the wave goes on after the deferred updates are applied
+		t.inWave = true
+		t.waveStart = now
+	}
+	return services, added, deleted
+}
+
+// takeRetries returns the services whose deferred updates failed. They are retried with the
+// updates deferred by a wave when it ends, or after nodeScaleWaveRetryInterval out of waves.
+func (t *nodeScaleWaveTracker) takeRetries(now time.Time, waveEnded bool) map[string]nodeScaleWaveServiceInfo {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.failed) == 0 || !waveEnded && (t.inWave || now.Before(t.retryAfter)) {
+		return nil
+	}
+
+	failed := t.failed
+	t.failed = make(map[string]nodeScaleWaveServiceInfo)
+	return failed
+}
+
+// retryLater keeps the failed deferred update of the service to be retried.
+func (t *nodeScaleWaveTracker) retryLater(key string, info nodeScaleWaveServiceInfo, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.failed[key] = info
+	t.retryAfter = now.Add(nodeScaleWaveRetryInterval)
+}
+
+// applyDeferredUpdates updates the load balancers of the services deferred during the wave
+// once the wave ends, and retries the failed ones.
+func (t *nodeScaleWaveTracker) applyDeferredUpdates(ctx context.Context, now time.Time) {
+	services, _, _ := t.endWave(now)
+	for key, info := range t.takeRetries(now, len(services) > 0) {
+		if services == nil {
+			services = make(map[string]nodeScaleWaveServiceInfo)
+		}
+		// the update deferred by the last wave has the latest nodes
+		if _, found := services[key]; !found {
+			services[key] = info
+		}
+	}
+	if len(services) == 0 {
+		return
+	}
+
+	logger := log.FromContextOrBackground(ctx).WithName("nodeScaleWaveTracker")
+	logger.Info("Updating the load balancers of the services deferred by node scale waves", "services", len(services))
+	updated := 0
+	for key, info := range services {
+		service, serviceExists, err := t.az.getLatestService(key, true)
+		if err != nil {
+			logger.Error(err, "Failed to get the service", "service", key)
+			t.retryLater(key, info, now)
+			continue
+		}
+		if !serviceExists {
+			continue
+		}
+
+		nodes := t.az.getLatestNodes(info.nodes)
+		if err := t.az.UpdateLoadBalancer(context.WithValue(ctx, nodeScaleWaveUpdateKey{}, true), info.clusterName, service, nodes); err != nil {
+			logger.Error(err, "Failed to update the load balancer of the service", "service", key)
+			t.az.Event(service, v1.EventTypeWarning, "NodeScaleWaveSyncFailed",
+				fmt.Sprintf("Failed to update the backend pools after the scale wave, will retry: %v", err))
+			t.retryLater(key, info, now)
+			continue
+		}
+		updated++
+		logger.V(2).Info("Updated the load balancer of the service", "service", key, "nodes", len(nodes), "updated", updated, "services", len(services))
+		t.az.Event(service, v1.EventTypeNormal, "NodeScaleWaveSynced",
+			fmt.Sprintf("Updated the backend pools with %d nodes after the scale wave of %d node additions and %d node deletions", len(nodes), info.added, info.deleted))
+	}
+}
+
+func (t *nodeScaleWaveTracker) run(ctx context.Context) {
+	logger := log.FromContextOrBackground(ctx).WithName("nodeScaleWaveTracker")
+	logger.Info("Started")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		t.refreshScaleSetsOfNewNodes(ctx)
+		t.applyDeferredUpdates(ctx, time.Now())
+	}, nodeScaleWaveCheckInterval)
+	logger.Info("Stopped")
+}
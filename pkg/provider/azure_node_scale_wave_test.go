@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetclient/mock_virtualmachinescalesetclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetvmclient/mock_virtualmachinescalesetvmclient"
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+func newTestNodeScaleWaveTracker(threshold int) *nodeScaleWaveTracker {
+	az := &Cloud{eventRecorder: record.NewFakeRecorder(10)}
+	az.NodeScaleWaveThreshold = threshold
+	return newNodeScaleWaveTracker(az)
+}
+
+func TestNodeScaleWaveTrackerDetectsWave(t *testing.T) {
+	tracker := newTestNodeScaleWaveTracker(3)
+	assert.Equal(t, time.Minute, tracker.window)
+	assert.Equal(t, 30*time.Second, tracker.settle)
+
+	now := time.Now()
+	service := getTestService("service1", v1.ProtocolTCP, nil, false, 80)
+
+	// the changes out of the window do not make a wave
+	tracker.onNodeChange(true, now.Add(-2*time.Minute))
+	tracker.onNodeChange(true, now.Add(-10*time.Second))
+	tracker.onNodeChange(false, now.Add(-5*time.Second))
+	assert.False(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nil))
+
+	tracker.onNodeChange(true, now)
+	nodes := []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}}
+	assert.True(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nil))
+	assert.True(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nodes))
+	assert.Equal(t, 2, tracker.added)
+	assert.Equal(t, 1, tracker.deleted)
+	// the event is emitted once per service
+	assert.Len(t, tracker.az.eventRecorder.(*record.FakeRecorder).Events, 1)
+
+	// the deferred updates applied at the end of the wave are not deferred again
+	ctx := context.WithValue(context.Background(), nodeScaleWaveUpdateKey{}, true)
+	assert.False(t, tracker.deferUpdate(ctx, testClusterName, &service, nil))
+
+	tracker.onNodeChange(true, now.Add(10*time.Second))
+	services, _, _ := tracker.endWave(now.Add(30 * time.Second))
+	assert.Empty(t, services, "the wave should not end before it settles")
+
+	services, added, deleted := tracker.endWave(now.Add(40 * time.Second))
+	assert.Equal(t, map[string]nodeScaleWaveServiceInfo{
+		"default/service1": {clusterName: testClusterName, nodes: nodes, added: 3, deleted: 1},
+	}, services)
+	assert.Equal(t, 3, added)
+	assert.Equal(t, 1, deleted)
+	assert.False(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nil))
+}
+
+func TestNodeScaleWaveTrackerMaxDeferral(t *testing.T) {
+	tracker := newTestNodeScaleWaveTracker(1)
+	service := getTestService("service1", v1.ProtocolTCP, nil, false, 80)
+
+	now := time.Now()
+	tracker.onNodeChange(true, now)
+	assert.True(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nil))
+
+	// the nodes keep changing, the deferred updates are applied after the max deferral
+	tracker.onNodeChange(true, now.Add(maxNodeScaleWaveDeferral))
+	services, added, _ := tracker.endWave(now.Add(maxNodeScaleWaveDeferral))
+	assert.Len(t, services, 1)
+	assert.Equal(t, 2, added)
+
+	// and the wave goes on
+	assert.True(t, tracker.deferUpdate(context.Background(), testClusterName, &service, nil))
+}
+
+func TestNodeScaleWaveTrackerOnNodeAdd(t *testing.T) {
+	tracker := newTestNodeScaleWaveTracker(1)
+	now := time.Now()
+
+	// the nodes listed by the informer when it starts are not added by a scale-up
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}}, now)
+	assert.False(t, tracker.inWave)
+
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)}}, now)
+	assert.True(t, tracker.inWave)
+
+	var nilTracker *nodeScaleWaveTracker
+	nilTracker.onNodeAdd(&v1.Node{}, now)
+	nilTracker.onNodeChange(false, now)
+	assert.False(t, nilTracker.deferUpdate(context.Background(), testClusterName, &v1.Service{}, nil))
+}
+
+func TestNodeScaleWaveTrackerRefreshesScaleSetsOfNewNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	ss.VMSet = ss
+	ss.NodeScaleWaveThreshold = 2
+	tracker := newNodeScaleWaveTracker(ss.Cloud)
+
+	expectedVMSS := &armcompute.VirtualMachineScaleSet{
+		Name: ptr.To(testVMSSName),
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{},
+		},
+	}
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSClient.EXPECT().List(gomock.Any(), ss.ResourceGroup).Return([]*armcompute.VirtualMachineScaleSet{expectedVMSS}, nil).AnyTimes()
+	expectedVMSSVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, []string{"vmss000000", "vmss000001"}, "", false)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	// the new nodes of the scale set share one listing
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), ss.ResourceGroup, testVMSSName).Return(expectedVMSSVMs, nil).Times(1)
+
+	now := time.Now()
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "vmss000000", CreationTimestamp: metav1.NewTime(now)}}, now)
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "vmss000001", CreationTimestamp: metav1.NewTime(now)}}, now)
+	assert.True(t, tracker.inScaleWave())
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-node", CreationTimestamp: metav1.NewTime(now)}}, now)
+	assert.Equal(t, []string{"vmss000000", "vmss000001", "unmanaged-node"}, tracker.newNodes)
+
+	tracker.refreshScaleSetsOfNewNodes(context.Background())
+	assert.Empty(t, tracker.newNodes)
+	for _, nodeName := range []string{"vmss000000", "vmss000001"} {
+		_, err := ss.getVmssVM(context.Background(), nodeName, azcache.CacheReadTypeDefault)
+		assert.NoError(t, err)
+	}
+
+	// the scale set refreshed just now is not refreshed again
+	tracker.onNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "vmss000002", CreationTimestamp: metav1.NewTime(now)}}, now)
+	tracker.refreshScaleSetsOfNewNodes(context.Background())
+}
+
+func TestUpdateLoadBalancerDeferredDuringNodeScaleWave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.NodeScaleWaveThreshold = 1
+	az.nodeScaleWaveTracker = newNodeScaleWaveTracker(az)
+
+	service := getTestService("service1", v1.ProtocolTCP, nil, false, 80)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(&service))
+	az.serviceLister = corelisters.NewServiceLister(indexer)
+
+	now := time.Now()
+	az.nodeScaleWaveTracker.onNodeChange(true, now)
+	// no Azure API is called during the wave
+	assert.NoError(t, az.UpdateLoadBalancer(context.Background(), testClusterName, &service, nil))
+	assert.Contains(t, az.nodeScaleWaveTracker.services, "default/service1")
+
+	// the service deleted during the wave is not updated
+	assert.NoError(t, indexer.Delete(&service))
+	az.nodeScaleWaveTracker.applyDeferredUpdates(context.Background(), now.Add(time.Minute))
+	assert.False(t, az.nodeScaleWaveTracker.inWave)
+	assert.Empty(t, az.nodeScaleWaveTracker.services)
+}
+
+func TestNodeScaleWaveTrackerRetriesFailedUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.NodeScaleWaveThreshold = 1
+	az.nodeScaleWaveTracker = newNodeScaleWaveTracker(az)
+	tracker := az.nodeScaleWaveTracker
+
+	service := getTestService("service1", v1.ProtocolTCP, nil, false, 80)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(&service))
+	az.serviceLister = corelisters.NewServiceLister(indexer)
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+
+	now := time.Now()
+	tracker.onNodeChange(true, now)
+	assert.True(t, tracker.inScaleWave())
+	assert.NoError(t, az.UpdateLoadBalancer(context.Background(), testClusterName, &service, nil))
+
+	// the failed update is kept to be retried
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return(nil, errors.New("list error"))
+	tracker.applyDeferredUpdates(context.Background(), now.Add(time.Minute))
+	assert.False(t, tracker.inScaleWave())
+	assert.Contains(t, tracker.failed, "default/service1")
+
+	// and retried after the retry interval
+	tracker.applyDeferredUpdates(context.Background(), now.Add(time.Minute+nodeScaleWaveCheckInterval))
+	assert.Contains(t, tracker.failed, "default/service1")
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return(nil, errors.New("list error"))
+	tracker.applyDeferredUpdates(context.Background(), now.Add(time.Minute+nodeScaleWaveRetryInterval))
+	assert.Contains(t, tracker.failed, "default/service1")
+
+	// during a wave, the retries wait for the end of the wave
+	tracker.onNodeChange(true, now.Add(2*time.Minute))
+	tracker.applyDeferredUpdates(context.Background(), now.Add(2*time.Minute+nodeScaleWaveCheckInterval))
+	assert.Contains(t, tracker.failed, "default/service1")
+
+	// the retried service deleted meanwhile is dropped
+	assert.NoError(t, indexer.Delete(&service))
+	tracker.applyDeferredUpdates(context.Background(), now.Add(3*time.Minute))
+	assert.Empty(t, tracker.failed)
+}
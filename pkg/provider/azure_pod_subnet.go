@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/utils/ptr"
+)
+
+// GetPodSubnetAddressPrefixes returns the address prefixes of the pod subnet in the virtual network of the cluster.
+// The node CIDRs are allocated from the whole subnet, so it can't be delegated, e.g. to Azure CNI, or have IP
+// configurations of NICs or other services.
+func (az *Cloud) GetPodSubnetAddressPrefixes(ctx context.Context, subnetName string) ([]string, error) {
+	rg := az.ResourceGroup
+	if len(az.VnetResourceGroup) > 0 {
+		rg = az.VnetResourceGroup
+	}
+	subnet, err := az.subnetRepo.Get(ctx, rg, az.VnetName, subnetName)
+	if err != nil {
+		return nil, err
+	}
+	if subnet == nil || subnet.Properties == nil {
+		return nil, fmt.Errorf("pod subnet %s in virtual network %s has no properties", subnetName, az.VnetName)
+	}
+	if len(subnet.Properties.Delegations) > 0 {
+		return nil, fmt.Errorf("pod subnet %s is delegated to %s and can't be dedicated to the node cidrs", subnetName, ptr.Deref(subnet.Properties.Delegations[0].Name, ""))
+	}
+	if len(subnet.Properties.IPConfigurations) > 0 {
+		return nil, fmt.Errorf("pod subnet %s has %d IP configurations and can't be dedicated to the node cidrs", subnetName, len(subnet.Properties.IPConfigurations))
+	}
+
+	var prefixes []string
+	if prefix := ptr.Deref(subnet.Properties.AddressPrefix, ""); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	for _, prefix := range subnet.Properties.AddressPrefixes {
+		if p := ptr.Deref(prefix, ""); p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes, nil
+}
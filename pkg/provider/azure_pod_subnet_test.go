@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/subnet"
+)
+
+func TestGetPodSubnetAddressPrefixes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.VnetResourceGroup = "vnet-rg"
+	subnetRepo := az.subnetRepo.(*subnet.MockRepository)
+	subnetRepo.EXPECT().Get(gomock.Any(), "vnet-rg", az.VnetName, "pods").Return(&armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{ptr.To("10.1.0.0/16"), ptr.To("fd00:1::/64")},
+		},
+	}, nil)
+
+	prefixes, err := az.GetPodSubnetAddressPrefixes(context.TODO(), "pods")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.1.0.0/16", "fd00:1::/64"}, prefixes)
+
+	// The subnets not dedicated to the node cidrs should be rejected.
+	subnetRepo.EXPECT().Get(gomock.Any(), "vnet-rg", az.VnetName, "cni").Return(&armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: ptr.To("10.2.0.0/16"),
+			Delegations:   []*armnetwork.Delegation{{Name: ptr.To("aks")}},
+		},
+	}, nil)
+	_, err = az.GetPodSubnetAddressPrefixes(context.TODO(), "cni")
+	assert.EqualError(t, err, "pod subnet cni is delegated to aks and can't be dedicated to the node cidrs")
+
+	subnetRepo.EXPECT().Get(gomock.Any(), "vnet-rg", az.VnetName, "used").Return(&armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix:    ptr.To("10.3.0.0/16"),
+			IPConfigurations: []*armnetwork.IPConfiguration{{ID: ptr.To("ipconfig")}},
+		},
+	}, nil)
+	_, err = az.GetPodSubnetAddressPrefixes(context.TODO(), "used")
+	assert.EqualError(t, err, "pod subnet used has 1 IP configurations and can't be dedicated to the node cidrs")
+}
@@ -34,6 +34,7 @@ import (
 
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/features"
 	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
 	fnutil "sigs.k8s.io/cloud-provider-azure/pkg/util/collectionutil"
 )
@@ -47,6 +48,14 @@ func (az *Cloud) reconcilePrivateLinkService(
 	fipConfig *armnetwork.FrontendIPConfiguration,
 	wantPLS bool,
 ) (bool /*deleted PLS*/, error) {
+	if !az.featureEnabled(features.PrivateLinkService) {
+		klog.V(2).Infof("reconcilePrivateLinkService: feature gate %s is disabled, skip reconcilePrivateLinkService for service(%s)", features.PrivateLinkService, getServiceName(service))
+		if wantPLS && serviceRequiresPLS(service) {
+			az.Event(service, v1.EventTypeWarning, "PrivateLinkServiceDisabled", fmt.Sprintf("The private link service is not reconciled as feature gate %s is disabled", features.PrivateLinkService))
+		}
+		return false, nil
+	}
+
 	isinternal := requiresInternalLoadBalancer(service)
 	_, _, fipIPVersion := az.serviceOwnsFrontendIP(ctx, fipConfig, service)
 	serviceName := getServiceName(service)
@@ -86,7 +95,7 @@ func (az *Cloud) reconcilePrivateLinkService(
 
 	if createPLS {
 		// Firstly, make sure it's internal service
-		if !isinternal && !consts.IsK8sServiceDisableLoadBalancerFloatingIP(service) {
+		if !isinternal && !az.isFloatingIPDisabled(service) {
 			return false, fmt.Errorf("reconcilePrivateLinkService for service(%s): service requiring private link service must be internal or disable floating ip", serviceName)
 		}
 
@@ -588,7 +597,7 @@ func (az *Cloud) reconcilePLSTags(
 	clusterName *string,
 	service *v1.Service,
 ) bool {
-	configTags := parseTags(az.Tags, az.TagsMap)
+	configTags := az.getServiceResourceTags(service)
 	serviceName := getServiceName(service)
 
 	if existingPLS.Tags == nil {
@@ -29,10 +29,12 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/features"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/privatelinkservice"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/subnet"
 )
@@ -406,6 +408,33 @@ func TestReconcilePrivateLinkService(t *testing.T) {
 	}
 }
 
+func TestReconcilePrivateLinkServiceFeatureGateDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	az.eventRecorder = recorder
+	featureGates, err := features.New(map[string]bool{string(features.PrivateLinkService): false})
+	assert.NoError(t, err)
+	az.featureGates = featureGates
+
+	service := getTestServiceWithAnnotation("test", map[string]string{
+		consts.ServiceAnnotationPLSCreation:          "true",
+		consts.ServiceAnnotationLoadBalancerInternal: "true",
+	}, false, 80)
+	fipConfig := &armnetwork.FrontendIPConfiguration{Name: ptr.To("fipConfig"), ID: ptr.To("fipConfigID")}
+
+	// The private link service is neither read, created nor deleted.
+	for _, wantPLS := range []bool{true, false} {
+		deleted, err := az.reconcilePrivateLinkService(context.TODO(), testClusterName, &service, fipConfig, wantPLS)
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+	}
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "PrivateLinkServiceDisabled")
+}
+
 func TestGetPLSResourceGroup(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1520,6 +1549,25 @@ func TestReconcilePLSTags(t *testing.T) {
 		assert.True(t, changed)
 		assert.Equal(t, expectedPLS, pls)
 	})
+
+	t.Run("reconcilePLSTags should apply the tags in the service annotation", func(t *testing.T) {
+		service.Annotations = map[string]string{consts.ServiceAnnotationAzureResourceTags: "team=payments,a=e"}
+		defer func() { service.Annotations = nil }()
+		expectedPLS := armnetwork.PrivateLinkService{
+			Tags: map[string]*string{
+				consts.ClusterNameTagKey:  ptr.To("testCluster1"),
+				consts.OwnerServiceTagKey: ptr.To("default/svc"),
+				"foo":                     ptr.To("bar"),
+				"a":                       ptr.To("e"),
+				"a=b":                     ptr.To("c=d"),
+				"Y":                       ptr.To("zz"),
+				"team":                    ptr.To("payments"),
+			},
+		}
+		changed := cloud.reconcilePLSTags(&pls, &clusterName, &service)
+		assert.True(t, changed)
+		assert.Equal(t, expectedPLS, pls)
+	})
 }
 
 func TestGetPLSSubnetName(t *testing.T) {
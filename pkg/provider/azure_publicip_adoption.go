@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+// validateUserAssignedPublicIP checks that the user-assigned public IP referenced by the service can be used by
+// the frontend of its load balancer. The mismatches would otherwise only be reported by ARM when the load balancer
+// is updated, or be "fixed" by updating the public IP which doesn't belong to the cluster.
+func (az *Cloud) validateUserAssignedPublicIP(service *v1.Service, pip *armnetwork.PublicIPAddress, isIPv6 bool) error {
+	pipName := ptr.Deref(pip.Name, "")
+
+	if location := ptr.Deref(pip.Location, ""); location != "" && !strings.EqualFold(location, az.Location) {
+		return fmt.Errorf("public IP %s is in location %s, but the load balancer is in location %s", pipName, location, az.Location)
+	}
+
+	if pip.SKU != nil && pip.SKU.Name != nil {
+		lbSKU, pipSKU := consts.LoadBalancerSKUBasic, string(*pip.SKU.Name)
+		if az.UseStandardLoadBalancer() {
+			lbSKU = consts.LoadBalancerSKUStandard
+		}
+		if !strings.EqualFold(lbSKU, pipSKU) {
+			return fmt.Errorf("public IP %s of SKU %s cannot be used by the load balancer of SKU %s", pipName, pipSKU, lbSKU)
+		}
+	}
+
+	if pip.Properties != nil && pip.Properties.PublicIPAddressVersion != nil {
+		ipVersion := armnetwork.IPVersionIPv4
+		if isIPv6 {
+			ipVersion = armnetwork.IPVersionIPv6
+		}
+		if !strings.EqualFold(string(*pip.Properties.PublicIPAddressVersion), string(ipVersion)) {
+			return fmt.Errorf("public IP %s is an %s address, but an %s address is required", pipName, *pip.Properties.PublicIPAddressVersion, ipVersion)
+		}
+	}
+
+	// a zonal public IP can only be used by the services pinned to the same zone
+	if zone := getServiceZone(service); zone != "" && len(pip.Zones) == 1 && !strings.EqualFold(ptr.Deref(pip.Zones[0], ""), zone) {
+		return fmt.Errorf("public IP %s is in zone %s, but the service is pinned to zone %s", pipName, ptr.Deref(pip.Zones[0], ""), zone)
+	}
+
+	if pip.Properties != nil {
+		if pip.Properties.NatGateway != nil {
+			return fmt.Errorf("public IP %s is in use by NAT gateway %s", pipName, ptr.Deref(pip.Properties.NatGateway.ID, ""))
+		}
+		if pip.Properties.IPConfiguration != nil {
+			ipConfigurationID := ptr.Deref(pip.Properties.IPConfiguration.ID, "")
+			if !strings.Contains(strings.ToLower(ipConfigurationID), "/providers/microsoft.network/loadbalancers/") {
+				return fmt.Errorf("public IP %s is in use by %s", pipName, ipConfigurationID)
+			}
+		}
+	}
+	return nil
+}
+
+// adoptUserAssignedPublicIP validates the user-assigned public IP and records the service in its tags. The
+// tags are only for tracking, so failing to update them, e.g. without the write access to the public IP, is
+// not an error.
+func (az *Cloud) adoptUserAssignedPublicIP(service *v1.Service, pipResourceGroup string, pip *armnetwork.PublicIPAddress, isIPv6 bool) error {
+	serviceName := getServiceName(service)
+	logger := log.Background().WithName("adoptUserAssignedPublicIP").WithValues("service", serviceName, "pip", ptr.Deref(pip.Name, ""))
+	if err := az.validateUserAssignedPublicIP(service, pip, isIPv6); err != nil {
+		logger.Error(err, "The user-assigned pip cannot be used")
+		az.Event(service, v1.EventTypeWarning, "PublicIPAdoptionFailed", err.Error())
+		return err
+	}
+
+	if bindServiceToAdoptedPIP(pip, serviceName) {
+		logger.V(2).Info("Tagging the user-assigned pip")
+		if err := az.CreateOrUpdatePIP(service, pipResourceGroup, pip); err != nil {
+			logger.Error(err, "Failed to tag the user-assigned pip")
+		}
+	}
+	return nil
+}
+
+// untrackUserAssignedPublicIPs removes the service from the tags of the user-assigned public IPs of the IP
+// family which are no longer used by the service. Like adoptUserAssignedPublicIP, the failures are only logged.
+func (az *Cloud) untrackUserAssignedPublicIPs(service *v1.Service, pipResourceGroup string, pips []*armnetwork.PublicIPAddress, wantPublicIP bool, desiredPipName string, isIPv6 bool) {
+	serviceName := getServiceName(service)
+	logger := log.Background().WithName("untrackUserAssignedPublicIPs").WithValues("service", serviceName)
+	for _, pip := range pips {
+		if pip.Properties != nil && pip.Properties.PublicIPAddressVersion != nil &&
+			(*pip.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv6) != isIPv6 {
+			continue
+		}
+		pipName := ptr.Deref(pip.Name, "")
+		if wantPublicIP && (pipName == desiredPipName || !isIPv6 && isServiceAdditionalPIPName(service, pipName)) {
+			continue
+		}
+
+		// only the user-assigned public IPs have the tag
+		if unbindServiceFromAdoptedPIP(pip, serviceName) {
+			logger.V(2).Info("Untagging the user-assigned pip", "pip", pipName)
+			rg := pipResourceGroup
+			if id := ptr.Deref(pip.ID, ""); id != "" {
+				if idRG, err := getPIPRGFromID(strings.ToLower(id)); err == nil {
+					rg = idRG
+				}
+			}
+			if err := az.CreateOrUpdatePIP(service, rg, pip); err != nil {
+				logger.Error(err, "Failed to untag the user-assigned pip", "pip", pipName)
+			}
+		}
+	}
+}
+
+// bindServiceToAdoptedPIP adds the service to the adopted-by-services tag of the user-assigned public IP.
+func bindServiceToAdoptedPIP(pip *armnetwork.PublicIPAddress, serviceName string) bool {
+	serviceNames := parsePIPServiceTag(pip.Tags[consts.AdoptedByServicesKey])
+	for _, name := range serviceNames {
+		if strings.EqualFold(name, serviceName) {
+			return false
+		}
+	}
+	if pip.Tags == nil {
+		pip.Tags = make(map[string]*string)
+	}
+	pip.Tags[consts.AdoptedByServicesKey] = ptr.To(strings.Join(append(serviceNames, serviceName), ","))
+	return true
+}
+
+// unbindServiceFromAdoptedPIP removes the service from the adopted-by-services tag of the user-assigned public
+// IP, and the tag itself after the last service. It returns true if the tags are changed.
+func unbindServiceFromAdoptedPIP(pip *armnetwork.PublicIPAddress, serviceName string) bool {
+	serviceNames := parsePIPServiceTag(pip.Tags[consts.AdoptedByServicesKey])
+	for i, name := range serviceNames {
+		if !strings.EqualFold(name, serviceName) {
+			continue
+		}
+		serviceNames = append(serviceNames[:i], serviceNames[i+1:]...)
+		if len(serviceNames) == 0 {
+			delete(pip.Tags, consts.AdoptedByServicesKey)
+		} else {
+			pip.Tags[consts.AdoptedByServicesKey] = ptr.To(strings.Join(serviceNames, ","))
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func newTestUserAssignedPIP() *armnetwork.PublicIPAddress {
+	return &armnetwork.PublicIPAddress{
+		Name:     ptr.To("pip"),
+		ID:       ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+		Location: ptr.To("westus"),
+		SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			PublicIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv4),
+			IPAddress:              ptr.To("1.2.3.4"),
+		},
+	}
+}
+
+func TestValidateUserAssignedPublicIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tc := range []struct {
+		desc          string
+		mutate        func(pip *armnetwork.PublicIPAddress)
+		annotations   map[string]string
+		isIPv6        bool
+		expectedError string
+	}{
+		{
+			desc: "compatible public IP",
+		},
+		{
+			desc:          "public IP in another location",
+			mutate:        func(pip *armnetwork.PublicIPAddress) { pip.Location = ptr.To("eastus") },
+			expectedError: "public IP pip is in location eastus, but the load balancer is in location westus",
+		},
+		{
+			desc:          "basic public IP",
+			mutate:        func(pip *armnetwork.PublicIPAddress) { pip.SKU.Name = to.Ptr(armnetwork.PublicIPAddressSKUNameBasic) },
+			expectedError: "public IP pip of SKU Basic cannot be used by the load balancer of SKU standard",
+		},
+		{
+			desc:          "public IP of another IP version",
+			isIPv6:        true,
+			expectedError: "public IP pip is an IPv4 address, but an IPv6 address is required",
+		},
+		{
+			desc:        "zonal public IP in the zone of the service",
+			mutate:      func(pip *armnetwork.PublicIPAddress) { pip.Zones = []*string{ptr.To("1")} },
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerZone: "1"},
+		},
+		{
+			desc:        "zone-redundant public IP",
+			mutate:      func(pip *armnetwork.PublicIPAddress) { pip.Zones = []*string{ptr.To("1"), ptr.To("2"), ptr.To("3")} },
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerZone: "1"},
+		},
+		{
+			desc:          "zonal public IP in another zone",
+			mutate:        func(pip *armnetwork.PublicIPAddress) { pip.Zones = []*string{ptr.To("2")} },
+			annotations:   map[string]string{consts.ServiceAnnotationLoadBalancerZone: "1"},
+			expectedError: "public IP pip is in zone 2, but the service is pinned to zone 1",
+		},
+		{
+			desc: "public IP used by a load balancer",
+			mutate: func(pip *armnetwork.PublicIPAddress) {
+				pip.Properties.IPConfiguration = &armnetwork.IPConfiguration{
+					ID: ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb/frontendIPConfigurations/fip"),
+				}
+			},
+		},
+		{
+			desc: "public IP used by a network interface",
+			mutate: func(pip *armnetwork.PublicIPAddress) {
+				pip.Properties.IPConfiguration = &armnetwork.IPConfiguration{
+					ID: ptr.To("/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic/ipConfigurations/ipconfig"),
+				}
+			},
+			expectedError: "public IP pip is in use by /subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic/ipConfigurations/ipconfig",
+		},
+		{
+			desc: "public IP used by a NAT gateway",
+			mutate: func(pip *armnetwork.PublicIPAddress) {
+				pip.Properties.NatGateway = &armnetwork.NatGateway{ID: ptr.To("natgw")}
+			},
+			expectedError: "public IP pip is in use by NAT gateway natgw",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := GetTestCloud(ctrl)
+			az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+			service := getTestService("test1", v1.ProtocolTCP, tc.annotations, false, 80)
+			pip := newTestUserAssignedPIP()
+			if tc.mutate != nil {
+				tc.mutate(pip)
+			}
+
+			err := az.validateUserAssignedPublicIP(&service, pip, tc.isIPv6)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestAdoptUserAssignedPublicIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	recorder := record.NewFakeRecorder(1)
+	az.eventRecorder = recorder
+	service := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+
+	// the incompatible public IP is reported by an event
+	pip := newTestUserAssignedPIP()
+	pip.SKU.Name = to.Ptr(armnetwork.PublicIPAddressSKUNameBasic)
+	assert.Error(t, az.adoptUserAssignedPublicIP(&service, "rg", pip, false))
+	event := <-recorder.Events
+	assert.True(t, strings.HasPrefix(event, "Warning PublicIPAdoptionFailed "), event)
+
+	// the service is recorded in the tags, and failing to update them is not an error
+	pip = newTestUserAssignedPIP()
+	mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "pip", gomock.Any()).Return(nil, &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"})
+	assert.NoError(t, az.adoptUserAssignedPublicIP(&service, "rg", pip, false))
+	assert.Equal(t, "default/test1", ptr.Deref(pip.Tags[consts.AdoptedByServicesKey], ""))
+
+	// the public IP is not updated again
+	assert.NoError(t, az.adoptUserAssignedPublicIP(&service, "rg", pip, false))
+}
+
+func TestUntrackUserAssignedPublicIPs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	service := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	newPIP := func(name, services string) *armnetwork.PublicIPAddress {
+		pip := newTestUserAssignedPIP()
+		pip.Name = ptr.To(name)
+		pip.ID = ptr.To("/subscriptions/subscription/resourceGroups/pip-rg/providers/Microsoft.Network/publicIPAddresses/" + name)
+		pip.Tags = map[string]*string{consts.AdoptedByServicesKey: ptr.To(services)}
+		return pip
+	}
+	used, shared, unused := newPIP("used", "default/test1"), newPIP("shared", "default/test2,default/test1"), newPIP("unused", "default/test1")
+
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "pip-rg", "shared", gomock.Any()).Return(nil, nil)
+	mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "pip-rg", "unused", gomock.Any()).Return(nil, nil)
+	az.untrackUserAssignedPublicIPs(&service, "rg", []*armnetwork.PublicIPAddress{used, shared, unused}, true, "used", false)
+
+	assert.Equal(t, "default/test1", ptr.Deref(used.Tags[consts.AdoptedByServicesKey], ""))
+	assert.Equal(t, "default/test2", ptr.Deref(shared.Tags[consts.AdoptedByServicesKey], ""))
+	assert.NotContains(t, unused.Tags, consts.AdoptedByServicesKey)
+
+	// all the public IPs are untracked after the service is deleted
+	mockPIPClient.EXPECT().CreateOrUpdate(gomock.Any(), "pip-rg", "used", gomock.Any()).Return(nil, nil)
+	az.untrackUserAssignedPublicIPs(&service, "rg", []*armnetwork.PublicIPAddress{used, shared, unused}, false, "", false)
+	assert.NotContains(t, used.Tags, consts.AdoptedByServicesKey)
+}
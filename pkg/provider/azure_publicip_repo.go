@@ -53,7 +53,7 @@ func (az *Cloud) CreateOrUpdatePIP(service *v1.Service, pipResourceGroup string,
 
 	pipJSON, _ := json.Marshal(pip)
 	klog.Warningf("NetworkClientFactory.GetPublicIPAddressClient().CreateOrUpdate(%s, %s) failed: %s, PublicIP request: %s", pipResourceGroup, ptr.Deref(pip.Name, ""), rerr.Error(), string(pipJSON))
-	az.Event(service, v1.EventTypeWarning, "CreateOrUpdatePublicIPAddress", rerr.Error())
+	az.Event(service, v1.EventTypeWarning, "CreateOrUpdatePublicIPAddress", getAzureErrorEventMessage(rerr))
 
 	// Invalidate the cache because ETAG precondition mismatch.
 	var respError *azcore.ResponseError
@@ -82,7 +82,7 @@ func (az *Cloud) DeletePublicIP(service *v1.Service, pipResourceGroup string, pi
 	rerr := az.NetworkClientFactory.GetPublicIPAddressClient().Delete(ctx, pipResourceGroup, pipName)
 	if rerr != nil {
 		klog.Errorf("NetworkClientFactory.GetPublicIPAddressClient().Delete(%s) failed: %s", pipName, rerr.Error())
-		az.Event(service, v1.EventTypeWarning, "DeletePublicIPAddress", rerr.Error())
+		az.Event(service, v1.EventTypeWarning, "DeletePublicIPAddress", getAzureErrorEventMessage(rerr))
 
 		if strings.Contains(rerr.Error(), consts.CannotDeletePublicIPErrorMessageCode) {
 			klog.Warningf("DeletePublicIP for public IP %s failed with error %v, this is because other resources are referencing the public IP. The deletion of the service will continue.", pipName, rerr)
@@ -0,0 +1,44 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+// newClientFactoryConfig returns the config of the client factory in the subscription with the rate limits of
+// the cloud config, and keeps it so that the rate limits of the factory can be tuned with UpdateRateLimits.
+func (az *Cloud) newClientFactoryConfig(subscriptionID string) *azclient.ClientFactoryConfig {
+	factoryConfig := &azclient.ClientFactoryConfig{
+		CloudProviderRateLimitConfig: az.CloudProviderRateLimitConfig,
+		SubscriptionID:               subscriptionID,
+	}
+	az.clientFactoryConfigs = append(az.clientFactoryConfigs, factoryConfig)
+	return factoryConfig
+}
+
+// UpdateRateLimits applies the rate limits of the config to the rate limiters of the ARM clients in place.
+func (az *Cloud) UpdateRateLimits(config *azureconfig.Config) {
+	for _, factoryConfig := range az.clientFactoryConfigs {
+		factoryConfig.UpdateRateLimitConfig(config.CloudProviderRateLimitConfig)
+	}
+	az.CloudProviderRateLimitConfig = config.CloudProviderRateLimitConfig
+	klog.Infof("UpdateRateLimits: updated the rate limits of %d client factories", len(az.clientFactoryConfigs))
+}
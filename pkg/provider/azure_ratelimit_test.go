@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/zone"
+)
+
+func TestUpdateRateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newConfig := func(loadBalancerQPS float32) *config.Config {
+		c := &config.Config{}
+		c.TenantID = "tenant-id"
+		c.SubscriptionID = "subscription-id"
+		c.AADClientID = "client-id"
+		c.AADClientSecret = "secret"
+		c.CloudProviderRateLimit = true
+		c.CloudProviderRateLimitQPS = 10
+		c.CloudProviderRateLimitBucket = 100
+		c.LoadBalancerRateLimit = &ratelimit.Config{
+			CloudProviderRateLimit:    true,
+			CloudProviderRateLimitQPS: loadBalancerQPS,
+		}
+		return c
+	}
+
+	az := GetTestCloud(ctrl)
+	zoneMock := az.zoneRepo.(*zone.MockRepository)
+	zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+	az.AuthProvider = nil
+	az.ComputeClientFactory = nil
+	az.NetworkClientFactory = nil
+	assert.NoError(t, az.InitializeCloudFromConfig(context.Background(), newConfig(20), false, true))
+	assert.Len(t, az.clientFactoryConfigs, 2)
+	for _, factoryConfig := range az.clientFactoryConfigs {
+		assert.Equal(t, float32(20), factoryConfig.GetRateLimitConfig("loadBalancerRateLimit").CloudProviderRateLimitQPS)
+		assert.Equal(t, 100, factoryConfig.GetRateLimitConfig("loadBalancerRateLimit").CloudProviderRateLimitBucket)
+		assert.Equal(t, float32(10), factoryConfig.GetRateLimitConfig("securityGroupRateLimit").CloudProviderRateLimitQPS)
+	}
+
+	// the rate limits are tuned without re-initializing the cloud
+	assert.NoError(t, az.RotateCredentials(newConfig(50)))
+	assert.Equal(t, float32(50), az.LoadBalancerRateLimit.CloudProviderRateLimitQPS)
+	for _, factoryConfig := range az.clientFactoryConfigs {
+		assert.Equal(t, float32(50), factoryConfig.GetRateLimitConfig("loadBalancerRateLimit").CloudProviderRateLimitQPS)
+	}
+}
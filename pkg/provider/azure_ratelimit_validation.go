@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	azureconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+const routeTableRateLimitClientName = "routeTableRateLimit"
+
+// validateRateLimitConfiguration validates the rate limits and the backoff of the config. The invalid settings
+// are rejected, and the pathological ones, which would only show up as slowness, are logged as warnings, or
+// rejected as well if StrictRateLimitValidation is set. It expects the backoff defaults to be set.
+func validateRateLimitConfiguration(config *azureconfig.Config) error {
+	if err := validateRateLimits(config); err != nil {
+		return err
+	}
+	return validateBackoff(config)
+}
+
+// validateRateLimits validates the rate limits of the default and each client config.
+func validateRateLimits(config *azureconfig.Config) error {
+	clientNames := append([]string{""}, config.CloudProviderRateLimitConfig.ClientNames()...)
+	for _, clientName := range clientNames {
+		rateLimit := config.CloudProviderRateLimitConfig.GetRateLimitConfig(clientName)
+		if !rateLimit.CloudProviderRateLimit {
+			continue
+		}
+		name := "the default rate limit"
+		if clientName != "" {
+			name = clientName
+		}
+		if rateLimit.CloudProviderRateLimitQPS < 0 || rateLimit.CloudProviderRateLimitQPSWrite < 0 {
+			return fmt.Errorf("%s cannot have negative QPS", name)
+		}
+		if rateLimit.CloudProviderRateLimitBucket < 0 || rateLimit.CloudProviderRateLimitBucketWrite < 0 {
+			return fmt.Errorf("%s cannot have negative bucket size", name)
+		}
+		if rateLimit.CloudProviderRateLimitQPS == 0 || rateLimit.CloudProviderRateLimitBucket == 0 {
+			if err := reportRateLimitIssue(config, "%s has zero read QPS or bucket size, which rejects all the read requests", name); err != nil {
+				return err
+			}
+		}
+		if rateLimit.CloudProviderRateLimitQPSWrite == 0 || rateLimit.CloudProviderRateLimitBucketWrite == 0 {
+			if err := reportRateLimitIssue(config, "%s has zero write QPS or bucket size, which rejects all the write requests", name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// each route table shard is updated once every route update interval, all at the same time
+	routeTableRateLimit := config.CloudProviderRateLimitConfig.GetRateLimitConfig(routeTableRateLimitClientName)
+	if routeTableRateLimit.CloudProviderRateLimit && routeTableRateLimit.CloudProviderRateLimitQPSWrite > 0 && routeTableRateLimit.CloudProviderRateLimitBucketWrite > 0 {
+		shards := max(config.RouteTableShardCount, 1)
+		interval := config.RouteUpdateIntervalInSeconds
+		if interval <= 0 {
+			interval = consts.DefaultRouteUpdateIntervalInSeconds
+		}
+		if routeTableRateLimit.CloudProviderRateLimitBucketWrite < shards {
+			if err := reportRateLimitIssue(config, "the write bucket size %d of the route table rate limit is less than the %d route table shards updated at once",
+				routeTableRateLimit.CloudProviderRateLimitBucketWrite, shards); err != nil {
+				return err
+			}
+		}
+		if float64(routeTableRateLimit.CloudProviderRateLimitQPSWrite)*float64(interval) < float64(shards) {
+			if err := reportRateLimitIssue(config, "the write QPS %v of the route table rate limit cannot update the %d route table shards every %d seconds",
+				routeTableRateLimit.CloudProviderRateLimitQPSWrite, shards, interval); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateBackoff validates the retries of the ARM clients.
+func validateBackoff(config *azureconfig.Config) error {
+	if config.CloudProviderBackoffRetries < 0 {
+		return fmt.Errorf("cloudProviderBackoffRetries %d cannot be negative", config.CloudProviderBackoffRetries)
+	}
+	if config.CloudProviderBackoffDuration < 0 {
+		return fmt.Errorf("cloudProviderBackoffDuration %d cannot be negative", config.CloudProviderBackoffDuration)
+	}
+	return nil
+}
+
+// reportRateLimitIssue returns the issue as an error if StrictRateLimitValidation is set, or logs it as a warning.
+func reportRateLimitIssue(config *azureconfig.Config, format string, args ...interface{}) error {
+	if config.StrictRateLimitValidation {
+		return fmt.Errorf(format, args...)
+	}
+	klog.Warningf("validateRateLimitConfiguration: "+format, args...)
+	return nil
+}
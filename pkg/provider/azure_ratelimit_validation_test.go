@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+)
+
+func TestValidateRateLimitConfiguration(t *testing.T) {
+	newConfig := func(strict bool) *config.Config {
+		c := &config.Config{StrictRateLimitValidation: strict}
+		c.CloudProviderRateLimit = true
+		c.CloudProviderRateLimitQPS = 10
+		c.CloudProviderRateLimitBucket = 100
+		c.CloudProviderRateLimitQPSWrite = 1
+		c.CloudProviderRateLimitBucketWrite = 10
+		c.CloudProviderBackoff = true
+		c.CloudProviderBackoffRetries = 6
+		c.CloudProviderBackoffDuration = 5
+		return c
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		modify        func(c *config.Config)
+		expectedErr   string
+		expectedIssue bool
+	}{
+		{
+			desc:   "valid rate limits and backoff",
+			modify: func(_ *config.Config) {},
+		},
+		{
+			desc: "disabled rate limits are not validated",
+			modify: func(c *config.Config) {
+				c.CloudProviderRateLimit = false
+				c.CloudProviderRateLimitQPS = -1
+			},
+		},
+		{
+			desc: "negative QPS of a client",
+			modify: func(c *config.Config) {
+				c.LoadBalancerRateLimit = &ratelimit.Config{CloudProviderRateLimit: true, CloudProviderRateLimitQPSWrite: -1}
+			},
+			expectedErr: "loadBalancerRateLimit cannot have negative QPS",
+		},
+		{
+			desc: "negative bucket size of an entry",
+			modify: func(c *config.Config) {
+				c.Entries = map[string]*ratelimit.Config{"diskRateLimit": {CloudProviderRateLimit: true, CloudProviderRateLimitBucket: -1}}
+			},
+			expectedErr: "diskRateLimit cannot have negative bucket size",
+		},
+		{
+			desc: "zero write bucket size rejects all the writes",
+			modify: func(c *config.Config) {
+				c.CloudProviderRateLimitBucketWrite = 0
+			},
+			expectedIssue: true,
+		},
+		{
+			desc: "zero read QPS of a client inherited from the default",
+			modify: func(c *config.Config) {
+				c.CloudProviderRateLimit = false
+				c.CloudProviderRateLimitQPS = 0
+				c.SecurityGroupRateLimit = &ratelimit.Config{CloudProviderRateLimit: true, CloudProviderRateLimitBucket: 10, CloudProviderRateLimitQPSWrite: 1, CloudProviderRateLimitBucketWrite: 1}
+			},
+			expectedIssue: true,
+		},
+		{
+			desc: "route table bucket size less than the shards",
+			modify: func(c *config.Config) {
+				c.RouteTableShardCount = 3
+				c.RouteTableRateLimit = &ratelimit.Config{CloudProviderRateLimit: true, CloudProviderRateLimitBucketWrite: 2}
+			},
+			expectedIssue: true,
+		},
+		{
+			desc: "route table QPS below the shards every route update interval",
+			modify: func(c *config.Config) {
+				c.RouteTableShardCount = 4
+				c.RouteUpdateIntervalInSeconds = 10
+				c.RouteTableRateLimit = &ratelimit.Config{CloudProviderRateLimit: true, CloudProviderRateLimitQPSWrite: 0.2}
+			},
+			expectedIssue: true,
+		},
+		{
+			desc: "route table QPS enough for the shards every default route update interval",
+			modify: func(c *config.Config) {
+				c.RouteTableShardCount = 4
+				c.RouteTableRateLimit = &ratelimit.Config{CloudProviderRateLimit: true, CloudProviderRateLimitQPSWrite: 0.2}
+			},
+		},
+		{
+			desc: "negative backoff retries",
+			modify: func(c *config.Config) {
+				c.CloudProviderBackoffRetries = -1
+			},
+			expectedErr: "cloudProviderBackoffRetries -1 cannot be negative",
+		},
+		{
+			desc: "negative backoff duration",
+			modify: func(c *config.Config) {
+				c.CloudProviderBackoffDuration = -1
+			},
+			expectedErr: "cloudProviderBackoffDuration -1 cannot be negative",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newConfig(false)
+			tc.modify(c)
+			err := validateRateLimitConfiguration(c)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			c = newConfig(true)
+			tc.modify(c)
+			err = validateRateLimitConfiguration(c)
+			assert.Equal(t, tc.expectedIssue, err != nil, "unexpected error %v", err)
+		})
+	}
+}
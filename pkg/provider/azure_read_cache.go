@@ -0,0 +1,39 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/readcache"
+)
+
+// newReadCacheClientOption returns the client option putting a new read cache in front of the other policies
+// of the ARM clients, so that the GETs served by the cache don't consume the rate limits and the ARM quota.
+func (az *Cloud) newReadCacheClientOption() func(option *arm.ClientOptions) {
+	ttl := time.Duration(az.ARMReadCacheTTLInSeconds) * time.Second
+	if az.DisableAPICallCache {
+		ttl = 0
+	}
+	cache := readcache.NewPolicy(ttl)
+	return func(option *arm.ClientOptions) {
+		option.PerCallPolicies = append([]policy.Policy{cache}, option.PerCallPolicies...)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/readcache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/useragent"
+)
+
+func TestNewReadCacheClientOption(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.ARMReadCacheTTLInSeconds = 10
+	option := az.newReadCacheClientOption()
+
+	userAgentPolicy := useragent.NewCustomUserAgentPolicy("test")
+	networkOptions := &arm.ClientOptions{}
+	networkOptions.PerCallPolicies = []policy.Policy{userAgentPolicy}
+	computeOptions := &arm.ClientOptions{}
+	option(networkOptions)
+	option(computeOptions)
+
+	assert.Len(t, networkOptions.PerCallPolicies, 2)
+	assert.IsType(t, &readcache.Policy{}, networkOptions.PerCallPolicies[0])
+	assert.Equal(t, userAgentPolicy, networkOptions.PerCallPolicies[1])
+	// the clients share the same cache
+	assert.Same(t, networkOptions.PerCallPolicies[0], computeOptions.PerCallPolicies[0])
+}
@@ -19,11 +19,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/netip"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	cloudprovider "k8s.io/cloud-provider"
@@ -34,6 +38,11 @@ import (
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace/attributes"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
 )
 
 var _ cloudprovider.Routes = (*Cloud)(nil)
@@ -48,15 +57,25 @@ const (
 	routeOperationAdd             routeOperation = "add"
 	routeOperationDelete          routeOperation = "delete"
 	routeTableOperationUpdateTags routeOperation = "updateRouteTableTags"
+
+	// routeTableSubnetCacheTTL is the TTL of the address prefixes of the subnets of the route tables.
+	routeTableSubnetCacheTTL = 10 * time.Minute
+	// routeNodeInterfacesCacheTTL is the TTL of the NICs of the nodes the next hops of the routes are checked against.
+	routeNodeInterfacesCacheTTL = 15 * time.Minute
 )
 
 // delayedRouteOperation defines a delayed route operation which is used in delayedRouteUpdater.
 type delayedRouteOperation struct {
 	route          *armnetwork.Route
+	routeTableName string
 	routeTableTags map[string]*string
 	operation      routeOperation
 	result         chan batchOperationResult
 	nodeName       string
+	// queuedAt is the time the operation is added to the delayedRouteUpdater.
+	queuedAt time.Time
+	// err is the error of the operation rejected without updating the route table.
+	err error
 }
 
 // wait waits for the operation completion and returns the result.
@@ -75,14 +94,20 @@ type delayedRouteUpdater struct {
 
 	lock           sync.Mutex
 	routesToUpdate []batchOperation
+	// associatedRouteTables are the route tables already associated with their subnets.
+	associatedRouteTables *utilsets.IgnoreCaseSet
+	// queueMetrics reports the standard workqueue metrics of the route operations.
+	queueMetrics *routeQueueMetrics
 }
 
 // newDelayedRouteUpdater creates a new delayedRouteUpdater.
 func newDelayedRouteUpdater(az *Cloud, interval time.Duration) batchProcessor {
 	return &delayedRouteUpdater{
-		az:             az,
-		interval:       interval,
-		routesToUpdate: make([]batchOperation, 0),
+		az:                    az,
+		interval:              interval,
+		routesToUpdate:        make([]batchOperation, 0),
+		associatedRouteTables: utilsets.NewString(),
+		queueMetrics:          newRouteQueueMetrics(),
 	}
 }
 
@@ -96,52 +121,97 @@ func (d *delayedRouteUpdater) run(ctx context.Context) {
 	klog.Infof("delayedRouteUpdater: stopped due to %s", err.Error())
 }
 
-// updateRoutes invokes route table client to update all routes.
+// updateRoutes invokes route table client to update all routes. The pending operations are
+// taken out of the queue before updating the route tables, so the operations added during the
+// update, including the wait for the routes to take effect, are coalesced into the next batch
+// instead of blocking their callers.
 func (d *delayedRouteUpdater) updateRoutes(ctx context.Context) {
 	d.lock.Lock()
-	defer d.lock.Unlock()
+	routesToUpdate := d.routesToUpdate
+	d.routesToUpdate = make([]batchOperation, 0)
+	d.lock.Unlock()
+	d.az.routeMetrics.observePendingOperations(0)
 
 	// No need to do any updating.
-	if len(d.routesToUpdate) == 0 {
+	if len(routesToUpdate) == 0 {
 		klog.V(4).Info("updateRoutes: nothing to update, returning")
 		return
 	}
+	klog.V(2).Infof("updateRoutes: updating route tables with %d operations", len(routesToUpdate))
 
-	var err error
-	defer func() {
+	// Group the operations by route table, so each route table is updated once.
+	var routeTableNames []string
+	operations := make(map[string][]*delayedRouteOperation)
+	hasDeletions := make(map[string]bool)
+	for _, op := range routesToUpdate {
+		rt := op.(*delayedRouteOperation)
+		d.queueMetrics.take(rt)
+		if _, ok := operations[rt.routeTableName]; !ok {
+			routeTableNames = append(routeTableNames, rt.routeTableName)
+		}
+		operations[rt.routeTableName] = append(operations[rt.routeTableName], rt)
+		if rt.operation == routeOperationDelete {
+			hasDeletions[rt.routeTableName] = true
+		}
+	}
+	// The route tables with the routes of the removed nodes are updated first, so the cleanup
+	// is not delayed by the route tables updated for the new nodes.
+	sort.SliceStable(routeTableNames, func(i, j int) bool {
+		return hasDeletions[routeTableNames[i]] && !hasDeletions[routeTableNames[j]]
+	})
+
+	for _, routeTableName := range routeTableNames {
+		start := time.Now()
+		attempts := 0
+		err := retryOnETagConflict(ctx, fmt.Sprintf("route table %s", routeTableName), func() error {
+			attempts++
+			if attempts > 1 {
+				d.queueMetrics.retry()
+			}
+			return d.updateRouteTable(ctx, routeTableName, operations[routeTableName])
+		})
+		d.az.routeMetrics.observeRouteTableSync(routeTableName, start, err)
+		d.queueMetrics.done(start)
 		// Notify all the goroutines.
-		for _, op := range d.routesToUpdate {
-			rt := op.(*delayedRouteOperation)
-			rt.result <- newBatchOperationResult("", false, err)
+		for _, rt := range operations[routeTableName] {
+			opErr := err
+			if rt.err != nil {
+				opErr = rt.err
+			}
+			rt.result <- newBatchOperationResult("", false, opErr)
 		}
-		// Clear all the jobs.
-		d.routesToUpdate = make([]batchOperation, 0)
-	}()
+	}
+}
 
-	var (
-		routeTable *armnetwork.RouteTable
-	)
-	routeTable, err = d.az.routeTableRepo.Get(ctx, d.az.RouteTableName, azcache.CacheReadTypeDefault)
+// updateRouteTable applies the operations to the routes of the route table, and creates the
+// route table if it doesn't exist yet.
+func (d *delayedRouteUpdater) updateRouteTable(ctx context.Context, routeTableName string, operations []*delayedRouteOperation) error {
+	routeTable, err := d.az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeDefault)
 	if err != nil {
 		klog.Errorf("getRouteTable() failed with error: %v", err)
-		return
+		return err
 	}
 
 	// create route table if it doesn't exists yet.
 	if routeTable == nil {
-		err = d.az.createRouteTable(ctx)
+		err = d.az.createRouteTable(ctx, routeTableName)
 		if err != nil {
 			klog.Errorf("createRouteTable() failed with error: %v", err)
-			return
+			return err
 		}
 
-		routeTable, err = d.az.routeTableRepo.Get(ctx, d.az.RouteTableName, azcache.CacheReadTypeDefault)
+		routeTable, err = d.az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeDefault)
 		if err != nil {
 			klog.Errorf("getRouteTable() failed with error: %v", err)
-			return
+			return err
 		}
 	}
 
+	if err := d.ensureRouteTableAssociated(ctx, routeTable); err != nil {
+		klog.Errorf("ensureRouteTableAssociated() failed with error: %v", err)
+		return err
+	}
+
 	// reconcile routes.
 	dirty, onlyUpdateTags := false, true
 	var routes []*armnetwork.Route
@@ -149,19 +219,30 @@ func (d *delayedRouteUpdater) updateRoutes(ctx context.Context) {
 		routes = routeTable.Properties.Routes
 	}
 
-	routes, dirty = d.cleanupOutdatedRoutes(routes)
+	routes, dirty = d.cleanupOutdatedRoutes(ctx, routeTableName, routes)
 	if dirty {
 		onlyUpdateTags = false
 	}
 
-	for _, op := range d.routesToUpdate {
-		rt := op.(*delayedRouteOperation)
+	for _, rt := range operations {
 		if rt.operation == routeTableOperationUpdateTags {
 			routeTable.Tags = rt.routeTableTags
 			dirty = true
 			continue
 		}
 
+		if rt.operation == routeOperationAdd {
+			if userRoute := d.az.findConflictingUserRoute(routes, rt.route); userRoute != nil {
+				if d.az.FailOnUserRouteConflict {
+					rt.err = fmt.Errorf("route %s conflicts with the user-defined route %s with address prefix %s",
+						ptr.Deref(rt.route.Name, ""), ptr.Deref(userRoute.Name, ""), ptr.Deref(userRoute.Properties.AddressPrefix, ""))
+					klog.Errorf("updateRoutes: %v", rt.err)
+					continue
+				}
+				klog.Warningf("updateRoutes: route %s targeting node %s is overridden by the user-defined route %s", ptr.Deref(rt.route.Name, ""), rt.nodeName, ptr.Deref(userRoute.Name, ""))
+			}
+		}
+
 		routeMatch := false
 		onlyUpdateTags = false
 		for i, existingRoute := range routes {
@@ -215,23 +296,47 @@ func (d *delayedRouteUpdater) updateRoutes(ctx context.Context) {
 		_, err := d.az.routeTableRepo.CreateOrUpdate(ctx, *routeTable)
 		if err != nil {
 			klog.Errorf("CreateOrUpdateRouteTable() failed with error: %v", err)
-			return
+			return err
 		}
 
 		// wait a while for route updates to take effect.
 		time.Sleep(time.Duration(d.az.Config.RouteUpdateWaitingInSeconds) * time.Second)
 	}
+	return nil
 }
 
 // cleanupOutdatedRoutes deletes all non-dualstack routes when dualstack is enabled,
-// and deletes all dualstack routes when dualstack is not enabled.
-func (d *delayedRouteUpdater) cleanupOutdatedRoutes(existingRoutes []*armnetwork.Route) (routes []*armnetwork.Route, changed bool) {
+// and deletes all dualstack routes when dualstack is not enabled. The routes of the nodes
+// assigned to another route table are deleted as well, e.g. after routeTableShardCount changes.
+func (d *delayedRouteUpdater) cleanupOutdatedRoutes(ctx context.Context, routeTableName string, existingRoutes []*armnetwork.Route) (routes []*armnetwork.Route, changed bool) {
 	for i := len(existingRoutes) - 1; i >= 0; i-- {
 		existingRouteName := ptr.Deref(existingRoutes[i].Name, "")
-		split := strings.Split(existingRouteName, consts.RouteNameSeparator)
 
 		klog.V(4).Infof("cleanupOutdatedRoutes: checking route %s", existingRouteName)
 
+		// Routes without the prefix are user-defined, except the ones targeting the nodes created before
+		// the prefix is configured. They are renamed with the prefix in the same update of the route table,
+		// so the nodes don't lose their routes, or deleted if the prefixed routes exist already.
+		if !d.az.isManagedRoute(existingRouteName) {
+			if !d.az.nodeNames.Has(strings.Split(existingRouteName, consts.RouteNameSeparator)[0]) {
+				continue
+			}
+			prefixedRouteName := d.az.RouteNamePrefix + existingRouteName
+			changed = true
+			if hasRouteWithName(existingRoutes, prefixedRouteName) {
+				klog.V(2).Infof("cleanupOutdatedRoutes: deleting outdated route %s replaced by %s", existingRouteName, prefixedRouteName)
+				existingRoutes = append(existingRoutes[:i], existingRoutes[i+1:]...)
+				continue
+			}
+			klog.V(2).Infof("cleanupOutdatedRoutes: renaming outdated route %s to %s", existingRouteName, prefixedRouteName)
+			existingRoutes[i] = &armnetwork.Route{
+				Name:       ptr.To(prefixedRouteName),
+				Properties: existingRoutes[i].Properties,
+			}
+			existingRouteName = prefixedRouteName
+		}
+		split := strings.Split(existingRouteName[len(d.az.RouteNamePrefix):], consts.RouteNameSeparator)
+
 		// filter out unmanaged routes
 		deleteRoute := false
 		if d.az.nodeNames.Has(split[0]) {
@@ -241,6 +346,11 @@ func (d *delayedRouteUpdater) cleanupOutdatedRoutes(existingRoutes []*armnetwork
 			} else if !d.az.ipv6DualStackEnabled && len(split) == 2 {
 				klog.V(2).Infof("cleanupOutdatedRoutes: deleting outdated dualstack route %s", existingRouteName)
 				deleteRoute = true
+			} else if expected, err := d.az.getRouteTableNameForNode(ctx, split[0]); err != nil {
+				klog.V(4).Infof("cleanupOutdatedRoutes: keeping route %s because the route table of its node is unknown: %v", existingRouteName, err)
+			} else if !strings.EqualFold(expected, routeTableName) {
+				klog.V(2).Infof("cleanupOutdatedRoutes: deleting route %s moved to route table %s", existingRouteName, expected)
+				deleteRoute = true
 			}
 
 			if deleteRoute {
@@ -253,26 +363,39 @@ func (d *delayedRouteUpdater) cleanupOutdatedRoutes(existingRoutes []*armnetwork
 	return existingRoutes, changed
 }
 
-func getAddRouteOperation(route *armnetwork.Route, nodeName string) batchOperation {
+// hasRouteWithName returns true if one of the routes has the name.
+func hasRouteWithName(routes []*armnetwork.Route, name string) bool {
+	for _, route := range routes {
+		if strings.EqualFold(ptr.Deref(route.Name, ""), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func getAddRouteOperation(route *armnetwork.Route, nodeName, routeTableName string) batchOperation {
 	return &delayedRouteOperation{
-		route:     route,
-		nodeName:  nodeName,
-		operation: routeOperationAdd,
-		result:    make(chan batchOperationResult),
+		route:          route,
+		routeTableName: routeTableName,
+		nodeName:       nodeName,
+		operation:      routeOperationAdd,
+		result:         make(chan batchOperationResult),
 	}
 }
 
-func getDeleteRouteOperation(route *armnetwork.Route, nodeName string) batchOperation {
+func getDeleteRouteOperation(route *armnetwork.Route, nodeName, routeTableName string) batchOperation {
 	return &delayedRouteOperation{
-		route:     route,
-		nodeName:  nodeName,
-		operation: routeOperationDelete,
-		result:    make(chan batchOperationResult),
+		route:          route,
+		routeTableName: routeTableName,
+		nodeName:       nodeName,
+		operation:      routeOperationDelete,
+		result:         make(chan batchOperationResult),
 	}
 }
 
-func getUpdateRouteTableTagsOperation(tags map[string]*string) batchOperation {
+func getUpdateRouteTableTagsOperation(tags map[string]*string, routeTableName string) batchOperation {
 	return &delayedRouteOperation{
+		routeTableName: routeTableName,
 		routeTableTags: tags,
 		operation:      routeTableOperationUpdateTags,
 		result:         make(chan batchOperationResult),
@@ -284,7 +407,12 @@ func (d *delayedRouteUpdater) addOperation(operation batchOperation) batchOperat
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
+	if op, ok := operation.(*delayedRouteOperation); ok {
+		op.queuedAt = time.Now()
+	}
 	d.routesToUpdate = append(d.routesToUpdate, operation)
+	d.az.routeMetrics.observePendingOperations(len(d.routesToUpdate))
+	d.queueMetrics.add()
 	return operation
 }
 
@@ -292,12 +420,42 @@ func (d *delayedRouteUpdater) removeOperation(_ string) {}
 
 // ListRoutes lists all managed routes that belong to the specified clusterName
 // implements cloudprovider.Routes.ListRoutes
-func (az *Cloud) ListRoutes(ctx context.Context, clusterName string) ([]*cloudprovider.Route, error) {
+func (az *Cloud) ListRoutes(ctx context.Context, clusterName string) (routes []*cloudprovider.Route, err error) {
+	ctx = withARMCaller(ctx, armCallerRoute)
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "ListRoutes")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+
 	klog.V(10).Infof("ListRoutes: START clusterName=%q", clusterName)
-	routeTable, err := az.routeTableRepo.Get(ctx, az.RouteTableName, azcache.CacheReadTypeDefault)
-	routes, err := processRoutes(az.ipv6DualStackEnabled, routeTable, err)
-	if err != nil {
-		return nil, err
+	routeTables := make([]*armnetwork.RouteTable, 0)
+	for _, routeTableName := range az.getRouteTableNames() {
+		routeTable, err := az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeDefault)
+		tableRoutes, err := processRoutes(az.ipv6DualStackEnabled, routeTable, err)
+		if err != nil {
+			return nil, err
+		}
+		outdatedRouteNames := az.getRoutesWithOutdatedNextHops(ctx, routeTable)
+		managedRoutes := 0
+		for _, route := range tableRoutes {
+			// Skip the user-defined routes so the route controller never deletes them.
+			if !az.isManagedRoute(route.Name) {
+				continue
+			}
+			managedRoutes++
+			// Skip the routes with outdated next hops so the route controller recreates them.
+			if outdatedRouteNames.Has(route.Name) {
+				klog.V(2).Infof("ListRoutes: route %s has an outdated next hop, leaving it to be recreated", route.Name)
+				continue
+			}
+			route.TargetNode = az.mapRouteNameToNodeName(route.Name)
+			routes = append(routes, route)
+		}
+		if routeTable != nil {
+			routeTables = append(routeTables, routeTable)
+		}
+		az.routeMetrics.observeRoutes(routeTableName, managedRoutes)
 	}
 
 	// Compose routes for unmanaged routes so that node controller won't retry creating routes for them.
@@ -316,22 +474,76 @@ func (az *Cloud) ListRoutes(ctx context.Context, clusterName string) ([]*cloudpr
 			})
 		}
 	}
+	az.observeRoutePendingNodes(routes, unmanagedNodes)
+
+	// ensure the route tables are tagged as configured
+	for _, routeTable := range routeTables {
+		tags, changed := az.ensureRouteTableTagged(routeTable)
+		if changed {
+			klog.V(2).Infof("ListRoutes: updating tags on route table %s", ptr.Deref(routeTable.Name, ""))
+			op := az.routeUpdater.addOperation(getUpdateRouteTableTagsOperation(tags, ptr.Deref(routeTable.Name, "")))
+
+			// Wait for operation complete.
+			err = op.wait().err
+			if err != nil {
+				klog.Errorf("ListRoutes: failed to update route table tags with error: %v", err)
+				return nil, err
+			}
+		}
+	}
 
-	// ensure the route table is tagged as configured
-	tags, changed := az.ensureRouteTableTagged(routeTable)
-	if changed {
-		klog.V(2).Infof("ListRoutes: updating tags on route table %s", ptr.Deref(routeTable.Name, ""))
-		op := az.routeUpdater.addOperation(getUpdateRouteTableTagsOperation(tags))
+	return routes, nil
+}
 
-		// Wait for operation complete.
-		err = op.wait().err
+// getRoutesWithOutdatedNextHops returns the names of the managed routes in the route table whose next hops are
+// outdated, e.g. after the route-ip-configuration annotation of the node or the next hop policies changed.
+// Only the routes of the nodes in nodesWithOutdatedRouteNextHops are checked, and the nodes whose routes are
+// all up to date are removed from it.
+func (az *Cloud) getRoutesWithOutdatedNextHops(ctx context.Context, routeTable *armnetwork.RouteTable) *utilsets.IgnoreCaseSet {
+	outdatedRouteNames := utilsets.NewString()
+	az.nodeCachesLock.RLock()
+	nodesToCheck := az.nodesWithOutdatedRouteNextHops.Len()
+	az.nodeCachesLock.RUnlock()
+	if nodesToCheck == 0 || routeTable == nil || routeTable.Properties == nil {
+		return outdatedRouteNames
+	}
+
+	verifiedNodes, unverifiedNodes := utilsets.NewString(), utilsets.NewString()
+	for _, route := range routeTable.Properties.Routes {
+		routeName := ptr.Deref(route.Name, "")
+		if route.Properties == nil || !az.isManagedRoute(routeName) {
+			continue
+		}
+		nodeName := az.mapRouteNameToNodeName(routeName)
+		az.nodeCachesLock.RLock()
+		toCheck := az.nodesWithOutdatedRouteNextHops.Has(string(nodeName))
+		az.nodeCachesLock.RUnlock()
+		if !toCheck {
+			continue
+		}
+
+		outdated, err := az.isRouteNextHopOutdated(ctx, route, nodeName)
 		if err != nil {
-			klog.Errorf("ListRoutes: failed to update route table tags with error: %v", err)
-			return nil, err
+			klog.Warningf("getRoutesWithOutdatedNextHops: failed to check the next hop of route %s: %v", routeName, err)
+			unverifiedNodes.Insert(string(nodeName))
+			continue
+		}
+		if outdated {
+			outdatedRouteNames.Insert(routeName)
+			unverifiedNodes.Insert(string(nodeName))
+			continue
 		}
+		verifiedNodes.Insert(string(nodeName))
 	}
 
-	return routes, nil
+	az.nodeCachesLock.Lock()
+	defer az.nodeCachesLock.Unlock()
+	for _, nodeName := range verifiedNodes.UnsortedList() {
+		if !unverifiedNodes.Has(nodeName) {
+			az.nodesWithOutdatedRouteNextHops.Delete(nodeName)
+		}
+	}
+	return outdatedRouteNames
 }
 
 // Injectable for testing
@@ -363,14 +575,14 @@ func processRoutes(ipv6DualStackEnabled bool, routeTable *armnetwork.RouteTable,
 	return kubeRoutes, nil
 }
 
-func (az *Cloud) createRouteTable(ctx context.Context) error {
+func (az *Cloud) createRouteTable(ctx context.Context, routeTableName string) error {
 	routeTable := armnetwork.RouteTable{
-		Name:       ptr.To(az.RouteTableName),
+		Name:       ptr.To(routeTableName),
 		Location:   ptr.To(az.Location),
 		Properties: &armnetwork.RouteTablePropertiesFormat{},
 	}
 
-	klog.V(3).Infof("createRouteTableIfNotExists: creating routetable. routeTableName=%q", az.RouteTableName)
+	klog.V(3).Infof("createRouteTableIfNotExists: creating routetable. routeTableName=%q", routeTableName)
 	_, err := az.routeTableRepo.CreateOrUpdate(ctx, routeTable)
 	return err
 }
@@ -379,7 +591,15 @@ func (az *Cloud) createRouteTable(ctx context.Context) error {
 // route.Name will be ignored, although the cloud-provider may use nameHint
 // to create a more user-meaningful name.
 // implements cloudprovider.Routes.CreateRoute
-func (az *Cloud) CreateRoute(ctx context.Context, clusterName string, _ string, kubeRoute *cloudprovider.Route) error {
+func (az *Cloud) CreateRoute(ctx context.Context, clusterName string, _ string, kubeRoute *cloudprovider.Route) (err error) {
+	ctx = withARMCaller(ctx, armCallerRoute)
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "CreateRoute")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Node(string(kubeRoute.TargetNode)))
+
 	mc := metrics.NewMetricContext("routes", "create_route", az.ResourceGroup, az.getNetworkResourceSubscriptionID(), string(kubeRoute.TargetNode))
 	isOperationSucceeded := false
 	defer func() {
@@ -387,7 +607,6 @@ func (az *Cloud) CreateRoute(ctx context.Context, clusterName string, _ string,
 	}()
 
 	// Returns  for unmanaged nodes because azure cloud provider couldn't fetch information for them.
-	var targetIP string
 	nodeName := string(kubeRoute.TargetNode)
 	unmanaged, err := az.IsNodeUnmanaged(nodeName)
 	if err != nil {
@@ -400,62 +619,183 @@ func (az *Cloud) CreateRoute(ctx context.Context, clusterName string, _ string,
 		az.routeCIDRs[nodeName] = kubeRoute.DestinationCIDR
 		return nil
 	}
+	// The routes of the nodes marked for deletion are deleted before the nodes are deleted. The route
+	// controller retries the route until the node is deleted, or created if the node is unmarked.
+	if az.isNodeNameMarkedForDeletion(nodeName) {
+		klog.V(2).Infof("CreateRoute: omitting node %q marked for deletion", kubeRoute.TargetNode)
+		return fmt.Errorf("node %s is marked for deletion", nodeName)
+	}
+	start := time.Now()
+	defer func() {
+		az.routeMetrics.observeRouteOperation(routeMetricOperationCreate, nodeName, start, err)
+		if err == nil {
+			az.observeRouteCreated(nodeName)
+		}
+	}()
+
+	nextHopType, targetIP, err := az.getRouteNextHop(ctx, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	if err != nil {
+		return err
+	}
+	routeName := az.getRouteName(kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	route := &armnetwork.Route{
+		Name: ptr.To(routeName),
+		Properties: &armnetwork.RoutePropertiesFormat{
+			AddressPrefix: ptr.To(kubeRoute.DestinationCIDR),
+			NextHopType:   ptr.To(nextHopType),
+		},
+	}
+	if targetIP != "" {
+		route.Properties.NextHopIPAddress = ptr.To(targetIP)
+	}
+
+	routeTableName, err := az.getRouteTableNameForNode(ctx, nodeName)
+	if err != nil {
+		klog.Errorf("CreateRoute failed to get the route table of node %q with error: %v", kubeRoute.TargetNode, err)
+		return err
+	}
+
+	klog.V(2).Infof("CreateRoute: creating route for clusterName=%q instance=%q cidr=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	op := az.routeUpdater.addOperation(getAddRouteOperation(route, nodeName, routeTableName))
+
+	// Wait for operation complete.
+	err = op.wait().err
+	if err != nil {
+		klog.Errorf("CreateRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
+		return err
+	}
+
+	klog.V(2).Infof("CreateRoute: route created. clusterName=%q instance=%q cidr=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	isOperationSucceeded = true
 
-	CIDRv6 := utilnet.IsIPv6CIDRString(kubeRoute.DestinationCIDR)
+	return nil
+}
+
+// getRouteNextHop returns the next hop type and IP of the route to the pod CIDR of the node, from the next hop
+// policies, the configured IP configurations or the private IPs of the node in order.
+func (az *Cloud) getRouteNextHop(ctx context.Context, nodeName types.NodeName, cidr string) (nextHopType armnetwork.RouteNextHopType, targetIP string, err error) {
+	nextHopType, targetIP, overridden, err := az.getRouteNextHopOverride(ctx, nodeName, cidr, azcache.CacheReadTypeForceRefresh)
+	if err != nil {
+		return "", "", err
+	}
+	if overridden {
+		return nextHopType, targetIP, nil
+	}
+
+	CIDRv6 := utilnet.IsIPv6CIDRString(cidr)
+	nextHopType = armnetwork.RouteNextHopTypeVirtualAppliance
 	// if single stack IPv4 then get the IP for the primary ip config
 	// single stack IPv6 is supported on dual stack host. So the IPv6 IP is secondary IP for both single stack IPv6 and dual stack
 	// Get all private IPs for the machine and find the first one that matches the IPv6 family
 	if !az.ipv6DualStackEnabled && !CIDRv6 {
-		targetIP, _, err = az.getIPForMachine(ctx, kubeRoute.TargetNode)
+		targetIP, _, err = az.getIPForMachine(ctx, nodeName)
 		if err != nil {
-			return err
+			return "", "", err
 		}
 	} else {
 		// for dual stack and single stack IPv6 we need to select
 		// a private ip that matches family of the cidr
-		klog.V(4).Infof("CreateRoute: create route instance=%q cidr=%q is in dual stack mode", kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-		nodePrivateIPs, err := az.getPrivateIPsForMachine(ctx, kubeRoute.TargetNode)
+		klog.V(4).Infof("getRouteNextHop: route instance=%q cidr=%q is in dual stack mode", nodeName, cidr)
+		nodePrivateIPs, err := az.getPrivateIPsForMachine(ctx, nodeName)
 		if nil != err {
-			klog.V(3).Infof("CreateRoute: create route: failed(GetPrivateIPsByNodeName) instance=%q cidr=%q with error=%v", kubeRoute.TargetNode, kubeRoute.DestinationCIDR, err)
-			return err
+			klog.V(3).Infof("getRouteNextHop: failed(GetPrivateIPsByNodeName) instance=%q cidr=%q with error=%v", nodeName, cidr, err)
+			return "", "", err
 		}
 
 		targetIP, err = findFirstIPByFamily(nodePrivateIPs, CIDRv6)
 		if nil != err {
-			klog.V(3).Infof("CreateRoute: create route: failed(findFirstIpByFamily) instance=%q cidr=%q with error=%v", kubeRoute.TargetNode, kubeRoute.DestinationCIDR, err)
-			return err
+			klog.V(3).Infof("getRouteNextHop: failed(findFirstIpByFamily) instance=%q cidr=%q with error=%v", nodeName, cidr, err)
+			return "", "", err
 		}
 	}
-	routeName := mapNodeNameToRouteName(az.ipv6DualStackEnabled, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-	route := &armnetwork.Route{
-		Name: ptr.To(routeName),
-		Properties: &armnetwork.RoutePropertiesFormat{
-			AddressPrefix:    ptr.To(kubeRoute.DestinationCIDR),
-			NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
-			NextHopIPAddress: ptr.To(targetIP),
-		},
+	return nextHopType, targetIP, nil
+}
+
+// getRouteNextHopOverride returns the next hop type and IP of the route to the pod CIDR of the node from the next hop
+// policies or the configured IP configurations, which are looked up in the NICs of the node read with crt.
+// overridden is false if neither of them applies, and the next hop is one of the private IPs of the node.
+func (az *Cloud) getRouteNextHopOverride(ctx context.Context, nodeName types.NodeName, cidr string, crt azcache.AzureCacheReadType) (nextHopType armnetwork.RouteNextHopType, targetIP string, overridden bool, err error) {
+	ipConfigurationNames := az.getRouteIPConfigurationNames(string(nodeName))
+	if len(az.RouteNextHopPolicies) == 0 && len(ipConfigurationNames) == 0 {
+		return "", "", false, nil
+	}
+	nics, err := az.getRouteNodeInterfaces(ctx, nodeName, crt)
+	if err != nil {
+		klog.Errorf("getRouteNextHopOverride(%s): failed to get the interfaces: %v", nodeName, err)
+		return "", "", false, err
 	}
 
-	klog.V(2).Infof("CreateRoute: creating route for clusterName=%q instance=%q cidr=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-	op := az.routeUpdater.addOperation(getAddRouteOperation(route, string(kubeRoute.TargetNode)))
+	CIDRv6 := utilnet.IsIPv6CIDRString(cidr)
+	policy, err := az.getRouteNextHopPolicy(nics, CIDRv6)
+	if err != nil {
+		return "", "", false, err
+	}
+	if policy != nil {
+		nextHopType = armnetwork.RouteNextHopType(policy.NextHopType)
+		targetIP = policy.NextHopIPAddress
+		klog.V(4).Infof("getRouteNextHopOverride: route instance=%q cidr=%q with the next hop type %q and IP %q of the next hop policy", nodeName, cidr, nextHopType, targetIP)
+		return nextHopType, targetIP, true, nil
+	}
+	if len(ipConfigurationNames) > 0 {
+		if targetIP = getIPConfigurationPrivateIP(nodeName, nics, ipConfigurationNames, CIDRv6); targetIP != "" {
+			klog.V(4).Infof("getRouteNextHopOverride: route instance=%q cidr=%q with the next hop %q of the configured IP configurations", nodeName, cidr, targetIP)
+			return armnetwork.RouteNextHopTypeVirtualAppliance, targetIP, true, nil
+		}
+	}
+	return "", "", false, nil
+}
 
-	// Wait for operation complete.
-	err = op.wait().err
+// getRouteNodeInterfaces returns the NICs of the node, the primary one first, from routeNodeInterfacesCache.
+func (az *Cloud) getRouteNodeInterfaces(ctx context.Context, nodeName types.NodeName, crt azcache.AzureCacheReadType) ([]*armnetwork.Interface, error) {
+	cached, err := az.routeNodeInterfacesCache.Get(ctx, string(nodeName), crt)
 	if err != nil {
-		klog.Errorf("CreateRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
-		return err
+		return nil, err
 	}
+	nics, _ := cached.([]*armnetwork.Interface)
+	return nics, nil
+}
 
-	klog.V(2).Infof("CreateRoute: route created. clusterName=%q instance=%q cidr=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-	isOperationSucceeded = true
+// newRouteNodeInterfacesCache returns the cache of the NICs of the nodes, which the next hops of the routes
+// overridden by the next hop policies or the IP configurations are looked up in.
+func (az *Cloud) newRouteNodeInterfacesCache() (azcache.Resource, error) {
+	getter := func(ctx context.Context, nodeName string) (interface{}, error) {
+		return az.VMSet.GetInterfacesByNodeName(ctx, nodeName)
+	}
+	return azcache.NewTimedCache(routeNodeInterfacesCacheTTL, getter, az.Config.DisableAPICallCache)
+}
 
-	return nil
+// isRouteNextHopOutdated returns true if the next hop of the route is not the one CreateRoute chooses for the node.
+func (az *Cloud) isRouteNextHopOutdated(ctx context.Context, route *armnetwork.Route, nodeName types.NodeName) (bool, error) {
+	if route == nil || route.Properties == nil {
+		return false, nil
+	}
+	nextHopType, targetIP, err := az.getRouteNextHop(ctx, nodeName, ptr.Deref(route.Properties.AddressPrefix, ""))
+	if err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(string(ptr.Deref(route.Properties.NextHopType, "")), string(nextHopType)) ||
+		!strings.EqualFold(ptr.Deref(route.Properties.NextHopIPAddress, ""), targetIP), nil
+}
+
+// hasRouteNextHopOverrides returns true if the next hops of the routes of the node may not be its primary IPs,
+// because of the next hop policies or the configured IP configurations.
+func (az *Cloud) hasRouteNextHopOverrides(node *v1.Node) bool {
+	return len(az.RouteNextHopPolicies) > 0 || az.RouteIPConfigurationName != "" ||
+		node.Annotations[consts.RouteIPConfigurationAnnotation] != ""
 }
 
 // DeleteRoute deletes the specified managed route
 // Route should be as returned by ListRoutes
 // implements cloudprovider.Routes.DeleteRoute
-func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *cloudprovider.Route) error {
+func (az *Cloud) DeleteRoute(ctx context.Context, clusterName string, kubeRoute *cloudprovider.Route) (err error) {
+	ctx = withARMCaller(ctx, armCallerRoute)
+	ctx, span := trace.BeginReconcile(ctx, trace.DefaultTracer(), "DeleteRoute")
+	defer func() {
+		err = errutils.WithARMRequestIDs(err)
+		span.Observe(ctx, err)
+	}()
+	span.Inner().SetAttributes(attributes.Node(string(kubeRoute.TargetNode)))
+
 	mc := metrics.NewMetricContext("routes", "delete_route", az.ResourceGroup, az.getNetworkResourceSubscriptionID(), string(kubeRoute.TargetNode))
 	isOperationSucceeded := false
 	defer func() {
@@ -475,18 +815,21 @@ func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *c
 		delete(az.routeCIDRs, nodeName)
 		return nil
 	}
+	start := time.Now()
+	defer func() {
+		az.routeMetrics.observeRouteOperation(routeMetricOperationDelete, nodeName, start, err)
+		if err == nil {
+			az.observeRouteDeleted(nodeName)
+		}
+	}()
 
-	routeName := mapNodeNameToRouteName(az.ipv6DualStackEnabled, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	routeName := az.getRouteName(kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
 	klog.V(2).Infof("DeleteRoute: deleting route. clusterName=%q instance=%q cidr=%q routeName=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR, routeName)
 	route := &armnetwork.Route{
 		Name:       ptr.To(routeName),
 		Properties: &armnetwork.RoutePropertiesFormat{},
 	}
-	op := az.routeUpdater.addOperation(getDeleteRouteOperation(route, string(kubeRoute.TargetNode)))
-
-	// Wait for operation complete.
-	err = op.wait().err
-	if err != nil {
+	if err := az.deleteRouteFromRouteTables(ctx, route, nodeName); err != nil {
 		klog.Errorf("DeleteRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
 		return err
 	}
@@ -499,11 +842,7 @@ func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *c
 			Name:       ptr.To(routeNameWithoutIPV6Suffix),
 			Properties: &armnetwork.RoutePropertiesFormat{},
 		}
-		op := az.routeUpdater.addOperation(getDeleteRouteOperation(route, string(kubeRoute.TargetNode)))
-
-		// Wait for operation complete.
-		err = op.wait().err
-		if err != nil {
+		if err := az.deleteRouteFromRouteTables(ctx, route, nodeName); err != nil {
 			klog.Errorf("DeleteRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
 			return err
 		}
@@ -515,6 +854,23 @@ func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *c
 	return nil
 }
 
+// deleteRouteFromRouteTables deletes the route from the route tables holding it.
+func (az *Cloud) deleteRouteFromRouteTables(ctx context.Context, route *armnetwork.Route, nodeName string) error {
+	routeTableNames, err := az.getRouteTableNamesWithRoute(ctx, ptr.Deref(route.Name, ""))
+	if err != nil {
+		return err
+	}
+	for _, routeTableName := range routeTableNames {
+		op := az.routeUpdater.addOperation(getDeleteRouteOperation(route, nodeName, routeTableName))
+
+		// Wait for operation complete.
+		if err := op.wait().err; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // This must be kept in sync with MapRouteNameToNodeName.
 // These two functions enable stashing the instance name in the route
 // and then retrieving it later when listing. This is needed because
@@ -526,6 +882,49 @@ func mapNodeNameToRouteName(ipv6DualStackEnabled bool, nodeName types.NodeName,
 	return fmt.Sprintf(consts.RouteNameFmt, nodeName, cidrtoRfc1035(cidr))
 }
 
+// getRouteName returns the name of the managed route of the node and the CIDR.
+func (az *Cloud) getRouteName(nodeName types.NodeName, cidr string) string {
+	return az.RouteNamePrefix + mapNodeNameToRouteName(az.ipv6DualStackEnabled, nodeName, cidr)
+}
+
+// isManagedRoute returns whether the route is managed by the route controller. All routes are
+// managed if routeNamePrefix is not set.
+func (az *Cloud) isManagedRoute(routeName string) bool {
+	return strings.HasPrefix(strings.ToLower(routeName), strings.ToLower(az.RouteNamePrefix))
+}
+
+// mapRouteNameToNodeName returns the node targeted by the managed route.
+func (az *Cloud) mapRouteNameToNodeName(routeName string) types.NodeName {
+	return MapRouteNameToNodeName(az.ipv6DualStackEnabled, routeName[len(az.RouteNamePrefix):])
+}
+
+// findConflictingUserRoute returns the user-defined route having the same or a more specific address
+// prefix than the route, which takes over the traffic to the pod CIDR. It returns nil if routeNamePrefix
+// is not set, because the user-defined routes cannot be told from the managed ones.
+func (az *Cloud) findConflictingUserRoute(existingRoutes []*armnetwork.Route, route *armnetwork.Route) *armnetwork.Route {
+	if az.RouteNamePrefix == "" || route.Properties == nil {
+		return nil
+	}
+	_, podCIDR, err := net.ParseCIDR(ptr.Deref(route.Properties.AddressPrefix, ""))
+	if err != nil {
+		return nil
+	}
+	podCIDRSize, _ := podCIDR.Mask.Size()
+	for _, existingRoute := range existingRoutes {
+		if az.isManagedRoute(ptr.Deref(existingRoute.Name, "")) || existingRoute.Properties == nil {
+			continue
+		}
+		_, userCIDR, err := net.ParseCIDR(ptr.Deref(existingRoute.Properties.AddressPrefix, ""))
+		if err != nil {
+			continue
+		}
+		if userCIDRSize, _ := userCIDR.Mask.Size(); userCIDRSize >= podCIDRSize && podCIDR.Contains(userCIDR.IP) {
+			return existingRoute
+		}
+	}
+	return nil
+}
+
 // MapRouteNameToNodeName is used with mapNodeNameToRouteName.
 // See comment on mapNodeNameToRouteName for detailed usage.
 func MapRouteNameToNodeName(ipv6DualStackEnabled bool, routeName string) types.NodeName {
@@ -538,6 +937,87 @@ func MapRouteNameToNodeName(ipv6DualStackEnabled bool, routeName string) types.N
 
 }
 
+// getRouteIPConfigurationNames returns the names of the IP configurations whose private IPs are the next hops
+// of the routes of the node, from the route-ip-configuration annotation of the node or routeIPConfigurationName.
+func (az *Cloud) getRouteIPConfigurationNames(nodeName string) []string {
+	az.nodeCachesLock.RLock()
+	ipConfigurations, ok := az.nodeRouteIPConfigurations[nodeName]
+	az.nodeCachesLock.RUnlock()
+	if !ok {
+		ipConfigurations = az.RouteIPConfigurationName
+	}
+
+	var names []string
+	for _, name := range strings.Split(ipConfigurations, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// getIPConfigurationPrivateIP returns the private IP of the first IP configuration of the NICs of the node in the
+// names with the requested IP family, or an empty string if none of them matches. The IP configurations of the
+// primary NIC are looked up before the ones of the secondary NICs.
+func getIPConfigurationPrivateIP(nodeName types.NodeName, nics []*armnetwork.Interface, names []string, v6 bool) string {
+	for _, name := range names {
+		for _, nic := range nics {
+			if nic == nil || nic.Properties == nil {
+				continue
+			}
+			for _, ipConfig := range nic.Properties.IPConfigurations {
+				if ipConfig == nil || ipConfig.Properties == nil || !strings.EqualFold(ptr.Deref(ipConfig.Name, ""), name) {
+					continue
+				}
+				ip := ptr.Deref(ipConfig.Properties.PrivateIPAddress, "")
+				if ip != "" && utilnet.IsIPv6String(ip) == v6 {
+					return ip
+				}
+			}
+		}
+	}
+	klog.V(4).Infof("getIPConfigurationPrivateIP(%s): none of the IP configurations %v matches the IP family, using the default one", nodeName, names)
+	return ""
+}
+
+// getRouteNextHopPolicy returns the first next hop policy listing the subnet of the primary IP configuration
+// of the primary NIC of the node, which is the first one of nics. The policies with the next hop IP of another
+// IP family are ignored.
+func (az *Cloud) getRouteNextHopPolicy(nics []*armnetwork.Interface, v6 bool) (*config.RouteNextHopPolicy, error) {
+	if len(az.RouteNextHopPolicies) == 0 || len(nics) == 0 {
+		return nil, nil
+	}
+
+	nic := nics[0]
+	if nic == nil || nic.Properties == nil {
+		return nil, nil
+	}
+	ipConfig, err := getPrimaryIPConfig(nic)
+	if err != nil {
+		return nil, err
+	}
+	if ipConfig.Properties == nil || ipConfig.Properties.Subnet == nil || ipConfig.Properties.Subnet.ID == nil {
+		return nil, nil
+	}
+	subnetName, err := getLastSegment(*ipConfig.Properties.Subnet.ID, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range az.RouteNextHopPolicies {
+		policy := &az.RouteNextHopPolicies[i]
+		if policy.NextHopIPAddress != "" && utilnet.IsIPv6String(policy.NextHopIPAddress) != v6 {
+			continue
+		}
+		for _, name := range policy.SubnetNames {
+			if strings.EqualFold(name, subnetName) {
+				return policy, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
 // given a list of ips, return the first one
 // that matches the family requested
 // error if no match, or failure to parse
@@ -579,3 +1059,150 @@ func (az *Cloud) ensureRouteTableTagged(rt *armnetwork.RouteTable) (map[string]*
 
 	return rt.Tags, changed
 }
+
+// getRouteTableNames returns the names of the route tables the routes of the nodes are spread across.
+func (az *Cloud) getRouteTableNames() []string {
+	names := []string{az.RouteTableName}
+	for i := 1; i < az.RouteTableShardCount; i++ {
+		names = append(names, fmt.Sprintf("%s-%d", az.RouteTableName, i))
+	}
+	return names
+}
+
+// getRouteTableNameForNode returns the name of the route table holding the routes of the node.
+// Azure applies one route table per subnet, so with routeTableShardCount the node is assigned to
+// the route table of the subnet in routeTableShardSubnetNames containing its private IPs.
+func (az *Cloud) getRouteTableNameForNode(ctx context.Context, nodeName string) (string, error) {
+	if az.RouteTableShardCount <= 1 {
+		return az.RouteTableName, nil
+	}
+
+	az.nodeCachesLock.RLock()
+	var nodeIPs []string
+	if ips := az.nodePrivateIPs[strings.ToLower(nodeName)]; ips != nil {
+		nodeIPs = ips.UnsortedList()
+	}
+	az.nodeCachesLock.RUnlock()
+
+	routeTableNames := az.getRouteTableNames()
+	for i, subnetName := range az.RouteTableShardSubnetNames {
+		cached, err := az.routeTableSubnetCache.Get(ctx, subnetName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the address prefixes of subnet %s: %w", subnetName, err)
+		}
+		prefixes, _ := cached.([]netip.Prefix)
+		for _, ip := range nodeIPs {
+			addr, err := netip.ParseAddr(ip)
+			if err != nil {
+				continue
+			}
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					return routeTableNames[i], nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("the private IPs %v of node %s are not in any of the subnets %v of the route tables", nodeIPs, nodeName, az.RouteTableShardSubnetNames)
+}
+
+// newRouteTableSubnetCache returns the cache of the address prefixes of the subnets in
+// routeTableShardSubnetNames, which the nodes are assigned to the route tables by.
+func (az *Cloud) newRouteTableSubnetCache() (azcache.Resource, error) {
+	getter := func(ctx context.Context, subnetName string) (interface{}, error) {
+		rg := az.ResourceGroup
+		if len(az.VnetResourceGroup) > 0 {
+			rg = az.VnetResourceGroup
+		}
+		subnet, err := az.subnetRepo.Get(ctx, rg, az.VnetName, subnetName)
+		if err != nil {
+			return nil, err
+		}
+		var prefixes []netip.Prefix
+		if subnet == nil || subnet.Properties == nil {
+			return prefixes, nil
+		}
+		addressPrefixes := subnet.Properties.AddressPrefixes
+		if subnet.Properties.AddressPrefix != nil {
+			addressPrefixes = append(addressPrefixes, subnet.Properties.AddressPrefix)
+		}
+		for _, addressPrefix := range addressPrefixes {
+			prefix, err := netip.ParsePrefix(ptr.Deref(addressPrefix, ""))
+			if err != nil {
+				klog.Warningf("newRouteTableSubnetCache: failed to parse address prefix %q of subnet %s: %v", ptr.Deref(addressPrefix, ""), subnetName, err)
+				continue
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		return prefixes, nil
+	}
+	return azcache.NewTimedCache(routeTableSubnetCacheTTL, getter, az.Config.DisableAPICallCache)
+}
+
+// getRouteTableNamesWithRoute returns the names of the route tables holding the route, so the routes
+// of the deleted nodes, whose subnets are unknown, are deleted from their route tables.
+func (az *Cloud) getRouteTableNamesWithRoute(ctx context.Context, routeName string) ([]string, error) {
+	if az.RouteTableShardCount <= 1 {
+		return []string{az.RouteTableName}, nil
+	}
+
+	var names []string
+	for _, routeTableName := range az.getRouteTableNames() {
+		routeTable, err := az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeDefault)
+		if err != nil {
+			return nil, err
+		}
+		if routeTable == nil || routeTable.Properties == nil {
+			continue
+		}
+		for _, route := range routeTable.Properties.Routes {
+			if strings.EqualFold(ptr.Deref(route.Name, ""), routeName) {
+				names = append(names, routeTableName)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// getRouteTableSubnetName returns the name of the subnet the route table should be associated
+// with, or an empty string if the subnets of the route tables are not managed.
+func (az *Cloud) getRouteTableSubnetName(routeTableName string) string {
+	for i, name := range az.getRouteTableNames() {
+		if strings.EqualFold(name, routeTableName) && i < len(az.RouteTableShardSubnetNames) {
+			return az.RouteTableShardSubnetNames[i]
+		}
+	}
+	return ""
+}
+
+// ensureRouteTableAssociated associates the route table with its subnet in routeTableShardSubnetNames.
+// Each route table is only checked once, because it is not expected to be detached from the subnet.
+func (d *delayedRouteUpdater) ensureRouteTableAssociated(ctx context.Context, routeTable *armnetwork.RouteTable) error {
+	routeTableName := ptr.Deref(routeTable.Name, "")
+	subnetName := d.az.getRouteTableSubnetName(routeTableName)
+	if subnetName == "" || d.associatedRouteTables.Has(routeTableName) {
+		return nil
+	}
+
+	rg := d.az.ResourceGroup
+	if len(d.az.VnetResourceGroup) > 0 {
+		rg = d.az.VnetResourceGroup
+	}
+	subnet, err := d.az.subnetRepo.Get(ctx, rg, d.az.VnetName, subnetName)
+	if err != nil {
+		return err
+	}
+	if subnet.Properties == nil {
+		subnet.Properties = &armnetwork.SubnetPropertiesFormat{}
+	}
+	if subnet.Properties.RouteTable == nil || !strings.EqualFold(ptr.Deref(subnet.Properties.RouteTable.ID, ""), ptr.Deref(routeTable.ID, "")) {
+		klog.V(2).Infof("ensureRouteTableAssociated: associating route table %s with subnet %s", routeTableName, subnetName)
+		subnet.Properties.RouteTable = &armnetwork.RouteTable{ID: routeTable.ID}
+		if err := d.az.subnetRepo.CreateOrUpdate(ctx, rg, d.az.VnetName, subnetName, *subnet); err != nil {
+			return err
+		}
+	}
+	d.associatedRouteTables.Insert(routeTableName)
+	return nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+const (
+	// routeDriftReasonDeletedNode is the reason of the routes targeting nodes which do not exist.
+	routeDriftReasonDeletedNode = "deleted_node"
+	// routeDriftReasonWrongNextHop is the reason of the routes whose next hop is not an IP of the node,
+	// e.g. after the VM got a new IP.
+	routeDriftReasonWrongNextHop = "wrong_next_hop"
+)
+
+var (
+	routeDriftCount = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_drift_count",
+			Help:           "Number of drifted routes found by the last route drift reconciliation",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"route_table", "reason"},
+	)
+	routeDriftRepairCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_drift_repairs_total",
+			Help:           "Number of drifted routes repaired by the route drift reconciliation",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"route_table", "reason", "result"},
+	)
+
+	registerRouteDriftMetricsOnce sync.Once
+)
+
+func registerRouteDriftMetrics() {
+	registerRouteDriftMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(routeDriftCount)
+		legacyregistry.MustRegister(routeDriftRepairCount)
+	})
+}
+
+// routeDrift is a managed route which does not match the node it targets.
+type routeDrift struct {
+	routeTableName string
+	route          *cloudprovider.Route
+	reason         string
+}
+
+// runRouteDriftReconciler reconciles the routes against the nodes every interval. The route controller
+// only compares the route names and CIDRs with the nodes, so routes with outdated next hops are never
+// fixed, and routes of deleted nodes linger if the cluster CIDR is not configured.
+func (az *Cloud) runRouteDriftReconciler(ctx context.Context, interval time.Duration) {
+	logger := log.FromContextOrBackground(ctx).WithName("runRouteDriftReconciler")
+	registerRouteDriftMetrics()
+	logger.V(2).Info("Reconciling the routes periodically", "interval", interval, "mode", az.RouteDriftReconciliationMode)
+	err := wait.PollUntilContextCancel(ctx, interval, false, func(ctx context.Context) (bool, error) {
+		az.reconcileRouteDrifts(ctx)
+		return false, nil
+	})
+	logger.V(2).Info("Stopped", "error", err.Error())
+}
+
+// reconcileRouteDrifts reports the drifted routes by metrics, and repairs them in the repair mode.
+func (az *Cloud) reconcileRouteDrifts(ctx context.Context) {
+	logger := log.FromContextOrBackground(ctx).WithName("reconcileRouteDrifts")
+	if az.warmStandby.Load() {
+		logger.V(4).Info("Skipped as the cloud is in warm standby")
+		return
+	}
+	if !az.armRequestBudget.allowBackground() {
+		logger.V(2).Info("Skipped as the ARM request budget is nearly consumed")
+		return
+	}
+	drifts, err := az.detectRouteDrifts(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to detect the drifted routes")
+		return
+	}
+
+	routeDriftCount.Reset()
+	for _, routeTableName := range az.getRouteTableNames() {
+		routeDriftCount.WithLabelValues(routeTableName, routeDriftReasonDeletedNode).Set(0)
+		routeDriftCount.WithLabelValues(routeTableName, routeDriftReasonWrongNextHop).Set(0)
+	}
+	for _, drift := range drifts {
+		logger.V(2).Info("Route drifted", "route", drift.route.Name, "routeTable", drift.routeTableName, "reason", drift.reason)
+		routeDriftCount.WithLabelValues(drift.routeTableName, drift.reason).Inc()
+	}
+
+	if !strings.EqualFold(az.RouteDriftReconciliationMode, consts.RouteDriftReconciliationModeRepair) {
+		return
+	}
+	for _, drift := range drifts {
+		result := "succeeded"
+		if err := az.repairRouteDrift(ctx, drift); err != nil {
+			logger.Error(err, "Failed to repair the route", "route", drift.route.Name, "routeTable", drift.routeTableName)
+			result = "failed"
+		}
+		routeDriftRepairCount.WithLabelValues(drift.routeTableName, drift.reason, result).Inc()
+	}
+}
+
+// detectRouteDrifts returns the managed routes targeting deleted nodes and the ones whose next hops are
+// not the ones CreateRoute chooses. Only the routes to virtual appliances and virtual network gateways are
+// checked, and the next hops are not checked for the nodes without known IPs.
+func (az *Cloud) detectRouteDrifts(ctx context.Context) ([]routeDrift, error) {
+	// The nodes are unknown before the node informer is synced, and all routes would be reported.
+	if az.nodeInformerSynced == nil || !az.nodeInformerSynced() {
+		log.FromContextOrBackground(ctx).WithName("detectRouteDrifts").V(4).Info("Node informer is not synced, skipping")
+		return nil, nil
+	}
+
+	var drifts []routeDrift
+	for _, routeTableName := range az.getRouteTableNames() {
+		routeTable, err := az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeForceRefresh)
+		if err != nil {
+			return nil, err
+		}
+		if routeTable == nil || routeTable.Properties == nil {
+			continue
+		}
+		drifts = append(drifts, az.detectRouteTableDrifts(ctx, routeTableName, routeTable.Properties.Routes)...)
+	}
+	return drifts, nil
+}
+
+func (az *Cloud) detectRouteTableDrifts(ctx context.Context, routeTableName string, routes []*armnetwork.Route) []routeDrift {
+	// overriddenRoute is a route whose next hop is overridden by the next hop policies or the IP configurations.
+	type overriddenRoute struct {
+		kubeRoute *cloudprovider.Route
+		route     *armnetwork.Route
+		nodeIPs   *utilsets.IgnoreCaseSet
+	}
+	var drifts []routeDrift
+	var overriddenRoutes []overriddenRoute
+	az.nodeCachesLock.RLock()
+	for _, route := range routes {
+		if route == nil || route.Properties == nil || !isManagedRouteNextHopType(ptr.Deref(route.Properties.NextHopType, "")) {
+			continue
+		}
+		routeName := ptr.Deref(route.Name, "")
+		if !az.isManagedRoute(routeName) {
+			continue
+		}
+		nodeName := az.mapRouteNameToNodeName(routeName)
+		kubeRoute := &cloudprovider.Route{
+			Name:            routeName,
+			TargetNode:      nodeName,
+			DestinationCIDR: ptr.Deref(route.Properties.AddressPrefix, ""),
+		}
+
+		if !az.nodeNames.Has(string(nodeName)) {
+			drifts = append(drifts, routeDrift{routeTableName: routeTableName, route: kubeRoute, reason: routeDriftReasonDeletedNode})
+			continue
+		}
+		nodeIPs := az.nodePrivateIPs[strings.ToLower(string(nodeName))]
+		// The next hops chosen by the next hop policies or the configured IP configurations are looked up
+		// like CreateRoute does, because the nodes may not report them.
+		if len(az.RouteNextHopPolicies) > 0 || az.RouteIPConfigurationName != "" || az.nodeRouteIPConfigurations[string(nodeName)] != "" {
+			var ips []string
+			if nodeIPs != nil {
+				ips = nodeIPs.UnsortedList()
+			}
+			overriddenRoutes = append(overriddenRoutes, overriddenRoute{kubeRoute: kubeRoute, route: route, nodeIPs: utilsets.NewString(ips...)})
+			continue
+		}
+		if nodeIPs != nil && nodeIPs.Len() > 0 && !nodeIPs.Has(ptr.Deref(route.Properties.NextHopIPAddress, "")) {
+			drifts = append(drifts, routeDrift{routeTableName: routeTableName, route: kubeRoute, reason: routeDriftReasonWrongNextHop})
+		}
+	}
+	az.nodeCachesLock.RUnlock()
+
+	for _, r := range overriddenRoutes {
+		// The NICs of the nodes are read from the cache, and only the suspected drifts are checked again
+		// with the latest NICs.
+		drifted, err := az.isRouteNextHopDrifted(ctx, r.route, r.kubeRoute.TargetNode, r.nodeIPs, azcache.CacheReadTypeDefault)
+		if err == nil && drifted {
+			drifted, err = az.isRouteNextHopDrifted(ctx, r.route, r.kubeRoute.TargetNode, r.nodeIPs, azcache.CacheReadTypeForceRefresh)
+		}
+		if err != nil {
+			log.FromContextOrBackground(ctx).WithName("detectRouteTableDrifts").Error(err, "Failed to check the next hop of the route", "route", r.kubeRoute.Name)
+			continue
+		}
+		if drifted {
+			drifts = append(drifts, routeDrift{routeTableName: routeTableName, route: r.kubeRoute, reason: routeDriftReasonWrongNextHop})
+		}
+	}
+	return drifts
+}
+
+// isRouteNextHopDrifted returns true if the next hop of the route is not the one of the next hop policies or the
+// IP configurations in the NICs of the node read with crt. If neither of them applies, the next hop should be
+// one of the node IPs, which is only checked if the IPs are known.
+func (az *Cloud) isRouteNextHopDrifted(ctx context.Context, route *armnetwork.Route, nodeName types.NodeName, nodeIPs *utilsets.IgnoreCaseSet, crt azcache.AzureCacheReadType) (bool, error) {
+	nextHopType, targetIP, overridden, err := az.getRouteNextHopOverride(ctx, nodeName, ptr.Deref(route.Properties.AddressPrefix, ""), crt)
+	if err != nil {
+		return false, err
+	}
+	if !overridden {
+		if ptr.Deref(route.Properties.NextHopType, "") != armnetwork.RouteNextHopTypeVirtualAppliance {
+			return true, nil
+		}
+		return nodeIPs.Len() > 0 && !nodeIPs.Has(ptr.Deref(route.Properties.NextHopIPAddress, "")), nil
+	}
+	return !strings.EqualFold(string(ptr.Deref(route.Properties.NextHopType, "")), string(nextHopType)) ||
+		!strings.EqualFold(ptr.Deref(route.Properties.NextHopIPAddress, ""), targetIP), nil
+}
+
+// isManagedRouteNextHopType returns true if CreateRoute creates the routes with the next hop type, i.e. the
+// virtual appliances and the virtual network gateways of the next hop policies.
+func isManagedRouteNextHopType(nextHopType armnetwork.RouteNextHopType) bool {
+	return nextHopType == armnetwork.RouteNextHopTypeVirtualAppliance || nextHopType == armnetwork.RouteNextHopTypeVirtualNetworkGateway
+}
+
+// repairRouteDrift deletes the route of the deleted node, or recreates the route with the current IP of the node.
+// Only the managed routes are repaired, which requires routeNamePrefix to tell them from the user-defined routes.
+func (az *Cloud) repairRouteDrift(ctx context.Context, drift routeDrift) error {
+	if az.RouteNamePrefix == "" || !az.isManagedRoute(drift.route.Name) {
+		return fmt.Errorf("refusing to repair route %s which is not prefixed by routeNamePrefix %q", drift.route.Name, az.RouteNamePrefix)
+	}
+	switch drift.reason {
+	case routeDriftReasonDeletedNode:
+		return az.DeleteRoute(ctx, "", drift.route)
+	case routeDriftReasonWrongNextHop:
+		return az.CreateRoute(ctx, "", "", drift.route)
+	}
+	return nil
+}
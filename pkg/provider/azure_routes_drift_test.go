@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+func TestReconcileRouteDrifts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRoute := func(name, cidr, nextHop string, nextHopType armnetwork.RouteNextHopType) *armnetwork.Route {
+		return &armnetwork.Route{
+			Name: ptr.To(name),
+			Properties: &armnetwork.RoutePropertiesFormat{
+				AddressPrefix:    ptr.To(cidr),
+				NextHopType:      ptr.To(nextHopType),
+				NextHopIPAddress: ptr.To(nextHop),
+			},
+		}
+	}
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		VMSet:          mockVMSet,
+		Config: config.Config{
+			RouteTableName:               "rt",
+			RouteNamePrefix:              "k8s-",
+			RouteDriftReconciliationMode: consts.RouteDriftReconciliationModeAudit,
+		},
+		nodeNames:          utilsets.NewString("node0", "node1"),
+		nodePrivateIPs:     map[string]*utilsets.IgnoreCaseSet{"node0": utilsets.NewString("10.0.0.4"), "node1": utilsets.NewString("10.0.0.5")},
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+
+	var lock sync.Mutex
+	routeTable := &armnetwork.RouteTable{
+		Name: ptr.To("rt"),
+		Properties: &armnetwork.RouteTablePropertiesFormat{
+			Routes: []*armnetwork.Route{
+				newRoute("k8s-node0", "10.244.0.0/24", "10.0.0.4", armnetwork.RouteNextHopTypeVirtualAppliance),
+				newRoute("k8s-node1", "10.244.1.0/24", "10.0.0.9", armnetwork.RouteNextHopTypeVirtualAppliance),
+				newRoute("k8s-node2", "10.244.2.0/24", "10.0.0.6", armnetwork.RouteNextHopTypeVirtualAppliance),
+				newRoute("internet", "0.0.0.0/0", "", armnetwork.RouteNextHopTypeInternet),
+				newRoute("user-nva", "10.1.0.0/16", "10.0.0.100", armnetwork.RouteNextHopTypeVirtualAppliance),
+			},
+		},
+	}
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ azcache.AzureCacheReadType) (*armnetwork.RouteTable, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		routes := make([]*armnetwork.Route, len(routeTable.Properties.Routes))
+		copy(routes, routeTable.Properties.Routes)
+		return &armnetwork.RouteTable{Name: routeTable.Name, Properties: &armnetwork.RouteTablePropertiesFormat{Routes: routes}}, nil
+	}).AnyTimes()
+	mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rt armnetwork.RouteTable) (*armnetwork.RouteTable, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		routeTable = &rt
+		return &rt, nil
+	}).AnyTimes()
+
+	drifts, err := cloud.detectRouteDrifts(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []routeDrift{
+		{routeTableName: "rt", route: &cloudprovider.Route{Name: "k8s-node1", TargetNode: "node1", DestinationCIDR: "10.244.1.0/24"}, reason: routeDriftReasonWrongNextHop},
+		{routeTableName: "rt", route: &cloudprovider.Route{Name: "k8s-node2", TargetNode: "node2", DestinationCIDR: "10.244.2.0/24"}, reason: routeDriftReasonDeletedNode},
+	}, drifts)
+
+	// The routes should not be changed in the audit mode.
+	cloud.reconcileRouteDrifts(context.TODO())
+	assert.Len(t, routeTable.Properties.Routes, 5)
+
+	cloud.RouteDriftReconciliationMode = consts.RouteDriftReconciliationModeRepair
+	cloud.routeUpdater = newDelayedRouteUpdater(cloud, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cloud.routeUpdater.run(ctx)
+	mockVMSet.EXPECT().GetIPByNodeName(gomock.Any(), "node1").Return("10.0.0.5", "", nil)
+	cloud.reconcileRouteDrifts(context.TODO())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.ElementsMatch(t, []*armnetwork.Route{
+		newRoute("k8s-node0", "10.244.0.0/24", "10.0.0.4", armnetwork.RouteNextHopTypeVirtualAppliance),
+		newRoute("k8s-node1", "10.244.1.0/24", "10.0.0.5", armnetwork.RouteNextHopTypeVirtualAppliance),
+		newRoute("internet", "0.0.0.0/0", "", armnetwork.RouteNextHopTypeInternet),
+		newRoute("user-nva", "10.1.0.0/16", "10.0.0.100", armnetwork.RouteNextHopTypeVirtualAppliance),
+	}, routeTable.Properties.Routes, "the user-defined route should be kept")
+
+	// The routes are not repaired without routeNamePrefix.
+	cloud.RouteNamePrefix = ""
+	assert.Error(t, cloud.repairRouteDrift(context.TODO(), routeDrift{
+		routeTableName: "rt",
+		route:          &cloudprovider.Route{Name: "user-nva", TargetNode: "user-nva", DestinationCIDR: "10.1.0.0/16"},
+		reason:         routeDriftReasonDeletedNode,
+	}))
+}
+
+func TestDetectRouteDriftsWithNextHopPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRoute := func(name, nextHop string, nextHopType armnetwork.RouteNextHopType) *armnetwork.Route {
+		return &armnetwork.Route{
+			Name: ptr.To(name),
+			Properties: &armnetwork.RoutePropertiesFormat{
+				AddressPrefix:    ptr.To("10.244.0.0/24"),
+				NextHopType:      ptr.To(nextHopType),
+				NextHopIPAddress: ptr.To(nextHop),
+			},
+		}
+	}
+	nicInSubnet := func(subnetName string) *armnetwork.Interface {
+		return &armnetwork.Interface{
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				IPConfigurations: []*armnetwork.InterfaceIPConfiguration{{
+					Name: ptr.To("ipconfig1"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						PrivateIPAddress: ptr.To("10.0.0.4"),
+						Subnet:           &armnetwork.Subnet{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/" + subnetName)},
+					},
+				}},
+			},
+		}
+	}
+
+	mockVMSet := NewMockVMSet(ctrl)
+	cloud := &Cloud{
+		VMSet: mockVMSet,
+		Config: config.Config{
+			RouteNamePrefix: "k8s-",
+			RouteNextHopPolicies: []config.RouteNextHopPolicy{
+				{SubnetNames: []string{"gateway"}, NextHopType: string(armnetwork.RouteNextHopTypeVirtualNetworkGateway)},
+			},
+		},
+		nodeNames:      utilsets.NewString("node0", "node1", "node2"),
+		unmanagedNodes: utilsets.NewString(),
+		nodePrivateIPs: map[string]*utilsets.IgnoreCaseSet{"node2": utilsets.NewString("10.0.0.6")},
+	}
+	cloud.routeNodeInterfacesCache, _ = cloud.newRouteNodeInterfacesCache()
+	// The NICs of the nodes with suspected drifts are read again.
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node0").Return([]*armnetwork.Interface{nicInSubnet("gateway")}, nil)
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node1").Return([]*armnetwork.Interface{nicInSubnet("gateway")}, nil).Times(2)
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node2").Return([]*armnetwork.Interface{nicInSubnet("default")}, nil).Times(2)
+
+	// The routes whose next hops do not follow the policies, e.g. after the policies changed, should be reported.
+	drifts := cloud.detectRouteTableDrifts(context.TODO(), "rt", []*armnetwork.Route{
+		newRoute("k8s-node0", "", armnetwork.RouteNextHopTypeVirtualNetworkGateway),
+		newRoute("k8s-node1", "10.0.0.5", armnetwork.RouteNextHopTypeVirtualAppliance),
+		newRoute("k8s-node2", "", armnetwork.RouteNextHopTypeVirtualNetworkGateway),
+		newRoute("k8s-node3", "", armnetwork.RouteNextHopTypeVirtualNetworkGateway),
+	})
+	assert.Equal(t, []routeDrift{
+		{routeTableName: "rt", route: &cloudprovider.Route{Name: "k8s-node3", TargetNode: "node3", DestinationCIDR: "10.244.0.0/24"}, reason: routeDriftReasonDeletedNode},
+		{routeTableName: "rt", route: &cloudprovider.Route{Name: "k8s-node1", TargetNode: "node1", DestinationCIDR: "10.244.0.0/24"}, reason: routeDriftReasonWrongNextHop},
+		{routeTableName: "rt", route: &cloudprovider.Route{Name: "k8s-node2", TargetNode: "node2", DestinationCIDR: "10.244.0.0/24"}, reason: routeDriftReasonWrongNextHop},
+	}, drifts)
+
+	// The routes without drifts are checked against the cached NICs.
+	drifts = cloud.detectRouteTableDrifts(context.TODO(), "rt", []*armnetwork.Route{
+		newRoute("k8s-node0", "", armnetwork.RouteNextHopTypeVirtualNetworkGateway),
+		newRoute("k8s-node2", "10.0.0.6", armnetwork.RouteNextHopTypeVirtualAppliance),
+	})
+	assert.Empty(t, drifts)
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	azmetrics "sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+const (
+	// routeControllerMetricsSubsystem is the subsystem of the workqueue metrics of the route controller.
+	routeControllerMetricsSubsystem = "route_controller"
+	// routeUpdaterQueueName is the name of the queue of the route operations batched by the delayedRouteUpdater.
+	routeUpdaterQueueName = "route-updater"
+
+	routeMetricOperationCreate = "create"
+	routeMetricOperationDelete = "delete"
+
+	routeMetricResultSucceeded = "succeeded"
+	routeMetricResultFailed    = "failed"
+)
+
+var (
+	routeManagedCount = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_managed_count",
+			Help:           "Number of managed routes in the route table found by the last listing of the routes",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"route_table"},
+	)
+	routePendingNodes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_pending_nodes",
+			Help:           "Number of managed nodes with pod CIDRs but no route found by the last listing of the routes",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	routePendingOperations = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_pending_operations",
+			Help:           "Number of route operations waiting for the next batch update of the route tables",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	routeOperationDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_operation_duration_seconds",
+			Help:           "Duration of the creations and the deletions of the routes of the nodes, including the wait for the batch update of the route tables",
+			Buckets:        []float64{1, 5, 10, 20, 30, 60, 120, 300, 600},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "result"},
+	)
+	routeOperationErrorCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_operation_errors_total",
+			Help:           "Number of failed creations and deletions of the routes by node, the series of a node are removed once its routes are deleted after the node is gone",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "node"},
+	)
+	routeNodeConvergenceDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_node_convergence_duration_seconds",
+			Help:           "Time from the creation of a node to the creation of its first route, for the nodes created after the controller started",
+			Buckets:        []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	routeTableSyncDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "route_table_sync_duration_seconds",
+			Help:           "Duration of the batch updates of the route tables applying the pending route operations, including the wait for the routes to take effect",
+			Buckets:        []float64{1, 5, 10, 20, 30, 60, 120, 300},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"route_table", "result"},
+	)
+
+	registerRouteMetricsOnce sync.Once
+)
+
+func registerRouteMetrics() {
+	registerRouteMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(routeManagedCount)
+		legacyregistry.MustRegister(routePendingNodes)
+		legacyregistry.MustRegister(routePendingOperations)
+		legacyregistry.MustRegister(routeOperationDuration)
+		legacyregistry.MustRegister(routeOperationErrorCount)
+		legacyregistry.MustRegister(routeNodeConvergenceDuration)
+		legacyregistry.MustRegister(routeTableSyncDuration)
+	})
+}
+
+// routeMetricsTracker reports the metrics of the route reconciliation, so the convergence of the routes
+// lagging behind the creation of the nodes can be alerted on. A nil routeMetricsTracker reports nothing.
+type routeMetricsTracker struct {
+	lock sync.Mutex
+	// convergedNodes are the nodes whose first routes have been created since the start
+	convergedNodes *utilsets.IgnoreCaseSet
+	// startedAt is when the tracker started. The convergence of the nodes created before is not known, since
+	// their routes may have been created by the previous controller.
+	startedAt time.Time
+
+	now func() time.Time
+}
+
+func newRouteMetricsTracker() *routeMetricsTracker {
+	registerRouteMetrics()
+	return &routeMetricsTracker{
+		convergedNodes: utilsets.NewString(),
+		startedAt:      time.Now(),
+		now:            time.Now,
+	}
+}
+
+// observeRoutes records the managed routes of a route table found by the listing of the routes.
+func (t *routeMetricsTracker) observeRoutes(routeTableName string, managedRoutes int) {
+	if t == nil {
+		return
+	}
+	routeManagedCount.WithLabelValues(strings.ToLower(routeTableName)).Set(float64(managedRoutes))
+}
+
+// observePendingNodes records the managed nodes with pod CIDRs but no route.
+func (t *routeMetricsTracker) observePendingNodes(pendingNodes int) {
+	if t == nil {
+		return
+	}
+	routePendingNodes.Set(float64(pendingNodes))
+}
+
+// observePendingOperations records the route operations waiting for the next batch update.
+func (t *routeMetricsTracker) observePendingOperations(pendingOperations int) {
+	if t == nil {
+		return
+	}
+	routePendingOperations.Set(float64(pendingOperations))
+}
+
+// observeRouteOperation records the duration and the result of the creation or the deletion of the route of the node.
+func (t *routeMetricsTracker) observeRouteOperation(operation, nodeName string, start time.Time, err error) {
+	if t == nil {
+		return
+	}
+	result := routeMetricResultSucceeded
+	if err != nil {
+		result = routeMetricResultFailed
+		routeOperationErrorCount.WithLabelValues(operation, strings.ToLower(nodeName)).Inc()
+	}
+	routeOperationDuration.WithLabelValues(operation, result).Observe(t.now().Sub(start).Seconds())
+}
+
+// observeRouteCreated records the time from the creation of the node to the creation of its first route.
+// The nodes whose routes are created again, e.g. with new next hops, are not recorded twice, and the nodes
+// created before the tracker started are not recorded, e.g. when their routes are recreated after a restart.
+func (t *routeMetricsTracker) observeRouteCreated(nodeName string, nodeCreationTime time.Time) {
+	if t == nil || nodeCreationTime.IsZero() || nodeCreationTime.Before(t.startedAt) {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.convergedNodes.Has(nodeName) {
+		return
+	}
+	t.convergedNodes.Insert(nodeName)
+	routeNodeConvergenceDuration.Observe(t.now().Sub(nodeCreationTime).Seconds())
+}
+
+// forgetNode removes the metrics of the deleted node.
+func (t *routeMetricsTracker) forgetNode(nodeName string) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.convergedNodes.Delete(nodeName)
+	for _, operation := range []string{routeMetricOperationCreate, routeMetricOperationDelete} {
+		routeOperationErrorCount.DeleteLabelValues(operation, strings.ToLower(nodeName))
+	}
+}
+
+// observeRouteTableSync records the duration and the result of the batch update of the route table.
+func (t *routeMetricsTracker) observeRouteTableSync(routeTableName string, start time.Time, err error) {
+	if t == nil {
+		return
+	}
+	result := routeMetricResultSucceeded
+	if err != nil {
+		result = routeMetricResultFailed
+	}
+	routeTableSyncDuration.WithLabelValues(strings.ToLower(routeTableName), result).Observe(t.now().Sub(start).Seconds())
+}
+
+// observeRoutePendingNodes records the managed nodes with pod CIDRs, not marked for deletion, but without any of the routes.
+func (az *Cloud) observeRoutePendingNodes(routes []*cloudprovider.Route, unmanagedNodes *utilsets.IgnoreCaseSet) {
+	if az.routeMetrics == nil || az.nodeLister == nil {
+		return
+	}
+	nodes, err := az.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.V(4).InfoS("observeRoutePendingNodes: failed to list the nodes", "error", err)
+		return
+	}
+	routedNodes := utilsets.NewString()
+	for _, route := range routes {
+		routedNodes.Insert(string(route.TargetNode))
+	}
+	pendingNodes := 0
+	for _, node := range nodes {
+		if len(node.Spec.PodCIDRs) == 0 && node.Spec.PodCIDR == "" {
+			continue
+		}
+		if routedNodes.Has(node.Name) || unmanagedNodes.Has(node.Name) || az.IsNodeMarkedForDeletion(node) {
+			continue
+		}
+		pendingNodes++
+	}
+	az.routeMetrics.observePendingNodes(pendingNodes)
+}
+
+// observeRouteCreated records the convergence of the routes of the node.
+func (az *Cloud) observeRouteCreated(nodeName string) {
+	if az.routeMetrics == nil || az.nodeLister == nil {
+		return
+	}
+	node, err := az.nodeLister.Get(nodeName)
+	if err != nil {
+		return
+	}
+	az.routeMetrics.observeRouteCreated(nodeName, node.CreationTimestamp.Time)
+}
+
+// observeRouteDeleted removes the metrics of the node once its routes are deleted after the node is gone.
+func (az *Cloud) observeRouteDeleted(nodeName string) {
+	if az.routeMetrics == nil || az.nodeLister == nil {
+		return
+	}
+	if _, err := az.nodeLister.Get(nodeName); apierrors.IsNotFound(err) {
+		az.routeMetrics.forgetNode(nodeName)
+	}
+}
+
+// routeQueueMetrics reports the standard workqueue metrics of the route operations batched by the delayedRouteUpdater,
+// which is the backlog of the route controller. A nil routeQueueMetrics reports nothing.
+type routeQueueMetrics struct {
+	depth        workqueue.GaugeMetric
+	adds         workqueue.CounterMetric
+	latency      workqueue.HistogramMetric
+	workDuration workqueue.HistogramMetric
+	retries      workqueue.CounterMetric
+}
+
+func newRouteQueueMetrics() *routeQueueMetrics {
+	provider := azmetrics.NewWorkqueueMetricsProvider(routeControllerMetricsSubsystem)
+	return &routeQueueMetrics{
+		depth:        provider.NewDepthMetric(routeUpdaterQueueName),
+		adds:         provider.NewAddsMetric(routeUpdaterQueueName),
+		latency:      provider.NewLatencyMetric(routeUpdaterQueueName),
+		workDuration: provider.NewWorkDurationMetric(routeUpdaterQueueName),
+		retries:      provider.NewRetriesMetric(routeUpdaterQueueName),
+	}
+}
+
+// add records the route operation added to the queue.
+func (m *routeQueueMetrics) add() {
+	if m == nil {
+		return
+	}
+	m.depth.Inc()
+	m.adds.Inc()
+}
+
+// take records the route operation taken out of the queue for the batch update of its route table.
+func (m *routeQueueMetrics) take(op *delayedRouteOperation) {
+	if m == nil {
+		return
+	}
+	m.depth.Dec()
+	if !op.queuedAt.IsZero() {
+		m.latency.Observe(time.Since(op.queuedAt).Seconds())
+	}
+}
+
+// done records the duration of the batch update of a route table.
+func (m *routeQueueMetrics) done(start time.Time) {
+	if m == nil {
+		return
+	}
+	m.workDuration.Observe(time.Since(start).Seconds())
+}
+
+// retry records the batch update of a route table retried on the conflicts of the etags.
+func (m *routeQueueMetrics) retry() {
+	if m == nil {
+		return
+	}
+	m.retries.Inc()
+}
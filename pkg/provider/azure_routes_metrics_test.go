@@ -0,0 +1,209 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+func TestRouteMetricsTracker(t *testing.T) {
+	routeOperationDuration.Reset()
+	routeOperationErrorCount.Reset()
+	routeTableSyncDuration.Reset()
+
+	now := time.Now()
+	tracker := newRouteMetricsTracker()
+	tracker.startedAt = now.Add(-time.Hour)
+	tracker.now = func() time.Time { return now }
+	convergedCount, err := testutil.GetHistogramMetricCount(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+	convergedSum, err := testutil.GetHistogramMetricValue(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+
+	tracker.observeRouteOperation(routeMetricOperationCreate, "Node1", now.Add(-time.Minute), errors.New("conflict"))
+	failures, err := testutil.GetCounterMetricValue(routeOperationErrorCount.WithLabelValues(routeMetricOperationCreate, "node1"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), failures)
+	sum, err := testutil.GetHistogramMetricValue(routeOperationDuration.WithLabelValues(routeMetricOperationCreate, routeMetricResultFailed))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute.Seconds(), sum)
+
+	tracker.observeRouteOperation(routeMetricOperationCreate, "node1", now.Add(-time.Second), nil)
+	count, err := testutil.GetHistogramMetricCount(routeOperationDuration.WithLabelValues(routeMetricOperationCreate, routeMetricResultSucceeded))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	// the convergence is measured from the creation of the node, once per node
+	tracker.observeRouteCreated("node1", now.Add(-2*time.Minute))
+	tracker.observeRouteCreated("NODE1", now.Add(-2*time.Minute))
+	count, err = testutil.GetHistogramMetricCount(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, convergedCount+1, count)
+	sum, err = testutil.GetHistogramMetricValue(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, convergedSum+(2*time.Minute).Seconds(), sum)
+
+	// the metrics of the node are removed once it is forgotten, and a node with the same name converges again
+	tracker.forgetNode("node1")
+	failures, err = testutil.GetCounterMetricValue(routeOperationErrorCount.WithLabelValues(routeMetricOperationCreate, "node1"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), failures)
+	tracker.observeRouteCreated("node1", now.Add(-time.Minute))
+	count, err = testutil.GetHistogramMetricCount(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, convergedCount+2, count)
+
+	// the nodes created before the tracker started are not recorded
+	tracker.observeRouteCreated("old", now.Add(-2*time.Hour))
+	count, err = testutil.GetHistogramMetricCount(routeNodeConvergenceDuration.ObserverMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, convergedCount+2, count)
+
+	tracker.observeRouteTableSync("RouteTable", now.Add(-10*time.Second), nil)
+	sum, err = testutil.GetHistogramMetricValue(routeTableSyncDuration.WithLabelValues("routetable", routeMetricResultSucceeded))
+	assert.NoError(t, err)
+	assert.Equal(t, (10 * time.Second).Seconds(), sum)
+
+	// a nil tracker reports nothing
+	var nilTracker *routeMetricsTracker
+	nilTracker.observeRoutes("routetable", 1)
+	nilTracker.observePendingNodes(1)
+	nilTracker.observePendingOperations(1)
+	nilTracker.observeRouteOperation(routeMetricOperationDelete, "node2", now, errors.New("error"))
+	nilTracker.observeRouteCreated("node2", now)
+	nilTracker.forgetNode("node2")
+	nilTracker.observeRouteTableSync("routetable", now, nil)
+	failures, err = testutil.GetCounterMetricValue(routeOperationErrorCount.WithLabelValues(routeMetricOperationDelete, "node2"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), failures)
+}
+
+func TestObserveRoutePendingNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.routeMetrics = newRouteMetricsTracker()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "routed"}, Spec: v1.NodeSpec{PodCIDRs: []string{"10.244.0.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending"}, Spec: v1.NodeSpec{PodCIDR: "10.244.1.0/24"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}, Spec: v1.NodeSpec{PodCIDRs: []string{"10.244.2.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-cidr"}},
+	} {
+		assert.NoError(t, indexer.Add(node))
+	}
+	az.nodeLister = corelisters.NewNodeLister(indexer)
+
+	routes := []*cloudprovider.Route{{Name: "routed", TargetNode: "Routed", DestinationCIDR: "10.244.0.0/24"}}
+	az.observeRoutePendingNodes(routes, utilsets.NewString("unmanaged"))
+	pending, err := testutil.GetGaugeMetricValue(routePendingNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), pending)
+
+	routes = append(routes, &cloudprovider.Route{Name: "pending", TargetNode: "pending", DestinationCIDR: "10.244.1.0/24"})
+	az.observeRoutePendingNodes(routes, utilsets.NewString("unmanaged"))
+	pending, err = testutil.GetGaugeMetricValue(routePendingNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), pending)
+}
+
+func TestObserveRouteDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	routeOperationErrorCount.Reset()
+	az := GetTestCloud(ctrl)
+	az.routeMetrics = newRouteMetricsTracker()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "existing"}}))
+	az.nodeLister = corelisters.NewNodeLister(indexer)
+
+	for _, nodeName := range []string{"existing", "deleted"} {
+		az.routeMetrics.observeRouteOperation(routeMetricOperationDelete, nodeName, time.Now(), errors.New("error"))
+		az.observeRouteDeleted(nodeName)
+	}
+	// only the metrics of the nodes which are gone are removed
+	failures, err := testutil.GetCounterMetricValue(routeOperationErrorCount.WithLabelValues(routeMetricOperationDelete, "existing"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), failures)
+	failures, err = testutil.GetCounterMetricValue(routeOperationErrorCount.WithLabelValues(routeMetricOperationDelete, "deleted"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), failures)
+}
+
+func TestRouteQueueMetrics(t *testing.T) {
+	queueMetrics := newRouteQueueMetrics()
+	getDepth := func() float64 {
+		v, err := testutil.GetGaugeMetricValue(queueMetrics.depth.(metrics.GaugeMetric))
+		assert.NoError(t, err)
+		return v
+	}
+	getCount := func(m workqueue.HistogramMetric) uint64 {
+		v, err := testutil.GetHistogramMetricCount(m.(metrics.ObserverMetric))
+		assert.NoError(t, err)
+		return v
+	}
+	getCounter := func(m workqueue.CounterMetric) float64 {
+		v, err := testutil.GetCounterMetricValue(m.(metrics.CounterMetric))
+		assert.NoError(t, err)
+		return v
+	}
+	depth, adds, retries := getDepth(), getCounter(queueMetrics.adds), getCounter(queueMetrics.retries)
+	latencyCount, workCount := getCount(queueMetrics.latency), getCount(queueMetrics.workDuration)
+
+	d := &delayedRouteUpdater{az: &Cloud{routeMetrics: newRouteMetricsTracker()}, queueMetrics: queueMetrics}
+	op := d.addOperation(getDeleteRouteOperation(&armnetwork.Route{}, "node", "routetable")).(*delayedRouteOperation)
+	assert.False(t, op.queuedAt.IsZero())
+	assert.Equal(t, depth+1, getDepth())
+	assert.Equal(t, adds+1, getCounter(queueMetrics.adds))
+	// the pending operations are reported by their own gauge as well
+	pendingOperations, err := testutil.GetGaugeMetricValue(routePendingOperations)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), pendingOperations)
+
+	queueMetrics.take(op)
+	queueMetrics.retry()
+	queueMetrics.done(time.Now())
+	assert.Equal(t, depth, getDepth())
+	assert.Equal(t, latencyCount+1, getCount(queueMetrics.latency))
+	assert.Equal(t, workCount+1, getCount(queueMetrics.workDuration))
+	assert.Equal(t, retries+1, getCounter(queueMetrics.retries))
+
+	// a nil routeQueueMetrics reports nothing
+	var nilMetrics *routeQueueMetrics
+	nilMetrics.add()
+	nilMetrics.take(op)
+	nilMetrics.retry()
+	nilMetrics.done(time.Now())
+}
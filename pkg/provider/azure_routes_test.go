@@ -28,12 +28,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/utils/ptr"
 
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/subnet"
 	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
 )
 
@@ -394,6 +399,196 @@ func TestCreateRoute(t *testing.T) {
 	}
 }
 
+func TestCreateRouteWithIPConfigurations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		VMSet:          mockVMSet,
+		Config: config.Config{
+			RouteTableName:           "rt",
+			RouteIPConfigurationName: "missing",
+		},
+		nodeNames:          utilsets.NewString(),
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+	cloud.routeNodeInterfacesCache, _ = cloud.newRouteNodeInterfacesCache()
+	cloud.updateNodeCaches(nil, &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node0",
+		Annotations: map[string]string{consts.RouteIPConfigurationAnnotation: "pods-v6, pods"},
+	}})
+	cloud.routeUpdater = newDelayedRouteUpdater(cloud, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cloud.routeUpdater.run(ctx)
+
+	primaryNIC := &armnetwork.Interface{
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{Name: ptr.To("ipconfig1"), Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{PrivateIPAddress: ptr.To("10.0.0.4")}},
+			},
+		},
+	}
+	podNIC := &armnetwork.Interface{
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{Name: ptr.To("pods-v6"), Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{PrivateIPAddress: ptr.To("fd00::4")}},
+				{Name: ptr.To("pods"), Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{PrivateIPAddress: ptr.To("10.0.1.4")}},
+			},
+		},
+	}
+	var nextHops []string
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{Name: ptr.To("rt"), Properties: &armnetwork.RouteTablePropertiesFormat{}}, nil).Times(2)
+	mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, routeTable armnetwork.RouteTable) (*armnetwork.RouteTable, error) {
+		routes := routeTable.Properties.Routes
+		nextHops = append(nextHops, ptr.Deref(routes[len(routes)-1].Properties.NextHopIPAddress, ""))
+		return nil, nil
+	}).Times(2)
+
+	// The IP configuration of the annotation with the same IP family should be used, even on a secondary NIC.
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node0").Return([]*armnetwork.Interface{primaryNIC, podNIC}, nil)
+	assert.NoError(t, cloud.CreateRoute(context.TODO(), "cluster", "", &cloudprovider.Route{TargetNode: "node0", DestinationCIDR: "10.244.0.0/24"}))
+
+	// The primary IP configuration should be used if none of the configured IP configurations exists.
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node1").Return([]*armnetwork.Interface{primaryNIC}, nil)
+	mockVMSet.EXPECT().GetIPByNodeName(gomock.Any(), "node1").Return("10.0.0.5", "", nil)
+	assert.NoError(t, cloud.CreateRoute(context.TODO(), "cluster", "", &cloudprovider.Route{TargetNode: "node1", DestinationCIDR: "10.244.1.0/24"}))
+
+	assert.Equal(t, []string{"10.0.1.4", "10.0.0.5"}, nextHops)
+}
+
+func TestListRoutesWithOutdatedNextHops(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+	cloud := &Cloud{
+		routeTableRepo:     mockRTRepo,
+		VMSet:              mockVMSet,
+		Config:             config.Config{RouteTableName: "rt"},
+		nodeNames:          utilsets.NewString(),
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+	cloud.routeNodeInterfacesCache, _ = cloud.newRouteNodeInterfacesCache()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node0"}}
+	cloud.updateNodeCaches(nil, node)
+	assert.False(t, cloud.nodesWithOutdatedRouteNextHops.Has("node0"), "nodes without next hop overrides should not be checked")
+
+	newNode := node.DeepCopy()
+	newNode.Annotations = map[string]string{consts.RouteIPConfigurationAnnotation: "pods"}
+	cloud.updateNodeCaches(node, newNode)
+	assert.True(t, cloud.nodesWithOutdatedRouteNextHops.Has("node0"), "nodes whose annotation changed should be checked")
+
+	routeTable := &armnetwork.RouteTable{
+		Name: ptr.To("rt"),
+		Tags: map[string]*string{},
+		Properties: &armnetwork.RouteTablePropertiesFormat{
+			Routes: []*armnetwork.Route{{
+				Name: ptr.To("node0"),
+				Properties: &armnetwork.RoutePropertiesFormat{
+					AddressPrefix:    ptr.To("10.244.0.0/24"),
+					NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
+					NextHopIPAddress: ptr.To("10.0.0.4"),
+				},
+			}},
+		},
+	}
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(routeTable, nil).Times(2)
+	nics := []*armnetwork.Interface{{
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{Name: ptr.To("pods"), Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{PrivateIPAddress: ptr.To("10.0.1.4")}},
+			},
+		},
+	}}
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node0").Return(nics, nil).Times(2)
+
+	// The route with the outdated next hop should be left out, so the route controller recreates it.
+	routes, err := cloud.ListRoutes(context.TODO(), "cluster")
+	assert.NoError(t, err)
+	assert.Empty(t, routes)
+	assert.True(t, cloud.nodesWithOutdatedRouteNextHops.Has("node0"))
+
+	// The node should not be checked anymore after its route is updated.
+	routeTable.Properties.Routes[0].Properties.NextHopIPAddress = ptr.To("10.0.1.4")
+	routes, err = cloud.ListRoutes(context.TODO(), "cluster")
+	assert.NoError(t, err)
+	assert.Equal(t, []*cloudprovider.Route{{Name: "node0", TargetNode: "node0", DestinationCIDR: "10.244.0.0/24"}}, routes)
+	assert.False(t, cloud.nodesWithOutdatedRouteNextHops.Has("node0"))
+}
+
+func TestCreateRouteWithNextHopPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		VMSet:          mockVMSet,
+		Config: config.Config{
+			RouteTableName: "rt",
+			RouteNextHopPolicies: []config.RouteNextHopPolicy{
+				{SubnetNames: []string{"firewalled"}, NextHopType: string(armnetwork.RouteNextHopTypeVirtualAppliance), NextHopIPAddress: "fd00::1"},
+				{SubnetNames: []string{"other", "Firewalled"}, NextHopType: string(armnetwork.RouteNextHopTypeVirtualAppliance), NextHopIPAddress: "10.1.0.4"},
+				{SubnetNames: []string{"gateway"}, NextHopType: string(armnetwork.RouteNextHopTypeVirtualNetworkGateway)},
+			},
+		},
+		nodeNames:          utilsets.NewString(),
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+	cloud.routeNodeInterfacesCache, _ = cloud.newRouteNodeInterfacesCache()
+	cloud.routeUpdater = newDelayedRouteUpdater(cloud, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cloud.routeUpdater.run(ctx)
+
+	nicInSubnet := func(subnetName string) *armnetwork.Interface {
+		return &armnetwork.Interface{
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+					{
+						Name: ptr.To("ipconfig1"),
+						Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							PrivateIPAddress: ptr.To("10.0.0.4"),
+							Subnet:           &armnetwork.Subnet{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/" + subnetName)},
+						},
+					},
+				},
+			},
+		}
+	}
+	var nextHops []string
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{Name: ptr.To("rt"), Properties: &armnetwork.RouteTablePropertiesFormat{}}, nil).Times(3)
+	mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, routeTable armnetwork.RouteTable) (*armnetwork.RouteTable, error) {
+		routes := routeTable.Properties.Routes
+		route := routes[len(routes)-1]
+		nextHops = append(nextHops, fmt.Sprintf("%s/%s", ptr.Deref(route.Properties.NextHopType, ""), ptr.Deref(route.Properties.NextHopIPAddress, "")))
+		return nil, nil
+	}).Times(3)
+
+	// The first policy of the subnet with the next hop IP of the same IP family should be used.
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node0").Return([]*armnetwork.Interface{nicInSubnet("firewalled")}, nil)
+	assert.NoError(t, cloud.CreateRoute(context.TODO(), "cluster", "", &cloudprovider.Route{TargetNode: "node0", DestinationCIDR: "10.244.0.0/24"}))
+
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node1").Return([]*armnetwork.Interface{nicInSubnet("gateway")}, nil)
+	assert.NoError(t, cloud.CreateRoute(context.TODO(), "cluster", "", &cloudprovider.Route{TargetNode: "node1", DestinationCIDR: "10.244.1.0/24"}))
+
+	// The IP of the node should be used if none of the policies lists the subnet.
+	mockVMSet.EXPECT().GetInterfacesByNodeName(gomock.Any(), "node2").Return([]*armnetwork.Interface{nicInSubnet("default")}, nil)
+	mockVMSet.EXPECT().GetIPByNodeName(gomock.Any(), "node2").Return("10.0.0.6", "", nil)
+	assert.NoError(t, cloud.CreateRoute(context.TODO(), "cluster", "", &cloudprovider.Route{TargetNode: "node2", DestinationCIDR: "10.244.2.0/24"}))
+
+	assert.Equal(t, []string{"VirtualAppliance/10.1.0.4", "VirtualNetworkGateway/", "VirtualAppliance/10.0.0.6"}, nextHops)
+}
+
 func TestCreateRouteTable(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -415,7 +610,7 @@ func TestCreateRouteTable(t *testing.T) {
 		Properties: &armnetwork.RouteTablePropertiesFormat{},
 	}
 	mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), expectedTable).Return(nil, nil)
-	err := cloud.createRouteTable(context.Background())
+	err := cloud.createRouteTable(context.Background(), cloud.RouteTableName)
 	if err != nil {
 		t.Errorf("unexpected error in creating route table: %v", err)
 		t.FailNow()
@@ -811,13 +1006,272 @@ func TestCleanupOutdatedRoutes(t *testing.T) {
 				az: cloud,
 			}
 
-			routes, changed := d.cleanupOutdatedRoutes(testCase.existingRoutes)
+			routes, changed := d.cleanupOutdatedRoutes(context.TODO(), cloud.RouteTableName, testCase.existingRoutes)
 			assert.Equal(t, testCase.expectedChanged, changed)
 			assert.Equal(t, testCase.expectedRoutes, routes)
 		})
 	}
 }
 
+func TestUpdateRoutesCoalescesOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		Config:         config.Config{RouteTableName: "rt"},
+		nodeNames:      utilsets.NewString(),
+	}
+	d := newDelayedRouteUpdater(cloud, time.Second).(*delayedRouteUpdater)
+	newRoute := func(name string) *armnetwork.Route {
+		return &armnetwork.Route{Name: ptr.To(name), Properties: &armnetwork.RoutePropertiesFormat{AddressPrefix: ptr.To("10.244.0.0/24")}}
+	}
+
+	getStarted, getContinue := make(chan struct{}), make(chan struct{})
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ azcache.AzureCacheReadType) (*armnetwork.RouteTable, error) {
+		close(getStarted)
+		<-getContinue
+		return &armnetwork.RouteTable{Name: ptr.To("rt"), Properties: &armnetwork.RouteTablePropertiesFormat{}}, nil
+	})
+	mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, routeTable armnetwork.RouteTable) (*armnetwork.RouteTable, error) {
+		assert.Equal(t, []*armnetwork.Route{newRoute("node0"), newRoute("node1")}, routeTable.Properties.Routes, "the routes should be applied in a single update")
+		return nil, nil
+	})
+
+	ops := []batchOperation{
+		d.addOperation(getAddRouteOperation(newRoute("node0"), "node0", "rt")),
+		d.addOperation(getAddRouteOperation(newRoute("node1"), "node1", "rt")),
+	}
+	go d.updateRoutes(context.TODO())
+	<-getStarted
+	// The operations added during the update should not be blocked, and wait for the next batch.
+	d.addOperation(getAddRouteOperation(newRoute("node2"), "node2", "rt"))
+	close(getContinue)
+	for _, op := range ops {
+		assert.NoError(t, op.(*delayedRouteOperation).wait().err)
+	}
+	assert.Len(t, d.routesToUpdate, 1)
+}
+
+func TestUpdateRoutesDeletionsFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		Config:         config.Config{RouteTableName: "rt"},
+		nodeNames:      utilsets.NewString(),
+	}
+	d := newDelayedRouteUpdater(cloud, time.Second).(*delayedRouteUpdater)
+	newRoute := func(name string) *armnetwork.Route {
+		return &armnetwork.Route{Name: ptr.To(name), Properties: &armnetwork.RoutePropertiesFormat{AddressPrefix: ptr.To("10.244.0.0/24")}}
+	}
+	getRouteTable := func(_ context.Context, name string, _ azcache.AzureCacheReadType) (*armnetwork.RouteTable, error) {
+		return &armnetwork.RouteTable{Name: ptr.To(name), Properties: &armnetwork.RouteTablePropertiesFormat{
+			Routes: []*armnetwork.Route{newRoute("node1")},
+		}}, nil
+	}
+
+	gomock.InOrder(
+		mockRTRepo.EXPECT().Get(gomock.Any(), "rt-1", gomock.Any()).DoAndReturn(getRouteTable),
+		mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mockRTRepo.EXPECT().Get(gomock.Any(), "rt-0", gomock.Any()).DoAndReturn(getRouteTable),
+		mockRTRepo.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	addOp := d.addOperation(getAddRouteOperation(newRoute("node0"), "node0", "rt-0"))
+	deleteOp := d.addOperation(getDeleteRouteOperation(newRoute("node1"), "node1", "rt-1"))
+	go d.updateRoutes(context.TODO())
+	// the results are sent in the order the route tables are updated
+	for _, op := range []batchOperation{deleteOp, addOp} {
+		assert.NoError(t, op.(*delayedRouteOperation).wait().err)
+	}
+}
+
+func TestUserDefinedRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRoute := func(name, cidr string) *armnetwork.Route {
+		return &armnetwork.Route{Name: ptr.To(name), Properties: &armnetwork.RoutePropertiesFormat{AddressPrefix: ptr.To(cidr)}}
+	}
+	mockRTRepo := routetable.NewMockRepository(ctrl)
+	cloud := &Cloud{
+		routeTableRepo: mockRTRepo,
+		Config: config.Config{
+			RouteTableName:  "rt",
+			RouteNamePrefix: "k8s-",
+		},
+		nodeNames:          utilsets.NewString("node0"),
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+
+	t.Run("ListRoutes should skip the user-defined routes", func(t *testing.T) {
+		mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{
+			Name: ptr.To("rt"),
+			Properties: &armnetwork.RouteTablePropertiesFormat{
+				Routes: []*armnetwork.Route{newRoute("k8s-node0", "10.244.0.0/24"), newRoute("firewall", "0.0.0.0/0")},
+			},
+		}, nil)
+		routes, err := cloud.ListRoutes(context.TODO(), "cluster")
+		assert.NoError(t, err)
+		assert.Equal(t, []*cloudprovider.Route{{Name: "k8s-node0", TargetNode: "node0", DestinationCIDR: "10.244.0.0/24"}}, routes)
+	})
+
+	t.Run("cleanupOutdatedRoutes should rename or replace the routes without the prefix and keep the user-defined routes", func(t *testing.T) {
+		d := &delayedRouteUpdater{az: cloud}
+		routes, changed := d.cleanupOutdatedRoutes(context.TODO(), "rt", []*armnetwork.Route{
+			newRoute("node0", "10.244.0.0/24"),
+			newRoute("firewall", "0.0.0.0/0"),
+			newRoute("k8s-node0", "10.244.0.0/24"),
+		})
+		assert.True(t, changed)
+		assert.Equal(t, []*armnetwork.Route{newRoute("firewall", "0.0.0.0/0"), newRoute("k8s-node0", "10.244.0.0/24")}, routes)
+
+		// The route is renamed instead if the prefixed route doesn't exist yet.
+		routes, changed = d.cleanupOutdatedRoutes(context.TODO(), "rt", []*armnetwork.Route{
+			newRoute("node0", "10.244.0.0/24"),
+			newRoute("firewall", "0.0.0.0/0"),
+		})
+		assert.True(t, changed)
+		assert.Equal(t, []*armnetwork.Route{newRoute("k8s-node0", "10.244.0.0/24"), newRoute("firewall", "0.0.0.0/0")}, routes)
+	})
+
+	t.Run("findConflictingUserRoute should return the user-defined routes taking over the pod CIDR", func(t *testing.T) {
+		route := newRoute("k8s-node0", "10.244.0.0/24")
+		assert.Nil(t, cloud.findConflictingUserRoute([]*armnetwork.Route{newRoute("firewall", "0.0.0.0/0"), newRoute("k8s-node1", "10.244.0.0/24")}, route))
+		assert.Equal(t, "override", ptr.Deref(cloud.findConflictingUserRoute([]*armnetwork.Route{newRoute("override", "10.244.0.128/25")}, route).Name, ""))
+		assert.Equal(t, "same", ptr.Deref(cloud.findConflictingUserRoute([]*armnetwork.Route{newRoute("same", "10.244.0.0/24")}, route).Name, ""))
+	})
+
+	t.Run("updateRoutes should reject the routes conflicting with user-defined routes if failOnUserRouteConflict is set", func(t *testing.T) {
+		cloud.FailOnUserRouteConflict = true
+		defer func() { cloud.FailOnUserRouteConflict = false }()
+		mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{
+			Name: ptr.To("rt"),
+			Properties: &armnetwork.RouteTablePropertiesFormat{
+				Routes: []*armnetwork.Route{newRoute("override", "10.244.0.0/24")},
+			},
+		}, nil)
+		d := newDelayedRouteUpdater(cloud, time.Second).(*delayedRouteUpdater)
+		op := d.addOperation(getAddRouteOperation(newRoute("k8s-node0", "10.244.0.0/24"), "node0", "rt"))
+		go d.updateRoutes(context.TODO())
+		assert.EqualError(t, op.(*delayedRouteOperation).wait().err, "route k8s-node0 conflicts with the user-defined route override with address prefix 10.244.0.0/24")
+	})
+}
+
+func TestRouteTableShards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubnetRepo := subnet.NewMockRepository(ctrl)
+	mockRouteTableRepo := routetable.NewMockRepository(ctrl)
+	cloud := &Cloud{
+		subnetRepo:     mockSubnetRepo,
+		routeTableRepo: mockRouteTableRepo,
+		Config: config.Config{
+			ResourceGroup:              "rg",
+			VnetName:                   "vnet",
+			RouteTableName:             "rt",
+			RouteTableShardCount:       3,
+			RouteTableShardSubnetNames: []string{"subnet0", "subnet1", "subnet2"},
+		},
+		nodeNames: utilsets.NewString("node0", "node1", "node3"),
+		nodePrivateIPs: map[string]*utilsets.IgnoreCaseSet{
+			"node0": utilsets.NewString("10.0.0.4"),
+			"node1": utilsets.NewString("10.0.1.4", "fd00::4"),
+			"node3": utilsets.NewString("10.0.3.4"),
+		},
+	}
+	var err error
+	cloud.routeTableSubnetCache, err = cloud.newRouteTableSubnetCache()
+	assert.NoError(t, err)
+	for i, subnetName := range cloud.RouteTableShardSubnetNames {
+		mockSubnetRepo.EXPECT().Get(gomock.Any(), "rg", "vnet", subnetName).Return(&armnetwork.Subnet{
+			Name: ptr.To(subnetName),
+			Properties: &armnetwork.SubnetPropertiesFormat{
+				AddressPrefixes: []*string{ptr.To(fmt.Sprintf("10.0.%d.0/24", i))},
+			},
+		}, nil).Times(1)
+	}
+
+	assert.Equal(t, []string{"rt", "rt-1", "rt-2"}, cloud.getRouteTableNames())
+	assert.Equal(t, "subnet2", cloud.getRouteTableSubnetName("rt-2"))
+	assert.Equal(t, "", cloud.getRouteTableSubnetName("other"))
+
+	for nodeName, expected := range map[string]string{"node0": "rt", "NODE1": "rt-1"} {
+		routeTableName, err := cloud.getRouteTableNameForNode(context.TODO(), nodeName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, routeTableName, nodeName)
+	}
+	_, err = cloud.getRouteTableNameForNode(context.TODO(), "node3")
+	assert.Error(t, err, "the node should not be assigned if it is not in the subnets of the route tables")
+
+	d := &delayedRouteUpdater{az: cloud}
+	newRoutes := func() []*armnetwork.Route {
+		return []*armnetwork.Route{{Name: ptr.To("node0")}, {Name: ptr.To("node3")}, {Name: ptr.To("unmanaged")}}
+	}
+	cleaned, changed := d.cleanupOutdatedRoutes(context.TODO(), "rt", newRoutes())
+	assert.False(t, changed)
+	assert.Len(t, cleaned, 3)
+	cleaned, changed = d.cleanupOutdatedRoutes(context.TODO(), "rt-1", newRoutes())
+	assert.True(t, changed)
+	assert.Equal(t, []*armnetwork.Route{{Name: ptr.To("node3")}, {Name: ptr.To("unmanaged")}}, cleaned,
+		"the route of node0 should only be deleted from the route tables of other subnets")
+
+	// the routes are deleted from the route tables holding them
+	mockRouteTableRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{
+		Properties: &armnetwork.RouteTablePropertiesFormat{Routes: []*armnetwork.Route{{Name: ptr.To("node0")}}},
+	}, nil)
+	mockRouteTableRepo.EXPECT().Get(gomock.Any(), "rt-1", gomock.Any()).Return(nil, nil)
+	mockRouteTableRepo.EXPECT().Get(gomock.Any(), "rt-2", gomock.Any()).Return(&armnetwork.RouteTable{
+		Properties: &armnetwork.RouteTablePropertiesFormat{Routes: []*armnetwork.Route{{Name: ptr.To("NODE0")}}},
+	}, nil)
+	routeTableNames, err := cloud.getRouteTableNamesWithRoute(context.TODO(), "node0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rt", "rt-2"}, routeTableNames)
+}
+
+func TestEnsureRouteTableAssociated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubnetRepo := subnet.NewMockRepository(ctrl)
+	cloud := &Cloud{
+		subnetRepo: mockSubnetRepo,
+		Config: config.Config{
+			ResourceGroup:              "rg",
+			VnetName:                   "vnet",
+			RouteTableName:             "rt",
+			RouteTableShardCount:       2,
+			RouteTableShardSubnetNames: []string{"subnet0", "subnet1"},
+		},
+	}
+	d := newDelayedRouteUpdater(cloud, time.Second).(*delayedRouteUpdater)
+	routeTable := &armnetwork.RouteTable{Name: ptr.To("rt-1"), ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/routeTables/rt-1")}
+
+	mockSubnetRepo.EXPECT().Get(gomock.Any(), "rg", "vnet", "subnet1").Return(&armnetwork.Subnet{
+		Name:       ptr.To("subnet1"),
+		Properties: &armnetwork.SubnetPropertiesFormat{AddressPrefix: ptr.To("10.0.1.0/24")},
+	}, nil)
+	mockSubnetRepo.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "vnet", "subnet1", armnetwork.Subnet{
+		Name: ptr.To("subnet1"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: ptr.To("10.0.1.0/24"),
+			RouteTable:    &armnetwork.RouteTable{ID: routeTable.ID},
+		},
+	}).Return(nil)
+	assert.NoError(t, d.ensureRouteTableAssociated(context.TODO(), routeTable))
+	// The subnet should not be checked again.
+	assert.NoError(t, d.ensureRouteTableAssociated(context.TODO(), routeTable))
+
+	mockSubnetRepo.EXPECT().Get(gomock.Any(), "rg", "vnet", "subnet0").Return(nil, errors.New("get error"))
+	assert.EqualError(t, d.ensureRouteTableAssociated(context.TODO(), &armnetwork.RouteTable{Name: ptr.To("rt")}), "get error")
+}
+
 func TestEnsureRouteTableTagged(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
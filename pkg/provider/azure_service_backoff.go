@@ -0,0 +1,283 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cloud-provider/api"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/log"
+)
+
+const (
+	// serviceReconcileBackoffJitterFactor is the max jitter factor of the per-service backoff delay.
+	serviceReconcileBackoffJitterFactor = 0.1
+	// defaultServiceReconcileBackoffMaxDelay is the default max delay of the per-service backoff.
+	defaultServiceReconcileBackoffMaxDelay = 5 * time.Minute
+	// defaultServiceReconcileCircuitBreakerProbeInterval is the default interval between two
+	// reconciliations of a parked service.
+	defaultServiceReconcileCircuitBreakerProbeInterval = 30 * time.Minute
+)
+
+// serviceReconcileBackoffEntry records the failures of a service.
+type serviceReconcileBackoffEntry struct {
+	// fingerprint is the hash of the service spec and annotations when the failures happened.
+	fingerprint string
+	failures    int
+	// nextRetry is the time after which the service is retried, or probed if it is parked.
+	nextRetry time.Time
+	parked    bool
+}
+
+// serviceReconcileBackoff delays the reconciliation of a service after it fails, and parks
+// the service when it keeps failing until its spec or annotations change. A parked service
+// is still probed once per probe interval, so it recovers when the failure is fixed outside
+// of the service. Only non-retriable errors are counted, throttling, server errors and
+// conflicts with other operations are left to the regular retries. It prevents a
+// misconfigured service from retrying hot and consuming the ARM quota.
+type serviceReconcileBackoff struct {
+	lock    sync.Mutex
+	entries map[string]*serviceReconcileBackoffEntry
+
+	baseDelay     time.Duration
+	maxDelay      time.Duration
+	maxFailures   int
+	probeInterval time.Duration
+	jitterFactor  float64
+	now           func() time.Time
+}
+
+// newServiceReconcileBackoff creates a new serviceReconcileBackoff. It returns nil if the
+// base delay is not set.
+func newServiceReconcileBackoff(baseDelay, maxDelay time.Duration, maxFailures int, probeInterval time.Duration) *serviceReconcileBackoff {
+	if baseDelay <= 0 {
+		return nil
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultServiceReconcileBackoffMaxDelay
+	}
+	if probeInterval <= 0 {
+		probeInterval = defaultServiceReconcileCircuitBreakerProbeInterval
+	}
+	return &serviceReconcileBackoff{
+		entries:       make(map[string]*serviceReconcileBackoffEntry),
+		baseDelay:     baseDelay,
+		maxDelay:      maxDelay,
+		maxFailures:   maxFailures,
+		probeInterval: probeInterval,
+		jitterFactor:  serviceReconcileBackoffJitterFactor,
+		now:           time.Now,
+	}
+}
+
+// isServiceReconcileErrorRetriable returns true if the error is expected to go away on
+// its own, e.g. throttling, server errors, timeouts, or a conflict with another operation
+// on the same resource. Such errors do not count as failures of the service.
+func isServiceReconcileErrorRetriable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var retryErr *api.RetryError
+	if errors.As(err, &retryErr) {
+		return true
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	switch {
+	case rerr.StatusCode == http.StatusTooManyRequests,
+		rerr.StatusCode == http.StatusRequestTimeout,
+		rerr.StatusCode == http.StatusPreconditionFailed,
+		rerr.StatusCode >= http.StatusInternalServerError:
+		return true
+	case rerr.StatusCode == http.StatusConflict:
+		return strings.EqualFold(rerr.ErrorCode, "AnotherOperationInProgress") ||
+			strings.Contains(strings.ToLower(rerr.Error()), consts.OperationCanceledErrorMessage)
+	}
+	return false
+}
+
+// getServiceFingerprint returns the hash of the spec and annotations of the service. The
+// annotations managed by the cloud provider are left out.
+func getServiceFingerprint(service *v1.Service) string {
+	annotations := service.Annotations
+	if _, found := annotations[consts.ServiceAnnotationLoadBalancerPendingOperation]; found {
+		annotations = maps.Clone(annotations)
+		delete(annotations, consts.ServiceAnnotationLoadBalancerPendingOperation)
+	}
+	data, err := json.Marshal(struct {
+		Spec        v1.ServiceSpec
+		Annotations map[string]string
+	}{
+		Spec:        service.Spec,
+		Annotations: annotations,
+	})
+	if err != nil {
+		log.Background().WithName("getServiceFingerprint").Error(err, "Failed to marshal the service", "service", getServiceName(service))
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// check returns an error if the service should not be reconciled now. The failures are
+// forgotten if the spec or annotations of the service have changed since they happened.
+func (b *serviceReconcileBackoff) check(service *v1.Service) error {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := strings.ToLower(getServiceName(service))
+	entry, ok := b.entries[key]
+	if !ok {
+		return nil
+	}
+	logger := log.Background().WithName("serviceReconcileBackoff").WithValues("service", key)
+	if entry.fingerprint != getServiceFingerprint(service) {
+		logger.V(2).Info("The service has changed, resetting the backoff")
+		delete(b.entries, key)
+		return nil
+	}
+	now := b.now()
+	if entry.parked {
+		if now.Before(entry.nextRetry) {
+			return fmt.Errorf("reconciliation of service %s is parked after %d consecutive failures, update the service to retry now or wait %s for the next probe", key, entry.failures, entry.nextRetry.Sub(now).Round(time.Second))
+		}
+		logger.V(2).Info("Probing the parked service")
+		return nil
+	}
+	if now.Before(entry.nextRetry) {
+		return fmt.Errorf("reconciliation of service %s is backed off for %s after %d consecutive failures", key, entry.nextRetry.Sub(now).Round(time.Second), entry.failures)
+	}
+	return nil
+}
+
+// observe records the result of the reconciliation of the service. It returns true
+// if the service is parked because of this failure. Retriable errors are not counted.
+func (b *serviceReconcileBackoff) observe(service *v1.Service, err error) bool {
+	if b == nil {
+		return false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := strings.ToLower(getServiceName(service))
+	if err == nil {
+		delete(b.entries, key)
+		return false
+	}
+	logger := log.Background().WithName("serviceReconcileBackoff").WithValues("service", key)
+	if isServiceReconcileErrorRetriable(err) {
+		logger.V(4).Info("Not counting the retriable error of the service", "error", err)
+		return false
+	}
+
+	fingerprint := getServiceFingerprint(service)
+	entry, ok := b.entries[key]
+	if !ok || entry.fingerprint != fingerprint {
+		entry = &serviceReconcileBackoffEntry{fingerprint: fingerprint}
+		b.entries[key] = entry
+	}
+	entry.failures++
+
+	if entry.parked {
+		// The probe of the parked service failed, wait for the next one.
+		entry.nextRetry = b.now().Add(b.probeInterval)
+		logger.V(2).Info("The probe of the parked service failed", "nextProbeIn", b.probeInterval)
+		return false
+	}
+
+	delay := b.maxDelay
+	if shift := entry.failures - 1; shift < 32 && b.baseDelay<<shift > 0 && b.baseDelay<<shift < b.maxDelay {
+		delay = b.baseDelay << shift
+	}
+	if b.jitterFactor > 0 {
+		delay = wait.Jitter(delay, b.jitterFactor)
+	}
+	entry.nextRetry = b.now().Add(delay)
+
+	if b.maxFailures > 0 && entry.failures >= b.maxFailures {
+		entry.parked = true
+		entry.nextRetry = b.now().Add(b.probeInterval)
+		logger.Info("Parking the service after consecutive failures", "failures", entry.failures)
+		return true
+	}
+	logger.V(2).Info("Backing off the service after consecutive failures", "delay", delay, "failures", entry.failures)
+	return false
+}
+
+// forget removes the failures of the service.
+func (b *serviceReconcileBackoff) forget(service *v1.Service) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.entries, strings.ToLower(getServiceName(service)))
+}
+
+// parkedServices returns the names of the parked services.
+func (b *serviceReconcileBackoff) parkedServices() []string {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var services []string
+	for key, entry := range b.entries {
+		if entry.parked {
+			services = append(services, key)
+		}
+	}
+	return services
+}
+
+// reconcileServiceWithBackoff reconciles the service unless it is backed off or parked
+// by the per-service backoff. It is only used when the service itself changes, the node
+// updates of the backend pools always go through reconcileService.
+func (az *Cloud) reconcileServiceWithBackoff(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
+	if err := az.serviceReconcileBackoff.check(service); err != nil {
+		return nil, err
+	}
+
+	lbStatus, err := az.reconcileService(ctx, clusterName, service, nodes)
+	if parked := az.serviceReconcileBackoff.observe(service, err); parked {
+		az.Event(service, v1.EventTypeWarning, "ReconciliationParked",
+			fmt.Sprintf("Reconciliation is parked after consecutive failures until the service spec or annotations change or the next probe succeeds, last error: %v", err))
+	}
+	return lbStatus, err
+}
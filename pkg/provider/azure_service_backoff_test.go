@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestNewServiceReconcileBackoff(t *testing.T) {
+	assert.Nil(t, newServiceReconcileBackoff(0, time.Minute, 3, 0))
+
+	b := newServiceReconcileBackoff(time.Second, 0, 3, 0)
+	assert.NotNil(t, b)
+	assert.Equal(t, defaultServiceReconcileBackoffMaxDelay, b.maxDelay)
+	assert.Equal(t, defaultServiceReconcileCircuitBreakerProbeInterval, b.probeInterval)
+}
+
+func TestServiceReconcileBackoff(t *testing.T) {
+	now := time.Now()
+	b := newServiceReconcileBackoff(10*time.Second, 30*time.Second, 4, time.Hour)
+	b.jitterFactor = 0
+	b.now = func() time.Time { return now }
+
+	svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	reconcileErr := errors.New("subnet not found")
+
+	assert.NoError(t, b.check(&svc))
+
+	// the delay doubles after each failure and is capped by the max delay
+	for _, expectedDelay := range []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second} {
+		assert.False(t, b.observe(&svc, reconcileErr))
+		assert.Error(t, b.check(&svc))
+		now = now.Add(expectedDelay - time.Millisecond)
+		assert.Error(t, b.check(&svc))
+		now = now.Add(time.Millisecond)
+		assert.NoError(t, b.check(&svc))
+	}
+
+	// retriable errors are not counted
+	assert.False(t, b.observe(&svc, &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}))
+	assert.NoError(t, b.check(&svc))
+
+	// the service is parked after reaching the threshold
+	assert.True(t, b.observe(&svc, reconcileErr))
+	now = now.Add(time.Hour - time.Second)
+	err := b.check(&svc)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parked")
+
+	// the parked service is probed after the probe interval, and a failed probe waits for the next one
+	now = now.Add(time.Second)
+	assert.NoError(t, b.check(&svc))
+	assert.False(t, b.observe(&svc, reconcileErr))
+	assert.Error(t, b.check(&svc))
+	now = now.Add(time.Hour)
+	assert.NoError(t, b.check(&svc))
+
+	// a successful probe unparks the service
+	assert.False(t, b.observe(&svc, nil))
+	assert.NoError(t, b.check(&svc))
+	for i := 0; i < 4; i++ {
+		b.observe(&svc, reconcileErr)
+	}
+	assert.Error(t, b.check(&svc))
+
+	// the service is unparked after its annotations change
+	svc.Annotations[consts.ServiceAnnotationLoadBalancerInternal] = consts.TrueAnnotationValue
+	assert.NoError(t, b.check(&svc))
+
+	// a successful reconciliation resets the backoff
+	assert.False(t, b.observe(&svc, reconcileErr))
+	assert.Error(t, b.check(&svc))
+	assert.False(t, b.observe(&svc, nil))
+	assert.NoError(t, b.check(&svc))
+
+	// forget removes the failures
+	assert.False(t, b.observe(&svc, reconcileErr))
+	b.forget(&svc)
+	assert.NoError(t, b.check(&svc))
+}
+
+func TestServiceReconcileBackoffNil(t *testing.T) {
+	var b *serviceReconcileBackoff
+	svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+	assert.NoError(t, b.check(&svc))
+	assert.False(t, b.observe(&svc, errors.New("error")))
+	b.forget(&svc)
+}
+
+func TestIsServiceReconcileErrorRetriable(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected bool
+	}{
+		{err: errors.New("subnet not found")},
+		{err: context.DeadlineExceeded, expected: true},
+		{err: fmt.Errorf("wrapped: %w", &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}), expected: true},
+		{err: &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, expected: true},
+		{err: &azcore.ResponseError{StatusCode: http.StatusPreconditionFailed}, expected: true},
+		{err: &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "AnotherOperationInProgress"}, expected: true},
+		{err: &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "InUseSubnetCannotBeDeleted"}},
+		{err: &azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "InvalidResourceReference"}},
+	} {
+		assert.Equal(t, tc.expected, isServiceReconcileErrorRetriable(tc.err), tc.err.Error())
+	}
+}
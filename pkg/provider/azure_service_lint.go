@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// lintLoadBalancerRuleCountRatio is the ratio of the maximum load balancing rules of a load balancer
+// above which it is reported to be about to run out of rules.
+const lintLoadBalancerRuleCountRatio = 0.9
+
+// LintWarning is a problem found by LintServices.
+type LintWarning struct {
+	// Resource is the resource the warning is about, e.g. `Service default/nginx` or `LoadBalancer kubernetes`.
+	Resource string
+	// Message describes the problem.
+	Message string
+}
+
+// LintServices evaluates the annotations of the LoadBalancer services reconciled by the cloud provider against
+// the cloud config, and returns the deprecated annotations, the impossible combinations and the invalid values
+// which would fail the reconciliations. With checkAzure, the public IPs referenced by the services are checked,
+// and the load balancers in the resource group about to run out of load balancing rules are reported as well.
+// The warnings are sorted by resources.
+func (az *Cloud) LintServices(ctx context.Context, services []*v1.Service, checkAzure bool) ([]LintWarning, error) {
+	var warnings []LintWarning
+	for _, service := range services {
+		if !az.wantsLoadBalancer(service) {
+			continue
+		}
+		messages := az.lintService(service)
+		if checkAzure {
+			messages = append(messages, az.lintServicePublicIPs(ctx, service)...)
+		}
+		for _, message := range messages {
+			warnings = append(warnings, LintWarning{Resource: "Service " + getServiceName(service), Message: message})
+		}
+	}
+
+	if checkAzure {
+		lbWarnings, err := az.lintLoadBalancers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, lbWarnings...)
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Resource < warnings[j].Resource })
+	return warnings, nil
+}
+
+// lintService returns the problems of the service which can be found without calling Azure.
+func (az *Cloud) lintService(service *v1.Service) []string {
+	var messages []string
+	ignored := func(annotation, reason string) {
+		if _, found := service.Annotations[annotation]; found {
+			messages = append(messages, fmt.Sprintf("annotation %s is ignored %s", annotation, reason))
+		}
+	}
+
+	// deprecations
+	if service.Spec.LoadBalancerIP != "" {
+		messages = append(messages, fmt.Sprintf("spec.loadBalancerIP is deprecated, use annotation %s or %s instead",
+			consts.ServiceAnnotationLoadBalancerIPDualStack[false], consts.ServiceAnnotationLoadBalancerIPDualStack[true]))
+	}
+	if az.UseStandardLoadBalancer() {
+		ignored(consts.ServiceAnnotationLoadBalancerMode, "by the standard load balancer")
+	}
+
+	// impossible combinations
+	if requiresInternalLoadBalancer(service) {
+		for _, annotation := range []string{
+			consts.ServiceAnnotationPIPNameDualStack[false],
+			consts.ServiceAnnotationPIPNameDualStack[true],
+			consts.ServiceAnnotationPIPPrefixIDDualStack[false],
+			consts.ServiceAnnotationPIPPrefixIDDualStack[true],
+			consts.ServiceAnnotationDNSLabelName,
+			consts.ServiceAnnotationAzurePIPTags,
+			consts.ServiceAnnotationIPTagsForPublicIP,
+			consts.ServiceAnnotationAzurePIPZones,
+			consts.ServiceAnnotationAdditionalPIPNames,
+			consts.ServiceAnnotationAdditionalPIPPrefixID,
+		} {
+			ignored(annotation, "by the internal service")
+		}
+	} else {
+		ignored(consts.ServiceAnnotationLoadBalancerInternalSubnet, "by the external service")
+		for _, isIPv6 := range []bool{false, true} {
+			if getServicePIPName(service, isIPv6) != "" {
+				ignored(consts.ServiceAnnotationPIPPrefixIDDualStack[isIPv6], "with annotation "+consts.ServiceAnnotationPIPNameDualStack[isIPv6])
+			}
+		}
+	}
+	if zone := getServiceZone(service); zone != "" {
+		if !az.useZonalServiceBackendPool(service) {
+			messages = append(messages, fmt.Sprintf("the service is pinned to zone %s, which requires the IP-based backend pools of the standard load balancer", zone))
+		}
+		if pipZones := service.Annotations[consts.ServiceAnnotationAzurePIPZones]; pipZones != "" && !requiresInternalLoadBalancer(service) &&
+			!strings.EqualFold(strings.TrimSpace(pipZones), zone) {
+			messages = append(messages, fmt.Sprintf("the service is pinned to zone %s, but annotation %s is %q",
+				zone, consts.ServiceAnnotationAzurePIPZones, pipZones))
+		}
+	}
+	if names := consts.GetLoadBalancerConfigurationsNames(service); len(names) > 0 {
+		if !az.UseMultipleStandardLoadBalancers() {
+			ignored(consts.ServiceAnnotationLoadBalancerConfigurations, "without the multiple standard load balancers")
+		} else {
+			configured := make(map[string]bool)
+			for _, multiSLBConfig := range az.MultipleStandardLoadBalancerConfigurations {
+				configured[strings.ToLower(multiSLBConfig.Name)] = true
+			}
+			for _, name := range names {
+				if !configured[strings.ToLower(name)] {
+					messages = append(messages, fmt.Sprintf("annotation %s selects load balancer configuration %s, which is not in the cloud config",
+						consts.ServiceAnnotationLoadBalancerConfigurations, name))
+				}
+			}
+		}
+	}
+
+	for _, notice := range az.getLBRuleNotices(service) {
+		messages = append(messages, notice.message)
+	}
+
+	// invalid values, which fail building the load balancing rules and health probes of the service
+	v4Enabled, v6Enabled := getIPFamiliesEnabled(service)
+	for _, isIPv6 := range []bool{false, true} {
+		if isIPv6 && !v6Enabled || !isIPv6 && !v4Enabled {
+			continue
+		}
+		if _, _, err := az.getExpectedLBRules(service, "", "", "", isIPv6); err != nil {
+			messages = append(messages, err.Error())
+			break
+		}
+	}
+	return messages
+}
+
+// lintServicePublicIPs checks the user-assigned public IPs referenced by the annotations of the service.
+func (az *Cloud) lintServicePublicIPs(ctx context.Context, service *v1.Service) []string {
+	if requiresInternalLoadBalancer(service) {
+		return nil
+	}
+	var messages []string
+	pipResourceGroup := az.getPublicIPAddressResourceGroup(service)
+	v4Enabled, v6Enabled := getIPFamiliesEnabled(service)
+	for _, isIPv6 := range []bool{false, true} {
+		pipName := getServicePIPName(service, isIPv6)
+		if isIPv6 && !v6Enabled || !isIPv6 && !v4Enabled || pipName == "" {
+			continue
+		}
+		pip, found, err := az.getPublicIPAddress(ctx, pipResourceGroup, pipName, azcache.CacheReadTypeDefault)
+		switch {
+		case err != nil:
+			messages = append(messages, fmt.Sprintf("failed to get public IP %s: %v", pipName, err))
+		case !found:
+			messages = append(messages, fmt.Sprintf("public IP %s of annotation %s is not found in resource group %s",
+				pipName, consts.ServiceAnnotationPIPNameDualStack[isIPv6], pipResourceGroup))
+		default:
+			if _, isUserAssignedPIP := serviceOwnsPublicIP(service, pip, ""); isUserAssignedPIP {
+				if err := az.validateUserAssignedPublicIP(service, pip, isIPv6); err != nil {
+					messages = append(messages, err.Error())
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// lintLoadBalancers reports the load balancers in the load balancer resource group about to run out of load
+// balancing rules.
+func (az *Cloud) lintLoadBalancers(ctx context.Context) ([]LintWarning, error) {
+	if az.MaximumLoadBalancerRuleCount <= 0 {
+		return nil, nil
+	}
+	rgName := az.getLoadBalancerResourceGroup()
+	lbs, err := az.NetworkClientFactory.GetLoadBalancerClient().List(ctx, rgName)
+	if err != nil {
+		return nil, fmt.Errorf("list the load balancers of resource group %s: %w", rgName, err)
+	}
+
+	var warnings []LintWarning
+	for _, lb := range az.excludeRegionalLoadBalancers(lbs) {
+		if lb == nil || lb.Properties == nil {
+			continue
+		}
+		count := len(lb.Properties.LoadBalancingRules)
+		if float64(count) >= lintLoadBalancerRuleCountRatio*float64(az.MaximumLoadBalancerRuleCount) {
+			warnings = append(warnings, LintWarning{
+				Resource: "LoadBalancer " + ptr.Deref(lb.Name, ""),
+				Message: fmt.Sprintf("the load balancer has %d of the maximum %d load balancing rules, the new services would fail to be placed on it",
+					count, az.MaximumLoadBalancerRuleCount),
+			})
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestLintService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tc := range []struct {
+		desc             string
+		annotations      map[string]string
+		protocol         v1.Protocol
+		loadBalancerIP   string
+		useBasicLB       bool
+		expectedMessages []string
+	}{
+		{
+			desc: "valid service",
+			annotations: map[string]string{
+				consts.ServiceAnnotationPIPNameDualStack[false]:         "pip",
+				consts.ServiceAnnotationLoadBalancerHealthProbeInterval: "10",
+			},
+		},
+		{
+			desc:           "deprecated load balancer IP",
+			loadBalancerIP: "1.2.3.4",
+			expectedMessages: []string{
+				"spec.loadBalancerIP is deprecated, use annotation service.beta.kubernetes.io/azure-load-balancer-ipv4 or service.beta.kubernetes.io/azure-load-balancer-ipv6 instead",
+			},
+		},
+		{
+			desc: "public IP annotations of an internal service",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternal:    consts.TrueAnnotationValue,
+				consts.ServiceAnnotationPIPNameDualStack[false]: "pip",
+				consts.ServiceAnnotationDNSLabelName:            "dns",
+			},
+			expectedMessages: []string{
+				"annotation service.beta.kubernetes.io/azure-pip-name is ignored by the internal service",
+				"annotation service.beta.kubernetes.io/azure-dns-label-name is ignored by the internal service",
+			},
+		},
+		{
+			desc: "internal subnet of an external service",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternalSubnet: "subnet",
+			},
+			expectedMessages: []string{
+				"annotation service.beta.kubernetes.io/azure-load-balancer-internal-subnet is ignored by the external service",
+			},
+		},
+		{
+			desc: "public IP prefix with a public IP name",
+			annotations: map[string]string{
+				consts.ServiceAnnotationPIPNameDualStack[false]:     "pip",
+				consts.ServiceAnnotationPIPPrefixIDDualStack[false]: "prefix",
+			},
+			expectedMessages: []string{
+				"annotation service.beta.kubernetes.io/azure-pip-prefix-id is ignored with annotation service.beta.kubernetes.io/azure-pip-name",
+			},
+		},
+		{
+			desc:       "zonal service of the basic load balancer",
+			useBasicLB: true,
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerZone: "1",
+				consts.ServiceAnnotationAzurePIPZones:    "2",
+			},
+			expectedMessages: []string{
+				"the service is pinned to zone 1, which requires the IP-based backend pools of the standard load balancer",
+				`the service is pinned to zone 1, but annotation service.beta.kubernetes.io/azure-pip-zones is "2"`,
+			},
+		},
+		{
+			desc: "load balancer configurations without the multiple standard load balancers",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerConfigurations: "lb1",
+				consts.ServiceAnnotationLoadBalancerMode:           "__auto__",
+			},
+			expectedMessages: []string{
+				"annotation service.beta.kubernetes.io/azure-load-balancer-mode is ignored by the standard load balancer",
+				"annotation service.beta.kubernetes.io/azure-load-balancer-configurations is ignored without the multiple standard load balancers",
+			},
+		},
+		{
+			desc: "invalid idle timeout",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerIdleTimeout: "1",
+			},
+			expectedMessages: []string{
+				"error generate lb rule for ha mod loadbalancer. err: error parsing idle timeout key: service.beta.kubernetes.io/azure-load-balancer-tcp-idle-timeout, " +
+					"err: error parsing value: idle timeout value must be a whole number representing minutes between 4 and 100, actual value: 1",
+			},
+		},
+		{
+			desc: "HA ports of an external service",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerEnableHighAvailabilityPorts: consts.TrueAnnotationValue,
+			},
+			expectedMessages: []string{
+				"annotation service.beta.kubernetes.io/azure-load-balancer-enable-high-availability-ports is only supported on standard loadbalancer in internal mode, ignoring it",
+			},
+		},
+		{
+			desc:     "SCTP port of an external service",
+			protocol: v1.ProtocolSCTP,
+			expectedMessages: []string{
+				"SCTP port 80 is only supported on standard loadbalancer in internal mode, skipping it",
+			},
+		},
+		{
+			desc:     "SCTP port of an internal service",
+			protocol: v1.ProtocolSCTP,
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternal: consts.TrueAnnotationValue,
+			},
+			expectedMessages: []string{
+				"SCTP ports are served by a high availability ports rule forwarding all the ports of the service",
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			az := GetTestCloud(ctrl)
+			if !tc.useBasicLB {
+				az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+			}
+			protocol := v1.ProtocolTCP
+			if tc.protocol != "" {
+				protocol = tc.protocol
+			}
+			service := getTestService("test1", protocol, tc.annotations, false, 80)
+			service.Spec.LoadBalancerIP = tc.loadBalancerIP
+
+			assert.Equal(t, tc.expectedMessages, az.lintService(&service))
+		})
+	}
+}
+
+func TestLintServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.MaximumLoadBalancerRuleCount = 10
+	az.LoadBalancerResourceGroup = "lb-rg"
+	az.eventRecorder = nil
+
+	byoService := getTestService("byo", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationPIPNameDualStack[false]: "pip"}, false, 80)
+	missingService := getTestService("missing", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationPIPNameDualStack[false]: "missing"}, false, 80)
+	clusterIPService := getTestService("clusterip", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationLoadBalancerIdleTimeout: "1"}, false, 80)
+	clusterIPService.Spec.Type = v1.ServiceTypeClusterIP
+
+	pip := newTestUserAssignedPIP()
+	pip.SKU.Name = ptr.To(armnetwork.PublicIPAddressSKUNameBasic)
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockPIPClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.PublicIPAddress{pip}, nil).AnyTimes()
+
+	lb := &armnetwork.LoadBalancer{Name: ptr.To("kubernetes"), Properties: &armnetwork.LoadBalancerPropertiesFormat{}}
+	for i := 0; i < 9; i++ {
+		lb.Properties.LoadBalancingRules = append(lb.Properties.LoadBalancingRules, &armnetwork.LoadBalancingRule{Name: ptr.To(fmt.Sprintf("rule%d", i))})
+	}
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().List(gomock.Any(), "lb-rg").Return([]*armnetwork.LoadBalancer{
+		lb,
+		{Name: ptr.To("kubernetes-internal"), Properties: &armnetwork.LoadBalancerPropertiesFormat{}},
+	}, nil)
+
+	warnings, err := az.LintServices(context.Background(), []*v1.Service{&missingService, &clusterIPService, &byoService}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []LintWarning{
+		{
+			Resource: "LoadBalancer kubernetes",
+			Message:  "the load balancer has 9 of the maximum 10 load balancing rules, the new services would fail to be placed on it",
+		},
+		{
+			Resource: "Service default/byo",
+			Message:  "public IP pip of SKU Basic cannot be used by the load balancer of SKU standard",
+		},
+		{
+			Resource: "Service default/missing",
+			Message:  "public IP missing of annotation service.beta.kubernetes.io/azure-pip-name is not found in resource group rg",
+		},
+	}, warnings)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cloud-provider/api"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+const (
+	loadBalancerTypeInternal = "internal"
+	loadBalancerTypeExternal = "external"
+
+	// serviceFailureCategoryNone is the failure category of the load balancers provisioned without failures.
+	serviceFailureCategoryNone = "none"
+	// serviceFailureCategoryOther is the failure category of the errors not categorized.
+	serviceFailureCategoryOther = "other"
+)
+
+var (
+	serviceProvisioningDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "service_load_balancer_provisioning_duration_seconds",
+			Help:           "Time from the creation or the update of a service to its load balancer being ready with an ingress IP, by the category of the last failure on the way",
+			Buckets:        []float64{1, 5, 10, 20, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"load_balancer_type", "failure_category"},
+	)
+	serviceReconcileFailureCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "service_reconcile_failures_total",
+			Help:           "Number of failed load balancer reconciliations of the services",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"load_balancer_type", "failure_category"},
+	)
+	servicesFailingReconcile = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      consts.AzureMetricsNamespace,
+			Name:           "services_failing_reconcile",
+			Help:           "Number of services whose last load balancer reconciliation failed",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"load_balancer_type"},
+	)
+
+	registerServiceReconcileMetricsOnce sync.Once
+)
+
+func registerServiceReconcileMetrics() {
+	registerServiceReconcileMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(serviceProvisioningDuration)
+		legacyregistry.MustRegister(serviceReconcileFailureCount)
+		legacyregistry.MustRegister(servicesFailingReconcile)
+	})
+}
+
+// serviceProvisioningEntry tracks the provisioning of the load balancer of a service.
+type serviceProvisioningEntry struct {
+	// fingerprint is the hash of the service spec and annotations being provisioned.
+	fingerprint      string
+	loadBalancerType string
+	startedAt        time.Time
+	// lastFailure is the category of the last failure since the provisioning started.
+	lastFailure string
+	failing     bool
+	ready       bool
+}
+
+// serviceProvisioningTracker measures the time from the creation or the update of the services
+// to their load balancers being ready, and tracks the services failing to reconcile. A service
+// update starts when its spec or annotations are first seen changed. A nil
+// serviceProvisioningTracker tracks nothing.
+type serviceProvisioningTracker struct {
+	lock    sync.Mutex
+	entries map[string]*serviceProvisioningEntry
+
+	now func() time.Time
+}
+
+func newServiceProvisioningTracker() *serviceProvisioningTracker {
+	registerServiceReconcileMetrics()
+	return &serviceProvisioningTracker{
+		entries: make(map[string]*serviceProvisioningEntry),
+		now:     time.Now,
+	}
+}
+
+// observe records the result of the load balancer reconciliation of the service.
+func (t *serviceProvisioningTracker) observe(service *v1.Service, lbStatus *v1.LoadBalancerStatus, err error) {
+	if t == nil {
+		return
+	}
+	var retryErr *api.RetryError
+	if errors.As(err, &retryErr) {
+		// the load balancer is still being provisioned
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := strings.ToLower(getServiceName(service))
+	now := t.now()
+	fingerprint := getServiceFingerprint(service)
+	entry, found := t.entries[key]
+	if !found || entry.fingerprint != fingerprint {
+		entry = &serviceProvisioningEntry{
+			fingerprint:      fingerprint,
+			loadBalancerType: loadBalancerTypeExternal,
+			startedAt:        now,
+		}
+		if requiresInternalLoadBalancer(service) {
+			entry.loadBalancerType = loadBalancerTypeInternal
+		}
+		switch {
+		case !found && len(service.Status.LoadBalancer.Ingress) > 0:
+			// the load balancer was provisioned before the service is first seen, e.g. after a restart
+			entry.ready = true
+		case !found && !service.CreationTimestamp.IsZero():
+			entry.startedAt = service.CreationTimestamp.Time
+		}
+		t.entries[key] = entry
+	}
+
+	if err != nil {
+		category := getServiceFailureCategory(err)
+		serviceReconcileFailureCount.WithLabelValues(entry.loadBalancerType, category).Inc()
+		entry.lastFailure = category
+		entry.failing = true
+		t.updateFailingServices()
+		return
+	}
+	if entry.failing {
+		entry.failing = false
+		t.updateFailingServices()
+	}
+	if !entry.ready && lbStatus != nil && len(lbStatus.Ingress) > 0 {
+		category := entry.lastFailure
+		if category == "" {
+			category = serviceFailureCategoryNone
+		}
+		serviceProvisioningDuration.WithLabelValues(entry.loadBalancerType, category).Observe(now.Sub(entry.startedAt).Seconds())
+		entry.ready = true
+	}
+}
+
+// forget stops tracking the service after its load balancer is deleted.
+func (t *serviceProvisioningTracker) forget(service *v1.Service) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.entries, strings.ToLower(getServiceName(service)))
+	t.updateFailingServices()
+}
+
+// failingServices returns the failure categories of the services whose last reconciliation failed by their names.
+func (t *serviceProvisioningTracker) failingServices() map[string]string {
+	if t == nil {
+		return nil
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	services := make(map[string]string)
+	for key, entry := range t.entries {
+		if entry.failing {
+			services[key] = entry.lastFailure
+		}
+	}
+	return services
+}
+
+// updateFailingServices sets the number of failing services. It is called with the lock held.
+func (t *serviceProvisioningTracker) updateFailingServices() {
+	counts := map[string]int{loadBalancerTypeInternal: 0, loadBalancerTypeExternal: 0}
+	for _, entry := range t.entries {
+		if entry.failing {
+			counts[entry.loadBalancerType]++
+		}
+	}
+	for loadBalancerType, count := range counts {
+		servicesFailingReconcile.WithLabelValues(loadBalancerType).Set(float64(count))
+	}
+}
+
+// getServiceFailureCategory returns the category of the error of the load balancer reconciliation.
+func getServiceFailureCategory(err error) string {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, retryrepectthrottled.ErrTooManyRequest) {
+		return "throttled"
+	}
+	if _, locked := errutils.GetLockedScopes(err); locked {
+		return "management_lock"
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return serviceFailureCategoryOther
+	}
+	switch {
+	case rerr.StatusCode == http.StatusTooManyRequests:
+		return "throttled"
+	case rerr.StatusCode == http.StatusUnauthorized, rerr.StatusCode == http.StatusForbidden:
+		return "authorization"
+	case rerr.StatusCode == http.StatusNotFound:
+		return "not_found"
+	case rerr.StatusCode == http.StatusConflict, rerr.StatusCode == http.StatusPreconditionFailed:
+		return "conflict"
+	case rerr.StatusCode == http.StatusBadRequest:
+		return "invalid_request"
+	case rerr.StatusCode >= http.StatusInternalServerError:
+		return "server_error"
+	}
+	return "arm_error"
+}
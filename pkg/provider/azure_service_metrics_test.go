@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestServiceProvisioningTracker(t *testing.T) {
+	servicesFailingReconcile.Reset()
+	serviceProvisioningDuration.Reset()
+	serviceReconcileFailureCount.Reset()
+
+	now := time.Now()
+	tracker := newServiceProvisioningTracker()
+	tracker.now = func() time.Time { return now }
+
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:              "svc",
+		Namespace:         "default",
+		CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+		Annotations:       map[string]string{consts.ServiceAnnotationLoadBalancerInternal: "true"},
+	}}
+	ready := &v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "10.0.0.4"}}}
+	getFailing := func() float64 {
+		v, err := testutil.GetGaugeMetricValue(servicesFailingReconcile.WithLabelValues(loadBalancerTypeInternal))
+		assert.NoError(t, err)
+		return v
+	}
+
+	tracker.observe(service, nil, &azcore.ResponseError{StatusCode: http.StatusTooManyRequests})
+	assert.Equal(t, float64(1), getFailing())
+	failures, err := testutil.GetCounterMetricValue(serviceReconcileFailureCount.WithLabelValues(loadBalancerTypeInternal, "throttled"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), failures)
+
+	now = now.Add(time.Minute)
+	tracker.observe(service, ready, nil)
+	assert.Equal(t, float64(0), getFailing())
+	// the provisioning time is measured from the creation of the service
+	count, err := testutil.GetHistogramMetricCount(serviceProvisioningDuration.WithLabelValues(loadBalancerTypeInternal, "throttled"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+	sum, err := testutil.GetHistogramMetricValue(serviceProvisioningDuration.WithLabelValues(loadBalancerTypeInternal, "throttled"))
+	assert.NoError(t, err)
+	assert.Equal(t, (2 * time.Minute).Seconds(), sum)
+
+	// the ready load balancer is not measured again until the service is updated
+	tracker.observe(service, ready, nil)
+	count, err = testutil.GetHistogramMetricCount(serviceProvisioningDuration.WithLabelValues(loadBalancerTypeInternal, "throttled"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	updated := service.DeepCopy()
+	updated.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+	tracker.observe(updated, nil, errors.New("failed"))
+	assert.Equal(t, float64(1), getFailing())
+	tracker.forget(updated)
+	assert.Equal(t, float64(0), getFailing())
+}
+
+func TestGetServiceFailureCategory(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected string
+	}{
+		{err: errors.New("failed"), expected: "other"},
+		{err: &azcore.ResponseError{StatusCode: http.StatusForbidden}, expected: "authorization"},
+		{err: &azcore.ResponseError{StatusCode: http.StatusConflict}, expected: "conflict"},
+		{err: &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: consts.ScopeLockedErrorCode}, expected: "management_lock"},
+		{err: &azcore.ResponseError{StatusCode: http.StatusBadRequest}, expected: "invalid_request"},
+		{err: &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, expected: "server_error"},
+	} {
+		assert.Equal(t, tc.expected, getServiceFailureCategory(tc.err), tc.err.Error())
+	}
+}
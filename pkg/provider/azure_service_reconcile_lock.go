@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"sync"
+)
+
+// reconcilePriority is the priority of a reconciliation waiting for the service reconcile lock.
+type reconcilePriority int
+
+const (
+	// reconcilePriorityNodeSync is the priority of the backend pool updates of the node sync,
+	// which updates all the services at once whenever the nodes change.
+	reconcilePriorityNodeSync reconcilePriority = iota
+	// reconcilePriorityService is the priority of the reconciliations of the changed services.
+	reconcilePriorityService
+	// reconcilePriorityDeletion is the priority of the deletions of the load balancers.
+	reconcilePriorityDeletion
+
+	numReconcilePriorities = int(reconcilePriorityDeletion) + 1
+)
+
+// priorityLock is a mutex which is handed over to the waiter with the highest priority when it
+// is unlocked, and to the earliest one among the waiters with the same priority. It keeps the
+// service deletions from being starved by the bulk of node sync updates. The zero value is an
+// unlocked priorityLock.
+type priorityLock struct {
+	lock    sync.Mutex
+	locked  bool
+	waiters [numReconcilePriorities][]chan struct{}
+}
+
+// Lock locks l with the priority, waiting for the waiters with higher priorities first.
+func (l *priorityLock) Lock(priority reconcilePriority) {
+	l.lock.Lock()
+	if !l.locked {
+		l.locked = true
+		l.lock.Unlock()
+		return
+	}
+	ready := make(chan struct{})
+	l.waiters[priority] = append(l.waiters[priority], ready)
+	l.lock.Unlock()
+
+	// the lock is handed over without being unlocked in between
+	<-ready
+}
+
+// Unlock unlocks l, or hands it over to the next waiter.
+func (l *priorityLock) Unlock() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for priority := numReconcilePriorities - 1; priority >= 0; priority-- {
+		if len(l.waiters[priority]) > 0 {
+			ready := l.waiters[priority][0]
+			l.waiters[priority] = l.waiters[priority][1:]
+			close(ready)
+			return
+		}
+	}
+	l.locked = false
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityLock(t *testing.T) {
+	var l priorityLock
+	l.Lock(reconcilePriorityService)
+
+	var (
+		lock     sync.Mutex
+		order    []string
+		finished sync.WaitGroup
+	)
+	waitFor := func(name string, priority reconcilePriority) {
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			l.Lock(priority)
+			lock.Lock()
+			order = append(order, name)
+			lock.Unlock()
+			l.Unlock()
+		}()
+		// wait for the goroutine to queue up, so the waiters are in a known order
+		assert.Eventually(t, func() bool {
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			count := 0
+			for _, waiters := range l.waiters {
+				count += len(waiters)
+			}
+			return count == len(name)
+		}, time.Second, time.Millisecond)
+	}
+
+	// the names are of increasing lengths to count the waiters
+	waitFor("a", reconcilePriorityNodeSync)
+	waitFor("bb", reconcilePriorityNodeSync)
+	waitFor("ccc", reconcilePriorityService)
+	waitFor("dddd", reconcilePriorityDeletion)
+
+	l.Unlock()
+	finished.Wait()
+	assert.Equal(t, []string{"dddd", "ccc", "a", "bb"}, order)
+
+	// the lock is released after the last waiter
+	assert.False(t, l.locked)
+	l.Lock(reconcilePriorityNodeSync)
+	l.Unlock()
+}
@@ -18,6 +18,8 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -207,6 +209,46 @@ func getPrimaryInterfaceID(machine *armcompute.VirtualMachine) (string, error) {
 	return "", fmt.Errorf("failed to find a primary nic for the vm. vmname=%q", *machine.Name)
 }
 
+// getInterfaceIDs returns the IDs of the network interfaces in the network profile, the primary one first.
+func getInterfaceIDs(networkProfile *armcompute.NetworkProfile) []string {
+	if networkProfile == nil {
+		return nil
+	}
+	var ids []string
+	for _, ref := range networkProfile.NetworkInterfaces {
+		if ref == nil || ref.ID == nil {
+			continue
+		}
+		if ref.Properties != nil && ptr.Deref(ref.Properties.Primary, false) {
+			ids = append([]string{*ref.ID}, ids...)
+			continue
+		}
+		ids = append(ids, *ref.ID)
+	}
+	return ids
+}
+
+// getInterfacesByIDs gets the standalone network interfaces by their IDs.
+func (az *Cloud) getInterfacesByIDs(ctx context.Context, nicIDs []string) ([]*armnetwork.Interface, error) {
+	nics := make([]*armnetwork.Interface, 0, len(nicIDs))
+	for _, nicID := range nicIDs {
+		nicName, err := getLastSegment(nicID, "/")
+		if err != nil {
+			return nil, err
+		}
+		nicResourceGroup, err := extractResourceGroupByNicID(nicID)
+		if err != nil {
+			return nil, err
+		}
+		nic, err := az.getNetworkClientFactoryForResource(nicID).GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
+		if err != nil {
+			return nil, err
+		}
+		nics = append(nics, nic)
+	}
+	return nics, nil
+}
+
 func getPrimaryIPConfig(nic *armnetwork.Interface) (*armnetwork.InterfaceIPConfiguration, error) {
 	if nic.Properties.IPConfigurations == nil {
 		return nil, fmt.Errorf("nic.Properties.IPConfigurations for nic (nicname=%q) is nil", *nic.Name)
@@ -284,9 +326,13 @@ func managedResourceHasIPv6Suffix(name string) bool {
 
 func (az *Cloud) getLoadBalancerRuleName(service *v1.Service, protocol v1.Protocol, port int32, isIPv6 bool) string {
 	prefix := az.getRulePrefix(service)
+	isDualStack := isServiceDualStack(service)
+	if strings.EqualFold(az.LoadBalancerResourceNamingScheme, consts.LoadBalancerResourceNamingSchemeHashed) {
+		return getResourceByIPFamily(fmt.Sprintf("%s-%s", prefix, getLoadBalancerRuleNameHash(service, protocol, port)), isDualStack, isIPv6)
+	}
+
 	ruleName := fmt.Sprintf("%s-%s-%d", prefix, protocol, port)
 	subnet := getInternalSubnet(service)
-	isDualStack := isServiceDualStack(service)
 	if subnet == nil {
 		return getResourceByIPFamily(ruleName, isDualStack, isIPv6)
 	}
@@ -301,6 +347,18 @@ func (az *Cloud) getLoadBalancerRuleName(service *v1.Service, protocol v1.Protoc
 	return getResourceByIPFamily(fmt.Sprintf("%s-%s-%s-%d", prefix, subnetSegment, protocol, port), isDualStack, isIPv6)
 }
 
+// getLoadBalancerRuleNameHash returns the hash of the service UID, internal subnet, protocol and port
+// used by the hashed load balancer resource naming scheme. The subnet is omitted if it is not set,
+// so that the rules of the same port on frontends in different subnets don't collide.
+func getLoadBalancerRuleNameHash(service *v1.Service, protocol v1.Protocol, port int32) string {
+	key := strings.ToLower(string(service.UID))
+	if subnet := getInternalSubnet(service); subnet != nil {
+		key = fmt.Sprintf("%s/%s", key, strings.ToLower(*subnet))
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", key, strings.ToUpper(string(protocol)), port)))
+	return hex.EncodeToString(hash[:])[:consts.LoadBalancerResourceNameHashLength]
+}
+
 func (az *Cloud) getloadbalancerHAmodeRuleName(service *v1.Service, isIPv6 bool) string {
 	return az.getLoadBalancerRuleName(service, service.Spec.Ports[0].Protocol, service.Spec.Ports[0].Port, isIPv6)
 }
@@ -784,10 +842,22 @@ func (as *availabilitySet) GetNodeVMSetName(ctx context.Context, node *v1.Node)
 
 // GetPrimaryInterface gets machine primary network interface by node name.
 func (as *availabilitySet) GetPrimaryInterface(ctx context.Context, nodeName string) (*armnetwork.Interface, error) {
-	nic, _, err := as.getPrimaryInterfaceWithVMSet(ctx, nodeName, "")
+	nic, _, err := as.getInterfaceWithVMSet(ctx, nodeName, "", getPrimaryInterfaceID)
 	return nic, err
 }
 
+// GetInterfacesByNodeName gets all network interfaces of the machine by node name, the primary one first.
+func (as *availabilitySet) GetInterfacesByNodeName(ctx context.Context, nodeName string) ([]*armnetwork.Interface, error) {
+	machine, err := as.GetVirtualMachineWithRetry(ctx, types.NodeName(nodeName), azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, err
+	}
+	if machine.Properties == nil {
+		return nil, nil
+	}
+	return as.getInterfacesByIDs(ctx, getInterfaceIDs(machine.Properties.NetworkProfile))
+}
+
 // extractResourceGroupByNicID extracts the resource group name by nicID.
 func extractResourceGroupByNicID(nicID string) (string, error) {
 	matches := nicResourceGroupRE.FindStringSubmatch(nicID)
@@ -798,8 +868,8 @@ func extractResourceGroupByNicID(nicID string) (string, error) {
 	return matches[1], nil
 }
 
-// getPrimaryInterfaceWithVMSet gets machine primary network interface by node name and vmSet.
-func (as *availabilitySet) getPrimaryInterfaceWithVMSet(ctx context.Context, nodeName, vmSetName string) (*armnetwork.Interface, string, error) {
+// getInterfaceWithVMSet gets the machine network interface selected by getInterfaceID by node name and vmSet.
+func (as *availabilitySet) getInterfaceWithVMSet(ctx context.Context, nodeName, vmSetName string, getInterfaceID func(*armcompute.VirtualMachine) (string, error)) (*armnetwork.Interface, string, error) {
 	var machine *armcompute.VirtualMachine
 
 	machine, err := as.GetVirtualMachineWithRetry(ctx, types.NodeName(nodeName), azcache.CacheReadTypeDefault)
@@ -808,7 +878,7 @@ func (as *availabilitySet) getPrimaryInterfaceWithVMSet(ctx context.Context, nod
 		return nil, "", err
 	}
 
-	primaryNicID, err := getPrimaryInterfaceID(machine)
+	primaryNicID, err := getInterfaceID(machine)
 	if err != nil {
 		return nil, "", err
 	}
@@ -850,7 +920,7 @@ func (as *availabilitySet) getPrimaryInterfaceWithVMSet(ctx context.Context, nod
 
 	ctx, cancel := getContextWithCancel()
 	defer cancel()
-	nic, rerr := as.NetworkClientFactory.GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
+	nic, rerr := as.getNetworkClientFactoryForResource(primaryNicID).GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
 	if rerr != nil {
 		return nil, "", rerr
 	}
@@ -867,7 +937,7 @@ func (as *availabilitySet) getPrimaryInterfaceWithVMSet(ctx context.Context, nod
 func (as *availabilitySet) EnsureHostInPool(ctx context.Context, service *v1.Service, nodeName types.NodeName, backendPoolID string, vmSetName string) (string, string, string, *armcompute.VirtualMachineScaleSetVM, error) {
 	vmName := mapNodeNameToVMName(nodeName)
 	serviceName := getServiceName(service)
-	nic, _, err := as.getPrimaryInterfaceWithVMSet(ctx, vmName, vmSetName)
+	nic, _, err := as.getInterfaceWithVMSet(ctx, vmName, vmSetName, as.getBackendPoolInterfaceID)
 	if err != nil {
 		if errors.Is(err, errNotInVMSet) {
 			klog.V(3).Infof("EnsureHostInPool skips node %s because it is not in the vmSet %s", nodeName, vmSetName)
@@ -883,18 +953,9 @@ func (as *availabilitySet) EnsureHostInPool(ctx context.Context, service *v1.Ser
 		return "", "", "", nil, nil
 	}
 
-	var primaryIPConfig *armnetwork.InterfaceIPConfiguration
-	ipv6 := isBackendPoolIPv6(backendPoolID)
-	if !as.Cloud.ipv6DualStackEnabled && !ipv6 {
-		primaryIPConfig, err = getPrimaryIPConfig(nic)
-		if err != nil {
-			return "", "", "", nil, err
-		}
-	} else {
-		primaryIPConfig, err = getIPConfigByIPFamily(nic, ipv6)
-		if err != nil {
-			return "", "", "", nil, err
-		}
+	primaryIPConfig, err := as.getBackendPoolIPConfig(nic, isBackendPoolIPv6(backendPoolID))
+	if err != nil {
+		return "", "", "", nil, err
 	}
 
 	foundPool := false
@@ -944,6 +1005,14 @@ func (as *availabilitySet) EnsureHostInPool(ctx context.Context, service *v1.Ser
 			return "", "", "", nil, err
 		}
 	}
+
+	err = as.removeBackendPoolFromPrimaryInterface(ctx, service, nic, backendPoolID, func() (*armnetwork.Interface, error) {
+		primaryNIC, _, err := as.getInterfaceWithVMSet(ctx, vmName, vmSetName, getPrimaryInterfaceID)
+		return primaryNIC, err
+	})
+	if err != nil {
+		return "", "", "", nil, err
+	}
 	return "", "", "", nil, nil
 }
 
@@ -1045,7 +1114,7 @@ func (as *availabilitySet) EnsureBackendPoolDeleted(ctx context.Context, service
 		}
 
 		vmName := mapNodeNameToVMName(types.NodeName(nodeName))
-		nic, vmasID, err := as.getPrimaryInterfaceWithVMSet(ctx, vmName, vmSetName)
+		nic, vmasID, err := as.getInterfaceWithVMSet(ctx, vmName, vmSetName, as.getBackendPoolInterfaceID)
 		if err != nil {
 			if errors.Is(err, errNotInVMSet) {
 				klog.V(3).Infof("EnsureBackendPoolDeleted skips node %s because it is not in the vmSet %s", nodeName, vmSetName)
@@ -1082,7 +1151,7 @@ func (as *availabilitySet) EnsureBackendPoolDeleted(ctx context.Context, service
 		nic := ipconfigPrefixToNicMap[k]
 		newIPConfigs := nic.Properties.IPConfigurations
 		for j, ipConf := range newIPConfigs {
-			if isServiceIPv4 && !ptr.Deref(ipConf.Properties.Primary, false) {
+			if isServiceIPv4 && !as.isBackendPoolIPConfig(nic, ipConf) {
 				continue
 			}
 			// To support IPv6 only and dual-stack clusters, all IP configurations
@@ -1153,7 +1222,7 @@ func (as *availabilitySet) GetNodeNameByIPConfigurationID(ctx context.Context, i
 	if nicResourceGroup == "" || nicName == "" {
 		return "", "", fmt.Errorf("invalid ip config ID %s", ipConfigurationID)
 	}
-	nic, rerr := as.NetworkClientFactory.GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
+	nic, rerr := as.getNetworkClientFactoryForResource(ipConfigurationID).GetInterfaceClient().Get(ctx, nicResourceGroup, nicName, nil)
 	if rerr != nil {
 		return "", "", fmt.Errorf("GetNodeNameByIPConfigurationID(%s): failed to get interface of name %s: %w", ipConfigurationID, nicName, rerr)
 	}
@@ -224,6 +224,7 @@ func TestGetLoadBalancingRuleName(t *testing.T) {
 		isIPv6        bool
 		useStandardLB bool
 		port          int32
+		namingScheme  string
 	}{
 		{
 			description:   "internal lb should have subnet name on the rule name",
@@ -320,10 +321,31 @@ func TestGetLoadBalancingRuleName(t *testing.T) {
 			port:          9000,
 			expected:      "a257b965551374ad2b091ef3f07043ad-TCP-9000-IPv6",
 		},
+		{
+			description:   "hashed naming scheme should hash the subnet name into the rule name",
+			subnetName:    "shortsubnet",
+			isInternal:    true,
+			useStandardLB: true,
+			protocol:      v1.ProtocolTCP,
+			port:          9000,
+			namingScheme:  consts.LoadBalancerResourceNamingSchemeHashed,
+			expected:      "a257b965551374ad2b091ef3f07043ad-0da35e32ae4d40f7",
+		},
+		{
+			description:   "hashed naming scheme IPv6",
+			isInternal:    false,
+			isIPv6:        true,
+			useStandardLB: true,
+			protocol:      v1.ProtocolTCP,
+			port:          9000,
+			namingScheme:  consts.LoadBalancerResourceNamingSchemeHashed,
+			expected:      "a257b965551374ad2b091ef3f07043ad-5b93ca1e816bed48-IPv6",
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
+			az.LoadBalancerResourceNamingScheme = c.namingScheme
 			if c.useStandardLB {
 				az.Config.LoadBalancerSKU = consts.LoadBalancerSKUStandard
 			} else {
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
+)
+
+// EnterWarmStandby marks the cloud as kept warm on a non-leader replica. The caches of the cloud are
+// filled by WarmUpCaches and by the informers, while the background loops don't change any resource
+// until the cloud is initialized by the leader.
+func (az *Cloud) EnterWarmStandby() {
+	az.warmStandby.Store(true)
+}
+
+// WarmUpCaches fetches the load balancers, the public IPs, the security group, the route tables and the
+// VMs of the scale sets of the nodes into the caches, so a replica taking over the leadership doesn't
+// start with cold caches. Only the entries missing or expired are fetched, and nothing is changed.
+func (az *Cloud) WarmUpCaches(ctx context.Context) {
+	if az.DisableAPICallCache {
+		return
+	}
+	ctx = withARMCaller(ctx, armCallerBackground)
+	if !az.armRequestBudget.allowBackground() {
+		klog.V(2).Infof("WarmUpCaches: skipped as the ARM request budget is nearly consumed")
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		az.warmUpLoadBalancerCache(ctx)
+	}()
+	if az.pipCache != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := az.pipCache.Get(ctx, az.ResourceGroup, azcache.CacheReadTypeDefault); err != nil {
+				klog.Warningf("WarmUpCaches: failed to list the public IPs in resource group %s: %v", az.ResourceGroup, err)
+			}
+		}()
+	}
+	if az.nsgRepo != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := az.nsgRepo.GetSecurityGroup(ctx); err != nil {
+				klog.Warningf("WarmUpCaches: failed to get the security group %s: %v", az.SecurityGroupName, err)
+			}
+		}()
+	}
+	if az.routeTableRepo != nil && az.RouteTableName != "" {
+		for _, routeTableName := range az.getRouteTableNames() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeDefault); err != nil {
+					klog.Warningf("WarmUpCaches: failed to get the route table %s: %v", routeTableName, err)
+				}
+			}()
+		}
+	}
+	if ss, ok := az.VMSet.(*ScaleSet); ok && az.nodeInformerSynced != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ss.warmUpCaches(ctx, az.nodeInformerSynced)
+		}()
+	}
+	wg.Wait()
+}
+
+// warmUpLoadBalancerCache lists the load balancers into the cache, as the cache fetches them one by one.
+// The cached load balancers are not replaced, since they may be fresher than the listed ones.
+func (az *Cloud) warmUpLoadBalancerCache(ctx context.Context) {
+	if az.lbCache == nil || az.lbCache.GetStore() == nil {
+		return
+	}
+	rgName := az.getLoadBalancerResourceGroup()
+	lbs, err := az.NetworkClientFactory.GetLoadBalancerClient().List(ctx, rgName)
+	if err != nil {
+		klog.Warningf("WarmUpCaches: failed to list the load balancers in resource group %s: %v", rgName, errutils.WithARMRequestIDs(err))
+		return
+	}
+	for _, lb := range lbs {
+		name := ptr.Deref(lb.Name, "")
+		if name == "" {
+			continue
+		}
+		if _, found, _ := az.lbCache.GetStore().GetByKey(name); found {
+			continue
+		}
+		az.lbCache.Update(name, lb)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient/mock_publicipaddressclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient/mock_securitygroupclient"
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
+)
+
+func TestCloudWarmUpCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	cachedLB := &armnetwork.LoadBalancer{Name: ptr.To("lb1"), Etag: ptr.To("cached")}
+	az.lbCache.Set("lb1", cachedLB)
+
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.LoadBalancer{
+		{Name: ptr.To("lb1"), Etag: ptr.To("listed")},
+		{Name: ptr.To("lb2"), Etag: ptr.To("listed")},
+	}, nil)
+	mockPIPClient := az.NetworkClientFactory.GetPublicIPAddressClient().(*mock_publicipaddressclient.MockInterface)
+	mockPIPClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]*armnetwork.PublicIPAddress{{Name: ptr.To("pip")}}, nil)
+	mockSGClient := az.NetworkClientFactory.GetSecurityGroupClient().(*mock_securitygroupclient.MockInterface)
+	mockSGClient.EXPECT().Get(gomock.Any(), az.SecurityGroupResourceGroup, "nsg").Return(&armnetwork.SecurityGroup{Name: ptr.To("nsg")}, nil)
+	mockRTRepo := az.routeTableRepo.(*routetable.MockRepository)
+	mockRTRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{Name: ptr.To("rt")}, nil)
+
+	az.WarmUpCaches(context.Background())
+
+	lbs := cachedData(az.lbCache)
+	assert.Len(t, lbs, 2)
+	assert.Equal(t, cachedLB, lbs["lb1"], "the cached load balancer should not be replaced")
+	assert.Equal(t, "listed", ptr.Deref(lbs["lb2"].(*armnetwork.LoadBalancer).Etag, ""))
+	pips, err := az.listPIP(context.Background(), az.ResourceGroup, azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Len(t, pips, 1)
+}
+
+func TestWarmStandbySkipsRouteDriftReconciliation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cloud := &Cloud{
+		routeTableRepo: routetable.NewMockRepository(ctrl),
+		Config: config.Config{
+			RouteTableName:               "rt",
+			RouteDriftReconciliationMode: consts.RouteDriftReconciliationModeRepair,
+		},
+		nodeInformerSynced: func() bool { return true },
+	}
+	cloud.EnterWarmStandby()
+
+	// The route table is not read nor changed in the warm standby.
+	cloud.reconcileRouteDrifts(context.Background())
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+// stateDump is the internal state of the cloud provider served on the state debugging endpoint, so it can be
+// compared with the resources in Azure. It is read from the caches, which may be stale.
+type stateDump struct {
+	Time time.Time `json:"time"`
+	// Services are the LoadBalancer services with the load balancers holding their rules.
+	Services []serviceStateDump `json:"services"`
+	// BackendPools are the backend pools of the cached load balancers with their members.
+	BackendPools []backendPoolStateDump `json:"backendPools"`
+	// LoadBalancerConfigurations are the services and the nodes assigned to the load balancers in the multiple
+	// standard load balancers mode.
+	LoadBalancerConfigurations []loadBalancerConfigurationStateDump `json:"loadBalancerConfigurations,omitempty"`
+	// Routes are the route tables assigned to the nodes and the routes in the cached route tables.
+	Routes routesStateDump `json:"routes"`
+}
+
+type serviceStateDump struct {
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+	// LoadBalancers are the names of the load balancers with the rules of the service.
+	LoadBalancers []string `json:"loadBalancers,omitempty"`
+	// Rules are the load balancing rules of the service as <load balancer>/<rule>.
+	Rules []string `json:"rules,omitempty"`
+	// FrontendIPConfigurations are the frontends of the rules of the service as <load balancer>/<frontend>.
+	FrontendIPConfigurations []string `json:"frontendIPConfigurations,omitempty"`
+	IngressIPs               []string `json:"ingressIPs,omitempty"`
+}
+
+type backendPoolStateDump struct {
+	LoadBalancer string                   `json:"loadBalancer"`
+	Name         string                   `json:"name"`
+	Members      []backendPoolMemberState `json:"members,omitempty"`
+}
+
+// backendPoolMemberState is a member of a backend pool, which is an IP address of a node in the IP based pools,
+// or an IP configuration of a NIC or a VMSS VM in the NIC based pools.
+type backendPoolMemberState struct {
+	// NodeName is the node of the IP address, if it is known.
+	NodeName          string `json:"nodeName,omitempty"`
+	IPAddress         string `json:"ipAddress,omitempty"`
+	IPConfigurationID string `json:"ipConfigurationID,omitempty"`
+}
+
+type loadBalancerConfigurationStateDump struct {
+	Name           string   `json:"name"`
+	ActiveServices []string `json:"activeServices"`
+	ActiveNodes    []string `json:"activeNodes"`
+}
+
+type routesStateDump struct {
+	// NodeRouteTables are the route tables assigned to the nodes.
+	NodeRouteTables map[string]string `json:"nodeRouteTables,omitempty"`
+	// NodeRouteIPConfigurations are the IP configurations overriding the next hops of the routes of the nodes.
+	NodeRouteIPConfigurations map[string]string `json:"nodeRouteIPConfigurations,omitempty"`
+	// UnmanagedNodeRouteCIDRs are the CIDRs of the routes of the unmanaged nodes, which are only kept in memory.
+	UnmanagedNodeRouteCIDRs map[string]string     `json:"unmanagedNodeRouteCIDRs,omitempty"`
+	RouteTables             []routeTableStateDump `json:"routeTables,omitempty"`
+}
+
+type routeTableStateDump struct {
+	Name   string           `json:"name"`
+	Routes []routeStateDump `json:"routes,omitempty"`
+	// Error is the error reading the route table.
+	Error string `json:"error,omitempty"`
+}
+
+type routeStateDump struct {
+	Name             string `json:"name"`
+	AddressPrefix    string `json:"addressPrefix,omitempty"`
+	NextHopType      string `json:"nextHopType,omitempty"`
+	NextHopIPAddress string `json:"nextHopIPAddress,omitempty"`
+}
+
+// DumpState returns the internal state of the cloud provider, i.e. the mapping of the services to the load
+// balancers, the members of the backend pools and the route assignments, for debugging. It is encoded as JSON.
+func (az *Cloud) DumpState(ctx context.Context) interface{} {
+	dump := &stateDump{Time: time.Now().UTC()}
+
+	var lbs []*armnetwork.LoadBalancer
+	if az.lbCache != nil {
+		for _, data := range cachedData(az.lbCache) {
+			if lb, ok := data.(*armnetwork.LoadBalancer); ok {
+				lbs = append(lbs, lb)
+			}
+		}
+	}
+	sort.Slice(lbs, func(i, j int) bool { return ptr.Deref(lbs[i].Name, "") < ptr.Deref(lbs[j].Name, "") })
+
+	dump.Services = az.dumpServiceStates(lbs)
+	dump.BackendPools = az.dumpBackendPoolStates(lbs)
+	dump.LoadBalancerConfigurations = az.dumpLoadBalancerConfigurationStates()
+	dump.Routes = az.dumpRouteStates(ctx)
+	return dump
+}
+
+// dumpServiceStates maps the LoadBalancer services to the rules of the load balancers.
+func (az *Cloud) dumpServiceStates(lbs []*armnetwork.LoadBalancer) []serviceStateDump {
+	if az.serviceLister == nil {
+		return nil
+	}
+	services, err := az.serviceLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("DumpState: failed to list the services: %v", err)
+		return nil
+	}
+	var states []serviceStateDump
+	for _, service := range services {
+		if !az.wantsLoadBalancer(service) {
+			continue
+		}
+		state := serviceStateDump{Name: getServiceName(service), UID: string(service.UID)}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			state.IngressIPs = append(state.IngressIPs, ingress.IP)
+		}
+		for _, lb := range lbs {
+			if lb.Properties == nil {
+				continue
+			}
+			lbName := ptr.Deref(lb.Name, "")
+			owned := false
+			for _, rule := range lb.Properties.LoadBalancingRules {
+				ruleName := ptr.Deref(rule.Name, "")
+				if !az.serviceOwnsRule(service, ruleName) {
+					continue
+				}
+				owned = true
+				state.Rules = append(state.Rules, lbName+"/"+ruleName)
+				if rule.Properties != nil && rule.Properties.FrontendIPConfiguration != nil {
+					fipName, _ := getLastSegment(ptr.Deref(rule.Properties.FrontendIPConfiguration.ID, ""), "/")
+					fipName = lbName + "/" + fipName
+					if !slices.Contains(state.FrontendIPConfigurations, fipName) {
+						state.FrontendIPConfigurations = append(state.FrontendIPConfigurations, fipName)
+					}
+				}
+			}
+			if owned {
+				state.LoadBalancers = append(state.LoadBalancers, lbName)
+			}
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// dumpBackendPoolStates lists the members of the backend pools, resolving the nodes of the IP addresses.
+func (az *Cloud) dumpBackendPoolStates(lbs []*armnetwork.LoadBalancer) []backendPoolStateDump {
+	az.nodeCachesLock.RLock()
+	defer az.nodeCachesLock.RUnlock()
+
+	var states []backendPoolStateDump
+	for _, lb := range lbs {
+		if lb.Properties == nil {
+			continue
+		}
+		for _, pool := range lb.Properties.BackendAddressPools {
+			if pool == nil {
+				continue
+			}
+			state := backendPoolStateDump{LoadBalancer: ptr.Deref(lb.Name, ""), Name: ptr.Deref(pool.Name, "")}
+			if pool.Properties != nil {
+				for _, address := range pool.Properties.LoadBalancerBackendAddresses {
+					if address == nil || address.Properties == nil {
+						continue
+					}
+					ip := ptr.Deref(address.Properties.IPAddress, "")
+					state.Members = append(state.Members, backendPoolMemberState{
+						NodeName:  az.nodePrivateIPToNodeNameMap[ip],
+						IPAddress: ip,
+					})
+				}
+				for _, ipConfiguration := range pool.Properties.BackendIPConfigurations {
+					if ipConfiguration == nil {
+						continue
+					}
+					state.Members = append(state.Members, backendPoolMemberState{IPConfigurationID: ptr.Deref(ipConfiguration.ID, "")})
+				}
+			}
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// dumpLoadBalancerConfigurationStates lists the services and the nodes assigned to the load balancers.
+func (az *Cloud) dumpLoadBalancerConfigurationStates() []loadBalancerConfigurationStateDump {
+	az.multipleStandardLoadBalancersActiveServicesLock.Lock()
+	defer az.multipleStandardLoadBalancersActiveServicesLock.Unlock()
+	az.multipleStandardLoadBalancersActiveNodesLock.Lock()
+	defer az.multipleStandardLoadBalancersActiveNodesLock.Unlock()
+
+	var states []loadBalancerConfigurationStateDump
+	for _, config := range az.MultipleStandardLoadBalancerConfigurations {
+		state := loadBalancerConfigurationStateDump{Name: config.Name, ActiveServices: []string{}, ActiveNodes: []string{}}
+		if config.ActiveServices != nil {
+			state.ActiveServices = config.ActiveServices.UnsortedList()
+			sort.Strings(state.ActiveServices)
+		}
+		if config.ActiveNodes != nil {
+			state.ActiveNodes = config.ActiveNodes.UnsortedList()
+			sort.Strings(state.ActiveNodes)
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// dumpRouteStates lists the route assignments of the nodes and the routes of the route tables, which are
+// only read from Azure if they are not cached.
+func (az *Cloud) dumpRouteStates(ctx context.Context) routesStateDump {
+	var state routesStateDump
+	az.nodeCachesLock.RLock()
+	var nodeNames []string
+	if az.nodeNames != nil {
+		nodeNames = az.nodeNames.UnsortedList()
+	}
+	if len(az.nodeRouteIPConfigurations) > 0 {
+		state.NodeRouteIPConfigurations = make(map[string]string, len(az.nodeRouteIPConfigurations))
+		for nodeName, ipConfiguration := range az.nodeRouteIPConfigurations {
+			state.NodeRouteIPConfigurations[nodeName] = ipConfiguration
+		}
+	}
+	az.nodeCachesLock.RUnlock()
+
+	az.routeCIDRsLock.Lock()
+	if len(az.routeCIDRs) > 0 {
+		state.UnmanagedNodeRouteCIDRs = make(map[string]string, len(az.routeCIDRs))
+		for nodeName, cidr := range az.routeCIDRs {
+			state.UnmanagedNodeRouteCIDRs[nodeName] = cidr
+		}
+	}
+	az.routeCIDRsLock.Unlock()
+
+	if az.RouteTableName == "" || az.routeTableRepo == nil {
+		return state
+	}
+	state.NodeRouteTables = make(map[string]string, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		routeTableName, err := az.getRouteTableNameForNode(ctx, nodeName)
+		if err != nil {
+			klog.Errorf("DumpState: failed to get the route table of node %s: %v", nodeName, err)
+			continue
+		}
+		state.NodeRouteTables[nodeName] = routeTableName
+	}
+	for _, routeTableName := range az.getRouteTableNames() {
+		dump := routeTableStateDump{Name: routeTableName}
+		routeTable, err := az.routeTableRepo.Get(ctx, routeTableName, azcache.CacheReadTypeUnsafe)
+		switch {
+		case err != nil:
+			dump.Error = err.Error()
+		case routeTable == nil:
+			dump.Error = "route table not found"
+		case routeTable.Properties != nil:
+			for _, route := range routeTable.Properties.Routes {
+				if route == nil {
+					continue
+				}
+				routeDump := routeStateDump{Name: ptr.Deref(route.Name, "")}
+				if route.Properties != nil {
+					routeDump.AddressPrefix = ptr.Deref(route.Properties.AddressPrefix, "")
+					routeDump.NextHopIPAddress = ptr.Deref(route.Properties.NextHopIPAddress, "")
+					if route.Properties.NextHopType != nil {
+						routeDump.NextHopType = string(*route.Properties.NextHopType)
+					}
+				}
+				dump.Routes = append(dump.Routes, routeDump)
+			}
+			sort.Slice(dump.Routes, func(i, j int) bool { return dump.Routes[i].Name < dump.Routes[j].Name })
+		}
+		state.RouteTables = append(state.RouteTables, dump)
+	}
+	return state
+}
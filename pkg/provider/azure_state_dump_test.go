@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+func TestDumpState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "uid"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status:     v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(service))
+	assert.NoError(t, indexer.Add(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "cluster-ip", Namespace: "default"}}))
+	assert.NoError(t, indexer.Add(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "metallb", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, LoadBalancerClass: ptr.To("metallb.io/metallb")},
+	}))
+	az.serviceLister = corelisters.NewServiceLister(indexer)
+
+	rulePrefix := az.getRulePrefix(service)
+	az.lbCache.Set("kubernetes", &armnetwork.LoadBalancer{
+		Name: ptr.To("kubernetes"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
+				{
+					Name: ptr.To(rulePrefix + "-TCP-80"),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						FrontendIPConfiguration: &armnetwork.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/kubernetes/frontendIPConfigurations/" + rulePrefix)},
+					},
+				},
+				{Name: ptr.To("other-TCP-80")},
+			},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{
+					Name: ptr.To("kubernetes"),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{
+							{Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{IPAddress: ptr.To("10.0.0.4")}},
+							{Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{IPAddress: ptr.To("10.0.0.5")}},
+						},
+					},
+				},
+			},
+		},
+	})
+	az.nodeNames = utilsets.NewString("node1")
+	az.nodePrivateIPToNodeNameMap = map[string]string{"10.0.0.4": "node1"}
+	az.routeCIDRs = map[string]string{"unmanaged": "10.244.1.0/24"}
+
+	mockRouteTableRepo := az.routeTableRepo.(*routetable.MockRepository)
+	mockRouteTableRepo.EXPECT().Get(gomock.Any(), "rt", azcache.CacheReadTypeUnsafe).Return(&armnetwork.RouteTable{
+		Name: ptr.To("rt"),
+		Properties: &armnetwork.RouteTablePropertiesFormat{
+			Routes: []*armnetwork.Route{
+				{
+					Name: ptr.To("node1"),
+					Properties: &armnetwork.RoutePropertiesFormat{
+						AddressPrefix:    ptr.To("10.244.0.0/24"),
+						NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
+						NextHopIPAddress: ptr.To("10.0.0.4"),
+					},
+				},
+			},
+		},
+	}, nil)
+
+	dump, ok := az.DumpState(context.Background()).(*stateDump)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, []serviceStateDump{
+		{
+			Name:                     "default/svc",
+			UID:                      "uid",
+			LoadBalancers:            []string{"kubernetes"},
+			Rules:                    []string{"kubernetes/" + rulePrefix + "-TCP-80"},
+			FrontendIPConfigurations: []string{"kubernetes/" + rulePrefix},
+			IngressIPs:               []string{"1.2.3.4"},
+		},
+	}, dump.Services)
+	assert.Equal(t, []backendPoolStateDump{
+		{
+			LoadBalancer: "kubernetes",
+			Name:         "kubernetes",
+			Members: []backendPoolMemberState{
+				{NodeName: "node1", IPAddress: "10.0.0.4"},
+				{IPAddress: "10.0.0.5"},
+			},
+		},
+	}, dump.BackendPools)
+	assert.Empty(t, dump.LoadBalancerConfigurations)
+	assert.Equal(t, routesStateDump{
+		NodeRouteTables:         map[string]string{"node1": "rt"},
+		UnmanagedNodeRouteCIDRs: map[string]string{"unmanaged": "10.244.1.0/24"},
+		RouteTables: []routeTableStateDump{
+			{
+				Name: "rt",
+				Routes: []routeStateDump{
+					{Name: "node1", AddressPrefix: "10.244.0.0/24", NextHopType: "VirtualAppliance", NextHopIPAddress: "10.0.0.4"},
+				},
+			},
+		},
+	}, dump.Routes)
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	statusv1alpha1 "sigs.k8s.io/cloud-provider-azure/pkg/apis/status/v1alpha1"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+// certificateExpirationWarningPeriod is how long before the expiration of a client certificate the
+// credentials are reported invalid, so the certificate is rotated before the ARM calls start failing.
+const certificateExpirationWarningPeriod = 7 * 24 * time.Hour
+
+// runStatusReporter reports the status of the cloud provider to the AzureCloudProviderStatus every interval.
+func (az *Cloud) runStatusReporter(ctx context.Context, client dynamic.Interface, interval time.Duration) {
+	klog.V(2).Infof("runStatusReporter: reporting the status every %s", interval)
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		if err := az.reportStatus(ctx, client); err != nil {
+			klog.Errorf("runStatusReporter: failed to report the status: %v", err)
+		}
+		return false, nil
+	})
+	klog.V(2).Infof("runStatusReporter: stopped with error: %s", err.Error())
+}
+
+// reportStatus updates the status of the AzureCloudProviderStatus, which is created if it doesn't exist.
+func (az *Cloud) reportStatus(ctx context.Context, client dynamic.Interface) error {
+	resource := client.Resource(statusv1alpha1.Resource)
+	obj, err := resource.Get(ctx, statusv1alpha1.DefaultName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion(statusv1alpha1.SchemeGroupVersion.String())
+		obj.SetKind(statusv1alpha1.Kind)
+		obj.SetName(statusv1alpha1.DefaultName)
+		obj, err = resource.Create(ctx, obj, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get or create %s %s: %w", statusv1alpha1.Kind, statusv1alpha1.DefaultName, err)
+	}
+
+	var status statusv1alpha1.AzureCloudProviderStatus
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &status); err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", statusv1alpha1.Kind, statusv1alpha1.DefaultName, err)
+	}
+	status.Status = az.collectStatus(ctx, status.Status.Conditions)
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", statusv1alpha1.Kind, statusv1alpha1.DefaultName, err)
+	}
+	if _, err := resource.UpdateStatus(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update the status of %s %s: %w", statusv1alpha1.Kind, statusv1alpha1.DefaultName, err)
+	}
+	return nil
+}
+
+// collectStatus collects the status of the cloud provider. The conditions are updated in place so their
+// transition times are kept.
+func (az *Cloud) collectStatus(ctx context.Context, conditions []metav1.Condition) statusv1alpha1.AzureCloudProviderStatusStatus {
+	status := statusv1alpha1.AzureCloudProviderStatusStatus{
+		LastUpdateTime: metav1.Now(),
+		Conditions:     conditions,
+	}
+	setCondition := func(conditionType string, healthy bool, reason, message string) {
+		conditionStatus := metav1.ConditionTrue
+		if !healthy {
+			conditionStatus = metav1.ConditionFalse
+		}
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  conditionStatus,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	var unhealthy []string
+	status.LoadBalancers = az.collectLoadBalancerStatuses()
+	status.FailingServices = az.collectFailingServices()
+	if len(status.FailingServices) > 0 {
+		setCondition(statusv1alpha1.ConditionLoadBalancersReconciled, false, "ServicesFailing", fmt.Sprintf("%d services failed to reconcile", len(status.FailingServices)))
+		unhealthy = append(unhealthy, statusv1alpha1.ConditionLoadBalancersReconciled)
+	} else {
+		setCondition(statusv1alpha1.ConditionLoadBalancersReconciled, true, "Reconciled", "")
+	}
+
+	status.RouteTables = az.collectRouteTableStatuses(ctx)
+	var notReadyRouteTables []string
+	for _, routeTable := range status.RouteTables {
+		if routeTable.Error != "" || !strings.EqualFold(routeTable.ProvisioningState, string(armnetwork.ProvisioningStateSucceeded)) {
+			notReadyRouteTables = append(notReadyRouteTables, routeTable.Name)
+		}
+	}
+	if len(notReadyRouteTables) > 0 {
+		setCondition(statusv1alpha1.ConditionRouteTablesReady, false, "RouteTablesNotReady", fmt.Sprintf("route tables %s are not ready", strings.Join(notReadyRouteTables, ", ")))
+		unhealthy = append(unhealthy, statusv1alpha1.ConditionRouteTablesReady)
+	} else {
+		setCondition(statusv1alpha1.ConditionRouteTablesReady, true, "Ready", "")
+	}
+
+	status.Credentials = az.collectCredentialStatuses(ctx)
+	var invalidCredentials []string
+	for _, credential := range status.Credentials {
+		if credential.Error != "" ||
+			(credential.CertificateExpirationTime != nil && time.Until(credential.CertificateExpirationTime.Time) < certificateExpirationWarningPeriod) {
+			invalidCredentials = append(invalidCredentials, credential.Name)
+		}
+	}
+	if len(invalidCredentials) > 0 {
+		setCondition(statusv1alpha1.ConditionCredentialsValid, false, "CredentialsInvalid", fmt.Sprintf("credentials %s fail to get tokens or their certificates expire within %s", strings.Join(invalidCredentials, ", "), certificateExpirationWarningPeriod))
+		unhealthy = append(unhealthy, statusv1alpha1.ConditionCredentialsValid)
+	} else {
+		setCondition(statusv1alpha1.ConditionCredentialsValid, true, "Valid", "")
+	}
+
+	if az.controllerStatuses != nil {
+		status.Controllers = az.controllerStatuses()
+	}
+	var staleControllers []string
+	for _, controller := range status.Controllers {
+		if controller.Stale {
+			staleControllers = append(staleControllers, controller.Name)
+		}
+	}
+	if len(staleControllers) > 0 {
+		setCondition(statusv1alpha1.ConditionControllersSynced, false, "ControllersStale", fmt.Sprintf("controllers %s have not synced within their staleness window", strings.Join(staleControllers, ", ")))
+		unhealthy = append(unhealthy, statusv1alpha1.ConditionControllersSynced)
+	} else {
+		setCondition(statusv1alpha1.ConditionControllersSynced, true, "Synced", "")
+	}
+
+	for _, resource := range retryrepectthrottled.ThrottledResources() {
+		status.ThrottledResources = append(status.ThrottledResources, statusv1alpha1.ThrottledResource{
+			Operation:  resource.Operation,
+			ResourceID: resource.ResourceID,
+			Until:      metav1.NewTime(resource.Until),
+		})
+	}
+	if len(status.ThrottledResources) > 0 {
+		// throttling is reported by the condition but is not unhealthy, the requests are retried after the backoffs
+		setCondition(statusv1alpha1.ConditionThrottled, true, "ResourcesThrottled", fmt.Sprintf("the requests to %d resources are backed off", len(status.ThrottledResources)))
+	} else {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: statusv1alpha1.ConditionThrottled, Status: metav1.ConditionFalse, Reason: "NotThrottled"})
+	}
+
+	if len(unhealthy) > 0 {
+		setCondition(statusv1alpha1.ConditionHealthy, false, "Unhealthy", fmt.Sprintf("conditions %s are unhealthy", strings.Join(unhealthy, ", ")))
+	} else {
+		setCondition(statusv1alpha1.ConditionHealthy, true, "Healthy", "")
+	}
+	return status
+}
+
+// collectLoadBalancerStatuses reports the load balancers in the load balancer cache, which holds the load
+// balancers read by the reconciliations. It makes no ARM calls, so the report adds no load to the subscription.
+func (az *Cloud) collectLoadBalancerStatuses() []statusv1alpha1.LoadBalancerStatus {
+	if az.lbCache == nil {
+		return nil
+	}
+	var statuses []statusv1alpha1.LoadBalancerStatus
+	for _, data := range cachedData(az.lbCache) {
+		lb, ok := data.(*armnetwork.LoadBalancer)
+		if !ok {
+			continue
+		}
+		status := statusv1alpha1.LoadBalancerStatus{Name: ptr.Deref(lb.Name, "")}
+		if lb.SKU != nil && lb.SKU.Name != nil {
+			status.SKU = string(*lb.SKU.Name)
+		}
+		if lb.Properties != nil {
+			if lb.Properties.ProvisioningState != nil {
+				status.ProvisioningState = string(*lb.Properties.ProvisioningState)
+			}
+			status.LoadBalancingRules = len(lb.Properties.LoadBalancingRules)
+			for _, fip := range lb.Properties.FrontendIPConfigurations {
+				if fip == nil {
+					continue
+				}
+				frontend := statusv1alpha1.FrontendStatus{Name: ptr.Deref(fip.Name, "")}
+				if fip.Properties != nil {
+					frontend.PrivateIPAddress = ptr.Deref(fip.Properties.PrivateIPAddress, "")
+					if fip.Properties.PublicIPAddress != nil {
+						frontend.PublicIPAddressID = ptr.Deref(fip.Properties.PublicIPAddress.ID, "")
+					}
+				}
+				status.Frontends = append(status.Frontends, frontend)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// collectRouteTableStatuses reads the route tables the routes of the nodes are managed in.
+func (az *Cloud) collectRouteTableStatuses(ctx context.Context) []statusv1alpha1.RouteTableStatus {
+	if az.RouteTableName == "" || az.routeTableRepo == nil {
+		return nil
+	}
+	var statuses []statusv1alpha1.RouteTableStatus
+	for _, name := range az.getRouteTableNames() {
+		status := statusv1alpha1.RouteTableStatus{Name: name}
+		routeTable, err := az.routeTableRepo.Get(ctx, name, azcache.CacheReadTypeDefault)
+		switch {
+		case err != nil:
+			status.Error = err.Error()
+		case routeTable == nil:
+			status.Error = "route table not found"
+		case routeTable.Properties != nil:
+			if routeTable.Properties.ProvisioningState != nil {
+				status.ProvisioningState = string(*routeTable.Properties.ProvisioningState)
+			}
+			status.Routes = len(routeTable.Properties.Routes)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// collectCredentialStatuses gets the tokens of the credentials, which are served from the token caches of the
+// credentials unless they are about to expire.
+func (az *Cloud) collectCredentialStatuses(ctx context.Context) []statusv1alpha1.CredentialStatus {
+	if az.AuthProvider == nil {
+		return nil
+	}
+	credentials := []struct {
+		name       string
+		credential azcore.TokenCredential
+	}{
+		{name: "compute", credential: az.AuthProvider.ComputeCredential},
+		{name: "network", credential: az.AuthProvider.NetworkCredential},
+	}
+	var certificateExpirationTime *metav1.Time
+	if az.AADClientCertPath != "" {
+		expiration, err := getCertificateExpirationTime(az.AADClientCertPath, az.getClientCertPassword())
+		if err != nil {
+			klog.Errorf("collectCredentialStatuses: %v", err)
+		} else {
+			certificateExpirationTime = &expiration
+		}
+	}
+
+	scope := azclient.DefaultTokenScopeFor(az.AuthProvider.CloudConfig)
+	var statuses []statusv1alpha1.CredentialStatus
+	for _, c := range credentials {
+		if c.credential == nil {
+			continue
+		}
+		status := statusv1alpha1.CredentialStatus{Name: c.name, CertificateExpirationTime: certificateExpirationTime}
+		token, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.TokenExpirationTime = ptr.To(metav1.NewTime(token.ExpiresOn))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// getCertificateExpirationTime returns when the client certificate in the file expires.
+func getCertificateExpirationTime(path, password string) (metav1.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return metav1.Time{}, fmt.Errorf("failed to read the client certificate %s: %w", path, err)
+	}
+	certificates, _, err := azidentity.ParseCertificates(data, []byte(password))
+	if err != nil {
+		return metav1.Time{}, fmt.Errorf("failed to parse the client certificate %s: %w", path, err)
+	}
+	if len(certificates) == 0 {
+		return metav1.Time{}, fmt.Errorf("no certificate found in %s", path)
+	}
+	// the first certificate is the client certificate, the others are its chain
+	return metav1.NewTime(certificates[0].NotAfter), nil
+}
+
+// SetControllerStatuses sets the function returning the syncs of the controllers, which are reported in the
+// status. It must be called before Initialize.
+func (az *Cloud) SetControllerStatuses(controllerStatuses func() []statusv1alpha1.ControllerStatus) {
+	az.controllerStatuses = controllerStatuses
+}
+
+// collectFailingServices returns the services whose last reconciliation failed, including the parked ones.
+func (az *Cloud) collectFailingServices() []statusv1alpha1.FailingService {
+	failing := az.serviceProvisioningTracker.failingServices()
+	if failing == nil {
+		failing = map[string]string{}
+	}
+	parked := map[string]bool{}
+	for _, name := range az.serviceReconcileBackoff.parkedServices() {
+		parked[name] = true
+		if _, found := failing[name]; !found {
+			failing[name] = serviceFailureCategoryOther
+		}
+	}
+	var services []statusv1alpha1.FailingService
+	for name, category := range failing {
+		services = append(services, statusv1alpha1.FailingService{Name: name, FailureCategory: category, Parked: parked[name]})
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	statusv1alpha1 "sigs.k8s.io/cloud-provider-azure/pkg/apis/status/v1alpha1"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient/mock_loadbalancerclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/mock_azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider/routetable"
+)
+
+func TestReportStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.serviceProvisioningTracker = newServiceProvisioningTracker()
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+	az.serviceProvisioningTracker.observe(service, nil, errors.New("failed"))
+
+	// the load balancers are reported from the cache without listing them
+	mockLBClient := az.NetworkClientFactory.GetLoadBalancerClient().(*mock_loadbalancerclient.MockInterface)
+	mockLBClient.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+	az.lbCache.Set("kubernetes", &armnetwork.LoadBalancer{
+		Name: ptr.To("kubernetes"),
+		SKU:  &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameStandard)},
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			ProvisioningState: ptr.To(armnetwork.ProvisioningStateSucceeded),
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{
+					Name: ptr.To("fip"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.PublicIPAddress{ID: ptr.To("pip")},
+					},
+				},
+			},
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{{Name: ptr.To("rule")}},
+		},
+	})
+	// a load balancer not found is not reported
+	az.lbCache.Set("deleted", nil)
+
+	controllers := []statusv1alpha1.ControllerStatus{{Name: "service-lb-controller", Error: "failed", Stale: true}}
+	az.SetControllerStatuses(func() []statusv1alpha1.ControllerStatus { return controllers })
+	mockRouteTableRepo := az.routeTableRepo.(*routetable.MockRepository)
+	mockRouteTableRepo.EXPECT().Get(gomock.Any(), "rt", gomock.Any()).Return(&armnetwork.RouteTable{
+		Name: ptr.To("rt"),
+		Properties: &armnetwork.RouteTablePropertiesFormat{
+			ProvisioningState: ptr.To(armnetwork.ProvisioningStateSucceeded),
+			Routes:            []*armnetwork.Route{{Name: ptr.To("node")}},
+		},
+	}, nil).Times(2)
+
+	expiresOn := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockCredential := az.AuthProvider.ComputeCredential.(*mock_azclient.MockTokenCredential)
+	mockCredential.EXPECT().GetToken(gomock.Any(), gomock.Any()).Return(azcore.AccessToken{Token: "token", ExpiresOn: expiresOn}, nil).Times(2)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		statusv1alpha1.Resource: statusv1alpha1.Kind + "List",
+	})
+	getStatus := func() statusv1alpha1.AzureCloudProviderStatus {
+		obj, err := client.Resource(statusv1alpha1.Resource).Get(context.Background(), statusv1alpha1.DefaultName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		var status statusv1alpha1.AzureCloudProviderStatus
+		assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &status))
+		return status
+	}
+
+	// the status is created on the first report
+	assert.NoError(t, az.reportStatus(context.Background(), client))
+	status := getStatus().Status
+	assert.Equal(t, []statusv1alpha1.LoadBalancerStatus{
+		{
+			Name:               "kubernetes",
+			SKU:                "Standard",
+			ProvisioningState:  "Succeeded",
+			Frontends:          []statusv1alpha1.FrontendStatus{{Name: "fip", PublicIPAddressID: "pip"}},
+			LoadBalancingRules: 1,
+		},
+	}, status.LoadBalancers)
+	assert.Equal(t, []statusv1alpha1.RouteTableStatus{{Name: "rt", ProvisioningState: "Succeeded", Routes: 1}}, status.RouteTables)
+	assert.Equal(t, []statusv1alpha1.CredentialStatus{{Name: "compute", TokenExpirationTime: ptr.To(metav1.NewTime(expiresOn))}}, status.Credentials)
+	assert.Equal(t, []statusv1alpha1.FailingService{{Name: "default/svc", FailureCategory: serviceFailureCategoryOther}}, status.FailingServices)
+	assert.Equal(t, controllers, status.Controllers)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, statusv1alpha1.ConditionHealthy))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, statusv1alpha1.ConditionLoadBalancersReconciled))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, statusv1alpha1.ConditionRouteTablesReady))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, statusv1alpha1.ConditionControllersSynced))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, statusv1alpha1.ConditionThrottled))
+	transitionTime := meta.FindStatusCondition(status.Conditions, statusv1alpha1.ConditionThrottled).LastTransitionTime
+
+	// the service and the controller recover
+	az.serviceProvisioningTracker.observe(service, &v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}}, nil)
+	controllers = []statusv1alpha1.ControllerStatus{{Name: "service-lb-controller", LastSyncTime: ptr.To(metav1.NewTime(expiresOn))}}
+	assert.NoError(t, az.reportStatus(context.Background(), client))
+	status = getStatus().Status
+	assert.Empty(t, status.FailingServices)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, statusv1alpha1.ConditionHealthy))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, statusv1alpha1.ConditionLoadBalancersReconciled))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, statusv1alpha1.ConditionControllersSynced))
+	assert.Equal(t, controllers, status.Controllers)
+	// the conditions unchanged keep their transition times
+	assert.Equal(t, transitionTime, meta.FindStatusCondition(status.Conditions, statusv1alpha1.ConditionThrottled).LastTransitionTime)
+}
@@ -53,6 +53,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient/mock_securitygroupclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/features"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	providerconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/privatelinkservice"
@@ -2075,6 +2076,67 @@ func TestGetNodeResourceGroup(t *testing.T) {
 	}
 }
 
+func TestUpdateNodeProviderIDResourceGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.SubscriptionID = "subscription"
+
+	for _, node := range []*v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmss-node"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/subscription/resourceGroups/RG1/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm-node"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/subscription/resourceGroups/rg2/providers/Microsoft.Compute/virtualMachines/vm-node"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "labeled-node", Labels: map[string]string{consts.ExternalResourceGroupLabel: "rg3"}},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/subscription/resourceGroups/rg4/providers/Microsoft.Compute/virtualMachines/labeled-node"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "configured-rg-node"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/subscription/resourceGroups/RG/providers/Microsoft.Compute/virtualMachines/configured-rg-node"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-subscription-node"},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/other/resourceGroups/rg5/providers/Microsoft.Compute/virtualMachines/other-subscription-node"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-node"},
+			Spec:       v1.NodeSpec{ProviderID: "kind://docker/kind/unmanaged-node"},
+		},
+	} {
+		az.updateNodeCaches(nil, node)
+	}
+	assert.Equal(t, map[string]string{"vmss-node": "rg1", "vm-node": "rg2"}, az.nodeProviderIDResourceGroups)
+
+	for node, expectedRG := range map[string]string{
+		"vmss-node":               "rg1",
+		"labeled-node":            "rg3",
+		"configured-rg-node":      "rg",
+		"other-subscription-node": "rg",
+	} {
+		rg, err := az.GetNodeResourceGroup(node)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRG, rg, node)
+	}
+
+	resourceGroups, err := az.GetResourceGroups()
+	assert.NoError(t, err)
+	assert.Equal(t, utilsets.NewString("rg", "rg1", "rg2", "rg3"), resourceGroups)
+
+	// the node in a resource group other than the configured one is not excluded from load balancers
+	// unless it has the external resource group label
+	excluded, err := az.ShouldNodeExcludedFromLoadBalancer("vm-node")
+	assert.NoError(t, err)
+	assert.False(t, excluded)
+
+	az.updateNodeCaches(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "vm-node"}}, nil)
+	assert.Equal(t, map[string]string{"vmss-node": "rg1"}, az.nodeProviderIDResourceGroups)
+}
+
 func TestSetInformers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2084,6 +2146,16 @@ func TestSetInformers(t *testing.T) {
 	sharedInformers := informers.NewSharedInformerFactory(az.KubeClient, time.Minute)
 	az.SetInformers(sharedInformers)
 	assert.NotNil(t, az.nodeInformerSynced)
+	assert.Nil(t, az.podIndexer)
+
+	az.NodeDeletionGracePeriodInSeconds = 600
+	az.SetInformers(informers.NewSharedInformerFactory(az.KubeClient, time.Minute))
+	assert.NotNil(t, az.podIndexer)
+
+	az.podIndexer = nil
+	az.DisablePodInformer()
+	az.SetInformers(informers.NewSharedInformerFactory(az.KubeClient, time.Minute))
+	assert.Nil(t, az.podIndexer)
 }
 
 func TestUpdateNodeCaches(t *testing.T) {
@@ -2414,6 +2486,27 @@ func TestInitializeCloudFromConfig(t *testing.T) {
 		expectedErr := fmt.Errorf("useInstanceMetadata must be enabled without Azure credentials")
 		assert.Equal(t, expectedErr, err)
 	})
+	t.Run("nodeAddressSource arm requires Azure credentials", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			AzureClientConfig: providerconfig.AzureClientConfig{
+				ARMClientConfig: azclient.ARMClientConfig{
+					Cloud: "AZUREPUBLICCLOUD",
+				},
+			},
+			CloudConfigType:     configloader.CloudConfigTypeFile,
+			UseInstanceMetadata: true,
+			NodeAddressSource:   consts.NodeAddressSourceARM,
+		}
+		az.AuthProvider = &azclient.AuthProvider{}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "nodeAddressSource arm requires Azure credentials")
+	})
 	t.Run("loadBalancerBackendPoolConfigurationType invalid is not supported, supported values are", func(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
@@ -2429,6 +2522,194 @@ func TestInitializeCloudFromConfig(t *testing.T) {
 		expectedErr := errors.New("loadBalancerBackendPoolConfigurationType invalid is not supported, supported values are")
 		assert.Contains(t, err.Error(), expectedErr.Error())
 	})
+	t.Run("deallocatedVMPolicy invalid is not supported, supported values are", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			DeallocatedVMPolicy: "invalid",
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		expectedErr := errors.New("deallocatedVMPolicy invalid is not supported, supported values are")
+		assert.Contains(t, err.Error(), expectedErr.Error())
+	})
+	t.Run("routeDriftReconciliationMode invalid is not supported, supported values are", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			RouteDriftReconciliationMode: "invalid",
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		expectedErr := errors.New("routeDriftReconciliationMode invalid is not supported, supported values are")
+		assert.Contains(t, err.Error(), expectedErr.Error())
+	})
+	t.Run("routeDriftReconciliationMode repair requires routeNamePrefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			RouteDriftReconciliationMode: consts.RouteDriftReconciliationModeRepair,
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "routeDriftReconciliationMode repair requires routeNamePrefix to tell the user-defined routes")
+	})
+	t.Run("failOnUserRouteConflict requires routeNamePrefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			FailOnUserRouteConflict: true,
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "failOnUserRouteConflict requires routeNamePrefix to tell the user-defined routes")
+	})
+	t.Run("routeNextHopPolicies invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		for _, tc := range []struct {
+			policy      config.RouteNextHopPolicy
+			expectedErr string
+		}{
+			{config.RouteNextHopPolicy{NextHopIPAddress: "10.0.0.4"}, "routeNextHopPolicies[0] does not have subnetNames"},
+			{config.RouteNextHopPolicy{SubnetNames: []string{"subnet"}, NextHopIPAddress: "invalid"}, "routeNextHopPolicies[0] requires a valid nextHopIPAddress for next hop type VirtualAppliance"},
+			{config.RouteNextHopPolicy{SubnetNames: []string{"subnet"}, NextHopType: "VirtualNetworkGateway", NextHopIPAddress: "10.0.0.4"}, "routeNextHopPolicies[0] cannot have nextHopIPAddress for next hop type VirtualNetworkGateway"},
+			{config.RouteNextHopPolicy{SubnetNames: []string{"subnet"}, NextHopType: "Internet"}, "routeNextHopPolicies[0] nextHopType Internet is not supported, supported values are [VirtualAppliance VirtualNetworkGateway]"},
+		} {
+			azureconfig := config.Config{
+				RouteNextHopPolicies: []config.RouteNextHopPolicy{tc.policy},
+			}
+			err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+			assert.EqualError(t, err, tc.expectedErr)
+		}
+	})
+	t.Run("additionalClusterCIDRs invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			AdditionalClusterCIDRs: []string{"10.1.0.0/16", "invalid"},
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "additionalClusterCIDRs invalid is not a valid CIDR: invalid CIDR address: invalid")
+	})
+	t.Run("nodePoolClusterCIDRs invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		for _, tc := range []struct {
+			nodePool    config.NodePoolClusterCIDR
+			expectedErr string
+		}{
+			{config.NodePoolClusterCIDR{NodeSelector: "kubernetes.io/os=windows"}, "nodePoolClusterCIDRs[0] does not have clusterCIDRs"},
+			{config.NodePoolClusterCIDR{NodeSelector: "kubernetes.io/os=windows", ClusterCIDRs: []string{"invalid"}}, "nodePoolClusterCIDRs[0] clusterCIDR invalid is not a valid CIDR: invalid CIDR address: invalid"},
+			{config.NodePoolClusterCIDR{NodeSelector: "kubernetes.io/os=windows", ClusterCIDRs: []string{"10.1.0.0/16", "10.2.0.0/16"}}, "nodePoolClusterCIDRs[0] has more than one cluster CIDR per IP family"},
+		} {
+			azureconfig := config.Config{
+				NodePoolClusterCIDRs: []config.NodePoolClusterCIDR{tc.nodePool},
+			}
+			err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+			assert.EqualError(t, err, tc.expectedErr)
+		}
+	})
+	t.Run("routeTableShardSubnetNames should have a subnet per route table", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			RouteTableShardCount:       3,
+			RouteTableShardSubnetNames: []string{"subnet0", "subnet1"},
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "routeTableShardSubnetNames has 2 subnets, but routeTableShardCount is 3")
+	})
+	t.Run("routeTableShardCount should have routeTableShardSubnetNames", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			RouteTableShardCount: 2,
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "routeTableShardSubnetNames has 0 subnets, but routeTableShardCount is 2")
+	})
+	t.Run("nodeAddressSource invalid is not supported, supported values are", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			NodeAddressSource: "invalid",
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		expectedErr := errors.New("nodeAddressSource invalid is not supported, supported values are")
+		assert.Contains(t, err.Error(), expectedErr.Error())
+	})
+	t.Run("instanceMetadataOnly requires useInstanceMetadata and the imds nodeAddressSource", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			InstanceMetadataOnly: true,
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "instanceMetadataOnly requires useInstanceMetadata to be enabled")
+
+		azureconfig = config.Config{
+			InstanceMetadataOnly: true,
+			UseInstanceMetadata:  true,
+			NodeAddressSource:    consts.NodeAddressSourceARM,
+		}
+		err = az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "instanceMetadataOnly requires the nodeAddressSource imds, got arm")
+	})
+	t.Run("nodeAddressOrder invalid is not supported, supported values are", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			NodeAddressOrder: "invalid",
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		expectedErr := errors.New("nodeAddressOrder invalid is not supported, supported values are")
+		assert.Contains(t, err.Error(), expectedErr.Error())
+	})
 	t.Run("loadBalancerBackendPoolConfigurationType is set to NodeIPConfiguration", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -2441,6 +2722,34 @@ func TestInitializeCloudFromConfig(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, az.Config.LoadBalancerBackendPoolConfigurationType, consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration)
 	})
+	t.Run("loadBalancerBackendPoolConfigurationType nodeIP falls back to NodeIPConfiguration if IPBasedBackendPool is disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		azureconfig := config.Config{
+			LoadBalancerBackendPoolConfigurationType: consts.LoadBalancerBackendPoolConfigurationTypeNodeIP,
+			AzureFeatureGates:                        map[string]bool{string(features.IPBasedBackendPool): false},
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration, az.Config.LoadBalancerBackendPoolConfigurationType)
+		assert.False(t, az.featureEnabled(features.IPBasedBackendPool))
+		assert.True(t, az.featureEnabled(features.PrivateLinkService))
+	})
+	t.Run("unknown azureFeatureGates are not supported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		az := GetTestCloud(ctrl)
+
+		azureconfig := config.Config{
+			AzureFeatureGates: map[string]bool{"Unknown": true},
+		}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.ErrorContains(t, err, "invalid azureFeatureGates: unrecognized feature gate: Unknown")
+	})
 
 	t.Run("should setup network client factory with network subscription ID - same network sub in same tenant", func(t *testing.T) {
 
@@ -2598,6 +2907,100 @@ func TestInitializeCloudFromConfig(t *testing.T) {
 		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
 		assert.NoError(t, err)
 	})
+
+	t.Run("should setup client factories of credential sets", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		az := GetTestCloud(ctrl)
+		zoneMock := az.zoneRepo.(*zone.MockRepository)
+		zoneMock.EXPECT().ListZones(gomock.Any()).Return(map[string][]string{"eastus": {"1", "2", "3"}}, nil).AnyTimes()
+
+		const (
+			tenantID                    = "tenant-id"
+			subscriptionID              = "subscription-id"
+			credentialSetTenantID       = "credential-set-tenant-id"
+			credentialSetSubscriptionID = "Credential-Set-Subscription-ID"
+		)
+
+		az.ComputeClientFactory = nil
+		az.NetworkClientFactory = nil
+
+		azureconfig := config.Config{}
+		azureconfig.ARMClientConfig.TenantID = tenantID
+		azureconfig.SubscriptionID = subscriptionID
+		azureconfig.CredentialSets = []config.CredentialSet{
+			{
+				SubscriptionID: credentialSetSubscriptionID,
+				TenantID:       credentialSetTenantID,
+				AzureAuthConfig: azclient.AzureAuthConfig{
+					AADClientID:     "client-id",
+					AADClientSecret: "client-secret",
+				},
+			},
+		}
+
+		nCall := 0
+		newARMClientFactory = func(
+			config *azclient.ClientFactoryConfig,
+			armConfig *azclient.ARMClientConfig,
+			cloud cloud.Configuration,
+			cred azcore.TokenCredential,
+			clientOptionsMutFn ...func(option *arm.ClientOptions),
+		) (azclient.ClientFactory, error) {
+			switch nCall {
+			case 0:
+				// It should create network client factory with the read cache and the auxiliary token of the credential set
+				assert.Equal(t, subscriptionID, config.SubscriptionID)
+				assert.Len(t, clientOptionsMutFn, 2)
+			case 1:
+				// It should create compute client factory
+				assert.Equal(t, subscriptionID, config.SubscriptionID)
+			case 2:
+				// It should create client factory of the credential set
+				assert.Equal(t, credentialSetSubscriptionID, config.SubscriptionID)
+				assert.Equal(t, credentialSetTenantID, armConfig.TenantID)
+				assert.Len(t, clientOptionsMutFn, 1)
+			default:
+				panic("unexpected call")
+			}
+			nCall++
+			return azclient.NewClientFactory(config, armConfig, cloud, cred, clientOptionsMutFn...)
+		}
+		defer func() {
+			newARMClientFactory = azclient.NewClientFactory
+		}()
+
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, nCall)
+		assert.Contains(t, az.credentialSetClientFactories, "credential-set-subscription-id")
+	})
+
+	t.Run("should reject credential sets without subscription ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		az := GetTestCloud(ctrl)
+		azureconfig := config.Config{}
+		azureconfig.CredentialSets = []config.CredentialSet{{TenantID: "tenant-id"}}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "credentialSets[0] does not have subscriptionId")
+	})
+
+	t.Run("should reject credential sets in other tenants with network resource tenant", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		az := GetTestCloud(ctrl)
+		azureconfig := config.Config{}
+		azureconfig.ARMClientConfig.TenantID = "tenant-id"
+		azureconfig.ARMClientConfig.NetworkResourceTenantID = "network-tenant-id"
+		azureconfig.CredentialSets = []config.CredentialSet{{SubscriptionID: "sub", TenantID: "other-tenant-id"}}
+		err := az.InitializeCloudFromConfig(context.Background(), &azureconfig, false, true)
+		assert.EqualError(t, err, "credentialSets[0] in tenant other-tenant-id cannot be combined with networkResourceTenantID")
+	})
 }
 
 func TestSetLBDefaults(t *testing.T) {
@@ -2743,3 +3146,178 @@ func TestIsNodeReady(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterNodesEligibleForLoadBalancer(t *testing.T) {
+	now := time.Now()
+	newNode := func(name string, age time.Duration, ready *v1.ConditionStatus, since time.Duration) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		if ready != nil {
+			node.Status.Conditions = []v1.NodeCondition{
+				{
+					Type:               v1.NodeReady,
+					Status:             *ready,
+					LastTransitionTime: metav1.NewTime(now.Add(-since)),
+				},
+			}
+		}
+		return node
+	}
+	readyStatus, notReadyStatus := v1.ConditionTrue, v1.ConditionFalse
+
+	nodes := []*v1.Node{
+		newNode("young-ready", time.Minute, &readyStatus, time.Minute),
+		newNode("old-ready", time.Hour, &readyStatus, time.Hour),
+		newNode("old-not-ready-recently", time.Hour, &notReadyStatus, time.Minute),
+		newNode("old-not-ready-long", time.Hour, &notReadyStatus, 30*time.Minute),
+		newNode("old-unknown", time.Hour, nil, 0),
+		newNode("old-never-ready", time.Hour, &notReadyStatus, 55*time.Minute),
+	}
+
+	for _, tc := range []struct {
+		desc                 string
+		minAge               int
+		gracePeriod          int
+		expected             []string
+		expectedRequeueAfter time.Duration
+	}{
+		{
+			desc:     "should keep all nodes when gating is disabled",
+			expected: []string{"young-ready", "old-ready", "old-not-ready-recently", "old-not-ready-long", "old-unknown", "old-never-ready"},
+		},
+		{
+			desc:                 "should exclude nodes younger than the minimum age and nodes not Ready when they aged in",
+			minAge:               600,
+			expected:             []string{"old-ready", "old-not-ready-recently", "old-not-ready-long"},
+			expectedRequeueAfter: 9 * time.Minute,
+		},
+		{
+			desc:                 "should exclude nodes NotReady longer than the grace period",
+			gracePeriod:          600,
+			expected:             []string{"young-ready", "old-ready", "old-not-ready-recently"},
+			expectedRequeueAfter: 9 * time.Minute,
+		},
+		{
+			desc:                 "should apply both minimum age and grace period",
+			minAge:               600,
+			gracePeriod:          600,
+			expected:             []string{"old-ready", "old-not-ready-recently"},
+			expectedRequeueAfter: 9 * time.Minute,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			az.LoadBalancerBackendPoolNodeMinAgeInSeconds = tc.minAge
+			az.LoadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds = tc.gracePeriod
+
+			var actual []string
+			eligibleNodes, requeueAfter := az.filterNodesEligibleForLoadBalancer(nodes)
+			for _, node := range eligibleNodes {
+				actual = append(actual, node.Name)
+			}
+			assert.Equal(t, tc.expected, actual)
+			assert.InDelta(t, tc.expectedRequeueAfter, requeueAfter, float64(5*time.Second))
+		})
+	}
+}
+
+func TestFilterNodesInLoadBalancerDedicatedHostGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerDedicatedHostGroups = "hg0, HG1"
+
+	vmClient := az.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	newVM := func(name string, properties *armcompute.VirtualMachineProperties) {
+		vmClient.EXPECT().Get(gomock.Any(), "rg", name, gomock.Any()).Return(&armcompute.VirtualMachine{
+			Name:       ptr.To(name),
+			Properties: properties,
+		}, nil).Times(1)
+	}
+	newVM("node0", &armcompute.VirtualMachineProperties{
+		HostGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/hostGroups/hg0")},
+	})
+	newVM("node1", &armcompute.VirtualMachineProperties{
+		Host: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/hostGroups/hg1/hosts/host0")},
+	})
+	newVM("node2", &armcompute.VirtualMachineProperties{
+		HostGroup: &armcompute.SubResource{ID: ptr.To("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/hostGroups/hg2")},
+	})
+	newVM("node3", &armcompute.VirtualMachineProperties{})
+	// the VM of node4 is deleted, which only excludes node4. The not found VMs are not cached
+	// because VMNotFoundCacheTTLInSeconds is 0.
+	vmClient.EXPECT().Get(gomock.Any(), "rg", "node4", gomock.Any()).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}).Times(2)
+
+	var nodes []*v1.Node
+	for _, name := range []string{"node0", "node1", "node2", "node3", "node4"} {
+		nodes = append(nodes, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{ProviderID: "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/" + name},
+		})
+	}
+	// the label of the node is not trusted
+	nodes[3].Labels = map[string]string{consts.LabelDedicatedHostGroup: "hg0"}
+
+	// the VMs are read once from the cache
+	for i := 0; i < 2; i++ {
+		filteredNodes, err := az.filterNodesInLoadBalancerDedicatedHostGroups(context.TODO(), nodes)
+		assert.NoError(t, err)
+		assert.Equal(t, nodes[:2], filteredNodes)
+	}
+
+	az.LoadBalancerDedicatedHostGroups = ""
+	filteredNodes, err := az.filterNodesInLoadBalancerDedicatedHostGroups(context.TODO(), nodes)
+	assert.NoError(t, err)
+	assert.Equal(t, nodes, filteredNodes)
+}
+
+func TestNodeEligibilityRequeuer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerBackendPoolNodeMinAgeInSeconds = 600
+	r := newNodeEligibilityRequeuer(az)
+	defer r.queue.ShutDown()
+
+	now := time.Now()
+	notReadyNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node",
+			CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{
+				{
+					Type:               v1.NodeReady,
+					Status:             v1.ConditionFalse,
+					LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+				},
+			},
+		},
+	}
+	readyNode := notReadyNode.DeepCopy()
+	readyNode.Status.Conditions[0].Status = v1.ConditionTrue
+	service := getTestService("service1", v1.ProtocolTCP, nil, false, 80)
+
+	r.track(testClusterName, &service, []*v1.Node{notReadyNode}, 0)
+	assert.Equal(t, 0, r.queue.Len())
+
+	r.onNodeUpdate(notReadyNode, notReadyNode)
+	assert.Equal(t, 0, r.queue.Len(), "should not requeue when the eligibility is unchanged")
+
+	r.onNodeUpdate(notReadyNode, readyNode)
+	assert.Equal(t, 1, r.queue.Len(), "should requeue when an aged-in node becomes Ready")
+	key, _ := r.queue.Get()
+	assert.Equal(t, "default/service1", key)
+	r.queue.Done(key)
+
+	r.forget(&service)
+	r.onNodeUpdate(readyNode, notReadyNode)
+	assert.Equal(t, 0, r.queue.Len(), "should not requeue forgotten services")
+}
@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 	"k8s.io/utils/ptr"
@@ -37,6 +40,9 @@ import (
 const (
 	IPVersionIPv6 bool = true
 	IPVersionIPv4 bool = false
+
+	// maxTagValueLength is the maximum length of the tag values of the Azure resources.
+	maxTagValueLength = 256
 )
 
 var strToExtendedLocationType = map[string]armnetwork.ExtendedLocationTypes{
@@ -136,7 +142,37 @@ func findKeyInMapWithPrefix(targetMap map[string]*string, key string) (bool, str
 	return false, ""
 }
 
+// getServiceResourceTags returns the tags set in the cloud config merged with the tags
+// set by the azure-resource-tags annotation of the service. The annotation takes precedence.
+func (az *Cloud) getServiceResourceTags(service *v1.Service) map[string]*string {
+	tags := parseTags(az.Tags, az.TagsMap)
+	if service == nil {
+		return tags
+	}
+	if v, ok := service.Annotations[consts.ServiceAnnotationAzureResourceTags]; ok {
+		mergeTagsCaseInsensitive(tags, parseTags(v, map[string]string{}))
+	}
+	return tags
+}
+
+// mergeTagsCaseInsensitive sets the tags in src to dst. The existing key in dst
+// is reused if it only differs from the key in src in case.
+func mergeTagsCaseInsensitive(dst, src map[string]*string) {
+	for k, v := range src {
+		found, key := findKeyInMapCaseInsensitive(dst, k)
+		if !found {
+			dst[k] = v
+		} else if !strings.EqualFold(ptr.Deref(v, ""), ptr.Deref(dst[key], "")) {
+			dst[key] = v
+		}
+	}
+}
+
 func (az *Cloud) reconcileTags(currentTagsOnResource, newTags map[string]*string) (reconciledTags map[string]*string, changed bool) {
+	if az.PreserveForeignTags {
+		return reconcileManagedTags(currentTagsOnResource, newTags)
+	}
+
 	var systemTags []string
 	systemTagsMap := make(map[string]*string)
 
@@ -180,6 +216,110 @@ func (az *Cloud) reconcileTags(currentTagsOnResource, newTags map[string]*string
 	return currentTagsOnResource, changed
 }
 
+// reconcileManagedTags adds or updates the new tags, and deletes the tags applied before which are not
+// in the new tags any more. The other tags are kept. The keys of the applied tags are recorded in the
+// k8s-azure-managed-tags tag, which is split into k8s-azure-managed-tags-1, k8s-azure-managed-tags-2 and
+// so on if the keys don't fit in a tag value.
+func reconcileManagedTags(currentTagsOnResource, newTags map[string]*string) (reconciledTags map[string]*string, changed bool) {
+	appliedKeys := sets.New[string]()
+	// recorded are the values of the k8s-azure-managed-tags tags by the lower case keys
+	recorded := make(map[string]string)
+	for key, value := range currentTagsOnResource {
+		if !isManagedTagsKey(key) {
+			continue
+		}
+		for _, k := range strings.Split(ptr.Deref(value, ""), consts.TagsDelimiter) {
+			if k != "" {
+				appliedKeys.Insert(strings.ToLower(k))
+			}
+		}
+		recorded[strings.ToLower(key)] = ptr.Deref(value, "")
+		delete(currentTagsOnResource, key)
+	}
+
+	keys := make([]string, 0, len(newTags))
+	for k, v := range newTags {
+		if isManagedTagsKey(k) {
+			continue
+		}
+		keys = append(keys, k)
+		found, key := findKeyInMapCaseInsensitive(currentTagsOnResource, k)
+		if !found {
+			currentTagsOnResource[k] = v
+			changed = true
+		} else if !strings.EqualFold(ptr.Deref(v, ""), ptr.Deref(currentTagsOnResource[key], "")) {
+			currentTagsOnResource[key] = v
+			changed = true
+		}
+	}
+
+	for k := range currentTagsOnResource {
+		if !appliedKeys.Has(strings.ToLower(k)) {
+			continue
+		}
+		if found, _ := findKeyInMapCaseInsensitive(newTags, k); !found {
+			klog.V(2).InfoS("reconcileManagedTags: delete tag", "key", k, "value", ptr.Deref(currentTagsOnResource[k], ""))
+			delete(currentTagsOnResource, k)
+			changed = true
+		}
+	}
+
+	sort.Strings(keys)
+	for i, value := range splitManagedTagKeys(keys) {
+		key := consts.ManagedTagsKey
+		if i > 0 {
+			key = fmt.Sprintf("%s-%d", consts.ManagedTagsKey, i)
+		}
+		currentTagsOnResource[key] = ptr.To(value)
+		if previous, found := recorded[key]; !found || previous != value {
+			changed = true
+		}
+		delete(recorded, key)
+	}
+	if len(recorded) > 0 {
+		// the records not needed any more are deleted
+		changed = true
+	}
+
+	return currentTagsOnResource, changed
+}
+
+// isManagedTagsKey returns if the tag is k8s-azure-managed-tags or one of the tags it is split into.
+func isManagedTagsKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == consts.ManagedTagsKey {
+		return true
+	}
+	suffix, found := strings.CutPrefix(key, consts.ManagedTagsKey+"-")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// splitManagedTagKeys joins the keys of the managed tags into the values of the k8s-azure-managed-tags
+// tags, each no longer than the maximum length of the tag values.
+func splitManagedTagKeys(keys []string) []string {
+	var values []string
+	var value string
+	for _, k := range keys {
+		switch {
+		case value == "":
+			value = k
+		case len(value)+len(consts.TagsDelimiter)+len(k) <= maxTagValueLength:
+			value += consts.TagsDelimiter + k
+		default:
+			values = append(values, value)
+			value = k
+		}
+	}
+	if value != "" {
+		values = append(values, value)
+	}
+	return values
+}
+
 func getExtendedLocationTypeFromString(extendedLocationType string) armnetwork.ExtendedLocationTypes {
 	extendedLocationType = strings.ToLower(extendedLocationType)
 	if val, ok := strToExtendedLocationType[extendedLocationType]; ok {
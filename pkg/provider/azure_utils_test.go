@@ -18,6 +18,8 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 
@@ -192,6 +194,80 @@ func TestReconcileTags(t *testing.T) {
 	}
 }
 
+func TestReconcileTagsPreserveForeignTags(t *testing.T) {
+	cloud := &Cloud{}
+	cloud.PreserveForeignTags = true
+	cloud.SystemTags = "aks-managed"
+
+	// the foreign tags are kept even if they are not in the system tags
+	tags, changed := cloud.reconcileTags(map[string]*string{
+		"foreign": ptr.To("x"),
+	}, map[string]*string{
+		"a": ptr.To("b"),
+		"c": ptr.To("d"),
+	})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]*string{
+		"foreign":             ptr.To("x"),
+		"a":                   ptr.To("b"),
+		"c":                   ptr.To("d"),
+		consts.ManagedTagsKey: ptr.To("a,c"),
+	}, tags)
+
+	tags, changed = cloud.reconcileTags(tags, map[string]*string{
+		"a": ptr.To("b"),
+		"c": ptr.To("d"),
+	})
+	assert.False(t, changed)
+	assert.Len(t, tags, 4)
+
+	// the tags removed from the config are deleted
+	tags, changed = cloud.reconcileTags(tags, map[string]*string{
+		"A": ptr.To("e"),
+	})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]*string{
+		"foreign":             ptr.To("x"),
+		"a":                   ptr.To("e"),
+		consts.ManagedTagsKey: ptr.To("A"),
+	}, tags)
+
+	// the record is deleted with the last applied tag
+	tags, changed = cloud.reconcileTags(tags, map[string]*string{})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]*string{"foreign": ptr.To("x")}, tags)
+
+	// the record is split if the keys don't fit in a tag value
+	newTags := map[string]*string{}
+	for i := 0; i < 20; i++ {
+		newTags[fmt.Sprintf("%s-%02d", strings.Repeat("k", 20), i)] = ptr.To("v")
+	}
+	tags, changed = cloud.reconcileTags(tags, newTags)
+	assert.True(t, changed)
+	assert.Len(t, tags, 1+20+2)
+	for _, key := range []string{consts.ManagedTagsKey, consts.ManagedTagsKey + "-1"} {
+		assert.LessOrEqual(t, len(ptr.Deref(tags[key], "")), maxTagValueLength)
+	}
+	_, changed = cloud.reconcileTags(tags, newTags)
+	assert.False(t, changed)
+
+	// the split records are merged back once the keys fit in a tag value
+	tags, changed = cloud.reconcileTags(tags, map[string]*string{"a": ptr.To("b")})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]*string{
+		"foreign":             ptr.To("x"),
+		"a":                   ptr.To("b"),
+		consts.ManagedTagsKey: ptr.To("a"),
+	}, tags)
+}
+
+func TestIsManagedTagsKey(t *testing.T) {
+	assert.True(t, isManagedTagsKey("K8s-Azure-Managed-Tags"))
+	assert.True(t, isManagedTagsKey("k8s-azure-managed-tags-2"))
+	assert.False(t, isManagedTagsKey("k8s-azure-managed-tags-x"))
+	assert.False(t, isManagedTagsKey("k8s-azure-managed"))
+}
+
 func TestParseTags(t *testing.T) {
 	for _, testCase := range []struct {
 		description, tags string
@@ -46,6 +46,8 @@ type VMSet interface {
 	GetIPByNodeName(ctx context.Context, name string) (string, string, error)
 	// GetPrimaryInterface gets machine primary network interface by node name.
 	GetPrimaryInterface(ctx context.Context, nodeName string) (*armnetwork.Interface, error)
+	// GetInterfacesByNodeName gets all network interfaces of the machine by node name, the primary one first.
+	GetInterfacesByNodeName(ctx context.Context, nodeName string) ([]*armnetwork.Interface, error)
 	// GetNodeNameByProviderID gets the node name by provider ID.
 	GetNodeNameByProviderID(ctx context.Context, providerID string) (types.NodeName, error)
 
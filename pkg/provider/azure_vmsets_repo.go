@@ -148,7 +148,12 @@ func (az *Cloud) newVMCache() (azcache.Resource, error) {
 	if az.VMCacheTTLInSeconds == 0 {
 		az.VMCacheTTLInSeconds = vmCacheTTLDefaultInSeconds
 	}
-	return azcache.NewTimedCache(time.Duration(az.VMCacheTTLInSeconds)*time.Second, getter, az.Config.DisableAPICallCache)
+	return azcache.NewTimedCacheWithNotFoundTTL(
+		time.Duration(az.VMCacheTTLInSeconds)*time.Second,
+		time.Duration(az.VMNotFoundCacheTTLInSeconds)*time.Second,
+		getter,
+		az.Config.DisableAPICallCache,
+	)
 }
 
 // getVirtualMachine calls 'ComputeClientFactory.GetVirtualMachineScaleSetClient().Get' with a timed cache
@@ -168,3 +173,29 @@ func (az *Cloud) getVirtualMachine(ctx context.Context, nodeName types.NodeName,
 
 	return (cachedVM.(*armcompute.VirtualMachine)), nil
 }
+
+// deleteVMNotFoundCacheForNode removes the cached not found results of the VM of a node, so
+// the VMs created just before their nodes registered are not reported as not found until
+// the not found cache TTLs expire.
+func (az *Cloud) deleteVMNotFoundCacheForNode(nodeName string) {
+	ss, isScaleSet := az.VMSet.(*ScaleSet)
+	if az.VMNotFoundCacheTTLInSeconds <= 0 && (!isScaleSet || ss.vmssVMNotFoundTTL() <= 0) {
+		return
+	}
+
+	if az.vmCache != nil && az.vmCache.GetStore() != nil {
+		if obj, exists, err := az.vmCache.GetStore().GetByKey(nodeName); err == nil && exists {
+			if entry, ok := obj.(*azcache.AzureCacheEntry); ok {
+				entry.Lock.Lock()
+				notFound := entry.Data == nil
+				entry.Lock.Unlock()
+				if notFound {
+					_ = az.vmCache.Delete(nodeName)
+				}
+			}
+		}
+	}
+	if isScaleSet {
+		ss.vmssVMNotFoundTimes.Delete(nodeName)
+	}
+}
@@ -52,6 +52,10 @@ var (
 	// ErrorNotVmssInstance indicates an instance is not belonging to any vmss.
 	ErrorNotVmssInstance = errors.New("not a vmss instance")
 	ErrScaleSetNotFound  = errors.New("scale set not found")
+	// errVMSSVMNotFoundDuringScaleWave indicates a node is not found in the VMs cache of its
+	// scale set refreshed during a node scale wave. It is not cloudprovider.InstanceNotFound,
+	// because the VM may be created after the refresh, so the callers retry later.
+	errVMSSVMNotFoundDuringScaleWave = errors.New("vmss vm not found in the cache refreshed during the node scale wave")
 
 	scaleSetNameRE           = regexp.MustCompile(`.*/subscriptions/(?:.*)/Microsoft.Compute/virtualMachineScaleSets/(.+)/virtualMachines(?:.*)`)
 	resourceGroupRE          = regexp.MustCompile(`.*/subscriptions/(?:.*)/resourceGroups/(.+)/providers/Microsoft.Compute/virtualMachineScaleSets/(?:.*)/virtualMachines(?:.*)`)
@@ -108,6 +112,18 @@ type ScaleSet struct {
 
 	// lockMap in cache refresh
 	lockMap *lockmap.LockMap
+
+	// vmssVMNotFoundTimes stores the nodes not found by the last refresh of their scale sets,
+	// so the scale sets are not refreshed again before vmssVMNotFoundTTL.
+	// Key: nodeName
+	// Value: time.Time of the refresh
+	vmssVMNotFoundTimes sync.Map
+
+	// nonVmssUniformNodeNotFoundTimes stores the provider IDs not found by the last refresh of
+	// nonVmssUniformNodesCache, so the cache is not refreshed again before VMNotFoundCacheTTLInSeconds.
+	// Key: providerID
+	// Value: time.Time of the refresh
+	nonVmssUniformNodeNotFoundTimes sync.Map
 }
 
 // RefreshCaches invalidates and renew all related caches.
@@ -205,6 +221,43 @@ func (ss *ScaleSet) getVMSS(ctx context.Context, vmssName string, crt azcache.Az
 	return vmss, nil
 }
 
+// isNonVmssUniformNodeNotFoundRecently returns true if the provider ID was not found by a refresh of
+// nonVmssUniformNodesCache within VMNotFoundCacheTTLInSeconds.
+func (ss *ScaleSet) isNonVmssUniformNodeNotFoundRecently(providerID string) bool {
+	notFoundTime, ok := ss.nonVmssUniformNodeNotFoundTimes.Load(providerID)
+	if !ok {
+		return false
+	}
+	if time.Since(notFoundTime.(time.Time)) < time.Duration(ss.VMNotFoundCacheTTLInSeconds)*time.Second {
+		return true
+	}
+	ss.nonVmssUniformNodeNotFoundTimes.Delete(providerID)
+	return false
+}
+
+// vmssVMNotFoundTTL returns how long a node not found by a refresh of its scale set is reported
+// as not found without refreshing the scale set again.
+func (ss *ScaleSet) vmssVMNotFoundTTL() time.Duration {
+	if ss.VmssVirtualMachinesNotFoundCacheTTLInSeconds > 0 {
+		return time.Duration(ss.VmssVirtualMachinesNotFoundCacheTTLInSeconds) * time.Second
+	}
+	return time.Duration(ss.VMNotFoundCacheTTLInSeconds) * time.Second
+}
+
+// isVMSSVMNotFoundRecently returns true if the node was not found by a refresh of its scale set
+// within vmssVMNotFoundTTL.
+func (ss *ScaleSet) isVMSSVMNotFoundRecently(nodeName string) bool {
+	notFoundTime, ok := ss.vmssVMNotFoundTimes.Load(nodeName)
+	if !ok {
+		return false
+	}
+	if time.Since(notFoundTime.(time.Time)) < ss.vmssVMNotFoundTTL() {
+		return true
+	}
+	ss.vmssVMNotFoundTimes.Delete(nodeName)
+	return false
+}
+
 // getVmssVMByNodeIdentity find virtualMachineScaleSetVM by nodeIdentity, using node's parent VMSS cache.
 // Returns cloudprovider.InstanceNotFound if the node does not belong to the scale set named in nodeIdentity.
 func (ss *ScaleSet) getVmssVMByNodeIdentity(ctx context.Context, node *nodeIdentity, crt azcache.AzureCacheReadType) (*virtualmachine.VirtualMachine, error) {
@@ -250,11 +303,29 @@ func (ss *ScaleSet) getVmssVMByNodeIdentity(ctx context.Context, node *nodeIdent
 			return vm, nil
 		}
 
+		if ss.isVMSSVMNotFoundRecently(node.nodeName) && crt != azcache.CacheReadTypeForceRefresh {
+			klog.V(4).Infof("VMSS VM with nodeName %s was not found recently, skipping the refresh of the cache(vmss: %s, rg: %s)", node.nodeName, node.vmssName, node.resourceGroup)
+			return nil, cloudprovider.InstanceNotFound
+		}
+
+		// During a node scale wave, the lookups of the new nodes share the refreshes of the cache.
+		// The node may still have a VM created after the refresh, so the lookup fails with a
+		// retriable error instead of cloudprovider.InstanceNotFound, which would let the node
+		// lifecycle controller delete the node.
+		if ss.nodeScaleWaveTracker.inScaleWave() && crt != azcache.CacheReadTypeForceRefresh &&
+			ss.isVMSSVMCacheRefreshedWithin(cacheKey, nodeScaleWaveVMCacheRefreshInterval) {
+			klog.V(4).Infof("VMSS VM with nodeName %s was not found in the cache refreshed during the node scale wave(vmss: %s, rg: %s)", node.nodeName, node.vmssName, node.resourceGroup)
+			return nil, fmt.Errorf("node %s: %w", node.nodeName, errVMSSVMNotFoundDuringScaleWave)
+		}
+
 		klog.V(2).Infof("Couldn't find VMSS VM with nodeName %s, refreshing the cache(vmss: %s, rg: %s)", node.nodeName, node.vmssName, node.resourceGroup)
 		vm, found, err = getter(ctx, azcache.CacheReadTypeForceRefresh)
 		if err != nil {
 			return nil, err
 		}
+		if !found && ss.vmssVMNotFoundTTL() > 0 {
+			ss.vmssVMNotFoundTimes.Store(node.nodeName, time.Now())
+		}
 	}
 
 	if found && vm != nil {
@@ -644,11 +715,11 @@ func (ss *ScaleSet) GetIPByNodeName(ctx context.Context, nodeName string) (strin
 	return internalIP, publicIP, nil
 }
 
-func (ss *ScaleSet) getVMSSPublicIPAddress(resourceGroupName string, virtualMachineScaleSetName string, virtualMachineIndex string, networkInterfaceName string, IPConfigurationName string, publicIPAddressName string) (*armnetwork.PublicIPAddress, bool, error) {
+func (az *Cloud) getVMSSPublicIPAddress(resourceGroupName string, virtualMachineScaleSetName string, virtualMachineIndex string, networkInterfaceName string, IPConfigurationName string, publicIPAddressName string) (*armnetwork.PublicIPAddress, bool, error) {
 	ctx, cancel := getContextWithCancel()
 	defer cancel()
 
-	pip, err := ss.NetworkClientFactory.GetPublicIPAddressClient().GetVirtualMachineScaleSetPublicIPAddress(ctx, resourceGroupName, virtualMachineScaleSetName, virtualMachineIndex, networkInterfaceName, IPConfigurationName, publicIPAddressName, nil)
+	pip, err := az.NetworkClientFactory.GetPublicIPAddressClient().GetVirtualMachineScaleSetPublicIPAddress(ctx, resourceGroupName, virtualMachineScaleSetName, virtualMachineIndex, networkInterfaceName, IPConfigurationName, publicIPAddressName, nil)
 	exists, rerr := checkResourceExistsFromError(err)
 	if rerr != nil {
 		return nil, false, err
@@ -992,6 +1063,55 @@ func (ss *ScaleSet) GetPrimaryInterface(ctx context.Context, nodeName string) (*
 	return nic, nil
 }
 
+// GetInterfacesByNodeName gets all network interfaces of the machine by node name, the primary one first.
+func (ss *ScaleSet) GetInterfacesByNodeName(ctx context.Context, nodeName string) ([]*armnetwork.Interface, error) {
+	vmManagementType, err := ss.getVMManagementTypeByNodeName(ctx, nodeName, azcache.CacheReadTypeUnsafe)
+	if err != nil {
+		klog.Errorf("Failed to check VM management type: %v", err)
+		return nil, err
+	}
+
+	if vmManagementType == ManagedByAvSet {
+		// vm is managed by availability set.
+		return ss.availabilitySet.GetInterfacesByNodeName(ctx, nodeName)
+	}
+	if vmManagementType == ManagedByVmssFlex {
+		// vm is managed by vmss flex.
+		return ss.flexScaleSet.GetInterfacesByNodeName(ctx, nodeName)
+	}
+
+	vm, err := ss.getVmssVM(ctx, nodeName, azcache.CacheReadTypeDefault)
+	if err != nil {
+		// VM is availability set, but not cached yet in availabilitySetNodesCache.
+		if errors.Is(err, ErrorNotVmssInstance) {
+			return ss.availabilitySet.GetInterfacesByNodeName(ctx, nodeName)
+		}
+		return nil, err
+	}
+	machine := vm.AsVirtualMachineScaleSetVM()
+	if machine.Properties == nil {
+		return nil, nil
+	}
+
+	var nics []*armnetwork.Interface
+	for _, nicID := range getInterfaceIDs(machine.Properties.NetworkProfile) {
+		nicName, err := getLastSegment(nicID, "/")
+		if err != nil {
+			return nil, err
+		}
+		resourceGroup, err := extractResourceGroupByVMSSNicID(nicID)
+		if err != nil {
+			return nil, err
+		}
+		nic, err := ss.NetworkClientFactory.GetInterfaceClient().GetVirtualMachineScaleSetNetworkInterface(ctx, resourceGroup, vm.VMSSName, vm.InstanceID, nicName)
+		if err != nil {
+			return nil, err
+		}
+		nics = append(nics, nic)
+	}
+	return nics, nil
+}
+
 // getPrimaryNetworkInterfaceConfiguration gets primary network interface configuration for VMSS VM or VMSS.
 func getPrimaryNetworkInterfaceConfiguration(networkConfigurations []*armcompute.VirtualMachineScaleSetNetworkConfiguration, resource string) (*armcompute.VirtualMachineScaleSetNetworkConfiguration, error) {
 	if len(networkConfigurations) == 1 {
@@ -1051,6 +1171,11 @@ func (ss *ScaleSet) EnsureHostInPool(ctx context.Context, _ *v1.Service, nodeNam
 			klog.Infof("EnsureHostInPool: skipping node %s because it is not found", vmName)
 			return "", "", "", nil, nil
 		}
+		if errors.Is(err, errVMSSVMNotFoundDuringScaleWave) {
+			// the node is added by the update at the end of the wave
+			klog.Infof("EnsureHostInPool: skipping node %s because it is not found during the node scale wave", vmName)
+			return "", "", "", nil, nil
+		}
 
 		logger.Error(err, "failed to get vmss vm", "vmName", vmName)
 		if !errors.Is(err, ErrorNotVmssInstance) {
@@ -1096,13 +1221,13 @@ func (ss *ScaleSet) EnsureHostInPool(ctx context.Context, _ *v1.Service, nodeNam
 	}
 
 	networkInterfaceConfigurations := vm.VirtualMachineScaleSetVMProperties.NetworkProfileConfiguration.NetworkInterfaceConfigurations
-	primaryNetworkInterfaceConfiguration, err := getPrimaryNetworkInterfaceConfiguration(networkInterfaceConfigurations, vmName)
+	primaryNetworkInterfaceConfiguration, err := ss.getBackendPoolNetworkInterfaceConfiguration(networkInterfaceConfigurations, vmName)
 	if err != nil {
 		return "", "", "", nil, err
 	}
 
 	// Find primary network interface configuration.
-	primaryIPConfiguration, err := getPrimaryIPConfigFromVMSSNetworkConfig(primaryNetworkInterfaceConfiguration, backendPoolID, vmName)
+	primaryIPConfiguration, err := ss.getBackendPoolIPConfigFromVMSSNetworkConfig(primaryNetworkInterfaceConfiguration, backendPoolID, vmName)
 	if err != nil {
 		return "", "", "", nil, err
 	}
@@ -1120,12 +1245,7 @@ func (ss *ScaleSet) EnsureHostInPool(ctx context.Context, _ *v1.Service, nodeNam
 		}
 	}
 
-	// The backendPoolID has already been found from existing LoadBalancerBackendAddressPools.
-	if foundPool {
-		return "", "", "", nil, nil
-	}
-
-	if ss.UseStandardLoadBalancer() && len(newBackendPools) > 0 {
+	if !foundPool && ss.UseStandardLoadBalancer() && len(newBackendPools) > 0 {
 		// Although standard load balancer supports backends from multiple scale
 		// sets, the same network interface couldn't be added to more than one load balancer of
 		// the same type. Omit those nodes (e.g. masters) so Azure ARM won't complain
@@ -1146,12 +1266,20 @@ func (ss *ScaleSet) EnsureHostInPool(ctx context.Context, _ *v1.Service, nodeNam
 		}
 	}
 
+	removedFromOthers := ss.removeBackendPoolFromOtherNetworkConfigurations(networkInterfaceConfigurations, primaryNetworkInterfaceConfiguration, backendPoolID)
+	// The backendPoolID has already been found from existing LoadBalancerBackendAddressPools.
+	if foundPool && !removedFromOthers {
+		return "", "", "", nil, nil
+	}
+
 	// Compose a new vmssVM with added backendPoolID.
-	newBackendPools = append(newBackendPools,
-		&armcompute.SubResource{
-			ID: ptr.To(backendPoolID),
-		})
-	primaryIPConfiguration.Properties.LoadBalancerBackendAddressPools = newBackendPools
+	if !foundPool {
+		newBackendPools = append(newBackendPools,
+			&armcompute.SubResource{
+				ID: ptr.To(backendPoolID),
+			})
+		primaryIPConfiguration.Properties.LoadBalancerBackendAddressPools = newBackendPools
+	}
 	newVM := &armcompute.VirtualMachineScaleSetVM{
 		Location: &vm.Location,
 		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
@@ -1267,12 +1395,12 @@ func (ss *ScaleSet) ensureVMSSInPool(ctx context.Context, _ *v1.Service, nodes [
 		}
 
 		vmssNIC := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-		primaryNIC, err := getPrimaryNetworkInterfaceConfiguration(vmssNIC, vmssName)
+		primaryNIC, err := ss.getBackendPoolNetworkInterfaceConfiguration(vmssNIC, vmssName)
 		if err != nil {
 			return err
 		}
 		// Find primary network interface configuration.
-		primaryIPConfig, err := getPrimaryIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, vmssName)
+		primaryIPConfig, err := ss.getBackendPoolIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, vmssName)
 		if err != nil {
 			return err
 		}
@@ -1289,11 +1417,7 @@ func (ss *ScaleSet) ensureVMSSInPool(ctx context.Context, _ *v1.Service, nodes [
 				break
 			}
 		}
-		if found {
-			continue
-		}
-
-		if ss.UseStandardLoadBalancer() && len(loadBalancerBackendAddressPools) > 0 {
+		if !found && ss.UseStandardLoadBalancer() && len(loadBalancerBackendAddressPools) > 0 {
 			// Although standard load balancer supports backends from multiple scale
 			// sets, the same network interface couldn't be added to more than one load balancer of
 			// the same type. Omit those nodes (e.g. masters) so Azure ARM won't complain
@@ -1314,12 +1438,19 @@ func (ss *ScaleSet) ensureVMSSInPool(ctx context.Context, _ *v1.Service, nodes [
 			}
 		}
 
+		removedFromOthers := ss.removeBackendPoolFromOtherNetworkConfigurations(vmssNIC, primaryNIC, backendPoolID)
+		if found && !removedFromOthers {
+			continue
+		}
+
 		// Compose a new vmss with added backendPoolID.
-		loadBalancerBackendAddressPools = append(loadBalancerBackendAddressPools,
-			&armcompute.SubResource{
-				ID: ptr.To(backendPoolID),
-			})
-		primaryIPConfig.Properties.LoadBalancerBackendAddressPools = loadBalancerBackendAddressPools
+		if !found {
+			loadBalancerBackendAddressPools = append(loadBalancerBackendAddressPools,
+				&armcompute.SubResource{
+					ID: ptr.To(backendPoolID),
+				})
+			primaryIPConfig.Properties.LoadBalancerBackendAddressPools = loadBalancerBackendAddressPools
+		}
 		newVMSS := armcompute.VirtualMachineScaleSet{
 			Location: vmss.Location,
 			Properties: &armcompute.VirtualMachineScaleSetProperties{
@@ -1406,6 +1537,7 @@ func (ss *ScaleSet) ensureHostsInPool(ctx context.Context, service *v1.Service,
 
 	hostUpdates := make([]func() error, 0, len(nodes))
 	nodeUpdates := make(map[vmssMetaInfo]map[string]armcompute.VirtualMachineScaleSetVM)
+	updatedNodeNames := make(map[vmssMetaInfo][]string)
 	errors := make([]error, 0)
 	for _, node := range nodes {
 		localNodeName := node.Name
@@ -1445,6 +1577,7 @@ func (ss *ScaleSet) ensureHostsInPool(ctx context.Context, service *v1.Service,
 				nodeInstanceID: *nodeVMSSVM,
 			}
 		}
+		updatedNodeNames[nodeVMSSMetaInfo] = append(updatedNodeNames[nodeVMSSMetaInfo], localNodeName)
 
 		// Invalidate the cache since the VMSS VM would be updated.
 		defer func() {
@@ -1452,6 +1585,18 @@ func (ss *ScaleSet) ensureHostsInPool(ctx context.Context, service *v1.Service,
 		}()
 	}
 
+	// The VMs are updated together with the other backend pools of the service if they are batched.
+	if batch := vmssVMUpdateBatchFromContext(ctx); batch != nil {
+		for meta, update := range nodeUpdates {
+			batch.add(ss, meta, update, updatedNodeNames[meta])
+		}
+		if len(errors) > 0 {
+			return utilerrors.Flatten(utilerrors.NewAggregate(errors))
+		}
+		isOperationSucceeded = true
+		return nil
+	}
+
 	// Update VMs with best effort that have already been added to nodeUpdates.
 	for meta, update := range nodeUpdates {
 		// create new instance of meta and update for passing to anonymous function
@@ -1465,22 +1610,7 @@ func (ss *ScaleSet) ensureHostsInPool(ctx context.Context, service *v1.Service,
 				"backendPoolID", backendPoolID,
 			}
 			logger := klog.LoggerWithValues(klog.FromContext(ctx), logFields...)
-			batchSize, err := ss.VMSSBatchSize(ctx, meta.vmssName)
-			if err != nil {
-				logger.Error(err, "Failed to get vmss batch size")
-				return err
-			}
-			ctx = klog.NewContext(ctx, logger)
-
-			errChan := ss.UpdateVMSSVMsInBatch(ctx, meta, update, batchSize)
-
-			errs := make([]error, 0)
-			for err := range errChan {
-				if err != nil {
-					errs = append(errs, err)
-				}
-			}
-			return utilerrors.NewAggregate(errs)
+			return ss.updateVMSSVMs(klog.NewContext(ctx, logger), meta, update)
 		})
 	}
 	errs := utilerrors.AggregateGoroutines(hostUpdates...)
@@ -1580,7 +1710,7 @@ func (ss *ScaleSet) ensureBackendPoolDeletedFromNode(ctx context.Context, nodeNa
 	logger := klog.Background().WithName("ensureBackendPoolDeletedFromNode").WithValues("nodeName", nodeName, "backendPoolIDs", backendPoolIDs)
 	vm, err := ss.getVmssVM(ctx, nodeName, azcache.CacheReadTypeDefault)
 	if err != nil {
-		if errors.Is(err, cloudprovider.InstanceNotFound) {
+		if errors.Is(err, cloudprovider.InstanceNotFound) || errors.Is(err, errVMSSVMNotFoundDuringScaleWave) {
 			klog.Infof("ensureBackendPoolDeletedFromNode: skipping node %s because it is not found", nodeName)
 			return "", "", "", nil, nil
 		}
@@ -1603,14 +1733,14 @@ func (ss *ScaleSet) ensureBackendPoolDeletedFromNode(ctx context.Context, nodeNa
 		return "", "", "", nil, nil
 	}
 	networkInterfaceConfigurations := vm.VirtualMachineScaleSetVMProperties.NetworkProfileConfiguration.NetworkInterfaceConfigurations
-	primaryNetworkInterfaceConfiguration, err := getPrimaryNetworkInterfaceConfiguration(networkInterfaceConfigurations, nodeName)
+	primaryNetworkInterfaceConfiguration, err := ss.getBackendPoolNetworkInterfaceConfiguration(networkInterfaceConfigurations, nodeName)
 	if err != nil {
 		return "", "", "", nil, err
 	}
 
 	foundTotal := false
 	for _, backendPoolID := range backendPoolIDs {
-		found, err := deleteBackendPoolFromIPConfig("ensureBackendPoolDeletedFromNode", backendPoolID, nodeName, primaryNetworkInterfaceConfiguration)
+		found, err := ss.deleteBackendPoolFromIPConfig("ensureBackendPoolDeletedFromNode", backendPoolID, nodeName, primaryNetworkInterfaceConfiguration)
 		if err != nil {
 			return "", "", "", nil, err
 		}
@@ -1786,7 +1916,7 @@ func (ss *ScaleSet) ensureBackendPoolDeletedFromVmssUniform(ctx context.Context,
 				return true
 			}
 			vmssNIC := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-			primaryNIC, err := getPrimaryNetworkInterfaceConfiguration(vmssNIC, ptr.Deref(vmss.Name, ""))
+			primaryNIC, err := ss.getBackendPoolNetworkInterfaceConfiguration(vmssNIC, ptr.Deref(vmss.Name, ""))
 			if err != nil {
 				klog.Errorf("ensureBackendPoolDeletedFromVMSS: failed to get the primary network interface config of the VMSS %s: %v", ptr.Deref(vmss.Name, ""), err)
 				errorList = append(errorList, err)
@@ -1794,7 +1924,7 @@ func (ss *ScaleSet) ensureBackendPoolDeletedFromVmssUniform(ctx context.Context,
 			}
 
 			handleBackendPool := func(backendPoolID string) bool {
-				primaryIPConfig, err := getPrimaryIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, ptr.Deref(vmss.Name, ""))
+				primaryIPConfig, err := ss.getBackendPoolIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, ptr.Deref(vmss.Name, ""))
 				if err != nil {
 					klog.Errorf("ensureBackendPoolDeletedFromVMSS: failed to find the primary IP config from the VMSS %s's network config : %v", ptr.Deref(vmss.Name, ""), err)
 					errorList = append(errorList, err)
@@ -1945,20 +2075,7 @@ func (ss *ScaleSet) ensureBackendPoolDeleted(ctx context.Context, service *v1.Se
 				"backendPoolIDs", backendPoolIDs,
 			}
 
-			batchSize, err := ss.VMSSBatchSize(ctx, meta.vmssName)
-			if err != nil {
-				klog.ErrorS(err, "Failed to get vmss batch size", logFields...)
-				return err
-			}
-
-			errChan := ss.UpdateVMSSVMsInBatch(ctx, meta, update, batchSize)
-			errs := make([]error, 0)
-			for err := range errChan {
-				if err != nil {
-					errs = append(errs, err)
-				}
-			}
-			err = utilerrors.NewAggregate(errs)
+			err := ss.updateVMSSVMs(ctx, meta, update)
 			if err != nil {
 				klog.ErrorS(err, "Failed to update VMs for VMSS", logFields...)
 				return err
@@ -2140,8 +2257,8 @@ func (ss *ScaleSet) GetNodeCIDRMasksByProviderID(ctx context.Context, providerID
 }
 
 // deleteBackendPoolFromIPConfig deletes the backend pool from the IP config.
-func deleteBackendPoolFromIPConfig(msg, backendPoolID, resource string, primaryNIC *armcompute.VirtualMachineScaleSetNetworkConfiguration) (bool, error) {
-	primaryIPConfig, err := getPrimaryIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, resource)
+func (az *Cloud) deleteBackendPoolFromIPConfig(msg, backendPoolID, resource string, primaryNIC *armcompute.VirtualMachineScaleSetNetworkConfiguration) (bool, error) {
+	primaryIPConfig, err := az.getBackendPoolIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, resource)
 	if err != nil {
 		klog.Errorf("%s: failed to get the primary IP config from the VMSS %q's network config: %v", msg, resource, err)
 		return false, err
@@ -2192,7 +2309,7 @@ func (ss *ScaleSet) EnsureBackendPoolDeletedFromVMSets(ctx context.Context, vmss
 			continue
 		}
 		vmssNIC := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-		primaryNIC, err := getPrimaryNetworkInterfaceConfiguration(vmssNIC, vmssName)
+		primaryNIC, err := ss.getBackendPoolNetworkInterfaceConfiguration(vmssNIC, vmssName)
 		if err != nil {
 			klog.Errorf("EnsureBackendPoolDeletedFromVMSets: failed to get the primary network interface config of the VMSS %s: %v", vmssName, err)
 			errors = append(errors, err)
@@ -2200,7 +2317,7 @@ func (ss *ScaleSet) EnsureBackendPoolDeletedFromVMSets(ctx context.Context, vmss
 		}
 		foundTotal := false
 		for _, backendPoolID := range backendPoolIDs {
-			found, err := deleteBackendPoolFromIPConfig("EnsureBackendPoolDeletedFromVMSets", backendPoolID, vmssName, primaryNIC)
+			found, err := ss.deleteBackendPoolFromIPConfig("EnsureBackendPoolDeletedFromVMSets", backendPoolID, vmssName, primaryNIC)
 			if err != nil {
 				errors = append(errors, err)
 				continue
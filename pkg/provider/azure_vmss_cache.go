@@ -21,9 +21,13 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
@@ -73,17 +77,17 @@ func (ss *ScaleSet) newVMSSCache() (azcache.Resource, error) {
 			return nil, err
 		}
 
-		resourceGroupNotFound := false
-		for _, resourceGroup := range allResourceGroups.UnsortedList() {
+		var resourceGroupNotFound atomic.Bool
+		err = ss.forEachResourceGroup(ctx, allResourceGroups.UnsortedList(), func(ctx context.Context, resourceGroup string) error {
 			allScaleSets, rerr := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().List(ctx, resourceGroup)
 			if rerr != nil {
 				if exists, err := errutils.CheckResourceExistsFromAzcoreError(rerr); !exists && err == nil {
 					klog.Warningf("Skip caching vmss for resource group %s due to error: %v", resourceGroup, rerr.Error())
-					resourceGroupNotFound = true
-					continue
+					resourceGroupNotFound.Store(true)
+					return nil
 				}
 				klog.Errorf("ComputeClientFactory.GetVirtualMachineScaleSetClient().List failed: %v", rerr)
-				return nil, rerr
+				return rerr
 			}
 
 			for i := range allScaleSets {
@@ -100,10 +104,14 @@ func (ss *ScaleSet) newVMSSCache() (azcache.Resource, error) {
 					})
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 
 		if !ss.Cloud.Config.DisableAPICallCache {
-			if resourceGroupNotFound {
+			if resourceGroupNotFound.Load() {
 				// gc vmss vm cache when there is resource group not found
 				vmssVMKeys := ss.vmssVMCache.GetStore().ListKeys()
 				for _, cacheKey := range vmssVMKeys {
@@ -124,6 +132,74 @@ func (ss *ScaleSet) newVMSSCache() (azcache.Resource, error) {
 	return azcache.NewTimedCache(time.Duration(ss.Config.VmssCacheTTLInSeconds)*time.Second, getter, ss.Config.DisableAPICallCache)
 }
 
+// forEachResourceGroup calls fn for the resource groups concurrently, at most CacheWarmupConcurrency
+// at a time, and returns the first error.
+func (az *Cloud) forEachResourceGroup(ctx context.Context, resourceGroups []string, fn func(ctx context.Context, resourceGroup string) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(az.cacheWarmupConcurrency())
+	for _, resourceGroup := range resourceGroups {
+		g.Go(func() error {
+			return fn(ctx, resourceGroup)
+		})
+	}
+	return g.Wait()
+}
+
+func (az *Cloud) cacheWarmupConcurrency() int {
+	if az.CacheWarmupConcurrency > 0 {
+		return az.CacheWarmupConcurrency
+	}
+	return consts.DefaultCacheWarmupConcurrency
+}
+
+// warmUpCaches fills the caches of the scale sets and of the VMs of the scale sets of the nodes once
+// the nodes are synced, so the first reconciliations after the startup or a leader failover don't
+// list them one by one. The scale sets are listed concurrently and independently, the ones failed to
+// be listed are listed again when they are read.
+func (ss *ScaleSet) warmUpCaches(ctx context.Context, nodesSynced cache.InformerSynced) {
+	if !cache.WaitForCacheSync(ctx.Done(), nodesSynced) {
+		return
+	}
+	start := time.Now()
+	nodes, err := ss.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("warmUpCaches: failed to list the nodes: %v", err)
+		return
+	}
+
+	type scaleSet struct {
+		resourceGroup, name string
+	}
+	scaleSets := make(map[string]scaleSet)
+	for _, node := range nodes {
+		name, err := extractScaleSetNameByProviderID(node.Spec.ProviderID)
+		if err != nil {
+			continue
+		}
+		resourceGroup, err := extractResourceGroupByProviderID(node.Spec.ProviderID)
+		if err != nil {
+			continue
+		}
+		scaleSets[getVMSSVMCacheKey(resourceGroup, name)] = scaleSet{resourceGroup: resourceGroup, name: name}
+	}
+
+	if _, err := ss.vmssCache.Get(ctx, consts.VMSSKey, azcache.CacheReadTypeDefault); err != nil {
+		klog.Warningf("warmUpCaches: failed to list the scale sets: %v", err)
+	}
+	var g errgroup.Group
+	g.SetLimit(ss.cacheWarmupConcurrency())
+	for _, scaleSet := range scaleSets {
+		g.Go(func() error {
+			if _, err := ss.getVMSSVMsFromCache(ctx, scaleSet.resourceGroup, scaleSet.name, azcache.CacheReadTypeDefault); err != nil {
+				klog.Warningf("warmUpCaches: failed to list the VMs of scale set %s in resource group %s: %v", scaleSet.name, scaleSet.resourceGroup, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	klog.V(2).Infof("warmUpCaches: listed the VMs of %d scale sets in %s", len(scaleSets), time.Since(start))
+}
+
 func (ss *ScaleSet) getVMSSVMsFromCache(ctx context.Context, resourceGroup, vmssName string, crt azcache.AzureCacheReadType) (*sync.Map, error) {
 	cacheKey := getVMSSVMCacheKey(resourceGroup, vmssName)
 	entry, err := ss.vmssVMCache.Get(ctx, cacheKey, crt)
@@ -140,6 +216,21 @@ func (ss *ScaleSet) getVMSSVMsFromCache(ctx context.Context, resourceGroup, vmss
 	return virtualMachines, nil
 }
 
+// isVMSSVMCacheRefreshedWithin returns true if the VMs cache of the scale set was refreshed within the interval.
+func (ss *ScaleSet) isVMSSVMCacheRefreshedWithin(cacheKey string, interval time.Duration) bool {
+	entry, exists, err := ss.vmssVMCache.GetStore().GetByKey(cacheKey)
+	if err != nil || !exists {
+		return false
+	}
+	cached, ok := entry.(*azcache.AzureCacheEntry)
+	if !ok {
+		return false
+	}
+	cached.Lock.Lock()
+	defer cached.Lock.Unlock()
+	return cached.Data != nil && time.Since(cached.CreatedOn) < interval
+}
+
 // newVMSSVirtualMachinesCache instantiates a new VMs cache for VMs belonging to the provided VMSS.
 func (ss *ScaleSet) newVMSSVirtualMachinesCache() (azcache.Resource, error) {
 	vmssVirtualMachinesCacheTTL := time.Duration(ss.Config.VmssVirtualMachinesCacheTTLInSeconds) * time.Second
@@ -250,6 +341,7 @@ func (ss *ScaleSet) DeleteCacheForNode(ctx context.Context, nodeName string) err
 	if ss.Config.DisableAPICallCache {
 		return nil
 	}
+	ss.vmssVMNotFoundTimes.Delete(nodeName)
 	vmManagementType, err := ss.getVMManagementTypeByNodeName(ctx, nodeName, azcache.CacheReadTypeUnsafe)
 	if err != nil {
 		klog.Errorf("getVMManagementTypeByNodeName(%s) failed with %v", nodeName, err)
@@ -341,23 +433,34 @@ func (ss *ScaleSet) newNonVmssUniformNodesCache() (azcache.Resource, error) {
 		}
 		klog.V(2).Infof("refresh the cache of NonVmssUniformNodesCache in rg %v", resourceGroups)
 
-		for _, resourceGroup := range resourceGroups.UnsortedList() {
-			vms, err := ss.Cloud.ListVirtualMachines(ctx, resourceGroup)
+		var (
+			lock sync.Mutex
+			vms  []*armcompute.VirtualMachine
+		)
+		err = ss.forEachResourceGroup(ctx, resourceGroups.UnsortedList(), func(ctx context.Context, resourceGroup string) error {
+			resourceGroupVMs, err := ss.Cloud.ListVirtualMachines(ctx, resourceGroup)
 			if err != nil {
-				return nil, fmt.Errorf("getter function of nonVmssUniformNodesCache: failed to list vms in the resource group %s: %w", resourceGroup, err)
+				return fmt.Errorf("getter function of nonVmssUniformNodesCache: failed to list vms in the resource group %s: %w", resourceGroup, err)
 			}
-			for _, vm := range vms {
-				if vm.Properties.OSProfile != nil && vm.Properties.OSProfile.ComputerName != nil {
-					if vm.Properties.VirtualMachineScaleSet != nil {
-						vmssFlexVMNodeNames.Insert(strings.ToLower(ptr.Deref(vm.Properties.OSProfile.ComputerName, "")))
-						if vm.ID != nil {
-							vmssFlexVMProviderIDs.Insert(ss.ProviderName() + "://" + ptr.Deref(vm.ID, ""))
-						}
-					} else {
-						avSetVMNodeNames.Insert(strings.ToLower(ptr.Deref(vm.Properties.OSProfile.ComputerName, "")))
-						if vm.ID != nil {
-							avSetVMProviderIDs.Insert(ss.ProviderName() + "://" + ptr.Deref(vm.ID, ""))
-						}
+			lock.Lock()
+			defer lock.Unlock()
+			vms = append(vms, resourceGroupVMs...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, vm := range vms {
+			if vm.Properties.OSProfile != nil && vm.Properties.OSProfile.ComputerName != nil {
+				if vm.Properties.VirtualMachineScaleSet != nil {
+					vmssFlexVMNodeNames.Insert(strings.ToLower(ptr.Deref(vm.Properties.OSProfile.ComputerName, "")))
+					if vm.ID != nil {
+						vmssFlexVMProviderIDs.Insert(ss.ProviderName() + "://" + ptr.Deref(vm.ID, ""))
+					}
+				} else {
+					avSetVMNodeNames.Insert(strings.ToLower(ptr.Deref(vm.Properties.OSProfile.ComputerName, "")))
+					if vm.ID != nil {
+						avSetVMProviderIDs.Insert(ss.ProviderName() + "://" + ptr.Deref(vm.ID, ""))
 					}
 				}
 			}
@@ -461,6 +564,22 @@ func (ss *ScaleSet) getVMManagementTypeByProviderID(ctx context.Context, provide
 	cachedVmssFlexVMProviderIDs := cached.(NonVmssUniformNodesEntry).VMSSFlexVMProviderIDs
 	cachedAvSetVMProviderIDs := cached.(NonVmssUniformNodesEntry).AvSetVMProviderIDs
 
+	// If the VM is not in the cache, assume it has been created after the last cache refresh and attempt to refresh the cache,
+	// unless the VM was not found by a recent refresh.
+	if !cachedAvSetVMProviderIDs.Has(providerID) && !cachedVmssFlexVMProviderIDs.Has(providerID) && crt != azcache.CacheReadTypeForceRefresh &&
+		!ss.isNonVmssUniformNodeNotFoundRecently(providerID) {
+		klog.V(2).Infof("VM %s has been created since the last VM cache refresh in NonVmssUniformNodesEntry, refreshing the cache", providerID)
+		cached, err = ss.nonVmssUniformNodesCache.Get(ctx, consts.NonVmssUniformNodesKey, azcache.CacheReadTypeForceRefresh)
+		if err != nil {
+			return ManagedByUnknownVMSet, err
+		}
+		cachedVmssFlexVMProviderIDs = cached.(NonVmssUniformNodesEntry).VMSSFlexVMProviderIDs
+		cachedAvSetVMProviderIDs = cached.(NonVmssUniformNodesEntry).AvSetVMProviderIDs
+		if !cachedAvSetVMProviderIDs.Has(providerID) && !cachedVmssFlexVMProviderIDs.Has(providerID) && ss.VMNotFoundCacheTTLInSeconds > 0 {
+			ss.nonVmssUniformNodeNotFoundTimes.Store(providerID, time.Now())
+		}
+	}
+
 	if cachedAvSetVMProviderIDs.Has(providerID) {
 		return ManagedByAvSet, nil
 	}
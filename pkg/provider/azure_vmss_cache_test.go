@@ -21,7 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
@@ -31,6 +33,10 @@ import (
 
 	"go.uber.org/mock/gomock"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/utils/ptr"
 
@@ -325,6 +331,54 @@ func TestGetVMManagementTypeByProviderID(t *testing.T) {
 	}
 }
 
+func TestGetVMManagementTypeByProviderIDRefreshesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+
+	testVM1 := generateVmssFlexTestVMWithoutInstanceView(testVM1Spec)
+	testVM2 := generateVmssFlexTestVMWithoutInstanceView(testVM2Spec)
+	mockVMClient := ss.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	gomock.InOrder(
+		mockVMClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachine{testVM1}, nil),
+		mockVMClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachine{testVM1, testVM2}, nil),
+	)
+
+	vmManagementType, err := ss.getVMManagementTypeByProviderID(context.TODO(), "azure://"+ptr.Deref(testVM1.ID, ""), azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, ManagedByVmssFlex, vmManagementType)
+
+	// the new vmss flex VM is found after refreshing the cache
+	vmManagementType, err = ss.getVMManagementTypeByProviderID(context.TODO(), "azure://"+ptr.Deref(testVM2.ID, ""), azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, ManagedByVmssFlex, vmManagementType)
+}
+
+func TestGetVMManagementTypeByProviderIDNotFoundRecently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	ss.VMNotFoundCacheTTLInSeconds = 60
+
+	testVM1 := generateVmssFlexTestVMWithoutInstanceView(testVM1Spec)
+	mockVMClient := ss.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	mockVMClient.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*armcompute.VirtualMachine{testVM1}, nil).Times(2)
+
+	providerID := "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/unknown"
+	_, err = ss.getVMManagementTypeByProviderID(context.TODO(), providerID, azcache.CacheReadTypeDefault)
+	assert.Error(t, err)
+	assert.True(t, ss.isNonVmssUniformNodeNotFoundRecently(providerID))
+
+	// the unknown provider ID doesn't refresh the cache again before the TTL expires
+	_, err = ss.getVMManagementTypeByProviderID(context.TODO(), providerID, azcache.CacheReadTypeDefault)
+	assert.Error(t, err)
+
+	ss.nonVmssUniformNodeNotFoundTimes.Store(providerID, time.Now().Add(-time.Minute))
+	assert.False(t, ss.isNonVmssUniformNodeNotFoundRecently(providerID))
+}
+
 func buildTestNICWithVMName(vmName string) *armnetwork.Interface {
 	return &armnetwork.Interface{
 		Name: &vmName,
@@ -460,3 +514,82 @@ func TestGetVMManagementTypeByIPConfigurationID(t *testing.T) {
 		})
 	}
 }
+
+func TestForEachResourceGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.CacheWarmupConcurrency = 2
+
+	var (
+		lock             sync.Mutex
+		running, maxSeen int
+		listed           []string
+	)
+	resourceGroups := []string{"rg1", "rg2", "rg3", "rg4", "rg5"}
+	err := az.forEachResourceGroup(context.Background(), resourceGroups, func(_ context.Context, resourceGroup string) error {
+		lock.Lock()
+		running++
+		maxSeen = max(maxSeen, running)
+		listed = append(listed, resourceGroup)
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, resourceGroups, listed)
+	assert.LessOrEqual(t, maxSeen, 2)
+
+	err = az.forEachResourceGroup(context.Background(), resourceGroups, func(_ context.Context, resourceGroup string) error {
+		if resourceGroup == "rg3" {
+			return errors.New("list failed")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "list failed")
+}
+
+func TestWarmUpCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vmList := []string{"vmssee6c2000000", "vmssee6c2000001"}
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+
+	expectedScaleSet := buildTestVMSS(testVMSSName, "vmssee6c2")
+	mockVMSSClient.EXPECT().List(gomock.Any(), "rg").Return([]*armcompute.VirtualMachineScaleSet{expectedScaleSet}, nil).Times(1)
+	expectedVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, vmList, "", false)
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), "rg", testVMSSName).Return(expectedVMs, nil).Times(1)
+	// the scale set of the node failed to be listed is skipped and listed again when it is read
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), "rg", "missing").Return(nil, errors.New("list failed")).Times(1)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, providerID := range map[string]string{
+		"vmssee6c2000000": "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+		"vmssee6c2000001": "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/1",
+		"missing000000":   "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/missing/virtualMachines/0",
+		"vm":              "azure:///subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm",
+	} {
+		assert.NoError(t, indexer.Add(&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{ProviderID: providerID},
+		}))
+	}
+	ss.nodeLister = corelisters.NewNodeLister(indexer)
+
+	ss.warmUpCaches(context.Background(), func() bool { return true })
+
+	// the VMs are read from the cache without listing them again
+	realVM, err := ss.getVmssVM(context.TODO(), "vmssee6c2000001", azcache.CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, testVMSSName, realVM.VMSSName)
+}
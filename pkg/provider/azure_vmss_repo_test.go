@@ -126,6 +126,30 @@ func TestGetVirtualMachineWithRetry(t *testing.T) {
 	}
 }
 
+func TestGetVirtualMachineNotFoundCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.VMNotFoundCacheTTLInSeconds = 60
+	var err error
+	az.vmCache, err = az.newVMCache()
+	assert.NoError(t, err)
+
+	mockVMClient := az.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	mockVMClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "vm", gomock.Any()).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err = az.getVirtualMachine(context.TODO(), "vm", cache.CacheReadTypeDefault)
+		assert.Equal(t, cloudprovider.InstanceNotFound, err)
+	}
+
+	// the not found result is removed when the node is added
+	az.deleteVMNotFoundCacheForNode("vm")
+	_, err = az.getVirtualMachine(context.TODO(), "vm", cache.CacheReadTypeDefault)
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+}
+
 func TestGetPrivateIPsForMachine(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1096,6 +1096,92 @@ func TestGetVmssVMByNodeIdentity(t *testing.T) {
 	}
 }
 
+func TestGetVmssVMByNodeIdentityNotFoundCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err, "unexpected error when creating test VMSS")
+	ss.VMNotFoundCacheTTLInSeconds = 60
+	ss.VMSet = ss
+
+	expectedVMSS := &armcompute.VirtualMachineScaleSet{
+		Name: ptr.To(testVMSSName),
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{},
+		},
+	}
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSClient.EXPECT().List(gomock.Any(), ss.ResourceGroup).Return([]*armcompute.VirtualMachineScaleSet{expectedVMSS}, nil).AnyTimes()
+
+	expectedVMSSVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, []string{"vmss-vm-000000"}, "", false)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	// the scale set is listed by the first lookup and refreshed by the lookups not finding the vm
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), ss.ResourceGroup, testVMSSName).Return(expectedVMSSVMs, nil).Times(3)
+
+	node := nodeIdentity{ss.ResourceGroup, testVMSSName, "vmss-vm-000001"}
+	for i := 0; i < 3; i++ {
+		_, err = ss.getVmssVMByNodeIdentity(context.TODO(), &node, azcache.CacheReadTypeDefault)
+		assert.Equal(t, cloudprovider.InstanceNotFound, err)
+	}
+
+	// the not found result is removed when the node is added
+	ss.deleteVMNotFoundCacheForNode("vmss-vm-000001")
+	_, err = ss.getVmssVMByNodeIdentity(context.TODO(), &node, azcache.CacheReadTypeDefault)
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+}
+
+func TestGetVmssVMByNodeIdentityDuringNodeScaleWave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err, "unexpected error when creating test VMSS")
+	ss.NodeScaleWaveThreshold = 1
+	ss.nodeScaleWaveTracker = newNodeScaleWaveTracker(ss.Cloud)
+	ss.nodeScaleWaveTracker.onNodeChange(true, time.Now())
+
+	expectedVMSS := &armcompute.VirtualMachineScaleSet{
+		Name: ptr.To(testVMSSName),
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{},
+		},
+	}
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSClient.EXPECT().List(gomock.Any(), ss.ResourceGroup).Return([]*armcompute.VirtualMachineScaleSet{expectedVMSS}, nil).AnyTimes()
+
+	expectedVMSSVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, []string{"vmss-vm-000000"}, "", false)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	// the lookups of the nodes during the wave share the listing of the scale set
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), ss.ResourceGroup, testVMSSName).Return(expectedVMSSVMs, nil).Times(1)
+
+	for _, nodeName := range []string{"vmss-vm-000001", "vmss-vm-000002", "vmss-vm-000003"} {
+		_, err = ss.getVmssVMByNodeIdentity(context.TODO(), &nodeIdentity{ss.ResourceGroup, testVMSSName, nodeName}, azcache.CacheReadTypeDefault)
+		// the VM may be created after the refresh, so the node is not reported as not found
+		assert.ErrorIs(t, err, errVMSSVMNotFoundDuringScaleWave)
+		assert.NotErrorIs(t, err, cloudprovider.InstanceNotFound)
+	}
+
+	// the cache is refreshed again out of the wave
+	ss.nodeScaleWaveTracker.inWave = false
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), ss.ResourceGroup, testVMSSName).Return(expectedVMSSVMs, nil).Times(1)
+	_, err = ss.getVmssVMByNodeIdentity(context.TODO(), &nodeIdentity{ss.ResourceGroup, testVMSSName, "vmss-vm-000001"}, azcache.CacheReadTypeDefault)
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+}
+
+func TestVMSSVMNotFoundTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err, "unexpected error when creating test VMSS")
+
+	assert.Equal(t, time.Duration(0), ss.vmssVMNotFoundTTL())
+
+	ss.VMNotFoundCacheTTLInSeconds = 60
+	assert.Equal(t, time.Minute, ss.vmssVMNotFoundTTL())
+
+	ss.VmssVirtualMachinesNotFoundCacheTTLInSeconds = 300
+	assert.Equal(t, 5*time.Minute, ss.vmssVMNotFoundTTL())
+}
+
 func TestGetInstanceTypeByNodeName(t *testing.T) {
 
 	testCases := []struct {
@@ -2203,7 +2289,7 @@ func TestDeleteBackendPoolFromIPConfig(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.desc, func(t *testing.T) {
-			found, err := deleteBackendPoolFromIPConfig("test", tc.backendPoolID, "test-resource", tc.primaryNIC)
+			found, err := (&Cloud{}).deleteBackendPoolFromIPConfig("test", tc.backendPoolID, "test-resource", tc.primaryNIC)
 			assert.Equal(t, tc.expectedFound, found)
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedPrimaryNIC, tc.primaryNIC)
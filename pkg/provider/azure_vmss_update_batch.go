@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+type vmssVMUpdateBatchContextKey struct{}
+
+// vmssVMUpdateBatch collects the backend pool additions of the VMs of each scale set made by the
+// reconciliation of the backend pools of a service, e.g. the IPv4 and IPv6 backend pools or the
+// backend pools of multiple standard load balancers, so each VM is updated once with all of them.
+type vmssVMUpdateBatch struct {
+	lock      sync.Mutex
+	ss        *ScaleSet
+	updates   map[vmssMetaInfo]map[string]armcompute.VirtualMachineScaleSetVM
+	nodeNames map[vmssMetaInfo][]string
+}
+
+// withVMSSVMUpdateBatch returns a context collecting the VM updates of the scale sets into the returned batch
+// instead of updating the VMs right away. The VMs are updated by flush.
+func withVMSSVMUpdateBatch(ctx context.Context) (context.Context, *vmssVMUpdateBatch) {
+	batch := &vmssVMUpdateBatch{
+		updates:   make(map[vmssMetaInfo]map[string]armcompute.VirtualMachineScaleSetVM),
+		nodeNames: make(map[vmssMetaInfo][]string),
+	}
+	return context.WithValue(ctx, vmssVMUpdateBatchContextKey{}, batch), batch
+}
+
+// vmssVMUpdateBatchFromContext returns the batch of the context, or nil if the VMs are updated right away.
+func vmssVMUpdateBatchFromContext(ctx context.Context) *vmssVMUpdateBatch {
+	batch, _ := ctx.Value(vmssVMUpdateBatchContextKey{}).(*vmssVMUpdateBatch)
+	return batch
+}
+
+// add merges the VM updates of the scale set into the batch. The backend pools of an update are
+// added to the ones of the pending update of the same VM.
+func (b *vmssVMUpdateBatch) add(ss *ScaleSet, meta vmssMetaInfo, updates map[string]armcompute.VirtualMachineScaleSetVM, nodeNames []string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.ss = ss
+	pending, ok := b.updates[meta]
+	if !ok {
+		pending = make(map[string]armcompute.VirtualMachineScaleSetVM)
+		b.updates[meta] = pending
+	}
+	for instanceID, vm := range updates {
+		if pendingVM, ok := pending[instanceID]; ok {
+			mergeVMSSVMBackendPools(&vm, &pendingVM)
+		}
+		pending[instanceID] = vm
+	}
+	b.nodeNames[meta] = append(b.nodeNames[meta], nodeNames...)
+}
+
+// flush updates the VMs of the batch, the scale sets are updated concurrently.
+func (b *vmssVMUpdateBatch) flush(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	ss, updates, nodeNames := b.ss, b.updates, b.nodeNames
+	b.updates = make(map[vmssMetaInfo]map[string]armcompute.VirtualMachineScaleSetVM)
+	b.nodeNames = make(map[vmssMetaInfo][]string)
+	b.lock.Unlock()
+
+	hostUpdates := make([]func() error, 0, len(updates))
+	for meta, update := range updates {
+		meta := meta
+		update := update
+		hostUpdates = append(hostUpdates, func() error {
+			// Invalidate the cache since the VMSS VMs are updated, the VMs may have been read
+			// again by the backend pools reconciled after the ones updating them.
+			defer func() {
+				for _, nodeName := range nodeNames[meta] {
+					_ = ss.DeleteCacheForNode(ctx, nodeName)
+				}
+			}()
+			return ss.updateVMSSVMs(ctx, meta, update)
+		})
+	}
+	if errs := utilerrors.AggregateGoroutines(hostUpdates...); errs != nil {
+		return utilerrors.Flatten(errs)
+	}
+	return nil
+}
+
+// updateVMSSVMs updates the VMs of the scale set. The VMs are updated in flushes of at most
+// PutVMSSVMFlushMaxInstances VMs, PutVMSSVMFlushIntervalInSeconds apart, so the updates of a
+// large scale event are spread out instead of being throttled by ARM. The requests of a flush
+// are sent with the batch size of the scale set.
+func (ss *ScaleSet) updateVMSSVMs(ctx context.Context, meta vmssMetaInfo, update map[string]armcompute.VirtualMachineScaleSetVM) error {
+	logger := klog.FromContext(ctx)
+	batchSize, err := ss.VMSSBatchSize(ctx, meta.vmssName)
+	if err != nil {
+		logger.Error(err, "Failed to get vmss batch size")
+		return err
+	}
+
+	instanceIDs := make([]string, 0, len(update))
+	for instanceID := range update {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	sort.Strings(instanceIDs)
+	flushSize := ss.PutVMSSVMFlushMaxInstances
+	if flushSize <= 0 {
+		flushSize = len(instanceIDs)
+	}
+	flushInterval := time.Duration(ss.PutVMSSVMFlushIntervalInSeconds) * time.Second
+
+	errs := make([]error, 0)
+	for start := 0; start < len(instanceIDs); start += flushSize {
+		if start > 0 && flushInterval > 0 {
+			select {
+			case <-time.After(flushInterval):
+			case <-ctx.Done():
+				return utilerrors.NewAggregate(append(errs, ctx.Err()))
+			}
+		}
+		end := min(start+flushSize, len(instanceIDs))
+		flush := make(map[string]armcompute.VirtualMachineScaleSetVM, end-start)
+		for _, instanceID := range instanceIDs[start:end] {
+			flush[instanceID] = update[instanceID]
+		}
+		logger.V(2).Info("Updating VMs of the scale set", "vmssName", meta.vmssName, "resourceGroup", meta.resourceGroup, "count", len(flush), "remaining", len(instanceIDs)-end)
+		for err := range ss.UpdateVMSSVMsInBatch(ctx, meta, flush, batchSize) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// mergeVMSSVMBackendPools adds the backend pools of the IP configurations of the pending VM
+// to the matching IP configurations of the VM.
+func mergeVMSSVMBackendPools(vm, pendingVM *armcompute.VirtualMachineScaleSetVM) {
+	if vm.Properties == nil || vm.Properties.NetworkProfileConfiguration == nil ||
+		pendingVM.Properties == nil || pendingVM.Properties.NetworkProfileConfiguration == nil {
+		return
+	}
+
+	pendingIPConfigs := make(map[string]*armcompute.VirtualMachineScaleSetIPConfiguration)
+	for _, nic := range pendingVM.Properties.NetworkProfileConfiguration.NetworkInterfaceConfigurations {
+		if nic == nil || nic.Properties == nil {
+			continue
+		}
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			if ipConfig == nil {
+				continue
+			}
+			pendingIPConfigs[strings.ToLower(ptr.Deref(nic.Name, ""))+"/"+strings.ToLower(ptr.Deref(ipConfig.Name, ""))] = ipConfig
+		}
+	}
+
+	for _, nic := range vm.Properties.NetworkProfileConfiguration.NetworkInterfaceConfigurations {
+		if nic == nil || nic.Properties == nil {
+			continue
+		}
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.Properties == nil {
+				continue
+			}
+			pendingIPConfig, ok := pendingIPConfigs[strings.ToLower(ptr.Deref(nic.Name, ""))+"/"+strings.ToLower(ptr.Deref(ipConfig.Name, ""))]
+			if !ok || pendingIPConfig.Properties == nil {
+				continue
+			}
+			for _, pool := range pendingIPConfig.Properties.LoadBalancerBackendAddressPools {
+				if pool == nil || pool.ID == nil {
+					continue
+				}
+				found := false
+				for _, existingPool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+					if existingPool != nil && strings.EqualFold(ptr.Deref(existingPool.ID, ""), *pool.ID) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					ipConfig.Properties.LoadBalancerBackendAddressPools = append(ipConfig.Properties.LoadBalancerBackendAddressPools, pool)
+				}
+			}
+		}
+	}
+}
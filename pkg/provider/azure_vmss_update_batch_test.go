@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetclient/mock_virtualmachinescalesetclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachinescalesetvmclient/mock_virtualmachinescalesetvmclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func buildTestVMSSVMWithBackendPools(poolIDs ...string) armcompute.VirtualMachineScaleSetVM {
+	pools := make([]*armcompute.SubResource, 0, len(poolIDs))
+	for _, id := range poolIDs {
+		pools = append(pools, &armcompute.SubResource{ID: ptr.To(id)})
+	}
+	return armcompute.VirtualMachineScaleSetVM{
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			NetworkProfileConfiguration: &armcompute.VirtualMachineScaleSetVMNetworkProfileConfiguration{
+				NetworkInterfaceConfigurations: []*armcompute.VirtualMachineScaleSetNetworkConfiguration{
+					{
+						Name: ptr.To("nic"),
+						Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+							IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
+								{
+									Name: ptr.To("ipconfig"),
+									Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+										LoadBalancerBackendAddressPools: pools,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTestVMSSVMBackendPoolIDs(vm armcompute.VirtualMachineScaleSetVM) []string {
+	var ids []string
+	for _, nic := range vm.Properties.NetworkProfileConfiguration.NetworkInterfaceConfigurations {
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			for _, pool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+				ids = append(ids, *pool.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func TestMergeVMSSVMBackendPools(t *testing.T) {
+	vm := buildTestVMSSVMWithBackendPools("outbound", "pool2")
+	pendingVM := buildTestVMSSVMWithBackendPools("outbound", "pool1")
+
+	mergeVMSSVMBackendPools(&vm, &pendingVM)
+	assert.Equal(t, []string{"outbound", "pool2", "pool1"}, getTestVMSSVMBackendPoolIDs(vm))
+}
+
+func TestVMSSVMUpdateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	ss.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+
+	expectedVMSS := buildTestVMSSWithLB(testVMSSName, "vmss-vm-", []string{testLBBackendpoolID0}, false)
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSClient.EXPECT().List(gomock.Any(), ss.ResourceGroup).Return([]*armcompute.VirtualMachineScaleSet{expectedVMSS}, nil).AnyTimes()
+	mockVMSSClient.EXPECT().Get(gomock.Any(), ss.ResourceGroup, testVMSSName, nil).Return(expectedVMSS, nil).AnyTimes()
+	mockVMSSClient.EXPECT().CreateOrUpdate(gomock.Any(), ss.ResourceGroup, testVMSSName, gomock.Any()).Return(nil, nil).AnyTimes()
+	mockVMClient := ss.ComputeClientFactory.GetVirtualMachineClient().(*mock_virtualmachineclient.MockInterface)
+	mockVMClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	nodeNames := []string{"vmss-vm-000000", "vmss-vm-000001"}
+	expectedVMSSVMs, _, _ := buildTestVirtualMachineEnv(ss.Cloud, testVMSSName, "", 0, nodeNames, "", false)
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+	mockVMSSVMClient.EXPECT().ListVMInstanceView(gomock.Any(), ss.ResourceGroup, testVMSSName).Return(expectedVMSSVMs, nil).AnyTimes()
+
+	var (
+		lock    sync.Mutex
+		updated = map[string]armcompute.VirtualMachineScaleSetVM{}
+	)
+	mockVMSSVMClient.EXPECT().BeginUpdate(gomock.Any(), ss.ResourceGroup, testVMSSName, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _, instanceID string, vm armcompute.VirtualMachineScaleSetVM, _ *armcompute.VirtualMachineScaleSetVMsClientBeginUpdateOptions) (*runtime.Poller[armcompute.VirtualMachineScaleSetVMsClientUpdateResponse], error) {
+			lock.Lock()
+			defer lock.Unlock()
+			updated[instanceID] = vm
+			return nil, nil
+		}).Times(len(nodeNames))
+
+	nodes := make([]*v1.Node, 0, len(nodeNames))
+	for i, nodeName := range nodeNames {
+		nodes = append(nodes, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: v1.NodeSpec{
+				ProviderID: fmt.Sprintf("azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/%d", i),
+			},
+		})
+	}
+
+	ctx, batch := withVMSSVMUpdateBatch(context.Background())
+	for _, backendPoolID := range []string{testLBBackendpoolID1, testLBBackendpoolID2} {
+		assert.NoError(t, ss.EnsureHostsInPool(ctx, &v1.Service{}, nodes, backendPoolID, testVMSSName))
+	}
+	// the VMs are not updated until the batch is flushed
+	assert.Empty(t, updated)
+
+	assert.NoError(t, batch.flush(ctx))
+	assert.Len(t, updated, len(nodeNames))
+	for _, vm := range updated {
+		assert.Subset(t, getTestVMSSVMBackendPoolIDs(vm), []string{testLBBackendpoolID1, testLBBackendpoolID2})
+	}
+
+	// flushing the empty batch again updates nothing
+	assert.NoError(t, batch.flush(ctx))
+	var nilBatch *vmssVMUpdateBatch
+	assert.NoError(t, nilBatch.flush(ctx))
+}
+
+func TestUpdateVMSSVMsInFlushes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss, err := NewTestScaleSet(ctrl)
+	assert.NoError(t, err)
+	expectedVMSS := buildTestVMSSWithLB(testVMSSName, "vmss-vm-", []string{testLBBackendpoolID0}, false)
+	mockVMSSClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetClient().(*mock_virtualmachinescalesetclient.MockInterface)
+	mockVMSSClient.EXPECT().List(gomock.Any(), ss.ResourceGroup).Return([]*armcompute.VirtualMachineScaleSet{expectedVMSS}, nil).AnyTimes()
+
+	update := map[string]armcompute.VirtualMachineScaleSetVM{
+		"0": buildTestVMSSVMWithBackendPools(testLBBackendpoolID1),
+		"1": buildTestVMSSVMWithBackendPools(testLBBackendpoolID1),
+		"2": buildTestVMSSVMWithBackendPools(testLBBackendpoolID1),
+	}
+	meta := vmssMetaInfo{vmssName: testVMSSName, resourceGroup: ss.ResourceGroup}
+	mockVMSSVMClient := ss.ComputeClientFactory.GetVirtualMachineScaleSetVMClient().(*mock_virtualmachinescalesetvmclient.MockInterface)
+
+	t.Run("should update the VMs in flushes of the max instances", func(t *testing.T) {
+		ss.PutVMSSVMFlushMaxInstances = 2
+		ss.PutVMSSVMFlushIntervalInSeconds = 0
+		mockVMSSVMClient.EXPECT().BeginUpdate(gomock.Any(), ss.ResourceGroup, testVMSSName, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+		assert.NoError(t, ss.updateVMSSVMs(context.Background(), meta, update))
+	})
+
+	t.Run("should stop waiting for the next flush when the context is cancelled", func(t *testing.T) {
+		ss.PutVMSSVMFlushMaxInstances = 2
+		ss.PutVMSSVMFlushIntervalInSeconds = 3600
+		mockVMSSVMClient.EXPECT().BeginUpdate(gomock.Any(), ss.ResourceGroup, testVMSSName, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ss.updateVMSSVMs(ctx, meta, update)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
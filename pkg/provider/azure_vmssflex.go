@@ -245,6 +245,9 @@ func (fs *FlexScaleSet) GetZoneByNodeName(ctx context.Context, name string) (clo
 	} else if vm.Properties.InstanceView != nil && vm.Properties.InstanceView.PlatformFaultDomain != nil {
 		// Availability zone is not used for the node, falling back to fault domain.
 		failureDomain = strconv.Itoa(int(ptr.Deref(vm.Properties.InstanceView.PlatformFaultDomain, 0)))
+	} else if vm.Properties.PlatformFaultDomain != nil {
+		// The instance view may be missing if it failed to be listed, use the fault domain in the VM properties.
+		failureDomain = strconv.Itoa(int(ptr.Deref(vm.Properties.PlatformFaultDomain, 0)))
 	} else {
 		err = fmt.Errorf("failed to get zone info")
 		klog.Errorf("GetZoneByNodeName: got unexpected error %v", err)
@@ -290,13 +293,18 @@ func (fs *FlexScaleSet) GetPowerStatusByNodeName(ctx context.Context, name strin
 
 // GetPrimaryInterface gets machine primary network interface by node name.
 func (fs *FlexScaleSet) GetPrimaryInterface(ctx context.Context, nodeName string) (*armnetwork.Interface, error) {
+	return fs.getInterface(ctx, nodeName, getPrimaryInterfaceID)
+}
+
+// getInterface gets the machine network interface selected by getInterfaceID by node name.
+func (fs *FlexScaleSet) getInterface(ctx context.Context, nodeName string, getInterfaceID func(*armcompute.VirtualMachine) (string, error)) (*armnetwork.Interface, error) {
 	machine, err := fs.getVmssFlexVM(ctx, nodeName, azcache.CacheReadTypeDefault)
 	if err != nil {
 		klog.Errorf("fs.GetInstanceTypeByNodeName(%s) failed: fs.getVmssFlexVMWithoutInstanceView(%s) err=%v", nodeName, nodeName, err)
 		return nil, err
 	}
 
-	primaryNicID, err := getPrimaryInterfaceID(machine)
+	primaryNicID, err := getInterfaceID(machine)
 	if err != nil {
 		return nil, err
 	}
@@ -318,6 +326,18 @@ func (fs *FlexScaleSet) GetPrimaryInterface(ctx context.Context, nodeName string
 	return nic, nil
 }
 
+// GetInterfacesByNodeName gets all network interfaces of the machine by node name, the primary one first.
+func (fs *FlexScaleSet) GetInterfacesByNodeName(ctx context.Context, nodeName string) ([]*armnetwork.Interface, error) {
+	machine, err := fs.getVmssFlexVM(ctx, nodeName, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, err
+	}
+	if machine.Properties == nil {
+		return nil, nil
+	}
+	return fs.getInterfacesByIDs(ctx, getInterfaceIDs(machine.Properties.NetworkProfile))
+}
+
 // GetIPByNodeName gets machine private IP and public IP by node name.
 func (fs *FlexScaleSet) GetIPByNodeName(ctx context.Context, name string) (string, string, error) {
 	nic, err := fs.GetPrimaryInterface(ctx, name)
@@ -470,7 +490,7 @@ func (fs *FlexScaleSet) EnsureHostInPool(ctx context.Context, service *v1.Servic
 		return "", "", "", nil, errNotInVMSet
 	}
 
-	nic, err := fs.GetPrimaryInterface(ctx, name)
+	nic, err := fs.getInterface(ctx, name, fs.getBackendPoolInterfaceID)
 	if err != nil {
 		klog.Errorf("error: fs.EnsureHostInPool(%s), s.GetPrimaryInterface(%s), vmSetNameOfLB: %s, err=%v", name, name, vmSetNameOfLB, err)
 		return "", "", "", nil, err
@@ -481,18 +501,9 @@ func (fs *FlexScaleSet) EnsureHostInPool(ctx context.Context, service *v1.Servic
 		return "", "", "", nil, nil
 	}
 
-	var primaryIPConfig *armnetwork.InterfaceIPConfiguration
-	ipv6 := isBackendPoolIPv6(backendPoolID)
-	if !fs.Cloud.ipv6DualStackEnabled && !ipv6 {
-		primaryIPConfig, err = getPrimaryIPConfig(nic)
-		if err != nil {
-			return "", "", "", nil, err
-		}
-	} else {
-		primaryIPConfig, err = getIPConfigByIPFamily(nic, ipv6)
-		if err != nil {
-			return "", "", "", nil, err
-		}
+	primaryIPConfig, err := fs.getBackendPoolIPConfig(nic, isBackendPoolIPv6(backendPoolID))
+	if err != nil {
+		return "", "", "", nil, err
 	}
 
 	foundPool := false
@@ -506,8 +517,14 @@ func (fs *FlexScaleSet) EnsureHostInPool(ctx context.Context, service *v1.Servic
 			break
 		}
 	}
+	getPrimaryInterface := func() (*armnetwork.Interface, error) {
+		return fs.getInterface(ctx, name, getPrimaryInterfaceID)
+	}
 	// The backendPoolID has already been found from existing LoadBalancerBackendAddressPools.
 	if foundPool {
+		if err := fs.removeBackendPoolFromPrimaryInterface(ctx, service, nic, backendPoolID, getPrimaryInterface); err != nil {
+			return "", "", "", nil, err
+		}
 		return "", "", "", nil, nil
 	}
 
@@ -545,6 +562,9 @@ func (fs *FlexScaleSet) EnsureHostInPool(ctx context.Context, service *v1.Servic
 	if err != nil {
 		return "", "", "", nil, err
 	}
+	if err := fs.removeBackendPoolFromPrimaryInterface(ctx, service, nic, backendPoolID, getPrimaryInterface); err != nil {
+		return "", "", "", nil, err
+	}
 
 	// Get the node resource group.
 	nodeResourceGroup, err := fs.GetNodeResourceGroup(name)
@@ -627,11 +647,11 @@ func (fs *FlexScaleSet) ensureVMSSFlexInPool(ctx context.Context, _ *v1.Service,
 			continue
 		}
 		vmssNIC := vmssFlex.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-		primaryNIC, err := getPrimaryNetworkInterfaceConfiguration(vmssNIC, vmssFlexName)
+		primaryNIC, err := fs.getBackendPoolNetworkInterfaceConfiguration(vmssNIC, vmssFlexName)
 		if err != nil {
 			return err
 		}
-		primaryIPConfig, err := getPrimaryIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, vmssFlexName)
+		primaryIPConfig, err := fs.getBackendPoolIPConfigFromVMSSNetworkConfig(primaryNIC, backendPoolID, vmssFlexName)
 		if err != nil {
 			return err
 		}
@@ -648,11 +668,7 @@ func (fs *FlexScaleSet) ensureVMSSFlexInPool(ctx context.Context, _ *v1.Service,
 				break
 			}
 		}
-		if found {
-			continue
-		}
-
-		if fs.UseStandardLoadBalancer() && len(loadBalancerBackendAddressPools) > 0 {
+		if !found && fs.UseStandardLoadBalancer() && len(loadBalancerBackendAddressPools) > 0 {
 			// Although standard load balancer supports backends from multiple scale
 			// sets, the same network interface couldn't be added to more than one load balancer of
 			// the same type. Omit those nodes (e.g. masters) so Azure ARM won't complain
@@ -673,12 +689,19 @@ func (fs *FlexScaleSet) ensureVMSSFlexInPool(ctx context.Context, _ *v1.Service,
 			}
 		}
 
+		removedFromOthers := fs.removeBackendPoolFromOtherNetworkConfigurations(vmssNIC, primaryNIC, backendPoolID)
+		if found && !removedFromOthers {
+			continue
+		}
+
 		// Compose a new vmss with added backendPoolID.
-		loadBalancerBackendAddressPools = append(loadBalancerBackendAddressPools,
-			&armcompute.SubResource{
-				ID: ptr.To(backendPoolID),
-			})
-		primaryIPConfig.Properties.LoadBalancerBackendAddressPools = loadBalancerBackendAddressPools
+		if !found {
+			loadBalancerBackendAddressPools = append(loadBalancerBackendAddressPools,
+				&armcompute.SubResource{
+					ID: ptr.To(backendPoolID),
+				})
+			primaryIPConfig.Properties.LoadBalancerBackendAddressPools = loadBalancerBackendAddressPools
+		}
 		newVMSS := armcompute.VirtualMachineScaleSet{
 			Location: vmssFlex.Location,
 			Properties: &armcompute.VirtualMachineScaleSetProperties{
@@ -800,7 +823,7 @@ func (fs *FlexScaleSet) EnsureBackendPoolDeletedFromVMSets(ctx context.Context,
 			continue
 		}
 		vmssNIC := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-		primaryNIC, err := getPrimaryNetworkInterfaceConfiguration(vmssNIC, vmssName)
+		primaryNIC, err := fs.getBackendPoolNetworkInterfaceConfiguration(vmssNIC, vmssName)
 		if err != nil {
 			klog.Errorf("fs.EnsureBackendPoolDeletedFromVMSets: failed to get the primary network interface config of the VMSS %s: %v", vmssName, err)
 			errors = append(errors, err)
@@ -808,7 +831,7 @@ func (fs *FlexScaleSet) EnsureBackendPoolDeletedFromVMSets(ctx context.Context,
 		}
 		foundTotal := false
 		for _, backendPoolID := range backendPoolIDs {
-			found, err := deleteBackendPoolFromIPConfig("FlexSet.EnsureBackendPoolDeletedFromVMSets", backendPoolID, vmssName, primaryNIC)
+			found, err := fs.deleteBackendPoolFromIPConfig("FlexSet.EnsureBackendPoolDeletedFromVMSets", backendPoolID, vmssName, primaryNIC)
 			if err != nil {
 				errors = append(errors, err)
 				continue
@@ -973,10 +996,10 @@ func (fs *FlexScaleSet) ensureBackendPoolDeletedFromNode(ctx context.Context, vm
 		nic := nic
 		newIPConfigs := nic.Properties.IPConfigurations
 		for j, ipConf := range newIPConfigs {
-			if !ptr.Deref(ipConf.Properties.Primary, false) {
+			if !fs.isBackendPoolIPConfig(nic, ipConf) {
 				continue
 			}
-			// found primary ip configuration
+			// found the ip configuration joining the backend pools
 			if ipConf.Properties.LoadBalancerBackendAddressPools != nil {
 				newLBAddressPools := ipConf.Properties.LoadBalancerBackendAddressPools
 				for k := len(newLBAddressPools) - 1; k >= 0; k-- {
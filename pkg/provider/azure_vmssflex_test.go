@@ -455,6 +455,20 @@ func TestGetZoneByNodeNameVmssFlex(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			description: "GetZoneByNodeName should return the fault domain in the vm properties if the instance view is nil",
+			nodeName:    "vmssflex1000002",
+			testVMListWithoutInstanceView: func() []*armcompute.VirtualMachine {
+				vm := generateVmssFlexTestVMWithoutInstanceView(testVM2Spec)
+				vm.Properties.PlatformFaultDomain = ptr.To(int32(2))
+				return []*armcompute.VirtualMachine{vm}
+			}(),
+			testVMListWithOnlyInstanceView: []*armcompute.VirtualMachine{},
+			expectedZone: cloudprovider.Zone{
+				FailureDomain: "2",
+				Region:        "eastus",
+			},
+		},
 		{
 			description:                    "GetZoneByNodeName should return the error if both zone and fault domain are nil",
 			nodeName:                       "vmssflex1000003",
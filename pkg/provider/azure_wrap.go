@@ -33,8 +33,9 @@ var (
 	loadBalancerCacheTTLDefaultInSeconds = 120
 	publicIPCacheTTLDefaultInSeconds     = 120
 
-	azureNodeProviderIDRE    = regexp.MustCompile(`^azure:///subscriptions/(?:.*)/resourceGroups/(?:.*)/providers/Microsoft.Compute/(?:.*)`)
-	azureResourceGroupNameRE = regexp.MustCompile(`.*/subscriptions/(?:.*)/resourceGroups/(.+)/providers/(?:.*)`)
+	azureNodeProviderIDRE              = regexp.MustCompile(`^azure:///subscriptions/(?:.*)/resourceGroups/(?:.*)/providers/Microsoft.Compute/(?:.*)`)
+	azureResourceGroupNameRE           = regexp.MustCompile(`.*/subscriptions/(?:.*)/resourceGroups/(.+)/providers/(?:.*)`)
+	azureNodeProviderIDResourceGroupRE = regexp.MustCompile(`(?i)^azure:///subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/Microsoft.Compute/`)
 )
 
 // checkExistsFromError inspects an error and returns a true if err is nil,
@@ -72,6 +73,16 @@ func (az *Cloud) IsNodeUnmanagedByProviderID(providerID string) bool {
 	return !azureNodeProviderIDRE.Match([]byte(providerID))
 }
 
+// getSubscriptionAndResourceGroupFromProviderID returns the subscription and the resource group
+// in the provider ID of a managed node.
+func getSubscriptionAndResourceGroupFromProviderID(providerID string) (string, string, bool) {
+	matches := azureNodeProviderIDResourceGroupRE.FindStringSubmatch(providerID)
+	if len(matches) != 3 {
+		return "", "", false
+	}
+	return matches[1], matches[2], true
+}
+
 // ConvertResourceGroupNameToLower converts the resource group name in the resource ID to be lowered.
 func ConvertResourceGroupNameToLower(resourceID string) (string, error) {
 	matches := azureResourceGroupNameRE.FindStringSubmatch(resourceID)
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// getServiceZone returns the availability zone the service is pinned to by the azure-load-balancer-zone
+// annotation, or by the azure-pip-zones annotation of an external service naming a single zone. It
+// returns an empty string if the service is not pinned to a zone.
+func getServiceZone(service *v1.Service) string {
+	if zone := strings.TrimSpace(service.Annotations[consts.ServiceAnnotationLoadBalancerZone]); zone != "" {
+		return zone
+	}
+	if requiresInternalLoadBalancer(service) {
+		return ""
+	}
+
+	var pipZone string
+	for _, zone := range strings.Split(service.Annotations[consts.ServiceAnnotationAzurePIPZones], ",") {
+		zone = strings.TrimSpace(zone)
+		switch {
+		case zone == "" || zone == pipZone:
+			continue
+		case pipZone != "" || strings.EqualFold(zone, consts.PIPZonesZoneRedundant) || strings.EqualFold(zone, consts.PIPZonesNoZone):
+			return ""
+		}
+		pipZone = zone
+	}
+	return pipZone
+}
+
+// useZonalServiceBackendPool returns true if the service is pinned to a zone and gets a backend pool
+// of its own, which only contains the nodes in the zone.
+func (az *Cloud) useZonalServiceBackendPool(service *v1.Service) bool {
+	if !az.UseStandardLoadBalancer() || !az.IsLBBackendPoolTypeNodeIP() {
+		return false
+	}
+	if az.UseLocalServiceBackendPools() && isLocalService(service) {
+		return false
+	}
+	return getServiceZone(service) != ""
+}
+
+// useServiceBackendPool returns true if the service has a backend pool of its own instead of the
+// backend pool of the cluster, either of a local service or of a service pinned to a zone.
+func (az *Cloud) useServiceBackendPool(service *v1.Service) bool {
+	return az.UseLocalServiceBackendPools() && isLocalService(service) || az.useZonalServiceBackendPool(service)
+}
+
+// filterNodesByZone returns the nodes in the availability zone of the region according to their
+// topology labels.
+func (az *Cloud) filterNodesByZone(nodes []*v1.Node, zone string) []*v1.Node {
+	var filtered []*v1.Node
+	for _, node := range nodes {
+		zoneLabel, found := node.Labels[v1.LabelTopologyZone]
+		if !found {
+			zoneLabel = node.Labels[v1.LabelFailureDomainBetaZone]
+		}
+		if strings.EqualFold(az.GetZoneID(strings.ToLower(zoneLabel)), zone) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/backendaddresspoolclient/mock_backendaddresspoolclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+func TestGetServiceZone(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			desc: "service without zone annotations",
+		},
+		{
+			desc:        "service pinned by the annotation",
+			annotations: map[string]string{consts.ServiceAnnotationLoadBalancerZone: "2", consts.ServiceAnnotationAzurePIPZones: "1"},
+			expected:    "2",
+		},
+		{
+			desc:        "service with a public IP in a single zone",
+			annotations: map[string]string{consts.ServiceAnnotationAzurePIPZones: " 1, 1"},
+			expected:    "1",
+		},
+		{
+			desc:        "service with a public IP in multiple zones",
+			annotations: map[string]string{consts.ServiceAnnotationAzurePIPZones: "1,2"},
+		},
+		{
+			desc:        "service with a zone-redundant public IP",
+			annotations: map[string]string{consts.ServiceAnnotationAzurePIPZones: consts.PIPZonesZoneRedundant},
+		},
+		{
+			desc:        "service with a public IP without zones",
+			annotations: map[string]string{consts.ServiceAnnotationAzurePIPZones: consts.PIPZonesNoZone},
+		},
+		{
+			desc: "internal service with the public IP zones annotation",
+			annotations: map[string]string{
+				consts.ServiceAnnotationLoadBalancerInternal: consts.TrueAnnotationValue,
+				consts.ServiceAnnotationAzurePIPZones:        "1",
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			assert.Equal(t, tc.expected, getServiceZone(service))
+		})
+	}
+}
+
+func TestGetBackendPoolNameForZonalService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	service := getTestService("svc-1", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationLoadBalancerZone: "1"}, false, 80)
+
+	// the NIC-based backend pools are shared by all the services
+	assert.Equal(t, "kubernetes", az.getBackendPoolNameForService(&service, "kubernetes", false))
+
+	az.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIP
+	assert.Equal(t, "default-svc-1", az.getBackendPoolNameForService(&service, "kubernetes", false))
+	assert.Equal(t, "default-svc-1-ipv6", az.getBackendPoolNameForService(&service, "kubernetes", true))
+
+	delete(service.Annotations, consts.ServiceAnnotationLoadBalancerZone)
+	assert.Equal(t, "kubernetes", az.getBackendPoolNameForService(&service, "kubernetes", false))
+}
+
+func TestEnsureHostsInPoolNodeIPZonalService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newNode := func(name, ip string, labels map[string]string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: ip}}},
+		}
+	}
+	nodes := []*v1.Node{
+		newNode("node-1", "10.0.0.1", map[string]string{v1.LabelTopologyZone: "westus-1"}),
+		newNode("node-2", "10.0.0.2", map[string]string{v1.LabelTopologyZone: "westus-2"}),
+		newNode("node-3", "10.0.0.3", map[string]string{v1.LabelFailureDomainBetaZone: "westus-1"}),
+		newNode("node-4", "10.0.0.4", map[string]string{v1.LabelTopologyZone: "0"}),
+	}
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIP
+	bi := newBackendPoolTypeNodeIP(az)
+
+	backendPool := &armnetwork.BackendAddressPool{
+		Name: ptr.To("default-svc-1"),
+		Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+			LoadBalancerBackendAddresses: []*armnetwork.LoadBalancerBackendAddress{
+				{Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{IPAddress: ptr.To("10.0.0.1")}},
+				{Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{IPAddress: ptr.To("10.0.0.2")}},
+			},
+		},
+	}
+	backendpoolClient := az.NetworkClientFactory.GetBackendAddressPoolClient().(*mock_backendaddresspoolclient.MockInterface)
+	backendpoolClient.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), "kubernetes", "default-svc-1", gomock.Any()).Return(nil, nil)
+
+	service := getTestService("svc-1", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationAzurePIPZones: "1"}, false, 80)
+	err := bi.EnsureHostsInPool(context.Background(), &service, nodes, "", "", "kubernetes", "kubernetes", backendPool)
+	assert.NoError(t, err)
+
+	var ips []string
+	for _, address := range backendPool.Properties.LoadBalancerBackendAddresses {
+		ips = append(ips, ptr.Deref(address.Properties.IPAddress, ""))
+	}
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.3"}, ips)
+}
+
+func TestDeleteServiceBackendPoolsZonalService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	az := GetTestCloud(ctrl)
+	az.LoadBalancerSKU = consts.LoadBalancerSKUStandard
+	az.LoadBalancerBackendPoolConfigurationType = consts.LoadBalancerBackendPoolConfigurationTypeNodeIP
+	lb := &armnetwork.LoadBalancer{
+		Name: ptr.To("kubernetes"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{{Name: ptr.To("other")}},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{Name: ptr.To("kubernetes")},
+				{Name: ptr.To("default-svc-1")},
+				{Name: ptr.To("default-svc-1-ipv6")},
+				{Name: ptr.To("default-svc-2")},
+			},
+		},
+	}
+	backendpoolClient := az.NetworkClientFactory.GetBackendAddressPoolClient().(*mock_backendaddresspoolclient.MockInterface)
+	backendpoolClient.EXPECT().Delete(gomock.Any(), gomock.Any(), "kubernetes", "default-svc-1").Return(nil)
+	backendpoolClient.EXPECT().Delete(gomock.Any(), gomock.Any(), "kubernetes", "default-svc-1-ipv6").Return(nil)
+
+	service := getTestService("svc-1", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationAzurePIPZones: "1"}, false, 80)
+	assert.NoError(t, az.deleteServiceBackendPools(context.Background(), &service, lb))
+
+	// the backend pools of the other services are kept
+	service = getTestService("svc-2", v1.ProtocolTCP, nil, false, 80)
+	assert.NoError(t, az.deleteServiceBackendPools(context.Background(), &service, lb))
+
+	// the load balancer without frontends is deleted with its backend pools
+	lb.Properties.FrontendIPConfigurations = nil
+	service = getTestService("svc-1", v1.ProtocolTCP, map[string]string{consts.ServiceAnnotationAzurePIPZones: "1"}, false, 80)
+	assert.NoError(t, az.deleteServiceBackendPools(context.Background(), &service, lb))
+}
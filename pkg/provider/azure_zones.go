@@ -24,10 +24,15 @@ import (
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
@@ -120,6 +125,74 @@ func (az *Cloud) getRegionZonesBackoff(ctx context.Context, region string) ([]*s
 	return []*string{}, nil
 }
 
+// getServicePIPZones returns the zones of the public IP to be created for the service in the
+// location according to the azure-pip-zones annotation. It returns nil for a public IP without zones.
+func (az *Cloud) getServicePIPZones(ctx context.Context, service *v1.Service, location string) ([]*string, error) {
+	regionZones, err := az.getRegionZonesBackoff(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	value := strings.TrimSpace(service.Annotations[consts.ServiceAnnotationAzurePIPZones])
+	switch {
+	case value == "" || strings.EqualFold(value, consts.PIPZonesZoneRedundant):
+		if len(regionZones) == 0 {
+			if value != "" {
+				return nil, fmt.Errorf("annotation %s=%s is invalid: region %s does not support availability zones", consts.ServiceAnnotationAzurePIPZones, value, location)
+			}
+			return nil, nil
+		}
+		return regionZones, nil
+	case strings.EqualFold(value, consts.PIPZonesNoZone):
+		return nil, nil
+	}
+
+	supported := sets.New[string]()
+	for _, zone := range regionZones {
+		supported.Insert(ptr.Deref(zone, ""))
+	}
+	var zones []*string
+	added := sets.New[string]()
+	for _, zone := range strings.Split(value, ",") {
+		zone = strings.TrimSpace(zone)
+		if zone == "" || added.Has(zone) {
+			continue
+		}
+		if !supported.Has(zone) {
+			return nil, fmt.Errorf("annotation %s=%s is invalid: zone %q is not supported by region %s, supported zones: %v",
+				consts.ServiceAnnotationAzurePIPZones, value, zone, location, sets.List(supported))
+		}
+		added.Insert(zone)
+		zones = append(zones, ptr.To(zone))
+	}
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("annotation %s=%s is invalid: no zone is specified", consts.ServiceAnnotationAzurePIPZones, value)
+	}
+	return zones, nil
+}
+
+// checkPIPZones emits a warning event if the zones of the existing public IP differ from the
+// zones in the azure-pip-zones annotation, because the zones cannot be changed in place.
+func (az *Cloud) checkPIPZones(ctx context.Context, service *v1.Service, pip *armnetwork.PublicIPAddress) {
+	if _, found := service.Annotations[consts.ServiceAnnotationAzurePIPZones]; !found {
+		return
+	}
+	zones, err := az.getServicePIPZones(ctx, service, ptr.Deref(pip.Location, az.Location))
+	if err != nil {
+		klog.Warningf("checkPIPZones: failed to get the zones of public IP %s: %v", ptr.Deref(pip.Name, ""), err)
+		az.Event(service, v1.EventTypeWarning, "InvalidPublicIPZones", err.Error())
+		return
+	}
+	expected := sets.New(lo.FromSlicePtr(zones)...)
+	actual := sets.New(lo.FromSlicePtr(pip.Zones)...)
+	if !expected.Equal(actual) {
+		msg := fmt.Sprintf("the zones %v of public IP %s differ from the zones %v in annotation %s, the public IP should be recreated to change its zones",
+			sets.List(actual), ptr.Deref(pip.Name, ""), sets.List(expected), consts.ServiceAnnotationAzurePIPZones)
+		klog.Warningf("checkPIPZones: %s", msg)
+		az.Event(service, v1.EventTypeWarning, "PublicIPZonesMismatch", msg)
+	}
+}
+
 // makeZone returns the zone value in format of <region>-<zone-id>.
 func (az *Cloud) makeZone(location string, zoneID int) string {
 	return fmt.Sprintf("%s-%d", strings.ToLower(location), zoneID)
@@ -26,14 +26,18 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/stretchr/testify/assert"
 
 	"go.uber.org/mock/gomock"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualmachineclient/mock_virtualmachineclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider/zone"
 	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
@@ -335,3 +339,123 @@ func TestRefreshZones(t *testing.T) {
 	}()
 	az.refreshZones(ctx, az.syncRegionZonesMap)
 }
+
+func TestGetServicePIPZones(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		annotation    *string
+		location      string
+		expectedZones []*string
+		expectedErr   bool
+	}{
+		{
+			desc:          "should return all zones of the region by default",
+			location:      "westus",
+			expectedZones: to.SliceOfPtrs("1", "2", "3"),
+		},
+		{
+			desc:     "should return nil by default if the region does not support zones",
+			location: "northcentralus",
+		},
+		{
+			desc:          "should return all zones of the region for zone-redundant",
+			annotation:    ptr.To("Zone-Redundant"),
+			location:      "westus",
+			expectedZones: to.SliceOfPtrs("1", "2", "3"),
+		},
+		{
+			desc:        "should report an error for zone-redundant if the region does not support zones",
+			annotation:  ptr.To(consts.PIPZonesZoneRedundant),
+			location:    "northcentralus",
+			expectedErr: true,
+		},
+		{
+			desc:       "should return nil for no-zone",
+			annotation: ptr.To(consts.PIPZonesNoZone),
+			location:   "westus",
+		},
+		{
+			desc:          "should return the specified zones",
+			annotation:    ptr.To(" 2, 1,2"),
+			location:      "westus",
+			expectedZones: to.SliceOfPtrs("2", "1"),
+		},
+		{
+			desc:        "should report an error if the zone is not supported by the region",
+			annotation:  ptr.To("1,4"),
+			location:    "westus",
+			expectedErr: true,
+		},
+		{
+			desc:        "should report an error if no zone is specified",
+			annotation:  ptr.To(" , "),
+			location:    "westus",
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			az.regionZonesMap = map[string][]string{"westus": {"1", "2", "3"}, "northcentralus": {}}
+
+			svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+			if tc.annotation != nil {
+				svc.Annotations[consts.ServiceAnnotationAzurePIPZones] = *tc.annotation
+			}
+			zones, err := az.getServicePIPZones(context.TODO(), &svc, tc.location)
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedZones, zones)
+		})
+	}
+}
+
+func TestCheckPIPZones(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		annotation    *string
+		pipZones      []*string
+		expectedEvent bool
+	}{
+		{
+			desc:     "should not check the zones without the annotation",
+			pipZones: to.SliceOfPtrs("1"),
+		},
+		{
+			desc:       "should not report the matched zones",
+			annotation: ptr.To("2,1"),
+			pipZones:   to.SliceOfPtrs("1", "2"),
+		},
+		{
+			desc:          "should report the mismatched zones",
+			annotation:    ptr.To(consts.PIPZonesZoneRedundant),
+			pipZones:      to.SliceOfPtrs("1"),
+			expectedEvent: true,
+		},
+		{
+			desc:          "should report the invalid annotation",
+			annotation:    ptr.To("4"),
+			expectedEvent: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			az := GetTestCloud(ctrl)
+			az.regionZonesMap = map[string][]string{"westus": {"1", "2", "3"}}
+			recorder := record.NewFakeRecorder(1)
+			az.eventRecorder = recorder
+
+			svc := getTestService("test1", v1.ProtocolTCP, nil, false, 80)
+			if tc.annotation != nil {
+				svc.Annotations[consts.ServiceAnnotationAzurePIPZones] = *tc.annotation
+			}
+			az.checkPIPZones(context.TODO(), &svc, &armnetwork.PublicIPAddress{
+				Name:     ptr.To("pip"),
+				Location: ptr.To("westus"),
+				Zones:    tc.pipZones,
+			})
+			assert.Equal(t, tc.expectedEvent, len(recorder.Events) == 1)
+		})
+	}
+}
@@ -52,6 +52,11 @@ type Config struct {
 	VnetResourceGroup string `json:"vnetResourceGroup,omitempty" yaml:"vnetResourceGroup,omitempty"`
 	// The name of the subnet that the cluster is deployed in
 	SubnetName string `json:"subnetName,omitempty" yaml:"subnetName,omitempty"`
+	// (Optional) The key of the VNet tag that lists the subnets internal load balancers can be placed in
+	// when the service does not set the azure-load-balancer-internal-subnet annotation. The tag value is
+	// a comma-separated list of subnet names, and the one with the most available addresses is selected.
+	// Subnets cannot be tagged in Azure, so the candidates are declared on the VNet.
+	InternalLoadBalancerSubnetTagKey string `json:"internalLoadBalancerSubnetTagKey,omitempty" yaml:"internalLoadBalancerSubnetTagKey,omitempty"`
 	// The name of the security group attached to the cluster's subnet
 	SecurityGroupName string `json:"securityGroupName,omitempty" yaml:"securityGroupName,omitempty"`
 	// The name of the resource group that the security group is deployed in
@@ -60,6 +65,43 @@ type Config struct {
 	RouteTableName string `json:"routeTableName,omitempty" yaml:"routeTableName,omitempty"`
 	// The name of the resource group that the RouteTable is deployed in
 	RouteTableResourceGroup string `json:"routeTableResourceGroup,omitempty" yaml:"routeTableResourceGroup,omitempty"`
+	// (Optional) The comma-separated names of the IP configurations of the NICs of the nodes whose private IPs are
+	// the next hops of the routes, e.g. when a secondary IP configuration or NIC is dedicated to the pod traffic.
+	// The first IP configuration of the same IP family as the route is used, looking up the primary NIC before the
+	// secondary ones, and the primary IP configuration is used if none matches. It is overridden by the
+	// kubernetes.azure.com/route-ip-configuration annotation of the node. The existing routes are updated when the
+	// option or the annotation changes.
+	RouteIPConfigurationName string `json:"routeIPConfigurationName,omitempty" yaml:"routeIPConfigurationName,omitempty"`
+	// (Optional) The policies overriding the next hops of the routes of the nodes in the specific subnets. The
+	// first policy listing the subnet of the primary IP configuration of the node is applied. The existing routes
+	// are updated after the cloud controller manager restarts with the changed policies.
+	RouteNextHopPolicies []RouteNextHopPolicy `json:"routeNextHopPolicies,omitempty" yaml:"routeNextHopPolicies,omitempty"`
+	// (Optional) The additional cluster CIDRs the node IPAM range allocator allocates the pod CIDRs from once the
+	// cluster CIDR of the same IP family is exhausted. They are picked up without restarting the cloud controller
+	// manager when the dynamic reloading is enabled, and the existing allocations are kept.
+	AdditionalClusterCIDRs []string `json:"additionalClusterCIDRs,omitempty" yaml:"additionalClusterCIDRs,omitempty"`
+	// (Optional) The cluster CIDRs the node IPAM range allocator allocates the pod CIDRs of the nodes in the
+	// node pools from instead of the cluster CIDR of the same IP family.
+	NodePoolClusterCIDRs []NodePoolClusterCIDR `json:"nodePoolClusterCIDRs,omitempty" yaml:"nodePoolClusterCIDRs,omitempty"`
+	// (Optional) The prefix of the names of the routes managed by the route controller. If set, the routes
+	// without the prefix are regarded as user-defined routes, which are never listed, updated or deleted,
+	// and the managed routes created without the prefix are replaced by prefixed ones. If not set, all
+	// routes in the route tables are regarded as managed.
+	RouteNamePrefix string `json:"routeNamePrefix,omitempty" yaml:"routeNamePrefix,omitempty"`
+	// (Optional) Fail the creation of the route of a node if a user-defined route has the same or a more
+	// specific address prefix than the pod CIDR of the node, which takes over the pod traffic. The conflicts
+	// are only logged if not set. It requires routeNamePrefix.
+	FailOnUserRouteConflict bool `json:"failOnUserRouteConflict,omitempty" yaml:"failOnUserRouteConflict,omitempty"`
+	// (Optional) The number of route tables the routes of the nodes are spread across, because a route
+	// table can only hold 400 routes. The first table is routeTableName and the others are named
+	// routeTableName-1 to routeTableName-(n-1). Azure applies one route table per subnet, so each table
+	// belongs to a subnet in routeTableShardSubnetNames, and each node is assigned to the table of the
+	// subnet of its private IPs. The pod traffic from other subnets has to be routed by the network.
+	// Default to 1.
+	RouteTableShardCount int `json:"routeTableShardCount,omitempty" yaml:"routeTableShardCount,omitempty"`
+	// (Optional) The names of the subnets in the VNet of the cluster associated with the route tables,
+	// one per table in order. It is required if routeTableShardCount is more than 1.
+	RouteTableShardSubnetNames []string `json:"routeTableShardSubnetNames,omitempty" yaml:"routeTableShardSubnetNames,omitempty"`
 	// (Optional) The name of the availability set that should be used as the load balancer backend
 	// If this is set, the Azure cloudprovider will only add nodes from that availability set to the load
 	// balancer backend pool. If this is not set, and multiple agent pools (availability sets) are used, then
@@ -88,6 +130,12 @@ type Config struct {
 	// in `SystemTags` after the update of `Tags`.
 	// SystemTags now support prefix match, which means that if a key in `SystemTags` is a prefix of a key in `Tags`, that tag will not be deleted
 	SystemTags string `json:"systemTags,omitempty" yaml:"systemTags,omitempty"`
+	// PreserveForeignTags keeps the tags not applied by the cloud provider on the resources even if `SystemTags` is set.
+	// The keys of the tags applied from `Tags`, `TagsMap` and the service annotations are recorded in the
+	// `k8s-azure-managed-tags` tag of each resource, so the tags removed from them are still deleted.
+	// The keys which don't fit in the 256 characters of a tag value are continued in `k8s-azure-managed-tags-1`,
+	// `k8s-azure-managed-tags-2` and so on.
+	PreserveForeignTags bool `json:"preserveForeignTags,omitempty" yaml:"preserveForeignTags,omitempty"`
 	// Sku of Load Balancer and Public IP. Candidate values are: basic and standard.
 	// If not set, it will be default to basic.
 	LoadBalancerSKU string `json:"loadBalancerSku,omitempty" yaml:"loadBalancerSku,omitempty"`
@@ -111,6 +159,13 @@ type Config struct {
 	EnableVmssFlexNodes bool `json:"enableVmssFlexNodes,omitempty" yaml:"enableVmssFlexNodes,omitempty"`
 	// Use instance metadata service where possible
 	UseInstanceMetadata bool `json:"useInstanceMetadata,omitempty" yaml:"useInstanceMetadata,omitempty"`
+	// InstanceMetadataOnly reads the addresses, the instance type and the zone of the local instance only from
+	// the instance metadata service and never falls back to ARM, for clusters with outbound lockdown where ARM is
+	// only reachable via a proxy. The metadata of the other instances can't be read, so the nodes are initialized
+	// by cloud-node-manager. The node lifecycle checks don't read ARM either: the instances are assumed to exist
+	// and to be running, so the nodes of the deleted VMs are never removed by the cloud controller manager and have
+	// to be deleted by the cluster operator. It requires useInstanceMetadata and the `imds` nodeAddressSource.
+	InstanceMetadataOnly bool `json:"instanceMetadataOnly,omitempty" yaml:"instanceMetadataOnly,omitempty"`
 
 	// Backoff exponent
 	CloudProviderBackoffExponent float64 `json:"cloudProviderBackoffExponent,omitempty" yaml:"cloudProviderBackoffExponent,omitempty"`
@@ -136,6 +191,13 @@ type Config struct {
 	// PutVMSSVMBatchSize defines how many requests the client send concurrently when putting the VMSS VMs.
 	// If it is smaller than or equal to one, the request will be sent one by one in sequence (default).
 	PutVMSSVMBatchSize int `json:"putVMSSVMBatchSize" yaml:"putVMSSVMBatchSize"`
+	// PutVMSSVMFlushMaxInstances is the number of VMs of a scale set updated in one flush when the backend pools of
+	// the VMs change. The backend pool additions of a service are merged per scale set first, so each VM is updated
+	// once. Default is 0, which updates all the VMs of the scale set in one flush.
+	PutVMSSVMFlushMaxInstances int `json:"putVMSSVMFlushMaxInstances,omitempty" yaml:"putVMSSVMFlushMaxInstances,omitempty"`
+	// PutVMSSVMFlushIntervalInSeconds is the duration between the flushes of the VMs of a scale set, so the updates of
+	// a large scale event are spread out. Default is 0, which sends the flushes back to back.
+	PutVMSSVMFlushIntervalInSeconds int `json:"putVMSSVMFlushIntervalInSeconds,omitempty" yaml:"putVMSSVMFlushIntervalInSeconds,omitempty"`
 	// PrivateLinkServiceResourceGroup determines the specific resource group of the private link services user want to use
 	PrivateLinkServiceResourceGroup string `json:"privateLinkServiceResourceGroup,omitempty" yaml:"privateLinkServiceResourceGroup,omitempty"`
 
@@ -150,11 +212,176 @@ type Config struct {
 	// If the length is not 0, it is assumed the multiple standard load balancers mode is on. In this case,
 	// there must be one configuration named "<clustername>" or an error will be reported.
 	MultipleStandardLoadBalancerConfigurations []MultipleStandardLoadBalancerConfiguration `json:"multipleStandardLoadBalancerConfigurations,omitempty" yaml:"multipleStandardLoadBalancerConfigurations,omitempty"`
+	// EnableLocalServiceBackendPools creates a dedicated backend pool for each service with `externalTrafficPolicy: Local`
+	// on the single standard load balancer. The backend pool only contains the nodes hosting the endpoints of the service,
+	// and it is updated by the EndpointSlice informer. It is always enabled with multiple standard load balancers, and it
+	// is ignored unless LoadBalancerBackendPoolConfigurationType is nodeIP.
+	EnableLocalServiceBackendPools bool `json:"enableLocalServiceBackendPools,omitempty" yaml:"enableLocalServiceBackendPools,omitempty"`
+	// NodePoolOutboundConfigurations assigns the outbound public IPs of the node pools on the single standard
+	// load balancer. It requires the LoadBalancerBackendPoolConfigurationType nodeIPConfiguration.
+	NodePoolOutboundConfigurations []NodePoolOutboundConfiguration `json:"nodePoolOutboundConfigurations,omitempty" yaml:"nodePoolOutboundConfigurations,omitempty"`
+	// RegionalLoadBalancers are the standard load balancers in the other regions of a cluster stretched over peered
+	// virtual networks, which give the services selecting the regions a frontend in each of them. It requires the
+	// standard load balancer SKU.
+	RegionalLoadBalancers []RegionalLoadBalancerConfiguration `json:"regionalLoadBalancers,omitempty" yaml:"regionalLoadBalancers,omitempty"`
+	// LoadBalancerBackendPoolNICNamePattern is a regular expression selecting the network interface of the multi-NIC nodes
+	// which joins the load balancer backend pools, matched against the NIC names of the VMs and the network interface
+	// configuration names of the scale sets. The nodes with a single NIC always use it. Defaults to the primary NIC,
+	// which is removed from the backend pools once another NIC joins them. It is ignored unless
+	// LoadBalancerBackendPoolConfigurationType is nodeIPConfiguration.
+	LoadBalancerBackendPoolNICNamePattern string `json:"loadBalancerBackendPoolNICNamePattern,omitempty" yaml:"loadBalancerBackendPoolNICNamePattern,omitempty"`
+	// LoadBalancerBackendPoolIPConfigurationName is the name of the IPv4 IP configuration of the selected NIC which joins
+	// the load balancer backend pools. The NICs with a single IP configuration always use it. Defaults to the primary IP
+	// configuration. It is ignored unless LoadBalancerBackendPoolConfigurationType is nodeIPConfiguration.
+	LoadBalancerBackendPoolIPConfigurationName string `json:"loadBalancerBackendPoolIPConfigurationName,omitempty" yaml:"loadBalancerBackendPoolIPConfigurationName,omitempty"`
+	// NodeDeletionTaintKeys are the keys of the taints marking the nodes going to be deleted, e.g. by cluster-autoscaler
+	// during a scale-down. The marked nodes are removed from the backend pools of the load balancers and their routes are
+	// deleted before the VMs are deleted, which reduces the connection resets. Defaults to ToBeDeletedByClusterAutoscaler,
+	// and an empty list disables it.
+	NodeDeletionTaintKeys []string `json:"nodeDeletionTaintKeys,omitempty" yaml:"nodeDeletionTaintKeys,omitempty"`
+	// SecurityGroupManagementMode is how the security rules of the services are managed: full, append-only or
+	// disabled. The append-only mode never modifies or deletes the security rules not created by the cloud provider,
+	// and the disabled mode never adds or updates any rule, only removing the stale rules created by the cloud
+	// provider, e.g. if the security group is owned by a central network team and the changes are reverted by
+	// Azure Policy. Defaults to full. The services can use a stricter mode by the
+	// annotation service.beta.kubernetes.io/azure-security-group-management-mode.
+	SecurityGroupManagementMode string `json:"securityGroupManagementMode,omitempty" yaml:"securityGroupManagementMode,omitempty"`
+	// WindowsNodes is the load balancer behavior while there are Windows nodes in the backend pools.
+	WindowsNodes *WindowsNodeConfiguration `json:"windowsNodes,omitempty" yaml:"windowsNodes,omitempty"`
 
-	// RouteUpdateIntervalInSeconds is the interval for updating routes. Default is 30 seconds.
+	// RouteUpdateIntervalInSeconds is the interval for updating routes. The routes created and deleted
+	// within the interval are applied by a single update of each route table. Default is 30 seconds.
 	RouteUpdateIntervalInSeconds int `json:"routeUpdateIntervalInSeconds,omitempty" yaml:"routeUpdateIntervalInSeconds,omitempty"`
+	// RouteDriftReconciliationMode enables the periodic reconciliation of the routes against the nodes. The routes
+	// targeting deleted nodes, or with next hops which are not the IPs of their nodes, are reported by the
+	// cloudprovider_azure_route_drift_count metric in the audit mode, and repaired as well in the repair mode.
+	// Candidate values are: audit and repair. Disabled by default. The repair mode requires routeNamePrefix, so only
+	// the managed routes are changed. Set routeNamePrefix in the audit mode as well to keep the user-defined routes
+	// to virtual appliances from being reported as routes of deleted nodes.
+	RouteDriftReconciliationMode string `json:"routeDriftReconciliationMode,omitempty" yaml:"routeDriftReconciliationMode,omitempty"`
+	// RouteDriftReconciliationIntervalInSeconds is the interval of the route drift reconciliation. Default is 300 seconds.
+	RouteDriftReconciliationIntervalInSeconds int `json:"routeDriftReconciliationIntervalInSeconds,omitempty" yaml:"routeDriftReconciliationIntervalInSeconds,omitempty"`
 	// LoadBalancerBackendPoolUpdateIntervalInSeconds is the interval for updating load balancer backend pool of local services. Default is 30 seconds.
 	LoadBalancerBackendPoolUpdateIntervalInSeconds int `json:"loadBalancerBackendPoolUpdateIntervalInSeconds,omitempty" yaml:"loadBalancerBackendPoolUpdateIntervalInSeconds,omitempty"`
+	// EnableAsyncLoadBalancerUpdate makes the load balancer updates asynchronous. The reconciliation returns once the
+	// update is accepted by ARM and is requeued when the update finishes, instead of blocking the worker while polling.
+	// The pending update is recorded on the service, so it is resumed after a restart or a leader change.
+	EnableAsyncLoadBalancerUpdate bool `json:"enableAsyncLoadBalancerUpdate,omitempty" yaml:"enableAsyncLoadBalancerUpdate,omitempty"`
+	// LoadBalancerBackendPoolNodeMinAgeInSeconds is the minimum age of a node before it is added to load balancer backend pools.
+	// Nodes younger than this are excluded even if they are Ready, and older nodes have to be Ready to be added.
+	// The services are updated when their nodes age in. Default is 0, which disables the check.
+	LoadBalancerBackendPoolNodeMinAgeInSeconds int `json:"loadBalancerBackendPoolNodeMinAgeInSeconds,omitempty" yaml:"loadBalancerBackendPoolNodeMinAgeInSeconds,omitempty"`
+	// LoadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds is the duration a node can stay NotReady before it is removed
+	// from load balancer backend pools. Default is 0, which never removes NotReady nodes from the backend pools.
+	// The nodes which are not Ready when they reach LoadBalancerBackendPoolNodeMinAgeInSeconds are still not added.
+	LoadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds int `json:"loadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds,omitempty" yaml:"loadBalancerBackendPoolNotReadyNodeGracePeriodInSeconds,omitempty"`
+	// NodeScaleWaveThreshold is the number of nodes added or deleted within NodeScaleWaveWindowInSeconds that makes a
+	// scale wave, e.g. a scale-up by cluster-autoscaler. During a wave, the node sync updates of the load balancers are
+	// deferred until no node has been added or deleted for NodeScaleWaveSettleInSeconds, and then each service is updated
+	// once with all the nodes. The lookups of the new nodes, e.g. by the node status updates, share the refreshes of the
+	// VMs cache of their scale sets. Default is 0, which disables the detection.
+	NodeScaleWaveThreshold int `json:"nodeScaleWaveThreshold,omitempty" yaml:"nodeScaleWaveThreshold,omitempty"`
+	// NodeScaleWaveWindowInSeconds is the window NodeScaleWaveThreshold counts the node additions and deletions in.
+	// Default is 60 seconds.
+	NodeScaleWaveWindowInSeconds int `json:"nodeScaleWaveWindowInSeconds,omitempty" yaml:"nodeScaleWaveWindowInSeconds,omitempty"`
+	// NodeScaleWaveSettleInSeconds is how long no node has to be added or deleted for a scale wave to end. Default is 30 seconds.
+	NodeScaleWaveSettleInSeconds int `json:"nodeScaleWaveSettleInSeconds,omitempty" yaml:"nodeScaleWaveSettleInSeconds,omitempty"`
+	// LoadBalancerDedicatedHostGroups is a comma-separated list of dedicated host group names. If it is set, only the nodes
+	// whose VMs are in these host groups are added to load balancer backend pools. The host group of a node is read
+	// from its cached VM or scale set, not from the node labels.
+	// Default is empty, which does not filter nodes by host group.
+	LoadBalancerDedicatedHostGroups string `json:"loadBalancerDedicatedHostGroups,omitempty" yaml:"loadBalancerDedicatedHostGroups,omitempty"`
+
+	// ServiceReconcileBackoffBaseDelayInSeconds is the base delay of the per-service exponential backoff after
+	// a failed reconciliation. The delay doubles after each consecutive failure with jitter. Default is 0, which
+	// disables the backoff.
+	ServiceReconcileBackoffBaseDelayInSeconds int `json:"serviceReconcileBackoffBaseDelayInSeconds,omitempty" yaml:"serviceReconcileBackoffBaseDelayInSeconds,omitempty"`
+	// ServiceReconcileBackoffMaxDelayInSeconds is the max delay of the per-service backoff. Default is 300 seconds.
+	ServiceReconcileBackoffMaxDelayInSeconds int `json:"serviceReconcileBackoffMaxDelayInSeconds,omitempty" yaml:"serviceReconcileBackoffMaxDelayInSeconds,omitempty"`
+	// ServiceReconcileCircuitBreakerThreshold is the number of consecutive failures after which the reconciliation
+	// of a service is parked until its spec or annotations change. Throttling, server errors and conflicts with other
+	// operations are not counted, and the node updates of the backend pools are never parked. It only works with the
+	// per-service backoff. Default is 0, which never parks services.
+	ServiceReconcileCircuitBreakerThreshold int `json:"serviceReconcileCircuitBreakerThreshold,omitempty" yaml:"serviceReconcileCircuitBreakerThreshold,omitempty"`
+	// ServiceReconcileCircuitBreakerProbeIntervalInSeconds is the interval at which a parked service is reconciled
+	// again to check whether the failure has been fixed. Default is 1800 seconds.
+	ServiceReconcileCircuitBreakerProbeIntervalInSeconds int `json:"serviceReconcileCircuitBreakerProbeIntervalInSeconds,omitempty" yaml:"serviceReconcileCircuitBreakerProbeIntervalInSeconds,omitempty"`
+
+	// ARMRequestBudgetsPerHour are the numbers of ARM requests per hour budgeted for the callers, which are service,
+	// node, route, background and other. The requests of the callers are counted by the cloudprovider_azure_arm_requests_total
+	// metric, and the ones served by the read cache are not counted. The background reconciliations, i.e. the route drift
+	// reconciliation, the cache invalidation by the activity log and the refresh of the caches restored from a snapshot,
+	// are skipped when the background budget or 90% of the total budget is consumed in the hour, so the quota is left
+	// for the other callers, which are never throttled. A budget of 0 leaves the caller unlimited. Default is empty,
+	// which disables the budgets.
+	ARMRequestBudgetsPerHour map[string]int `json:"armRequestBudgetsPerHour,omitempty" yaml:"armRequestBudgetsPerHour,omitempty"`
+	// ARMAuditLogPath is the file the audit records of the mutating ARM calls are appended to as JSON lines. A record
+	// includes the caller, the method, the resource ID, a summary of the changed fields, the correlation ID and the
+	// outcome of the call. The long running operations are recorded once they are polled until they finish. Default is
+	// empty, which doesn't write the audit log.
+	ARMAuditLogPath string `json:"armAuditLogPath,omitempty" yaml:"armAuditLogPath,omitempty"`
+	// ARMAuditWebhookURL is the http(s) URL the audit records of the mutating ARM calls are posted to as JSON. The records
+	// are posted in the background and dropped if the webhook falls behind. Default is empty, which doesn't post the records.
+	ARMAuditWebhookURL string `json:"armAuditWebhookURL,omitempty" yaml:"armAuditWebhookURL,omitempty"`
+	// StrictRateLimitValidation refuses to start with the pathological rate limit and backoff settings instead of
+	// warning about them, e.g. the rate limits rejecting all the read or write requests of a client, the route table
+	// write rate limit below the updates of the route table shards every routeUpdateIntervalInSeconds. Default is false,
+	// which only logs warnings.
+	StrictRateLimitValidation bool `json:"strictRateLimitValidation,omitempty" yaml:"strictRateLimitValidation,omitempty"`
+
+	// StatusReportIntervalInSeconds is the interval at which the cloud provider reports the load balancers, the route
+	// tables, the credentials, the throttled resources, the failing services and the syncs of the controllers to
+	// the cluster-scoped AzureCloudProviderStatus named azure-cloud-provider, whose CRD must be installed. Default is
+	// 0, which disables the status reporting.
+	StatusReportIntervalInSeconds int `json:"statusReportIntervalInSeconds,omitempty" yaml:"statusReportIntervalInSeconds,omitempty"`
+
+	// AzureFeatureGates enables or disables the feature gates of the Azure cloud provider by their names, e.g.
+	// {"PrivateLinkService": true, "IPBasedBackendPool": false}. The feature gates set by --azure-feature-gates
+	// take precedence. Default is empty, which uses the defaults of the feature gates.
+	AzureFeatureGates map[string]bool `json:"azureFeatureGates,omitempty" yaml:"azureFeatureGates,omitempty"`
+
+	// LoadBalancerResourceNamingScheme determines how the load balancing rules and health probes of services are named.
+	// Supported values are `legacy` and `hashed`.
+	// `legacy`: `<prefix>[-<subnet>]-<protocol>-<port>`, where the prefix is `a` followed by the service UID (default);
+	// `hashed`: `<prefix>-<hash>`, where the hash is the first 16 hex characters of the SHA-256 of
+	// `<service UID>[/<internal subnet>]/<protocol>/<port>`, all lower-cased except the upper-cased protocol.
+	// The prefix keeps the ownership of the rules, so the rules and probes with the legacy names are replaced by the
+	// hashed ones on the first reconciliation after switching.
+	// Frontend IP configurations keep the `<prefix>[-<subnet>]` names in both schemes, for both the existing and the new ones,
+	// since a frontend IP configuration can't be renamed without recreating it and detaching its IP address.
+	LoadBalancerResourceNamingScheme string `json:"loadBalancerResourceNamingScheme,omitempty" yaml:"loadBalancerResourceNamingScheme,omitempty"`
+
+	// NodeAddressIPFamilies is a comma-separated list of the IP families of the node addresses in the order of
+	// preference, e.g. `IPv4,IPv6` or `IPv6,IPv4` for dual-stack clusters. The InternalIPs and ExternalIPs are sorted
+	// by the order and the IPs of the families not in the list are dropped. If both families are set, the private
+	// and public IPs of all IP configurations of the primary NIC are read from ARM instead of the primary IP
+	// configuration only. Default is empty, which reports the addresses as-is.
+	NodeAddressIPFamilies string `json:"nodeAddressIPFamilies,omitempty" yaml:"nodeAddressIPFamilies,omitempty"`
+
+	// NodeAddressSource determines where the node addresses are read from.
+	// Supported values are `imds` and `arm`.
+	// `imds`: the addresses of the local instance are read from IMDS if useInstanceMetadata is enabled, others from ARM (default);
+	// `arm`: the addresses are always read from the NICs in ARM, it requires Azure credentials.
+	NodeAddressSource string `json:"nodeAddressSource,omitempty" yaml:"nodeAddressSource,omitempty"`
+	// NodeAddressOrder determines the order of the InternalIPs and the ExternalIPs in the node addresses.
+	// Supported values are `internalIPFirst` (default) and `externalIPFirst`.
+	NodeAddressOrder string `json:"nodeAddressOrder,omitempty" yaml:"nodeAddressOrder,omitempty"`
+	// ExcludeNodePublicIPs drops the public IPs from the node addresses, so no ExternalIP is reported.
+	ExcludeNodePublicIPs bool `json:"excludeNodePublicIPs,omitempty" yaml:"excludeNodePublicIPs,omitempty"`
+
+	// DeallocatedVMPolicy determines how the nodes of deallocated VMs are reported to the cloud node lifecycle controller.
+	// Supported values are `shutdown` and `deleted`.
+	// `shutdown`: the nodes get the shutdown taint and are kept in the cluster, so VMs stopped for cost savings rejoin when started (default);
+	// `deleted`: the nodes are deleted from the cluster.
+	// VMs stopped without deallocation are always reported as shut down.
+	DeallocatedVMPolicy string `json:"deallocatedVMPolicy,omitempty" yaml:"deallocatedVMPolicy,omitempty"`
+
+	// NodeDeletionGracePeriodInSeconds is the grace period to drain the nodes whose VMs are deleted.
+	// The nodes are cordoned and tainted with `kubernetes.azure.com/vm-deleted:NoExecute`, and are
+	// removed after the grace period or after their pods are evicted. The taint is removed and the nodes are uncordoned
+	// if their VMs exist again. The pods are watched, so the cloud provider needs to list and watch pods.
+	// Default is 0, which removes the nodes immediately.
+	NodeDeletionGracePeriodInSeconds int `json:"nodeDeletionGracePeriodInSeconds,omitempty" yaml:"nodeDeletionGracePeriodInSeconds,omitempty"`
 
 	// ClusterServiceLoadBalancerHealthProbeMode determines the health probe mode for cluster service load balancer.
 	// Supported values are `shared` and `servicenodeport`.
@@ -208,6 +435,15 @@ func (az *Config) UseSingleStandardLoadBalancer() bool {
 	return az.UseStandardLoadBalancer() && len(az.MultipleStandardLoadBalancerConfigurations) == 0
 }
 
+// UseLocalServiceBackendPools returns true if services with `externalTrafficPolicy: Local`
+// use dedicated backend pools that only contain the nodes hosting their endpoints.
+func (az *Config) UseLocalServiceBackendPools() bool {
+	if az.UseMultipleStandardLoadBalancers() {
+		return true
+	}
+	return az.UseSingleStandardLoadBalancer() && az.EnableLocalServiceBackendPools && az.IsLBBackendPoolTypeNodeIP()
+}
+
 func (az *Config) IsStackCloud() bool {
 	return strings.EqualFold(az.Cloud, consts.AzureStackCloudName) && !az.DisableAzureStackCloud
 }
@@ -48,6 +48,11 @@ type AzureClientConfig struct {
 
 	// The ID of the Azure Subscription that the network resources are deployed in
 	NetworkResourceSubscriptionID string `json:"networkResourceSubscriptionID,omitempty" yaml:"networkResourceSubscriptionID,omitempty"`
+	// (Optional) The credentials of the GETs of the network interfaces of the nodes in other subscriptions. The
+	// credential set is chosen by the subscription in the resource ID of the network interface, and the credential
+	// of the cluster is used if none matches. The other ARM calls always use the credential of the cluster.
+	// The credential sets in other tenants cannot be combined with networkResourceTenantID.
+	CredentialSets []CredentialSet `json:"credentialSets,omitempty" yaml:"credentialSets,omitempty"`
 }
 
 // UsesNetworkResourceInDifferentSubscription determines whether the AzureAuthConfig indicates to use network resources
@@ -26,6 +26,24 @@ type CloudProviderCacheConfig struct {
 	VmssCacheTTLInSeconds int `json:"vmssCacheTTLInSeconds,omitempty" yaml:"vmssCacheTTLInSeconds,omitempty"`
 	// VmssVirtualMachinesCacheTTLInSeconds sets the cache TTL for vmssVirtualMachines
 	VmssVirtualMachinesCacheTTLInSeconds int `json:"vmssVirtualMachinesCacheTTLInSeconds,omitempty" yaml:"vmssVirtualMachinesCacheTTLInSeconds,omitempty"`
+	// VmssVirtualMachinesNotFoundCacheTTLInSeconds sets the cache TTL for the vmss vms not found, so the
+	// scale sets are not listed again for the nodes of deleted vmss vms. Default is VMNotFoundCacheTTLInSeconds.
+	VmssVirtualMachinesNotFoundCacheTTLInSeconds int `json:"vmssVirtualMachinesNotFoundCacheTTLInSeconds,omitempty" yaml:"vmssVirtualMachinesNotFoundCacheTTLInSeconds,omitempty"`
+
+	// CacheWarmupConcurrency sets the number of resource groups and scale sets listed concurrently when the
+	// caches of the scale sets and their VMs are filled, e.g. after the startup or a leader failover. Default is 8.
+	CacheWarmupConcurrency int `json:"cacheWarmupConcurrency,omitempty" yaml:"cacheWarmupConcurrency,omitempty"`
+	// CacheSnapshotPath is the path of the file the leader saves the snapshot of the VM, VMSS, VMSS VM and load
+	// balancer caches to. A newly elected leader loads the snapshot to serve the reads while the cached resources
+	// are fetched again in the background. The file should be on a volume shared by the replicas.
+	// It can't be set together with CacheSnapshotConfigMap.
+	CacheSnapshotPath string `json:"cacheSnapshotPath,omitempty" yaml:"cacheSnapshotPath,omitempty"`
+	// CacheSnapshotConfigMap is the "<namespace>/<name>" of the ConfigMap to save the snapshot of the caches to,
+	// like CacheSnapshotPath. The namespace defaults to kube-system. The snapshots larger than the size limit of
+	// ConfigMaps are not saved.
+	CacheSnapshotConfigMap string `json:"cacheSnapshotConfigMap,omitempty" yaml:"cacheSnapshotConfigMap,omitempty"`
+	// CacheSnapshotIntervalInSeconds sets the interval to save the snapshot of the caches. Default is 300.
+	CacheSnapshotIntervalInSeconds int `json:"cacheSnapshotIntervalInSeconds,omitempty" yaml:"cacheSnapshotIntervalInSeconds,omitempty"`
 
 	// VmssFlexCacheTTLInSeconds sets the cache TTL for VMSS Flex
 	VmssFlexCacheTTLInSeconds int `json:"vmssFlexCacheTTLInSeconds,omitempty" yaml:"vmssFlexCacheTTLInSeconds,omitempty"`
@@ -34,6 +52,15 @@ type CloudProviderCacheConfig struct {
 
 	// VmCacheTTLInSeconds sets the cache TTL for vm
 	VMCacheTTLInSeconds int `json:"vmCacheTTLInSeconds,omitempty" yaml:"vmCacheTTLInSeconds,omitempty"`
+	// VMNotFoundCacheTTLInSeconds sets the cache TTL for the vms not found, so the lookups of deleted
+	// vms don't call ARM every time. It is also the default of VmssVirtualMachinesNotFoundCacheTTLInSeconds,
+	// and the TTL of the provider IDs not found in the VMSS Flex and availability set VMs.
+	// Default is 0, which disables the cache.
+	VMNotFoundCacheTTLInSeconds int `json:"vmNotFoundCacheTTLInSeconds,omitempty" yaml:"vmNotFoundCacheTTLInSeconds,omitempty"`
+	// ActivityLogCacheInvalidationIntervalInSeconds sets the interval to poll the activity log of the resource groups of the nodes.
+	// The vms, vmss and vmss vms written or deleted since the last poll are removed from the caches, so they are
+	// refreshed on the next read instead of after the cache TTL. Default is 0, which disables the polling.
+	ActivityLogCacheInvalidationIntervalInSeconds int `json:"activityLogCacheInvalidationIntervalInSeconds,omitempty" yaml:"activityLogCacheInvalidationIntervalInSeconds,omitempty"`
 	// LoadBalancerCacheTTLInSeconds sets the cache TTL for load balancer
 	LoadBalancerCacheTTLInSeconds int `json:"loadBalancerCacheTTLInSeconds,omitempty" yaml:"loadBalancerCacheTTLInSeconds,omitempty"`
 	// NsgCacheTTLInSeconds sets the cache TTL for network security group
@@ -46,6 +73,11 @@ type CloudProviderCacheConfig struct {
 	AvailabilitySetsCacheTTLInSeconds int `json:"availabilitySetsCacheTTLInSeconds,omitempty" yaml:"availabilitySetsCacheTTLInSeconds,omitempty"`
 	// PublicIPCacheTTLInSeconds sets the cache TTL for public ip
 	PublicIPCacheTTLInSeconds int `json:"publicIPCacheTTLInSeconds,omitempty" yaml:"publicIPCacheTTLInSeconds,omitempty"`
+	// ARMReadCacheTTLInSeconds sets the TTL of the read-through cache of the GETs of the load balancers, scale sets,
+	// network interfaces and subnets shared by the ARM clients. The concurrent identical GETs are coalesced into one
+	// request regardless of the TTL, and the cached GETs of a resource are invalidated by the writes to it.
+	// Default is 0, which only coalesces the GETs. The cache is disabled by DisableAPICallCache.
+	ARMReadCacheTTLInSeconds int `json:"armReadCacheTTLInSeconds,omitempty" yaml:"armReadCacheTTLInSeconds,omitempty"`
 	// RouteUpdateWaitingInSeconds is the delay time for waiting route updates to take effect. This waiting delay is added
 	// because the routes are not taken effect when the async route updating operation returns success. Default is 30 seconds.
 	RouteUpdateWaitingInSeconds int `json:"routeUpdateWaitingInSeconds,omitempty" yaml:"routeUpdateWaitingInSeconds,omitempty"`
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+)
+
+// CredentialSet is the credential of the GETs of the network interfaces of the nodes joined from a subscription
+// other than the ones of the cluster.
+type CredentialSet struct {
+	// SubscriptionID is the subscription of the resources the credential is used for.
+	SubscriptionID string `json:"subscriptionId" yaml:"subscriptionId"`
+	// TenantID is the tenant of the subscription. Default to the tenant of the cluster. If it is another tenant,
+	// the credential also issues the auxiliary tokens of the ARM calls on the network resources of the cluster,
+	// so that they can reference the resources in the tenant, e.g. a public IP prefix or a peered VNet.
+	TenantID string `json:"tenantId,omitempty" yaml:"tenantId,omitempty"`
+
+	azclient.AzureAuthConfig `json:",inline" yaml:",inline"`
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// NodePoolClusterCIDR is the cluster CIDRs the node IPAM range allocator allocates the pod CIDRs of the
+// nodes in a node pool from, e.g. to give the Windows nodes their own range.
+type NodePoolClusterCIDR struct {
+	// NodeSelector is the label selector of the nodes in the node pool, e.g. kubernetes.io/os=windows.
+	// The first node pool matching the node is used.
+	NodeSelector string `json:"nodeSelector" yaml:"nodeSelector"`
+	// ClusterCIDRs are the cluster CIDRs of the node pool, at most one per IP family. The pod CIDRs of
+	// the other IP families are allocated from the cluster CIDRs of the cluster.
+	ClusterCIDRs []string `json:"clusterCIDRs" yaml:"clusterCIDRs"`
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// NodePoolOutboundConfiguration is the outbound public IPs of the nodes in a node pool, e.g. to give the
+// nodes of a tenant their own egress IPs for the allowlists of the partners. The cloud provider manages an
+// outbound rule with the public IPs and prefixes on the standard load balancer of the cluster, and a backend
+// pool with the nodes of the node pool backing it. The nodes must not be in the backend pools of the other
+// outbound rules of the load balancer, since an IP configuration can only be used by one outbound rule. The
+// nodes stay in the backend pools of the cluster for the load balancing rules, so the configurations are
+// rejected while an outbound rule, e.g. the default outbound rule of the cluster, uses them. A node pool
+// with an empty nodeSelector can give the other nodes their outbound connectivity instead.
+type NodePoolOutboundConfiguration struct {
+	// Name is the unique name of the node pool. The outbound rule and the backend pool are named
+	// `nodepool-outbound-<name>`.
+	Name string `json:"name" yaml:"name"`
+	// NodeSelector is the label selector of the nodes in the node pool, e.g. agentpool=tenanta. The first
+	// node pool matching the node is used.
+	NodeSelector string `json:"nodeSelector" yaml:"nodeSelector"`
+	// PublicIPAddressIDs are the resource IDs of the public IPs the nodes egress from.
+	PublicIPAddressIDs []string `json:"publicIPAddressIDs,omitempty" yaml:"publicIPAddressIDs,omitempty"`
+	// PublicIPPrefixIDs are the resource IDs of the public IP prefixes the nodes egress from.
+	PublicIPPrefixIDs []string `json:"publicIPPrefixIDs,omitempty" yaml:"publicIPPrefixIDs,omitempty"`
+	// AllocatedOutboundPorts is the number of the SNAT ports allocated to each node, a multiple of 8.
+	// The ports are allocated by the size of the backend pool if it is 0.
+	AllocatedOutboundPorts int32 `json:"allocatedOutboundPorts,omitempty" yaml:"allocatedOutboundPorts,omitempty"`
+	// IdleTimeoutInMinutes is the idle timeout of the outbound flows, from 4 to 120 minutes. Defaults to 4.
+	IdleTimeoutInMinutes int32 `json:"idleTimeoutInMinutes,omitempty" yaml:"idleTimeoutInMinutes,omitempty"`
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// RegionalLoadBalancerConfiguration is a standard load balancer in another region of a cluster stretched over
+// peered virtual networks. The services selecting the region by the annotation
+// service.beta.kubernetes.io/azure-load-balancer-regions get a public frontend on the load balancer, backed by
+// the nodes in the region by their topology.kubernetes.io/region labels. The load balancer is named
+// `<clustername>-<region>` and created in the load balancer resource group.
+type RegionalLoadBalancerConfiguration struct {
+	// Region is the Azure location of the load balancer and the public IPs of its frontends, e.g. westus2.
+	// It must not be the location of the cluster.
+	Region string `json:"region" yaml:"region"`
+	// VirtualNetworkID is the resource ID of the virtual network of the nodes in the region, which the IP-based
+	// backend pool of the load balancer refers to.
+	VirtualNetworkID string `json:"virtualNetworkID" yaml:"virtualNetworkID"`
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// RouteNextHopPolicy overrides the next hop of the routes to the pod CIDRs of the nodes in the subnets,
+// e.g. to chain the pod traffic through a firewall required by the network policy.
+type RouteNextHopPolicy struct {
+	// SubnetNames are the names of the subnets of the primary IP configurations of the nodes.
+	SubnetNames []string `json:"subnetNames" yaml:"subnetNames"`
+	// NextHopType is the next hop type of the routes. Candidate values are: VirtualAppliance and
+	// VirtualNetworkGateway. Default to VirtualAppliance.
+	NextHopType string `json:"nextHopType,omitempty" yaml:"nextHopType,omitempty"`
+	// NextHopIPAddress is the IP of the virtual appliance forwarding the traffic to the nodes. It is
+	// required by the VirtualAppliance next hop type, and only applied to the routes of the same IP family.
+	NextHopIPAddress string `json:"nextHopIPAddress,omitempty" yaml:"nextHopIPAddress,omitempty"`
+}
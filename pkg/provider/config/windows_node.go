@@ -0,0 +1,36 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// WindowsNodeConfiguration is the load balancer behavior for the clusters with Windows nodes, which
+// need longer health probes and may not support the direct server return (DSR) of floating IPs. It
+// applies to all the services of the load balancers while any Windows node, i.e. a node labeled
+// kubernetes.io/os=windows, is in the backend pools, so mixed OS clusters don't need to tune the
+// services one by one. The service annotations take precedence over it.
+type WindowsNodeConfiguration struct {
+	// HealthProbeIntervalInSeconds is the default interval of the health probes, at least 5 seconds.
+	// The default interval of the cloud provider is used if it is 0.
+	HealthProbeIntervalInSeconds int32 `json:"healthProbeIntervalInSeconds,omitempty" yaml:"healthProbeIntervalInSeconds,omitempty"`
+	// HealthProbeNumOfProbe is the default number of the failed probes marking a node unhealthy, at least 2.
+	// The default number of the cloud provider is used if it is 0.
+	HealthProbeNumOfProbe int32 `json:"healthProbeNumOfProbe,omitempty" yaml:"healthProbeNumOfProbe,omitempty"`
+	// DisableFloatingIP disables the floating IPs of the load balancing rules, as if the services had the
+	// annotation service.beta.kubernetes.io/azure-disable-load-balancer-floating-ip, so the traffic is sent to
+	// the node ports instead of being returned directly by the nodes. With multiple standard load balancers,
+	// only the services on the load balancers with Windows nodes are affected.
+	DisableFloatingIP bool `json:"disableFloatingIP,omitempty" yaml:"disableFloatingIP,omitempty"`
+}
@@ -52,7 +52,9 @@ type AccessControl struct {
 }
 
 type accessControlOptions struct {
-	EventEmitter K8sEventEmitter
+	EventEmitter      K8sEventEmitter
+	DisableFloatingIP bool
+	AppendOnly        bool
 }
 
 var defaultAccessControlOptions = accessControlOptions{
@@ -67,6 +69,21 @@ func WithEventEmitter(emitter K8sEventEmitter) AccessControlOption {
 	}
 }
 
+// WithAppendOnly keeps the security rules not created by the cloud provider untouched.
+func WithAppendOnly() AccessControlOption {
+	return func(o *accessControlOptions) {
+		o.AppendOnly = true
+	}
+}
+
+// WithFloatingIPDisabled sets the security rules to allow the node ports, as if the service had the
+// annotation to disable the floating IP.
+func WithFloatingIPDisabled() AccessControlOption {
+	return func(o *accessControlOptions) {
+		o.DisableFloatingIP = true
+	}
+}
+
 func NewAccessControl(logger logr.Logger, svc *v1.Service, sg *armnetwork.SecurityGroup, opts ...AccessControlOption) (*AccessControl, error) {
 	logger = logger.WithName("AccessControl").WithValues("security-group", ptr.To(sg.Name))
 
@@ -81,6 +98,9 @@ func NewAccessControl(logger logr.Logger, svc *v1.Service, sg *armnetwork.Securi
 		logger.Error(err, "Failed to initialize RuleHelper")
 		return nil, err
 	}
+	if options.AppendOnly {
+		sgHelper.SetAppendOnly()
+	}
 	sourceRanges, invalidSourceRanges, err := SourceRanges(svc)
 	if err != nil {
 		logger.Error(err, "Failed to parse SourceRange configuration")
@@ -96,7 +116,7 @@ func NewAccessControl(logger logr.Logger, svc *v1.Service, sg *armnetwork.Securi
 		eventEmitter(svc, v1.EventTypeWarning, "InvalidAllowedIPRanges", EventMessageOfInvalidAllowedIPRanges(invalidAllowedIPRanges))
 	}
 	allowedServiceTags := AllowedServiceTags(svc)
-	securityRuleDestinationPortsByProtocol, err := SecurityRuleDestinationPortsByProtocol(svc)
+	securityRuleDestinationPortsByProtocol, err := SecurityRuleDestinationPortsByProtocolWithFloatingIP(svc, options.DisableFloatingIP || consts.IsK8sServiceDisableLoadBalancerFloatingIP(svc))
 	if err != nil {
 		logger.Error(err, "Failed to parse service Spec.Ports")
 		return nil, err
@@ -337,6 +357,12 @@ func (ac *AccessControl) SecurityGroup() (*armnetwork.SecurityGroup, bool, error
 
 // SecurityRuleDestinationPortsByProtocol returns the service ports grouped by SecurityGroup protocol.
 func SecurityRuleDestinationPortsByProtocol(svc *v1.Service) (map[armnetwork.SecurityRuleProtocol][]int32, error) {
+	return SecurityRuleDestinationPortsByProtocolWithFloatingIP(svc, consts.IsK8sServiceDisableLoadBalancerFloatingIP(svc))
+}
+
+// SecurityRuleDestinationPortsByProtocolWithFloatingIP returns the service ports grouped by SecurityGroup protocol,
+// which are the node ports if the floating IP is disabled.
+func SecurityRuleDestinationPortsByProtocolWithFloatingIP(svc *v1.Service, disableFloatingIP bool) (map[armnetwork.SecurityRuleProtocol][]int32, error) {
 	rv := make(map[armnetwork.SecurityRuleProtocol][]int32)
 	for _, port := range svc.Spec.Ports {
 		protocol, err := securitygroup.ProtocolFromKubernetes(port.Protocol)
@@ -345,7 +371,7 @@ func SecurityRuleDestinationPortsByProtocol(svc *v1.Service) (map[armnetwork.Sec
 		}
 
 		var p int32
-		if consts.IsK8sServiceDisableLoadBalancerFloatingIP(svc) {
+		if disableFloatingIP {
 			p = port.NodePort
 		} else {
 			p = port.Port
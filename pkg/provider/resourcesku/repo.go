@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcesku
+
+// Generate mocks for the repository interface
+//go:generate mockgen -destination=./mock_repo.go -package=resourcesku -copyright_file ../../../hack/boilerplate/boilerplate.generatego.txt -source=repo.go Repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+const (
+	resourceTypeVirtualMachines = "virtualMachines"
+
+	capabilityVCPUs    = "vCPUs"
+	capabilityMemoryGB = "MemoryGB"
+	capabilityGPUs     = "GPUs"
+)
+
+// VMSKU is the hardware of a VM size.
+type VMSKU struct {
+	Name     string
+	VCPUs    string
+	MemoryGB string
+	GPUs     string
+}
+
+type Repository interface {
+	// GetVMSKU returns the VM SKU of the name in the location, or nil if the SKU is not found.
+	// The SKUs of a location are listed once and cached.
+	GetVMSKU(ctx context.Context, location, skuName string) (*VMSKU, error)
+}
+
+type repo struct {
+	client *armcompute.ResourceSKUsClient
+	// cache stores the VM SKUs of each location.
+	// Key: location
+	// Value: map[string]*VMSKU keyed by the lower case SKU name
+	cache cache.Resource
+}
+
+func NewRepo(subscriptionID string, credential azcore.TokenCredential, options *arm.ClientOptions, cacheTTL time.Duration) (Repository, error) {
+	client, err := armcompute.NewResourceSKUsClient(subscriptionID, credential, options)
+	if err != nil {
+		return nil, err
+	}
+	r := &repo{client: client}
+	r.cache, err = cache.NewTimedCache(cacheTTL, r.listVMSKUs, false)
+	if err != nil {
+		return nil, fmt.Errorf("new ResourceSKU cache: %w", err)
+	}
+	return r, nil
+}
+
+func (r *repo) GetVMSKU(ctx context.Context, location, skuName string) (*VMSKU, error) {
+	cached, err := r.cache.Get(ctx, normalizeLocation(location), cache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, fmt.Errorf("get VM SKUs of location %s: %w", location, err)
+	}
+	skus, ok := cached.(map[string]*VMSKU)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for VM SKUs: got %T, want map[string]*VMSKU", cached)
+	}
+	return skus[strings.ToLower(skuName)], nil
+}
+
+func (r *repo) listVMSKUs(ctx context.Context, location string) (interface{}, error) {
+	skus := make(map[string]*VMSKU)
+	pager := r.client.NewListPager(&armcompute.ResourceSKUsClientListOptions{
+		Filter: ptr.To(fmt.Sprintf("location eq '%s'", location)),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, sku := range page.Value {
+			if sku == nil || sku.Name == nil || !strings.EqualFold(ptr.Deref(sku.ResourceType, ""), resourceTypeVirtualMachines) {
+				continue
+			}
+			vmSKU := &VMSKU{Name: *sku.Name}
+			for _, capability := range sku.Capabilities {
+				if capability == nil || capability.Name == nil {
+					continue
+				}
+				switch *capability.Name {
+				case capabilityVCPUs:
+					vmSKU.VCPUs = ptr.Deref(capability.Value, "")
+				case capabilityMemoryGB:
+					vmSKU.MemoryGB = ptr.Deref(capability.Value, "")
+				case capabilityGPUs:
+					vmSKU.GPUs = ptr.Deref(capability.Value, "")
+				}
+			}
+			skus[strings.ToLower(*sku.Name)] = vmSKU
+		}
+	}
+	return skus, nil
+}
+
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcesku
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestRepo_GetVMSKU(t *testing.T) {
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/subscriptions/sub/providers/Microsoft.Compute/skus", r.URL.Path)
+		assert.Equal(t, "location eq 'eastus'", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[
+{"name":"Standard_NC6s_v3","resourceType":"virtualMachines","capabilities":[{"name":"vCPUs","value":"6"},{"name":"MemoryGB","value":"112"},{"name":"GPUs","value":"1"}]},
+{"name":"Standard_D2s_v3","resourceType":"virtualMachines","capabilities":[{"name":"vCPUs","value":"2"},{"name":"MemoryGB","value":"8"}]},
+{"name":"Premium_LRS","resourceType":"disks","capabilities":[]}
+]}`)
+	}))
+	defer server.Close()
+
+	r, err := NewRepo("sub", fakeCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Endpoint: server.URL, Audience: server.URL},
+				},
+			},
+			Transport: server.Client(),
+		},
+	}, time.Hour)
+	assert.NoError(t, err)
+
+	sku, err := r.GetVMSKU(context.Background(), "East US", "standard_nc6s_v3")
+	assert.NoError(t, err)
+	assert.Equal(t, &VMSKU{Name: "Standard_NC6s_v3", VCPUs: "6", MemoryGB: "112", GPUs: "1"}, sku)
+
+	sku, err = r.GetVMSKU(context.Background(), "eastus", "Standard_D2s_v3")
+	assert.NoError(t, err)
+	assert.Equal(t, &VMSKU{Name: "Standard_D2s_v3", VCPUs: "2", MemoryGB: "8"}, sku)
+
+	sku, err = r.GetVMSKU(context.Background(), "eastus", "Premium_LRS")
+	assert.NoError(t, err)
+	assert.Nil(t, sku)
+
+	// the SKUs of the location are listed once
+	assert.Equal(t, 1, requests)
+}
@@ -18,9 +18,12 @@ package routetable
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/routetableclient"
@@ -85,6 +88,11 @@ func (r *repo) CreateOrUpdate(ctx context.Context, routeTable armnetwork.RouteTa
 
 	rv, err := r.client.CreateOrUpdate(ctx, r.resourceGroup, *routeTable.Name, routeTable)
 	if err != nil {
+		// Invalidate the cache because ETAG precondition mismatch.
+		var rerr *azcore.ResponseError
+		if errors.As(err, &rerr) && rerr.StatusCode == http.StatusPreconditionFailed {
+			_ = r.cache.Delete(*routeTable.Name)
+		}
 		return nil, fmt.Errorf("create or update RouteTable: %w", err)
 	}
 	_ = r.cache.Delete(*routeTable.Name)
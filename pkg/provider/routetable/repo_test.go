@@ -19,9 +19,11 @@ package routetable
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/stretchr/testify/assert"
@@ -382,4 +384,37 @@ func TestRepo_CreateOrUpdate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, RouteTableName, *v.Name)
 	})
+
+	t.Run("should clear cache on precondition failed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		cli := mock_routetableclient.NewMockInterface(ctrl)
+		repo, err := NewRepo(cli, ResourceGroup, 60*time.Second, false)
+		assert.NoError(t, err)
+		ctx := context.Background()
+
+		const (
+			RouteTableName = "route-table-name"
+		)
+
+		cli.EXPECT().Get(gomock.Any(), ResourceGroup, RouteTableName).Return(&armnetwork.RouteTable{
+			Name: to.Ptr(RouteTableName),
+		}, nil).Times(2)
+
+		cli.EXPECT().CreateOrUpdate(gomock.Any(), ResourceGroup, RouteTableName, armnetwork.RouteTable{
+			Name: to.Ptr(RouteTableName),
+		}).Return(nil, &azcore.ResponseError{StatusCode: http.StatusPreconditionFailed}).Times(1)
+
+		_, err = repo.Get(ctx, RouteTableName, cache.CacheReadTypeDefault)
+		assert.NoError(t, err)
+
+		_, err = repo.CreateOrUpdate(ctx, armnetwork.RouteTable{
+			Name: to.Ptr(RouteTableName),
+		})
+		assert.Error(t, err)
+
+		_, err = repo.Get(ctx, RouteTableName, cache.CacheReadTypeDefault)
+		assert.NoError(t, err)
+	})
 }
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/util/errutils"
@@ -39,6 +41,11 @@ import (
 
 const (
 	nsgCacheTTLDefaultInSeconds = 120
+
+	// maxSecurityRuleOperations is the largest number of changed security rules which are updated
+	// one by one instead of updating the whole security group. Every rule is a separate operation,
+	// so a change of many rules is cheaper as a single update of the security group.
+	maxSecurityRuleOperations = 5
 )
 
 type Repository interface {
@@ -51,10 +58,14 @@ type securityGroupRepo struct {
 	securityGroupName          string
 	nsgCacheTTLInSeconds       int
 	securigyGroupClient        securitygroupclient.Interface
-	nsgCache                   azcache.Resource
+	// securityRuleClient updates the changed security rules alone, it's optional
+	securityRuleClient securityruleclient.Interface
+	nsgCache           azcache.Resource
 }
 
-func NewSecurityGroupRepo(securityGroupResourceGroup string, securityGroupName string, nsgCacheTTLInSeconds int, disableAPICallCache bool, securityGroupClient securitygroupclient.Interface) (Repository, error) {
+// NewSecurityGroupRepo creates the security group repository. The security groups are always updated
+// as a whole if securityRuleClient is nil.
+func NewSecurityGroupRepo(securityGroupResourceGroup string, securityGroupName string, nsgCacheTTLInSeconds int, disableAPICallCache bool, securityGroupClient securitygroupclient.Interface, securityRuleClient securityruleclient.Interface) (Repository, error) {
 	getter := func(ctx context.Context, key string) (interface{}, error) {
 		nsg, err := securityGroupClient.Get(ctx, securityGroupResourceGroup, key)
 		exists, rerr := errutils.CheckResourceExistsFromAzcoreError(err)
@@ -84,12 +95,19 @@ func NewSecurityGroupRepo(securityGroupResourceGroup string, securityGroupName s
 		securityGroupName:          securityGroupName,
 		nsgCacheTTLInSeconds:       nsgCacheTTLDefaultInSeconds,
 		securigyGroupClient:        securityGroupClient,
+		securityRuleClient:         securityRuleClient,
 		nsgCache:                   cache,
 	}, nil
 }
 
-// CreateOrUpdateSecurityGroup invokes az.SecurityGroupsClient.CreateOrUpdate with exponential backoff retry
+// CreateOrUpdateSecurityGroup invokes az.SecurityGroupsClient.CreateOrUpdate with exponential backoff retry.
+// If only a few security rules are changed, they are updated alone instead, so the large security groups
+// are not sent as a whole.
 func (az *securityGroupRepo) CreateOrUpdateSecurityGroup(ctx context.Context, sg *armnetwork.SecurityGroup) error {
+	if updatedRules, deletedRules, ok := az.getSecurityRuleChanges(ctx, sg); ok {
+		return az.updateSecurityRules(ctx, *sg.Name, updatedRules, deletedRules)
+	}
+
 	_, rerr := az.securigyGroupClient.CreateOrUpdate(ctx, az.securityGroupResourceGroup, *sg.Name, *sg)
 	klog.V(10).Infof("SecurityGroupsClient.CreateOrUpdate(%s): end", *sg.Name)
 	if rerr == nil {
@@ -117,6 +135,106 @@ func (az *securityGroupRepo) CreateOrUpdateSecurityGroup(ctx context.Context, sg
 	return rerr
 }
 
+// getSecurityRuleChanges returns the security rules to create or update and the names of the ones to
+// delete, if the security group differs from the cached one only in a few security rules. It returns
+// false if the security group should be updated as a whole, including when the security group was not
+// built from the cached one: the security rules are updated without the Etag of the security group, so
+// only the PUT of the whole security group with If-Match detects the concurrent updates.
+func (az *securityGroupRepo) getSecurityRuleChanges(ctx context.Context, sg *armnetwork.SecurityGroup) ([]*armnetwork.SecurityRule, []string, bool) {
+	if az.securityRuleClient == nil || sg.Name == nil || sg.Properties == nil {
+		return nil, nil, false
+	}
+	cached, err := az.nsgCache.Get(ctx, *sg.Name, azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, nil, false
+	}
+	original, ok := cached.(*armnetwork.SecurityGroup)
+	if !ok || original == nil || original.Properties == nil {
+		return nil, nil, false
+	}
+	if sg.Etag == nil || original.Etag == nil || *sg.Etag != *original.Etag {
+		klog.V(3).Infof("CreateOrUpdateSecurityGroup(%s): the security group is not built from the cached one, updating it as a whole", *sg.Name)
+		return nil, nil, false
+	}
+	if !reflect.DeepEqual(withoutSecurityRules(original), withoutSecurityRules(sg)) {
+		return nil, nil, false
+	}
+
+	originalRules := make(map[string]*armnetwork.SecurityRule, len(original.Properties.SecurityRules))
+	for _, rule := range original.Properties.SecurityRules {
+		originalRules[strings.ToLower(ptr.Deref(rule.Name, ""))] = rule
+	}
+	var updatedRules []*armnetwork.SecurityRule
+	expectedRules := make(map[string]bool, len(sg.Properties.SecurityRules))
+	for _, rule := range sg.Properties.SecurityRules {
+		if rule == nil || rule.Name == nil || rule.Properties == nil {
+			return nil, nil, false
+		}
+		name := strings.ToLower(*rule.Name)
+		expectedRules[name] = true
+		if !reflect.DeepEqual(originalRules[name], rule) {
+			updatedRules = append(updatedRules, rule)
+		}
+	}
+	var deletedRules []string
+	rulesByPriority := make(map[int32]string, len(original.Properties.SecurityRules))
+	for _, rule := range original.Properties.SecurityRules {
+		name := strings.ToLower(ptr.Deref(rule.Name, ""))
+		if !expectedRules[name] {
+			deletedRules = append(deletedRules, ptr.Deref(rule.Name, ""))
+			continue
+		}
+		if rule.Properties != nil && rule.Properties.Priority != nil {
+			rulesByPriority[*rule.Properties.Priority] = name
+		}
+	}
+
+	if len(updatedRules) == 0 && len(deletedRules) == 0 || len(updatedRules)+len(deletedRules) > maxSecurityRuleOperations {
+		return nil, nil, false
+	}
+	// The priorities must be unique after every operation, so a rule can't take the priority of
+	// another rule which is kept and is given a new priority in the same update.
+	for _, rule := range updatedRules {
+		name, found := rulesByPriority[ptr.Deref(rule.Properties.Priority, 0)]
+		if found && name != strings.ToLower(*rule.Name) {
+			return nil, nil, false
+		}
+	}
+	return updatedRules, deletedRules, true
+}
+
+// withoutSecurityRules returns a copy of the security group without its security rules.
+func withoutSecurityRules(sg *armnetwork.SecurityGroup) armnetwork.SecurityGroup {
+	rv := *sg
+	properties := *sg.Properties
+	properties.SecurityRules = nil
+	rv.Properties = &properties
+	return rv
+}
+
+// updateSecurityRules deletes and then creates or updates the security rules of the security group.
+func (az *securityGroupRepo) updateSecurityRules(ctx context.Context, sgName string, updatedRules []*armnetwork.SecurityRule, deletedRules []string) error {
+	// Invalidate the cache after updating, or after a failure since some rules may be updated already
+	defer func() { _ = az.nsgCache.Delete(sgName) }()
+
+	for _, ruleName := range deletedRules {
+		klog.V(2).Infof("CreateOrUpdateSecurityGroup(%s): deleting security rule %s", sgName, ruleName)
+		if err := az.securityRuleClient.Delete(ctx, az.securityGroupResourceGroup, sgName, ruleName); err != nil {
+			klog.Warningf("CreateOrUpdateSecurityGroup(%s) failed to delete security rule %s: %v", sgName, ruleName, err)
+			return err
+		}
+	}
+	for _, rule := range updatedRules {
+		klog.V(2).Infof("CreateOrUpdateSecurityGroup(%s): updating security rule %s", sgName, *rule.Name)
+		if _, err := az.securityRuleClient.CreateOrUpdate(ctx, az.securityGroupResourceGroup, sgName, *rule.Name, *rule); err != nil {
+			ruleJSON, _ := json.Marshal(rule)
+			klog.Warningf("CreateOrUpdateSecurityGroup(%s) failed to update security rule %s: %v, security rule request: %s", sgName, *rule.Name, err, string(ruleJSON))
+			return err
+		}
+	}
+	return nil
+}
+
 func (az *securityGroupRepo) GetSecurityGroup(ctx context.Context) (*armnetwork.SecurityGroup, error) {
 	nsg := &armnetwork.SecurityGroup{}
 	if az.securityGroupName == "" {
@@ -32,6 +32,8 @@ import (
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securitygroupclient/mock_securitygroupclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/securityruleclient/mock_securityruleclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 )
@@ -40,7 +42,7 @@ func TestCreateOrUpdateSecurityGroupCanceled(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockSGClient := mock_securitygroupclient.NewMockInterface(ctrl)
-	az, err := NewSecurityGroupRepo("rg", "sg", 120, false, mockSGClient)
+	az, err := NewSecurityGroupRepo("rg", "sg", 120, false, mockSGClient, nil)
 	assert.NoError(t, err)
 	az.(*securityGroupRepo).nsgCache.Set("sg", "test")
 
@@ -59,3 +61,123 @@ func TestCreateOrUpdateSecurityGroupCanceled(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, shouldBeEmpty)
 }
+
+func TestCreateOrUpdateSecurityGroupRules(t *testing.T) {
+	newRule := func(name string, priority int32) *armnetwork.SecurityRule {
+		return &armnetwork.SecurityRule{
+			Name: ptr.To(name),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				Priority:  ptr.To(priority),
+				Access:    ptr.To(armnetwork.SecurityRuleAccessAllow),
+				Direction: ptr.To(armnetwork.SecurityRuleDirectionInbound),
+			},
+		}
+	}
+	newSecurityGroup := func(rules ...*armnetwork.SecurityRule) *armnetwork.SecurityGroup {
+		return &armnetwork.SecurityGroup{
+			Name: ptr.To("sg"),
+			Etag: ptr.To("etag"),
+			Tags: map[string]*string{"foo": ptr.To("bar")},
+			Properties: &armnetwork.SecurityGroupPropertiesFormat{
+				SecurityRules: rules,
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc                 string
+		disableRuleUpdates   bool
+		updated              *armnetwork.SecurityGroup
+		expectedUpdatedRules []string
+		expectedDeletedRules []string
+	}{
+		{
+			desc:                 "changed rules are updated alone",
+			updated:              newSecurityGroup(newRule("a", 500), newRule("c", 501), newRule("d", 503)),
+			expectedUpdatedRules: []string{"c", "d"},
+			expectedDeletedRules: []string{"b"},
+		},
+		{
+			desc:                 "rule can take the priority of a deleted rule",
+			updated:              newSecurityGroup(newRule("a", 500), newRule("c", 501)),
+			expectedUpdatedRules: []string{"c"},
+			expectedDeletedRules: []string{"b"},
+		},
+		{
+			desc:    "security group is updated if the rules swap their priorities",
+			updated: newSecurityGroup(newRule("a", 501), newRule("b", 500)),
+		},
+		{
+			desc: "security group is updated if its tags are changed",
+			updated: func() *armnetwork.SecurityGroup {
+				sg := newSecurityGroup(newRule("a", 500), newRule("b", 501), newRule("c", 502))
+				sg.Tags["foo"] = ptr.To("baz")
+				return sg
+			}(),
+		},
+		{
+			desc: "security group is updated if many rules are changed",
+			updated: newSecurityGroup(newRule("a", 500), newRule("b", 501),
+				newRule("c", 502), newRule("d", 503), newRule("e", 504), newRule("f", 505), newRule("g", 506), newRule("h", 507)),
+		},
+		{
+			desc: "security group is updated if its Etag differs from the cached one",
+			updated: func() *armnetwork.SecurityGroup {
+				sg := newSecurityGroup(newRule("a", 500), newRule("c", 501))
+				sg.Etag = ptr.To("stale-etag")
+				return sg
+			}(),
+		},
+		{
+			desc: "security group is updated if it has no Etag",
+			updated: func() *armnetwork.SecurityGroup {
+				sg := newSecurityGroup(newRule("a", 500), newRule("c", 501))
+				sg.Etag = nil
+				return sg
+			}(),
+		},
+		{
+			desc:               "security group is updated if the rule updates are disabled",
+			disableRuleUpdates: true,
+			updated:            newSecurityGroup(newRule("a", 500), newRule("b", 501), newRule("c", 502)),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockSGClient := mock_securitygroupclient.NewMockInterface(ctrl)
+			mockRuleClient := mock_securityruleclient.NewMockInterface(ctrl)
+			var ruleClient securityruleclient.Interface = mockRuleClient
+			if tc.disableRuleUpdates {
+				ruleClient = nil
+			}
+			az, err := NewSecurityGroupRepo("rg", "sg", 120, false, mockSGClient, ruleClient)
+			assert.NoError(t, err)
+
+			mockSGClient.EXPECT().Get(gomock.Any(), "rg", "sg").Return(newSecurityGroup(newRule("a", 500), newRule("b", 501)), nil).MaxTimes(1)
+			_, err = az.GetSecurityGroup(context.TODO())
+			assert.NoError(t, err)
+
+			var updatedRules, deletedRules []string
+			mockRuleClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "sg", gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _, _, name string, rule armnetwork.SecurityRule) (*armnetwork.SecurityRule, error) {
+					assert.Equal(t, name, ptr.Deref(rule.Name, ""))
+					updatedRules = append(updatedRules, name)
+					return &rule, nil
+				}).AnyTimes()
+			mockRuleClient.EXPECT().Delete(gomock.Any(), "rg", "sg", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _, _, name string) error {
+					deletedRules = append(deletedRules, name)
+					return nil
+				}).AnyTimes()
+			expectSecurityGroupUpdate := len(tc.expectedUpdatedRules) == 0 && len(tc.expectedDeletedRules) == 0
+			if expectSecurityGroupUpdate {
+				mockSGClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", "sg", *tc.updated).Return(tc.updated, nil)
+			}
+
+			assert.NoError(t, az.CreateOrUpdateSecurityGroup(context.TODO(), tc.updated))
+			assert.Equal(t, tc.expectedUpdatedRules, updatedRules)
+			assert.Equal(t, tc.expectedDeletedRules, deletedRules)
+		})
+	}
+}
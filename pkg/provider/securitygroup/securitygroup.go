@@ -68,6 +68,9 @@ type RuleHelper struct {
 	// name -> security rule
 	rules      map[string]*armnetwork.SecurityRule
 	priorities map[int32]string
+
+	// appendOnly keeps the rules not created by the cloud provider untouched.
+	appendOnly bool
 }
 
 func NewSecurityGroupHelper(logger logr.Logger, sg *armnetwork.SecurityGroup) (*RuleHelper, error) {
@@ -293,6 +296,17 @@ func (helper *RuleHelper) AddRuleForDenyAll(dstAddresses []netip.Addr) error {
 	return nil
 }
 
+// SetAppendOnly makes the helper never modify or delete the rules not created by the cloud provider,
+// i.e. the rules without the prefix SecurityRuleNamePrefix in their names.
+func (helper *RuleHelper) SetAppendOnly() {
+	helper.appendOnly = true
+}
+
+// isRuleUnmodifiable returns true if the rule is not created by the cloud provider in the append-only mode.
+func (helper *RuleHelper) isRuleUnmodifiable(rule *armnetwork.SecurityRule) bool {
+	return helper.appendOnly && !IsManagedSecurityRuleName(ptr.Deref(rule.Name, ""))
+}
+
 // RemoveDestinationFromRules removes the given destination addresses from rules that match the given protocol and ports is in the retainDstPorts list.
 // It may add a new rule if the original rule needs to be split.
 func (helper *RuleHelper) RemoveDestinationFromRules(
@@ -316,6 +330,10 @@ func (helper *RuleHelper) RemoveDestinationFromRules(
 		if *rule.Properties.Protocol != protocol {
 			continue
 		}
+		if helper.isRuleUnmodifiable(rule) {
+			logger.V(4).Info("Skip rule not created by the cloud provider in the append-only mode", "rule-name", *rule.Name)
+			continue
+		}
 
 		if err := helper.removeDestinationFromRule(rule, dstPrefixes, retainDstPorts); err != nil {
 			logger.Error(err, "Failed to remove destination from rule", "rule-name", *rule.Name)
@@ -390,7 +408,7 @@ func (helper *RuleHelper) SecurityGroup() (*armnetwork.SecurityGroup, bool, erro
 			dstASGs      = r.Properties.DestinationApplicationSecurityGroups
 		)
 
-		if len(dstAddresses) == 0 && len(dstASGs) == 0 {
+		if len(dstAddresses) == 0 && len(dstASGs) == 0 && !helper.isRuleUnmodifiable(r) {
 			// Skip the rule without destination prefixes.
 			continue
 		}
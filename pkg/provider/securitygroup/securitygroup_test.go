@@ -1550,6 +1550,78 @@ func TestRuleHelper_RemoveDestinationFromRules(t *testing.T) {
 			},
 		}, outputSG.Properties.SecurityRules)
 	})
+
+	t.Run("it should only patch the rules created by the cloud provider in the append-only mode", func(t *testing.T) {
+		var (
+			managedRuleName = GenerateAllowSecurityRuleName(armnetwork.SecurityRuleProtocolTCP, iputil.IPv4, []string{"Internet"}, []int32{80})
+			rules           = []*armnetwork.SecurityRule{
+				{
+					Name: ptr.To("test-rule-0"),
+					Properties: &armnetwork.SecurityRulePropertiesFormat{
+						Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+						Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+						Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+						SourceAddressPrefix:      ptr.To("Internet"),
+						SourcePortRange:          ptr.To("*"),
+						DestinationAddressPrefix: ptr.To("192.168.0.1"),
+						DestinationPortRanges:    to.SliceOfPtrs("80"),
+						Priority:                 ptr.To(int32(500)),
+					},
+				},
+				{
+					Name: ptr.To(managedRuleName),
+					Properties: &armnetwork.SecurityRulePropertiesFormat{
+						Protocol:                   to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+						Access:                     to.Ptr(armnetwork.SecurityRuleAccessAllow),
+						Direction:                  to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+						SourceAddressPrefix:        ptr.To("Internet"),
+						SourcePortRange:            ptr.To("*"),
+						DestinationAddressPrefixes: to.SliceOfPtrs("10.0.0.1", "192.168.0.1"),
+						DestinationPortRanges:      to.SliceOfPtrs("80"),
+						Priority:                   ptr.To(int32(501)),
+					},
+				},
+			}
+
+			sg     = fx.Azure().SecurityGroup().WithRules(rules).Build()
+			helper = ExpectNewSecurityGroupHelper(t, sg)
+		)
+		helper.SetAppendOnly()
+
+		assert.NoError(t, helper.RemoveDestinationFromRules(armnetwork.SecurityRuleProtocolTCP, []string{"192.168.0.1"}, []int32{}))
+
+		outputSG, updated, err := helper.SecurityGroup()
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		testutil.ExpectEqualInJSON(t, []*armnetwork.SecurityRule{
+			{
+				Name: ptr.To("test-rule-0"),
+				Properties: &armnetwork.SecurityRulePropertiesFormat{
+					Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+					Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+					Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+					SourceAddressPrefix:      ptr.To("Internet"),
+					SourcePortRange:          ptr.To("*"),
+					DestinationAddressPrefix: ptr.To("192.168.0.1"),
+					DestinationPortRanges:    to.SliceOfPtrs("80"),
+					Priority:                 ptr.To(int32(500)),
+				},
+			},
+			{
+				Name: ptr.To(managedRuleName),
+				Properties: &armnetwork.SecurityRulePropertiesFormat{
+					Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+					Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+					Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+					SourceAddressPrefix:      ptr.To("Internet"),
+					SourcePortRange:          ptr.To("*"),
+					DestinationAddressPrefix: ptr.To("10.0.0.1"),
+					DestinationPortRanges:    to.SliceOfPtrs("80"),
+					Priority:                 ptr.To(int32(501)),
+				},
+			},
+		}, outputSG.Properties.SecurityRules)
+	})
 }
 
 func TestSecurityGroupHelper_SecurityGroup(t *testing.T) {
@@ -65,6 +65,11 @@ func GenerateDenyAllSecurityRuleName(ipFamily iputil.Family) string {
 	return strings.Join([]string{SecurityRuleNamePrefix, "deny-all", string(ipFamily)}, SecurityRuleNameSep)
 }
 
+// IsManagedSecurityRuleName returns true if the security rule is named by the cloud provider.
+func IsManagedSecurityRuleName(name string) bool {
+	return strings.HasPrefix(name, SecurityRuleNamePrefix+SecurityRuleNameSep)
+}
+
 // NormalizeSecurityRuleAddressPrefixes normalizes the given rule address prefixes.
 func NormalizeSecurityRuleAddressPrefixes(vs []string) []string {
 	// Remove redundant addresses.
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	apitrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	headerCorrelationRequestID = "X-Ms-Correlation-Request-Id"
+	// AttributeCorrelationRequestID is the attribute of the ARM correlation ID of the requests of the ARM client calls.
+	AttributeCorrelationRequestID = "az.correlation_request_id"
+)
+
+// ARMClientOption makes the ARM clients start the spans of their calls and requests with the default
+// tracer, as the children of the spans in the contexts, and record the ARM correlation IDs on them.
+func ARMClientOption(option *arm.ClientOptions) {
+	option.TracingProvider = tracing.NewProvider(func(_, _ string) tracing.Tracer {
+		return tracing.NewTracer(startARMSpan, &tracing.TracerOptions{
+			SpanFromContext: func(ctx context.Context) tracing.Span {
+				return newARMSpan(apitrace.SpanFromContext(ctx))
+			},
+		})
+	}, nil)
+	option.PerRetryPolicies = append(option.PerRetryPolicies, correlationIDPolicy{})
+}
+
+func startARMSpan(ctx context.Context, name string, options *tracing.SpanOptions) (context.Context, tracing.Span) {
+	var startOptions []apitrace.SpanStartOption
+	if options != nil {
+		startOptions = append(startOptions, apitrace.WithAttributes(armAttributes(options.Attributes)...))
+		switch options.Kind {
+		case tracing.SpanKindClient:
+			startOptions = append(startOptions, apitrace.WithSpanKind(apitrace.SpanKindClient))
+		case tracing.SpanKindInternal:
+			startOptions = append(startOptions, apitrace.WithSpanKind(apitrace.SpanKindInternal))
+		}
+	}
+	ctx, span := DefaultTracer().Start(ctx, name, startOptions...)
+	return ctx, newARMSpan(span)
+}
+
+func newARMSpan(span apitrace.Span) tracing.Span {
+	return tracing.NewSpan(tracing.SpanImpl{
+		End: func() {
+			span.End()
+		},
+		SetAttributes: func(attributes ...tracing.Attribute) {
+			span.SetAttributes(armAttributes(attributes)...)
+		},
+		AddEvent: func(name string, attributes ...tracing.Attribute) {
+			span.AddEvent(name, apitrace.WithAttributes(armAttributes(attributes)...))
+		},
+		SetStatus: func(status tracing.SpanStatus, description string) {
+			switch status {
+			case tracing.SpanStatusOK:
+				span.SetStatus(codes.Ok, description)
+			case tracing.SpanStatusError:
+				span.SetStatus(codes.Error, description)
+			default:
+				span.SetStatus(codes.Unset, description)
+			}
+		},
+	})
+}
+
+func armAttributes(attributes []tracing.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attributes))
+	for _, attr := range attributes {
+		switch v := attr.Value.(type) {
+		case string:
+			result = append(result, attribute.String(attr.Key, v))
+		case int:
+			result = append(result, attribute.Int(attr.Key, v))
+		case bool:
+			result = append(result, attribute.Bool(attr.Key, v))
+		default:
+			result = append(result, attribute.String(attr.Key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return result
+}
+
+// correlationIDPolicy records the ARM correlation IDs of the responses on the spans of the client calls.
+type correlationIDPolicy struct{}
+
+func (correlationIDPolicy) Do(req *policy.Request) (*http.Response, error) {
+	resp, err := req.Next()
+	if resp != nil {
+		if id := resp.Header.Get(headerCorrelationRequestID); id != "" {
+			apitrace.SpanFromContext(req.Raw().Context()).SetAttributes(attribute.String(AttributeCorrelationRequestID, id))
+		}
+	}
+	return resp, err
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordingProcessor records the ended spans.
+type recordingProcessor struct {
+	lock  sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (p *recordingProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (p *recordingProcessor) Shutdown(context.Context) error                  { return nil }
+func (p *recordingProcessor) ForceFlush(context.Context) error                { return nil }
+func (p *recordingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.spans = append(p.spans, s)
+}
+
+type fakeTransport struct{}
+
+func (fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{headerCorrelationRequestID: []string{"correlation-id"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestARMClientOption(t *testing.T) {
+	processor := &recordingProcessor{}
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	SetGlobalProvider(&Provider{traceProvider: traceProvider, defaultTracer: traceProvider.Tracer(DefaultTracerName), tracingEnabled: true})
+	defer SetGlobalProvider(nil)
+
+	options := &arm.ClientOptions{}
+	options.Transport = fakeTransport{}
+	ARMClientOption(options)
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &options.ClientOptions)
+	tracer := options.TracingProvider.NewTracer("test", "v0.0.0")
+
+	ctx, parent := BeginReconcile(context.Background(), DefaultTracer(), "EnsureLoadBalancer")
+	ctx, endSpan := runtime.StartSpan(ctx, "LoadBalancersClient.Get", tracer, nil)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, "https://management.azure.com/lb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	resp, err := pl.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	endSpan(nil)
+	parent.Done(ctx)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range processor.spans {
+		spans[span.Name()] = span
+	}
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+	call, request := spans["LoadBalancersClient.Get"], spans["HTTP GET"]
+	if !assert.NotNil(t, call) || !assert.NotNil(t, request) {
+		return
+	}
+	// the spans of the ARM client calls are the children of the reconciliations
+	assert.Equal(t, spans["EnsureLoadBalancer"].SpanContext().SpanID(), call.Parent().SpanID())
+	assert.Equal(t, call.SpanContext().SpanID(), request.Parent().SpanID())
+	assert.Contains(t, call.Attributes(), attribute.String(AttributeCorrelationRequestID, "correlation-id"))
+	assert.Contains(t, request.Attributes(), attribute.Int("http.status_code", http.StatusOK))
+}
@@ -23,6 +23,15 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 )
 
+// Service returns the attributes identifying the service. They are only set on the spans, as they
+// would blow up the cardinality of the metrics.
+func Service(svc *v1.Service) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("service.namespace", svc.Namespace),
+		attribute.String("service.name", svc.Name),
+	}
+}
+
 func FeatureOfService(svc *v1.Service) []attribute.KeyValue {
 	hasAnnotation := func(key string) bool {
 		if svc.Annotations == nil {
@@ -38,6 +47,8 @@ func FeatureOfService(svc *v1.Service) []attribute.KeyValue {
 		attribute.Bool("annotations.internal_load_balancer", hasAnnotation(consts.ServiceAnnotationLoadBalancerInternal)),
 		attribute.Bool("annotations.load_balancer_source_ranges", hasAnnotation(v1.AnnotationLoadBalancerSourceRangesKey)),
 		attribute.Bool("annotations.additional_public_ips", hasAnnotation(consts.ServiceAnnotationAdditionalPublicIPs)),
+		attribute.Bool("annotations.additional_pip_names", hasAnnotation(consts.ServiceAnnotationAdditionalPIPNames)),
+		attribute.Bool("annotations.additional_pip_prefix", hasAnnotation(consts.ServiceAnnotationAdditionalPIPPrefixID)),
 		attribute.Bool("annotations.pip.name", hasAnnotation(consts.ServiceAnnotationPIPNameDualStack[false])),
 		attribute.Bool("annotations.pip.prefix", hasAnnotation(consts.ServiceAnnotationPIPPrefixIDDualStack[false])),
 		attribute.Bool("spec.load_balancer_source_ranges", len(svc.Spec.LoadBalancerSourceRanges) > 0),
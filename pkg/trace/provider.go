@@ -24,11 +24,16 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	prometheusexporter "go.opentelemetry.io/otel/exporters/prometheus"
 	apimetric "go.opentelemetry.io/otel/metric"
 	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 	apitrace "go.opentelemetry.io/otel/trace"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -54,6 +59,11 @@ func DefaultTracer() apitrace.Tracer {
 	return globalProvider.defaultTracer
 }
 
+// Enabled returns true if the spans are exported.
+func Enabled() bool {
+	return globalProvider != nil && globalProvider.tracingEnabled
+}
+
 func DefaultMeter() apimetric.Meter {
 	if globalProvider == nil {
 		return metricnoop.Meter{}
@@ -62,22 +72,57 @@ func DefaultMeter() apimetric.Meter {
 }
 
 type Provider struct {
-	traceProvider *sdktrace.TracerProvider
-	defaultTracer apitrace.Tracer
+	traceProvider  *sdktrace.TracerProvider
+	defaultTracer  apitrace.Tracer
+	tracingEnabled bool
 
 	meterProvider      *sdkmetric.MeterProvider
 	defaultMeter       apimetric.Meter
 	prometheusRegistry *prometheus.Registry
 }
 
-func New() (*Provider, error) {
-	// TODO: support tracing
+// Options are the options of the tracing.
+type Options struct {
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector the spans are exported to.
+	// The spans are not sampled if it is empty.
+	OTLPEndpoint string
+	// OTLPInsecure disables the TLS of the connection to the collector.
+	OTLPInsecure bool
+	// SamplingRatio is the ratio of the traces sampled, the spans of the sampled traces
+	// are always sampled.
+	SamplingRatio float64
+	// ServiceName is the name of the service reported with the spans.
+	ServiceName string
+}
+
+func New(ctx context.Context, options Options) (*Provider, error) {
 	var (
+		traceProvider  *sdktrace.TracerProvider
+		tracingEnabled = options.OTLPEndpoint != ""
+	)
+	if tracingEnabled {
+		exporterOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.OTLPEndpoint)}
+		if options.OTLPInsecure {
+			exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("initialize otlp trace exporter: %w", err)
+		}
+		traceProvider = sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(options.ServiceName))),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SamplingRatio))),
+		)
+		// the trace context is propagated to the spans started by the other libraries
+		otel.SetTracerProvider(traceProvider)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	} else {
 		traceProvider = sdktrace.NewTracerProvider(
 			sdktrace.WithSampler(sdktrace.NeverSample()),
 		)
-		defaultTracer = traceProvider.Tracer(DefaultTracerName)
-	)
+	}
+	defaultTracer := traceProvider.Tracer(DefaultTracerName)
 
 	var (
 		meterProvider      *sdkmetric.MeterProvider
@@ -98,6 +143,7 @@ func New() (*Provider, error) {
 	return &Provider{
 		traceProvider:      traceProvider,
 		defaultTracer:      defaultTracer,
+		tracingEnabled:     tracingEnabled,
 		meterProvider:      meterProvider,
 		defaultMeter:       defaultMeter,
 		prometheusRegistry: prometheusRegistry,
@@ -24,8 +24,54 @@ import (
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 )
 
+// ARMRequestIDs returns the correlation ID and the request ID of the ARM response of the error, which
+// are empty if the error is not from an ARM response.
+func ARMRequestIDs(err error) (correlationID, requestID string) {
+	var respError *azcore.ResponseError
+	if !errors.As(err, &respError) || respError == nil || respError.RawResponse == nil {
+		return "", ""
+	}
+	return respError.RawResponse.Header.Get(consts.CorrelationRequestIDHeaderKey), respError.RawResponse.Header.Get(consts.RequestIDHeaderKey)
+}
+
+// armRequestError is an error of an ARM response with the IDs of the request in its message.
+type armRequestError struct {
+	err           error
+	correlationID string
+	requestID     string
+}
+
+func (e *armRequestError) Error() string {
+	return fmt.Sprintf("CorrelationID: %s, RequestID: %s, %s", e.correlationID, e.requestID, e.err.Error())
+}
+
+func (e *armRequestError) Unwrap() error {
+	return e.err
+}
+
+// WithARMRequestIDs adds the correlation ID and the request ID of the ARM response of the error to the
+// front of its message, so they are kept in the logs and the events of the error even if the message is
+// truncated, and Azure support can find the request. The error is returned as is if it is not from an
+// ARM response or already has the IDs.
+func WithARMRequestIDs(err error) error {
+	if err == nil {
+		return nil
+	}
+	var requestErr *armRequestError
+	if errors.As(err, &requestErr) {
+		return err
+	}
+	correlationID, requestID := ARMRequestIDs(err)
+	if correlationID == "" && requestID == "" {
+		return err
+	}
+	return &armRequestError{err: err, correlationID: correlationID, requestID: requestID}
+}
+
 func CheckResourceExistsFromAzcoreError(err error) (bool, error) {
 	if err == nil {
 		return true, nil
@@ -83,3 +129,28 @@ func GetVMSSMetadataByRawError(err error) (string, string, error) {
 func isErrorLoadBalancerInUseByVirtualMachineScaleSet(rawError string) bool {
 	return strings.Contains(rawError, "LoadBalancerInUseByVirtualMachineScaleSet")
 }
+
+// lockedScopesRE matches the locked scopes in the message of the ScopeLocked error, e.g.
+// "... because following scope(s) are locked: '/subscriptions/sub/resourceGroups/rg'. Please remove the lock and try again."
+var lockedScopesRE = regexp.MustCompile(`'(/subscriptions/[^']+)'`)
+
+// GetLockedScopes returns the scopes locked by the read-only or delete management locks if the ARM
+// request failed because of them, and false otherwise.
+func GetLockedScopes(err error) ([]string, bool) {
+	var respError *azcore.ResponseError
+	if !errors.As(err, &respError) || respError == nil || respError.StatusCode != http.StatusConflict ||
+		!strings.EqualFold(respError.ErrorCode, consts.ScopeLockedErrorCode) {
+		return nil, false
+	}
+	message := respError.Error()
+	// the scope of the operation is quoted before the locked scopes
+	_, lockedScopes, found := strings.Cut(message, "are locked:")
+	if !found {
+		return nil, true
+	}
+	var scopes []string
+	for _, match := range lockedScopesRE.FindAllStringSubmatch(lockedScopes, -1) {
+		scopes = append(scopes, match[1])
+	}
+	return scopes, true
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventrecorder implements an event recorder which emits the events through the events/v1
+// API, and rate limits the similar events of an object so a flood of events, e.g. the throttling
+// and backoff events during an incident, is aggregated into summary events.
+package eventrecorder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultBurst is the default number of similar events of an object emitted at once.
+	DefaultBurst = 5
+	// DefaultQPS is the default rate of the similar events of an object emitted after the burst.
+	DefaultQPS = 1.0 / 60
+	// DefaultSummaryInterval is the default interval of the summary events.
+	DefaultSummaryInterval = 5 * time.Minute
+)
+
+// Policy is the aggregation policy of the events. The events of an object with the same type
+// and reason are similar events, which are rate limited by a token bucket. The suppressed events
+// are counted in the next event emitted, or in a summary event if no event is emitted in the
+// summary interval.
+type Policy struct {
+	// Burst is the number of similar events of an object emitted at once. The events are not
+	// rate limited if it is not positive.
+	Burst int
+	// QPS is the rate of the similar events of an object emitted after the burst.
+	QPS float64
+	// SummaryInterval is the interval of the summary events of the suppressed events.
+	SummaryInterval time.Duration
+}
+
+// DefaultPolicy returns the default aggregation policy.
+func DefaultPolicy() Policy {
+	return Policy{
+		Burst:           DefaultBurst,
+		QPS:             DefaultQPS,
+		SummaryInterval: DefaultSummaryInterval,
+	}
+}
+
+// New returns an event recorder emitting the events of the component through the events/v1 API
+// with the aggregation policy until the context is done. The annotations of the events are dropped
+// as the events/v1 API does not support them.
+func New(ctx context.Context, client clientset.Interface, component string, policy Policy) record.EventRecorder {
+	broadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+	if err := broadcaster.StartRecordingToSinkWithContext(ctx); err != nil {
+		klog.Errorf("eventrecorder: failed to start recording the events of %s: %v", component, err)
+	}
+	broadcaster.StartStructuredLogging(2)
+	return newAggregator(ctx, broadcaster.NewRecorder(scheme.Scheme, component), policy)
+}
+
+type aggregationKey struct {
+	uid       types.UID
+	namespace string
+	name      string
+	eventtype string
+	reason    string
+}
+
+type aggregationEntry struct {
+	limiter *rate.Limiter
+	// object is the object of the last event, to which the summary event is emitted.
+	object     runtime.Object
+	suppressed int
+	lastNote   string
+	lastSeen   time.Time
+}
+
+// aggregator is a record.EventRecorder rate limiting the similar events of the objects.
+type aggregator struct {
+	recorder events.EventRecorder
+	policy   Policy
+
+	lock    sync.Mutex
+	entries map[aggregationKey]*aggregationEntry
+	now     func() time.Time
+}
+
+var _ record.EventRecorder = &aggregator{}
+
+func newAggregator(ctx context.Context, recorder events.EventRecorder, policy Policy) *aggregator {
+	a := &aggregator{
+		recorder: recorder,
+		policy:   policy,
+		entries:  make(map[aggregationKey]*aggregationEntry),
+		now:      time.Now,
+	}
+	if policy.Burst > 0 && policy.SummaryInterval > 0 {
+		go wait.UntilWithContext(ctx, func(context.Context) { a.summarize() }, policy.SummaryInterval)
+	}
+	return a
+}
+
+// Event implements record.EventRecorder.
+func (a *aggregator) Event(object runtime.Object, eventtype, reason, message string) {
+	a.emit(object, eventtype, reason, message)
+}
+
+// Eventf implements record.EventRecorder.
+func (a *aggregator) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	a.emit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder. The annotations are dropped.
+func (a *aggregator) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	a.emit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (a *aggregator) emit(object runtime.Object, eventtype, reason, note string) {
+	accessor, err := meta.Accessor(object)
+	if a.policy.Burst <= 0 || err != nil {
+		a.record(object, eventtype, reason, note)
+		return
+	}
+	key := aggregationKey{
+		uid:       accessor.GetUID(),
+		namespace: accessor.GetNamespace(),
+		name:      accessor.GetName(),
+		eventtype: eventtype,
+		reason:    reason,
+	}
+
+	a.lock.Lock()
+	now := a.now()
+	entry, found := a.entries[key]
+	if !found {
+		entry = &aggregationEntry{limiter: rate.NewLimiter(rate.Limit(a.policy.QPS), a.policy.Burst)}
+		a.entries[key] = entry
+	}
+	entry.object = object
+	entry.lastSeen = now
+	if !entry.limiter.AllowN(now, 1) {
+		entry.suppressed++
+		entry.lastNote = note
+		a.lock.Unlock()
+		return
+	}
+	suppressed := entry.suppressed
+	entry.suppressed = 0
+	a.lock.Unlock()
+
+	if suppressed > 0 {
+		note = fmt.Sprintf("%s (%d similar events suppressed)", note, suppressed)
+	}
+	a.record(object, eventtype, reason, note)
+}
+
+// summarize emits the summary events of the suppressed events, and forgets the objects without
+// events in the summary interval.
+func (a *aggregator) summarize() {
+	type summary struct {
+		object     runtime.Object
+		eventtype  string
+		reason     string
+		suppressed int
+		lastNote   string
+	}
+	var summaries []summary
+
+	a.lock.Lock()
+	now := a.now()
+	for key, entry := range a.entries {
+		if entry.suppressed > 0 {
+			summaries = append(summaries, summary{entry.object, key.eventtype, key.reason, entry.suppressed, entry.lastNote})
+			entry.suppressed = 0
+			continue
+		}
+		if now.Sub(entry.lastSeen) > a.policy.SummaryInterval && entry.limiter.TokensAt(now) >= float64(a.policy.Burst) {
+			delete(a.entries, key)
+		}
+	}
+	a.lock.Unlock()
+
+	for _, s := range summaries {
+		a.record(s.object, s.eventtype, s.reason, fmt.Sprintf("%d similar events suppressed in the last %s, the last one: %s", s.suppressed, a.policy.SummaryInterval, s.lastNote))
+	}
+}
+
+func (a *aggregator) record(object runtime.Object, eventtype, reason, note string) {
+	// the action is required by the events/v1 API, the reason is the closest to it
+	a.recorder.Eventf(object, nil, eventtype, reason, reason, "%s", note)
+}
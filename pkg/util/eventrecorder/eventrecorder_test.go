@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventrecorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+func getEvents(recorder *events.FakeRecorder) []string {
+	var result []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			result = append(result, event)
+		default:
+			return result
+		}
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	recorder := events.NewFakeRecorder(100)
+	now := time.Now()
+	a := newAggregator(context.Background(), recorder, Policy{Burst: 2, QPS: 1, SummaryInterval: time.Minute})
+	a.now = func() time.Time { return now }
+
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "uid"}}
+	other := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+	for i := 0; i < 5; i++ {
+		a.Eventf(svc, v1.EventTypeWarning, "Throttled", "throttled %d", i)
+	}
+	// the events of other reasons and objects are rate limited separately
+	a.Event(svc, v1.EventTypeNormal, "EnsuredLoadBalancer", "ensured")
+	a.Event(other, v1.EventTypeWarning, "Throttled", "throttled")
+	assert.Equal(t, []string{
+		"Warning Throttled throttled 0",
+		"Warning Throttled throttled 1",
+		"Normal EnsuredLoadBalancer ensured",
+		"Warning Throttled throttled",
+	}, getEvents(recorder))
+
+	// the next event allowed counts the suppressed ones
+	now = now.Add(time.Second)
+	a.Eventf(svc, v1.EventTypeWarning, "Throttled", "throttled %d", 5)
+	assert.Equal(t, []string{"Warning Throttled throttled 5 (3 similar events suppressed)"}, getEvents(recorder))
+
+	// the suppressed events without a following event are summarized
+	a.Eventf(svc, v1.EventTypeWarning, "Throttled", "throttled %d", 6)
+	a.summarize()
+	assert.Equal(t, []string{"Warning Throttled 1 similar events suppressed in the last 1m0s, the last one: throttled 6"}, getEvents(recorder))
+
+	// the idle objects are forgotten
+	now = now.Add(2 * time.Minute)
+	a.summarize()
+	assert.Empty(t, getEvents(recorder))
+	assert.Empty(t, a.entries)
+}
+
+func TestAggregatorWithoutRateLimits(t *testing.T) {
+	recorder := events.NewFakeRecorder(100)
+	a := newAggregator(context.Background(), recorder, Policy{})
+
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "uid"}}
+	for i := 0; i < 10; i++ {
+		a.Event(svc, v1.EventTypeWarning, "Throttled", "throttled")
+	}
+	assert.Len(t, getEvents(recorder), 10)
+}
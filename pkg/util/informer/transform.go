@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package informer implements the transform functions of the shared informers, which strip the
+// fields the controllers do not use from the objects before they are cached.
+package informer
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StripUnusedFields is a cache.TransformFunc which removes the managed fields and the last
+// applied configuration annotation from all the objects, and the container images from the
+// status of the nodes. None of them are read by the controllers, and the images alone take up
+// several KBs per node.
+func StripUnusedFields(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// e.g. cache.DeletedFinalStateUnknown, whose object is transformed already
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+	if annotations := accessor.GetAnnotations(); annotations != nil {
+		delete(annotations, v1.LastAppliedConfigAnnotation)
+	}
+
+	if node, ok := obj.(*v1.Node); ok {
+		node.Status.Images = nil
+	}
+	return obj, nil
+}
+
+// StripPod is a cache.TransformFunc which keeps only the metadata, the node name and the phase
+// of the pods, which are what is checked to find out whether a node is drained.
+func StripPod(obj interface{}) (interface{}, error) {
+	obj, err := StripUnusedFields(obj)
+	if err != nil {
+		return obj, err
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	return &v1.Pod{
+		TypeMeta:   pod.TypeMeta,
+		ObjectMeta: pod.ObjectMeta,
+		Spec: v1.PodSpec{
+			NodeName: pod.Spec.NodeName,
+		},
+		Status: v1.PodStatus{
+			Phase: pod.Status.Phase,
+		},
+	}, nil
+}
+
+// AcceptLoadBalancerClasses returns a cache.TransformFunc which strips the unused fields, and
+// clears the load balancer class of the services of the given classes. The service controller
+// only reconciles the services without a class, so it reconciles the ones of the given classes
+// as well when it is fed from an informer with the transform. The class is never written back,
+// since the controller only patches the differences it makes to the cached services.
+func AcceptLoadBalancerClasses(classes []string) cache.TransformFunc {
+	accepted := sets.New(classes...)
+	return func(obj interface{}) (interface{}, error) {
+		obj, err := StripUnusedFields(obj)
+		if err != nil {
+			return obj, err
+		}
+		if service, ok := obj.(*v1.Service); ok && service.Spec.LoadBalancerClass != nil && accepted.Has(*service.Spec.LoadBalancerClass) {
+			service.Spec.LoadBalancerClass = nil
+		}
+		return obj, nil
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+func newTestObjectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name: "obj",
+		Annotations: map[string]string{
+			v1.LastAppliedConfigAnnotation: "{}",
+			"foo":                          "bar",
+		},
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}
+}
+
+func TestStripUnusedFields(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: newTestObjectMeta(),
+		Status: v1.NodeStatus{
+			Images:    []v1.ContainerImage{{Names: []string{"image"}}},
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.4"}},
+		},
+	}
+	obj, err := StripUnusedFields(node)
+	assert.NoError(t, err)
+	assert.Equal(t, &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "obj",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.4"}},
+		},
+	}, obj)
+
+	service := &v1.Service{ObjectMeta: newTestObjectMeta(), Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer}}
+	obj, err = StripUnusedFields(service)
+	assert.NoError(t, err)
+	assert.Equal(t, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "obj",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+	}, obj)
+
+	tombstone := cache.DeletedFinalStateUnknown{Key: "obj"}
+	obj, err = StripUnusedFields(tombstone)
+	assert.NoError(t, err)
+	assert.Equal(t, tombstone, obj)
+}
+
+func TestStripPod(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: newTestObjectMeta(),
+		Spec: v1.PodSpec{
+			NodeName:   "node",
+			Containers: []v1.Container{{Name: "c", Image: "image"}},
+		},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "c"}},
+		},
+	}
+	obj, err := StripPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "obj",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec:   v1.PodSpec{NodeName: "node"},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}, obj)
+}
+
+func TestAcceptLoadBalancerClasses(t *testing.T) {
+	transform := AcceptLoadBalancerClasses([]string{"example.com/azure"})
+
+	for _, tc := range []struct {
+		desc     string
+		class    *string
+		expected *string
+	}{
+		{
+			desc: "service without a class",
+		},
+		{
+			desc:  "service of an accepted class",
+			class: ptr.To("example.com/azure"),
+		},
+		{
+			desc:     "service of another class",
+			class:    ptr.To("metallb.io/metallb"),
+			expected: ptr.To("metallb.io/metallb"),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			service := &v1.Service{
+				ObjectMeta: newTestObjectMeta(),
+				Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, LoadBalancerClass: tc.class},
+			}
+			obj, err := transform(service)
+			assert.NoError(t, err)
+			assert.Equal(t, &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "obj",
+					Annotations: map[string]string{"foo": "bar"},
+				},
+				Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, LoadBalancerClass: tc.expected},
+			}, obj)
+		})
+	}
+}
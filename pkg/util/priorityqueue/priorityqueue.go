@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package priorityqueue implements a rate limiting work queue which hands out the items by
+// their priorities, so the urgent items are not starved by the bulk of periodic resyncs.
+package priorityqueue
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Priority is the priority of an item in the queue.
+type Priority int
+
+const (
+	// PriorityLow is the priority of the periodic resyncs.
+	PriorityLow Priority = iota
+	// PriorityNormal is the priority of the items added without a priority, including the retries.
+	PriorityNormal
+	// PriorityHigh is the priority of the urgent items, e.g. the added or deleted objects.
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// RateLimitingInterface is a rate limiting work queue whose items have priorities. The items
+// with higher priorities are handed out first, and the items with the same priority in the
+// order they are added.
+type RateLimitingInterface[T comparable] interface {
+	workqueue.TypedRateLimitingInterface[T]
+
+	// AddWithPriority adds the item with the priority. The priority of an item already in
+	// the queue is raised if it is lower, and is never lowered.
+	AddWithPriority(item T, priority Priority)
+}
+
+type rateLimitingQueue[T comparable] struct {
+	workqueue.TypedRateLimitingInterface[T]
+
+	queue *queue[T]
+}
+
+// NewRateLimitingQueue creates a rate limiting work queue with priorities. The name and the
+// metrics provider are used for the metrics of the queue like the ones of workqueue, the
+// global metrics provider is used if metricsProvider is nil.
+func NewRateLimitingQueue[T comparable](rateLimiter workqueue.TypedRateLimiter[T], name string, metricsProvider workqueue.MetricsProvider) RateLimitingInterface[T] {
+	q := newQueue[T]()
+	return &rateLimitingQueue[T]{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[T]{
+			Name:            name,
+			MetricsProvider: metricsProvider,
+			DelayingQueue: workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[T]{
+				Name:            name,
+				MetricsProvider: metricsProvider,
+				Queue: workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[T]{
+					Name:            name,
+					MetricsProvider: metricsProvider,
+					Queue:           q,
+				}),
+			}),
+		}),
+		queue: q,
+	}
+}
+
+// AddWithPriority implements RateLimitingInterface.
+func (q *rateLimitingQueue[T]) AddWithPriority(item T, priority Priority) {
+	q.queue.setNextPriority(item, priority)
+	q.Add(item)
+}
+
+// queue is the storage of the work queue which pops the items by their priorities. The
+// priority of an item is passed by setNextPriority before the item is added to the work
+// queue, which then pushes or touches the item in the storage, or keeps it until the item
+// is done if it is being processed.
+type queue[T comparable] struct {
+	// lock guards next, which is set outside the lock of the work queue.
+	lock sync.Mutex
+	next map[T]Priority
+
+	// items are the FIFO queues of the priorities. An item raised to a higher priority is
+	// left in the lower queue and skipped when it is popped from there.
+	items  [numPriorities][]T
+	queued map[T]Priority
+}
+
+func newQueue[T comparable]() *queue[T] {
+	return &queue[T]{
+		next:   make(map[T]Priority),
+		queued: make(map[T]Priority),
+	}
+}
+
+func (q *queue[T]) setNextPriority(item T, priority Priority) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if priority < PriorityLow {
+		priority = PriorityLow
+	}
+	if priority > PriorityHigh {
+		priority = PriorityHigh
+	}
+	if current, found := q.next[item]; !found || current < priority {
+		q.next[item] = priority
+	}
+}
+
+// takeNextPriority returns the priority the item is added with.
+func (q *queue[T]) takeNextPriority(item T) (Priority, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	priority, found := q.next[item]
+	delete(q.next, item)
+	return priority, found
+}
+
+// Touch implements workqueue.Queue. It raises the priority of the queued item.
+func (q *queue[T]) Touch(item T) {
+	priority, found := q.takeNextPriority(item)
+	if !found || priority <= q.queued[item] {
+		return
+	}
+	q.queued[item] = priority
+	q.items[priority] = append(q.items[priority], item)
+}
+
+// Push implements workqueue.Queue.
+func (q *queue[T]) Push(item T) {
+	priority, found := q.takeNextPriority(item)
+	if !found {
+		priority = PriorityNormal
+	}
+	q.queued[item] = priority
+	q.items[priority] = append(q.items[priority], item)
+}
+
+// Len implements workqueue.Queue.
+func (q *queue[T]) Len() int {
+	return len(q.queued)
+}
+
+// Pop implements workqueue.Queue. It is only called if the queue is not empty.
+func (q *queue[T]) Pop() (item T) {
+	for priority := numPriorities - 1; priority >= 0; priority-- {
+		for len(q.items[priority]) > 0 {
+			item = q.items[priority][0]
+			q.items[priority][0] = *new(T)
+			q.items[priority] = q.items[priority][1:]
+
+			if queuedPriority, found := q.queued[item]; found && int(queuedPriority) == priority {
+				delete(q.queued, item)
+				return item
+			}
+		}
+	}
+	return item
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+)
+
+func newTestQueue() RateLimitingInterface[string] {
+	return NewRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string](), "", nil)
+}
+
+func getAll(q RateLimitingInterface[string]) []string {
+	var items []string
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		q.Done(item)
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestPriorityOrder(t *testing.T) {
+	q := newTestQueue()
+	defer q.ShutDown()
+
+	q.AddWithPriority("resync-1", PriorityLow)
+	q.AddWithPriority("resync-2", PriorityLow)
+	q.Add("retry")
+	q.AddWithPriority("deleted", PriorityHigh)
+	q.AddWithPriority("added", PriorityHigh)
+
+	assert.Equal(t, 5, q.Len())
+	assert.Equal(t, []string{"deleted", "added", "retry", "resync-1", "resync-2"}, getAll(q))
+}
+
+func TestPriorityIsRaisedButNotLowered(t *testing.T) {
+	q := newTestQueue()
+	defer q.ShutDown()
+
+	q.AddWithPriority("a", PriorityLow)
+	q.AddWithPriority("b", PriorityLow)
+	q.AddWithPriority("c", PriorityHigh)
+	q.AddWithPriority("b", PriorityHigh)
+	q.AddWithPriority("c", PriorityLow)
+
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, []string{"c", "b", "a"}, getAll(q))
+}
+
+func TestPriorityOfItemAddedWhileProcessing(t *testing.T) {
+	q := newTestQueue()
+	defer q.ShutDown()
+
+	q.AddWithPriority("a", PriorityLow)
+	item, _ := q.Get()
+	assert.Equal(t, "a", item)
+
+	q.AddWithPriority("b", PriorityNormal)
+	// a is added again while it is processed, it keeps the priority until it is done
+	q.AddWithPriority("a", PriorityHigh)
+	q.Done("a")
+
+	assert.Equal(t, []string{"a", "b"}, getAll(q))
+}
+
+func TestRequeueAfterRaise(t *testing.T) {
+	q := newTestQueue()
+	defer q.ShutDown()
+
+	q.AddWithPriority("a", PriorityLow)
+	q.AddWithPriority("a", PriorityHigh)
+	assert.Equal(t, []string{"a"}, getAll(q))
+
+	// the entry left in the low priority queue is skipped
+	q.AddWithPriority("b", PriorityLow)
+	assert.Equal(t, []string{"b"}, getAll(q))
+	assert.Equal(t, 0, q.Len())
+}
+
+type fakeMetricsProvider struct {
+	workqueue.MetricsProvider
+	names []string
+}
+
+func (p *fakeMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	p.names = append(p.names, name)
+	return p.MetricsProvider.NewDepthMetric(name)
+}
+
+func TestMetricsProvider(t *testing.T) {
+	provider := &fakeMetricsProvider{MetricsProvider: metrics.NewWorkqueueMetricsProvider("priorityqueue_test")}
+	q := NewRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string](), "test-queue", provider)
+	defer q.ShutDown()
+
+	assert.Equal(t, []string{"test-queue"}, provider.names)
+}